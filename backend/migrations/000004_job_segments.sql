@@ -0,0 +1,36 @@
+-- ----------------------------------------------------------------------
+--  Split jobs: one instance per segment per service date
+-- ----------------------------------------------------------------------
+ALTER TABLE job_definitions
+ADD COLUMN segment_strategy VARCHAR(20) NOT NULL DEFAULT 'NONE',
+ADD COLUMN segments JSONB NOT NULL DEFAULT '[]',
+ADD CONSTRAINT job_segment_strategy_ck CHECK (
+    segment_strategy IN ('NONE', 'BUILDING', 'FLOOR')
+);
+
+ALTER TABLE job_instances
+ADD COLUMN segment_index INT NOT NULL DEFAULT 0,
+ADD COLUMN segment_count INT NOT NULL DEFAULT 1,
+DROP CONSTRAINT job_instances_definition_id_service_date_key,
+ADD CONSTRAINT job_instances_definition_date_segment_key
+UNIQUE (definition_id, service_date, segment_index),
+ADD CONSTRAINT job_instances_segment_ck CHECK (
+    segment_index >= 0 AND segment_index < segment_count
+);
+
+---- create above / drop below ----
+
+DELETE FROM job_instances WHERE segment_index > 0;
+
+ALTER TABLE job_instances
+DROP CONSTRAINT job_instances_segment_ck,
+DROP CONSTRAINT job_instances_definition_date_segment_key,
+ADD CONSTRAINT job_instances_definition_id_service_date_key
+UNIQUE (definition_id, service_date),
+DROP COLUMN segment_count,
+DROP COLUMN segment_index;
+
+ALTER TABLE job_definitions
+DROP CONSTRAINT job_segment_strategy_ck,
+DROP COLUMN segments,
+DROP COLUMN segment_strategy;
@@ -0,0 +1,7 @@
+-- Worker job search filters on status + date before joining definitions.
+CREATE INDEX idx_job_instances_status_service_date
+ON job_instances (status, service_date);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_job_instances_status_service_date;
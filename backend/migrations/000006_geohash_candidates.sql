@@ -0,0 +1,64 @@
+-- ----------------------------------------------------------------------
+--  Geohash for properties (must match go-utils EncodeGeohash)
+-- ----------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION geohash_encode(
+    lat DOUBLE PRECISION, lng DOUBLE PRECISION, prec INT
+) RETURNS TEXT
+LANGUAGE plpgsql IMMUTABLE STRICT AS $$
+DECLARE
+    alphabet CONSTANT TEXT := '0123456789bcdefghjkmnpqrstuvwxyz';
+    lat_lo DOUBLE PRECISION := -90;
+    lat_hi DOUBLE PRECISION := 90;
+    lng_lo DOUBLE PRECISION := -180;
+    lng_hi DOUBLE PRECISION := 180;
+    mid DOUBLE PRECISION;
+    is_lng BOOLEAN := TRUE;
+    nbits INT := 0;
+    ch INT := 0;
+    hash TEXT := '';
+BEGIN
+    WHILE length(hash) < prec LOOP
+        IF is_lng THEN
+            mid := (lng_lo + lng_hi) / 2;
+            IF lng >= mid THEN
+                ch := ch * 2 + 1;
+                lng_lo := mid;
+            ELSE
+                ch := ch * 2;
+                lng_hi := mid;
+            END IF;
+        ELSE
+            mid := (lat_lo + lat_hi) / 2;
+            IF lat >= mid THEN
+                ch := ch * 2 + 1;
+                lat_lo := mid;
+            ELSE
+                ch := ch * 2;
+                lat_hi := mid;
+            END IF;
+        END IF;
+        is_lng := NOT is_lng;
+        nbits := nbits + 1;
+        IF nbits = 5 THEN
+            hash := hash || substr(alphabet, ch + 1, 1);
+            nbits := 0;
+            ch := 0;
+        END IF;
+    END LOOP;
+    RETURN hash;
+END;
+$$;
+
+ALTER TABLE properties
+ADD COLUMN geohash TEXT COLLATE "C"
+GENERATED ALWAYS AS (
+    geohash_encode(latitude::DOUBLE PRECISION, longitude::DOUBLE PRECISION, 6)
+) STORED;
+
+CREATE INDEX idx_properties_geohash ON properties (geohash);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_properties_geohash;
+ALTER TABLE properties DROP COLUMN IF EXISTS geohash;
+DROP FUNCTION IF EXISTS geohash_encode(DOUBLE PRECISION, DOUBLE PRECISION, INT);
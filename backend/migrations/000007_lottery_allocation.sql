@@ -0,0 +1,64 @@
+-- ----------------------------------------------------------------------
+--  Lottery allocation for newly released jobs
+-- ----------------------------------------------------------------------
+ALTER TABLE properties
+ADD COLUMN allocation_mode VARCHAR(20) NOT NULL DEFAULT 'FIRST_COME',
+ADD COLUMN lottery_window_seconds INT NOT NULL DEFAULT 120,
+ADD CONSTRAINT properties_allocation_mode_ck CHECK (
+    allocation_mode IN ('FIRST_COME', 'LOTTERY')
+),
+ADD CONSTRAINT properties_lottery_window_ck CHECK (
+    lottery_window_seconds > 0
+);
+
+CREATE TABLE job_lottery_draws (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instance_id UUID NOT NULL REFERENCES job_instances (id)
+    ON DELETE CASCADE,
+    seed BIGINT NOT NULL,
+    roll DOUBLE PRECISION NOT NULL,
+    total_weight DOUBLE PRECISION NOT NULL,
+    winner_worker_id UUID NULL REFERENCES workers (id),
+    entries JSONB NOT NULL DEFAULT '[]',
+    drawn_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_job_lottery_draws_instance
+ON job_lottery_draws (instance_id);
+
+CREATE TABLE job_lottery_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instance_id UUID NOT NULL REFERENCES job_instances (id)
+    ON DELETE CASCADE,
+    worker_id UUID NOT NULL REFERENCES workers (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    reliability_score INT NOT NULL,
+    distance_miles DOUBLE PRECISION NOT NULL,
+    weight DOUBLE PRECISION NOT NULL,
+    window_closes_at TIMESTAMPTZ NOT NULL,
+    draw_id UUID NULL REFERENCES job_lottery_draws (id),
+    loss_reason TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ NULL,
+    UNIQUE (instance_id, worker_id),
+    CONSTRAINT job_lottery_entries_status_ck CHECK (
+        status IN ('PENDING', 'WON', 'LOST')
+    )
+);
+
+CREATE INDEX idx_job_lottery_entries_pending
+ON job_lottery_entries (window_closes_at)
+WHERE status = 'PENDING';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_job_lottery_entries_pending;
+DROP TABLE IF EXISTS job_lottery_entries;
+DROP INDEX IF EXISTS idx_job_lottery_draws_instance;
+DROP TABLE IF EXISTS job_lottery_draws;
+
+ALTER TABLE properties
+DROP CONSTRAINT properties_lottery_window_ck,
+DROP CONSTRAINT properties_allocation_mode_ck,
+DROP COLUMN lottery_window_seconds,
+DROP COLUMN allocation_mode;
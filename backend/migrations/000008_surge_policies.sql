@@ -0,0 +1,56 @@
+-- ----------------------------------------------------------------------
+--  Surge pricing policies (data-driven) and audit trail
+-- ----------------------------------------------------------------------
+ALTER TABLE properties
+ADD COLUMN market TEXT NULL;
+
+CREATE TABLE surge_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    scope_key TEXT NOT NULL DEFAULT '',
+    stages JSONB NOT NULL DEFAULT '[]',
+    max_multiplier NUMERIC(6, 3) NOT NULL DEFAULT 0,
+    max_pay NUMERIC(10, 2) NULL,
+    overrides JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT surge_policies_scope_ck CHECK (
+        scope IN ('GLOBAL', 'MARKET', 'PROPERTY', 'DEFINITION')
+    )
+);
+
+CREATE INDEX idx_surge_policies_scope
+ON surge_policies (scope, scope_key)
+WHERE active;
+
+CREATE TABLE surge_audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(30) NOT NULL,
+    actor_id UUID NOT NULL,
+    policy_id UUID NULL REFERENCES surge_policies (id) ON DELETE SET NULL,
+    instance_id UUID NULL REFERENCES job_instances (id) ON DELETE SET NULL,
+    old_pay NUMERIC(10, 2) NULL,
+    new_pay NUMERIC(10, 2) NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_surge_audit_events_policy
+ON surge_audit_events (policy_id, created_at DESC);
+
+CREATE INDEX idx_surge_audit_events_instance
+ON surge_audit_events (instance_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_surge_audit_events_instance;
+DROP INDEX IF EXISTS idx_surge_audit_events_policy;
+DROP TABLE IF EXISTS surge_audit_events;
+DROP INDEX IF EXISTS idx_surge_policies_scope;
+DROP TABLE IF EXISTS surge_policies;
+
+ALTER TABLE properties DROP COLUMN IF EXISTS market;
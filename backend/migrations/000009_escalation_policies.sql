@@ -0,0 +1,64 @@
+CREATE TABLE escalation_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    scope_key TEXT NOT NULL DEFAULT '',
+    steps JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT escalation_policies_scope_ck CHECK (
+        scope IN ('GLOBAL', 'MARKET', 'PROPERTY', 'DEFINITION')
+    )
+);
+
+CREATE INDEX idx_escalation_policies_scope
+ON escalation_policies (scope, scope_key)
+WHERE active;
+
+CREATE TABLE escalation_step_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    instance_id UUID NOT NULL REFERENCES job_instances (id) ON DELETE CASCADE,
+    policy_id UUID NULL REFERENCES escalation_policies (id) ON DELETE SET NULL,
+    step_key TEXT NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    outcome VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    details JSONB NOT NULL DEFAULT '{}',
+    executed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT escalation_step_executions_instance_step_key
+    UNIQUE (instance_id, step_key)
+);
+
+CREATE TABLE escalation_policy_audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(30) NOT NULL,
+    actor_id UUID NOT NULL,
+    policy_id UUID NULL REFERENCES escalation_policies (id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_escalation_policy_audit_events_policy
+ON escalation_policy_audit_events (policy_id, created_at DESC);
+
+-- Carry over warnings already sent under the old fixed columns so the
+-- built-in policy does not send them again.
+INSERT INTO escalation_step_executions (instance_id, step_key, action, outcome, executed_at)
+SELECT id, 'internal_warning_90', 'NOTIFY', 'EXECUTED', warning_90_min_sent_at
+FROM job_instances
+WHERE warning_90_min_sent_at IS NOT NULL;
+
+INSERT INTO escalation_step_executions (instance_id, step_key, action, outcome, executed_at)
+SELECT id, 'on_call_warning_40', 'NOTIFY', 'EXECUTED', warning_40_min_sent_at
+FROM job_instances
+WHERE warning_40_min_sent_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_escalation_policy_audit_events_policy;
+DROP TABLE IF EXISTS escalation_policy_audit_events;
+DROP TABLE IF EXISTS escalation_step_executions;
+DROP INDEX IF EXISTS idx_escalation_policies_scope;
+DROP TABLE IF EXISTS escalation_policies;
@@ -0,0 +1,22 @@
+CREATE TABLE scheduler_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    service VARCHAR(50) NOT NULL,
+    job_name TEXT NOT NULL,
+    replica TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING',
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ NULL,
+    duration_ms BIGINT NULL,
+    error TEXT NULL,
+    CONSTRAINT scheduler_runs_status_ck CHECK (
+        status IN ('RUNNING', 'SUCCEEDED', 'FAILED')
+    )
+);
+
+CREATE INDEX idx_scheduler_runs_service_started
+ON scheduler_runs (service, started_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_scheduler_runs_service_started;
+DROP TABLE IF EXISTS scheduler_runs;
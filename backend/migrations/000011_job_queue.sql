@@ -0,0 +1,46 @@
+CREATE TABLE background_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue VARCHAR(50) NOT NULL,
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    unique_key TEXT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by TEXT NULL,
+    locked_at TIMESTAMPTZ NULL,
+    last_error TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT background_jobs_status_ck CHECK (
+        status IN ('PENDING', 'RUNNING', 'DEAD')
+    ),
+    CONSTRAINT background_jobs_attempts_ck CHECK (max_attempts > 0)
+);
+
+CREATE INDEX idx_background_jobs_due
+ON background_jobs (queue, run_at) WHERE status = 'PENDING';
+
+CREATE INDEX idx_background_jobs_locked
+ON background_jobs (queue, locked_at) WHERE status = 'RUNNING';
+
+CREATE UNIQUE INDEX uq_background_jobs_active_key
+ON background_jobs (queue, unique_key)
+WHERE unique_key IS NOT NULL AND status IN ('PENDING', 'RUNNING');
+
+-- Unit photos waiting on a queued verification; the job's payload holds
+-- the key.
+CREATE TABLE verification_photos (
+    key TEXT PRIMARY KEY,
+    photo BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS verification_photos;
+DROP INDEX IF EXISTS uq_background_jobs_active_key;
+DROP INDEX IF EXISTS idx_background_jobs_locked;
+DROP INDEX IF EXISTS idx_background_jobs_due;
+DROP TABLE IF EXISTS background_jobs;
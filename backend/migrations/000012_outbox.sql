@@ -0,0 +1,46 @@
+-- Transactional outbox. tx_id is the writing transaction's id; consumers
+-- read events in (tx_id, id) order from transactions that have finished.
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    tx_id BIGINT NOT NULL DEFAULT (pg_current_xact_id()::text::bigint),
+    topic VARCHAR(100) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_events_order ON outbox_events (tx_id, id);
+CREATE INDEX idx_outbox_events_created ON outbox_events (created_at);
+
+CREATE TABLE outbox_consumer_offsets (
+    consumer VARCHAR(100) PRIMARY KEY,
+    last_tx_id BIGINT NOT NULL DEFAULT 0,
+    last_event_id BIGINT NOT NULL DEFAULT 0,
+    -- The event after the offset that has been failing, and how often.
+    failing_event_id BIGINT,
+    failed_attempts INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Events a consumer gave up on after too many failed attempts. The payload is
+-- copied because delivered events are purged from outbox_events.
+CREATE TABLE outbox_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    consumer VARCHAR(100) NOT NULL,
+    event_id BIGINT NOT NULL,
+    topic VARCHAR(100) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (consumer, event_id)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS outbox_dead_letters;
+DROP TABLE IF EXISTS outbox_consumer_offsets;
+DROP INDEX IF EXISTS idx_outbox_events_created;
+DROP INDEX IF EXISTS idx_outbox_events_order;
+DROP TABLE IF EXISTS outbox_events;
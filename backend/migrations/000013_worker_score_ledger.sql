@@ -0,0 +1,26 @@
+-- ----------------------------------------------------------------------
+--  Score ledger: events name their job, ops adjustments and reversals
+-- ----------------------------------------------------------------------
+ALTER TABLE worker_score_events
+ADD COLUMN job_instance_id UUID NULL REFERENCES job_instances (id) ON DELETE SET NULL,
+ADD COLUMN actor_id UUID NULL,
+ADD COLUMN note TEXT NOT NULL DEFAULT '',
+ADD COLUMN reverses_event_id UUID NULL REFERENCES worker_score_events (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_worker_score_events_worker
+ON worker_score_events (worker_id, created_at DESC, id DESC);
+
+-- An event can be reversed at most once.
+CREATE UNIQUE INDEX uq_worker_score_events_reverses
+ON worker_score_events (reverses_event_id)
+WHERE reverses_event_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS uq_worker_score_events_reverses;
+DROP INDEX IF EXISTS idx_worker_score_events_worker;
+ALTER TABLE worker_score_events
+DROP COLUMN IF EXISTS reverses_event_id,
+DROP COLUMN IF EXISTS note,
+DROP COLUMN IF EXISTS actor_id,
+DROP COLUMN IF EXISTS job_instance_id;
@@ -0,0 +1,32 @@
+-- ----------------------------------------------------------------------
+--  Money in integer cents (models.Money)
+-- ----------------------------------------------------------------------
+ALTER TABLE job_instances RENAME COLUMN effective_pay TO effective_pay_cents;
+ALTER TABLE job_instances
+ALTER COLUMN effective_pay_cents TYPE BIGINT USING ROUND(effective_pay_cents * 100)::BIGINT;
+
+ALTER TABLE surge_policies RENAME COLUMN max_pay TO max_pay_cents;
+ALTER TABLE surge_policies
+ALTER COLUMN max_pay_cents TYPE BIGINT USING ROUND(max_pay_cents * 100)::BIGINT;
+
+ALTER TABLE surge_audit_events RENAME COLUMN old_pay TO old_pay_cents;
+ALTER TABLE surge_audit_events RENAME COLUMN new_pay TO new_pay_cents;
+ALTER TABLE surge_audit_events
+ALTER COLUMN old_pay_cents TYPE BIGINT USING ROUND(old_pay_cents * 100)::BIGINT,
+ALTER COLUMN new_pay_cents TYPE BIGINT USING ROUND(new_pay_cents * 100)::BIGINT;
+
+---- create above / drop below ----
+
+ALTER TABLE surge_audit_events
+ALTER COLUMN old_pay_cents TYPE NUMERIC(10, 2) USING old_pay_cents / 100.0,
+ALTER COLUMN new_pay_cents TYPE NUMERIC(10, 2) USING new_pay_cents / 100.0;
+ALTER TABLE surge_audit_events RENAME COLUMN new_pay_cents TO new_pay;
+ALTER TABLE surge_audit_events RENAME COLUMN old_pay_cents TO old_pay;
+
+ALTER TABLE surge_policies
+ALTER COLUMN max_pay_cents TYPE NUMERIC(10, 2) USING max_pay_cents / 100.0;
+ALTER TABLE surge_policies RENAME COLUMN max_pay_cents TO max_pay;
+
+ALTER TABLE job_instances
+ALTER COLUMN effective_pay_cents TYPE NUMERIC(10, 2) USING effective_pay_cents / 100.0;
+ALTER TABLE job_instances RENAME COLUMN effective_pay_cents TO effective_pay;
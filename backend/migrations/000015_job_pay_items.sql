@@ -0,0 +1,82 @@
+-- ----------------------------------------------------------------------
+--  Itemized pay ledger. job_instances.effective_pay_cents is the running
+--  total of an instance's items and is only changed together with them.
+-- ----------------------------------------------------------------------
+CREATE TABLE job_pay_items (
+    id UUID PRIMARY KEY,
+    job_instance_id UUID NOT NULL REFERENCES job_instances (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    actor_id UUID NULL,
+    surge_policy_id UUID NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT job_pay_items_kind_ck CHECK (
+        kind IN ('BASE', 'SURGE', 'MANUAL_BONUS', 'ADJUSTMENT', 'CLAWBACK')
+    ),
+    CONSTRAINT job_pay_items_sign_ck CHECK (
+        (kind IN ('BASE', 'SURGE', 'MANUAL_BONUS') AND amount_cents >= 0)
+        OR (kind = 'CLAWBACK' AND amount_cents < 0)
+        OR kind = 'ADJUSTMENT'
+    )
+);
+
+CREATE INDEX idx_job_pay_items_instance
+ON job_pay_items (job_instance_id, created_at, id);
+
+CREATE UNIQUE INDEX uq_job_pay_items_base
+ON job_pay_items (job_instance_id) WHERE kind = 'BASE';
+
+-- Items are never changed or removed. The only deletes let through are the
+-- ones cascading from a deleted instance.
+CREATE OR REPLACE FUNCTION job_pay_items_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' AND pg_trigger_depth() > 1 THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'job_pay_items is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_job_pay_items_append_only
+BEFORE UPDATE OR DELETE ON job_pay_items
+FOR EACH ROW EXECUTE FUNCTION job_pay_items_append_only();
+
+-- Existing pay is split into a base item, the definition's pay for the
+-- service date's weekday (the segment's unit share of it for split jobs),
+-- and a surge item for whatever the instance was raised above that.
+-- Instances whose definition has no pay for the day keep it all as base.
+WITH base AS (
+    SELECT
+        ji.id,
+        ji.effective_pay_cents,
+        ji.created_at,
+        LEAST(ji.effective_pay_cents, COALESCE((
+            SELECT ROUND((e ->> 'base_pay')::NUMERIC * 100 * COALESCE((
+                SELECT (seg ->> 'unit_count')::NUMERIC
+                    / NULLIF((SELECT SUM((s ->> 'unit_count')::NUMERIC) FROM jsonb_array_elements(jd.segments) s), 0)
+                FROM jsonb_array_elements(jd.segments) seg
+                WHERE ji.segment_count > 1 AND (seg ->> 'index')::INT = ji.segment_index
+            ), 1))::BIGINT
+            FROM jsonb_array_elements(jd.daily_pay_estimates) e
+            WHERE (e ->> 'day_of_week')::INT = EXTRACT(DOW FROM ji.service_date)::INT
+            LIMIT 1
+        ), ji.effective_pay_cents)) AS base_cents
+    FROM job_instances ji
+    JOIN job_definitions jd ON jd.id = ji.definition_id
+)
+INSERT INTO job_pay_items (id, job_instance_id, kind, amount_cents, description, created_at)
+SELECT gen_random_uuid(), id, 'BASE', base_cents, 'Base pay', created_at FROM base
+UNION ALL
+SELECT gen_random_uuid(), id, 'SURGE', effective_pay_cents - base_cents, 'Surge pay', created_at
+FROM base
+WHERE effective_pay_cents > base_cents;
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS trg_job_pay_items_append_only ON job_pay_items;
+DROP FUNCTION IF EXISTS job_pay_items_append_only();
+DROP INDEX IF EXISTS uq_job_pay_items_base;
+DROP INDEX IF EXISTS idx_job_pay_items_instance;
+DROP TABLE IF EXISTS job_pay_items;
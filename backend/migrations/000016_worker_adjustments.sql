@@ -0,0 +1,58 @@
+-- ----------------------------------------------------------------------
+--  Worker pay adjustments: ops bonuses, corrections and clawbacks, approved
+--  by a second ops user and folded into the worker's next payout. A payout
+--  that nets out at or below the minimum is settled with nothing sent: its
+--  net is recorded as carried_cents and carried forward as a CARRY_FORWARD
+--  adjustment.
+-- ----------------------------------------------------------------------
+CREATE TABLE worker_pay_adjustments (
+    id UUID PRIMARY KEY,
+    worker_id UUID NOT NULL REFERENCES workers (id),
+    kind VARCHAR(20) NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    job_instance_id UUID NULL REFERENCES job_instances (id) ON DELETE SET NULL,
+    related_payout_id UUID NULL REFERENCES worker_payouts (id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING_APPROVAL',
+    created_by UUID NULL,
+    decided_by UUID NULL,
+    decided_at TIMESTAMPTZ NULL,
+    decision_note TEXT NOT NULL DEFAULT '',
+    payout_id UUID NULL REFERENCES worker_payouts (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT worker_pay_adjustments_kind_ck CHECK (
+        kind IN ('BONUS', 'REFERRAL_BONUS', 'CORRECTION', 'CLAWBACK', 'CARRY_FORWARD')
+    ),
+    CONSTRAINT worker_pay_adjustments_status_ck CHECK (
+        status IN ('PENDING_APPROVAL', 'APPROVED', 'REJECTED', 'APPLIED')
+    ),
+    CONSTRAINT worker_pay_adjustments_amount_ck CHECK (amount_cents <> 0),
+    CONSTRAINT worker_pay_adjustments_applied_ck CHECK (
+        (status = 'APPLIED') = (payout_id IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_worker_pay_adjustments_worker
+ON worker_pay_adjustments (worker_id, created_at DESC);
+
+CREATE INDEX idx_worker_pay_adjustments_approved
+ON worker_pay_adjustments (worker_id) WHERE status = 'APPROVED';
+
+CREATE INDEX idx_worker_pay_adjustments_payout
+ON worker_pay_adjustments (payout_id) WHERE payout_id IS NOT NULL;
+
+ALTER TABLE worker_payouts
+ADD COLUMN adjustment_cents BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN carried_cents BIGINT NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE worker_payouts
+DROP COLUMN IF EXISTS carried_cents,
+DROP COLUMN IF EXISTS adjustment_cents;
+
+DROP INDEX IF EXISTS idx_worker_pay_adjustments_payout;
+DROP INDEX IF EXISTS idx_worker_pay_adjustments_approved;
+DROP INDEX IF EXISTS idx_worker_pay_adjustments_worker;
+DROP TABLE IF EXISTS worker_pay_adjustments;
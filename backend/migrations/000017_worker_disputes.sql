@@ -0,0 +1,73 @@
+-- ----------------------------------------------------------------------
+--  Worker pay disputes: a worker contests a job's pay or cancellation, or
+--  a payout week. Staff triage, ask for more information, then approve
+--  (creating a pay adjustment) or deny. Every step is kept in the
+--  dispute's event timeline.
+-- ----------------------------------------------------------------------
+CREATE TABLE worker_pay_disputes (
+    id UUID PRIMARY KEY,
+    worker_id UUID NOT NULL REFERENCES workers (id),
+    job_instance_id UUID NULL REFERENCES job_instances (id) ON DELETE SET NULL,
+    payout_id UUID NULL REFERENCES worker_payouts (id) ON DELETE SET NULL,
+    category VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    evidence TEXT [] NOT NULL DEFAULT '{}',
+    requested_amount_cents BIGINT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    assigned_to UUID NULL,
+    resolved_by UUID NULL,
+    resolved_at TIMESTAMPTZ NULL,
+    resolution_note TEXT NOT NULL DEFAULT '',
+    adjustment_id UUID NULL REFERENCES worker_pay_adjustments (id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT worker_pay_disputes_category_ck CHECK (
+        category IN ('UNDERPAID', 'UNFAIR_CANCELLATION', 'PAYOUT', 'OTHER')
+    ),
+    CONSTRAINT worker_pay_disputes_status_ck CHECK (
+        status IN ('OPEN', 'UNDER_REVIEW', 'NEEDS_INFO', 'APPROVED', 'DENIED')
+    ),
+    CONSTRAINT worker_pay_disputes_subject_ck CHECK (
+        job_instance_id IS NOT NULL OR payout_id IS NOT NULL
+    ),
+    CONSTRAINT worker_pay_disputes_requested_ck CHECK (
+        requested_amount_cents IS NULL OR requested_amount_cents > 0
+    )
+);
+
+CREATE INDEX idx_worker_pay_disputes_worker
+ON worker_pay_disputes (worker_id, created_at DESC);
+
+CREATE INDEX idx_worker_pay_disputes_unresolved
+ON worker_pay_disputes (status, created_at)
+WHERE status IN ('OPEN', 'UNDER_REVIEW', 'NEEDS_INFO');
+
+-- One unresolved dispute per worker and job.
+CREATE UNIQUE INDEX uq_worker_pay_disputes_open_job
+ON worker_pay_disputes (worker_id, job_instance_id)
+WHERE job_instance_id IS NOT NULL
+AND status IN ('OPEN', 'UNDER_REVIEW', 'NEEDS_INFO');
+
+CREATE TABLE worker_pay_dispute_events (
+    id UUID PRIMARY KEY,
+    dispute_id UUID NOT NULL REFERENCES worker_pay_disputes (id) ON DELETE CASCADE,
+    actor_id UUID NULL,
+    action VARCHAR(20) NOT NULL,
+    from_status VARCHAR(20) NULL,
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    evidence TEXT [] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_worker_pay_dispute_events_dispute
+ON worker_pay_dispute_events (dispute_id, created_at, id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_worker_pay_dispute_events_dispute;
+DROP TABLE IF EXISTS worker_pay_dispute_events;
+DROP INDEX IF EXISTS uq_worker_pay_disputes_open_job;
+DROP INDEX IF EXISTS idx_worker_pay_disputes_unresolved;
+DROP INDEX IF EXISTS idx_worker_pay_disputes_worker;
+DROP TABLE IF EXISTS worker_pay_disputes;
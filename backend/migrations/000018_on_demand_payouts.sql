@@ -0,0 +1,37 @@
+-- ----------------------------------------------------------------------
+--  On-demand payouts: a worker cashes out available earnings between
+--  weekly payouts, by standard or instant transfer, less a fee. Only
+--  weekly payouts are one per worker and pay period.
+-- ----------------------------------------------------------------------
+ALTER TABLE worker_payouts
+ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'WEEKLY',
+ADD COLUMN method VARCHAR(20) NOT NULL DEFAULT 'STANDARD',
+ADD COLUMN fee_cents BIGINT NOT NULL DEFAULT 0,
+ADD CONSTRAINT worker_payouts_kind_ck CHECK (kind IN ('WEEKLY', 'ON_DEMAND')),
+ADD CONSTRAINT worker_payouts_method_ck CHECK (method IN ('STANDARD', 'INSTANT')),
+ADD CONSTRAINT worker_payouts_fee_ck CHECK (fee_cents >= 0);
+
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_worker_id_week_start_date_key;
+
+CREATE UNIQUE INDEX uq_worker_payouts_weekly
+ON worker_payouts (worker_id, week_start_date)
+WHERE kind = 'WEEKLY';
+
+CREATE INDEX idx_worker_payouts_on_demand
+ON worker_payouts (worker_id, created_at)
+WHERE kind = 'ON_DEMAND';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_worker_payouts_on_demand;
+DROP INDEX IF EXISTS uq_worker_payouts_weekly;
+ALTER TABLE worker_payouts
+ADD CONSTRAINT worker_payouts_worker_id_week_start_date_key UNIQUE (worker_id, week_start_date);
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_fee_ck,
+DROP CONSTRAINT IF EXISTS worker_payouts_method_ck,
+DROP CONSTRAINT IF EXISTS worker_payouts_kind_ck,
+DROP COLUMN IF EXISTS fee_cents,
+DROP COLUMN IF EXISTS method,
+DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,140 @@
+-- ----------------------------------------------------------------------
+--  Pay schedules: how often a worker is paid (daily, weekly or biweekly),
+--  counted from an anchor date at a local start hour in the schedule's
+--  timezone, and how long after a period closes its payout is sent.
+--  Workers get a schedule of their own, else their market's, else the
+--  default. Payouts record the exact period they cover.
+-- ----------------------------------------------------------------------
+CREATE TABLE pay_schedules (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    frequency VARCHAR(20) NOT NULL,
+    anchor_date DATE NOT NULL,
+    start_hour INT NOT NULL DEFAULT 0,
+    timezone TEXT NOT NULL,
+    payout_delay_hours INT NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pay_schedules_frequency_ck CHECK (
+        frequency IN ('DAILY', 'WEEKLY', 'BIWEEKLY')
+    ),
+    CONSTRAINT pay_schedules_start_hour_ck CHECK (start_hour BETWEEN 0 AND 23),
+    CONSTRAINT pay_schedules_delay_ck CHECK (payout_delay_hours >= 0)
+);
+
+-- At most one default schedule.
+CREATE UNIQUE INDEX uq_pay_schedules_default
+ON pay_schedules (is_default) WHERE is_default;
+
+-- The period every worker was paid on before schedules existed.
+INSERT INTO pay_schedules (
+    id, name, frequency, anchor_date, start_hour, timezone, payout_delay_hours, is_default
+) VALUES (
+    '00000000-0000-0000-0000-0000000000a1', 'Weekly (Monday 4AM Eastern)', 'WEEKLY',
+    '2024-01-01', 4, 'America/New_York', 29, TRUE
+);
+
+CREATE TABLE pay_schedule_assignments (
+    id UUID PRIMARY KEY,
+    pay_schedule_id UUID NOT NULL REFERENCES pay_schedules (id) ON DELETE CASCADE,
+    worker_id UUID NULL REFERENCES workers (id) ON DELETE CASCADE,
+    market TEXT NULL,
+    created_by UUID NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pay_schedule_assignments_target_ck CHECK (
+        (worker_id IS NULL) <> (market IS NULL)
+    )
+);
+
+CREATE UNIQUE INDEX uq_pay_schedule_assignments_worker
+ON pay_schedule_assignments (worker_id) WHERE worker_id IS NOT NULL;
+
+CREATE UNIQUE INDEX uq_pay_schedule_assignments_market
+ON pay_schedule_assignments (market) WHERE market IS NOT NULL;
+
+ALTER TABLE worker_payouts
+ADD COLUMN period_start TIMESTAMPTZ NULL,
+ADD COLUMN period_end TIMESTAMPTZ NULL,
+ADD COLUMN pay_schedule_id UUID NULL REFERENCES pay_schedules (id) ON DELETE SET NULL;
+
+-- week_start_date and week_end_date held the period's first and last local
+-- days at UTC midnight. Weekly periods began at 4AM Eastern; the daily
+-- (short) ones, with equal start and end, at midnight.
+UPDATE worker_payouts
+SET
+    period_start = (
+        (week_start_date AT TIME ZONE 'UTC')::date
+        + CASE WHEN week_end_date = week_start_date THEN TIME '00:00' ELSE TIME '04:00' END
+    ) AT TIME ZONE 'America/New_York',
+    period_end = (
+        (week_end_date AT TIME ZONE 'UTC')::date + 1
+        + CASE WHEN week_end_date = week_start_date THEN TIME '00:00' ELSE TIME '04:00' END
+    ) AT TIME ZONE 'America/New_York',
+    pay_schedule_id = CASE
+        WHEN week_end_date = week_start_date THEN NULL
+        ELSE '00000000-0000-0000-0000-0000000000a1'::uuid
+    END;
+
+ALTER TABLE worker_payouts
+ALTER COLUMN period_start SET NOT NULL,
+ALTER COLUMN period_end SET NOT NULL,
+ADD CONSTRAINT worker_payouts_period_ck CHECK (period_end > period_start);
+
+DROP INDEX IF EXISTS uq_worker_payouts_weekly;
+
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_kind_ck;
+UPDATE worker_payouts SET kind = 'SCHEDULED' WHERE kind = 'WEEKLY';
+ALTER TABLE worker_payouts
+ALTER COLUMN kind SET DEFAULT 'SCHEDULED',
+ADD CONSTRAINT worker_payouts_kind_ck CHECK (kind IN ('SCHEDULED', 'ON_DEMAND'));
+
+-- One scheduled payout per worker and pay period.
+CREATE UNIQUE INDEX uq_worker_payouts_scheduled
+ON worker_payouts (worker_id, period_start)
+WHERE kind = 'SCHEDULED';
+
+CREATE INDEX idx_worker_payouts_worker_period
+ON worker_payouts (worker_id, period_start DESC);
+
+ALTER TABLE worker_payouts
+DROP COLUMN IF EXISTS week_start_date,
+DROP COLUMN IF EXISTS week_end_date;
+
+---- create above / drop below ----
+
+ALTER TABLE worker_payouts
+ADD COLUMN week_start_date TIMESTAMPTZ NULL,
+ADD COLUMN week_end_date TIMESTAMPTZ NULL;
+UPDATE worker_payouts
+SET
+    week_start_date = (period_start AT TIME ZONE 'America/New_York')::date AT TIME ZONE 'UTC',
+    week_end_date = (
+        (period_end AT TIME ZONE 'America/New_York') - INTERVAL '1 day'
+    )::date AT TIME ZONE 'UTC';
+ALTER TABLE worker_payouts
+ALTER COLUMN week_start_date SET NOT NULL,
+ALTER COLUMN week_end_date SET NOT NULL;
+DROP INDEX IF EXISTS idx_worker_payouts_worker_period;
+DROP INDEX IF EXISTS uq_worker_payouts_scheduled;
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_kind_ck;
+UPDATE worker_payouts SET kind = 'WEEKLY' WHERE kind = 'SCHEDULED';
+ALTER TABLE worker_payouts
+ALTER COLUMN kind SET DEFAULT 'WEEKLY',
+ADD CONSTRAINT worker_payouts_kind_ck CHECK (kind IN ('WEEKLY', 'ON_DEMAND'));
+CREATE UNIQUE INDEX uq_worker_payouts_weekly
+ON worker_payouts (worker_id, week_start_date)
+WHERE kind = 'WEEKLY';
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_period_ck,
+DROP COLUMN IF EXISTS pay_schedule_id,
+DROP COLUMN IF EXISTS period_end,
+DROP COLUMN IF EXISTS period_start;
+
+DROP INDEX IF EXISTS uq_pay_schedule_assignments_market;
+DROP INDEX IF EXISTS uq_pay_schedule_assignments_worker;
+DROP TABLE IF EXISTS pay_schedule_assignments;
+DROP INDEX IF EXISTS uq_pay_schedules_default;
+DROP TABLE IF EXISTS pay_schedules;
@@ -0,0 +1,42 @@
+-- ----------------------------------------------------------------------
+--  Payout holds: a completed job that matches a hold rule (flagged for
+--  review, completed by an agent, failed verification, disputed by the
+--  property manager, a fraud signal) is kept out of payouts until staff
+--  release it or reject its pay. One hold per job.
+-- ----------------------------------------------------------------------
+CREATE TABLE payout_holds (
+    id UUID PRIMARY KEY,
+    job_instance_id UUID NOT NULL REFERENCES job_instances (id) ON DELETE CASCADE,
+    worker_id UUID NOT NULL REFERENCES workers (id),
+    reason VARCHAR(30) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'HELD',
+    created_by UUID NULL,
+    resolved_by UUID NULL,
+    resolved_at TIMESTAMPTZ NULL,
+    resolution_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT payout_holds_reason_ck CHECK (
+        reason IN (
+            'FLAGGED_FOR_REVIEW', 'AGENT_COMPLETED', 'FAILED_VERIFICATION',
+            'PM_DISPUTE', 'FRAUD_SIGNAL'
+        )
+    ),
+    CONSTRAINT payout_holds_status_ck CHECK (
+        status IN ('HELD', 'RELEASED', 'REJECTED')
+    ),
+    CONSTRAINT payout_holds_job_uq UNIQUE (job_instance_id)
+);
+
+CREATE INDEX idx_payout_holds_worker
+ON payout_holds (worker_id, created_at DESC);
+
+CREATE INDEX idx_payout_holds_status
+ON payout_holds (status, created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_payout_holds_status;
+DROP INDEX IF EXISTS idx_payout_holds_worker;
+DROP TABLE IF EXISTS payout_holds;
@@ -0,0 +1,18 @@
+-- ----------------------------------------------------------------------
+--  Payout statements: when each payout was paid, for annual earnings
+--  summaries. Payouts already paid take their last update as the time.
+-- ----------------------------------------------------------------------
+ALTER TABLE worker_payouts
+ADD COLUMN paid_at TIMESTAMPTZ NULL;
+
+UPDATE worker_payouts SET paid_at = updated_at WHERE status = 'PAID';
+
+CREATE INDEX idx_worker_payouts_worker_paid
+ON worker_payouts (worker_id, paid_at)
+WHERE paid_at IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_worker_payouts_worker_paid;
+ALTER TABLE worker_payouts
+DROP COLUMN IF EXISTS paid_at;
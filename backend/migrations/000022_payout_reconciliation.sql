@@ -0,0 +1,32 @@
+-- ----------------------------------------------------------------------
+--  Reconciliation reports: the daily check of job pay against payouts
+--  and of payouts against Stripe. Each run keeps its findings.
+-- ----------------------------------------------------------------------
+CREATE TABLE reconciliation_reports (
+    id UUID PRIMARY KEY,
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    payouts_checked INT NOT NULL DEFAULT 0,
+    jobs_checked INT NOT NULL DEFAULT 0,
+    transfers_checked INT NOT NULL DEFAULT 0,
+    findings JSONB NOT NULL DEFAULT '[]'::jsonb,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT reconciliation_reports_status_ck CHECK (
+        status IN ('CLEAN', 'DISCREPANCIES', 'FAILED')
+    )
+);
+
+CREATE INDEX idx_reconciliation_reports_finished
+ON reconciliation_reports (finished_at DESC);
+
+CREATE INDEX idx_worker_payouts_created
+ON worker_payouts (created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_worker_payouts_created;
+DROP INDEX IF EXISTS idx_reconciliation_reports_finished;
+DROP TABLE IF EXISTS reconciliation_reports;
@@ -0,0 +1,87 @@
+-- ----------------------------------------------------------------------
+--  ACH payouts: workers' bank accounts for when Stripe Connect can't pay
+--  them, the NACHA files made for the bank and the returns imported from
+--  it. Payouts record the provider that last sent them.
+-- ----------------------------------------------------------------------
+CREATE TABLE worker_bank_accounts (
+    worker_id UUID PRIMARY KEY REFERENCES workers (id) ON DELETE CASCADE,
+    holder_name VARCHAR(22) NOT NULL,
+    routing_number CHAR(9) NOT NULL,
+    account_number_encrypted TEXT NOT NULL,
+    account_last4 VARCHAR(4) NOT NULL,
+    account_type VARCHAR(10) NOT NULL,
+    updated_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT worker_bank_accounts_type_ck CHECK (
+        account_type IN ('CHECKING', 'SAVINGS')
+    )
+);
+
+CREATE TABLE ach_batches (
+    id UUID PRIMARY KEY,
+    file_name VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    entry_count INT NOT NULL,
+    total_cents BIGINT NOT NULL,
+    effective_date DATE NOT NULL,
+    file_encrypted TEXT NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    submitted_by UUID NULL,
+    submitted_at TIMESTAMPTZ NULL,
+    CONSTRAINT ach_batches_status_ck CHECK (
+        status IN ('GENERATED', 'SUBMITTED')
+    )
+);
+
+CREATE INDEX idx_ach_batches_created
+ON ach_batches (created_at DESC);
+
+CREATE SEQUENCE ach_trace_seq;
+
+ALTER TABLE worker_payouts
+ADD COLUMN provider VARCHAR(10) NOT NULL DEFAULT 'STRIPE',
+ADD COLUMN ach_batch_id UUID NULL REFERENCES ach_batches (id) ON DELETE SET NULL,
+ADD COLUMN ach_trace_number VARCHAR(15) NULL,
+ADD CONSTRAINT worker_payouts_provider_ck CHECK (provider IN ('STRIPE', 'ACH'));
+
+CREATE INDEX idx_worker_payouts_ach_batch
+ON worker_payouts (ach_batch_id)
+WHERE ach_batch_id IS NOT NULL;
+
+CREATE INDEX idx_worker_payouts_ach_trace
+ON worker_payouts (ach_trace_number)
+WHERE ach_trace_number IS NOT NULL;
+
+CREATE TABLE ach_returns (
+    id UUID PRIMARY KEY,
+    trace_number VARCHAR(15) NOT NULL,
+    return_code VARCHAR(3) NOT NULL,
+    failure_reason VARCHAR(64) NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    payout_id UUID NULL REFERENCES worker_payouts (id) ON DELETE SET NULL,
+    batch_id UUID NULL REFERENCES ach_batches (id) ON DELETE SET NULL,
+    imported_by UUID NOT NULL,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT ach_returns_trace_code_uq UNIQUE (trace_number, return_code)
+);
+
+CREATE INDEX idx_ach_returns_imported
+ON ach_returns (imported_at DESC);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_ach_returns_imported;
+DROP TABLE IF EXISTS ach_returns;
+DROP INDEX IF EXISTS idx_worker_payouts_ach_trace;
+DROP INDEX IF EXISTS idx_worker_payouts_ach_batch;
+ALTER TABLE worker_payouts
+DROP CONSTRAINT IF EXISTS worker_payouts_provider_ck,
+DROP COLUMN IF EXISTS ach_trace_number,
+DROP COLUMN IF EXISTS ach_batch_id,
+DROP COLUMN IF EXISTS provider;
+DROP SEQUENCE IF EXISTS ach_trace_seq;
+DROP INDEX IF EXISTS idx_ach_batches_created;
+DROP TABLE IF EXISTS ach_batches;
+DROP TABLE IF EXISTS worker_bank_accounts;
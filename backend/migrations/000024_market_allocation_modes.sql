@@ -0,0 +1,22 @@
+-- ----------------------------------------------------------------------
+--  Allocation mode per market: every property in a market can hand out
+--  its newly released jobs by lottery. A property set to LOTTERY runs its
+--  own lottery whatever its market says.
+-- ----------------------------------------------------------------------
+CREATE TABLE market_allocation_modes (
+    market TEXT PRIMARY KEY,
+    allocation_mode VARCHAR(20) NOT NULL,
+    lottery_window_seconds INT NOT NULL DEFAULT 120,
+    updated_by UUID NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT market_allocation_modes_mode_ck CHECK (
+        allocation_mode IN ('FIRST_COME', 'LOTTERY')
+    ),
+    CONSTRAINT market_allocation_modes_window_ck CHECK (
+        lottery_window_seconds > 0
+    )
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS market_allocation_modes;
@@ -0,0 +1,47 @@
+-- ----------------------------------------------------------------------
+--  A job is in at most one live payout. Cash-outs that failed with no
+--  retry scheduled have given their jobs back and don't count. The check
+--  takes the worker's payout lock, the one the services hold while they
+--  add jobs to payouts, so two transactions can't both pass it.
+-- ----------------------------------------------------------------------
+CREATE OR REPLACE FUNCTION worker_payouts_job_paid_once() RETURNS TRIGGER AS $$
+DECLARE
+    dup UUID;
+BEGIN
+    IF NEW.kind = 'ON_DEMAND' AND NEW.status = 'FAILED' AND NEW.next_attempt_at IS NULL THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE'
+        AND NEW.job_instance_ids = OLD.job_instance_ids
+        AND NOT (OLD.kind = 'ON_DEMAND' AND OLD.status = 'FAILED' AND OLD.next_attempt_at IS NULL) THEN
+        RETURN NULL;
+    END IF;
+    IF cardinality(NEW.job_instance_ids) = 0 THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtext('worker_payouts:' || NEW.worker_id::text));
+    SELECT j.id INTO dup
+    FROM worker_payouts p, unnest(p.job_instance_ids) AS j(id)
+    WHERE p.id <> NEW.id
+      AND p.job_instance_ids && NEW.job_instance_ids
+      AND j.id = ANY(NEW.job_instance_ids)
+      AND NOT (p.kind = 'ON_DEMAND' AND p.status = 'FAILED' AND p.next_attempt_at IS NULL)
+    LIMIT 1;
+    IF dup IS NOT NULL THEN
+        RAISE EXCEPTION 'job % is already in a payout', dup
+            USING ERRCODE = 'unique_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- AFTER, so an insert that ON CONFLICT skips is never checked.
+CREATE TRIGGER trg_worker_payouts_job_paid_once
+AFTER INSERT OR UPDATE OF job_instance_ids, status, next_attempt_at ON worker_payouts
+FOR EACH ROW EXECUTE FUNCTION worker_payouts_job_paid_once();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS trg_worker_payouts_job_paid_once ON worker_payouts;
+DROP FUNCTION IF EXISTS worker_payouts_job_paid_once();
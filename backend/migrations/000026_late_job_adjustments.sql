@@ -0,0 +1,24 @@
+-- ----------------------------------------------------------------------
+--  A job completed after its period's payout was sent is paid by a
+--  LATE_JOB adjustment instead of a payout. The adjustment stands for the
+--  job being paid, so there is at most one per job.
+-- ----------------------------------------------------------------------
+ALTER TABLE worker_pay_adjustments
+DROP CONSTRAINT worker_pay_adjustments_kind_ck,
+ADD CONSTRAINT worker_pay_adjustments_kind_ck CHECK (
+    kind IN ('BONUS', 'REFERRAL_BONUS', 'CORRECTION', 'CLAWBACK', 'CARRY_FORWARD', 'LATE_JOB')
+);
+
+CREATE UNIQUE INDEX uq_worker_pay_adjustments_late_job
+ON worker_pay_adjustments (job_instance_id)
+WHERE kind = 'LATE_JOB' AND status <> 'REJECTED';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS uq_worker_pay_adjustments_late_job;
+DELETE FROM worker_pay_adjustments WHERE kind = 'LATE_JOB';
+ALTER TABLE worker_pay_adjustments
+DROP CONSTRAINT worker_pay_adjustments_kind_ck,
+ADD CONSTRAINT worker_pay_adjustments_kind_ck CHECK (
+    kind IN ('BONUS', 'REFERRAL_BONUS', 'CORRECTION', 'CLAWBACK', 'CARRY_FORWARD')
+);
@@ -0,0 +1,30 @@
+-- ----------------------------------------------------------------------
+--  Lottery weights use the worker's geocoded home address instead of the
+--  location sent with an accept, which the client controls.
+-- ----------------------------------------------------------------------
+ALTER TABLE workers
+ADD COLUMN home_latitude DOUBLE PRECISION,
+ADD COLUMN home_longitude DOUBLE PRECISION;
+
+-- ----------------------------------------------------------------------
+--  A property's allocation mode is NULL when it follows its market. Until
+--  now FIRST_COME meant the same thing, so a property could not opt out
+--  of a LOTTERY market; existing FIRST_COME rows keep following theirs.
+-- ----------------------------------------------------------------------
+ALTER TABLE properties
+ALTER COLUMN allocation_mode DROP NOT NULL,
+ALTER COLUMN allocation_mode DROP DEFAULT;
+
+UPDATE properties SET allocation_mode = NULL WHERE allocation_mode = 'FIRST_COME';
+
+---- create above / drop below ----
+
+UPDATE properties SET allocation_mode = 'FIRST_COME' WHERE allocation_mode IS NULL;
+
+ALTER TABLE properties
+ALTER COLUMN allocation_mode SET DEFAULT 'FIRST_COME',
+ALTER COLUMN allocation_mode SET NOT NULL;
+
+ALTER TABLE workers
+DROP COLUMN IF EXISTS home_longitude,
+DROP COLUMN IF EXISTS home_latitude;
@@ -0,0 +1,142 @@
+// backend/migrations/cmd/migrate/main.go
+//
+// Applies the embedded migrations.
+//
+//	migrate [flags] up            apply every pending migration
+//	migrate [flags] down [N]      roll back the last N migrations (default 1)
+//	migrate [flags] to VERSION    migrate up or down to VERSION (0 = empty)
+//	migrate [flags] status        list versions and whether they are applied
+//
+// With -runner-id and -run-number (or UNIQUE_RUNNER_ID and UNIQUE_RUN_NUMBER)
+// it connects as the isolated role utils.WithIsolatedRole derives and
+// migrates that role's schema.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/migrations"
+	"github.com/poofware/mono-repo/backend/migrations/migrate"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+func main() {
+	utils.InitLogger("migrate")
+
+	dbURL := flag.String("db-url", os.Getenv("DB_URL"), "database URL (env DB_URL)")
+	runnerID := flag.String("runner-id", os.Getenv("UNIQUE_RUNNER_ID"), "isolated schema runner id (env UNIQUE_RUNNER_ID)")
+	runNumber := flag.String("run-number", os.Getenv("UNIQUE_RUN_NUMBER"), "isolated schema run number (env UNIQUE_RUN_NUMBER)")
+	table := flag.String("table", migrate.DefaultTable, "version tracking table")
+	dryRun := flag.Bool("dry-run", false, "print what would run without changing anything")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: migrate [flags] up | down [N] | to VERSION | status\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *dbURL == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := migrate.Options{Table: *table, DryRun: *dryRun}
+	effectiveURL := *dbURL
+	if *runnerID != "" || *runNumber != "" {
+		var err error
+		effectiveURL, err = utils.WithIsolatedRole(*dbURL, *runnerID, *runNumber)
+		if err != nil {
+			utils.Logger.Fatal("Invalid isolated role: ", err)
+		}
+		opts.ExpectSchema = strings.ToLower(*runnerID + "-" + *runNumber)
+		utils.Logger.Infof("Using isolated schema; connecting as role %s", opts.ExpectSchema)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, effectiveURL, opts, flag.Args()); err != nil {
+		utils.Logger.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, url string, opts migrate.Options, args []string) error {
+	all, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	r := migrate.NewRunner(conn, all, opts)
+	var n int
+	switch cmd, rest := args[0], args[1:]; {
+	case cmd == "up" && len(rest) == 0:
+		n, err = r.Up(ctx)
+	case cmd == "down" && len(rest) <= 1:
+		steps := 1
+		if len(rest) == 1 {
+			if steps, err = strconv.Atoi(rest[0]); err != nil || steps < 1 {
+				return fmt.Errorf("down: %q is not a positive step count", rest[0])
+			}
+		}
+		n, err = r.Down(ctx, steps)
+	case cmd == "to" && len(rest) == 1:
+		target, pErr := strconv.ParseInt(rest[0], 10, 64)
+		if pErr != nil {
+			return fmt.Errorf("to: %q is not a version", rest[0])
+		}
+		n, err = r.To(ctx, target)
+	case cmd == "status" && len(rest) == 0:
+		return printStatus(ctx, r)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		utils.Logger.Infof("[dry run] %d migration(s) would run", n)
+	} else {
+		utils.Logger.Infof("%d migration(s) run", n)
+	}
+	return nil
+}
+
+func printStatus(ctx context.Context, r *migrate.Runner) error {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, s := range statuses {
+		state, at := "pending", ""
+		if s.Applied {
+			state, at = "applied", s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		switch {
+		case s.Missing:
+			state += " (not on disk)"
+		case s.Modified:
+			state += " (modified)"
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", s.Version, s.Name, state, at)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,10 @@
+// Package migrations embeds the schema migrations so they ship inside the
+// binaries that apply them. See the migrate package for the runner.
+package migrations
+
+import "embed"
+
+// FS holds every NNNNNN_name.sql file in this directory.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,89 @@
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marker separates a file's up section (above) from its down section (below).
+const Marker = "---- create above / drop below ----"
+
+var fileNameRe = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_]+)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 of the whole file
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// Parse splits a migration file at the first Marker. Everything after it,
+// including any later marker line, is the down section.
+func Parse(version int64, name string, src []byte) (*Migration, error) {
+	text := string(src)
+	i := strings.Index(text, Marker)
+	if i < 0 {
+		return nil, fmt.Errorf("migration %06d_%s: missing %q marker", version, name, Marker)
+	}
+	sum := sha256.Sum256(src)
+	return &Migration{
+		Version:  version,
+		Name:     name,
+		Up:       strings.TrimSpace(text[:i]),
+		Down:     strings.TrimSpace(text[i+len(Marker):]),
+		Checksum: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// Load reads the NNNNNN_name.sql files at the root of fsys in version
+// order. Other files are ignored; two files with one version are an error.
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Migration
+	seen := make(map[int64]string)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileNameRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: bad version: %w", e.Name(), err)
+		}
+		if prev, dup := seen[version]; dup {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", prev, e.Name(), version)
+		}
+		seen[version] = e.Name()
+
+		src, err := fs.ReadFile(fsys, path.Clean(e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		mig, err := Parse(version, m[2], src)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mig)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/poofware/mono-repo/backend/migrations"
+)
+
+func TestParseSplitsAtFirstMarker(t *testing.T) {
+	src := "CREATE TABLE a (id INT);\n" + Marker + "\nDROP TABLE a;\n" + Marker + "\n"
+	m, err := Parse(7, "a", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Up != "CREATE TABLE a (id INT);" {
+		t.Errorf("up = %q", m.Up)
+	}
+	if !strings.HasPrefix(m.Down, "DROP TABLE a;") || !strings.Contains(m.Down, Marker) {
+		t.Errorf("down = %q", m.Down)
+	}
+	if m.String() != "000007_a" {
+		t.Errorf("String() = %q", m.String())
+	}
+
+	if _, err := Parse(8, "b", []byte("CREATE TABLE b (id INT);")); err == nil {
+		t.Error("expected an error for a file without the marker")
+	}
+}
+
+func TestLoadOrdersAndRejectsDuplicates(t *testing.T) {
+	body := []byte("SELECT 1;\n" + Marker + "\nSELECT 2;\n")
+	fsys := fstest.MapFS{
+		"000010_later.sql": {Data: body},
+		"000002_early.sql": {Data: body},
+		"README.md":        {Data: []byte("not a migration")},
+	}
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 10 {
+		t.Fatalf("unexpected order: %v", got)
+	}
+
+	fsys["2_early_again.sql"] = &fstest.MapFile{Data: body}
+	if _, err := Load(fsys); err == nil {
+		t.Error("expected an error for two files with version 2")
+	}
+}
+
+func TestEmbeddedMigrationsParse(t *testing.T) {
+	all, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range all {
+		if m.Version != int64(i+1) {
+			t.Errorf("%s: expected version %d; versions must be contiguous", m, i+1)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Errorf("%s: empty up or down section", m)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+Runner applies migrations to the current schema of one connection and
+records each applied version in the tracking table, with the file's
+checksum and its down section. Down runs the recorded down section, so a
+migration edited after it was applied is still rolled back with the SQL
+that matches what was applied. Applied migrations must not be edited;
+schema changes go in a new migration.
+
+Every migration runs in its own transaction. A session advisory lock keyed
+on the schema stops two runners migrating the same schema at once, while
+isolated schemas in one database don't wait on each other.
+
+The tracking table replaces tern's schema_version. The first run against a
+schema tern migrated records tern's versions as applied instead of running
+them again. Nothing says which file contents tern ran, so those versions
+are recorded with UnknownChecksum and checked against ternBaseline, the
+files as tern last applied them, on every run.
+*/
+
+const (
+	DefaultTable = "schema_migrations"
+	ternTable    = "schema_version"
+	lockClassID  = 0x706f6f66 // "poof"
+
+	// UnknownChecksum marks a version adopted from tern, whose applied
+	// contents weren't recorded.
+	UnknownChecksum = "unknown"
+)
+
+// ternBaseline holds the sha256 of each migration file as tern applied it,
+// before this runner replaced tern. A version adopted from tern is only
+// trusted while its file still matches.
+var ternBaseline = map[int64]string{
+	1: "9d9dd222c4f14382e32e055d4424522dd6acc2dddf568dcada342fb9449cd30b",
+	2: "35962ce9849b05b247ae9f0ef2092a3bb137c95522417ab3be880102400321b6",
+	3: "4cce5edf5aa720abd3bb4976f917ba25ac74d4c59ae40912333af54070bed3d6",
+}
+
+// existsSQL looks only in the current schema; an isolated role's search_path
+// also reaches public.
+const existsSQL = `SELECT to_regclass(format('%I.%I', current_schema(), $1::text)) IS NOT NULL`
+
+var (
+	ErrChecksumMismatch = errors.New("migration changed after it was applied")
+	ErrUnverifiable     = errors.New("migration adopted from tern can't be verified")
+)
+
+type Options struct {
+	Table string // tracking table; defaults to DefaultTable
+	// ExpectSchema, if set, must be the connection's current schema. Set it
+	// to the isolated role's name so a missing schema fails loudly instead
+	// of migrating public.
+	ExpectSchema string
+	DryRun       bool // log what would run without changing anything
+}
+
+type Runner struct {
+	conn       *pgx.Conn
+	migrations []*Migration
+	opts       Options
+}
+
+// Status is one known version: on disk, applied, or both.
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // the file's checksum differs from the recorded one
+	Missing   bool // applied but no longer on disk
+}
+
+type appliedRow struct {
+	version   int64
+	name      string
+	checksum  string
+	downSQL   string
+	appliedAt time.Time
+}
+
+func NewRunner(conn *pgx.Conn, migrations []*Migration, opts Options) *Runner {
+	if opts.Table == "" {
+		opts.Table = DefaultTable
+	}
+	return &Runner{conn: conn, migrations: migrations, opts: opts}
+}
+
+// Latest returns the highest version on disk, or 0.
+func (r *Runner) Latest() int64 {
+	if len(r.migrations) == 0 {
+		return 0
+	}
+	return r.migrations[len(r.migrations)-1].Version
+}
+
+// Status lists every version on disk or in the tracking table.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	if _, err := r.checkSchema(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := r.loadApplied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Status
+	onDisk := make(map[int64]bool)
+	for _, m := range r.migrations {
+		onDisk[m.Version] = true
+		s := Status{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			at := a.appliedAt
+			s.Applied, s.AppliedAt = true, &at
+			s.Modified = checkApplied(m, a) != nil
+		}
+		out = append(out, s)
+	}
+	for v, a := range applied {
+		if !onDisk[v] {
+			at := a.appliedAt
+			out = append(out, Status{Version: v, Name: a.name, Applied: true, AppliedAt: &at, Missing: true})
+		}
+	}
+	sortStatuses(out)
+	return out, nil
+}
+
+// Up applies every pending migration.
+func (r *Runner) Up(ctx context.Context) (int, error) {
+	return r.To(ctx, r.Latest())
+}
+
+// Down rolls back the last steps applied migrations.
+func (r *Runner) Down(ctx context.Context, steps int) (n int, err error) {
+	if steps <= 0 {
+		return 0, nil
+	}
+	err = r.locked(ctx, func(applied map[int64]*appliedRow) error {
+		versions := appliedVersionsDesc(applied)
+		if steps > len(versions) {
+			steps = len(versions)
+		}
+		for _, v := range versions[:steps] {
+			if err := r.rollback(ctx, applied[v]); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// To migrates up or down until target is the highest applied version.
+// Target 0 rolls everything back.
+func (r *Runner) To(ctx context.Context, target int64) (n int, err error) {
+	if target < 0 {
+		return 0, fmt.Errorf("target version %d is negative", target)
+	}
+	if target > 0 && r.find(target) == nil {
+		return 0, fmt.Errorf("no migration with version %d", target)
+	}
+
+	err = r.locked(ctx, func(applied map[int64]*appliedRow) error {
+		for _, v := range appliedVersionsDesc(applied) {
+			if v <= target {
+				break
+			}
+			if err := r.rollback(ctx, applied[v]); err != nil {
+				return err
+			}
+			n++
+			delete(applied, v)
+		}
+
+		if err := r.verify(applied, target); err != nil {
+			return err
+		}
+		for _, m := range r.migrations {
+			if m.Version > target {
+				break
+			}
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			if err := r.apply(ctx, m); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// verify refuses to apply on top of a history that doesn't match the
+// files: a modified or unknown applied version, or a pending version below
+// one already applied.
+func (r *Runner) verify(applied map[int64]*appliedRow, target int64) error {
+	var highest int64
+	for v, a := range applied {
+		m := r.find(v)
+		if m == nil {
+			return fmt.Errorf("applied version %d (%s) is not in this build's migrations", v, a.name)
+		}
+		if err := checkApplied(m, a); err != nil {
+			return err
+		}
+		highest = max(highest, v)
+	}
+	for _, m := range r.migrations {
+		if m.Version > target || m.Version >= highest {
+			break
+		}
+		if _, ok := applied[m.Version]; !ok {
+			return fmt.Errorf("migration %s is pending but %d is already applied", m, highest)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) apply(ctx context.Context, m *Migration) error {
+	if r.opts.DryRun {
+		utils.Logger.Infof("[dry run] would apply %s", m)
+		utils.Logger.Debugf("[dry run] %s up:\n%s", m, m.Up)
+		return nil
+	}
+	start := time.Now()
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		// No arguments, so pgx sends the file as one simple-protocol query.
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(`
+            INSERT INTO %s (version, name, checksum, down_sql)
+            VALUES ($1,$2,$3,$4)
+        `, r.table()), m.Version, m.Name, m.Checksum, m.Down)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("apply %s: %w", m, err)
+	}
+	utils.Logger.Infof("Applied %s in %s", m, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func (r *Runner) rollback(ctx context.Context, a *appliedRow) error {
+	label := fmt.Sprintf("%06d_%s", a.version, a.name)
+	if r.opts.DryRun {
+		utils.Logger.Infof("[dry run] would roll back %s", label)
+		utils.Logger.Debugf("[dry run] %s down:\n%s", label, a.downSQL)
+		return nil
+	}
+	start := time.Now()
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if a.downSQL != "" {
+			if _, err := tx.Exec(ctx, a.downSQL); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE version=$1`, r.table()), a.version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("roll back %s: %w", label, err)
+	}
+	utils.Logger.Infof("Rolled back %s in %s", label, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// locked holds the schema's advisory lock while fn runs, creating the
+// tracking table first and passing it the applied versions.
+func (r *Runner) locked(ctx context.Context, fn func(applied map[int64]*appliedRow) error) (err error) {
+	schema, err := r.checkSchema(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = r.conn.Exec(ctx, `SELECT pg_advisory_lock($1, hashtext($2))`, lockClassID, schema); err != nil {
+		return fmt.Errorf("lock schema %s: %w", schema, err)
+	}
+	defer func() {
+		// Unlock even when ctx is done; a session lock outlives the error.
+		if _, uErr := r.conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1, hashtext($2))`, lockClassID, schema); uErr != nil && err == nil {
+			err = fmt.Errorf("unlock schema %s: %w", schema, uErr)
+		}
+	}()
+
+	if !r.opts.DryRun {
+		if err = r.ensureTable(ctx); err != nil {
+			return err
+		}
+	}
+	applied, err := r.loadApplied(ctx)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		if err = r.adoptTern(ctx, applied); err != nil {
+			return err
+		}
+	}
+	return fn(applied)
+}
+
+// checkSchema returns the schema unqualified names resolve to.
+func (r *Runner) checkSchema(ctx context.Context) (string, error) {
+	var schema *string
+	if err := r.conn.QueryRow(ctx, `SELECT current_schema()`).Scan(&schema); err != nil {
+		return "", err
+	}
+	if schema == nil {
+		return "", errors.New("no schema on the search_path exists")
+	}
+	if r.opts.ExpectSchema != "" && *schema != r.opts.ExpectSchema {
+		return "", fmt.Errorf("expected to migrate schema %q but current schema is %q; does the isolated schema exist?", r.opts.ExpectSchema, *schema)
+	}
+	return *schema, nil
+}
+
+func (r *Runner) ensureTable(ctx context.Context) error {
+	_, err := r.conn.Exec(ctx, fmt.Sprintf(`
+        CREATE TABLE IF NOT EXISTS %s (
+            version    BIGINT PRIMARY KEY,
+            name       TEXT NOT NULL,
+            checksum   TEXT NOT NULL,
+            down_sql   TEXT NOT NULL,
+            applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )
+    `, r.table()))
+	return err
+}
+
+// checkApplied reports whether m's file still matches what was applied as
+// a. A mismatch can't be fixed by re-running the migration without losing
+// data, so the advice is to restore the file and add a new one.
+func checkApplied(m *Migration, a *appliedRow) error {
+	want := a.checksum
+	if want == UnknownChecksum {
+		baseline, ok := ternBaseline[m.Version]
+		if !ok {
+			return fmt.Errorf("%w: %s has no baseline checksum", ErrUnverifiable, m)
+		}
+		want = baseline
+	}
+	if m.Checksum != want {
+		return fmt.Errorf("%w: %s; restore the applied file and put the change in a new migration", ErrChecksumMismatch, m)
+	}
+	return nil
+}
+
+// adoptTern records the versions tern applied as applied here, with
+// UnknownChecksum. It refuses to adopt a version whose file differs from
+// ternBaseline: the schema then isn't what the file describes.
+func (r *Runner) adoptTern(ctx context.Context, applied map[int64]*appliedRow) error {
+	var hasTern bool
+	if err := r.conn.QueryRow(ctx, existsSQL, ternTable).Scan(&hasTern); err != nil || !hasTern {
+		return err
+	}
+	var ternVersion int64
+	if err := r.conn.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM `+ternTable).Scan(&ternVersion); err != nil {
+		return fmt.Errorf("read %s: %w", ternTable, err)
+	}
+
+	for _, m := range r.migrations {
+		if m.Version > ternVersion {
+			break
+		}
+		a := &appliedRow{version: m.Version, name: m.Name, checksum: UnknownChecksum, downSQL: m.Down, appliedAt: time.Now()}
+		if err := checkApplied(m, a); err != nil {
+			return fmt.Errorf("adopt tern version %d: %w", m.Version, err)
+		}
+		if !r.opts.DryRun {
+			if err := r.conn.QueryRow(ctx, fmt.Sprintf(`
+                INSERT INTO %s (version, name, checksum, down_sql)
+                VALUES ($1,$2,$3,$4)
+                RETURNING applied_at
+            `, r.table()), m.Version, m.Name, UnknownChecksum, m.Down).Scan(&a.appliedAt); err != nil {
+				return err
+			}
+		}
+		applied[m.Version] = a
+		utils.Logger.Infof("Recorded %s as applied by tern", m)
+	}
+	return nil
+}
+
+func (r *Runner) loadApplied(ctx context.Context) (map[int64]*appliedRow, error) {
+	applied := make(map[int64]*appliedRow)
+
+	var exists bool
+	if err := r.conn.QueryRow(ctx, existsSQL, r.opts.Table).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := r.conn.Query(ctx, fmt.Sprintf(`
+        SELECT version, name, checksum, down_sql, applied_at FROM %s
+    `, r.table()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a := &appliedRow{}
+		if err := rows.Scan(&a.version, &a.name, &a.checksum, &a.downSQL, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.version] = a
+	}
+	return applied, rows.Err()
+}
+
+func (r *Runner) inTx(ctx context.Context, fn func(tx pgx.Tx) error) (err error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+	return fn(tx)
+}
+
+func (r *Runner) find(version int64) *Migration {
+	for _, m := range r.migrations {
+		if m.Version == version {
+			return m
+		}
+	}
+	return nil
+}
+
+func (r *Runner) table() string {
+	return pgx.Identifier{r.opts.Table}.Sanitize()
+}
+
+func appliedVersionsDesc(applied map[int64]*appliedRow) []int64 {
+	versions := make([]int64, 0, len(applied))
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+	return versions
+}
+
+func sortStatuses(s []Status) {
+	slices.SortFunc(s, func(a, b Status) int { return cmp.Compare(a.Version, b.Version) })
+}
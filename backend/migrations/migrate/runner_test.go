@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/poofware/mono-repo/backend/migrations"
+)
+
+func TestTernEraMigrationsMatchBaseline(t *testing.T) {
+	all, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range all {
+		if _, ok := ternBaseline[m.Version]; !ok {
+			continue
+		}
+		adopted := &appliedRow{version: m.Version, name: m.Name, checksum: UnknownChecksum}
+		if err := checkApplied(m, adopted); err != nil {
+			t.Errorf("%s was applied by tern and must not change: %v", m, err)
+		}
+	}
+}
+
+func TestCheckApplied(t *testing.T) {
+	m, err := Parse(3, "demo_mode", []byte("ALTER TABLE a ADD COLUMN b INT;\n"+Marker+"\nALTER TABLE a DROP COLUMN b;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkApplied(m, &appliedRow{checksum: m.Checksum}); err != nil {
+		t.Errorf("matching checksum: %v", err)
+	}
+	if err := checkApplied(m, &appliedRow{checksum: "other"}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("recorded checksum differs: got %v", err)
+	}
+	// Adopted from tern: compared with the baseline, which this file isn't.
+	if err := checkApplied(m, &appliedRow{checksum: UnknownChecksum}); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("adopted file differs from baseline: got %v", err)
+	}
+
+	later, err := Parse(99, "later", []byte("SELECT 1;\n"+Marker+"\nSELECT 2;\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkApplied(later, &appliedRow{checksum: UnknownChecksum}); !errors.Is(err, ErrUnverifiable) {
+		t.Errorf("adopted version without baseline: got %v", err)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	_ "time/tzdata" // Load timezone data
-	cron "github.com/robfig/cron/v3"
 
 	"github.com/poofware/mono-repo/backend/services/account-service/internal/app"
 	"github.com/poofware/mono-repo/backend/services/account-service/internal/config"
@@ -79,20 +78,17 @@ func main() {
 	checkrWebhookController := controllers.NewCheckrWebhookController(checkrService)
 	workerCheckrController := controllers.NewWorkerCheckrController(checkrService)
 
-	// Setup waitlist processing via cron (every 15 minutes)
-	c := cron.New()
-	_, schErr := c.AddFunc("*/15 * * * *", func() {
-		if err := waitlistService.ProcessWaitlist(context.Background()); err != nil {
-			utils.Logger.WithError(err).Error("Scheduled waitlist processing failed")
-		}
-	})
-	if schErr != nil {
-		utils.Logger.WithError(schErr).Fatal("Failed to schedule waitlist processing job")
+	// Setup waitlist processing via cron (every 15 minutes), one replica at a time
+	sched := utils.NewPostgresScheduler(cfg.AppName, application.DB, utils.SchedulerOptions{})
+	if err := sched.AddJob("waitlist-processing", "*/15 * * * *", 0, waitlistService.ProcessWaitlist); err != nil {
+		utils.Logger.WithError(err).Fatal("Failed to schedule waitlist processing job")
 	}
-	c.Start()
+	sched.Start()
+	defer sched.Stop()
 
 	workerUnversalLinksStripeController := controllers.NewWorkerUniversalLinksController(cfg.AppUrl)
 	wellKnownController := controllers.NewWellKnownController()
+	schedulerController := controllers.NewSchedulerController(cfg, sched)
 
 	// Start dynamic Checkr webhook if needed
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -158,6 +154,8 @@ func main() {
 	secured.HandleFunc(routes.PMBase, pmController.GetPMHandler).Methods(http.MethodGet)
 	secured.HandleFunc(routes.PMProperties, pmController.ListPropertiesHandler).Methods(http.MethodGet)
 
+	secured.HandleFunc(routes.AccountSchedulerRuns, schedulerController.RunsHandler).Methods(http.MethodGet)
+
 	// Worker Stripe
 	secured.HandleFunc(routes.WorkerStripeConnectFlowURL, workerStripeController.ConnectFlowURLHandler).Methods(http.MethodGet)
 	secured.HandleFunc(routes.WorkerStripeConnectFlowStatus, workerStripeController.ConnectFlowStatusHandler).Methods(http.MethodGet)
@@ -45,7 +45,8 @@ type Config struct {
 	LDFlag_DoRealMobileDeviceAttestation bool
 	LDFlag_CORSHighSecurity              bool
 	LDFlag_SendgridFromEmail             string
-	LDFlag_SendgridSandboxMode           bool // NEW
+	LDFlag_SendgridSandboxMode           bool     // NEW
+	LDFlag_OpsUserIDs                    []string // may read scheduler run history
 }
 
 const (
@@ -301,6 +302,15 @@ func LoadConfig() *Config {
 	}
 	utils.Logger.Debugf("dynamic_stripe_webhook_endpoint flag: %t", dynamicStripeWebhook)
 
+	// Comma-separated user IDs allowed to use ops endpoints
+	opsUserIDsFlag, err := ldClient.StringVariation("ops_user_ids", context, "")
+	if err != nil {
+		ldClient.Close()
+		utils.Logger.WithError(err).Fatal("Error retrieving ops_user_ids flag")
+	}
+	utils.Logger.Debugf("ops_user_ids flag: %s", opsUserIDsFlag)
+	opsUserIDs := utils.ParseOpsUserIDs(opsUserIDsFlag)
+
 	// Fetch LD_SDK_KEY_SHARED for shared LaunchDarkly flags
 	ldSDKKeyShared, ok := sharedSecrets["LD_SDK_KEY_SHARED"]
 	if !ok || ldSDKKeyShared == "" {
@@ -382,6 +392,7 @@ func LoadConfig() *Config {
 		LDFlag_CORSHighSecurity:              corsHighSecurityFlag,
 		LDFlag_SendgridFromEmail:             sendgridFromEmail,
 		LDFlag_SendgridSandboxMode:           sendgridSandboxMode, // NEW
+		LDFlag_OpsUserIDs:                    opsUserIDs,
 	}
 }
 
@@ -4,6 +4,9 @@ const (
 	// Health
 	Health = "/health"
 
+	// Scheduled job run history (all replicas)
+	AccountSchedulerRuns = "/api/v1/account/scheduler/runs"
+
 	// Worker (base)
 	WorkerBase               = "/api/v1/account/worker"
 	WorkerSubmitPersonalInfo = "/api/v1/account/worker/personal-info"
@@ -149,6 +149,7 @@ func (s *WorkerService) SubmitPersonalInfo(
 		stored.City = req.City
 		stored.State = normalizedState
 		stored.ZipCode = req.ZipCode
+		stored.HomeLatitude, stored.HomeLongitude = &lat, &lng
 		stored.VehicleYear = req.VehicleYear
 		stored.VehicleMake = req.VehicleMake
 		stored.VehicleModel = req.VehicleModel
@@ -255,6 +256,17 @@ func (s *WorkerService) PatchWorker(
 		if patchReq.ZipCode != nil {
 			stored.ZipCode = *patchReq.ZipCode
 		}
+		if patchReq.StreetAddress != nil || patchReq.City != nil || patchReq.State != nil || patchReq.ZipCode != nil {
+			// The home location weights job lotteries; a stale one would
+			// favour the old address, so drop it if the new one won't geocode.
+			address := fmt.Sprintf("%s, %s, %s %s", stored.StreetAddress, stored.City, stored.State, stored.ZipCode)
+			if lat, lng, gErr := utils.GeocodeAddress(address, s.cfg.GMapsAPIKey); gErr != nil {
+				utils.Logger.WithError(gErr).Warnf("Could not geocode new address for worker %s", wID)
+				stored.HomeLatitude, stored.HomeLongitude = nil, nil
+			} else {
+				stored.HomeLatitude, stored.HomeLongitude = &lat, &lng
+			}
+		}
 		if patchReq.VehicleYear != nil {
 			stored.VehicleYear = *patchReq.VehicleYear
 		}
@@ -5,7 +5,6 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	cron "github.com/robfig/cron/v3"
 	"github.com/rs/cors"
 
 	"github.com/poofware/mono-repo/backend/services/auth-service/internal/app"
@@ -184,39 +183,27 @@ func main() {
 	//----------------------------------------------------------------------
 	// Setup daily cleanup via cron
 	//----------------------------------------------------------------------
-	c := cron.New()
-
-	// verification codes
-	_, schErr1 := c.AddFunc("0 3 * * *", func() {
-		if e := verificationCleanupService.CleanupDaily(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("Scheduled verification-codes cleanup failed")
-		}
-	})
-	if schErr1 != nil {
-		utils.Logger.WithError(schErr1).Fatal("Failed to schedule verification-codes cleanup job")
-	}
-
-	// token cleanup
-	_, schErr2 := c.AddFunc("5 3 * * *", func() {
-		if e := tokenCleanupService.CleanupDaily(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("Scheduled token cleanup failed")
+	sched := utils.NewPostgresScheduler(cfg.AppName, application.DB, utils.SchedulerOptions{})
+	for _, job := range []struct {
+		name, spec string
+		run        func(ctx context.Context) error
+	}{
+		{"verification-codes-cleanup", "0 3 * * *", verificationCleanupService.CleanupDaily},
+		{"token-cleanup", "5 3 * * *", tokenCleanupService.CleanupDaily},
+		{"rate-limit-counter-cleanup", "10 3 * * *", rateLimitCleanupService.CleanupDaily},
+	} {
+		if err := sched.AddJob(job.name, job.spec, 0, job.run); err != nil {
+			utils.Logger.WithError(err).Fatal("Failed to schedule cleanup job")
 		}
-	})
-	if schErr2 != nil {
-		utils.Logger.WithError(schErr2).Fatal("Failed to schedule token cleanup job")
 	}
-
-	// rate limit counter cleanup
-	_, schErr3 := c.AddFunc("10 3 * * *", func() {
-		if e := rateLimitCleanupService.CleanupDaily(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("Scheduled rate limit counter cleanup failed")
-		}
-	})
-	if schErr3 != nil {
-		utils.Logger.WithError(schErr3).Fatal("Failed to schedule rate limit counter cleanup job")
-	}
-
-	c.Start()
+	sched.Start()
+	defer sched.Stop()
+
+	// Run history of the jobs above, across replicas (ops only)
+	schedulerProtected := v1Router.PathPrefix("/scheduler").Subrouter()
+	schedulerProtected.Use(middleware.AuthMiddleware(cfg.RSAPublicKey, cfg.LDFlag_DoRealMobileDeviceAttestation))
+	schedulerController := controllers.NewSchedulerController(cfg, sched)
+	schedulerProtected.HandleFunc("/runs", schedulerController.RunsHandler).Methods("GET")
 
 	allowedOrigins := []string{cfg.AppUrl}
 	if !cfg.LDFlag_CORSHighSecurity {
@@ -61,6 +61,7 @@ type Config struct {
 	LDFlag_UsingIsolatedSchema           bool
 	LDFlag_DoRealMobileDeviceAttestation bool
 	LDFlag_CORSHighSecurity              bool
+	LDFlag_OpsUserIDs                    []string // may read scheduler run history
 }
 
 // Constants for time-based configuration defaults.
@@ -333,6 +334,15 @@ func LoadConfig() *Config {
 	}
 	utils.Logger.Debugf("using_isolated_schema flag: %t", usingIsolatedSchemaFlag)
 
+	// Comma-separated user IDs allowed to use ops endpoints
+	opsUserIDsFlag, err := ldClient.StringVariation("ops_user_ids", context, "")
+	if err != nil {
+		ldClient.Close()
+		utils.Logger.WithError(err).Fatal("Error retrieving ops_user_ids flag")
+	}
+	utils.Logger.Debugf("ops_user_ids flag: %s", opsUserIDsFlag)
+	opsUserIDs := utils.ParseOpsUserIDs(opsUserIDsFlag)
+
 	//----------------------------------------------------------------------
 	// If shortTokenTTLFlag is true, override expiries and global limits.
 	//----------------------------------------------------------------------
@@ -431,6 +441,7 @@ func LoadConfig() *Config {
 		LDFlag_UsingIsolatedSchema:           usingIsolatedSchemaFlag,
 		LDFlag_DoRealMobileDeviceAttestation: doRealMobileDeviceAttestation,
 		LDFlag_CORSHighSecurity:              corsHighSecurity,
+		LDFlag_OpsUserIDs:                    opsUserIDs,
 	}
 }
 
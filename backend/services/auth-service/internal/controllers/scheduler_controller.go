@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/auth-service/internal/config"
+	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// SchedulerController exposes the service's scheduled job runs to ops.
+type SchedulerController struct {
+	cfg   *config.Config
+	sched *utils.Scheduler
+}
+
+func NewSchedulerController(cfg *config.Config, sched *utils.Scheduler) *SchedulerController {
+	return &SchedulerController{cfg: cfg, sched: sched}
+}
+
+// ----------------------------------------------------------------
+// GET /auth/v1/scheduler/runs?limit=
+// ----------------------------------------------------------------
+func (c *SchedulerController) RunsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.ContextKeyUserID).(string)
+	if !utils.RequireOpsUser(w, c.cfg.LDFlag_OpsUserIDs, userID) {
+		return
+	}
+	c.sched.RunsHandler(w, r)
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/poofware/mono-repo/backend/shared/go-middleware"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
-	"github.com/robfig/cron/v3"
 	"github.com/rs/cors"
 	_ "time/tzdata"
 )
@@ -34,8 +33,17 @@ func main() {
 
 	// Repositories
 	jobInstRepo := repositories.NewJobInstanceRepository(application.DB)
+	payItemRepo := repositories.NewJobPayItemRepository(application.DB)
 	defRepo := repositories.NewJobDefinitionRepository(application.DB)
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(application.DB)
+	adjustmentRepo := internal_repositories.NewWorkerAdjustmentRepository(application.DB)
+	disputeRepo := internal_repositories.NewWorkerDisputeRepository(application.DB)
+	scheduleRepo := internal_repositories.NewPayScheduleRepository(application.DB)
+	holdRepo := internal_repositories.NewPayoutHoldRepository(application.DB)
+	reconciliationRepo := internal_repositories.NewReconciliationReportRepository(application.DB)
+	bankAccountRepo := internal_repositories.NewWorkerBankAccountRepository(application.DB, cfg.DBEncryptionKey)
+	achBatchRepo := internal_repositories.NewACHBatchRepository(application.DB, cfg.DBEncryptionKey)
+	achReturnRepo := internal_repositories.NewACHReturnRepository(application.DB)
 	workerRepo := repositories.NewWorkerRepository(application.DB, cfg.DBEncryptionKey)
 	propRepo := repositories.NewPropertyRepository(application.DB) // NEW
 
@@ -46,10 +54,28 @@ func main() {
 	}
 
 	// Services
-	payoutService := services.NewPayoutService(cfg, workerRepo, jobInstRepo, payoutRepo)
+	// Durable background work: payout attempts, balance recovery, notifications.
+	queue := utils.NewPostgresJobQueue(cfg.AppName, application.DB, utils.JobQueueOptions{})
+	uow := repositories.NewUnitOfWork(application.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{})
+	payScheduleService := services.NewPayScheduleService(cfg, workerRepo, scheduleRepo)
+	payoutHoldService := services.NewPayoutHoldService(cfg, jobInstRepo, holdRepo, uow)
+	stripeLedger := services.NewStripeLedger()
+	// ACH only picks up payouts Stripe can't send, and only once our bank
+	// has enabled origination (the ach_originator flag).
+	var achProvider services.PayoutProvider
+	if cfg.LDFlag_ACHOriginator != nil {
+		achProvider = services.NewACHPayoutProvider(bankAccountRepo)
+	}
+	payoutService := services.NewPayoutService(cfg, workerRepo, jobInstRepo, payItemRepo, payoutRepo, adjustmentRepo, payScheduleService, payoutHoldService, uow, queue, services.NewStripePayoutProvider(cfg), achProvider, stripeLedger)
+	disputeService := services.NewDisputeService(cfg, workerRepo, payoutRepo, disputeRepo, uow, queue)
 	// MODIFIED: Inject PayoutService into EarningsService
-	earningsService := services.NewEarningsService(cfg, jobInstRepo, payoutRepo, defRepo, propRepo, payoutService)
+	earningsService := services.NewEarningsService(cfg, jobInstRepo, payoutRepo, defRepo, propRepo, payItemRepo, adjustmentRepo, payoutService, disputeService)
 	webhookCheckService := services.NewStripeWebhookCheckService()
+	adjustmentService := services.NewAdjustmentService(workerRepo, adjustmentRepo)
+	cashOutService := services.NewCashOutService(cfg, workerRepo, jobInstRepo, payItemRepo, payoutRepo, adjustmentRepo, uow, payoutService)
+	statementService := services.NewStatementService(workerRepo, jobInstRepo, defRepo, propRepo, payItemRepo, payoutRepo, adjustmentRepo, payScheduleService)
+	reconciliationService := services.NewReconciliationService(cfg, workerRepo, jobInstRepo, payItemRepo, payoutRepo, adjustmentRepo, holdRepo, reconciliationRepo, payScheduleService, stripeLedger)
+	achService := services.NewACHService(cfg, workerRepo, payoutRepo, bankAccountRepo, achBatchRepo, achReturnRepo, uow, payoutService)
 
 	// Start dynamic webhook manager
 	if err := payoutService.Start(context.Background()); err != nil {
@@ -65,6 +91,45 @@ func main() {
 	healthController := controllers.NewHealthController(application)
 	earningsController := controllers.NewEarningsController(earningsService)
 	stripeWebhookController := controllers.NewStripeWebhookController(cfg, payoutService, webhookCheckService)
+	adjustmentController := controllers.NewAdjustmentController(cfg, adjustmentService)
+	disputeController := controllers.NewDisputeController(cfg, disputeService)
+	cashOutController := controllers.NewCashOutController(cashOutService)
+	payScheduleController := controllers.NewPayScheduleController(cfg, payScheduleService)
+	payoutHoldController := controllers.NewPayoutHoldController(cfg, payoutHoldService)
+	statementController := controllers.NewStatementController(statementService)
+	reconciliationController := controllers.NewReconciliationController(cfg, reconciliationService)
+	achController := controllers.NewACHController(cfg, achService)
+
+	// Scheduled jobs run on one replica at a time (UTC schedule).
+	sched := utils.NewPostgresScheduler(cfg.AppName, application.DB, utils.SchedulerOptions{Location: time.UTC})
+	queueController := controllers.NewQueueController(cfg, queue, sched)
+
+	// Both run hourly; pay schedules decide when each payout is made and sent.
+	if cfg.LDFlag_UseShortPayPeriod {
+		utils.Logger.Warn("Short pay period: every worker is on the daily pay schedule")
+	}
+	if err := sched.AddJob("payout-aggregation", constants.PayoutAggregationCronSpec, constants.PayoutAggregationJobTimeout, payoutService.AggregateAndCreatePayouts); err != nil {
+		utils.Logger.WithError(err).Fatal("Failed to schedule payout aggregation cron")
+	}
+	if err := sched.AddJob("payout-processing", constants.PayoutProcessingCronSpec, constants.PayoutProcessingJobTimeout, payoutService.ProcessPendingPayouts); err != nil {
+		utils.Logger.WithError(err).Fatal("Failed to schedule pending payout processing cron")
+	}
+	// Daily; only reports discrepancies, see ReconciliationService.
+	if err := sched.AddJob("payout-reconciliation", constants.ReconciliationCronSpec, constants.ReconciliationJobTimeout, reconciliationService.Run); err != nil {
+		utils.Logger.WithError(err).Fatal("Failed to schedule payout reconciliation cron")
+	}
+	sched.Start()
+	defer sched.Stop()
+	utils.Logger.Info("Scheduled payout cron jobs")
+
+	queue.Start()
+	defer queue.Stop()
+
+	// Domain events from other services (job completions and cancellations).
+	relay := repositories.NewOutboxRelay(cfg.AppName, application.DB, repositories.OutboxRelayOptions{})
+	payoutService.SubscribeEvents(relay)
+	relay.Start()
+	defer relay.Stop()
 
 	// Router setup
 	router := mux.NewRouter()
@@ -78,47 +143,45 @@ func main() {
 	secured := router.NewRoute().Subrouter()
 	secured.Use(middleware.AuthMiddleware(cfg.RSAPublicKey, cfg.LDFlag_DoRealMobileDeviceAttestation))
 	secured.HandleFunc(routes.EarningsSummary, earningsController.GetEarningsSummaryHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsCashOut, cashOutController.QuoteHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsCashOut, cashOutController.CashOutHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsDisputes, disputeController.MineHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsDisputes, disputeController.OpenHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsDisputesReply, disputeController.ReplyHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsStatements, statementController.ListHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsStatementsPayout, statementController.PayoutHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsStatementsAnnual, statementController.AnnualHandler).Methods(http.MethodGet)
+
+	// Ops routes; handlers check the caller against ops_user_ids
+	secured.HandleFunc(routes.EarningsOpsAdjustments, adjustmentController.ListHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsAdjustments, adjustmentController.CreateHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsAdjustmentApprove, adjustmentController.ApproveHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsAdjustmentReject, adjustmentController.RejectHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsDisputes, disputeController.OpsListHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsDisputesTriage, disputeController.TriageHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsDisputesRequestInfo, disputeController.RequestInfoHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsDisputesApprove, disputeController.ApproveHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsDisputesDeny, disputeController.DenyHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPaySchedules, payScheduleController.ListHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsPaySchedules, payScheduleController.CreateHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPaySchedulesAssign, payScheduleController.AssignHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPaySchedulesUnassign, payScheduleController.UnassignHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPayoutHolds, payoutHoldController.ListHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsPayoutHolds, payoutHoldController.PlaceHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPayoutHoldsRelease, payoutHoldController.ReleaseHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsPayoutHoldsReject, payoutHoldController.RejectHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsSchedulerRuns, queueController.SchedulerRunsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsQueueDead, queueController.DeadJobsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsReconciliation, reconciliationController.LatestHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsACHBankAccounts, achController.BankAccountHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsACHBankAccounts, achController.SaveBankAccountHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsACHBatches, achController.ListBatchesHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsACHBatches, achController.GenerateBatchHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsACHBatchesFile, achController.BatchFileHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsACHBatchesSubmit, achController.SubmitBatchHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.EarningsOpsACHReturns, achController.ListReturnsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.EarningsOpsACHReturns, achController.ImportReturnsHandler).Methods(http.MethodPost)
 
-	// Cron job setup
-	c := cron.New(cron.WithLocation(time.UTC)) // Use UTC for cron scheduling
-
-	// Conditionally set cron specs based on LaunchDarkly flag
-	aggSpec := constants.PayoutAggregationCronSpec
-	procSpec := constants.PayoutProcessingCronSpec
-	if cfg.LDFlag_UseShortPayPeriod {
-		aggSpec = constants.ShortPayoutAggregationCronSpec
-		procSpec = constants.ShortPayoutProcessingCronSpec
-		utils.Logger.Warnf("Using short pay period cron specs: agg='%s', proc='%s'", aggSpec, procSpec)
-	}
-
-	// Schedule payout aggregation.
-	_, err = c.AddFunc(aggSpec, func() {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.PayoutAggregationJobTimeout)
-		defer cancel()
-		utils.Logger.Info("Starting payout aggregation cron job...")
-		if err := payoutService.AggregateAndCreatePayouts(ctx); err != nil {
-			utils.Logger.WithError(err).Error("Failed to aggregate weekly payouts")
-		}
-	})
-	if err != nil {
-		utils.Logger.WithError(err).Fatal("Failed to schedule payout aggregation cron")
-	}
-
-	// Schedule pending payout processing.
-	_, err = c.AddFunc(procSpec, func() {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.PayoutProcessingJobTimeout)
-		defer cancel()
-		utils.Logger.Info("Starting pending payout processing cron job...")
-		if err := payoutService.ProcessPendingPayouts(ctx); err != nil {
-			utils.Logger.WithError(err).Error("Failed to process pending payouts")
-		}
-	})
-	if err != nil {
-		utils.Logger.WithError(err).Fatal("Failed to schedule pending payout processing cron")
-	}
-
-	c.Start()
-	utils.Logger.Info("Scheduled payout cron jobs")
 
 	allowedOrigins := []string{cfg.AppUrl}
 	if !cfg.LDFlag_CORSHighSecurity {
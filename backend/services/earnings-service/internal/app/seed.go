@@ -9,7 +9,6 @@ import (
 	"github.com/jackc/pgx/v4"
 	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
 	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
-	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	seeding "github.com/poofware/mono-repo/backend/shared/go-seeding"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
@@ -43,10 +42,11 @@ func SeedAllTestData(
 		return nil
 	}
 
-	now := time.Now().UTC()
-	thisWeekStart := internal_utils.GetPayPeriodStartForDate(now)
-	lastWeekStart := thisWeekStart.AddDate(0, 0, -7)
-	weekBeforeLastStart := lastWeekStart.AddDate(0, 0, -7)
+	// Seeded payouts are for the last two periods of the default schedule.
+	schedule := internal_models.DefaultPaySchedule()
+	thisWeekStart, _ := schedule.PeriodAt(time.Now().UTC())
+	lastWeekStart, lastWeekEnd := schedule.PeriodAt(thisWeekStart.Add(-time.Minute))
+	weekBeforeLastStart, weekBeforeLastEnd := schedule.PeriodAt(lastWeekStart.Add(-time.Minute))
 
 	jobIDsWeekBeforeLast := []uuid.UUID{
 		uuid.MustParse(seeding.HistoricalJobWeekBeforeLast1),
@@ -60,13 +60,13 @@ func SeedAllTestData(
 
 	// Seed Payout 1 (Week before last) - Total: $67.00
 	// This payout uses the sentinel ID to ensure idempotency.
-	err := SeedPayoutIfNeeded(ctx, jobInstRepo, payoutRepo, defaultActiveWorkerID, weekBeforeLastStart, 6700, jobIDsWeekBeforeLast, &sentinelID)
+	err := SeedPayoutIfNeeded(ctx, jobInstRepo, payoutRepo, defaultActiveWorkerID, weekBeforeLastStart, weekBeforeLastEnd, 6700, jobIDsWeekBeforeLast, &sentinelID)
 	if err != nil {
 		return err
 	}
 
 	// Seed Payout 2 (Last week) - Total: $58.00
-	err = SeedPayoutIfNeeded(ctx, jobInstRepo, payoutRepo, defaultActiveWorkerID, lastWeekStart, 5800, jobIDsLastWeek, nil)
+	err = SeedPayoutIfNeeded(ctx, jobInstRepo, payoutRepo, defaultActiveWorkerID, lastWeekStart, lastWeekEnd, 5800, jobIDsLastWeek, nil)
 	if err != nil {
 		return err
 	}
@@ -75,20 +75,20 @@ func SeedAllTestData(
 	return nil
 }
 
-// SeedPayoutIfNeeded checks if a payout for a given worker and week exists, and creates it if not.
-// It validates that each job's service date falls within the specified payout week.
+// SeedPayoutIfNeeded checks if a payout for a given worker and pay period
+// [startDate, endDate) exists, and creates it if not.
 // An optional payoutID can be provided to use a specific ID.
 func SeedPayoutIfNeeded(
 	ctx context.Context,
 	jobRepo repositories.JobInstanceRepository,
 	repo internal_repositories.WorkerPayoutRepository,
 	workerID uuid.UUID,
-	startDate time.Time,
+	startDate, endDate time.Time,
 	amountCents int64,
 	jobIDs []uuid.UUID,
 	payoutID *uuid.UUID, // Optional: for using a specific ID like the sentinel
 ) error {
-	existing, err := repo.GetByWorkerAndWeek(ctx, workerID, startDate)
+	existing, err := repo.GetScheduledByPeriod(ctx, workerID, startDate)
 	if err != nil && err != pgx.ErrNoRows {
 		return fmt.Errorf("failed to check for existing payout for worker %s, week %s: %w", workerID, startDate.Format("2006-01-02"), err)
 	}
@@ -98,9 +98,6 @@ func SeedPayoutIfNeeded(
 		return nil
 	}
 
-	// A standard pay period is 7 days, so the end date is 6 days after the start.
-	endDate := startDate.AddDate(0, 0, 6)
-
 	id := uuid.New()
 	if payoutID != nil {
 		id = *payoutID
@@ -109,8 +106,9 @@ func SeedPayoutIfNeeded(
 	payout := &internal_models.WorkerPayout{
 		ID:                id,
 		WorkerID:          workerID,
-		WeekStartDate:     startDate,
-		WeekEndDate:       endDate,
+		PeriodStart:       startDate,
+		PeriodEnd:         endDate,
+		PayScheduleID:     utils.Ptr(internal_models.DefaultPayScheduleID),
 		AmountCents:       amountCents,
 		Status:            internal_models.PayoutStatusPaid,
 		JobInstanceIDs:    jobIDs,
@@ -5,11 +5,13 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	ld "github.com/launchdarkly/go-server-sdk/v7"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
@@ -35,6 +37,10 @@ type Config struct {
 	LDFlag_SendgridSandboxMode           bool
 	LDFlag_CORSHighSecurity              bool
 	LDFlag_SeedDbWithTestData            bool
+	LDFlag_OpsUserIDs                    []string // may manage pay adjustments
+	LDFlag_CashOutPolicy                 *internal_models.CashOutPolicy
+	LDFlag_PayoutHoldRules               *internal_models.PayoutHoldRules
+	LDFlag_ACHOriginator                 *internal_models.ACHOriginator // nil when ACH payouts are off
 }
 
 const (
@@ -194,6 +200,53 @@ func LoadConfig() *Config {
 	}
 	utils.Logger.Debugf("seed_db_with_test_data flag: %t", seedDbWithTestDataFlag)
 
+	// Comma-separated user IDs allowed to use ops endpoints
+	opsUserIDsFlag, err := ldClient.StringVariation("ops_user_ids", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving ops_user_ids flag")
+	}
+	utils.Logger.Debugf("ops_user_ids flag: %s", opsUserIDsFlag)
+	opsUserIDs := utils.ParseOpsUserIDs(opsUserIDsFlag)
+
+	// On-demand cash-out policy as JSON; empty keeps the built-in default
+	cashOutPolicyFlag, err := ldClient.StringVariation("cash_out_policy", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving cash_out_policy flag")
+	}
+	utils.Logger.Debugf("cash_out_policy flag: %s", cashOutPolicyFlag)
+	cashOutPolicy := internal_models.DefaultCashOutPolicy()
+	if strings.TrimSpace(cashOutPolicyFlag) != "" {
+		if cashOutPolicy, err = internal_models.ParseCashOutPolicy([]byte(cashOutPolicyFlag)); err != nil {
+			utils.Logger.WithError(err).Fatal("Invalid cash_out_policy flag")
+		}
+	}
+
+	// Payout hold rules as JSON; empty keeps the built-in default
+	payoutHoldRulesFlag, err := ldClient.StringVariation("payout_hold_rules", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving payout_hold_rules flag")
+	}
+	utils.Logger.Debugf("payout_hold_rules flag: %s", payoutHoldRulesFlag)
+	payoutHoldRules := internal_models.DefaultPayoutHoldRules()
+	if strings.TrimSpace(payoutHoldRulesFlag) != "" {
+		if payoutHoldRules, err = internal_models.ParsePayoutHoldRules([]byte(payoutHoldRulesFlag)); err != nil {
+			utils.Logger.WithError(err).Fatal("Invalid payout_hold_rules flag")
+		}
+	}
+
+	// ACH originator details as JSON; empty turns ACH payouts off
+	achOriginatorFlag, err := ldClient.StringVariation("ach_originator", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving ach_originator flag")
+	}
+	var achOriginator *internal_models.ACHOriginator
+	if strings.TrimSpace(achOriginatorFlag) != "" {
+		if achOriginator, err = internal_models.ParseACHOriginator([]byte(achOriginatorFlag)); err != nil {
+			utils.Logger.WithError(err).Fatal("Invalid ach_originator flag")
+		}
+		utils.Logger.Debugf("ach_originator flag: company %s", achOriginator.CompanyName)
+	}
+
 	ldSDKKeyShared, ok := sharedSecrets["LD_SDK_KEY_SHARED"]
 	if !ok {
 		utils.Logger.Fatal("LD_SDK_KEY_SHARED not found in BWS secrets (shared-env)")
@@ -249,6 +302,10 @@ func LoadConfig() *Config {
 		LDFlag_SendgridSandboxMode:           sgSandboxFlag,
 		LDFlag_CORSHighSecurity:              corsHighSecurityFlag,
 		LDFlag_SeedDbWithTestData:            seedDbWithTestDataFlag,
+		LDFlag_OpsUserIDs:                    opsUserIDs,
+		LDFlag_CashOutPolicy:                 cashOutPolicy,
+		LDFlag_PayoutHoldRules:               payoutHoldRules,
+		LDFlag_ACHOriginator:                 achOriginator,
 	}
 }
 
@@ -17,11 +17,13 @@ const (
 	ReasonWorkerNotFound         = "worker_record_not_found"
 	ReasonMissingStripeID        = "worker_missing_stripe_connect_id"
 	ReasonAccountPayoutsDisabled = "stripe_account_payouts_disabled"
+	ReasonMissingBankAccount     = "worker_missing_ach_bank_account"
 
 	// Stripe API call failures (for non-Stripe-error-code scenarios)
 	ReasonUnknownStripeAccountError  = "unknown_stripe_error_fetching_account"
 	ReasonUnknownStripeTransferError = "unknown_stripe_transfer_error"
 	ReasonPayoutInitiationFailed     = "payout_initiation_failed"
+	ReasonStripePayoutCanceled       = "stripe_payout_canceled"
 
 	// A payout attempt to a restricted account can fail with this code.
 	// This is a valid `failure_code` on a `payout` object.
@@ -37,6 +39,7 @@ const (
 const (
 	EmailSubjectPayoutFailureActionRequired = "Action Required: Your Poof Payout Has Failed"
 	EmailSubjectPayoutFailurePlatformIssue  = "URGENT: Platform Payout Failure for Worker %s"
+	EmailSubjectDisputeUpdate               = "Update on Your Poof Pay Dispute"
 	FinanceTeamEmail                        = "team@thepoofapp.com"
 	FinanceTeamName                         = "Poof Finance Team"
 	StripeExpressDashboardURL               = "https://connect.stripe.com/app/express"
@@ -45,25 +48,38 @@ const (
 // Payout Business Logic
 const (
 	MinimumPayoutAmountCents = 50
-	PayPeriodStartHourEST    = 4
-	DaysInWeek               = 7
 	EarningsSummaryDays      = 56 // 8 weeks
-	BusinessTimezone         = "America/New_York"
+	CashOutLookbackDays      = 14 // completed jobs older than this are left to scheduled payouts
+	PayoutCatchUpDays        = 28 // closed pay periods this recent are aggregated if they have no payout
+	BusinessTimezone         = "America/New_York" // cash-out days
+	StatementMaxRangeDays    = 3 * 366            // longest range one statement history request may cover
 )
 
 // Payout Job Scheduling and Timeouts
 const (
-	PayoutAggregationCronSpec       = "0 9 * * 1"  // 09:00 UTC on Mondays
-	PayoutProcessingCronSpec        = "0 13 * * 2" // 13:00 UTC on Tuesdays
-	ShortPayoutAggregationCronSpec  = "0 5 * * *"  // 05:00 UTC Daily
-	ShortPayoutProcessingCronSpec   = "0 6 * * *"  // 06:00 UTC Daily
+	PayoutAggregationCronSpec       = "5 * * * *"  // hourly; pay periods close on each schedule's hour
+	PayoutProcessingCronSpec        = "35 * * * *" // hourly sweep for payouts a queued job missed
 	PayoutAggregationJobTimeout     = 15 * time.Minute
 	PayoutProcessingJobTimeout      = 10 * time.Minute
+	ReconciliationCronSpec          = "50 6 * * *" // daily, after the night's payouts have settled
+	ReconciliationJobTimeout        = 20 * time.Minute
 )
 
-// Payout Recovery Logic
+// Financial reconciliation
 const (
-	BalanceRecoveryProcessTimeout = 10 * time.Minute
-	BalanceRecoveryInitialDelay   = 5 * time.Second
-	BalanceRecoveryInitialBackoff = 10 * time.Second
+	ReconciliationLookbackDays = 35            // payouts created and jobs serviced this recently are checked
+	ReconciliationOrphanGrace  = 6 * time.Hour // a closed period's jobs have this long to land in a payout
+)
+
+// ACH payouts
+const (
+	ACHReturnReasonPrefix = "ach_return_" // failure reason for return codes with no Stripe equivalent
+	ACHMaxReturnFileBytes = 5 << 20
+	ACHBatchListLimit     = 50
+)
+
+// Payout Recovery Logic (run on the job queue, which backs off between attempts)
+const (
+	BalanceRecoveryInitialDelay = 5 * time.Second
+	BalanceRecoveryMaxAttempts  = 5
 )
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// ACHController serves the ops endpoints for ACH payouts: workers' bank
+// accounts, NACHA batch files and return files.
+type ACHController struct {
+	cfg        *config.Config
+	achService *services.ACHService
+}
+
+func NewACHController(cfg *config.Config, s *services.ACHService) *ACHController {
+	return &ACHController{cfg: cfg, achService: s}
+}
+
+func respondACHError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrInvalidBankAccount), errors.Is(err, internal_utils.ErrInvalidReturnFile):
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrACHBatchNotFound):
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "ACH batch not found", nil, err)
+	case errors.Is(err, internal_utils.ErrACHDisabled),
+		errors.Is(err, internal_utils.ErrNothingToBatch),
+		errors.Is(err, internal_utils.ErrACHBatchSubmitted):
+		utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, err.Error(), nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/ach/bank-accounts?worker_id=
+// ----------------------------------------------------------------
+func (c *ACHController) BankAccountHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	workerID, err := uuid.Parse(r.URL.Query().Get("worker_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid worker_id", nil, err)
+		return
+	}
+	acct, err := c.achService.BankAccount(r.Context(), workerID)
+	if err != nil {
+		respondACHError(w, err, "get bank account")
+		return
+	}
+	if acct == nil {
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Worker has no bank account", nil, nil)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, acct)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/ach/bank-accounts
+// ----------------------------------------------------------------
+func (c *ACHController) SaveBankAccountHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.SaveBankAccountRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	acct, err := c.achService.SaveBankAccount(r.Context(), actorID, req)
+	if err != nil {
+		respondACHError(w, err, "save bank account")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, acct)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/ach/batches
+// ----------------------------------------------------------------
+func (c *ACHController) ListBatchesHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	batches, err := c.achService.Batches(r.Context())
+	if err != nil {
+		respondACHError(w, err, "list ACH batches")
+		return
+	}
+	if batches == nil {
+		batches = []*internal_models.ACHBatch{}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.ACHBatchListResponse{Batches: batches})
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/ach/batches
+// ----------------------------------------------------------------
+func (c *ACHController) GenerateBatchHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	batch, err := c.achService.Generate(r.Context(), actorID)
+	if err != nil {
+		respondACHError(w, err, "generate ACH batch")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, batch)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/ach/batches/file?id=
+// ----------------------------------------------------------------
+func (c *ACHController) BatchFileHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	id, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid id", nil, err)
+		return
+	}
+	batch, err := c.achService.Batch(r.Context(), id)
+	if err != nil {
+		respondACHError(w, err, "get ACH batch")
+		return
+	}
+	respondFile(w, "text/plain", batch.FileName, batch.File)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/ach/batches/submit
+// ----------------------------------------------------------------
+func (c *ACHController) SubmitBatchHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.SubmitACHBatchRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	batch, err := c.achService.Submit(r.Context(), actorID, req.ID)
+	if err != nil {
+		respondACHError(w, err, "submit ACH batch")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, batch)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/ach/returns
+// ----------------------------------------------------------------
+func (c *ACHController) ListReturnsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	returns, err := c.achService.Returns(r.Context())
+	if err != nil {
+		respondACHError(w, err, "list ACH returns")
+		return
+	}
+	if returns == nil {
+		returns = []*internal_models.ACHReturn{}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.ACHReturnListResponse{Returns: returns})
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/ach/returns (body: the bank's return file)
+// ----------------------------------------------------------------
+func (c *ACHController) ImportReturnsHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, constants.ACHMaxReturnFileBytes))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Unreadable or oversized return file", nil, err)
+		return
+	}
+	resp, err := c.achService.ImportReturns(r.Context(), actorID, data)
+	if err != nil {
+		respondACHError(w, err, "import ACH returns")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
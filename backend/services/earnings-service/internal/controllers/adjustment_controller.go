@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// AdjustmentController serves the ops endpoints for worker pay adjustments.
+type AdjustmentController struct {
+	cfg               *config.Config
+	adjustmentService *services.AdjustmentService
+}
+
+func NewAdjustmentController(cfg *config.Config, s *services.AdjustmentService) *AdjustmentController {
+	return &AdjustmentController{cfg: cfg, adjustmentService: s}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/adjustments?worker_id=&status=&limit=
+// ----------------------------------------------------------------
+func (c *AdjustmentController) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	var workerID *uuid.UUID
+	if v := q.Get("worker_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid worker_id", nil, err)
+			return
+		}
+		workerID = &id
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	resp, err := c.adjustmentService.List(r.Context(), workerID, internal_models.AdjustmentStatusType(q.Get("status")), limit)
+	if err != nil {
+		utils.Logger.WithError(err).Error("List adjustments error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to list adjustments", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/adjustments
+// ----------------------------------------------------------------
+func (c *AdjustmentController) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+
+	var req dtos.CreateAdjustmentRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	adj, err := c.adjustmentService.Create(r.Context(), actorID, req)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInvalidAdjustment) {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("Create adjustment error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to create adjustment", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, adj)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/adjustments/approve
+// ----------------------------------------------------------------
+func (c *AdjustmentController) ApproveHandler(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.adjustmentService.Approve)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/adjustments/reject
+// ----------------------------------------------------------------
+func (c *AdjustmentController) RejectHandler(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, c.adjustmentService.Reject)
+}
+
+func (c *AdjustmentController) decide(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(ctx context.Context, actorID uuid.UUID, req dtos.DecideAdjustmentRequest) (*internal_models.WorkerAdjustment, error),
+) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+
+	var req dtos.DecideAdjustmentRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+
+	adj, err := fn(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrAdjustmentNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Adjustment not found", nil, err)
+		case errors.Is(err, internal_utils.ErrSelfApproval):
+			utils.RespondErrorWithCode(w, http.StatusForbidden, utils.ErrCodeUnauthorized, err.Error(), nil, err)
+		case errors.Is(err, internal_utils.ErrAdjustmentDecided):
+			utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, err.Error(), nil, err)
+		default:
+			utils.Logger.WithError(err).Error("Decide adjustment error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to update adjustment", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, adj)
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// CashOutController serves a worker's on-demand cash-outs.
+type CashOutController struct {
+	cashOutService *services.CashOutService
+}
+
+func NewCashOutController(s *services.CashOutService) *CashOutController {
+	return &CashOutController{cashOutService: s}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/cashout
+// ----------------------------------------------------------------
+func (c *CashOutController) QuoteHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	resp, err := c.cashOutService.Quote(r.Context(), workerID)
+	if err != nil {
+		respondCashOutError(w, err, "quote cash-out")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/cashout
+// ----------------------------------------------------------------
+func (c *CashOutController) CashOutHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req dtos.CashOutRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	resp, err := c.cashOutService.CashOut(r.Context(), workerID, req.Method)
+	if err != nil {
+		respondCashOutError(w, err, "cash out")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, resp)
+}
+
+func respondCashOutError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrCashOutIneligible):
+		utils.RespondErrorWithCode(w, http.StatusForbidden, utils.ErrCodeUnauthorized, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrCashOutLimit):
+		utils.RespondErrorWithCode(w, http.StatusTooManyRequests, utils.ErrCodeRateLimitExceeded, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrNothingToCashOut):
+		utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, err.Error(), nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// DisputeController serves worker pay disputes: the worker endpoints for
+// opening, reading and replying to them, and the ops endpoints for working
+// them.
+type DisputeController struct {
+	cfg            *config.Config
+	disputeService *services.DisputeService
+}
+
+func NewDisputeController(cfg *config.Config, s *services.DisputeService) *DisputeController {
+	return &DisputeController{cfg: cfg, disputeService: s}
+}
+
+func respondDisputeError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrInvalidDispute):
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrDisputeNotFound):
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Dispute not found", nil, err)
+	case errors.Is(err, internal_utils.ErrDisputeExists), errors.Is(err, internal_utils.ErrDisputeWrongStatus):
+		utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, err.Error(), nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/disputes
+// ----------------------------------------------------------------
+func (c *DisputeController) OpenHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req dtos.OpenDisputeRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	resp, err := c.disputeService.Open(r.Context(), workerID, req)
+	if err != nil {
+		respondDisputeError(w, err, "open dispute")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/disputes[?id=]
+// ----------------------------------------------------------------
+func (c *DisputeController) MineHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	if v := r.URL.Query().Get("id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid id", nil, err)
+			return
+		}
+		resp, err := c.disputeService.GetForWorker(r.Context(), workerID, id)
+		if err != nil {
+			respondDisputeError(w, err, "load dispute")
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, resp)
+		return
+	}
+	resp, err := c.disputeService.ListForWorker(r.Context(), workerID)
+	if err != nil {
+		respondDisputeError(w, err, "list disputes")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/disputes/reply
+// ----------------------------------------------------------------
+func (c *DisputeController) ReplyHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	var req dtos.DisputeReplyRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	resp, err := c.disputeService.Reply(r.Context(), workerID, req)
+	if err != nil {
+		respondDisputeError(w, err, "reply to dispute")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/disputes?id= | ?worker_id=&status=&limit=
+// ----------------------------------------------------------------
+func (c *DisputeController) OpsListHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	q := r.URL.Query()
+	if v := q.Get("id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid id", nil, err)
+			return
+		}
+		resp, err := c.disputeService.Get(r.Context(), id)
+		if err != nil {
+			respondDisputeError(w, err, "load dispute")
+			return
+		}
+		utils.RespondWithJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	var workerID *uuid.UUID
+	if v := q.Get("worker_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid worker_id", nil, err)
+			return
+		}
+		workerID = &id
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	resp, err := c.disputeService.List(r.Context(), workerID, internal_models.DisputeStatusType(q.Get("status")), limit)
+	if err != nil {
+		respondDisputeError(w, err, "list disputes")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/disputes/triage
+// ----------------------------------------------------------------
+func (c *DisputeController) TriageHandler(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, c.disputeService.Triage)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/disputes/request-info
+// ----------------------------------------------------------------
+func (c *DisputeController) RequestInfoHandler(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, c.disputeService.RequestInfo)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/disputes/deny
+// ----------------------------------------------------------------
+func (c *DisputeController) DenyHandler(w http.ResponseWriter, r *http.Request) {
+	c.act(w, r, c.disputeService.Deny)
+}
+
+func (c *DisputeController) act(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(ctx context.Context, actorID uuid.UUID, req dtos.DisputeActionRequest) (*internal_models.WorkerDispute, error),
+) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.DisputeActionRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	d, err := fn(r.Context(), actorID, req)
+	if err != nil {
+		respondDisputeError(w, err, "update dispute")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, d)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/disputes/approve
+// ----------------------------------------------------------------
+func (c *DisputeController) ApproveHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.ApproveDisputeRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	d, err := c.disputeService.Approve(r.Context(), actorID, req)
+	if err != nil {
+		respondDisputeError(w, err, "approve dispute")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, d)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+var validate = validator.New()
+
+// callerID returns the authenticated caller's ID, writing the error
+// response if there isn't one.
+func callerID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	ctxUserID := r.Context().Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "No userID in context", nil, nil)
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(ctxUserID.(string))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "Invalid userID in context", nil, err)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// opsActor returns the caller's ID if they are an ops user (listed in the
+// ops_user_ids flag), writing the error response otherwise. Shared by every
+// /api/v1/earnings/ops handler.
+func opsActor(w http.ResponseWriter, r *http.Request, cfg *config.Config) (uuid.UUID, bool) {
+	actorID, ok := callerID(w, r)
+	if !ok {
+		return uuid.Nil, false
+	}
+	if !utils.RequireOpsUser(w, cfg.LDFlag_OpsUserIDs, actorID.String()) {
+		return uuid.Nil, false
+	}
+	return actorID, true
+}
+
+// decodeValid decodes the JSON body into dst and validates it, writing the
+// error response on failure.
+func decodeValid(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return false
+	}
+	if err := validate.StructCtx(r.Context(), dst); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return false
+	}
+	return true
+}
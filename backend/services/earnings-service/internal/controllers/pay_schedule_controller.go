@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// PayScheduleController serves the ops endpoints for pay schedules.
+type PayScheduleController struct {
+	cfg             *config.Config
+	scheduleService *services.PayScheduleService
+}
+
+func NewPayScheduleController(cfg *config.Config, s *services.PayScheduleService) *PayScheduleController {
+	return &PayScheduleController{cfg: cfg, scheduleService: s}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/pay-schedules
+// ----------------------------------------------------------------
+func (c *PayScheduleController) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	resp, err := c.scheduleService.List(r.Context())
+	if err != nil {
+		utils.Logger.WithError(err).Error("List pay schedules error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to list pay schedules", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/pay-schedules
+// ----------------------------------------------------------------
+func (c *PayScheduleController) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.CreatePayScheduleRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	sch, err := c.scheduleService.Create(r.Context(), actorID, req)
+	if err != nil {
+		respondPayScheduleError(w, err, "create pay schedule")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, sch)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/pay-schedules/assign
+// ----------------------------------------------------------------
+func (c *PayScheduleController) AssignHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.AssignPayScheduleRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	a, err := c.scheduleService.Assign(r.Context(), actorID, req)
+	if err != nil {
+		respondPayScheduleError(w, err, "assign pay schedule")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, a)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/pay-schedules/unassign
+// ----------------------------------------------------------------
+func (c *PayScheduleController) UnassignHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.UnassignPayScheduleRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	if err := c.scheduleService.Unassign(r.Context(), actorID, req); err != nil {
+		respondPayScheduleError(w, err, "unassign pay schedule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func respondPayScheduleError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrInvalidPaySchedule):
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrPayScheduleNotFound), errors.Is(err, internal_utils.ErrNotAssigned):
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, err.Error(), nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// PayoutHoldController serves the ops endpoints for payout holds.
+type PayoutHoldController struct {
+	cfg         *config.Config
+	holdService *services.PayoutHoldService
+}
+
+func NewPayoutHoldController(cfg *config.Config, s *services.PayoutHoldService) *PayoutHoldController {
+	return &PayoutHoldController{cfg: cfg, holdService: s}
+}
+
+func respondHoldError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrInvalidHold):
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrHoldNotFound):
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Payout hold not found", nil, err)
+	case errors.Is(err, internal_utils.ErrAlreadyHeld), errors.Is(err, internal_utils.ErrHoldResolved):
+		utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, err.Error(), nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/payout-holds?worker_id=&status=&limit=
+// ----------------------------------------------------------------
+func (c *PayoutHoldController) ListHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	q := r.URL.Query()
+	var workerID *uuid.UUID
+	if v := q.Get("worker_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid worker_id", nil, err)
+			return
+		}
+		workerID = &id
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	resp, err := c.holdService.List(r.Context(), workerID, internal_models.HoldStatusType(q.Get("status")), limit)
+	if err != nil {
+		respondHoldError(w, err, "list payout holds")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/payout-holds
+// ----------------------------------------------------------------
+func (c *PayoutHoldController) PlaceHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.PlaceHoldRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	h, err := c.holdService.Place(r.Context(), actorID, req)
+	if err != nil {
+		respondHoldError(w, err, "place payout hold")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, h)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/payout-holds/release
+// ----------------------------------------------------------------
+func (c *PayoutHoldController) ReleaseHandler(w http.ResponseWriter, r *http.Request) {
+	c.resolve(w, r, c.holdService.Release)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/earnings/ops/payout-holds/reject
+// ----------------------------------------------------------------
+func (c *PayoutHoldController) RejectHandler(w http.ResponseWriter, r *http.Request) {
+	c.resolve(w, r, c.holdService.Reject)
+}
+
+func (c *PayoutHoldController) resolve(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(ctx context.Context, actorID uuid.UUID, req dtos.ResolveHoldRequest) (*internal_models.PayoutHold, error),
+) {
+	actorID, ok := opsActor(w, r, c.cfg)
+	if !ok {
+		return
+	}
+	var req dtos.ResolveHoldRequest
+	if !decodeValid(w, r, &req) {
+		return
+	}
+	h, err := fn(r.Context(), actorID, req)
+	if err != nil {
+		respondHoldError(w, err, "resolve payout hold")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, h)
+}
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// QueueController exposes the service's background work to ops: dead-lettered
+// jobs and the scheduler's run history.
+type QueueController struct {
+	cfg   *config.Config
+	queue *utils.JobQueue
+	sched *utils.Scheduler
+}
+
+func NewQueueController(cfg *config.Config, q *utils.JobQueue, sched *utils.Scheduler) *QueueController {
+	return &QueueController{cfg: cfg, queue: q, sched: sched}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/scheduler/runs?limit=
+// ----------------------------------------------------------------
+func (c *QueueController) SchedulerRunsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	c.sched.RunsHandler(w, r)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/queue/dead?limit=
+// ----------------------------------------------------------------
+func (c *QueueController) DeadJobsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	c.queue.DeadJobsHandler(w, r)
+}
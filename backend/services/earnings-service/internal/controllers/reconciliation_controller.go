@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// ReconciliationController serves the ops endpoint for reconciliation
+// reports.
+type ReconciliationController struct {
+	cfg                   *config.Config
+	reconciliationService *services.ReconciliationService
+}
+
+func NewReconciliationController(cfg *config.Config, s *services.ReconciliationService) *ReconciliationController {
+	return &ReconciliationController{cfg: cfg, reconciliationService: s}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/ops/reconciliation
+// ----------------------------------------------------------------
+func (c *ReconciliationController) LatestHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.cfg); !ok {
+		return
+	}
+	rep, err := c.reconciliationService.Latest(r.Context())
+	if err != nil {
+		utils.Logger.WithError(err).Error("latest reconciliation report error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load reconciliation report", nil, err)
+		return
+	}
+	if rep == nil {
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Reconciliation has not run yet", nil, nil)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, rep)
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// StatementController serves workers' payout statements and annual
+// summaries, as JSON or as CSV or PDF downloads.
+type StatementController struct {
+	statementService *services.StatementService
+}
+
+func NewStatementController(s *services.StatementService) *StatementController {
+	return &StatementController{statementService: s}
+}
+
+func respondStatementError(w http.ResponseWriter, err error, op string) {
+	switch {
+	case errors.Is(err, internal_utils.ErrInvalidStatement):
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+	case errors.Is(err, internal_utils.ErrStatementNotFound):
+		utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Statement not found", nil, err)
+	default:
+		utils.Logger.WithError(err).Errorf("%s error", op)
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to "+op, nil, err)
+	}
+}
+
+// statementFormat reads the format query parameter, writing the error
+// response when it isn't one we render.
+func statementFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	switch f := r.URL.Query().Get("format"); f {
+	case "", services.StatementFormatJSON:
+		return services.StatementFormatJSON, true
+	case services.StatementFormatCSV, services.StatementFormatPDF:
+		return f, true
+	default:
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "format must be json, csv or pdf", nil, nil)
+		return "", false
+	}
+}
+
+// respondFile sends body as a download named filename.
+func respondFile(w http.ResponseWriter, contentType, filename string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		utils.Logger.WithError(err).Warn("Failed to write file download")
+	}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/statements?from=&to=
+// ----------------------------------------------------------------
+func (c *StatementController) ListHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	resp, err := c.statementService.List(r.Context(), workerID, q.Get("from"), q.Get("to"))
+	if err != nil {
+		respondStatementError(w, err, "list statements")
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/statements/payout?id=&format=json|csv|pdf
+// ----------------------------------------------------------------
+func (c *StatementController) PayoutHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	payoutID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid id", nil, err)
+		return
+	}
+	format, ok := statementFormat(w, r)
+	if !ok {
+		return
+	}
+	st, err := c.statementService.Payout(r.Context(), workerID, payoutID)
+	if err != nil {
+		respondStatementError(w, err, "build payout statement")
+		return
+	}
+
+	filename := fmt.Sprintf("poof-statement-%s-%s", st.Payout.PeriodEndDate, payoutID.String()[:8])
+	switch format {
+	case services.StatementFormatCSV:
+		body, err := services.PayoutStatementCSV(st)
+		if err != nil {
+			respondStatementError(w, err, "build payout statement")
+			return
+		}
+		respondFile(w, "text/csv; charset=utf-8", filename+".csv", body)
+	case services.StatementFormatPDF:
+		respondFile(w, "application/pdf", filename+".pdf", services.PayoutStatementPDF(st))
+	default:
+		utils.RespondWithJSON(w, http.StatusOK, st)
+	}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/earnings/statements/annual?year=&format=json|csv|pdf
+// ----------------------------------------------------------------
+func (c *StatementController) AnnualHandler(w http.ResponseWriter, r *http.Request) {
+	workerID, ok := callerID(w, r)
+	if !ok {
+		return
+	}
+	year := time.Now().Year()
+	if v := r.URL.Query().Get("year"); v != "" {
+		y, err := strconv.Atoi(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid year", nil, err)
+			return
+		}
+		year = y
+	}
+	format, ok := statementFormat(w, r)
+	if !ok {
+		return
+	}
+	sum, err := c.statementService.Annual(r.Context(), workerID, year)
+	if err != nil {
+		respondStatementError(w, err, "build annual summary")
+		return
+	}
+
+	filename := fmt.Sprintf("poof-earnings-%d", year)
+	if sum.YearToDate {
+		filename += "-ytd"
+	}
+	switch format {
+	case services.StatementFormatCSV:
+		body, err := services.AnnualSummaryCSV(sum)
+		if err != nil {
+			respondStatementError(w, err, "build annual summary")
+			return
+		}
+		respondFile(w, "text/csv; charset=utf-8", filename+".csv", body)
+	case services.StatementFormatPDF:
+		respondFile(w, "application/pdf", filename+".pdf", services.AnnualSummaryPDF(sum))
+	default:
+		utils.RespondWithJSON(w, http.StatusOK, sum)
+	}
+}
@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+)
+
+// SaveBankAccountRequest sets the bank account a worker's ACH payouts go
+// to, via POST /api/v1/earnings/ops/ach/bank-accounts. HolderName is what
+// the worker's bank sees, so it is cut to the 22 characters NACHA allows.
+type SaveBankAccountRequest struct {
+	WorkerID      uuid.UUID                       `json:"worker_id" validate:"required"`
+	HolderName    string                          `json:"holder_name" validate:"required,max=22"`
+	RoutingNumber string                          `json:"routing_number" validate:"required,len=9,numeric"`
+	AccountNumber string                          `json:"account_number" validate:"required,min=4,max=17,numeric"`
+	AccountType   internal_models.BankAccountType `json:"account_type" validate:"required,oneof=CHECKING SAVINGS"`
+}
+
+// SubmitACHBatchRequest records that a batch's file was uploaded to the
+// bank.
+type SubmitACHBatchRequest struct {
+	ID uuid.UUID `json:"id" validate:"required"`
+}
+
+// ACHBatchListResponse is the ops list of NACHA files, newest first.
+type ACHBatchListResponse struct {
+	Batches []*internal_models.ACHBatch `json:"batches"`
+}
+
+// ACHReturnListResponse is the ops list of imported returns, newest first.
+type ACHReturnListResponse struct {
+	Returns []*internal_models.ACHReturn `json:"returns"`
+}
+
+// ACHReturnImportResponse sums up an imported return file. Failed payouts
+// are retried or reported to the worker; Unmatched returns had a trace
+// number no payout was sent with; Duplicates were imported before.
+// Returns lists the returns recorded by this import.
+type ACHReturnImportResponse struct {
+	Failed     int                          `json:"failed"`
+	Unmatched  int                          `json:"unmatched"`
+	Duplicates int                          `json:"duplicates"`
+	Returns    []*internal_models.ACHReturn `json:"returns"`
+}
@@ -0,0 +1,32 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+)
+
+/*
+CreateAdjustmentRequest asks for a pay adjustment via
+POST /api/v1/earnings/ops/adjustments. Amount is signed dollars: bonuses are
+positive, clawbacks negative and corrections either. It waits for approval
+by another ops user before it is paid.
+*/
+type CreateAdjustmentRequest struct {
+	WorkerID        uuid.UUID                          `json:"worker_id" validate:"required"`
+	Kind            internal_models.AdjustmentKindType `json:"kind" validate:"required,oneof=BONUS REFERRAL_BONUS CORRECTION CLAWBACK"`
+	Amount          float64                            `json:"amount" validate:"required"`
+	Reason          string                             `json:"reason" validate:"required"`
+	JobInstanceID   *uuid.UUID                         `json:"job_instance_id,omitempty"`
+	RelatedPayoutID *uuid.UUID                         `json:"related_payout_id,omitempty"`
+}
+
+// DecideAdjustmentRequest approves or rejects a pending adjustment.
+type DecideAdjustmentRequest struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Note string    `json:"note"`
+}
+
+// AdjustmentListResponse is the ops list of adjustments, newest first.
+type AdjustmentListResponse struct {
+	Adjustments []*internal_models.WorkerAdjustment `json:"adjustments"`
+}
@@ -0,0 +1,42 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// CashOutRequest cashes out the worker's available earnings via
+// POST /api/v1/earnings/cashout.
+type CashOutRequest struct {
+	Method internal_models.PayoutMethodType `json:"method" validate:"required,oneof=STANDARD INSTANT"`
+}
+
+/*
+CashOutQuoteResponse is what the worker could cash out right now, from
+GET /api/v1/earnings/cashout. Available is before fees and already capped
+by what is left of today's limit; the fees are what each method would take
+from it. IneligibleReason says why Eligible is false.
+*/
+type CashOutQuoteResponse struct {
+	Eligible         bool         `json:"eligible"`
+	IneligibleReason string       `json:"ineligible_reason,omitempty"`
+	Available        models.Money `json:"available"`
+	JobCount         int          `json:"job_count"`
+	Adjustments      models.Money `json:"adjustments"`
+	StandardFee      models.Money `json:"standard_fee"`
+	InstantFee       models.Money `json:"instant_fee"`
+	DailyLimitLeft   models.Money `json:"daily_limit_left"`
+	CashOutsLeft     int          `json:"cash_outs_left"`
+}
+
+// CashOutResponse is the payout a cash-out created. Amount is what the
+// worker receives after Fee.
+type CashOutResponse struct {
+	PayoutID uuid.UUID                        `json:"payout_id"`
+	Method   internal_models.PayoutMethodType `json:"method"`
+	Amount   models.Money                     `json:"amount"`
+	Fee      models.Money                     `json:"fee"`
+	JobCount int                              `json:"job_count"`
+	Status   internal_models.PayoutStatusType `json:"status"`
+}
@@ -0,0 +1,93 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+OpenDisputeRequest opens a worker's dispute via
+POST /api/v1/earnings/disputes. It names the job, the payout week or both.
+Evidence is a list of links or notes; RequestedAmount is the dollars the
+worker thinks they are owed, if they know.
+*/
+type OpenDisputeRequest struct {
+	JobInstanceID   *uuid.UUID                          `json:"job_instance_id,omitempty"`
+	PayoutID        *uuid.UUID                          `json:"payout_id,omitempty"`
+	Category        internal_models.DisputeCategoryType `json:"category" validate:"required,oneof=UNDERPAID UNFAIR_CANCELLATION PAYOUT OTHER"`
+	Reason          string                              `json:"reason" validate:"required,max=4000"`
+	Evidence        []string                            `json:"evidence,omitempty" validate:"max=10,dive,required,max=2000"`
+	RequestedAmount *float64                            `json:"requested_amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+// DisputeReplyRequest is a worker's answer to a request for information.
+type DisputeReplyRequest struct {
+	ID       uuid.UUID `json:"id" validate:"required"`
+	Note     string    `json:"note" validate:"required,max=4000"`
+	Evidence []string  `json:"evidence,omitempty" validate:"max=10,dive,required,max=2000"`
+}
+
+// DisputeActionRequest triages, asks for information on or denies a
+// dispute. Asking for information and denying need a note for the worker.
+type DisputeActionRequest struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Note string    `json:"note" validate:"max=4000"`
+}
+
+// ApproveDisputeRequest approves a dispute, creating a correction of Amount
+// dollars that goes through the usual adjustment approval.
+type ApproveDisputeRequest struct {
+	ID     uuid.UUID `json:"id" validate:"required"`
+	Amount float64   `json:"amount" validate:"required,gt=0"`
+	Note   string    `json:"note" validate:"max=4000"`
+}
+
+// DisputeDTO is a dispute as the worker sees it.
+type DisputeDTO struct {
+	ID              uuid.UUID                           `json:"id"`
+	JobInstanceID   *uuid.UUID                          `json:"job_instance_id,omitempty"`
+	PayoutID        *uuid.UUID                          `json:"payout_id,omitempty"`
+	Category        internal_models.DisputeCategoryType `json:"category"`
+	Reason          string                              `json:"reason"`
+	Evidence        []string                            `json:"evidence"`
+	RequestedAmount *models.Money                       `json:"requested_amount,omitempty"`
+	Status          internal_models.DisputeStatusType   `json:"status"`
+	ResolutionNote  string                              `json:"resolution_note,omitempty"`
+	CreatedAt       time.Time                           `json:"created_at"`
+	UpdatedAt       time.Time                           `json:"updated_at"`
+}
+
+// DisputeEventDTO is a step in a dispute's timeline as the worker sees it.
+type DisputeEventDTO struct {
+	Action    internal_models.DisputeActionType `json:"action"`
+	Status    internal_models.DisputeStatusType `json:"status"`
+	Note      string                            `json:"note,omitempty"`
+	Evidence  []string                          `json:"evidence,omitempty"`
+	ByWorker  bool                              `json:"by_worker"`
+	CreatedAt time.Time                         `json:"created_at"`
+}
+
+// DisputeListResponse lists a worker's disputes newest first.
+type DisputeListResponse struct {
+	Disputes []DisputeDTO `json:"disputes"`
+}
+
+// DisputeDetailResponse is a worker's dispute with its timeline.
+type DisputeDetailResponse struct {
+	Dispute DisputeDTO        `json:"dispute"`
+	Events  []DisputeEventDTO `json:"events"`
+}
+
+// OpsDisputeListResponse lists disputes newest first for staff.
+type OpsDisputeListResponse struct {
+	Disputes []*internal_models.WorkerDispute `json:"disputes"`
+}
+
+// OpsDisputeDetailResponse is a dispute with its full timeline for staff.
+type OpsDisputeDetailResponse struct {
+	Dispute *internal_models.WorkerDispute        `json:"dispute"`
+	Events  []*internal_models.WorkerDisputeEvent `json:"events"`
+}
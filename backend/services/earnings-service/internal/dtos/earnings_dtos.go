@@ -4,41 +4,69 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 )
 
+// PayItemDTO is one line of a completed job's pay: base pay, a surge, a
+// bonus, an adjustment or a clawback.
+type PayItemDTO struct {
+	Kind        models.PayItemKind `json:"kind"`
+	Label       string             `json:"label"`
+	Amount      models.Money       `json:"amount"`
+	Description string             `json:"description,omitempty"`
+}
+
 // CompletedJobDTO contains simplified details for a completed job instance.
+// Pay is the sum of PayItems.
 type CompletedJobDTO struct {
-	InstanceID      uuid.UUID  `json:"instance_id"`
-	PropertyName    string     `json:"property_name"`
-	Pay             float64    `json:"pay"`
-	CompletedAt     *time.Time `json:"completed_at,omitempty"`
-	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+	InstanceID      uuid.UUID    `json:"instance_id"`
+	PropertyName    string       `json:"property_name"`
+	Pay             models.Money `json:"pay"`
+	PayItems        []PayItemDTO `json:"pay_items"`
+	CompletedAt     *time.Time   `json:"completed_at,omitempty"`
+	DurationMinutes *int         `json:"duration_minutes,omitempty"`
 }
 
 // DailyEarningDTO represents the total earnings for a single day.
 type DailyEarningDTO struct {
-	Date        string            `json:"date"`                   // YYYY-MM-DD
-	TotalAmount float64           `json:"total_amount"`           // In dollars
+	Date        string            `json:"date"`         // YYYY-MM-DD
+	TotalAmount models.Money      `json:"total_amount"` // In dollars
 	JobCount    int               `json:"job_count"`
 	Jobs        []CompletedJobDTO `json:"jobs,omitempty"` // NEW: List of completed jobs
 }
 
-// WeeklyEarningsDTO represents a full week's earnings, broken down by day.
+// AdjustmentDTO is a bonus, correction or clawback paid with a week's
+// payout, or approved and waiting for the next one.
+type AdjustmentDTO struct {
+	Kind   string       `json:"kind"`
+	Amount models.Money `json:"amount"`
+	Reason string       `json:"reason"`
+}
+
+// WeeklyEarningsDTO represents a pay period's earnings, broken down by day;
+// the period's length follows the worker's pay schedule. A cash-out is listed
+// like a period of its own, covering the jobs it paid.
 type WeeklyEarningsDTO struct {
-	WeekStartDate      string            `json:"week_start_date"` // YYYY-MM-DD
-	WeekEndDate        string            `json:"week_end_date"`   // YYYY-MM-DD
-	WeeklyTotal        float64           `json:"weekly_total"`    // In dollars
+	WeekStartDate      string            `json:"week_start_date"` // first day of the pay period, YYYY-MM-DD
+	WeekEndDate        string            `json:"week_end_date"`   // last day of the pay period, YYYY-MM-DD
+	WeeklyTotal        models.Money      `json:"weekly_total"`    // In dollars
 	JobCount           int               `json:"job_count"`
-	PayoutStatus       string            `json:"payout_status"`   // PENDING, PROCESSING, PAID, FAILED, or CURRENT
+	PayoutStatus       string            `json:"payout_status"`           // PENDING, PROCESSING, PAID, FAILED, or CURRENT
+	PayoutKind         string            `json:"payout_kind,omitempty"`   // SCHEDULED or ON_DEMAND; empty for CURRENT
+	PayoutMethod       string            `json:"payout_method,omitempty"` // STANDARD or INSTANT; empty for CURRENT
+	Fee                *models.Money     `json:"fee,omitempty"`           // cash-out fee, already taken out of WeeklyTotal
 	DailyBreakdown     []DailyEarningDTO `json:"daily_breakdown"`
+	Adjustments        []AdjustmentDTO   `json:"adjustments,omitempty"` // included in WeeklyTotal for paid weeks
 	FailureReason      *string           `json:"failure_reason,omitempty"`
 	RequiresUserAction bool              `json:"requires_user_action"`
 }
 
 // EarningsSummaryResponse is the top-level response for the earnings summary endpoint.
 type EarningsSummaryResponse struct {
-	TwoMonthTotal  float64             `json:"two_month_total"`
+	TwoMonthTotal  models.Money        `json:"two_month_total"`
 	CurrentWeek    *WeeklyEarningsDTO  `json:"current_week"`
 	PastWeeks      []WeeklyEarningsDTO `json:"past_weeks"`
 	NextPayoutDate string              `json:"next_payout_date"`
+	Disputes       []DisputeDTO        `json:"disputes"`  // unresolved, or resolved within the summary window
+	HeldJobs       []HeldJobDTO        `json:"held_jobs"` // held or rejected jobs within the summary window; not in any total
 }
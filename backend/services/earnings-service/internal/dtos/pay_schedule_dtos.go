@@ -0,0 +1,44 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+)
+
+/*
+CreatePayScheduleRequest adds a pay schedule via
+POST /api/v1/earnings/ops/pay-schedules. AnchorDate (YYYY-MM-DD) is the
+first day of any one period; StartHour is when each period starts there, in
+Timezone. A new default schedule replaces the old one for every worker
+without an assignment.
+*/
+type CreatePayScheduleRequest struct {
+	Name             string                           `json:"name" validate:"required"`
+	Frequency        internal_models.PayFrequencyType `json:"frequency" validate:"required,oneof=DAILY WEEKLY BIWEEKLY"`
+	AnchorDate       string                           `json:"anchor_date" validate:"required,datetime=2006-01-02"`
+	StartHour        int                              `json:"start_hour" validate:"min=0,max=23"`
+	Timezone         string                           `json:"timezone" validate:"required"`
+	PayoutDelayHours int                              `json:"payout_delay_hours" validate:"min=0"`
+	IsDefault        bool                             `json:"is_default"`
+}
+
+// AssignPayScheduleRequest puts one worker or one market on a schedule.
+type AssignPayScheduleRequest struct {
+	PayScheduleID uuid.UUID  `json:"pay_schedule_id" validate:"required"`
+	WorkerID      *uuid.UUID `json:"worker_id,omitempty" validate:"required_without=Market,excluded_with=Market"`
+	Market        *string    `json:"market,omitempty" validate:"omitempty,min=1"`
+}
+
+// UnassignPayScheduleRequest takes a worker or market off its schedule,
+// back to their market's schedule or the default.
+type UnassignPayScheduleRequest struct {
+	WorkerID *uuid.UUID `json:"worker_id,omitempty" validate:"required_without=Market,excluded_with=Market"`
+	Market   *string    `json:"market,omitempty" validate:"omitempty,min=1"`
+}
+
+// PayScheduleListResponse lists every schedule, default first, and who is
+// assigned to which.
+type PayScheduleListResponse struct {
+	Schedules   []*internal_models.PaySchedule           `json:"schedules"`
+	Assignments []*internal_models.PayScheduleAssignment `json:"assignments"`
+}
@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+)
+
+// PlaceHoldRequest holds a completed job's pay by hand via
+// POST /api/v1/earnings/ops/payout-holds, after a property manager disputes
+// the job or a fraud review turns something up.
+type PlaceHoldRequest struct {
+	JobInstanceID uuid.UUID                      `json:"job_instance_id" validate:"required"`
+	Reason        internal_models.HoldReasonType `json:"reason" validate:"required,oneof=PM_DISPUTE FRAUD_SIGNAL"`
+	Details       string                         `json:"details" validate:"required,max=4000"`
+}
+
+// ResolveHoldRequest releases a hold or rejects the held job's pay.
+type ResolveHoldRequest struct {
+	ID   uuid.UUID `json:"id" validate:"required"`
+	Note string    `json:"note" validate:"max=4000"`
+}
+
+// HoldListResponse is the ops list of payout holds, newest first.
+type HoldListResponse struct {
+	Holds []*internal_models.PayoutHold `json:"holds"`
+}
+
+// HeldJobDTO is a completed job whose pay is held, or was rejected after
+// review.
+type HeldJobDTO struct {
+	CompletedJobDTO
+	HoldReason     string    `json:"hold_reason"`
+	HoldStatus     string    `json:"hold_status"` // HELD or REJECTED
+	HeldAt         time.Time `json:"held_at"`
+	ResolutionNote string    `json:"resolution_note,omitempty"`
+}
@@ -0,0 +1,97 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// StatementSummaryDTO is one payout in the worker's statement history.
+type StatementSummaryDTO struct {
+	PayoutID         uuid.UUID    `json:"payout_id"`
+	PeriodStartDate  string       `json:"period_start_date"` // YYYY-MM-DD
+	PeriodEndDate    string       `json:"period_end_date"`   // YYYY-MM-DD, inclusive
+	Kind             string       `json:"kind"`              // SCHEDULED or ON_DEMAND
+	Method           string       `json:"method"`            // STANDARD or INSTANT
+	Status           string       `json:"status"`
+	Amount           models.Money `json:"amount"` // what was sent, after fees
+	Fee              models.Money `json:"fee"`
+	Carried          models.Money `json:"carried"` // net carried forward to the next payout instead of sent
+	JobCount         int          `json:"job_count"`
+	PaidAt           *time.Time   `json:"paid_at,omitempty"`
+	StripeTransferID *string      `json:"stripe_transfer_id,omitempty"`
+	StripePayoutID   *string      `json:"stripe_payout_id,omitempty"`
+}
+
+// StatementListResponse lists the worker's payouts for periods starting in
+// the requested range, newest first.
+type StatementListResponse struct {
+	From       string                `json:"from"` // YYYY-MM-DD
+	To         string                `json:"to"`   // YYYY-MM-DD
+	Statements []StatementSummaryDTO `json:"statements"`
+}
+
+// StatementJobDTO is a job on a statement with the components of its pay.
+type StatementJobDTO struct {
+	InstanceID   uuid.UUID    `json:"instance_id"`
+	ServiceDate  string       `json:"service_date"` // YYYY-MM-DD
+	PropertyName string       `json:"property_name"`
+	PayItems     []PayItemDTO `json:"pay_items"`
+	Pay          models.Money `json:"pay"`
+	PayoutID     uuid.UUID    `json:"payout_id"`
+}
+
+// StatementAdjustmentDTO is a bonus, correction or clawback paid with a
+// payout.
+type StatementAdjustmentDTO struct {
+	Kind     string       `json:"kind"`
+	Reason   string       `json:"reason"`
+	Amount   models.Money `json:"amount"`
+	PayoutID uuid.UUID    `json:"payout_id"`
+}
+
+// PayoutStatementDTO is the statement for a single payout: its jobs,
+// adjustments and fee, and the references to trace the transfer.
+type PayoutStatementDTO struct {
+	WorkerName  string                   `json:"worker_name"`
+	Payout      StatementSummaryDTO      `json:"payout"`
+	Jobs        []StatementJobDTO        `json:"jobs"`
+	Adjustments []StatementAdjustmentDTO `json:"adjustments"`
+	JobsTotal   models.Money             `json:"jobs_total"`
+	// Net is JobsTotal plus adjustments, less the fee. Less Payout.Carried
+	// it matches Payout.Amount, unless a job's pay changed after it was
+	// sent.
+	Net         models.Money `json:"net"`
+	GeneratedAt time.Time    `json:"generated_at"`
+}
+
+// MonthTotalDTO is what was paid in one calendar month.
+type MonthTotalDTO struct {
+	Month string       `json:"month"` // YYYY-MM
+	Paid  models.Money `json:"paid"`
+}
+
+/*
+AnnualSummaryDTO totals the payouts paid to a worker in a calendar year, or
+in the year so far. It counts payouts by the day they were paid, in the
+worker's pay schedule timezone, and lists every job and adjustment they
+covered.
+*/
+type AnnualSummaryDTO struct {
+	WorkerName      string                   `json:"worker_name"`
+	Year            int                      `json:"year"`
+	Through         string                   `json:"through"` // YYYY-MM-DD; the last day counted
+	YearToDate      bool                     `json:"year_to_date"`
+	JobCount        int                      `json:"job_count"`
+	JobsTotal       models.Money             `json:"jobs_total"`
+	AdjustmentTotal models.Money             `json:"adjustment_total"`
+	FeeTotal        models.Money             `json:"fee_total"`
+	CarriedTotal    models.Money             `json:"carried_total"` // settled below the minimum and carried forward; paid as adjustments later
+	PaidTotal       models.Money             `json:"paid_total"`
+	Months          []MonthTotalDTO          `json:"months"`
+	Payouts         []StatementSummaryDTO    `json:"payouts"`
+	Jobs            []StatementJobDTO        `json:"jobs"`
+	Adjustments     []StatementAdjustmentDTO `json:"adjustments"`
+	GeneratedAt     time.Time                `json:"generated_at"`
+}
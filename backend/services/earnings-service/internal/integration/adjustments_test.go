@@ -0,0 +1,229 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// approveAdjustment writes an approved adjustment for the worker.
+func approveAdjustment(t *testing.T, f *payoutFixture, workerID uuid.UUID, kind internal_models.AdjustmentKindType, cents int64) *internal_models.WorkerAdjustment {
+	a := &internal_models.WorkerAdjustment{
+		WorkerID: workerID,
+		Kind:     kind,
+		Amount:   models.USD(cents),
+		Reason:   "adjustment test",
+		Status:   internal_models.AdjustmentStatusApproved,
+	}
+	require.NoError(t, f.adjRepo.Create(h.Ctx, a))
+	return a
+}
+
+// aggregateFor runs aggregation and returns the worker's payout for the
+// last closed period.
+func aggregateFor(t *testing.T, f *payoutFixture, workerID uuid.UUID) *internal_models.WorkerPayout {
+	require.NoError(t, f.payouts.AggregateAndCreatePayouts(h.Ctx))
+	p, err := f.payoutRepo.GetScheduledByPeriod(h.Ctx, workerID, getPreviousWeekPayPeriodStart())
+	require.NoError(t, err)
+	require.NotNil(t, p, "expected a payout for the last closed period")
+	return p
+}
+
+// carriedForward returns the worker's approved CARRY_FORWARD adjustment.
+func carriedForward(t *testing.T, f *payoutFixture, workerID uuid.UUID) *internal_models.WorkerAdjustment {
+	approved, err := f.adjRepo.ListApprovedForWorker(h.Ctx, workerID)
+	require.NoError(t, err)
+	var carried []*internal_models.WorkerAdjustment
+	for _, a := range approved {
+		if a.Kind == internal_models.AdjustmentKindCarryForward {
+			carried = append(carried, a)
+		}
+	}
+	require.Len(t, carried, 1)
+	return carried[0]
+}
+
+func TestAdjustmentsFoldIntoPayout(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	worker, jobIDs := createWorkerWithJobs(t, "adj-fold", getPreviousWeekPayPeriodStart(), 40.00)
+	bonus := approveAdjustment(t, f, worker.ID, internal_models.AdjustmentKindBonus, 500)
+	clawback := approveAdjustment(t, f, worker.ID, internal_models.AdjustmentKindClawback, -200)
+
+	p := aggregateFor(t, f, worker.ID)
+	require.Equal(t, internal_models.PayoutStatusPending, p.Status)
+	require.Equal(t, int64(4300), p.AmountCents)
+	require.Equal(t, int64(300), p.AdjustmentCents)
+	require.Zero(t, p.CarriedCents)
+	require.Equal(t, jobIDs, p.JobInstanceIDs)
+
+	for _, id := range []uuid.UUID{bonus.ID, clawback.ID} {
+		a, err := f.adjRepo.GetByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, internal_models.AdjustmentStatusApplied, a.Status)
+		require.Equal(t, p.ID, *a.PayoutID)
+	}
+}
+
+func TestAdjustmentsCarryForwardBelowMinimum(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	worker, jobIDs := createWorkerWithJobs(t, "adj-carry", getPreviousWeekPayPeriodStart(), 10.00)
+	approveAdjustment(t, f, worker.ID, internal_models.AdjustmentKindClawback, -1500)
+
+	// The clawback takes more than the period earned, so the payout is
+	// settled at its net with nothing sent and the debt moves on.
+	p := aggregateFor(t, f, worker.ID)
+	require.Equal(t, internal_models.PayoutStatusPaid, p.Status)
+	require.Equal(t, int64(-500), p.AmountCents)
+	require.Equal(t, int64(-500), p.CarriedCents)
+	require.Zero(t, p.SentCents())
+	require.Nil(t, p.NextAttemptAt)
+	require.Nil(t, p.StripeTransferID)
+
+	// Its jobs stay paid: their pay went into the carried balance.
+	paidOut, err := f.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	require.NoError(t, err)
+	require.True(t, paidOut[jobIDs[0]])
+
+	carry := carriedForward(t, f, worker.ID)
+	require.Equal(t, models.USD(-500), carry.Amount)
+	require.Equal(t, p.ID, *carry.RelatedPayoutID)
+}
+
+func TestPayoutEventCarriesExcessForward(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	relay, _ := newTestRelay(t, 0)
+	f.payouts.SubscribeEvents(relay)
+	relay.Start()
+
+	worker, jobIDs := createWorkerWithJobs(t, "adj-event", getPreviousWeekPayPeriodStart(), 40.00, 10.00)
+	approveAdjustment(t, f, worker.ID, internal_models.AdjustmentKindClawback, -4400)
+	p := aggregateFor(t, f, worker.ID)
+	require.Equal(t, internal_models.PayoutStatusPending, p.Status)
+	require.Equal(t, int64(600), p.AmountCents)
+
+	// Canceling the $40.00 job leaves the payout owing $34.00.
+	job, err := h.JobInstRepo.GetByID(ctx, jobIDs[0])
+	require.NoError(t, err)
+	_, err = h.DB.Exec(ctx, `UPDATE job_instances SET status = $2 WHERE id = $1`, job.ID, models.InstanceStatusCanceled)
+	require.NoError(t, err)
+	job.Status = models.InstanceStatusCanceled
+	require.NoError(t, repositories.PublishEvent(ctx, h.DB, models.EventJobCanceled, job.ID, models.NewJobInstanceEvent(job)))
+
+	require.Eventually(t, func() bool {
+		p, err = f.payoutRepo.GetByID(ctx, p.ID)
+		return err == nil && p.Status == internal_models.PayoutStatusPaid
+	}, 15*time.Second, 100*time.Millisecond)
+	require.Equal(t, int64(-3400), p.AmountCents)
+	require.Equal(t, int64(-3400), p.CarriedCents)
+	require.Equal(t, jobIDs[1:], p.JobInstanceIDs)
+
+	carry := carriedForward(t, f, worker.ID)
+	require.Equal(t, models.USD(-3400), carry.Amount)
+	require.Equal(t, p.ID, *carry.RelatedPayoutID)
+}
+
+func TestPayChangeOnPaidJobBecomesAdjustment(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	relay, _ := newTestRelay(t, 0)
+	f.payouts.SubscribeEvents(relay)
+	relay.Start()
+
+	worker, jobIDs := createWorkerWithJobs(t, "adj-paid", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+
+	// The job was cashed out, so the bonus can only reach the worker through
+	// their next payout.
+	item := &models.JobPayItem{JobInstanceID: jobIDs[0], Kind: models.PayItemManualBonus, Amount: models.USD(500)}
+	_, err = h.JobInstRepo.AddPayItemAtomic(ctx, item, 0, []models.InstanceStatusType{models.InstanceStatusCompleted})
+	require.NoError(t, err)
+
+	var a *internal_models.WorkerAdjustment
+	require.Eventually(t, func() bool {
+		a, err = f.adjRepo.GetByID(ctx, item.ID)
+		return err == nil && a != nil
+	}, 15*time.Second, 100*time.Millisecond)
+	require.Equal(t, internal_models.AdjustmentKindCorrection, a.Kind)
+	require.Equal(t, internal_models.AdjustmentStatusApproved, a.Status)
+	require.Equal(t, models.USD(500), a.Amount)
+	require.Equal(t, jobIDs[0], *a.JobInstanceID)
+	require.Equal(t, resp.PayoutID, *a.RelatedPayoutID)
+
+	// The cash-out itself is left as it was sent.
+	p, err := f.payoutRepo.GetByID(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3000), p.AmountCents)
+}
+
+func TestLateJobAfterSentPayoutBecomesAdjustment(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	relay, consumer := newTestRelay(t, 0)
+	f.payouts.SubscribeEvents(relay)
+	relay.Start()
+
+	worker, _ := createWorkerWithJobs(t, "adj-late", getPreviousWeekPayPeriodStart(), 20.00)
+	p := aggregateFor(t, f, worker.ID)
+	require.NoError(t, f.payoutRepo.UpdateWithRetry(ctx, p.ID, func(p *internal_models.WorkerPayout) error {
+		p.Status = internal_models.PayoutStatusPaid
+		return nil
+	}))
+
+	// Completed by an agent after the period was paid.
+	late := addCompletedJob(t, "adj-late-job", worker.ID, getPreviousWeekPayPeriodStart(), 35.00)
+	publish := func() {
+		require.NoError(t, repositories.PublishEvent(ctx, h.DB, models.EventJobCompleted, late.ID, models.NewJobInstanceEvent(late)))
+		waitForDelivery(t, consumer, late.ID)
+	}
+	lateAdjustments := func() []*internal_models.WorkerAdjustment {
+		all, err := f.adjRepo.List(ctx, &worker.ID, "", 10)
+		require.NoError(t, err)
+		var out []*internal_models.WorkerAdjustment
+		for _, a := range all {
+			if a.Kind == internal_models.AdjustmentKindLateJob {
+				out = append(out, a)
+			}
+		}
+		return out
+	}
+	publish()
+
+	adjs := lateAdjustments()
+	require.Len(t, adjs, 1)
+	require.Equal(t, internal_models.AdjustmentStatusApproved, adjs[0].Status)
+	require.Equal(t, models.USD(3500), adjs[0].Amount)
+	require.Equal(t, late.ID, *adjs[0].JobInstanceID)
+	require.Equal(t, p.ID, *adjs[0].RelatedPayoutID)
+
+	sent, err := f.payoutRepo.GetByID(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2000), sent.AmountCents)
+	require.NotContains(t, sent.JobInstanceIDs, late.ID)
+
+	// The adjustment stands for the job: a cash-out pays it once, as the
+	// adjustment, and a redelivered event adds nothing.
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+	require.Zero(t, resp.JobCount)
+	require.Equal(t, models.USD(3500), resp.Amount)
+
+	publish()
+	require.Len(t, lateAdjustments(), 1)
+}
@@ -0,0 +1,321 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// testCashOutPolicy lets any worker with a Stripe account cash out
+// straight away, up to two $100.00 cash-outs a day.
+func testCashOutPolicy() *internal_models.CashOutPolicy {
+	p := internal_models.DefaultCashOutPolicy()
+	p.HoldHours = 0
+	p.MinTenureDays = 0
+	p.MinReliabilityScore = 0
+	p.MaxCashOutsPerDay = 2
+	p.DailyLimitCents = 10000
+	return p
+}
+
+type payoutFixture struct {
+	// cfg is the fixture's own copy; changing it changes what its services
+	// see.
+	cfg        *config.Config
+	payouts    *services.PayoutService
+	holds      *services.PayoutHoldService
+	cashOut    *services.CashOutService
+	provider   *services.FakePayoutProvider
+	payoutRepo internal_repositories.WorkerPayoutRepository
+	adjRepo    internal_repositories.WorkerAdjustmentRepository
+}
+
+// newPayoutFixture builds the payout and cash-out services under policy,
+// sending through a fake provider on a queue of its own so the running
+// service doesn't pick up what the test queues.
+func newPayoutFixture(policy *internal_models.CashOutPolicy) *payoutFixture {
+	c := *cfg
+	c.LDFlag_CashOutPolicy = policy
+	tc := &c
+
+	f := &payoutFixture{
+		cfg:        tc,
+		provider:   &services.FakePayoutProvider{},
+		payoutRepo: internal_repositories.NewWorkerPayoutRepository(h.DB),
+		adjRepo:    internal_repositories.NewWorkerAdjustmentRepository(h.DB),
+	}
+	uow := repositories.NewUnitOfWork(h.DB, tc.DBEncryptionKey, repositories.UnitOfWorkOptions{})
+	queue := utils.NewPostgresJobQueue("it-cash-out-"+uuid.NewString()[:8], h.DB, utils.JobQueueOptions{})
+	f.holds = services.NewPayoutHoldService(tc, h.JobInstRepo, internal_repositories.NewPayoutHoldRepository(h.DB), uow)
+	f.payouts = services.NewPayoutService(tc, h.WorkerRepo, h.JobInstRepo, h.PayItemRepo, f.payoutRepo, f.adjRepo, services.NewPayScheduleService(tc, h.WorkerRepo, internal_repositories.NewPayScheduleRepository(h.DB)), f.holds, uow, queue, f.provider, nil, services.NewStripeLedger())
+	f.cashOut = services.NewCashOutService(tc, h.WorkerRepo, h.JobInstRepo, h.PayItemRepo, f.payoutRepo, f.adjRepo, uow, f.payouts)
+	return f
+}
+
+// createWorkerWithJobs creates a worker with a Stripe account of their own
+// and a completed job for each amount on serviceDate.
+func createWorkerWithJobs(t *testing.T, prefix string, serviceDate time.Time, amounts ...float64) (*models.Worker, []uuid.UUID) {
+	ctx := h.Ctx
+	worker := h.CreateTestWorkerWithConnectID(ctx, prefix, "acct_it_"+uuid.NewString()[:12])
+	prop := h.CreateTestProperty(ctx, prefix+"-prop", testPM.ID, 0, 0)
+	earliest, latest := h.TestSameDayTimeWindow()
+	def := h.CreateTestJobDefinition(t, ctx, testPM.ID, prop.ID, prefix+"-def", nil, nil, earliest, latest, models.JobStatusActive, nil, models.JobFreqDaily, nil)
+
+	var jobIDs []uuid.UUID
+	for _, amount := range amounts {
+		inst := h.CreateTestJobInstance(t, ctx, def.ID, serviceDate, models.InstanceStatusCompleted, &worker.ID, amount)
+		jobIDs = append(jobIDs, inst.ID)
+	}
+	return worker, jobIDs
+}
+
+func TestCashOutDailyLimitAndCount(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	worker, jobIDs := createWorkerWithJobs(t, "cashout-limit", time.Now().UTC().AddDate(0, 0, -1), 40.00, 40.00, 40.00)
+
+	// The third job would take the day past $100.00, so it waits.
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.JobCount)
+	require.Equal(t, models.USD(8000), resp.Amount)
+
+	p, err := f.payoutRepo.GetByID(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	require.Equal(t, internal_models.PayoutKindOnDemand, p.Kind)
+	require.ElementsMatch(t, jobIDs[:2], p.JobInstanceIDs)
+
+	quote, err := f.cashOut.Quote(ctx, worker.ID)
+	require.NoError(t, err)
+	require.False(t, quote.Eligible)
+	require.Equal(t, 1, quote.CashOutsLeft)
+	require.Equal(t, models.USD(2000), quote.DailyLimitLeft)
+
+	_, err = f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.ErrorIs(t, err, internal_utils.ErrCashOutLimit)
+
+	// With one cash-out a day, the count refuses before the amount does.
+	one := testCashOutPolicy()
+	one.MaxCashOutsPerDay = 1
+	_, err = newPayoutFixture(one).cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.ErrorIs(t, err, internal_utils.ErrCashOutLimit)
+	require.Contains(t, err.Error(), "1 cash-out(s) a day")
+}
+
+func TestCashOutRacesScheduledAggregation(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	policy := testCashOutPolicy()
+	policy.DailyLimitCents = 100000
+	f := newPayoutFixture(policy)
+	// Done in the last closed period, so aggregation wants them too.
+	worker, jobIDs := createWorkerWithJobs(t, "cashout-race", getPreviousWeekPayPeriodStart(), 20.00, 30.00, 40.00)
+
+	var wg sync.WaitGroup
+	var cashOutErr, aggErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, cashOutErr = f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	}()
+	go func() {
+		defer wg.Done()
+		aggErr = f.payouts.AggregateAndCreatePayouts(ctx)
+	}()
+	wg.Wait()
+	require.NoError(t, aggErr)
+	if cashOutErr != nil {
+		// Aggregation won the lock and took every job first.
+		require.ErrorIs(t, cashOutErr, internal_utils.ErrNothingToCashOut)
+	}
+
+	payouts, err := f.payoutRepo.PayoutIDsByJobs(ctx, jobIDs)
+	require.NoError(t, err)
+	for _, id := range jobIDs {
+		require.Len(t, payouts[id], 1, "job %s must be in exactly one payout", id)
+	}
+}
+
+func TestFailedCashOutReleasesJobs(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	worker, jobIDs := createWorkerWithJobs(t, "cashout-failed", time.Now().UTC().AddDate(0, 0, -1), 25.00)
+
+	bonus := &internal_models.WorkerAdjustment{
+		ID:       uuid.New(),
+		WorkerID: worker.ID,
+		Kind:     internal_models.AdjustmentKindBonus,
+		Amount:   models.USD(500),
+		Reason:   "cash-out release test",
+		Status:   internal_models.AdjustmentStatusApproved,
+	}
+	require.NoError(t, f.adjRepo.Create(ctx, bonus))
+
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+	require.Equal(t, models.USD(3000), resp.Amount)
+
+	paidOut, err := f.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	require.NoError(t, err)
+	require.True(t, paidOut[jobIDs[0]])
+
+	// The provider reports a failure the worker has to fix, so the cash-out
+	// is never resent.
+	p, err := f.payoutRepo.GetByID(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	f.provider.SetStatus(p.ID, services.PayoutState{Status: internal_models.PayoutStatusFailed, FailureReason: "account_closed"})
+	_, err = f.payouts.ReconcileStalePayout(context.Background(), p)
+	require.NoError(t, err)
+
+	p, err = f.payoutRepo.GetByID(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	require.Equal(t, internal_models.PayoutStatusFailed, p.Status)
+	require.Nil(t, p.NextAttemptAt)
+
+	paidOut, err = f.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	require.NoError(t, err)
+	require.False(t, paidOut[jobIDs[0]], "a failed cash-out must give its jobs back")
+
+	adj, err := f.adjRepo.GetByID(ctx, bonus.ID)
+	require.NoError(t, err)
+	require.Equal(t, internal_models.AdjustmentStatusApproved, adj.Status)
+	require.Nil(t, adj.PayoutID)
+
+	// Nor does it count against the day.
+	quote, err := f.cashOut.Quote(ctx, worker.ID)
+	require.NoError(t, err)
+	require.True(t, quote.Eligible, quote.IneligibleReason)
+	require.Equal(t, 2, quote.CashOutsLeft)
+	require.Equal(t, models.USD(3000), quote.Available)
+}
+
+// addCompletedJob gives worker a completed job of its own on serviceDate.
+func addCompletedJob(t *testing.T, prefix string, workerID uuid.UUID, serviceDate time.Time, amount float64) *models.JobInstance {
+	ctx := h.Ctx
+	prop := h.CreateTestProperty(ctx, prefix+"-prop", testPM.ID, 0, 0)
+	earliest, latest := h.TestSameDayTimeWindow()
+	def := h.CreateTestJobDefinition(t, ctx, testPM.ID, prop.ID, prefix+"-def", nil, nil, earliest, latest, models.JobStatusActive, nil, models.JobFreqDaily, nil)
+	return h.CreateTestJobInstance(t, ctx, def.ID, serviceDate, models.InstanceStatusCompleted, &workerID, amount)
+}
+
+// waitForDelivery waits until consumer has handled every event published
+// for aggregateID.
+func waitForDelivery(t *testing.T, consumer string, aggregateID uuid.UUID) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		var pending bool
+		err := h.DB.QueryRow(h.Ctx, `
+            SELECT EXISTS (
+                SELECT 1
+                FROM outbox_events e, outbox_consumer_offsets o
+                WHERE o.consumer = $1
+                  AND e.aggregate_id = $2
+                  AND (o.last_tx_id, o.last_event_id) < (e.tx_id, e.id)
+            )
+        `, consumer, aggregateID).Scan(&pending)
+		return err == nil && !pending
+	}, 15*time.Second, 100*time.Millisecond)
+}
+
+func TestCashOutRacesJobCompletedEvent(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+
+	for i := range 5 {
+		prefix := fmt.Sprintf("cashout-event-race-%d", i)
+		// The period's payout is written before the late job completes, so
+		// both the event and a cash-out want the job.
+		worker, _ := createWorkerWithJobs(t, prefix, getPreviousWeekPayPeriodStart(), 20.00)
+		p := aggregateFor(t, f, worker.ID)
+		late := addCompletedJob(t, prefix+"-late", worker.ID, getPreviousWeekPayPeriodStart(), 35.00)
+
+		relay, consumer := newTestRelay(t, 0)
+		f.payouts.SubscribeEvents(relay)
+		require.NoError(t, repositories.PublishEvent(ctx, h.DB, models.EventJobCompleted, late.ID, models.NewJobInstanceEvent(late)))
+
+		// The relay delivers the event as soon as it starts, while the
+		// cash-out runs.
+		relay.Start()
+		_, cashOutErr := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+		waitForDelivery(t, consumer, late.ID)
+		relay.Stop()
+
+		payouts, err := f.payoutRepo.PayoutIDsByJobs(ctx, []uuid.UUID{late.ID})
+		require.NoError(t, err)
+		require.Len(t, payouts[late.ID], 1, "round %d: the late job must be in exactly one payout", i)
+
+		p, err = f.payoutRepo.GetByID(ctx, p.ID)
+		require.NoError(t, err)
+		if cashOutErr != nil {
+			// The event took the lock first and added the job to the period.
+			require.ErrorIs(t, cashOutErr, internal_utils.ErrNothingToCashOut)
+			require.Equal(t, int64(5500), p.AmountCents)
+			continue
+		}
+		require.Equal(t, int64(2000), p.AmountCents)
+		require.NotContains(t, p.JobInstanceIDs, late.ID)
+	}
+}
+
+func TestJobIsInOneLivePayout(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newPayoutFixture(testCashOutPolicy())
+	worker, jobIDs := createWorkerWithJobs(t, "cashout-paid-once", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+
+	// Whatever the services check, the database refuses to pay it twice.
+	start, end := testPaySchedule().PeriodAt(time.Now())
+	second := func() *internal_models.WorkerPayout {
+		return &internal_models.WorkerPayout{
+			ID:             uuid.New(),
+			WorkerID:       worker.ID,
+			PeriodStart:    start,
+			PeriodEnd:      end,
+			AmountCents:    3000,
+			Status:         internal_models.PayoutStatusPending,
+			JobInstanceIDs: jobIDs,
+		}
+	}
+	err = f.payoutRepo.Create(ctx, second())
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "23505", pgErr.Code)
+
+	// Once the cash-out fails for good its job can be paid again.
+	p, err := f.payoutRepo.GetByID(ctx, resp.PayoutID)
+	require.NoError(t, err)
+	f.provider.SetStatus(p.ID, services.PayoutState{Status: internal_models.PayoutStatusFailed, FailureReason: "account_closed"})
+	_, err = f.payouts.ReconcileStalePayout(context.Background(), p)
+	require.NoError(t, err)
+	paid := second()
+	require.NoError(t, f.payoutRepo.Create(ctx, paid))
+
+	// The released cash-out no longer counts as paying them.
+	byJob, err := f.payoutRepo.PayoutIDsByJobs(ctx, jobIDs)
+	require.NoError(t, err)
+	for _, id := range jobIDs {
+		require.Equal(t, []uuid.UUID{paid.ID}, byJob[id])
+	}
+}
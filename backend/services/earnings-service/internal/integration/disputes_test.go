@@ -0,0 +1,197 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// newDisputeService builds a DisputeService whose emails go to a queue of
+// its own, which nothing works.
+func newDisputeService() *services.DisputeService {
+	uow := repositories.NewUnitOfWork(h.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{})
+	queue := utils.NewPostgresJobQueue("it-disputes-"+uuid.NewString()[:8], h.DB, utils.JobQueueOptions{})
+	return services.NewDisputeService(cfg, h.WorkerRepo, internal_repositories.NewWorkerPayoutRepository(h.DB), internal_repositories.NewWorkerDisputeRepository(h.DB), uow, queue)
+}
+
+func openDispute(t *testing.T, s *services.DisputeService, workerID, jobID uuid.UUID) *dtos.DisputeDTO {
+	amount := 12.50
+	d, err := s.Open(h.Ctx, workerID, dtos.OpenDisputeRequest{
+		JobInstanceID:   &jobID,
+		Category:        internal_models.DisputeCategoryUnderpaid,
+		Reason:          "paid for one building, cleaned two",
+		Evidence:        []string{"https://example.com/photo.jpg"},
+		RequestedAmount: &amount,
+	})
+	require.NoError(t, err)
+	return d
+}
+
+func TestOpenDispute(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	s := newDisputeService()
+	worker, jobIDs := createWorkerWithJobs(t, "dispute-open", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	other, _ := createWorkerWithJobs(t, "dispute-open-other", time.Now().UTC().AddDate(0, 0, -1))
+
+	d := openDispute(t, s, worker.ID, jobIDs[0])
+	require.Equal(t, internal_models.DisputeStatusOpen, d.Status)
+	require.Equal(t, models.USD(1250), *d.RequestedAmount)
+
+	detail, err := s.GetForWorker(ctx, worker.ID, d.ID)
+	require.NoError(t, err)
+	require.Len(t, detail.Events, 1)
+	require.Equal(t, internal_models.DisputeActionOpened, detail.Events[0].Action)
+	require.True(t, detail.Events[0].ByWorker)
+
+	// One unresolved dispute per job.
+	_, err = s.Open(ctx, worker.ID, dtos.OpenDisputeRequest{JobInstanceID: &jobIDs[0], Category: internal_models.DisputeCategoryOther, Reason: "again"})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeExists)
+
+	// Only a job the worker was on, and something must be named.
+	_, err = s.Open(ctx, other.ID, dtos.OpenDisputeRequest{JobInstanceID: &jobIDs[0], Category: internal_models.DisputeCategoryUnderpaid, Reason: "not mine"})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidDispute)
+	missing := uuid.New()
+	_, err = s.Open(ctx, worker.ID, dtos.OpenDisputeRequest{PayoutID: &missing, Category: internal_models.DisputeCategoryPayout, Reason: "no such payout"})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidDispute)
+	_, err = s.Open(ctx, worker.ID, dtos.OpenDisputeRequest{Category: internal_models.DisputeCategoryOther, Reason: "nothing named"})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidDispute)
+
+	// Once resolved, the job can be disputed again.
+	_, err = s.Deny(ctx, uuid.New(), dtos.DisputeActionRequest{ID: d.ID, Note: "paid per the contract"})
+	require.NoError(t, err)
+	again := openDispute(t, s, worker.ID, jobIDs[0])
+	require.NotEqual(t, d.ID, again.ID)
+}
+
+func TestDisputeTransitions(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	s := newDisputeService()
+	worker, jobIDs := createWorkerWithJobs(t, "dispute-flow", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	d := openDispute(t, s, worker.ID, jobIDs[0])
+	staff := uuid.New()
+
+	// The worker can only reply when asked to.
+	_, err := s.Reply(ctx, worker.ID, dtos.DisputeReplyRequest{ID: d.ID, Note: "unprompted"})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeWrongStatus)
+
+	triaged, err := s.Triage(ctx, staff, dtos.DisputeActionRequest{ID: d.ID, Note: "looking"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.DisputeStatusUnderReview, triaged.Status)
+	require.Equal(t, staff, *triaged.AssignedTo)
+	_, err = s.Triage(ctx, staff, dtos.DisputeActionRequest{ID: d.ID})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeWrongStatus)
+
+	_, err = s.RequestInfo(ctx, staff, dtos.DisputeActionRequest{ID: d.ID})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidDispute, "asking for information needs a question")
+	asked, err := s.RequestInfo(ctx, staff, dtos.DisputeActionRequest{ID: d.ID, Note: "which buildings?"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.DisputeStatusNeedsInfo, asked.Status)
+
+	replied, err := s.Reply(ctx, worker.ID, dtos.DisputeReplyRequest{ID: d.ID, Note: "A and B", Evidence: []string{"https://example.com/b.jpg"}})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.DisputeStatusUnderReview, replied.Status)
+
+	_, err = s.Deny(ctx, staff, dtos.DisputeActionRequest{ID: d.ID})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidDispute, "a denial needs a reason")
+	denied, err := s.Deny(ctx, staff, dtos.DisputeActionRequest{ID: d.ID, Note: "B was not on the route"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.DisputeStatusDenied, denied.Status)
+	require.Equal(t, staff, *denied.ResolvedBy)
+
+	// Resolved disputes are final, and a refused approval pays nothing.
+	_, err = s.Deny(ctx, staff, dtos.DisputeActionRequest{ID: d.ID, Note: "again"})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeWrongStatus)
+	_, err = s.Approve(ctx, staff, dtos.ApproveDisputeRequest{ID: d.ID, Amount: 12.50})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeWrongStatus)
+	adjustments, err := internal_repositories.NewWorkerAdjustmentRepository(h.DB).List(ctx, &worker.ID, "", 10)
+	require.NoError(t, err)
+	require.Empty(t, adjustments)
+
+	_, err = s.Triage(ctx, staff, dtos.DisputeActionRequest{ID: uuid.New()})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeNotFound)
+
+	detail, err := s.Get(ctx, d.ID)
+	require.NoError(t, err)
+	var actions []internal_models.DisputeActionType
+	for _, ev := range detail.Events {
+		actions = append(actions, ev.Action)
+	}
+	require.Equal(t, []internal_models.DisputeActionType{
+		internal_models.DisputeActionOpened,
+		internal_models.DisputeActionTriaged,
+		internal_models.DisputeActionInfoRequested,
+		internal_models.DisputeActionInfoProvided,
+		internal_models.DisputeActionDenied,
+	}, actions)
+}
+
+func TestApproveDisputeCreatesPendingCorrection(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	s := newDisputeService()
+	worker, jobIDs := createWorkerWithJobs(t, "dispute-approve", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	d := openDispute(t, s, worker.ID, jobIDs[0])
+	staff := uuid.New()
+
+	approved, err := s.Approve(ctx, staff, dtos.ApproveDisputeRequest{ID: d.ID, Amount: 10.00, Note: "second building confirmed"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.DisputeStatusApproved, approved.Status)
+	require.NotNil(t, approved.AdjustmentID)
+
+	adj, err := internal_repositories.NewWorkerAdjustmentRepository(h.DB).GetByID(ctx, *approved.AdjustmentID)
+	require.NoError(t, err)
+	require.NotNil(t, adj)
+	require.Equal(t, worker.ID, adj.WorkerID)
+	require.Equal(t, internal_models.AdjustmentKindCorrection, adj.Kind)
+	require.Equal(t, internal_models.AdjustmentStatusPendingApproval, adj.Status, "a second ops user still approves it")
+	require.Equal(t, models.USD(1000), adj.Amount)
+	require.Equal(t, jobIDs[0], *adj.JobInstanceID)
+	require.Equal(t, staff, *adj.CreatedBy)
+	require.Contains(t, adj.Reason, d.ID.String())
+
+	// Pending, it isn't paid yet.
+	approvedAdj, err := internal_repositories.NewWorkerAdjustmentRepository(h.DB).ListApprovedForWorker(ctx, worker.ID)
+	require.NoError(t, err)
+	require.Empty(t, approvedAdj)
+}
+
+func TestWorkerCannotReadAnotherWorkersDispute(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	s := newDisputeService()
+	worker, jobIDs := createWorkerWithJobs(t, "dispute-owner", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	other, _ := createWorkerWithJobs(t, "dispute-snoop", time.Now().UTC().AddDate(0, 0, -1))
+	d := openDispute(t, s, worker.ID, jobIDs[0])
+
+	_, err := s.GetForWorker(ctx, other.ID, d.ID)
+	require.ErrorIs(t, err, internal_utils.ErrDisputeNotFound)
+
+	list, err := s.ListForWorker(ctx, other.ID)
+	require.NoError(t, err)
+	require.Empty(t, list.Disputes)
+
+	_, err = s.RequestInfo(ctx, uuid.New(), dtos.DisputeActionRequest{ID: d.ID, Note: "details?"})
+	require.NoError(t, err)
+	_, err = s.Reply(ctx, other.ID, dtos.DisputeReplyRequest{ID: d.ID, Note: "not my dispute"})
+	require.ErrorIs(t, err, internal_utils.ErrDisputeNotFound)
+
+	mine, err := s.ListForWorker(ctx, worker.ID)
+	require.NoError(t, err)
+	require.Len(t, mine.Disputes, 1)
+	require.Equal(t, d.ID, mine.Disputes[0].ID)
+}
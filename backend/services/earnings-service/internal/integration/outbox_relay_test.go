@@ -0,0 +1,165 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+type relayPayload struct {
+	N int `json:"n"`
+}
+
+// relayRecorder records every delivery attempt, failed ones included.
+type relayRecorder struct {
+	mu    sync.Mutex
+	seen  []int
+	fails func(n int, attempt int) bool
+	tries map[int]int
+}
+
+func (rec *relayRecorder) handle(_ context.Context, ev *models.OutboxEvent) error {
+	var p relayPayload
+	if err := json.Unmarshal(ev.Payload, &p); err != nil {
+		return err
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.seen = append(rec.seen, p.N)
+	rec.tries[p.N]++
+	if rec.fails != nil && rec.fails(p.N, rec.tries[p.N]) {
+		return errors.New("handler failed")
+	}
+	return nil
+}
+
+func (rec *relayRecorder) deliveries() []int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]int(nil), rec.seen...)
+}
+
+// newTestRelay makes a relay under a fresh consumer name whose offset starts
+// at the end of the outbox, so it only sees events published after it. The
+// caller subscribes and starts it; it is stopped and forgotten on cleanup.
+func newTestRelay(t *testing.T, maxAttempts int) (relay *repositories.OutboxRelay, consumer string) {
+	consumer = "it-relay-" + uuid.NewString()[:8]
+	_, err := h.DB.Exec(h.Ctx, `
+        INSERT INTO outbox_consumer_offsets (consumer, last_tx_id, last_event_id)
+        SELECT $1, COALESCE(MAX(tx_id),0), COALESCE(MAX(id),0) FROM outbox_events
+    `, consumer)
+	require.NoError(t, err)
+
+	relay = repositories.NewOutboxRelay(consumer, h.DB, repositories.OutboxRelayOptions{
+		PollInterval: 100 * time.Millisecond,
+		RetryDelay:   50 * time.Millisecond,
+		MaxAttempts:  maxAttempts,
+	})
+	t.Cleanup(func() {
+		relay.Stop()
+		_, _ = h.DB.Exec(context.Background(), `DELETE FROM outbox_consumer_offsets WHERE consumer=$1`, consumer)
+		_, _ = h.DB.Exec(context.Background(), `DELETE FROM outbox_dead_letters WHERE consumer=$1`, consumer)
+	})
+	return relay, consumer
+}
+
+// startTestRelay runs a test relay subscribed to a topic of its own.
+func startTestRelay(t *testing.T, rec *relayRecorder, maxAttempts int) (consumer, topic string) {
+	relay, consumer := newTestRelay(t, maxAttempts)
+	topic = "it.relay." + uuid.NewString()[:8]
+	rec.tries = make(map[int]int)
+	relay.Subscribe(topic, rec.handle)
+	relay.Start()
+	return consumer, topic
+}
+
+func publishTestEvent(t *testing.T, q repositories.Execer, topic string, n int) {
+	require.NoError(t, repositories.PublishEvent(h.Ctx, q, topic, uuid.New(), relayPayload{N: n}))
+}
+
+func requireDeliveries(t *testing.T, rec *relayRecorder, want []int) {
+	require.Eventually(t, func() bool {
+		return len(rec.deliveries()) >= len(want)
+	}, 15*time.Second, 50*time.Millisecond, "deliveries so far: %v", rec.deliveries())
+	// Nothing more may follow.
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, want, rec.deliveries())
+}
+
+func TestOutboxRelayDeliversInCommitOrder(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	rec := &relayRecorder{}
+	_, topic := startTestRelay(t, rec, 0)
+
+	// Event 1 belongs to a transaction that is still open while 2 and 3
+	// commit, so 2 and 3 are behind the horizon until it finishes.
+	tx, err := h.DB.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+	publishTestEvent(t, tx, topic, 1)
+	publishTestEvent(t, h.DB, topic, 2)
+	publishTestEvent(t, h.DB, topic, 3)
+
+	time.Sleep(time.Second)
+	require.Empty(t, rec.deliveries(), "events past an open transaction must wait")
+
+	require.NoError(t, tx.Commit(ctx))
+	requireDeliveries(t, rec, []int{1, 2, 3})
+}
+
+func TestOutboxRelayKeepsOffsetOnHandlerError(t *testing.T) {
+	h.T = t
+	rec := &relayRecorder{fails: func(n, attempt int) bool { return n == 2 && attempt == 1 }}
+	consumer, topic := startTestRelay(t, rec, 0)
+
+	for n := 1; n <= 3; n++ {
+		publishTestEvent(t, h.DB, topic, n)
+	}
+	// Event 1 is not repeated: the offset before the failure was committed.
+	requireDeliveries(t, rec, []int{1, 2, 2, 3})
+
+	var failingID *int64
+	var attempts int
+	require.NoError(t, h.DB.QueryRow(h.Ctx, `
+        SELECT failing_event_id, failed_attempts FROM outbox_consumer_offsets WHERE consumer=$1
+    `, consumer).Scan(&failingID, &attempts))
+	require.Nil(t, failingID)
+	require.Zero(t, attempts)
+}
+
+func TestOutboxRelayDeadLettersPoisonEvent(t *testing.T) {
+	h.T = t
+	rec := &relayRecorder{fails: func(n, _ int) bool { return n == 2 }}
+	consumer, topic := startTestRelay(t, rec, 3)
+
+	for n := 1; n <= 3; n++ {
+		publishTestEvent(t, h.DB, topic, n)
+	}
+	requireDeliveries(t, rec, []int{1, 2, 2, 2, 3})
+
+	var (
+		deadTopic string
+		payload   relayPayload
+		attempts  int
+		reason    string
+	)
+	require.NoError(t, h.DB.QueryRow(h.Ctx, `
+        SELECT topic, payload, attempts, error FROM outbox_dead_letters WHERE consumer=$1
+    `, consumer).Scan(&deadTopic, &payload, &attempts, &reason))
+	require.Equal(t, topic, deadTopic)
+	require.Equal(t, 2, payload.N)
+	require.Equal(t, 3, attempts)
+	require.Contains(t, reason, "handler failed")
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/services"
 	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	"github.com/stripe/stripe-go/v82"
 )
 
@@ -99,7 +101,7 @@ func TestPayoutLifecycle(t *testing.T) {
 	h.SeedPlatformBalance(t, 20000, "usd") // Instantly fund with $200.00
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 	// This MUST align with the service's internal logic, which always processes the *previous* pay period.
 	lastWeek := getPreviousWeekPayPeriodStart()
 
@@ -140,31 +142,39 @@ func TestPayoutLifecycle(t *testing.T) {
 		// Verification for the worker who was pre-seeded.
 		// The service should have found the existing payout and skipped creating a new one.
 		// We assert that the record is still the one from the seeder.
-		pExisting, err := payoutRepo.GetByWorkerAndWeek(ctx, workerWithExistingPayout.ID, lastWeek)
+		pExisting, err := payoutRepo.GetScheduledByPeriod(ctx, workerWithExistingPayout.ID, lastWeek)
 		require.NoError(t, err)
 		require.NotNil(t, pExisting, "Expected to find the payout record created by the seeder")
 		require.Equal(t, int64(5800), pExisting.AmountCents, "Seeded payout amount should not be modified by aggregation logic")
 		require.Equal(t, internal_models.PayoutStatusPaid, pExisting.Status, "Seeded payout status should not be modified")
 
 		// Verify new PENDING payouts were created correctly for the other workers.
-		pFailNoAcct, _ = payoutRepo.GetByWorkerAndWeek(ctx, workerFailNoAcct.ID, lastWeek)
+		pFailNoAcct, _ = payoutRepo.GetScheduledByPeriod(ctx, workerFailNoAcct.ID, lastWeek)
 		require.NotNil(t, pFailNoAcct, "A new payout record should have been created for the worker with no Stripe ID")
 		require.Equal(t, int64(6000), pFailNoAcct.AmountCents)
 		require.Equal(t, internal_models.PayoutStatusPending, pFailNoAcct.Status)
 
-		pFailAsync, _ = payoutRepo.GetByWorkerAndWeek(ctx, workerFailAsync.ID, lastWeek)
+		pFailAsync, _ = payoutRepo.GetScheduledByPeriod(ctx, workerFailAsync.ID, lastWeek)
 		require.NotNil(t, pFailAsync, "A new payout record should have been created for the async-failing worker")
 		require.Equal(t, int64(7000), pFailAsync.AmountCents)
 		require.Equal(t, internal_models.PayoutStatusPending, pFailAsync.Status)
 
 		// Verify no payout was created for the low-pay worker
-		pLowPay, _ := payoutRepo.GetByWorkerAndWeek(ctx, workerLowPay.ID, lastWeek)
+		pLowPay, _ := payoutRepo.GetScheduledByPeriod(ctx, workerLowPay.ID, lastWeek)
 		require.Nil(t, pLowPay, "No payout should be created for earnings below the minimum threshold")
 	})
 
 	// --- Test 1.2: Processing Logic & Final Asynchronous Results ---
 	t.Run("ProcessPayoutsAndVerifyFinalStates", func(t *testing.T) {
 		h.T = t
+		// Aggregated payouts wait for their schedule's send time; bring it forward.
+		for _, p := range []*internal_models.WorkerPayout{pFailNoAcct, pFailAsync} {
+			err := payoutRepo.UpdateWithRetry(ctx, p.ID, func(pToUpdate *internal_models.WorkerPayout) error {
+				pToUpdate.NextAttemptAt = utils.Ptr(time.Now().UTC())
+				return nil
+			})
+			require.NoError(t, err)
+		}
 		err := payoutService.ProcessPendingPayouts(ctx)
 		require.NoError(t, err)
 
@@ -172,7 +182,7 @@ func TestPayoutLifecycle(t *testing.T) {
 		// and won't be picked up by `ProcessPendingPayouts`.
 
 		// Payout for worker with no Stripe ID should fail synchronously.
-		finalFailNoAcct, _ := payoutRepo.GetByWorkerAndWeek(ctx, workerFailNoAcct.ID, lastWeek)
+		finalFailNoAcct, _ := payoutRepo.GetScheduledByPeriod(ctx, workerFailNoAcct.ID, lastWeek)
 		require.NotNil(t, finalFailNoAcct)
 		require.Equal(t, internal_models.PayoutStatusFailed, finalFailNoAcct.Status)
 		require.Equal(t, constants.ReasonMissingStripeID, *finalFailNoAcct.LastFailureReason)
@@ -201,7 +211,7 @@ func TestRealWebhookEvents(t *testing.T) {
 	h.SeedPlatformBalance(t, 20000, "usd") // Instantly fund with $200.00
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 	// Use a unique week to prevent data conflicts with other tests
 	testWeek := getPreviousWeekPayPeriodStart().AddDate(0, 0, -14)
 
@@ -470,7 +480,7 @@ func TestWebhookDrivenRecovery(t *testing.T) {
 	h.SeedPlatformBalance(t, 10000, "usd") // Instantly fund with $100.00
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 	// Use a unique week to prevent data conflicts with other tests
 	testWeek := getPreviousWeekPayPeriodStart().AddDate(0, 0, -28)
 
@@ -538,7 +548,8 @@ func TestGetEarningsSummaryEndpoint(t *testing.T) {
 	h.T = t
 	ctx := context.Background()
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	loc, _ := time.LoadLocation(constants.BusinessTimezone)
+	schedule := internal_models.DefaultPaySchedule()
+	loc := schedule.Location()
 
 	t.Run("Weekly - ForWorkerWithComplexHistory", func(t *testing.T) {
 		h.T = t
@@ -549,10 +560,8 @@ func TestGetEarningsSummaryEndpoint(t *testing.T) {
 
 		worker := h.CreateTestWorker(ctx, "summary-worker-weekly")
 		jwt := h.CreateMobileJWT(worker.ID, "summary-device-weekly", "FAKE-PLAY")
-		todayNY := time.Now().In(loc)
-
 		// --- Setup data across multiple weeks, statuses, and a timezone edge case ---
-		currentWeekStart := internal_utils.GetPayPeriodStartForDate(todayNY)
+		currentWeekStart, currentWeekEnd := schedule.PeriodAt(time.Now())
 		lastWeekStart := currentWeekStart.AddDate(0, 0, -7)
 		twoWeeksAgoStart := currentWeekStart.AddDate(0, 0, -14)
 		threeWeeksAgoStart := currentWeekStart.AddDate(0, 0, -21)
@@ -604,7 +613,7 @@ func TestGetEarningsSummaryEndpoint(t *testing.T) {
 		expectedTotal := 20.50 + 22.00 + 35.75 + 50.00 + 15.00 + 10.00
 		require.InDelta(t, expectedTotal, summary.TwoMonthTotal, 0.01)
 
-		expectedNextPayoutDate := internal_utils.GetPayPeriodStartForDate(todayNY).AddDate(0, 0, 8)
+		expectedNextPayoutDate := schedule.PayoutAt(currentWeekEnd).In(loc)
 		require.Equal(t, expectedNextPayoutDate.Format("2006-01-02"), summary.NextPayoutDate)
 
 		// We now have 3 settled weeks of payouts.
@@ -691,7 +700,7 @@ func TestPayoutFailureScenariosAndIdempotency(t *testing.T) {
 	h.SeedPlatformBalance(t, 10000, "usd") // Instantly fund with $100.00
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 	// Use a unique week to prevent data conflicts with other tests
 	testWeek := getPreviousWeekPayPeriodStart().AddDate(0, 0, -35)
 
@@ -804,7 +813,7 @@ func TestInsufficientBalanceAndRecovery(t *testing.T) {
 	h.SeedPlatformBalance(t, 5000, "usd") // $50.00
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 	testWeek := getPreviousWeekPayPeriodStart().AddDate(0, 0, -56)
 
 	// --- 1. Setup ---
@@ -868,7 +877,7 @@ func TestAdvancedRecoveryAndRetryScenarios(t *testing.T) {
 	h.SeedPlatformBalance(t, 10000, "usd")
 
 	payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-	payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+	payoutService := newTestPayoutService(payoutRepo)
 
 	// --- Test 8.1: Recovery from `capability.updated` Webhook ---
 	t.Run("CapabilityUpdatedRecovery", func(t *testing.T) {
@@ -990,7 +999,7 @@ func TestReconcileStaleProcessingPayout(t *testing.T) {
     stripe.Key = cfg.StripeSecretKey
 
     payoutRepo := internal_repositories.NewWorkerPayoutRepository(h.DB)
-    payoutService := services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, payoutRepo)
+    payoutService := newTestPayoutService(payoutRepo)
 
     // Use a unique week to avoid collisions with other tests
     testWeek := getPreviousWeekPayPeriodStart().AddDate(0, 0, -70)
@@ -1105,22 +1114,17 @@ func waitForPayoutStatus(t *testing.T, ctx context.Context, repo internal_reposi
 }
 
 // createTestPayout is a local helper because the repo is specific to this service.
-func createTestPayout(t *testing.T, ctx context.Context, repo internal_repositories.WorkerPayoutRepository, workerID uuid.UUID, amountCents int64, status internal_models.PayoutStatusType, transferID *string, weekStartDate time.Time, jobIDs []uuid.UUID) *internal_models.WorkerPayout {
+// periodStart must start a period of the pay schedule in effect (see testPaySchedule).
+func createTestPayout(t *testing.T, ctx context.Context, repo internal_repositories.WorkerPayoutRepository, workerID uuid.UUID, amountCents int64, status internal_models.PayoutStatusType, transferID *string, periodStart time.Time, jobIDs []uuid.UUID) *internal_models.WorkerPayout {
 	t.Helper()
 
-	// For daily payouts, WeekEndDate is the same as WeekStartDate.
-	var weekEndDate time.Time
-	if cfg.LDFlag_UseShortPayPeriod {
-		weekEndDate = weekStartDate
-	} else {
-		weekEndDate = weekStartDate.AddDate(0, 0, 6)
-	}
+	_, periodEnd := testPaySchedule().PeriodAt(periodStart)
 
 	payout := &internal_models.WorkerPayout{
 		ID:               uuid.New(),
 		WorkerID:         workerID,
-		WeekStartDate:    weekStartDate,
-		WeekEndDate:      weekEndDate,
+		PeriodStart:      periodStart,
+		PeriodEnd:        periodEnd,
 		AmountCents:      amountCents,
 		Status:           status,
 		StripeTransferID: transferID,
@@ -1129,20 +1133,19 @@ func createTestPayout(t *testing.T, ctx context.Context, repo internal_repositor
 	err := repo.Create(ctx, payout)
 	require.NoError(t, err, "payout creation failed. This may be due to a transient error, as the ON CONFLICT rule should handle uniqueness.")
 
-	// Fetch by the unique key (worker + week) to get the record regardless of whether it was
+	// Fetch by the unique key (worker + period) to get the record regardless of whether it was
 	// just inserted or already existed. This makes the helper robust to the ON CONFLICT clause.
-	created, err := repo.GetByWorkerAndWeek(ctx, workerID, weekStartDate)
+	created, err := repo.GetScheduledByPeriod(ctx, workerID, periodStart)
 	require.NoError(t, err)
 	require.NotNil(t, created, "payout was not found in DB after creation. The ON CONFLICT rule may have prevented insert and no existing record was found.")
 
 	// If a specific status was requested (e.g. for a test setup), ensure it's set.
 	// The ON CONFLICT might have returned an existing record with a different status.
-	if created.Status != status || (transferID != nil && (created.StripeTransferID == nil || *created.StripeTransferID != *transferID)) || created.WeekEndDate != weekEndDate {
+	if created.Status != status || (transferID != nil && (created.StripeTransferID == nil || *created.StripeTransferID != *transferID)) {
 		err = repo.UpdateWithRetry(ctx, created.ID, func(pToUpdate *internal_models.WorkerPayout) error {
 			pToUpdate.Status = status
 			pToUpdate.StripeTransferID = transferID
 			pToUpdate.StripePayoutID = nil // Reset this for test consistency
-			pToUpdate.WeekEndDate = weekEndDate
 			pToUpdate.JobInstanceIDs = jobIDs
 			return nil
 		})
@@ -1154,12 +1157,30 @@ func createTestPayout(t *testing.T, ctx context.Context, repo internal_repositor
 	return created
 }
 
-// getPreviousWeekPayPeriodStart returns the Monday that started the previous full pay period.
-func getPreviousWeekPayPeriodStart() time.Time {
-	// This logic must exactly match how the cron job determines its target week.
-	loc, _ := time.LoadLocation(constants.BusinessTimezone)
-	now := time.Now().In(loc)
+// newTestPayoutService wires a PayoutService the way main does, against the
+// real Stripe provider and the shared test config.
+func newTestPayoutService(payoutRepo internal_repositories.WorkerPayoutRepository) *services.PayoutService {
+	uow := repositories.NewUnitOfWork(h.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{})
+	schedules := services.NewPayScheduleService(cfg, h.WorkerRepo, internal_repositories.NewPayScheduleRepository(h.DB))
+	holds := services.NewPayoutHoldService(cfg, h.JobInstRepo, internal_repositories.NewPayoutHoldRepository(h.DB), uow)
+	queue := utils.NewPostgresJobQueue(cfg.AppName, h.DB, utils.JobQueueOptions{})
+	return services.NewPayoutService(cfg, h.WorkerRepo, h.JobInstRepo, h.PayItemRepo, payoutRepo, internal_repositories.NewWorkerAdjustmentRepository(h.DB), schedules, holds, uow, queue, services.NewStripePayoutProvider(cfg), nil, services.NewStripeLedger())
+}
 
-	thisWeekPayPeriodStart := internal_utils.GetPayPeriodStartForDate(now)
-	return thisWeekPayPeriodStart.AddDate(0, 0, -constants.DaysInWeek)
+// testPaySchedule is the schedule test workers are on: the default, or the
+// daily one when the short pay period flag is set.
+func testPaySchedule() *internal_models.PaySchedule {
+	if cfg.LDFlag_UseShortPayPeriod {
+		return internal_models.DailyPaySchedule()
+	}
+	return internal_models.DefaultPaySchedule()
+}
+
+// getPreviousWeekPayPeriodStart returns the start of the previous full pay
+// period, the last one AggregateAndCreatePayouts would close.
+func getPreviousWeekPayPeriodStart() time.Time {
+	schedule := testPaySchedule()
+	thisPeriodStart, _ := schedule.PeriodAt(time.Now())
+	prevPeriodStart, _ := schedule.PeriodAt(thisPeriodStart.Add(-time.Minute))
+	return prevPeriodStart
 }
@@ -0,0 +1,163 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// newHoldFixture is a payout fixture that holds jobs flagged for review.
+func newHoldFixture() *payoutFixture {
+	f := newPayoutFixture(testCashOutPolicy())
+	f.cfg.LDFlag_PayoutHoldRules = &internal_models.PayoutHoldRules{Enabled: true, FlaggedForReview: true}
+	return f
+}
+
+func flagForReview(t *testing.T, jobID uuid.UUID) *models.JobInstance {
+	_, err := h.DB.Exec(h.Ctx, `UPDATE job_instances SET flagged_for_review = TRUE WHERE id = $1`, jobID)
+	require.NoError(t, err)
+	job, err := h.JobInstRepo.GetByID(h.Ctx, jobID)
+	require.NoError(t, err)
+	return job
+}
+
+func placeHold(t *testing.T, f *payoutFixture, jobID uuid.UUID) *internal_models.PayoutHold {
+	hold, err := f.holds.Place(h.Ctx, uuid.New(), dtos.PlaceHoldRequest{
+		JobInstanceID: jobID,
+		Reason:        internal_models.HoldReasonPMDispute,
+		Details:       "hold test",
+	})
+	require.NoError(t, err)
+	return hold
+}
+
+func TestHoldScreenIsIdempotent(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newHoldFixture()
+	worker, jobIDs := createWorkerWithJobs(t, "hold-screen", getPreviousWeekPayPeriodStart(), 25.00)
+	job := flagForReview(t, jobIDs[0])
+
+	first, err := f.holds.ScreenOne(ctx, job)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	require.Equal(t, internal_models.HoldReasonFlaggedForReview, first.Reason)
+	require.Equal(t, internal_models.HoldStatusHeld, first.Status)
+	require.Nil(t, first.CreatedBy)
+
+	again, err := f.holds.Screen(ctx, []*models.JobInstance{job, job})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, again[job.ID].ID)
+
+	held, err := internal_repositories.NewPayoutHoldRepository(h.DB).List(ctx, &worker.ID, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, held, 1, "screening twice must not hold twice")
+}
+
+func TestHeldJobsSkipAggregationAndCashOut(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newHoldFixture()
+
+	// Aggregation pays the clean job and leaves the flagged one.
+	worker, jobIDs := createWorkerWithJobs(t, "hold-agg", getPreviousWeekPayPeriodStart(), 20.00, 35.00)
+	flagForReview(t, jobIDs[1])
+	p := aggregateFor(t, f, worker.ID)
+	require.Equal(t, jobIDs[:1], p.JobInstanceIDs)
+	require.Equal(t, int64(2000), p.AmountCents)
+
+	// So does a cash-out.
+	worker, jobIDs = createWorkerWithJobs(t, "hold-cashout", time.Now().UTC().AddDate(0, 0, -1), 20.00, 35.00)
+	flagForReview(t, jobIDs[0])
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.JobCount)
+	require.Equal(t, models.USD(3500), resp.Amount)
+
+	paidOut, err := f.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	require.NoError(t, err)
+	require.False(t, paidOut[jobIDs[0]])
+	require.True(t, paidOut[jobIDs[1]])
+}
+
+func TestReleasedHoldIsPaid(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newHoldFixture()
+	worker, jobIDs := createWorkerWithJobs(t, "hold-release", getPreviousWeekPayPeriodStart(), 30.00)
+	hold := placeHold(t, f, jobIDs[0])
+
+	_, err := f.holds.Place(ctx, uuid.New(), dtos.PlaceHoldRequest{JobInstanceID: jobIDs[0], Reason: internal_models.HoldReasonFraudSignal, Details: "again"})
+	require.ErrorIs(t, err, internal_utils.ErrAlreadyHeld)
+
+	released, err := f.holds.Release(ctx, uuid.New(), dtos.ResolveHoldRequest{ID: hold.ID, Note: "checked"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.HoldStatusReleased, released.Status)
+	_, err = f.holds.Reject(ctx, uuid.New(), dtos.ResolveHoldRequest{ID: hold.ID})
+	require.ErrorIs(t, err, internal_utils.ErrHoldResolved)
+
+	// Released after its period closed, it is paid in the period it was
+	// released in, which hasn't closed yet.
+	require.NoError(t, f.payouts.AggregateAndCreatePayouts(ctx))
+	paidOut, err := f.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	require.NoError(t, err)
+	require.False(t, paidOut[jobIDs[0]])
+
+	// A released job isn't held by the rules again.
+	job := flagForReview(t, jobIDs[0])
+	again, err := f.holds.ScreenOne(ctx, job)
+	require.NoError(t, err)
+	require.Equal(t, hold.ID, again.ID)
+	require.False(t, again.Blocks())
+
+	// Nothing stops a cash-out of it now.
+	resp, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+	require.Equal(t, models.USD(3000), resp.Amount)
+}
+
+func TestRejectedHoldKeepsJobUnpaid(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newHoldFixture()
+	worker, jobIDs := createWorkerWithJobs(t, "hold-reject", getPreviousWeekPayPeriodStart(), 30.00)
+	hold := placeHold(t, f, jobIDs[0])
+
+	rejected, err := f.holds.Reject(ctx, uuid.New(), dtos.ResolveHoldRequest{ID: hold.ID, Note: "not done"})
+	require.NoError(t, err)
+	require.Equal(t, internal_models.HoldStatusRejected, rejected.Status)
+
+	require.NoError(t, f.payouts.AggregateAndCreatePayouts(ctx))
+	p, err := f.payoutRepo.GetScheduledByPeriod(ctx, worker.ID, getPreviousWeekPayPeriodStart())
+	require.NoError(t, err)
+	require.Nil(t, p, "a rejected job's pay is never paid")
+
+	_, err = f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.ErrorIs(t, err, internal_utils.ErrNothingToCashOut)
+
+	// Nor can it be held again.
+	_, err = f.holds.Place(ctx, uuid.New(), dtos.PlaceHoldRequest{JobInstanceID: jobIDs[0], Reason: internal_models.HoldReasonPMDispute, Details: "again"})
+	require.ErrorIs(t, err, internal_utils.ErrAlreadyHeld)
+}
+
+func TestPlaceHoldRefusesPaidJob(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	f := newHoldFixture()
+	worker, jobIDs := createWorkerWithJobs(t, "hold-paid", time.Now().UTC().AddDate(0, 0, -1), 30.00)
+	_, err := f.cashOut.CashOut(ctx, worker.ID, internal_models.PayoutMethodStandard)
+	require.NoError(t, err)
+
+	_, err = f.holds.Place(ctx, uuid.New(), dtos.PlaceHoldRequest{JobInstanceID: jobIDs[0], Reason: internal_models.HoldReasonFraudSignal, Details: "too late"})
+	require.ErrorIs(t, err, internal_utils.ErrInvalidHold)
+}
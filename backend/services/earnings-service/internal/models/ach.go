@@ -0,0 +1,126 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ACHOriginator is who we are in the NACHA files sent to our bank (the ODFI).
+The bank supplies every value when it enables ACH origination.
+
+ODFIRoutingNumber and ODFIName go in the file header as its immediate
+destination; OriginID and OriginName as its immediate origin, usually "1"
+followed by our EIN. CompanyName, CompanyID and EntryDescription appear on
+the workers' bank statements. Entries settle EffectiveDays business days
+after their file is made.
+*/
+type ACHOriginator struct {
+	ODFIRoutingNumber string `json:"odfi_routing_number"`
+	ODFIName          string `json:"odfi_name"`
+	OriginID          string `json:"origin_id"`
+	OriginName        string `json:"origin_name"`
+	CompanyName       string `json:"company_name"`
+	CompanyID         string `json:"company_id"`
+	EntryDescription  string `json:"entry_description"`
+	EffectiveDays     int    `json:"effective_days"`
+}
+
+// ParseACHOriginator reads the originator from JSON. Entries are described
+// as PAYROLL and settle the next business day unless it says otherwise.
+func ParseACHOriginator(data []byte) (*ACHOriginator, error) {
+	o := &ACHOriginator{EntryDescription: "PAYROLL", EffectiveDays: 1}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, fmt.Errorf("invalid ACH originator: %w", err)
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *ACHOriginator) Validate() error {
+	switch {
+	case len(o.ODFIRoutingNumber) != 9 || strings.Trim(o.ODFIRoutingNumber, "0123456789") != "":
+		return fmt.Errorf("ACH originator: odfi_routing_number must be 9 digits")
+	case o.OriginID == "" || len(o.OriginID) > 10:
+		return fmt.Errorf("ACH originator: origin_id must be 1 to 10 characters")
+	case o.CompanyID == "" || len(o.CompanyID) > 10:
+		return fmt.Errorf("ACH originator: company_id must be 1 to 10 characters")
+	case o.CompanyName == "" || len(o.CompanyName) > 16:
+		return fmt.Errorf("ACH originator: company_name must be 1 to 16 characters")
+	case o.EntryDescription == "" || len(o.EntryDescription) > 10:
+		return fmt.Errorf("ACH originator: entry_description must be 1 to 10 characters")
+	case o.EffectiveDays < 0 || o.EffectiveDays > 2:
+		return fmt.Errorf("ACH originator: effective_days must be 0 to 2")
+	}
+	return nil
+}
+
+// BankAccountType is the kind of account an ACH credit goes to.
+type BankAccountType string
+
+const (
+	BankAccountChecking BankAccountType = "CHECKING"
+	BankAccountSavings  BankAccountType = "SAVINGS"
+)
+
+// WorkerBankAccount is where ACH payouts to a worker go. Ops enter it for
+// workers that Stripe Connect can't pay. The account number is stored
+// encrypted and never leaves the service except in NACHA files.
+type WorkerBankAccount struct {
+	WorkerID      uuid.UUID       `json:"worker_id"`
+	HolderName    string          `json:"holder_name"`
+	RoutingNumber string          `json:"routing_number"`
+	AccountNumber string          `json:"-"`
+	AccountLast4  string          `json:"account_last4"`
+	AccountType   BankAccountType `json:"account_type"`
+	UpdatedBy     uuid.UUID       `json:"updated_by"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// ACHBatchStatusType tracks a NACHA file from generation to upload.
+type ACHBatchStatusType string
+
+const (
+	// ACHBatchGenerated files are waiting to be uploaded to the bank.
+	ACHBatchGenerated ACHBatchStatusType = "GENERATED"
+	// ACHBatchSubmitted files were uploaded; their payouts are PAID unless
+	// a return comes back.
+	ACHBatchSubmitted ACHBatchStatusType = "SUBMITTED"
+)
+
+// ACHBatch is one NACHA file of ACH credits to workers. File holds the
+// file itself and is only loaded for download.
+type ACHBatch struct {
+	ID            uuid.UUID          `json:"id"`
+	FileName      string             `json:"file_name"`
+	Status        ACHBatchStatusType `json:"status"`
+	EntryCount    int                `json:"entry_count"`
+	TotalCents    int64              `json:"total_cents"`
+	EffectiveDate time.Time          `json:"effective_date"`
+	File          []byte             `json:"-"`
+	CreatedBy     uuid.UUID          `json:"created_by"`
+	CreatedAt     time.Time          `json:"created_at"`
+	SubmittedBy   *uuid.UUID         `json:"submitted_by,omitempty"`
+	SubmittedAt   *time.Time         `json:"submitted_at,omitempty"`
+}
+
+// ACHReturn is a credit the workers' bank sent back, from an imported
+// return file. PayoutID is nil when no payout had the trace number.
+type ACHReturn struct {
+	ID            uuid.UUID  `json:"id"`
+	TraceNumber   string     `json:"trace_number"`
+	ReturnCode    string     `json:"return_code"` // R01, R02, ...
+	FailureReason string     `json:"failure_reason"`
+	AmountCents   int64      `json:"amount_cents"`
+	PayoutID      *uuid.UUID `json:"payout_id,omitempty"`
+	BatchID       *uuid.UUID `json:"batch_id,omitempty"`
+	ImportedBy    uuid.UUID  `json:"imported_by"`
+	ImportedAt    time.Time  `json:"imported_at"`
+}
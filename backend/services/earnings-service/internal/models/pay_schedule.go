@@ -0,0 +1,169 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayFrequencyType is how long a pay schedule's periods run.
+type PayFrequencyType string
+
+const (
+	PayFrequencyDaily    PayFrequencyType = "DAILY"
+	PayFrequencyWeekly   PayFrequencyType = "WEEKLY"
+	PayFrequencyBiweekly PayFrequencyType = "BIWEEKLY"
+)
+
+// Days is the length of one period in local calendar days.
+func (f PayFrequencyType) Days() int {
+	switch f {
+	case PayFrequencyDaily:
+		return 1
+	case PayFrequencyWeekly:
+		return 7
+	case PayFrequencyBiweekly:
+		return 14
+	}
+	return 0
+}
+
+// DefaultPayScheduleID is the weekly schedule the migration seeds, which
+// every worker was paid on before schedules existed.
+var DefaultPayScheduleID = uuid.MustParse("00000000-0000-0000-0000-0000000000a1")
+
+/*
+PaySchedule says when a worker's pay periods begin and end and when their
+payouts go out.
+
+Periods are Frequency long and are counted in whole local days from
+AnchorDate, so a weekly schedule anchored on a Monday always starts on
+Mondays and a biweekly one every other Monday. Each period starts at
+StartHour in Timezone and ends where the next begins. A job belongs to the
+period its service date falls in. The payout for a period is sent
+PayoutDelayHours after it closes.
+*/
+type PaySchedule struct {
+	ID               uuid.UUID        `json:"id"`
+	Name             string           `json:"name"`
+	Frequency        PayFrequencyType `json:"frequency"`
+	AnchorDate       time.Time        `json:"anchor_date"` // a date; the time of day is ignored
+	StartHour        int              `json:"start_hour"`
+	Timezone         string           `json:"timezone"`
+	PayoutDelayHours int              `json:"payout_delay_hours"`
+	IsDefault        bool             `json:"is_default"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// DefaultPaySchedule is the seeded default: weekly from Monday 4AM
+// Eastern, paid out Tuesday morning.
+func DefaultPaySchedule() *PaySchedule {
+	return &PaySchedule{
+		ID:               DefaultPayScheduleID,
+		Name:             "Weekly (Monday 4AM Eastern)",
+		Frequency:        PayFrequencyWeekly,
+		AnchorDate:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		StartHour:        4,
+		Timezone:         "America/New_York",
+		PayoutDelayHours: 29,
+		IsDefault:        true,
+	}
+}
+
+// DailyPaySchedule runs midnight to midnight Eastern and pays out an hour
+// after. It is not stored; use_short_pay_period puts every worker on it.
+func DailyPaySchedule() *PaySchedule {
+	return &PaySchedule{
+		Name:             "Daily (short pay period)",
+		Frequency:        PayFrequencyDaily,
+		AnchorDate:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		StartHour:        0,
+		Timezone:         "America/New_York",
+		PayoutDelayHours: 1,
+	}
+}
+
+func (s *PaySchedule) Validate() error {
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		return fmt.Errorf("pay schedule: name is required")
+	case s.Frequency.Days() == 0:
+		return fmt.Errorf("pay schedule: unknown frequency %q", s.Frequency)
+	case s.AnchorDate.IsZero():
+		return fmt.Errorf("pay schedule: anchor date is required")
+	case s.StartHour < 0 || s.StartHour > 23:
+		return fmt.Errorf("pay schedule: start hour must be 0-23")
+	case s.PayoutDelayHours < 0:
+		return fmt.Errorf("pay schedule: negative payout delay")
+	}
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return fmt.Errorf("pay schedule: unknown timezone %q", s.Timezone)
+	}
+	return nil
+}
+
+// Location is the schedule's timezone, or UTC if it can't be loaded.
+func (s *PaySchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// PeriodForDate returns the period holding the calendar date d (such as a
+// job's service date), as [start, end).
+func (s *PaySchedule) PeriodForDate(d time.Time) (time.Time, time.Time) {
+	n := s.Frequency.Days()
+	anchor := time.Date(s.AnchorDate.Year(), s.AnchorDate.Month(), s.AnchorDate.Day(), 0, 0, 0, 0, time.UTC)
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+
+	offset := int(day.Sub(anchor).Hours() / 24)
+	idx := offset / n
+	if offset%n < 0 {
+		idx--
+	}
+	first := anchor.AddDate(0, 0, idx*n)
+
+	loc := s.Location()
+	start := time.Date(first.Year(), first.Month(), first.Day(), s.StartHour, 0, 0, 0, loc)
+	end := time.Date(first.Year(), first.Month(), first.Day()+n, s.StartHour, 0, 0, 0, loc)
+	return start, end
+}
+
+// PeriodAt returns the period the instant t falls in, as [start, end).
+// Before StartHour a local day still belongs to the day before.
+func (s *PaySchedule) PeriodAt(t time.Time) (time.Time, time.Time) {
+	local := t.In(s.Location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if local.Hour() < s.StartHour {
+		day = day.AddDate(0, 0, -1)
+	}
+	return s.PeriodForDate(day)
+}
+
+// PayoutAt is when the payout for the period ending at end is sent.
+func (s *PaySchedule) PayoutAt(end time.Time) time.Time {
+	return end.Add(time.Duration(s.PayoutDelayHours) * time.Hour)
+}
+
+// Dates returns the first and last local calendar days of [start, end).
+func (s *PaySchedule) Dates(start, end time.Time) (string, string) {
+	loc := s.Location()
+	return start.In(loc).Format("2006-01-02"), end.In(loc).AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// PayScheduleAssignment puts one worker, or every worker in a market, on a
+// pay schedule. Exactly one of WorkerID and Market is set; a worker's own
+// assignment wins over their market's.
+type PayScheduleAssignment struct {
+	ID            uuid.UUID  `json:"id"`
+	PayScheduleID uuid.UUID  `json:"pay_schedule_id"`
+	WorkerID      *uuid.UUID `json:"worker_id,omitempty"`
+	Market        *string    `json:"market,omitempty"`
+	CreatedBy     *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodForDate(t *testing.T) {
+	weekly := DefaultPaySchedule()
+	biweekly := DefaultPaySchedule()
+	biweekly.Frequency = PayFrequencyBiweekly
+	loc := weekly.Location()
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	for name, tc := range map[string]struct {
+		sch    *PaySchedule
+		date   time.Time
+		start  time.Time
+		length time.Duration
+	}{
+		"midweek":                {sch: weekly, date: date(2026, 2, 25), start: time.Date(2026, 2, 23, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"the anchor":             {sch: weekly, date: date(2024, 1, 1), start: time.Date(2024, 1, 1, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"the day before anchor":  {sch: weekly, date: date(2023, 12, 31), start: time.Date(2023, 12, 25, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"a period before anchor": {sch: weekly, date: date(2023, 12, 25), start: time.Date(2023, 12, 25, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"years before anchor":    {sch: weekly, date: date(2021, 6, 15), start: time.Date(2021, 6, 14, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"date in another zone":   {sch: weekly, date: time.Date(2026, 3, 1, 23, 0, 0, 0, time.FixedZone("UTC-10", -10*3600)), start: time.Date(2026, 2, 23, 4, 0, 0, 0, loc), length: 7 * 24 * time.Hour},
+		"biweekly first week":    {sch: biweekly, date: date(2024, 1, 7), start: time.Date(2024, 1, 1, 4, 0, 0, 0, loc), length: 14 * 24 * time.Hour},
+		"biweekly second week":   {sch: biweekly, date: date(2024, 1, 14), start: time.Date(2024, 1, 1, 4, 0, 0, 0, loc), length: 14 * 24 * time.Hour},
+		"biweekly next period":   {sch: biweekly, date: date(2024, 1, 15), start: time.Date(2024, 1, 15, 4, 0, 0, 0, loc), length: 14 * 24 * time.Hour},
+		"biweekly years on":      {sch: biweekly, date: date(2026, 2, 5), start: time.Date(2026, 1, 26, 4, 0, 0, 0, loc), length: 14 * 24 * time.Hour},
+		"biweekly before anchor": {sch: biweekly, date: date(2023, 12, 24), start: time.Date(2023, 12, 18, 4, 0, 0, 0, loc), length: 14 * 24 * time.Hour},
+		// Clocks go forward on 8 March 2026 and back on 1 November.
+		"spring forward":      {sch: weekly, date: date(2026, 3, 8), start: time.Date(2026, 3, 2, 4, 0, 0, 0, loc), length: 7*24*time.Hour - time.Hour},
+		"fall back":           {sch: weekly, date: date(2026, 11, 1), start: time.Date(2026, 10, 26, 4, 0, 0, 0, loc), length: 7*24*time.Hour + time.Hour},
+		"daily, fall back":    {sch: DailyPaySchedule(), date: date(2026, 11, 1), start: time.Date(2026, 11, 1, 0, 0, 0, 0, loc), length: 25 * time.Hour},
+		"daily, ordinary day": {sch: DailyPaySchedule(), date: date(2026, 11, 2), start: time.Date(2026, 11, 2, 0, 0, 0, 0, loc), length: 24 * time.Hour},
+	} {
+		start, end := tc.sch.PeriodForDate(tc.date)
+		if !start.Equal(tc.start) || end.Sub(start) != tc.length {
+			t.Errorf("%s: expected [%s, +%s), got [%s, %s)", name, tc.start, tc.length, start, end)
+		}
+		if start.In(loc).Hour() != tc.sch.StartHour || end.In(loc).Hour() != tc.sch.StartHour {
+			t.Errorf("%s: expected the period to start and end at %d:00 local, got [%s, %s)", name, tc.sch.StartHour, start, end)
+		}
+	}
+}
+
+func TestPeriodAt(t *testing.T) {
+	sch := DefaultPaySchedule()
+	loc := sch.Location()
+
+	for name, tc := range map[string]struct {
+		at    time.Time
+		start time.Time
+	}{
+		"monday at the start hour":   {at: time.Date(2026, 3, 9, 4, 0, 0, 0, loc), start: time.Date(2026, 3, 9, 4, 0, 0, 0, loc)},
+		"monday before 4AM":          {at: time.Date(2026, 3, 9, 3, 59, 0, 0, loc), start: time.Date(2026, 3, 2, 4, 0, 0, 0, loc)},
+		"monday just past midnight":  {at: time.Date(2026, 3, 9, 0, 0, 0, 0, loc), start: time.Date(2026, 3, 2, 4, 0, 0, 0, loc)},
+		"tuesday before 4AM":         {at: time.Date(2026, 3, 10, 1, 0, 0, 0, loc), start: time.Date(2026, 3, 9, 4, 0, 0, 0, loc)},
+		"sunday night, monday UTC":   {at: time.Date(2026, 3, 9, 3, 0, 0, 0, time.UTC), start: time.Date(2026, 3, 2, 4, 0, 0, 0, loc)},
+		"monday before 4AM, in UTC":  {at: time.Date(2026, 3, 9, 7, 59, 0, 0, time.UTC), start: time.Date(2026, 3, 2, 4, 0, 0, 0, loc)},
+		"monday after 4AM, in UTC":   {at: time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), start: time.Date(2026, 3, 9, 4, 0, 0, 0, loc)},
+		"the anchor before 4AM":      {at: time.Date(2024, 1, 1, 3, 0, 0, 0, loc), start: time.Date(2023, 12, 25, 4, 0, 0, 0, loc)},
+		"the hour clocks fall back":  {at: time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC), start: time.Date(2026, 10, 26, 4, 0, 0, 0, loc)},
+		"monday after the fall back": {at: time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC), start: time.Date(2026, 11, 2, 4, 0, 0, 0, loc)},
+	} {
+		start, end := sch.PeriodAt(tc.at)
+		if !start.Equal(tc.start) {
+			t.Errorf("%s: expected the period starting %s, got %s", name, tc.start, start)
+		}
+		if tc.at.Before(start) || !tc.at.Before(end) {
+			t.Errorf("%s: %s is outside its period [%s, %s)", name, tc.at, start, end)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HoldReasonType says why a job's pay is held.
+type HoldReasonType string
+
+const (
+	HoldReasonFlaggedForReview   HoldReasonType = "FLAGGED_FOR_REVIEW"
+	HoldReasonAgentCompleted     HoldReasonType = "AGENT_COMPLETED"
+	HoldReasonFailedVerification HoldReasonType = "FAILED_VERIFICATION"
+	// HoldReasonPMDispute and HoldReasonFraudSignal may also be placed by
+	// ops, from a property manager's complaint or a fraud review.
+	HoldReasonPMDispute   HoldReasonType = "PM_DISPUTE"
+	HoldReasonFraudSignal HoldReasonType = "FRAUD_SIGNAL"
+)
+
+// Manual reports whether ops may place a hold for the reason by hand.
+func (r HoldReasonType) Manual() bool {
+	return r == HoldReasonPMDispute || r == HoldReasonFraudSignal
+}
+
+// HoldStatusType is where a hold is in review.
+type HoldStatusType string
+
+const (
+	HoldStatusHeld HoldStatusType = "HELD"
+	// HoldStatusReleased lets the job be paid again.
+	HoldStatusReleased HoldStatusType = "RELEASED"
+	// HoldStatusRejected means the job is never paid.
+	HoldStatusRejected HoldStatusType = "REJECTED"
+)
+
+/*
+PayoutHold keeps a completed job out of payouts and cash-outs until ops
+release it or reject its pay. CreatedBy is nil for holds placed by a hold
+rule during payout aggregation.
+
+A released job is paid in the pay period it was completed in if it was
+released before that period closed, and otherwise in the period it was
+released in.
+*/
+type PayoutHold struct {
+	ID             uuid.UUID      `json:"id"`
+	JobInstanceID  uuid.UUID      `json:"job_instance_id"`
+	WorkerID       uuid.UUID      `json:"worker_id"`
+	Reason         HoldReasonType `json:"reason"`
+	Details        string         `json:"details,omitempty"`
+	Status         HoldStatusType `json:"status"`
+	CreatedBy      *uuid.UUID     `json:"created_by,omitempty"`
+	ResolvedBy     *uuid.UUID     `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty"`
+	ResolutionNote string         `json:"resolution_note,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// Blocks reports whether the hold keeps its job from being paid.
+func (h *PayoutHold) Blocks() bool {
+	return h != nil && h.Status != HoldStatusReleased
+}
+
+/*
+PayoutHoldRules decide which completed jobs are held automatically.
+
+FlaggedForReview holds jobs jobs-service flagged for review, AgentCompleted
+those an agent finished for the worker and FailedVerification those with a
+unit that permanently failed photo verification. MinMinutesOnSite is a fraud
+signal: a job checked out less than that many minutes after check-in is
+held. Zero turns it off.
+*/
+type PayoutHoldRules struct {
+	Enabled            bool `json:"enabled"`
+	FlaggedForReview   bool `json:"flagged_for_review"`
+	AgentCompleted     bool `json:"agent_completed"`
+	FailedVerification bool `json:"failed_verification"`
+	MinMinutesOnSite   int  `json:"min_minutes_on_site"`
+}
+
+// DefaultPayoutHoldRules holds flagged jobs and failed verifications. Agent
+// completions are paid; the agent completed the job on the worker's behalf.
+func DefaultPayoutHoldRules() *PayoutHoldRules {
+	return &PayoutHoldRules{
+		Enabled:            true,
+		FlaggedForReview:   true,
+		AgentCompleted:     false,
+		FailedVerification: true,
+		MinMinutesOnSite:   0,
+	}
+}
+
+// ParsePayoutHoldRules reads rules from JSON. Fields left out keep their
+// DefaultPayoutHoldRules values.
+func ParsePayoutHoldRules(data []byte) (*PayoutHoldRules, error) {
+	r := DefaultPayoutHoldRules()
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("invalid payout hold rules: %w", err)
+	}
+	if r.MinMinutesOnSite < 0 {
+		return nil, fmt.Errorf("payout hold rules: negative min_minutes_on_site")
+	}
+	return r, nil
+}
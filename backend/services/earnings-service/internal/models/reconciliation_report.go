@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// ReconciliationFindingType is a kind of discrepancy between completed jobs,
+// payouts and Stripe.
+type ReconciliationFindingType string
+
+const (
+	// FindingOrphanedJob is a completed job, not on hold, whose pay period
+	// closed without it landing in any payout.
+	FindingOrphanedJob ReconciliationFindingType = "ORPHANED_JOB"
+	// FindingDoublePaidJob is a job in more than one payout.
+	FindingDoublePaidJob ReconciliationFindingType = "DOUBLE_PAID_JOB"
+	// FindingInvalidPayoutJob is a job in a payout that doesn't exist, isn't
+	// completed or belongs to another worker.
+	FindingInvalidPayoutJob ReconciliationFindingType = "INVALID_PAYOUT_JOB"
+	// FindingAmountMismatch is a payout whose amount isn't its jobs' pay
+	// plus its adjustments, less its fee.
+	FindingAmountMismatch ReconciliationFindingType = "AMOUNT_MISMATCH"
+	// FindingMissingStripeReference is a PAID payout with money to send but
+	// no Stripe transfer or payout recorded.
+	FindingMissingStripeReference ReconciliationFindingType = "MISSING_STRIPE_REFERENCE"
+	// FindingStripeMismatch is a payout whose Stripe transfer or payout is
+	// missing, or differs from it in amount, destination or status.
+	FindingStripeMismatch ReconciliationFindingType = "STRIPE_MISMATCH"
+	// FindingUnmatchedStripeTransfer is a Stripe transfer made for a payout
+	// that doesn't record it, e.g. one left behind by a retried attempt.
+	FindingUnmatchedStripeTransfer ReconciliationFindingType = "UNMATCHED_STRIPE_TRANSFER"
+)
+
+// ReconciliationFinding is one discrepancy. Expected and Actual are set for
+// amount mismatches.
+type ReconciliationFinding struct {
+	Type          ReconciliationFindingType `json:"type"`
+	WorkerID      *uuid.UUID                `json:"worker_id,omitempty"`
+	PayoutID      *uuid.UUID                `json:"payout_id,omitempty"`
+	JobInstanceID *uuid.UUID                `json:"job_instance_id,omitempty"`
+	StripeID      string                    `json:"stripe_id,omitempty"`
+	Expected      *models.Money             `json:"expected,omitempty"`
+	Actual        *models.Money             `json:"actual,omitempty"`
+	Details       string                    `json:"details"`
+}
+
+// ReconciliationStatusType is the outcome of a reconciliation run.
+type ReconciliationStatusType string
+
+const (
+	ReconciliationStatusClean         ReconciliationStatusType = "CLEAN"
+	ReconciliationStatusDiscrepancies ReconciliationStatusType = "DISCREPANCIES"
+	// ReconciliationStatusFailed means the run stopped early; Error says
+	// why and Findings holds what was found before it did.
+	ReconciliationStatusFailed ReconciliationStatusType = "FAILED"
+)
+
+// ReconciliationReport is the result of one reconciliation run over the
+// payouts created and jobs serviced in [WindowStart, WindowEnd).
+type ReconciliationReport struct {
+	ID               uuid.UUID                `json:"id"`
+	WindowStart      time.Time                `json:"window_start"`
+	WindowEnd        time.Time                `json:"window_end"`
+	Status           ReconciliationStatusType `json:"status"`
+	PayoutsChecked   int                      `json:"payouts_checked"`
+	JobsChecked      int                      `json:"jobs_checked"`
+	TransfersChecked int                      `json:"transfers_checked"`
+	Findings         []ReconciliationFinding  `json:"findings"`
+	Error            string                   `json:"error,omitempty"`
+	StartedAt        time.Time                `json:"started_at"`
+	FinishedAt       time.Time                `json:"finished_at"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// AdjustmentKindType says why a worker's pay is being adjusted.
+type AdjustmentKindType string
+
+const (
+	AdjustmentKindBonus         AdjustmentKindType = "BONUS"
+	AdjustmentKindReferralBonus AdjustmentKindType = "REFERRAL_BONUS"
+	// AdjustmentKindCorrection fixes an under- or overpayment, so it may go
+	// either way.
+	AdjustmentKindCorrection AdjustmentKindType = "CORRECTION"
+	AdjustmentKindClawback   AdjustmentKindType = "CLAWBACK"
+	// AdjustmentKindCarryForward is written by payout aggregation when a
+	// payout that folds in adjustments can't be transferred, because it nets
+	// out negative or below the minimum; it moves the balance to the next
+	// payout.
+	AdjustmentKindCarryForward AdjustmentKindType = "CARRY_FORWARD"
+	// AdjustmentKindLateJob pays a job that was completed after its period's
+	// payout was sent. The job counts as paid once it exists.
+	AdjustmentKindLateJob AdjustmentKindType = "LATE_JOB"
+)
+
+// ValidAmount reports whether amount has the sign the kind allows.
+func (k AdjustmentKindType) ValidAmount(amount models.Money) bool {
+	switch k {
+	case AdjustmentKindBonus, AdjustmentKindReferralBonus, AdjustmentKindLateJob:
+		return amount.IsPositive()
+	case AdjustmentKindClawback:
+		return amount.IsNegative()
+	case AdjustmentKindCorrection, AdjustmentKindCarryForward:
+		return !amount.IsZero()
+	}
+	return false
+}
+
+// AdjustmentStatusType is where an adjustment is in its approval flow.
+type AdjustmentStatusType string
+
+const (
+	AdjustmentStatusPendingApproval AdjustmentStatusType = "PENDING_APPROVAL"
+	AdjustmentStatusApproved        AdjustmentStatusType = "APPROVED"
+	AdjustmentStatusRejected        AdjustmentStatusType = "REJECTED"
+	// AdjustmentStatusApplied means the adjustment was folded into PayoutID.
+	AdjustmentStatusApplied AdjustmentStatusType = "APPLIED"
+)
+
+/*
+WorkerAdjustment is a change to what a worker is paid that doesn't come from
+a job's pay: a bonus, a correction or a clawback. Ops create it, a different
+ops user approves or rejects it, and approved adjustments are folded into the
+worker's next payout. JobInstanceID and RelatedPayoutID link it to what it is
+about; PayoutID is the payout it was paid (or recovered) through.
+*/
+type WorkerAdjustment struct {
+	ID              uuid.UUID            `json:"id"`
+	WorkerID        uuid.UUID            `json:"worker_id"`
+	Kind            AdjustmentKindType   `json:"kind"`
+	Amount          models.Money         `json:"amount"`
+	Reason          string               `json:"reason"`
+	JobInstanceID   *uuid.UUID           `json:"job_instance_id,omitempty"`
+	RelatedPayoutID *uuid.UUID           `json:"related_payout_id,omitempty"`
+	Status          AdjustmentStatusType `json:"status"`
+	CreatedBy       *uuid.UUID           `json:"created_by,omitempty"`
+	DecidedBy       *uuid.UUID           `json:"decided_by,omitempty"`
+	DecidedAt       *time.Time           `json:"decided_at,omitempty"`
+	DecisionNote    string               `json:"decision_note,omitempty"`
+	PayoutID        *uuid.UUID           `json:"payout_id,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// DisputeCategoryType says what a worker is disputing.
+type DisputeCategoryType string
+
+const (
+	DisputeCategoryUnderpaid          DisputeCategoryType = "UNDERPAID"
+	DisputeCategoryUnfairCancellation DisputeCategoryType = "UNFAIR_CANCELLATION"
+	DisputeCategoryPayout             DisputeCategoryType = "PAYOUT"
+	DisputeCategoryOther              DisputeCategoryType = "OTHER"
+)
+
+// DisputeStatusType is where a dispute is in review.
+type DisputeStatusType string
+
+const (
+	DisputeStatusOpen        DisputeStatusType = "OPEN"
+	DisputeStatusUnderReview DisputeStatusType = "UNDER_REVIEW"
+	// DisputeStatusNeedsInfo waits on the worker; their reply moves it back
+	// to UNDER_REVIEW.
+	DisputeStatusNeedsInfo DisputeStatusType = "NEEDS_INFO"
+	DisputeStatusApproved  DisputeStatusType = "APPROVED"
+	DisputeStatusDenied    DisputeStatusType = "DENIED"
+)
+
+// Resolved reports whether the dispute is closed.
+func (s DisputeStatusType) Resolved() bool {
+	return s == DisputeStatusApproved || s == DisputeStatusDenied
+}
+
+// DisputeActionType names a step in a dispute's timeline.
+type DisputeActionType string
+
+const (
+	DisputeActionOpened        DisputeActionType = "OPENED"
+	DisputeActionTriaged       DisputeActionType = "TRIAGED"
+	DisputeActionInfoRequested DisputeActionType = "INFO_REQUESTED"
+	DisputeActionInfoProvided  DisputeActionType = "INFO_PROVIDED"
+	DisputeActionApproved      DisputeActionType = "APPROVED"
+	DisputeActionDenied        DisputeActionType = "DENIED"
+)
+
+/*
+WorkerDispute is a worker's claim that a job was underpaid or unfairly
+canceled, or that a payout week is wrong. It names a job, a payout or both.
+Evidence is a list of links or notes the worker attached. An approved
+dispute points at the pay adjustment created for it.
+*/
+type WorkerDispute struct {
+	ID              uuid.UUID           `json:"id"`
+	WorkerID        uuid.UUID           `json:"worker_id"`
+	JobInstanceID   *uuid.UUID          `json:"job_instance_id,omitempty"`
+	PayoutID        *uuid.UUID          `json:"payout_id,omitempty"`
+	Category        DisputeCategoryType `json:"category"`
+	Reason          string              `json:"reason"`
+	Evidence        []string            `json:"evidence"`
+	RequestedAmount *models.Money       `json:"requested_amount,omitempty"`
+	Status          DisputeStatusType   `json:"status"`
+	AssignedTo      *uuid.UUID          `json:"assigned_to,omitempty"`
+	ResolvedBy      *uuid.UUID          `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time          `json:"resolved_at,omitempty"`
+	ResolutionNote  string              `json:"resolution_note,omitempty"`
+	AdjustmentID    *uuid.UUID          `json:"adjustment_id,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// WorkerDisputeEvent is one step in a dispute's timeline. ActorID is the
+// worker or staff member who took it.
+type WorkerDisputeEvent struct {
+	ID         uuid.UUID          `json:"id"`
+	DisputeID  uuid.UUID          `json:"dispute_id"`
+	ActorID    *uuid.UUID         `json:"actor_id,omitempty"`
+	Action     DisputeActionType  `json:"action"`
+	FromStatus *DisputeStatusType `json:"from_status,omitempty"`
+	ToStatus   DisputeStatusType  `json:"to_status"`
+	Note       string             `json:"note,omitempty"`
+	Evidence   []string           `json:"evidence,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
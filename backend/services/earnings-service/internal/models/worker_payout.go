@@ -17,24 +17,67 @@ const (
 	PayoutStatusFailed     PayoutStatusType = "FAILED"
 )
 
-// WorkerPayout represents a weekly payout to a worker.
+// PayoutKindType tells the payout for a worker's pay period from an
+// on-demand cash-out.
+type PayoutKindType string
+
+const (
+	PayoutKindScheduled PayoutKindType = "SCHEDULED"
+	PayoutKindOnDemand  PayoutKindType = "ON_DEMAND"
+)
+
+// PayoutMethodType is how Stripe moves the money to the worker's bank.
+type PayoutMethodType string
+
+const (
+	PayoutMethodStandard PayoutMethodType = "STANDARD"
+	PayoutMethodInstant  PayoutMethodType = "INSTANT"
+)
+
+// PayoutProviderType is the rail a payout was last sent over.
+type PayoutProviderType string
+
+const (
+	PayoutProviderStripe PayoutProviderType = "STRIPE"
+	PayoutProviderACH    PayoutProviderType = "ACH"
+)
+
+// WorkerPayout represents a payout to a worker: the scheduled payout for a
+// pay period, or an on-demand cash-out filed under the period it was made in.
+// The period is [PeriodStart, PeriodEnd) on the worker's pay schedule.
 type WorkerPayout struct {
 	models.Versioned
-	ID                uuid.UUID        `json:"id"`
-	WorkerID          uuid.UUID        `json:"worker_id"`
-	WeekStartDate     time.Time        `json:"week_start_date"` // Always a Monday
-	WeekEndDate       time.Time        `json:"week_end_date"`   // Always a Sunday
-	AmountCents       int64            `json:"amount_cents"`
-	Status            PayoutStatusType `json:"status"`
-	StripeTransferID  *string          `json:"stripe_transfer_id,omitempty"`
-	StripePayoutID    *string          `json:"stripe_payout_id,omitempty"`
-	JobInstanceIDs    []uuid.UUID      `json:"job_instance_ids"`
-	LastFailureReason *string          `json:"last_failure_reason,omitempty"`
-	RetryCount        int              `json:"retry_count"`
-	LastAttemptAt     *time.Time       `json:"last_attempt_at,omitempty"`
-	NextAttemptAt     *time.Time       `json:"next_attempt_at,omitempty"`
-	CreatedAt         time.Time        `json:"created_at"`
-	UpdatedAt         time.Time        `json:"updated_at"`
+	ID                uuid.UUID          `json:"id"`
+	WorkerID          uuid.UUID          `json:"worker_id"`
+	PeriodStart       time.Time          `json:"period_start"`
+	PeriodEnd         time.Time          `json:"period_end"`
+	PayScheduleID     *uuid.UUID         `json:"pay_schedule_id,omitempty"` // nil for periods of the unstored daily schedule
+	AmountCents       int64              `json:"amount_cents"`
+	AdjustmentCents   int64              `json:"adjustment_cents"` // net of the adjustments folded in; included in AmountCents
+	FeeCents          int64              `json:"fee_cents"`        // cash-out fee; already taken out of AmountCents
+	CarriedCents      int64              `json:"carried_cents"`    // part of AmountCents carried forward to the next payout instead of sent
+	Kind              PayoutKindType     `json:"kind"`
+	Method            PayoutMethodType   `json:"method"`
+	Provider          PayoutProviderType `json:"provider"`
+	Status            PayoutStatusType   `json:"status"`
+	StripeTransferID  *string            `json:"stripe_transfer_id,omitempty"`
+	StripePayoutID    *string            `json:"stripe_payout_id,omitempty"`
+	ACHBatchID        *uuid.UUID         `json:"ach_batch_id,omitempty"`     // the NACHA file an ACH payout went out in
+	ACHTraceNumber    *string            `json:"ach_trace_number,omitempty"` // its entry's trace number in that file
+	JobInstanceIDs    []uuid.UUID        `json:"job_instance_ids"`
+	LastFailureReason *string            `json:"last_failure_reason,omitempty"`
+	RetryCount        int                `json:"retry_count"`
+	LastAttemptAt     *time.Time         `json:"last_attempt_at,omitempty"`
+	NextAttemptAt     *time.Time         `json:"next_attempt_at,omitempty"`
+	PaidAt            *time.Time         `json:"paid_at,omitempty"` // when it moved to PAID; cleared if it fails afterwards
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// SentCents is what the payout transfers: its amount less what was carried
+// forward.
+func (p *WorkerPayout) SentCents() int64 {
+	return p.AmountCents - p.CarriedCents
 }
 
 func (p *WorkerPayout) GetID() string {
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// ACHBatchRepository stores the NACHA files made for the bank. A file
+// holds account numbers, so it is encrypted with the DB encryption key.
+type ACHBatchRepository interface {
+	Create(ctx context.Context, b *internal_models.ACHBatch) error
+	// GetByID returns the batch with its file, or nil if there is none.
+	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.ACHBatch, error)
+	// List returns batches newest first, without their files.
+	List(ctx context.Context, limit int) ([]*internal_models.ACHBatch, error)
+	// CountCreatedSince counts the batches made at or after since.
+	CountCreatedSince(ctx context.Context, since time.Time) (int, error)
+	// NextTraceSequences takes n numbers from the ACH trace sequence.
+	NextTraceSequences(ctx context.Context, n int) ([]int64, error)
+	// MarkSubmitted moves a GENERATED batch to SUBMITTED. It returns nil
+	// when the batch doesn't exist or was submitted already.
+	MarkSubmitted(ctx context.Context, id, actorID uuid.UUID) (*internal_models.ACHBatch, error)
+}
+
+type achBatchRepo struct {
+	db     repositories.DB
+	encKey []byte
+}
+
+// NewACHBatchRepository creates a new instance of the repository.
+func NewACHBatchRepository(db repositories.DB, encKey []byte) ACHBatchRepository {
+	return &achBatchRepo{db: db, encKey: encKey}
+}
+
+const achBatchColumns = `
+	id, file_name, status, entry_count, total_cents, effective_date,
+	created_by, created_at, submitted_by, submitted_at
+`
+
+func scanACHBatch(row pgx.Row, extra ...any) (*internal_models.ACHBatch, error) {
+	var b internal_models.ACHBatch
+	dest := append([]any{
+		&b.ID, &b.FileName, &b.Status, &b.EntryCount, &b.TotalCents, &b.EffectiveDate,
+		&b.CreatedBy, &b.CreatedAt, &b.SubmittedBy, &b.SubmittedAt,
+	}, extra...)
+	err := row.Scan(dest...)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *achBatchRepo) Create(ctx context.Context, b *internal_models.ACHBatch) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	enc, err := utils.Encrypt(r.encKey, string(b.File))
+	if err != nil {
+		return fmt.Errorf("encrypt ACH file: %w", err)
+	}
+	return r.db.QueryRow(ctx, `
+		INSERT INTO ach_batches (
+			id, file_name, status, entry_count, total_cents, effective_date,
+			file_encrypted, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at`,
+		b.ID, b.FileName, b.Status, b.EntryCount, b.TotalCents, b.EffectiveDate, enc, b.CreatedBy,
+	).Scan(&b.CreatedAt)
+}
+
+func (r *achBatchRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal_models.ACHBatch, error) {
+	var enc string
+	b, err := scanACHBatch(r.db.QueryRow(ctx,
+		"SELECT"+achBatchColumns+", file_encrypted FROM ach_batches WHERE id = $1", id), &enc)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	file, err := utils.Decrypt(r.encKey, enc)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt ACH file: %w", err)
+	}
+	b.File = []byte(file)
+	return b, nil
+}
+
+func (r *achBatchRepo) List(ctx context.Context, limit int) ([]*internal_models.ACHBatch, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT"+achBatchColumns+"FROM ach_batches ORDER BY created_at DESC LIMIT $1", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.ACHBatch
+	for rows.Next() {
+		b, err := scanACHBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (r *achBatchRepo) CountCreatedSince(ctx context.Context, since time.Time) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM ach_batches WHERE created_at >= $1`, since).Scan(&n)
+	return n, err
+}
+
+func (r *achBatchRepo) NextTraceSequences(ctx context.Context, n int) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `SELECT nextval('ach_trace_seq') FROM generate_series(1, $1)`, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int64, 0, n)
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		out = append(out, seq)
+	}
+	return out, rows.Err()
+}
+
+func (r *achBatchRepo) MarkSubmitted(ctx context.Context, id, actorID uuid.UUID) (*internal_models.ACHBatch, error) {
+	return scanACHBatch(r.db.QueryRow(ctx, `
+		UPDATE ach_batches
+		SET status = 'SUBMITTED', submitted_by = $2, submitted_at = NOW()
+		WHERE id = $1 AND status = 'GENERATED'
+		RETURNING`+achBatchColumns,
+		id, actorID,
+	))
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// ACHReturnRepository stores the returns imported from the bank's return
+// files.
+type ACHReturnRepository interface {
+	// Create writes ret unless the same return (trace number and code) was
+	// imported before. It reports whether ret was written.
+	Create(ctx context.Context, ret *internal_models.ACHReturn) (bool, error)
+	// List returns returns newest first.
+	List(ctx context.Context, limit int) ([]*internal_models.ACHReturn, error)
+}
+
+type achReturnRepo struct {
+	db repositories.DB
+}
+
+// NewACHReturnRepository creates a new instance of the repository.
+func NewACHReturnRepository(db repositories.DB) ACHReturnRepository {
+	return &achReturnRepo{db: db}
+}
+
+func (r *achReturnRepo) Create(ctx context.Context, ret *internal_models.ACHReturn) (bool, error) {
+	if ret.ID == uuid.Nil {
+		ret.ID = uuid.New()
+	}
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO ach_returns (
+			id, trace_number, return_code, failure_reason, amount_cents,
+			payout_id, batch_id, imported_by, imported_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (trace_number, return_code) DO NOTHING`,
+		ret.ID, ret.TraceNumber, ret.ReturnCode, ret.FailureReason, ret.AmountCents,
+		ret.PayoutID, ret.BatchID, ret.ImportedBy, ret.ImportedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *achReturnRepo) List(ctx context.Context, limit int) ([]*internal_models.ACHReturn, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			id, trace_number, return_code, failure_reason, amount_cents,
+			payout_id, batch_id, imported_by, imported_at
+		FROM ach_returns
+		ORDER BY imported_at DESC
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.ACHReturn
+	for rows.Next() {
+		var ret internal_models.ACHReturn
+		if err := rows.Scan(
+			&ret.ID, &ret.TraceNumber, &ret.ReturnCode, &ret.FailureReason, &ret.AmountCents,
+			&ret.PayoutID, &ret.BatchID, &ret.ImportedBy, &ret.ImportedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &ret)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// PayScheduleRepository stores pay schedules and who is on them.
+type PayScheduleRepository interface {
+	List(ctx context.Context) ([]*internal_models.PaySchedule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.PaySchedule, error)
+	// Create writes s. A new default schedule replaces the old one.
+	Create(ctx context.Context, s *internal_models.PaySchedule) error
+	// Assign puts a worker or market on a schedule, replacing any schedule
+	// they were on.
+	Assign(ctx context.Context, a *internal_models.PayScheduleAssignment) error
+	// Unassign takes a worker or market off its schedule. It reports whether
+	// there was an assignment to remove.
+	Unassign(ctx context.Context, workerID *uuid.UUID, market *string) (bool, error)
+	ListAssignments(ctx context.Context) ([]*internal_models.PayScheduleAssignment, error)
+	// WorkerMarkets returns the market of the property each worker last
+	// completed a job at. Workers without one are left out.
+	WorkerMarkets(ctx context.Context, workerIDs []uuid.UUID) (map[uuid.UUID]string, error)
+}
+
+type payScheduleRepo struct {
+	db repositories.DB
+}
+
+// NewPayScheduleRepository creates a new instance of the repository.
+func NewPayScheduleRepository(db repositories.DB) PayScheduleRepository {
+	return &payScheduleRepo{db: db}
+}
+
+const payScheduleSelect = `
+	SELECT
+		id, name, frequency, anchor_date, start_hour, timezone, payout_delay_hours,
+		is_default, created_at, updated_at
+	FROM pay_schedules
+`
+
+func scanPaySchedule(row pgx.Row) (*internal_models.PaySchedule, error) {
+	var s internal_models.PaySchedule
+	err := row.Scan(
+		&s.ID, &s.Name, &s.Frequency, &s.AnchorDate, &s.StartHour, &s.Timezone, &s.PayoutDelayHours,
+		&s.IsDefault, &s.CreatedAt, &s.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *payScheduleRepo) List(ctx context.Context) ([]*internal_models.PaySchedule, error) {
+	rows, err := r.db.Query(ctx, payScheduleSelect+" ORDER BY is_default DESC, name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.PaySchedule
+	for rows.Next() {
+		s, err := scanPaySchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *payScheduleRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal_models.PaySchedule, error) {
+	return scanPaySchedule(r.db.QueryRow(ctx, payScheduleSelect+" WHERE id = $1", id))
+}
+
+func (r *payScheduleRepo) Create(ctx context.Context, s *internal_models.PaySchedule) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	if s.IsDefault {
+		if _, err = tx.Exec(ctx, `UPDATE pay_schedules SET is_default = FALSE, updated_at = NOW() WHERE is_default`); err != nil {
+			return err
+		}
+	}
+	q := `
+		INSERT INTO pay_schedules (
+			id, name, frequency, anchor_date, start_hour, timezone, payout_delay_hours,
+			is_default, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, q,
+		s.ID, s.Name, s.Frequency, s.AnchorDate, s.StartHour, s.Timezone, s.PayoutDelayHours, s.IsDefault,
+	).Scan(&s.CreatedAt, &s.UpdatedAt)
+	return err
+}
+
+func (r *payScheduleRepo) Assign(ctx context.Context, a *internal_models.PayScheduleAssignment) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	conflict := "(worker_id) WHERE worker_id IS NOT NULL"
+	if a.WorkerID == nil {
+		conflict = "(market) WHERE market IS NOT NULL"
+	}
+	q := `
+		INSERT INTO pay_schedule_assignments (id, pay_schedule_id, worker_id, market, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT ` + conflict + ` DO UPDATE SET
+			pay_schedule_id = EXCLUDED.pay_schedule_id,
+			created_by = EXCLUDED.created_by,
+			created_at = EXCLUDED.created_at
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(ctx, q, a.ID, a.PayScheduleID, a.WorkerID, a.Market, a.CreatedBy).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (r *payScheduleRepo) Unassign(ctx context.Context, workerID *uuid.UUID, market *string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM pay_schedule_assignments
+		WHERE ($1::uuid IS NOT NULL AND worker_id = $1)
+		   OR ($2::text IS NOT NULL AND market = $2)
+	`, workerID, market)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *payScheduleRepo) ListAssignments(ctx context.Context) ([]*internal_models.PayScheduleAssignment, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, pay_schedule_id, worker_id, market, created_by, created_at
+		FROM pay_schedule_assignments
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.PayScheduleAssignment
+	for rows.Next() {
+		var a internal_models.PayScheduleAssignment
+		if err := rows.Scan(&a.ID, &a.PayScheduleID, &a.WorkerID, &a.Market, &a.CreatedBy, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, &a)
+	}
+	return out, rows.Err()
+}
+
+func (r *payScheduleRepo) WorkerMarkets(ctx context.Context, workerIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	markets := make(map[uuid.UUID]string)
+	if len(workerIDs) == 0 {
+		return markets, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT ON (ji.assigned_worker_id) ji.assigned_worker_id, p.market
+		FROM job_instances ji
+		JOIN job_definitions d ON d.id = ji.definition_id
+		JOIN properties p ON p.id = d.property_id
+		WHERE ji.assigned_worker_id = ANY($1)
+		  AND ji.status = 'COMPLETED'
+		  AND p.market IS NOT NULL
+		ORDER BY ji.assigned_worker_id, ji.service_date DESC, ji.check_out_at DESC NULLS LAST
+	`, workerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id     uuid.UUID
+			market string
+		)
+		if err := rows.Scan(&id, &market); err != nil {
+			return nil, err
+		}
+		markets[id] = market
+	}
+	return markets, rows.Err()
+}
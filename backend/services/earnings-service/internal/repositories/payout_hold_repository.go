@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// PayoutHoldRepository stores the holds keeping jobs out of payouts.
+type PayoutHoldRepository interface {
+	// Create writes h unless the job already has a hold. It reports whether
+	// h was written.
+	Create(ctx context.Context, h *internal_models.PayoutHold) (bool, error)
+	// Place writes h, also putting a released hold on the job back on hold.
+	// It reports whether h was written; a job that is held or rejected
+	// already is left alone.
+	Place(ctx context.Context, h *internal_models.PayoutHold) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.PayoutHold, error)
+	// ByJobs returns the hold on each of the jobs that has one.
+	ByJobs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]*internal_models.PayoutHold, error)
+	// List returns holds newest first. A nil workerID or empty statuses
+	// matches all.
+	List(ctx context.Context, workerID *uuid.UUID, statuses []internal_models.HoldStatusType, limit int) ([]*internal_models.PayoutHold, error)
+	// ListReleasedSince returns holds released at or after since.
+	ListReleasedSince(ctx context.Context, since time.Time) ([]*internal_models.PayoutHold, error)
+	// Resolve moves a HELD hold to status. It returns nil when the hold
+	// doesn't exist or isn't held.
+	Resolve(ctx context.Context, id uuid.UUID, status internal_models.HoldStatusType, actorID uuid.UUID, note string) (*internal_models.PayoutHold, error)
+	// FailedVerificationJobIDs returns the jobs with a unit that
+	// permanently failed photo verification.
+	FailedVerificationJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type payoutHoldRepo struct {
+	db repositories.DB
+}
+
+// NewPayoutHoldRepository creates a new instance of the repository.
+func NewPayoutHoldRepository(db repositories.DB) PayoutHoldRepository {
+	return &payoutHoldRepo{db: db}
+}
+
+const payoutHoldSelect = `
+	SELECT
+		id, job_instance_id, worker_id, reason, details, status, created_by,
+		resolved_by, resolved_at, resolution_note, created_at, updated_at
+	FROM payout_holds
+`
+
+const payoutHoldReturning = `
+	RETURNING
+		id, job_instance_id, worker_id, reason, details, status, created_by,
+		resolved_by, resolved_at, resolution_note, created_at, updated_at
+`
+
+func scanPayoutHold(row pgx.Row) (*internal_models.PayoutHold, error) {
+	var h internal_models.PayoutHold
+	err := row.Scan(
+		&h.ID, &h.JobInstanceID, &h.WorkerID, &h.Reason, &h.Details, &h.Status, &h.CreatedBy,
+		&h.ResolvedBy, &h.ResolvedAt, &h.ResolutionNote, &h.CreatedAt, &h.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *payoutHoldRepo) insert(ctx context.Context, h *internal_models.PayoutHold, onConflict string) (bool, error) {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	h.Status = internal_models.HoldStatusHeld
+	q := `
+		INSERT INTO payout_holds (
+			id, job_instance_id, worker_id, reason, details, status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (job_instance_id) ` + onConflict + payoutHoldReturning
+	written, err := scanPayoutHold(r.db.QueryRow(ctx, q,
+		h.ID, h.JobInstanceID, h.WorkerID, h.Reason, h.Details, h.Status, h.CreatedBy,
+	))
+	if err != nil || written == nil {
+		return false, err
+	}
+	*h = *written
+	return true, nil
+}
+
+func (r *payoutHoldRepo) Create(ctx context.Context, h *internal_models.PayoutHold) (bool, error) {
+	return r.insert(ctx, h, "DO NOTHING")
+}
+
+func (r *payoutHoldRepo) Place(ctx context.Context, h *internal_models.PayoutHold) (bool, error) {
+	return r.insert(ctx, h, `DO UPDATE SET
+			reason = EXCLUDED.reason,
+			details = EXCLUDED.details,
+			status = EXCLUDED.status,
+			created_by = EXCLUDED.created_by,
+			resolved_by = NULL,
+			resolved_at = NULL,
+			resolution_note = '',
+			updated_at = NOW()
+		WHERE payout_holds.status = 'RELEASED'`)
+}
+
+func (r *payoutHoldRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal_models.PayoutHold, error) {
+	return scanPayoutHold(r.db.QueryRow(ctx, payoutHoldSelect+" WHERE id = $1", id))
+}
+
+func (r *payoutHoldRepo) ByJobs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]*internal_models.PayoutHold, error) {
+	holds := make(map[uuid.UUID]*internal_models.PayoutHold)
+	if len(jobIDs) == 0 {
+		return holds, nil
+	}
+	list, err := r.query(ctx, payoutHoldSelect+" WHERE job_instance_id = ANY($1)", jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range list {
+		holds[h.JobInstanceID] = h
+	}
+	return holds, nil
+}
+
+func (r *payoutHoldRepo) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	statuses []internal_models.HoldStatusType,
+	limit int,
+) ([]*internal_models.PayoutHold, error) {
+	st := make([]string, len(statuses))
+	for i, s := range statuses {
+		st[i] = string(s)
+	}
+	return r.query(ctx, payoutHoldSelect+`
+		WHERE ($1::uuid IS NULL OR worker_id = $1)
+		  AND (cardinality($2::text[]) = 0 OR status = ANY($2))
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`, workerID, st, limit)
+}
+
+func (r *payoutHoldRepo) ListReleasedSince(ctx context.Context, since time.Time) ([]*internal_models.PayoutHold, error) {
+	return r.query(ctx, payoutHoldSelect+`
+		WHERE status = 'RELEASED' AND resolved_at >= $1
+		ORDER BY resolved_at
+	`, since)
+}
+
+func (r *payoutHoldRepo) Resolve(
+	ctx context.Context,
+	id uuid.UUID,
+	status internal_models.HoldStatusType,
+	actorID uuid.UUID,
+	note string,
+) (*internal_models.PayoutHold, error) {
+	return scanPayoutHold(r.db.QueryRow(ctx, `
+		UPDATE payout_holds SET
+			status = $2,
+			resolved_by = $3,
+			resolved_at = NOW(),
+			resolution_note = $4,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'HELD'
+	`+payoutHoldReturning, id, status, actorID, note))
+}
+
+func (r *payoutHoldRepo) FailedVerificationJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	failed := make(map[uuid.UUID]bool)
+	if len(jobIDs) == 0 {
+		return failed, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT job_instance_id
+		FROM job_unit_verifications
+		WHERE job_instance_id = ANY($1) AND permanent_failure
+	`, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		failed[id] = true
+	}
+	return failed, rows.Err()
+}
+
+func (r *payoutHoldRepo) query(ctx context.Context, q string, args ...any) ([]*internal_models.PayoutHold, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.PayoutHold
+	for rows.Next() {
+		h, err := scanPayoutHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
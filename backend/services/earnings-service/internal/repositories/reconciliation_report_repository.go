@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// ReconciliationReportRepository stores the results of reconciliation runs.
+type ReconciliationReportRepository interface {
+	Create(ctx context.Context, r *internal_models.ReconciliationReport) error
+	// Latest returns the most recently finished report, or nil if there
+	// is none.
+	Latest(ctx context.Context) (*internal_models.ReconciliationReport, error)
+}
+
+type reconciliationReportRepo struct {
+	db repositories.DB
+}
+
+// NewReconciliationReportRepository creates a new instance of the repository.
+func NewReconciliationReportRepository(db repositories.DB) ReconciliationReportRepository {
+	return &reconciliationReportRepo{db: db}
+}
+
+func (r *reconciliationReportRepo) Create(ctx context.Context, rep *internal_models.ReconciliationReport) error {
+	if rep.ID == uuid.Nil {
+		rep.ID = uuid.New()
+	}
+	findings := rep.Findings
+	if findings == nil {
+		findings = []internal_models.ReconciliationFinding{}
+	}
+	raw, err := json.Marshal(findings)
+	if err != nil {
+		return fmt.Errorf("marshal findings: %w", err)
+	}
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO reconciliation_reports (
+			id, window_start, window_end, status, payouts_checked, jobs_checked,
+			transfers_checked, findings, error, started_at, finished_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		rep.ID, rep.WindowStart, rep.WindowEnd, rep.Status, rep.PayoutsChecked, rep.JobsChecked,
+		rep.TransfersChecked, raw, rep.Error, rep.StartedAt, rep.FinishedAt,
+	)
+	return err
+}
+
+func (r *reconciliationReportRepo) Latest(ctx context.Context) (*internal_models.ReconciliationReport, error) {
+	var rep internal_models.ReconciliationReport
+	var findings []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			id, window_start, window_end, status, payouts_checked, jobs_checked,
+			transfers_checked, findings, error, started_at, finished_at
+		FROM reconciliation_reports
+		ORDER BY finished_at DESC
+		LIMIT 1`,
+	).Scan(
+		&rep.ID, &rep.WindowStart, &rep.WindowEnd, &rep.Status, &rep.PayoutsChecked, &rep.JobsChecked,
+		&rep.TransfersChecked, &findings, &rep.Error, &rep.StartedAt, &rep.FinishedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(findings, &rep.Findings); err != nil {
+		return nil, fmt.Errorf("unmarshal findings: %w", err)
+	}
+	return &rep, nil
+}
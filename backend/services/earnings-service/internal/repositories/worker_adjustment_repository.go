@@ -0,0 +1,257 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// WorkerAdjustmentRepository stores worker pay adjustments. Rows move
+// forward through their statuses: PENDING_APPROVAL to APPROVED or REJECTED,
+// then APPROVED to APPLIED when folded into a payout. The one way back is a
+// cash-out that failed for good, which returns its adjustments to APPROVED.
+type WorkerAdjustmentRepository interface {
+	Create(ctx context.Context, a *internal_models.WorkerAdjustment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.WorkerAdjustment, error)
+	// List returns adjustments newest first. A nil workerID or empty status
+	// matches all.
+	List(ctx context.Context, workerID *uuid.UUID, status internal_models.AdjustmentStatusType, limit int) ([]*internal_models.WorkerAdjustment, error)
+	// Decide moves a PENDING_APPROVAL adjustment to status. It returns nil
+	// when the adjustment doesn't exist or was already decided.
+	Decide(ctx context.Context, id uuid.UUID, status internal_models.AdjustmentStatusType, deciderID uuid.UUID, note string) (*internal_models.WorkerAdjustment, error)
+	// ListApprovedForWorker returns the worker's approved, unapplied
+	// adjustments oldest first, locking them for the caller's transaction.
+	ListApprovedForWorker(ctx context.Context, workerID uuid.UUID) ([]*internal_models.WorkerAdjustment, error)
+	// ListWorkerIDsWithApproved returns workers with approved, unapplied
+	// adjustments.
+	ListWorkerIDsWithApproved(ctx context.Context) ([]uuid.UUID, error)
+	// MarkApplied records that ids were folded into payoutID.
+	MarkApplied(ctx context.Context, ids []uuid.UUID, payoutID uuid.UUID) error
+	// ReleaseFromPayout returns the adjustments applied to payoutID to
+	// APPROVED and reports how many there were.
+	ReleaseFromPayout(ctx context.Context, payoutID uuid.UUID) (int64, error)
+	// ListByPayoutIDs returns the adjustments folded into each payout.
+	ListByPayoutIDs(ctx context.Context, payoutIDs []uuid.UUID) (map[uuid.UUID][]*internal_models.WorkerAdjustment, error)
+	// ListByIDs returns those of ids that exist, by ID.
+	ListByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*internal_models.WorkerAdjustment, error)
+}
+
+type workerAdjustmentRepo struct {
+	db repositories.DB
+}
+
+// NewWorkerAdjustmentRepository creates a new instance of the repository.
+func NewWorkerAdjustmentRepository(db repositories.DB) WorkerAdjustmentRepository {
+	return &workerAdjustmentRepo{db: db}
+}
+
+const workerAdjustmentSelect = `
+	SELECT
+		id, worker_id, kind, amount_cents, reason, job_instance_id, related_payout_id,
+		status, created_by, decided_by, decided_at, decision_note, payout_id,
+		created_at, updated_at
+	FROM worker_pay_adjustments
+`
+
+func scanWorkerAdjustment(row pgx.Row) (*internal_models.WorkerAdjustment, error) {
+	var a internal_models.WorkerAdjustment
+	err := row.Scan(
+		&a.ID, &a.WorkerID, &a.Kind, &a.Amount, &a.Reason, &a.JobInstanceID, &a.RelatedPayoutID,
+		&a.Status, &a.CreatedBy, &a.DecidedBy, &a.DecidedAt, &a.DecisionNote, &a.PayoutID,
+		&a.CreatedAt, &a.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *workerAdjustmentRepo) queryAll(ctx context.Context, q string, args ...any) ([]*internal_models.WorkerAdjustment, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.WorkerAdjustment
+	for rows.Next() {
+		a, err := scanWorkerAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *workerAdjustmentRepo) Create(ctx context.Context, a *internal_models.WorkerAdjustment) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.Status == "" {
+		a.Status = internal_models.AdjustmentStatusPendingApproval
+	}
+	q := `
+		INSERT INTO worker_pay_adjustments (
+			id, worker_id, kind, amount_cents, reason, job_instance_id, related_payout_id,
+			status, created_by, decided_by, decided_at, decision_note, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	return r.db.QueryRow(ctx, q,
+		a.ID, a.WorkerID, a.Kind, a.Amount, a.Reason, a.JobInstanceID, a.RelatedPayoutID,
+		a.Status, a.CreatedBy, a.DecidedBy, a.DecidedAt, a.DecisionNote,
+	).Scan(&a.CreatedAt, &a.UpdatedAt)
+}
+
+func (r *workerAdjustmentRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal_models.WorkerAdjustment, error) {
+	return scanWorkerAdjustment(r.db.QueryRow(ctx, workerAdjustmentSelect+" WHERE id = $1", id))
+}
+
+func (r *workerAdjustmentRepo) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	status internal_models.AdjustmentStatusType,
+	limit int,
+) ([]*internal_models.WorkerAdjustment, error) {
+	q := workerAdjustmentSelect + `
+		WHERE ($1::uuid IS NULL OR worker_id = $1)
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`
+	return r.queryAll(ctx, q, workerID, string(status), limit)
+}
+
+func (r *workerAdjustmentRepo) Decide(
+	ctx context.Context,
+	id uuid.UUID,
+	status internal_models.AdjustmentStatusType,
+	deciderID uuid.UUID,
+	note string,
+) (*internal_models.WorkerAdjustment, error) {
+	q := `
+		UPDATE worker_pay_adjustments SET
+			status = $2,
+			decided_by = $3,
+			decided_at = NOW(),
+			decision_note = $4,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING_APPROVAL'
+		RETURNING id
+	`
+	var got uuid.UUID
+	if err := r.db.QueryRow(ctx, q, id, status, deciderID, note).Scan(&got); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.GetByID(ctx, got)
+}
+
+func (r *workerAdjustmentRepo) ListApprovedForWorker(ctx context.Context, workerID uuid.UUID) ([]*internal_models.WorkerAdjustment, error) {
+	q := workerAdjustmentSelect + `
+		WHERE worker_id = $1 AND status = 'APPROVED'
+		ORDER BY created_at, id
+		FOR UPDATE
+	`
+	return r.queryAll(ctx, q, workerID)
+}
+
+func (r *workerAdjustmentRepo) ListWorkerIDsWithApproved(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT worker_id
+		FROM worker_pay_adjustments
+		WHERE status = 'APPROVED'
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *workerAdjustmentRepo) MarkApplied(ctx context.Context, ids []uuid.UUID, payoutID uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.Exec(ctx, `
+		UPDATE worker_pay_adjustments SET
+			status = 'APPLIED',
+			payout_id = $2,
+			updated_at = NOW()
+		WHERE id = ANY($1) AND status = 'APPROVED'
+	`, ids, payoutID)
+	return err
+}
+
+func (r *workerAdjustmentRepo) ReleaseFromPayout(ctx context.Context, payoutID uuid.UUID) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE worker_pay_adjustments SET
+			status = 'APPROVED',
+			payout_id = NULL,
+			updated_at = NOW()
+		WHERE payout_id = $1 AND status = 'APPLIED'
+	`, payoutID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *workerAdjustmentRepo) ListByPayoutIDs(
+	ctx context.Context,
+	payoutIDs []uuid.UUID,
+) (map[uuid.UUID][]*internal_models.WorkerAdjustment, error) {
+	out := make(map[uuid.UUID][]*internal_models.WorkerAdjustment, len(payoutIDs))
+	if len(payoutIDs) == 0 {
+		return out, nil
+	}
+	list, err := r.queryAll(ctx, workerAdjustmentSelect+`
+		WHERE payout_id = ANY($1)
+		ORDER BY created_at, id
+	`, payoutIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range list {
+		out[*a.PayoutID] = append(out[*a.PayoutID], a)
+	}
+	return out, nil
+}
+
+func (r *workerAdjustmentRepo) ListByIDs(
+	ctx context.Context,
+	ids []uuid.UUID,
+) (map[uuid.UUID]*internal_models.WorkerAdjustment, error) {
+	out := make(map[uuid.UUID]*internal_models.WorkerAdjustment, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	list, err := r.queryAll(ctx, workerAdjustmentSelect+`
+		WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range list {
+		out[a.ID] = a
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// WorkerBankAccountRepository stores the bank accounts ACH payouts go to.
+// Account numbers are encrypted with the DB encryption key.
+type WorkerBankAccountRepository interface {
+	// Upsert writes a, replacing the worker's previous account.
+	Upsert(ctx context.Context, a *internal_models.WorkerBankAccount) error
+	// GetByWorker returns the worker's account, or nil if they have none.
+	GetByWorker(ctx context.Context, workerID uuid.UUID) (*internal_models.WorkerBankAccount, error)
+}
+
+type workerBankAccountRepo struct {
+	db     repositories.DB
+	encKey []byte
+}
+
+// NewWorkerBankAccountRepository creates a new instance of the repository.
+func NewWorkerBankAccountRepository(db repositories.DB, encKey []byte) WorkerBankAccountRepository {
+	return &workerBankAccountRepo{db: db, encKey: encKey}
+}
+
+func (r *workerBankAccountRepo) Upsert(ctx context.Context, a *internal_models.WorkerBankAccount) error {
+	enc, err := utils.Encrypt(r.encKey, a.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("encrypt account number: %w", err)
+	}
+	if n := len(a.AccountNumber); n > 4 {
+		a.AccountLast4 = a.AccountNumber[n-4:]
+	} else {
+		a.AccountLast4 = a.AccountNumber
+	}
+	return r.db.QueryRow(ctx, `
+		INSERT INTO worker_bank_accounts (
+			worker_id, holder_name, routing_number, account_number_encrypted,
+			account_last4, account_type, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (worker_id) DO UPDATE SET
+			holder_name = EXCLUDED.holder_name,
+			routing_number = EXCLUDED.routing_number,
+			account_number_encrypted = EXCLUDED.account_number_encrypted,
+			account_last4 = EXCLUDED.account_last4,
+			account_type = EXCLUDED.account_type,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = NOW()
+		RETURNING created_at, updated_at`,
+		a.WorkerID, a.HolderName, a.RoutingNumber, enc, a.AccountLast4, a.AccountType, a.UpdatedBy,
+	).Scan(&a.CreatedAt, &a.UpdatedAt)
+}
+
+func (r *workerBankAccountRepo) GetByWorker(ctx context.Context, workerID uuid.UUID) (*internal_models.WorkerBankAccount, error) {
+	var (
+		a   internal_models.WorkerBankAccount
+		enc string
+	)
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			worker_id, holder_name, routing_number, account_number_encrypted,
+			account_last4, account_type, updated_by, created_at, updated_at
+		FROM worker_bank_accounts
+		WHERE worker_id = $1`,
+		workerID,
+	).Scan(
+		&a.WorkerID, &a.HolderName, &a.RoutingNumber, &enc,
+		&a.AccountLast4, &a.AccountType, &a.UpdatedBy, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.AccountNumber, err = utils.Decrypt(r.encKey, enc); err != nil {
+		return nil, fmt.Errorf("decrypt account number: %w", err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// DisputeTransition moves a dispute from one of From to To and records the
+// step in its timeline. Evidence is appended to the dispute's evidence.
+// AssignTo, when set, becomes the dispute's assignee. Moving into APPROVED or
+// DENIED also records who resolved it, with Note as the resolution note.
+type DisputeTransition struct {
+	DisputeID    uuid.UUID
+	From         []internal_models.DisputeStatusType
+	To           internal_models.DisputeStatusType
+	Action       internal_models.DisputeActionType
+	ActorID      *uuid.UUID
+	Note         string
+	Evidence     []string
+	AssignTo     *uuid.UUID
+	AdjustmentID *uuid.UUID
+}
+
+// WorkerDisputeRepository stores worker pay disputes and their timelines.
+type WorkerDisputeRepository interface {
+	// Create writes d and its OPENED timeline event.
+	Create(ctx context.Context, d *internal_models.WorkerDispute) error
+	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.WorkerDispute, error)
+	// List returns disputes newest first. A nil workerID or empty statuses
+	// matches all.
+	List(ctx context.Context, workerID *uuid.UUID, statuses []internal_models.DisputeStatusType, limit int) ([]*internal_models.WorkerDispute, error)
+	// Transition applies t. It returns nil when the dispute doesn't exist or
+	// isn't in one of t.From.
+	Transition(ctx context.Context, t DisputeTransition) (*internal_models.WorkerDispute, error)
+	// ListEvents returns the dispute's timeline oldest first.
+	ListEvents(ctx context.Context, disputeID uuid.UUID) ([]*internal_models.WorkerDisputeEvent, error)
+	// WorkerHadJob reports whether the worker is or was on the job: assigned
+	// to it now, or scored for it (as a cancellation or no-show is).
+	WorkerHadJob(ctx context.Context, workerID, instanceID uuid.UUID) (bool, error)
+}
+
+type workerDisputeRepo struct {
+	db repositories.DB
+}
+
+// NewWorkerDisputeRepository creates a new instance of the repository.
+func NewWorkerDisputeRepository(db repositories.DB) WorkerDisputeRepository {
+	return &workerDisputeRepo{db: db}
+}
+
+const workerDisputeSelect = `
+	SELECT
+		id, worker_id, job_instance_id, payout_id, category, reason, evidence,
+		requested_amount_cents, status, assigned_to, resolved_by, resolved_at,
+		resolution_note, adjustment_id, created_at, updated_at
+	FROM worker_pay_disputes
+`
+
+func scanWorkerDispute(row pgx.Row) (*internal_models.WorkerDispute, error) {
+	var (
+		d         internal_models.WorkerDispute
+		requested *int64
+	)
+	err := row.Scan(
+		&d.ID, &d.WorkerID, &d.JobInstanceID, &d.PayoutID, &d.Category, &d.Reason, &d.Evidence,
+		&requested, &d.Status, &d.AssignedTo, &d.ResolvedBy, &d.ResolvedAt,
+		&d.ResolutionNote, &d.AdjustmentID, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if requested != nil {
+		m := models.USD(*requested)
+		d.RequestedAmount = &m
+	}
+	return &d, nil
+}
+
+func insertDisputeEvent(ctx context.Context, q repositories.DB, ev *internal_models.WorkerDisputeEvent) error {
+	if ev.ID == uuid.Nil {
+		ev.ID = uuid.New()
+	}
+	evidence := ev.Evidence
+	if evidence == nil {
+		evidence = []string{}
+	}
+	return q.QueryRow(ctx, `
+		INSERT INTO worker_pay_dispute_events (
+			id, dispute_id, actor_id, action, from_status, to_status, note, evidence, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING created_at
+	`, ev.ID, ev.DisputeID, ev.ActorID, ev.Action, ev.FromStatus, ev.ToStatus, ev.Note, evidence).Scan(&ev.CreatedAt)
+}
+
+func (r *workerDisputeRepo) Create(ctx context.Context, d *internal_models.WorkerDispute) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.Status == "" {
+		d.Status = internal_models.DisputeStatusOpen
+	}
+	if d.Evidence == nil {
+		d.Evidence = []string{}
+	}
+	var requested *int64
+	if d.RequestedAmount != nil {
+		requested = &d.RequestedAmount.Cents
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO worker_pay_disputes (
+			id, worker_id, job_instance_id, payout_id, category, reason, evidence,
+			requested_amount_cents, status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`, d.ID, d.WorkerID, d.JobInstanceID, d.PayoutID, d.Category, d.Reason, d.Evidence,
+		requested, d.Status).Scan(&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return insertDisputeEvent(ctx, tx, &internal_models.WorkerDisputeEvent{
+		DisputeID: d.ID,
+		ActorID:   &d.WorkerID,
+		Action:    internal_models.DisputeActionOpened,
+		ToStatus:  d.Status,
+		Note:      d.Reason,
+		Evidence:  d.Evidence,
+	})
+}
+
+func (r *workerDisputeRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal_models.WorkerDispute, error) {
+	return scanWorkerDispute(r.db.QueryRow(ctx, workerDisputeSelect+" WHERE id = $1", id))
+}
+
+func (r *workerDisputeRepo) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	statuses []internal_models.DisputeStatusType,
+	limit int,
+) ([]*internal_models.WorkerDispute, error) {
+	st := make([]string, len(statuses))
+	for i, s := range statuses {
+		st[i] = string(s)
+	}
+	rows, err := r.db.Query(ctx, workerDisputeSelect+`
+		WHERE ($1::uuid IS NULL OR worker_id = $1)
+		  AND (cardinality($2::text[]) = 0 OR status = ANY($2))
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`, workerID, st, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.WorkerDispute
+	for rows.Next() {
+		d, err := scanWorkerDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *workerDisputeRepo) Transition(ctx context.Context, t DisputeTransition) (d *internal_models.WorkerDispute, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	var from internal_models.DisputeStatusType
+	err = tx.QueryRow(ctx, `SELECT status FROM worker_pay_disputes WHERE id = $1 FOR UPDATE`, t.DisputeID).Scan(&from)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(t.From, from) {
+		return nil, nil
+	}
+
+	evidence := t.Evidence
+	if evidence == nil {
+		evidence = []string{}
+	}
+	var resolvedBy *uuid.UUID
+	resolutionNote := ""
+	if t.To.Resolved() {
+		resolvedBy = t.ActorID
+		resolutionNote = t.Note
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE worker_pay_disputes SET
+			status = $2,
+			evidence = evidence || $3::text[],
+			assigned_to = COALESCE($4, assigned_to),
+			resolved_by = COALESCE($5, resolved_by),
+			resolved_at = CASE WHEN $6 THEN NOW() ELSE resolved_at END,
+			resolution_note = CASE WHEN $6 THEN $7 ELSE resolution_note END,
+			adjustment_id = COALESCE($8, adjustment_id),
+			updated_at = NOW()
+		WHERE id = $1
+	`, t.DisputeID, t.To, evidence, t.AssignTo, resolvedBy, t.To.Resolved(), resolutionNote, t.AdjustmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertDisputeEvent(ctx, tx, &internal_models.WorkerDisputeEvent{
+		DisputeID:  t.DisputeID,
+		ActorID:    t.ActorID,
+		Action:     t.Action,
+		FromStatus: &from,
+		ToStatus:   t.To,
+		Note:       t.Note,
+		Evidence:   t.Evidence,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scanWorkerDispute(tx.QueryRow(ctx, workerDisputeSelect+" WHERE id = $1", t.DisputeID))
+}
+
+func (r *workerDisputeRepo) ListEvents(ctx context.Context, disputeID uuid.UUID) ([]*internal_models.WorkerDisputeEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, dispute_id, actor_id, action, from_status, to_status, note, evidence, created_at
+		FROM worker_pay_dispute_events
+		WHERE dispute_id = $1
+		ORDER BY created_at, id
+	`, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*internal_models.WorkerDisputeEvent
+	for rows.Next() {
+		var ev internal_models.WorkerDisputeEvent
+		if err := rows.Scan(
+			&ev.ID, &ev.DisputeID, &ev.ActorID, &ev.Action, &ev.FromStatus, &ev.ToStatus,
+			&ev.Note, &ev.Evidence, &ev.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &ev)
+	}
+	return out, rows.Err()
+}
+
+func (r *workerDisputeRepo) WorkerHadJob(ctx context.Context, workerID, instanceID uuid.UUID) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM job_instances WHERE id = $2 AND assigned_worker_id = $1
+		) OR EXISTS (
+			SELECT 1 FROM worker_score_events WHERE worker_id = $1 AND job_instance_id = $2
+		)
+	`, workerID, instanceID).Scan(&ok)
+	return ok, err
+}
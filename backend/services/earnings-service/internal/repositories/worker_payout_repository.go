@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
 	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/stripe/stripe-go/v82"
 )
@@ -17,15 +18,56 @@ import (
 type WorkerPayoutRepository interface {
 	Create(ctx context.Context, payout *internal_models.WorkerPayout) error
 	GetByID(ctx context.Context, id uuid.UUID) (*internal_models.WorkerPayout, error)
-	GetByWorkerAndWeek(ctx context.Context, workerID uuid.UUID, weekStartDate time.Time) (*internal_models.WorkerPayout, error)
+	// GetScheduledByPeriod returns the worker's scheduled payout for the pay
+	// period starting at periodStart.
+	GetScheduledByPeriod(ctx context.Context, workerID uuid.UUID, periodStart time.Time) (*internal_models.WorkerPayout, error)
 	UpdateIfVersion(ctx context.Context, p *internal_models.WorkerPayout, expectedVersion int64) (pgconn.CommandTag, error)
 	UpdateWithRetry(ctx context.Context, id uuid.UUID, mutate func(*internal_models.WorkerPayout) error) error
+	// FindReadyForPayout returns PENDING payouts whose send time has come and
+	// FAILED ones due a retry.
 	FindReadyForPayout(ctx context.Context) ([]*internal_models.WorkerPayout, error)
+	// FindForWorkerByDateRange returns the worker's payouts for periods
+	// starting within [startDate, endDate], newest first.
 	FindForWorkerByDateRange(ctx context.Context, workerID uuid.UUID, startDate, endDate time.Time) ([]*internal_models.WorkerPayout, error)
+	// FindPaidForWorker returns the worker's payouts paid within [from, to),
+	// oldest first.
+	FindPaidForWorker(ctx context.Context, workerID uuid.UUID, from, to time.Time) ([]*internal_models.WorkerPayout, error)
+	// FindFailedPayoutsForWorkerByAccountError and FindFailedByReason return
+	// failed scheduled payouts to retry; a failed cash-out is never resent
+	// because its jobs went back to the worker's balance.
 	FindFailedPayoutsForWorkerByAccountError(ctx context.Context, workerID uuid.UUID) ([]*internal_models.WorkerPayout, error)
 	FindFailedByReason(ctx context.Context, reason string) ([]*internal_models.WorkerPayout, error)
+	// FindCreatedBetween returns every worker's payouts created within
+	// [from, to), oldest first.
+	FindCreatedBetween(ctx context.Context, from, to time.Time) ([]*internal_models.WorkerPayout, error)
+	// FindUnbatchedACH returns the ACH payouts waiting for a NACHA file,
+	// oldest first, locking them until the surrounding transaction ends.
+	// Payouts locked by another transaction are skipped.
+	FindUnbatchedACH(ctx context.Context) ([]*internal_models.WorkerPayout, error)
+	FindByACHBatch(ctx context.Context, batchID uuid.UUID) ([]*internal_models.WorkerPayout, error)
+	// GetByACHTrace returns the payout most recently sent with the ACH trace
+	// number, or nil if none was.
+	GetByACHTrace(ctx context.Context, traceNumber string) (*internal_models.WorkerPayout, error)
+	// PaidOutJobIDs returns which of jobIDs are already in a payout, or paid
+	// by a LATE_JOB adjustment. A cash-out that failed for good has released
+	// its jobs and doesn't count.
+	PaidOutJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	// PayoutIDsByJobs returns the payouts each of jobIDs is in, oldest
+	// first. Cash-outs that failed for good are left out.
+	PayoutIDsByJobs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+	// OnDemandSince counts the worker's on-demand payouts created at or after
+	// since, and totals what they took before fees. Payouts that failed for
+	// good are left out.
+	OnDemandSince(ctx context.Context, workerID uuid.UUID, since time.Time) (int, models.Money, error)
+	// LockWorker holds the worker's payout lock until the surrounding
+	// transaction ends, so two payouts never claim the same jobs.
+	LockWorker(ctx context.Context, workerID uuid.UUID) error
 }
 
+// releasedCashOut matches cash-outs that failed with no retry scheduled.
+// Their jobs and adjustments are available again.
+const releasedCashOut = `(kind = 'ON_DEMAND' AND status = 'FAILED' AND next_attempt_at IS NULL)`
+
 type workerPayoutRepo struct {
 	*repositories.BaseVersionedRepo[*internal_models.WorkerPayout]
 	db repositories.DB
@@ -46,9 +88,9 @@ func (r *workerPayoutRepo) GetByID(ctx context.Context, id uuid.UUID) (*internal
 func baseSelectPayout() string {
 	return `
 		SELECT
-			id, worker_id, week_start_date, week_end_date, amount_cents, status,
-			stripe_transfer_id, stripe_payout_id, job_instance_ids, last_failure_reason, retry_count,
-			last_attempt_at, next_attempt_at, created_at, updated_at, row_version
+			id, worker_id, period_start, period_end, pay_schedule_id, amount_cents, adjustment_cents, fee_cents, carried_cents,
+			kind, method, provider, status, stripe_transfer_id, stripe_payout_id, ach_batch_id, ach_trace_number, job_instance_ids,
+			last_failure_reason, retry_count, last_attempt_at, next_attempt_at, paid_at, created_at, updated_at, row_version
 		FROM worker_payouts
 	`
 }
@@ -56,9 +98,9 @@ func baseSelectPayout() string {
 func (r *workerPayoutRepo) scanPayout(row pgx.Row) (*internal_models.WorkerPayout, error) {
 	var p internal_models.WorkerPayout
 	err := row.Scan(
-		&p.ID, &p.WorkerID, &p.WeekStartDate, &p.WeekEndDate, &p.AmountCents, &p.Status,
-		&p.StripeTransferID, &p.StripePayoutID, &p.JobInstanceIDs, &p.LastFailureReason, &p.RetryCount,
-		&p.LastAttemptAt, &p.NextAttemptAt, &p.CreatedAt, &p.UpdatedAt, &p.RowVersion,
+		&p.ID, &p.WorkerID, &p.PeriodStart, &p.PeriodEnd, &p.PayScheduleID, &p.AmountCents, &p.AdjustmentCents, &p.FeeCents, &p.CarriedCents,
+		&p.Kind, &p.Method, &p.Provider, &p.Status, &p.StripeTransferID, &p.StripePayoutID, &p.ACHBatchID, &p.ACHTraceNumber, &p.JobInstanceIDs,
+		&p.LastFailureReason, &p.RetryCount, &p.LastAttemptAt, &p.NextAttemptAt, &p.PaidAt, &p.CreatedAt, &p.UpdatedAt, &p.RowVersion,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -69,25 +111,63 @@ func (r *workerPayoutRepo) scanPayout(row pgx.Row) (*internal_models.WorkerPayou
 	return &p, nil
 }
 
+// Create writes p, defaulting to a scheduled standard payout sent over
+// Stripe. A second
+// scheduled payout for the same worker and period is silently dropped.
 func (r *workerPayoutRepo) Create(ctx context.Context, p *internal_models.WorkerPayout) error {
+	if p.Kind == "" {
+		p.Kind = internal_models.PayoutKindScheduled
+	}
+	if p.Method == "" {
+		p.Method = internal_models.PayoutMethodStandard
+	}
+	if p.Provider == "" {
+		p.Provider = internal_models.PayoutProviderStripe
+	}
 	q := `
 		INSERT INTO worker_payouts (
-			id, worker_id, week_start_date, week_end_date, amount_cents, status,
-			stripe_transfer_id, stripe_payout_id, job_instance_ids, retry_count, created_at, updated_at, row_version
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 0, NOW(), NOW(), 1)
-		ON CONFLICT (worker_id, week_start_date) DO NOTHING
+			id, worker_id, period_start, period_end, pay_schedule_id, amount_cents, adjustment_cents, fee_cents, carried_cents,
+			kind, method, provider, status, stripe_transfer_id, stripe_payout_id, job_instance_ids,
+			next_attempt_at, paid_at, retry_count, created_at, updated_at, row_version
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17,
+			CASE WHEN $13 = 'PAID' THEN NOW() END, 0, NOW(), NOW(), 1
+		)
+		ON CONFLICT (worker_id, period_start) WHERE kind = 'SCHEDULED' DO NOTHING
 	`
-	_, err := r.db.Exec(ctx, q, p.ID, p.WorkerID, p.WeekStartDate, p.WeekEndDate, p.AmountCents, p.Status, p.StripeTransferID, p.StripePayoutID, p.JobInstanceIDs)
+	_, err := r.db.Exec(ctx, q, p.ID, p.WorkerID, p.PeriodStart, p.PeriodEnd, p.PayScheduleID, p.AmountCents, p.AdjustmentCents, p.FeeCents, p.CarriedCents,
+		p.Kind, p.Method, p.Provider, p.Status, p.StripeTransferID, p.StripePayoutID, p.JobInstanceIDs, p.NextAttemptAt)
 	return err
 }
 
-func (r *workerPayoutRepo) GetByWorkerAndWeek(ctx context.Context, workerID uuid.UUID, weekStartDate time.Time) (*internal_models.WorkerPayout, error) {
-	q := baseSelectPayout() + " WHERE worker_id = $1 AND week_start_date = $2"
-	row := r.db.QueryRow(ctx, q, workerID, weekStartDate)
+func (r *workerPayoutRepo) GetScheduledByPeriod(ctx context.Context, workerID uuid.UUID, periodStart time.Time) (*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + " WHERE worker_id = $1 AND period_start = $2 AND kind = 'SCHEDULED'"
+	row := r.db.QueryRow(ctx, q, workerID, periodStart)
 	return r.scanPayout(row)
 }
 
+// UpdateIfVersion writes p if its row_version still matches. A move into
+// PAID or FAILED publishes payout.paid or payout.failed in the same
+// transaction.
 func (r *workerPayoutRepo) UpdateIfVersion(ctx context.Context, p *internal_models.WorkerPayout, expectedVersion int64) (pgconn.CommandTag, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	var prevStatus internal_models.PayoutStatusType
+	err = tx.QueryRow(ctx, `SELECT status FROM worker_payouts WHERE id = $1 FOR UPDATE`, p.ID).Scan(&prevStatus)
+	if err != nil {
+		return nil, err
+	}
+
 	q := `
 		UPDATE worker_payouts SET
 			status = $1,
@@ -97,13 +177,45 @@ func (r *workerPayoutRepo) UpdateIfVersion(ctx context.Context, p *internal_mode
 			retry_count = $5,
 			last_attempt_at = $6,
 			next_attempt_at = $7,
+			amount_cents = $8,
+			job_instance_ids = $9,
+			provider = $10,
+			ach_batch_id = $11,
+			ach_trace_number = $12,
+			carried_cents = $13,
+			paid_at = CASE WHEN $1 = 'PAID' THEN COALESCE(paid_at, NOW()) END,
 			updated_at = NOW(),
 			row_version = row_version + 1
-		WHERE id = $8 AND row_version = $9
+		WHERE id = $14 AND row_version = $15
 	`
-	return r.db.Exec(ctx, q,
+	tag, err := tx.Exec(ctx, q,
 		p.Status, p.StripeTransferID, p.StripePayoutID, p.LastFailureReason, p.RetryCount,
-		p.LastAttemptAt, p.NextAttemptAt, p.ID, expectedVersion)
+		p.LastAttemptAt, p.NextAttemptAt, p.AmountCents, p.JobInstanceIDs,
+		p.Provider, p.ACHBatchID, p.ACHTraceNumber, p.CarriedCents, p.ID, expectedVersion)
+	if err != nil || tag.RowsAffected() != 1 || p.Status == prevStatus {
+		return tag, err
+	}
+
+	topic := ""
+	switch p.Status {
+	case internal_models.PayoutStatusPaid:
+		topic = models.EventPayoutPaid
+	case internal_models.PayoutStatusFailed:
+		topic = models.EventPayoutFailed
+	default:
+		return tag, nil
+	}
+	err = repositories.PublishEvent(ctx, tx, topic, p.ID, models.PayoutEvent{
+		PayoutID:       p.ID,
+		WorkerID:       p.WorkerID,
+		PeriodStart:    p.PeriodStart,
+		PeriodEnd:      p.PeriodEnd,
+		AmountCents:    p.SentCents(),
+		Status:         string(p.Status),
+		FailureReason:  p.LastFailureReason,
+		JobInstanceIDs: p.JobInstanceIDs,
+	})
+	return tag, err
 }
 
 func (r *workerPayoutRepo) UpdateWithRetry(ctx context.Context, id uuid.UUID, mutate func(*internal_models.WorkerPayout) error) error {
@@ -111,7 +223,11 @@ func (r *workerPayoutRepo) UpdateWithRetry(ctx context.Context, id uuid.UUID, mu
 }
 
 func (r *workerPayoutRepo) FindReadyForPayout(ctx context.Context) ([]*internal_models.WorkerPayout, error) {
-	q := baseSelectPayout() + " WHERE status = 'PENDING' OR (status = 'FAILED' AND next_attempt_at IS NOT NULL AND next_attempt_at <= NOW()) ORDER BY created_at"
+	q := baseSelectPayout() + `
+		WHERE (status = 'PENDING' AND (next_attempt_at IS NULL OR next_attempt_at <= NOW()))
+		   OR (status = 'FAILED' AND next_attempt_at IS NOT NULL AND next_attempt_at <= NOW())
+		ORDER BY created_at
+	`
 	rows, err := r.db.Query(ctx, q)
 	if err != nil {
 		return nil, err
@@ -130,7 +246,7 @@ func (r *workerPayoutRepo) FindReadyForPayout(ctx context.Context) ([]*internal_
 }
 
 func (r *workerPayoutRepo) FindForWorkerByDateRange(ctx context.Context, workerID uuid.UUID, startDate, endDate time.Time) ([]*internal_models.WorkerPayout, error) {
-	q := baseSelectPayout() + " WHERE worker_id = $1 AND week_start_date >= $2 AND week_start_date <= $3 ORDER BY week_start_date DESC"
+	q := baseSelectPayout() + " WHERE worker_id = $1 AND period_start >= $2 AND period_start <= $3 ORDER BY period_start DESC, created_at DESC"
 	rows, err := r.db.Query(ctx, q, workerID, startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -148,6 +264,25 @@ func (r *workerPayoutRepo) FindForWorkerByDateRange(ctx context.Context, workerI
 	return payouts, rows.Err()
 }
 
+func (r *workerPayoutRepo) FindPaidForWorker(ctx context.Context, workerID uuid.UUID, from, to time.Time) ([]*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + " WHERE worker_id = $1 AND paid_at >= $2 AND paid_at < $3 ORDER BY paid_at, created_at"
+	rows, err := r.db.Query(ctx, q, workerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*internal_models.WorkerPayout
+	for rows.Next() {
+		p, err := r.scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
 func (r *workerPayoutRepo) FindFailedPayoutsForWorkerByAccountError(ctx context.Context, workerID uuid.UUID) ([]*internal_models.WorkerPayout, error) {
 	// These reasons indicate a payout failed due to an issue with the worker's Stripe
 	// account that they can resolve. An `account.updated` or `capability.updated` webhook
@@ -160,12 +295,14 @@ func (r *workerPayoutRepo) FindFailedPayoutsForWorkerByAccountError(ctx context.
 		constants.ReasonMissingStripeID,
 		constants.ReasonAccountPayoutsDisabled,
 		constants.StripeFailureCodeAccountRestricted,
+		constants.ReasonMissingBankAccount,
 	}
 
 	q := baseSelectPayout() + `
         WHERE worker_id = $1
           AND status = 'FAILED'
           AND next_attempt_at IS NULL
+          AND kind <> 'ON_DEMAND'
           AND last_failure_reason = ANY($2)
     `
 	rows, err := r.db.Query(ctx, q, workerID, userActionableReasons)
@@ -187,7 +324,7 @@ func (r *workerPayoutRepo) FindFailedPayoutsForWorkerByAccountError(ctx context.
 
 // FindFailedByReason finds all payouts that are in a FAILED state with a specific reason.
 func (r *workerPayoutRepo) FindFailedByReason(ctx context.Context, reason string) ([]*internal_models.WorkerPayout, error) {
-	q := baseSelectPayout() + " WHERE status = 'FAILED' AND last_failure_reason = $1 ORDER BY created_at"
+	q := baseSelectPayout() + " WHERE status = 'FAILED' AND kind <> 'ON_DEMAND' AND last_failure_reason = $1 ORDER BY created_at"
 	rows, err := r.db.Query(ctx, q, reason)
 	if err != nil {
 		return nil, err
@@ -204,3 +341,141 @@ func (r *workerPayoutRepo) FindFailedByReason(ctx context.Context, reason string
 	}
 	return payouts, rows.Err()
 }
+
+func (r *workerPayoutRepo) FindCreatedBetween(ctx context.Context, from, to time.Time) ([]*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + " WHERE created_at >= $1 AND created_at < $2 ORDER BY created_at"
+	rows, err := r.db.Query(ctx, q, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*internal_models.WorkerPayout
+	for rows.Next() {
+		p, err := r.scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *workerPayoutRepo) FindUnbatchedACH(ctx context.Context) ([]*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + `
+		WHERE provider = 'ACH' AND status = 'PROCESSING' AND ach_batch_id IS NULL
+		ORDER BY created_at
+		FOR UPDATE SKIP LOCKED
+	`
+	return r.queryPayouts(ctx, q)
+}
+
+func (r *workerPayoutRepo) FindByACHBatch(ctx context.Context, batchID uuid.UUID) ([]*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + " WHERE ach_batch_id = $1 ORDER BY ach_trace_number"
+	return r.queryPayouts(ctx, q, batchID)
+}
+
+func (r *workerPayoutRepo) GetByACHTrace(ctx context.Context, traceNumber string) (*internal_models.WorkerPayout, error) {
+	q := baseSelectPayout() + " WHERE ach_trace_number = $1 ORDER BY updated_at DESC LIMIT 1"
+	return r.scanPayout(r.db.QueryRow(ctx, q, traceNumber))
+}
+
+func (r *workerPayoutRepo) queryPayouts(ctx context.Context, q string, args ...any) ([]*internal_models.WorkerPayout, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []*internal_models.WorkerPayout
+	for rows.Next() {
+		p, err := r.scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
+
+func (r *workerPayoutRepo) PaidOutJobIDs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	paid := make(map[uuid.UUID]bool)
+	if len(jobIDs) == 0 {
+		return paid, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT j.id
+		FROM worker_payouts p, unnest(p.job_instance_ids) AS j(id)
+		WHERE p.job_instance_ids && $1::uuid[]
+		  AND j.id = ANY($1)
+		  AND NOT `+releasedCashOut+`
+		UNION
+		SELECT job_instance_id
+		FROM worker_pay_adjustments
+		WHERE kind = 'LATE_JOB'
+		  AND status <> 'REJECTED'
+		  AND job_instance_id = ANY($1)
+	`, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		paid[id] = true
+	}
+	return paid, rows.Err()
+}
+
+func (r *workerPayoutRepo) PayoutIDsByJobs(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	byJob := make(map[uuid.UUID][]uuid.UUID)
+	if len(jobIDs) == 0 {
+		return byJob, nil
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT j.id, p.id
+		FROM worker_payouts p, unnest(p.job_instance_ids) AS j(id)
+		WHERE p.job_instance_ids && $1::uuid[]
+		  AND j.id = ANY($1)
+		  AND NOT `+releasedCashOut+`
+		ORDER BY p.created_at, p.id
+	`, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID, payoutID uuid.UUID
+		if err := rows.Scan(&jobID, &payoutID); err != nil {
+			return nil, err
+		}
+		byJob[jobID] = append(byJob[jobID], payoutID)
+	}
+	return byJob, rows.Err()
+}
+
+func (r *workerPayoutRepo) OnDemandSince(ctx context.Context, workerID uuid.UUID, since time.Time) (int, models.Money, error) {
+	var (
+		count int
+		gross int64
+	)
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(amount_cents + fee_cents), 0)
+		FROM worker_payouts
+		WHERE worker_id = $1
+		  AND kind = 'ON_DEMAND'
+		  AND created_at >= $2
+		  AND NOT `+releasedCashOut+`
+	`, workerID, since).Scan(&count, &gross)
+	return count, models.USD(gross), err
+}
+
+func (r *workerPayoutRepo) LockWorker(ctx context.Context, workerID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('worker_payouts:' || $1::text))`, workerID)
+	return err
+}
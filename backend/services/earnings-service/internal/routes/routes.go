@@ -3,6 +3,51 @@ package routes
 const (
 	Health          = "/health"
 	EarningsSummary = "/api/v1/earnings/summary"
+	EarningsSchedulerRuns = "/api/v1/earnings/scheduler/runs"
+	EarningsQueueDead     = "/api/v1/earnings/queue/dead"
 	EarningsStripeWebhook   = "/api/v1/earnings/stripe/webhook"
 	EarningsStripeWebhookCheck = "/api/v1/earnings/stripe/webhook/check"
+
+	// Ops pay adjustments
+	EarningsOpsAdjustments       = "/api/v1/earnings/ops/adjustments"
+	EarningsOpsAdjustmentApprove = "/api/v1/earnings/ops/adjustments/approve"
+	EarningsOpsAdjustmentReject  = "/api/v1/earnings/ops/adjustments/reject"
+
+	// Worker on-demand cash-out
+	EarningsCashOut = "/api/v1/earnings/cashout"
+
+	// Worker pay disputes
+	EarningsDisputes      = "/api/v1/earnings/disputes"
+	EarningsDisputesReply = "/api/v1/earnings/disputes/reply"
+
+	EarningsOpsDisputes            = "/api/v1/earnings/ops/disputes"
+	EarningsOpsDisputesTriage      = "/api/v1/earnings/ops/disputes/triage"
+	EarningsOpsDisputesRequestInfo = "/api/v1/earnings/ops/disputes/request-info"
+	EarningsOpsDisputesApprove     = "/api/v1/earnings/ops/disputes/approve"
+	EarningsOpsDisputesDeny        = "/api/v1/earnings/ops/disputes/deny"
+
+	// Worker statements
+	EarningsStatements       = "/api/v1/earnings/statements"
+	EarningsStatementsPayout = "/api/v1/earnings/statements/payout"
+	EarningsStatementsAnnual = "/api/v1/earnings/statements/annual"
+
+	// Ops pay schedules
+	EarningsOpsPaySchedules         = "/api/v1/earnings/ops/pay-schedules"
+	EarningsOpsPaySchedulesAssign   = "/api/v1/earnings/ops/pay-schedules/assign"
+	EarningsOpsPaySchedulesUnassign = "/api/v1/earnings/ops/pay-schedules/unassign"
+
+	// Ops payout holds
+	EarningsOpsPayoutHolds        = "/api/v1/earnings/ops/payout-holds"
+	EarningsOpsPayoutHoldsRelease = "/api/v1/earnings/ops/payout-holds/release"
+	EarningsOpsPayoutHoldsReject  = "/api/v1/earnings/ops/payout-holds/reject"
+
+	// Ops reconciliation
+	EarningsOpsReconciliation = "/api/v1/earnings/ops/reconciliation"
+
+	// Ops ACH payouts
+	EarningsOpsACHBankAccounts  = "/api/v1/earnings/ops/ach/bank-accounts"
+	EarningsOpsACHBatches       = "/api/v1/earnings/ops/ach/batches"
+	EarningsOpsACHBatchesFile   = "/api/v1/earnings/ops/ach/batches/file"
+	EarningsOpsACHBatchesSubmit = "/api/v1/earnings/ops/ach/batches/submit"
+	EarningsOpsACHReturns       = "/api/v1/earnings/ops/ach/returns"
 )
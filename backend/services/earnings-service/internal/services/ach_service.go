@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/stripe/stripe-go/v82"
+)
+
+/*──────────── provider ────────────*/
+
+type achPayoutProvider struct {
+	bankRepo internal_repositories.WorkerBankAccountRepository
+}
+
+// NewACHPayoutProvider accepts payouts for workers with a bank account on
+// file. Nothing moves on Send: the payout waits, PROCESSING, for the next
+// NACHA file ops generate with ACHService.
+func NewACHPayoutProvider(bankRepo internal_repositories.WorkerBankAccountRepository) PayoutProvider {
+	return &achPayoutProvider{bankRepo: bankRepo}
+}
+
+func (a *achPayoutProvider) Name() internal_models.PayoutProviderType {
+	return internal_models.PayoutProviderACH
+}
+
+func (a *achPayoutProvider) Send(ctx context.Context, req PayoutRequest) (*PayoutReceipt, error) {
+	acct, err := a.bankRepo.GetByWorker(ctx, req.Payout.WorkerID)
+	if err != nil {
+		return nil, err
+	}
+	if acct == nil {
+		return nil, &PayoutFailure{Reason: constants.ReasonMissingBankAccount}
+	}
+	return &PayoutReceipt{}, nil
+}
+
+// Status has nothing to ask: an ACH payout only moves on when its batch is
+// submitted or a return comes back.
+func (a *achPayoutProvider) Status(context.Context, *internal_models.WorkerPayout, *models.Worker) (*PayoutState, error) {
+	return &PayoutState{Status: internal_models.PayoutStatusProcessing}, nil
+}
+
+// achReturnReasons maps ACH return codes onto the Stripe failure codes
+// with the same meaning, so returned payouts are retried or reported to
+// the worker like failed Stripe payouts. Other codes become
+// ACHReturnReasonPrefix plus the code.
+var achReturnReasons = map[string]string{
+	"R02": string(stripe.PayoutFailureCodeAccountClosed),
+	"R03": string(stripe.PayoutFailureCodeNoAccount),
+	"R04": string(stripe.PayoutFailureCodeInvalidAccountNumber),
+	"R16": string(stripe.PayoutFailureCodeAccountFrozen),
+	"R17": string(stripe.PayoutFailureCodeCouldNotProcess),
+	"R20": string(stripe.PayoutFailureCodeBankAccountRestricted),
+	"R23": string(stripe.PayoutFailureCodeDeclined),
+}
+
+func achReturnReason(code string) string {
+	if reason, ok := achReturnReasons[code]; ok {
+		return reason
+	}
+	return constants.ACHReturnReasonPrefix + code
+}
+
+/*──────────── service ────────────*/
+
+/*
+ACHService runs the ACH side of payouts for ops: the bank accounts ACH
+payouts go to, the NACHA files that carry them and the return files that
+come back.
+
+Payouts that went over ACH wait PROCESSING until Generate puts them in a
+file for the bank. Submitting the file marks them PAID. A credit the
+worker's bank returns fails its payout again, with the return code mapped
+to the matching Stripe failure code, so it is retried or reported to the
+worker like any other failed payout.
+*/
+type ACHService struct {
+	cfg        *config.Config
+	workerRepo repositories.WorkerRepository
+	payoutRepo internal_repositories.WorkerPayoutRepository
+	bankRepo   internal_repositories.WorkerBankAccountRepository
+	batchRepo  internal_repositories.ACHBatchRepository
+	returnRepo internal_repositories.ACHReturnRepository
+	uow        *repositories.UnitOfWork
+	payoutSvc  *PayoutService
+}
+
+func NewACHService(cfg *config.Config, workerRepo repositories.WorkerRepository, payoutRepo internal_repositories.WorkerPayoutRepository, bankRepo internal_repositories.WorkerBankAccountRepository, batchRepo internal_repositories.ACHBatchRepository, returnRepo internal_repositories.ACHReturnRepository, uow *repositories.UnitOfWork, payoutSvc *PayoutService) *ACHService {
+	return &ACHService{
+		cfg:        cfg,
+		workerRepo: workerRepo,
+		payoutRepo: payoutRepo,
+		bankRepo:   bankRepo,
+		batchRepo:  batchRepo,
+		returnRepo: returnRepo,
+		uow:        uow,
+		payoutSvc:  payoutSvc,
+	}
+}
+
+func (s *ACHService) originator() (*internal_models.ACHOriginator, error) {
+	if s.cfg.LDFlag_ACHOriginator == nil {
+		return nil, internal_utils.ErrACHDisabled
+	}
+	return s.cfg.LDFlag_ACHOriginator, nil
+}
+
+// BankAccount returns the worker's bank account, or nil if they have none.
+func (s *ACHService) BankAccount(ctx context.Context, workerID uuid.UUID) (*internal_models.WorkerBankAccount, error) {
+	return s.bankRepo.GetByWorker(ctx, workerID)
+}
+
+// SaveBankAccount sets the worker's bank account and re-queues their
+// payouts that failed for want of a way to pay them.
+func (s *ACHService) SaveBankAccount(ctx context.Context, actorID uuid.UUID, req dtos.SaveBankAccountRequest) (*internal_models.WorkerBankAccount, error) {
+	if _, err := s.originator(); err != nil {
+		return nil, err
+	}
+	if !internal_utils.ValidRoutingNumber(req.RoutingNumber) {
+		return nil, fmt.Errorf("%w: routing number check digit doesn't match", internal_utils.ErrInvalidBankAccount)
+	}
+	worker, err := s.workerRepo.GetByID(ctx, req.WorkerID)
+	if err != nil {
+		return nil, fmt.Errorf("get worker: %w", err)
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("%w: worker not found", internal_utils.ErrInvalidBankAccount)
+	}
+
+	acct := &internal_models.WorkerBankAccount{
+		WorkerID:      worker.ID,
+		HolderName:    strings.TrimSpace(req.HolderName),
+		RoutingNumber: req.RoutingNumber,
+		AccountNumber: req.AccountNumber,
+		AccountType:   req.AccountType,
+		UpdatedBy:     actorID,
+	}
+	if err := s.bankRepo.Upsert(ctx, acct); err != nil {
+		return nil, fmt.Errorf("save bank account: %w", err)
+	}
+	utils.Logger.Infof("Ops %s set the ACH bank account for worker %s (ending %s)", actorID, worker.ID, acct.AccountLast4)
+
+	err = s.payoutSvc.requeueAccountFailures(ctx, worker.ID, func(p *internal_models.WorkerPayout) bool {
+		return p.Provider == internal_models.PayoutProviderACH ||
+			(p.LastFailureReason != nil && fallbackReasons[*p.LastFailureReason])
+	})
+	if err != nil {
+		// The account is saved; the payouts go out on the next account
+		// update or by hand.
+		utils.Logger.WithError(err).Errorf("Failed to re-queue payouts for worker %s after a bank account change", worker.ID)
+	}
+	return acct, nil
+}
+
+// Generate puts every ACH payout waiting for a file into a new NACHA file.
+// Payouts whose worker has since lost their bank account are failed
+// instead. It returns ErrNothingToBatch when no payout can go in a file.
+func (s *ACHService) Generate(ctx context.Context, actorID uuid.UUID) (*internal_models.ACHBatch, error) {
+	orig, err := s.originator()
+	if err != nil {
+		return nil, err
+	}
+	loc, _ := time.LoadLocation(constants.BusinessTimezone)
+
+	var (
+		batch     *internal_models.ACHBatch
+		unpayable []*internal_models.WorkerPayout
+	)
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		batch, unpayable = nil, nil
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		batches := internal_repositories.NewACHBatchRepository(w, s.cfg.DBEncryptionKey)
+		accounts := internal_repositories.NewWorkerBankAccountRepository(w, s.cfg.DBEncryptionKey)
+
+		pending, err := payouts.FindUnbatchedACH(ctx)
+		if err != nil {
+			return fmt.Errorf("find unbatched ACH payouts: %w", err)
+		}
+		var (
+			batched []*internal_models.WorkerPayout
+			entries []internal_utils.ACHEntry
+			total   int64
+		)
+		for _, p := range pending {
+			acct, err := accounts.GetByWorker(ctx, p.WorkerID)
+			if err != nil {
+				return fmt.Errorf("get bank account for worker %s: %w", p.WorkerID, err)
+			}
+			if acct == nil {
+				unpayable = append(unpayable, p)
+				continue
+			}
+			batched = append(batched, p)
+			entries = append(entries, internal_utils.ACHEntry{
+				RoutingNumber: acct.RoutingNumber,
+				AccountNumber: acct.AccountNumber,
+				Savings:       acct.AccountType == internal_models.BankAccountSavings,
+				AmountCents:   p.AmountCents,
+				IndividualID:  strings.ReplaceAll(p.WorkerID.String(), "-", "")[:15],
+				Name:          acct.HolderName,
+			})
+			total += p.AmountCents
+		}
+		if len(entries) == 0 {
+			return internal_utils.ErrNothingToBatch
+		}
+
+		seqs, err := batches.NextTraceSequences(ctx, len(entries))
+		if err != nil {
+			return fmt.Errorf("take trace numbers: %w", err)
+		}
+		for i := range entries {
+			entries[i].TraceNumber = internal_utils.ACHTraceNumber(orig.ODFIRoutingNumber, seqs[i])
+		}
+
+		now := time.Now().In(loc)
+		dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		madeToday, err := batches.CountCreatedSince(ctx, dayStart)
+		if err != nil {
+			return fmt.Errorf("count today's batches: %w", err)
+		}
+		const modifiers = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+		modifier := modifiers[madeToday%len(modifiers)]
+
+		file := &internal_utils.ACHFile{
+			ImmediateDestination:     orig.ODFIRoutingNumber,
+			ImmediateDestinationName: orig.ODFIName,
+			ImmediateOrigin:          orig.OriginID,
+			ImmediateOriginName:      orig.OriginName,
+			FileIDModifier:           modifier,
+			CompanyName:              orig.CompanyName,
+			CompanyID:                orig.CompanyID,
+			EntryDescription:         orig.EntryDescription,
+			Created:                  now,
+			EffectiveDate:            achEffectiveDate(dayStart, orig.EffectiveDays),
+			Entries:                  entries,
+		}
+		data, err := file.Bytes()
+		if err != nil {
+			return fmt.Errorf("write NACHA file: %w", err)
+		}
+
+		batch = &internal_models.ACHBatch{
+			ID:            uuid.New(),
+			FileName:      fmt.Sprintf("poof-ach-%s-%c.ach", now.Format("20060102"), modifier),
+			Status:        internal_models.ACHBatchGenerated,
+			EntryCount:    len(entries),
+			TotalCents:    total,
+			EffectiveDate: file.EffectiveDate,
+			File:          data,
+			CreatedBy:     actorID,
+		}
+		if err := batches.Create(ctx, batch); err != nil {
+			return fmt.Errorf("create ACH batch: %w", err)
+		}
+		for i, p := range batched {
+			trace := entries[i].TraceNumber
+			err := payouts.UpdateWithRetry(ctx, p.ID, func(pp *internal_models.WorkerPayout) error {
+				pp.ACHBatchID, pp.ACHTraceNumber = &batch.ID, &trace
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("add payout %s to batch: %w", p.ID, err)
+			}
+		}
+		return nil
+	})
+
+	for _, p := range unpayable {
+		s.payoutSvc.handleFailure(ctx, p, constants.ReasonMissingBankAccount, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s generated ACH batch %s: %d entries, %s", actorID, batch.ID, batch.EntryCount, models.USD(batch.TotalCents))
+	return batch, nil
+}
+
+// achEffectiveDate is the weekday days business days after day, or day
+// itself for 0 if it is a weekday. Bank holidays are left to the bank,
+// which settles on the next banking day.
+func achEffectiveDate(day time.Time, days int) time.Time {
+	weekend := func(t time.Time) bool { return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday }
+	for weekend(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	for ; days > 0; days-- {
+		day = day.AddDate(0, 0, 1)
+		for weekend(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return day
+}
+
+// Batch returns the batch with its file.
+func (s *ACHService) Batch(ctx context.Context, id uuid.UUID) (*internal_models.ACHBatch, error) {
+	b, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, internal_utils.ErrACHBatchNotFound
+	}
+	return b, nil
+}
+
+// Batches returns the latest batches, newest first.
+func (s *ACHService) Batches(ctx context.Context) ([]*internal_models.ACHBatch, error) {
+	return s.batchRepo.List(ctx, constants.ACHBatchListLimit)
+}
+
+// Submit records that the batch's file was uploaded to the bank and marks
+// its payouts PAID. Returns imported later fail them again.
+func (s *ACHService) Submit(ctx context.Context, actorID, id uuid.UUID) (*internal_models.ACHBatch, error) {
+	var batch *internal_models.ACHBatch
+	err := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		batches := internal_repositories.NewACHBatchRepository(w, s.cfg.DBEncryptionKey)
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+
+		var err error
+		if batch, err = batches.MarkSubmitted(ctx, id, actorID); err != nil {
+			return fmt.Errorf("mark batch submitted: %w", err)
+		}
+		if batch == nil {
+			existing, err := batches.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return internal_utils.ErrACHBatchNotFound
+			}
+			return internal_utils.ErrACHBatchSubmitted
+		}
+
+		inBatch, err := payouts.FindByACHBatch(ctx, id)
+		if err != nil {
+			return fmt.Errorf("find batch payouts: %w", err)
+		}
+		for _, p := range inBatch {
+			err := payouts.UpdateWithRetry(ctx, p.ID, func(pp *internal_models.WorkerPayout) error {
+				if pp.Status == internal_models.PayoutStatusProcessing {
+					pp.Status = internal_models.PayoutStatusPaid
+					pp.LastFailureReason = nil
+					pp.NextAttemptAt = nil
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("mark payout %s paid: %w", p.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s submitted ACH batch %s", actorID, id)
+	return batch, nil
+}
+
+// Returns returns the latest imported returns, newest first.
+func (s *ACHService) Returns(ctx context.Context) ([]*internal_models.ACHReturn, error) {
+	return s.returnRepo.List(ctx, constants.ACHBatchListLimit)
+}
+
+// ImportReturns reads a return file from the bank and fails the payouts
+// whose credits came back. Returns imported before are skipped, so the
+// same file can be imported twice.
+func (s *ACHService) ImportReturns(ctx context.Context, actorID uuid.UUID, data []byte) (*dtos.ACHReturnImportResponse, error) {
+	entries, err := internal_utils.ParseACHReturns(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_utils.ErrInvalidReturnFile, err)
+	}
+
+	resp := &dtos.ACHReturnImportResponse{Returns: []*internal_models.ACHReturn{}}
+	for _, e := range entries {
+		p, err := s.payoutRepo.GetByACHTrace(ctx, e.TraceNumber)
+		if err != nil {
+			return nil, fmt.Errorf("find payout for trace %s: %w", e.TraceNumber, err)
+		}
+		ret := &internal_models.ACHReturn{
+			ID:            uuid.New(),
+			TraceNumber:   e.TraceNumber,
+			ReturnCode:    e.ReturnCode,
+			FailureReason: achReturnReason(e.ReturnCode),
+			AmountCents:   e.AmountCents,
+			ImportedBy:    actorID,
+			ImportedAt:    time.Now().UTC(),
+		}
+		if p != nil {
+			ret.PayoutID, ret.BatchID = &p.ID, p.ACHBatchID
+		}
+		created, err := s.returnRepo.Create(ctx, ret)
+		if err != nil {
+			return nil, fmt.Errorf("record return for trace %s: %w", e.TraceNumber, err)
+		}
+		if !created {
+			resp.Duplicates++
+			continue
+		}
+		resp.Returns = append(resp.Returns, ret)
+
+		switch {
+		case p == nil:
+			resp.Unmatched++
+			utils.Logger.Warnf("ACH return %s for trace %s matches no payout", e.ReturnCode, e.TraceNumber)
+		case p.Status == internal_models.PayoutStatusPaid || p.Status == internal_models.PayoutStatusProcessing:
+			resp.Failed++
+			s.payoutSvc.handleFailure(ctx, p, ret.FailureReason, nil)
+		default:
+			utils.Logger.Infof("ACH return %s for payout %s ignored; the payout is %s", e.ReturnCode, p.ID, p.Status)
+		}
+	}
+	utils.Logger.Infof("Ops %s imported %d ACH returns: %d failed, %d unmatched, %d duplicate",
+		actorID, len(entries), resp.Failed, resp.Unmatched, resp.Duplicates)
+	return resp, nil
+}
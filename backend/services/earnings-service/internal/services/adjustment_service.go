@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+const maxAdjustmentListLimit = 200
+
+/*
+AdjustmentService manages ops pay adjustments: bonuses, corrections and
+clawbacks that aren't tied to a job's pay ledger. One ops user creates an
+adjustment and another approves or rejects it; approved adjustments are
+folded into the worker's next payout by AggregateAndCreatePayouts.
+*/
+type AdjustmentService struct {
+	workerRepo     repositories.WorkerRepository
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository
+}
+
+func NewAdjustmentService(workerRepo repositories.WorkerRepository, adjustmentRepo internal_repositories.WorkerAdjustmentRepository) *AdjustmentService {
+	return &AdjustmentService{workerRepo: workerRepo, adjustmentRepo: adjustmentRepo}
+}
+
+// Create records a pending adjustment for a worker.
+func (s *AdjustmentService) Create(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.CreateAdjustmentRequest,
+) (*internal_models.WorkerAdjustment, error) {
+	a := &internal_models.WorkerAdjustment{
+		WorkerID:        req.WorkerID,
+		Kind:            req.Kind,
+		Amount:          models.MoneyFromDollars(req.Amount),
+		Reason:          req.Reason,
+		JobInstanceID:   req.JobInstanceID,
+		RelatedPayoutID: req.RelatedPayoutID,
+		Status:          internal_models.AdjustmentStatusPendingApproval,
+		CreatedBy:       &actorID,
+	}
+	if a.Kind == internal_models.AdjustmentKindCarryForward || a.Kind == internal_models.AdjustmentKindLateJob || !a.Kind.ValidAmount(a.Amount) {
+		return nil, fmt.Errorf("%w: bonuses must be positive, clawbacks negative and corrections non-zero", internal_utils.ErrInvalidAdjustment)
+	}
+
+	worker, err := s.workerRepo.GetByID(ctx, req.WorkerID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("%w: worker not found", internal_utils.ErrInvalidAdjustment)
+	}
+
+	if err := s.adjustmentRepo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s requested %s of %s for worker %s", actorID, a.Kind, a.Amount, a.WorkerID)
+	return a, nil
+}
+
+// Approve marks a pending adjustment to be paid with the worker's next
+// payout.
+func (s *AdjustmentService) Approve(ctx context.Context, actorID uuid.UUID, req dtos.DecideAdjustmentRequest) (*internal_models.WorkerAdjustment, error) {
+	return s.decide(ctx, actorID, req, internal_models.AdjustmentStatusApproved)
+}
+
+// Reject closes a pending adjustment without paying it.
+func (s *AdjustmentService) Reject(ctx context.Context, actorID uuid.UUID, req dtos.DecideAdjustmentRequest) (*internal_models.WorkerAdjustment, error) {
+	return s.decide(ctx, actorID, req, internal_models.AdjustmentStatusRejected)
+}
+
+func (s *AdjustmentService) decide(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.DecideAdjustmentRequest,
+	status internal_models.AdjustmentStatusType,
+) (*internal_models.WorkerAdjustment, error) {
+	a, err := s.adjustmentRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, internal_utils.ErrAdjustmentNotFound
+	}
+	if a.CreatedBy != nil && *a.CreatedBy == actorID {
+		return nil, internal_utils.ErrSelfApproval
+	}
+
+	decided, err := s.adjustmentRepo.Decide(ctx, a.ID, status, actorID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	if decided == nil {
+		return nil, internal_utils.ErrAdjustmentDecided
+	}
+	utils.Logger.Infof("Ops %s moved adjustment %s (%s of %s for worker %s) to %s", actorID, a.ID, a.Kind, a.Amount, a.WorkerID, status)
+	return decided, nil
+}
+
+// List returns adjustments newest first, optionally for one worker and
+// status.
+func (s *AdjustmentService) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	status internal_models.AdjustmentStatusType,
+	limit int,
+) (*dtos.AdjustmentListResponse, error) {
+	if limit <= 0 || limit > maxAdjustmentListLimit {
+		limit = maxAdjustmentListLimit
+	}
+	list, err := s.adjustmentRepo.List(ctx, workerID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []*internal_models.WorkerAdjustment{}
+	}
+	return &dtos.AdjustmentListResponse{Adjustments: list}, nil
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+CashOutService lets a worker take their available earnings between
+scheduled payouts, under the configured CashOutPolicy.
+
+Available earnings are the worker's completed jobs past the hold period and
+not yet in any payout, plus their approved adjustments, so a pending
+clawback is recovered before anything is cashed out. Jobs are taken oldest
+first while they fit in what is left of today's limit. The cash-out is
+written as an ON_DEMAND payout, less the method's fee, and queued straight
+away on the same processing path as scheduled payouts. It is filed under
+the worker's current pay period.
+*/
+type CashOutService struct {
+	cfg            *config.Config
+	workerRepo     repositories.WorkerRepository
+	jobInstRepo    repositories.JobInstanceRepository
+	payItemRepo    repositories.JobPayItemRepository
+	payoutRepo     internal_repositories.WorkerPayoutRepository
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository
+	uow            *repositories.UnitOfWork
+	payoutSvc      *PayoutService
+}
+
+func NewCashOutService(cfg *config.Config, workerRepo repositories.WorkerRepository, jobInstRepo repositories.JobInstanceRepository, payItemRepo repositories.JobPayItemRepository, payoutRepo internal_repositories.WorkerPayoutRepository, adjustmentRepo internal_repositories.WorkerAdjustmentRepository, uow *repositories.UnitOfWork, payoutSvc *PayoutService) *CashOutService {
+	return &CashOutService{
+		cfg:            cfg,
+		workerRepo:     workerRepo,
+		jobInstRepo:    jobInstRepo,
+		payItemRepo:    payItemRepo,
+		payoutRepo:     payoutRepo,
+		adjustmentRepo: adjustmentRepo,
+		uow:            uow,
+		payoutSvc:      payoutSvc,
+	}
+}
+
+// cashOut is what a worker could take right now.
+type cashOut struct {
+	jobIDs       []uuid.UUID
+	jobs         models.Money
+	adjustments  []uuid.UUID
+	adjTotal     models.Money
+	leftOver     int // available jobs that didn't fit in today's limit
+	limitLeft    models.Money
+	cashOutsLeft int
+}
+
+func (c *cashOut) gross() models.Money {
+	return c.jobs.Add(c.adjTotal)
+}
+
+// Quote reports what the worker could cash out now and what each method
+// would cost.
+func (s *CashOutService) Quote(ctx context.Context, workerID uuid.UUID) (*dtos.CashOutQuoteResponse, error) {
+	worker, err := s.workerRepo.GetByID(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("%w: worker not found", internal_utils.ErrCashOutIneligible)
+	}
+
+	now := time.Now().UTC()
+	c, err := s.available(ctx, s.payoutRepo, s.adjustmentRepo, worker, now)
+	if err != nil {
+		return nil, err
+	}
+	policy := s.cfg.LDFlag_CashOutPolicy
+	gross := c.gross()
+	resp := &dtos.CashOutQuoteResponse{
+		Eligible:       true,
+		Available:      models.USD(max(gross.Cents, 0)),
+		JobCount:       len(c.jobIDs),
+		Adjustments:    c.adjTotal,
+		StandardFee:    policy.Fee(internal_models.PayoutMethodStandard, gross),
+		InstantFee:     policy.Fee(internal_models.PayoutMethodInstant, gross),
+		DailyLimitLeft: c.limitLeft,
+		CashOutsLeft:   c.cashOutsLeft,
+	}
+	if _, err := s.check(worker, c, internal_models.PayoutMethodStandard, now); err != nil {
+		if !isCashOutRefusal(err) {
+			return nil, err
+		}
+		resp.Eligible = false
+		resp.IneligibleReason = err.Error()
+	}
+	return resp, nil
+}
+
+// CashOut pays the worker's available earnings by method as an on-demand
+// payout.
+func (s *CashOutService) CashOut(ctx context.Context, workerID uuid.UUID, method internal_models.PayoutMethodType) (*dtos.CashOutResponse, error) {
+	worker, err := s.workerRepo.GetByID(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("%w: worker not found", internal_utils.ErrCashOutIneligible)
+	}
+	if err := s.eligibility(worker, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	sch, err := s.payoutSvc.schedules.ForWorker(ctx, worker.ID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve pay schedule: %w", err)
+	}
+
+	var payout *internal_models.WorkerPayout
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		adjustments := internal_repositories.NewWorkerAdjustmentRepository(w)
+		if err := payouts.LockWorker(ctx, worker.ID); err != nil {
+			return fmt.Errorf("lock worker payouts: %w", err)
+		}
+
+		now := time.Now().UTC()
+		c, err := s.available(ctx, payouts, adjustments, worker, now)
+		if err != nil {
+			return err
+		}
+		fee, err := s.check(worker, c, method, now)
+		if err != nil {
+			return err
+		}
+
+		periodStart, periodEnd := sch.PeriodAt(now)
+		payout = &internal_models.WorkerPayout{
+			ID:              uuid.New(),
+			WorkerID:        worker.ID,
+			PeriodStart:     periodStart,
+			PeriodEnd:       periodEnd,
+			PayScheduleID:   payScheduleRef(sch),
+			AmountCents:     c.gross().Sub(fee).Cents,
+			AdjustmentCents: c.adjTotal.Cents,
+			FeeCents:        fee.Cents,
+			Kind:            internal_models.PayoutKindOnDemand,
+			Method:          method,
+			Status:          internal_models.PayoutStatusPending,
+			JobInstanceIDs:  c.jobIDs,
+		}
+		if err := payouts.Create(ctx, payout); err != nil {
+			return err
+		}
+		if err := adjustments.MarkApplied(ctx, c.adjustments, payout.ID); err != nil {
+			return fmt.Errorf("apply adjustments: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.payoutSvc.queuePayout(ctx, payout.ID, 0, time.Now().UTC())
+	utils.Logger.Infof("Worker %s cashed out %s by %s (fee %s, %d jobs) as payout %s",
+		worker.ID, models.USD(payout.AmountCents), method, models.USD(payout.FeeCents), len(payout.JobInstanceIDs), payout.ID)
+	return &dtos.CashOutResponse{
+		PayoutID: payout.ID,
+		Method:   payout.Method,
+		Amount:   models.USD(payout.AmountCents),
+		Fee:      models.USD(payout.FeeCents),
+		JobCount: len(payout.JobInstanceIDs),
+		Status:   payout.Status,
+	}, nil
+}
+
+// eligibility checks the policy's standing rules for the worker.
+func (s *CashOutService) eligibility(w *models.Worker, now time.Time) error {
+	policy := s.cfg.LDFlag_CashOutPolicy
+	switch {
+	case !policy.Enabled:
+		return fmt.Errorf("%w: cash-out is not available", internal_utils.ErrCashOutIneligible)
+	case w.IsBanned:
+		return fmt.Errorf("%w: account is not in good standing", internal_utils.ErrCashOutIneligible)
+	case w.StripeConnectAccountID == nil || *w.StripeConnectAccountID == "":
+		return fmt.Errorf("%w: finish setting up payouts first", internal_utils.ErrCashOutIneligible)
+	case now.Before(w.CreatedAt.AddDate(0, 0, policy.MinTenureDays)):
+		return fmt.Errorf("%w: available after %d days on the platform", internal_utils.ErrCashOutIneligible, policy.MinTenureDays)
+	case w.ReliabilityScore < policy.MinReliabilityScore:
+		return fmt.Errorf("%w: needs a reliability score of at least %d", internal_utils.ErrCashOutIneligible, policy.MinReliabilityScore)
+	}
+	return nil
+}
+
+// check decides whether c may be cashed out by method and returns the fee.
+func (s *CashOutService) check(w *models.Worker, c *cashOut, method internal_models.PayoutMethodType, now time.Time) (models.Money, error) {
+	if err := s.eligibility(w, now); err != nil {
+		return models.Money{}, err
+	}
+	policy := s.cfg.LDFlag_CashOutPolicy
+	gross := c.gross()
+	switch {
+	case c.cashOutsLeft <= 0:
+		return models.Money{}, fmt.Errorf("%w: %d cash-out(s) a day", internal_utils.ErrCashOutLimit, policy.MaxCashOutsPerDay)
+	case gross.Cents > c.limitLeft.Cents || (len(c.jobIDs) == 0 && c.leftOver > 0):
+		return models.Money{}, fmt.Errorf("%w: %s left today", internal_utils.ErrCashOutLimit, c.limitLeft)
+	}
+	fee := policy.Fee(method, gross)
+	if gross.Sub(fee).Cents < constants.MinimumPayoutAmountCents {
+		return models.Money{}, internal_utils.ErrNothingToCashOut
+	}
+	return fee, nil
+}
+
+// available works out what the worker could cash out at now, reading
+// payouts and adjustments through the given repositories so CashOut can do
+// it under the worker's payout lock.
+func (s *CashOutService) available(
+	ctx context.Context,
+	payouts internal_repositories.WorkerPayoutRepository,
+	adjustments internal_repositories.WorkerAdjustmentRepository,
+	worker *models.Worker,
+	now time.Time,
+) (*cashOut, error) {
+	policy := s.cfg.LDFlag_CashOutPolicy
+	loc, err := time.LoadLocation(constants.BusinessTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load business timezone: %w", err)
+	}
+	local := now.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	count, used, err := payouts.OnDemandSince(ctx, worker.ID, dayStart)
+	if err != nil {
+		return nil, fmt.Errorf("total today's cash-outs: %w", err)
+	}
+	c := &cashOut{
+		limitLeft:    models.USD(max(policy.DailyLimitCents-used.Cents, 0)),
+		cashOutsLeft: max(policy.MaxCashOutsPerDay-count, 0),
+	}
+
+	statuses := []models.InstanceStatusType{models.InstanceStatusCompleted}
+	jobs, err := s.jobInstRepo.ListInstancesByDateRange(ctx, &worker.ID, statuses, local.AddDate(0, 0, -constants.CashOutLookbackDays), local)
+	if err != nil {
+		return nil, fmt.Errorf("list completed jobs: %w", err)
+	}
+	cutoff := now.Add(-time.Duration(policy.HoldHours) * time.Hour)
+	var done []*models.JobInstance
+	for _, j := range jobs {
+		if !jobDoneAt(j).After(cutoff) {
+			done = append(done, j)
+		}
+	}
+	holds, err := s.payoutSvc.holds.Screen(ctx, done)
+	if err != nil {
+		return nil, fmt.Errorf("screen jobs for payout holds: %w", err)
+	}
+	var ready []*models.JobInstance
+	for _, j := range done {
+		if !holds[j.ID].Blocks() {
+			ready = append(ready, j)
+		}
+	}
+	sort.SliceStable(ready, func(i, k int) bool { return jobDoneAt(ready[i]).Before(jobDoneAt(ready[k])) })
+
+	ids := make([]uuid.UUID, len(ready))
+	for i, j := range ready {
+		ids[i] = j.ID
+	}
+	paidOut, err := payouts.PaidOutJobIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("check for paid-out jobs: %w", err)
+	}
+	pay, err := s.payItemRepo.TotalsByInstances(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("total pay ledgers: %w", err)
+	}
+
+	pending, err := adjustments.ListApprovedForWorker(ctx, worker.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list approved adjustments: %w", err)
+	}
+	c.adjTotal = models.USD(0)
+	for _, a := range pending {
+		c.adjTotal = c.adjTotal.Add(a.Amount)
+		c.adjustments = append(c.adjustments, a.ID)
+	}
+
+	c.jobs = models.USD(0)
+	for _, id := range ids {
+		if paidOut[id] {
+			continue
+		}
+		if c.leftOver > 0 || c.gross().Add(pay[id]).Cents > c.limitLeft.Cents {
+			c.leftOver++
+			continue
+		}
+		c.jobs = c.jobs.Add(pay[id])
+		c.jobIDs = append(c.jobIDs, id)
+	}
+	return c, nil
+}
+
+// jobDoneAt is when a completed job finished: check-out, or its last update
+// if it was completed without one.
+func jobDoneAt(j *models.JobInstance) time.Time {
+	if j.CheckOutAt != nil {
+		return *j.CheckOutAt
+	}
+	return j.UpdatedAt
+}
+
+func isCashOutRefusal(err error) bool {
+	return errors.Is(err, internal_utils.ErrCashOutIneligible) ||
+		errors.Is(err, internal_utils.ErrCashOutLimit) ||
+		errors.Is(err, internal_utils.ErrNothingToCashOut)
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func testCashOutService() *CashOutService {
+	return &CashOutService{cfg: &config.Config{LDFlag_CashOutPolicy: internal_models.DefaultCashOutPolicy()}}
+}
+
+func testCashOutWorker(now time.Time) *models.Worker {
+	acct := testAccount
+	return &models.Worker{
+		ID:                     testWorkerID,
+		StripeConnectAccountID: &acct,
+		ReliabilityScore:       90,
+		CreatedAt:              now.AddDate(0, 0, -30),
+	}
+}
+
+func TestCashOutEligibility(t *testing.T) {
+	now := time.Now().UTC()
+	s := testCashOutService()
+	if err := s.eligibility(testCashOutWorker(now), now); err != nil {
+		t.Fatalf("expected the worker to be eligible, got %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		policy func(*internal_models.CashOutPolicy)
+		worker func(*models.Worker)
+	}{
+		"disabled":     {policy: func(p *internal_models.CashOutPolicy) { p.Enabled = false }},
+		"banned":       {worker: func(w *models.Worker) { w.IsBanned = true }},
+		"no stripe":    {worker: func(w *models.Worker) { w.StripeConnectAccountID = nil }},
+		"empty stripe": {worker: func(w *models.Worker) { empty := ""; w.StripeConnectAccountID = &empty }},
+		"new worker":   {worker: func(w *models.Worker) { w.CreatedAt = now.AddDate(0, 0, -13) }},
+		"low score":    {worker: func(w *models.Worker) { w.ReliabilityScore = 79 }},
+	} {
+		s := testCashOutService()
+		w := testCashOutWorker(now)
+		if tc.policy != nil {
+			tc.policy(s.cfg.LDFlag_CashOutPolicy)
+		}
+		if tc.worker != nil {
+			tc.worker(w)
+		}
+		if err := s.eligibility(w, now); !errors.Is(err, internal_utils.ErrCashOutIneligible) {
+			t.Errorf("%s: expected ErrCashOutIneligible, got %v", name, err)
+		}
+	}
+}
+
+func TestCashOutCheckLimits(t *testing.T) {
+	now := time.Now().UTC()
+	s := testCashOutService()
+	w := testCashOutWorker(now)
+	available := func(jobCents, adjCents, limitCents int64, left int) *cashOut {
+		c := &cashOut{jobs: models.USD(jobCents), adjTotal: models.USD(adjCents), limitLeft: models.USD(limitCents), cashOutsLeft: left}
+		if jobCents > 0 {
+			c.jobIDs = []uuid.UUID{uuid.New()}
+		}
+		return c
+	}
+
+	if _, err := s.check(w, available(4000, 0, 50000, 1), internal_models.PayoutMethodStandard, now); err != nil {
+		t.Fatalf("expected the cash-out to pass, got %v", err)
+	}
+
+	// Today's count is used up.
+	if _, err := s.check(w, available(4000, 0, 50000, 0), internal_models.PayoutMethodStandard, now); !errors.Is(err, internal_utils.ErrCashOutLimit) {
+		t.Errorf("no cash-outs left: got %v", err)
+	}
+	// More than is left of today's limit.
+	if _, err := s.check(w, available(4000, 0, 3000, 1), internal_models.PayoutMethodStandard, now); !errors.Is(err, internal_utils.ErrCashOutLimit) {
+		t.Errorf("over the daily limit: got %v", err)
+	}
+	// Every available job was too big for what is left today.
+	c := available(0, 0, 1000, 1)
+	c.leftOver = 2
+	if _, err := s.check(w, c, internal_models.PayoutMethodStandard, now); !errors.Is(err, internal_utils.ErrCashOutLimit) {
+		t.Errorf("no job fits the limit: got %v", err)
+	}
+	// A pending clawback eats the earnings.
+	if _, err := s.check(w, available(4000, -3980, 50000, 1), internal_models.PayoutMethodStandard, now); !errors.Is(err, internal_utils.ErrNothingToCashOut) {
+		t.Errorf("below the minimum: got %v", err)
+	}
+	// The instant fee takes it below the minimum.
+	if _, err := s.check(w, available(90, 0, 50000, 1), internal_models.PayoutMethodInstant, now); !errors.Is(err, internal_utils.ErrNothingToCashOut) {
+		t.Errorf("below the minimum after the fee: got %v", err)
+	}
+}
+
+func TestCashOutFees(t *testing.T) {
+	now := time.Now().UTC()
+	s := testCashOutService()
+	s.cfg.LDFlag_CashOutPolicy.StandardFeeCents = 25
+	w := testCashOutWorker(now)
+
+	for _, tc := range []struct {
+		method internal_models.PayoutMethodType
+		gross  int64
+		fee    int64
+	}{
+		{internal_models.PayoutMethodStandard, 4000, 25},
+		{internal_models.PayoutMethodStandard, 50000, 25},
+		{internal_models.PayoutMethodInstant, 10000, 150}, // 1.5%
+		{internal_models.PayoutMethodInstant, 12345, 185}, // rounded down
+		{internal_models.PayoutMethodInstant, 2000, 50},   // the minimum
+	} {
+		c := &cashOut{jobs: models.USD(tc.gross), adjTotal: models.USD(0), jobIDs: []uuid.UUID{uuid.New()}, limitLeft: models.USD(50000), cashOutsLeft: 1}
+		fee, err := s.check(w, c, tc.method, now)
+		if err != nil {
+			t.Fatalf("%s %d: unexpected error %v", tc.method, tc.gross, err)
+		}
+		if fee.Cents != tc.fee {
+			t.Errorf("%s %d: expected a fee of %d, got %d", tc.method, tc.gross, tc.fee, fee.Cents)
+		}
+	}
+}
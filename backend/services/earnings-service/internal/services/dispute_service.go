@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/sendgrid/sendgrid-go"
+)
+
+const maxDisputeListLimit = 200
+
+// unresolvedDisputeStatuses are the statuses staff can still act in.
+var unresolvedDisputeStatuses = []internal_models.DisputeStatusType{
+	internal_models.DisputeStatusOpen,
+	internal_models.DisputeStatusUnderReview,
+	internal_models.DisputeStatusNeedsInfo,
+}
+
+/*
+DisputeService runs worker pay disputes. A worker opens one against a job
+(underpaid, or canceled unfairly) or a payout week. Staff triage it, may ask
+the worker for more information, and approve or deny it. Approving creates
+a CORRECTION adjustment for the approved amount, which a second ops user
+approves like any other before it is paid. The worker is emailed whenever
+staff move the dispute.
+*/
+type DisputeService struct {
+	cfg         *config.Config
+	workerRepo  repositories.WorkerRepository
+	payoutRepo  internal_repositories.WorkerPayoutRepository
+	disputeRepo internal_repositories.WorkerDisputeRepository
+	uow         *repositories.UnitOfWork
+	notifier    *utils.Notifier
+}
+
+func NewDisputeService(cfg *config.Config, workerRepo repositories.WorkerRepository, payoutRepo internal_repositories.WorkerPayoutRepository, disputeRepo internal_repositories.WorkerDisputeRepository, uow *repositories.UnitOfWork, queue *utils.JobQueue) *DisputeService {
+	return &DisputeService{
+		cfg:         cfg,
+		workerRepo:  workerRepo,
+		payoutRepo:  payoutRepo,
+		disputeRepo: disputeRepo,
+		uow:         uow,
+		notifier:    utils.NewNotifier(queue, sendgrid.NewSendClient(cfg.SendgridAPIKey), nil),
+	}
+}
+
+func disputeDTO(d *internal_models.WorkerDispute) dtos.DisputeDTO {
+	return dtos.DisputeDTO{
+		ID:              d.ID,
+		JobInstanceID:   d.JobInstanceID,
+		PayoutID:        d.PayoutID,
+		Category:        d.Category,
+		Reason:          d.Reason,
+		Evidence:        d.Evidence,
+		RequestedAmount: d.RequestedAmount,
+		Status:          d.Status,
+		ResolutionNote:  d.ResolutionNote,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+	}
+}
+
+// Open records a worker's dispute after checking the job or payout is
+// theirs.
+func (s *DisputeService) Open(ctx context.Context, workerID uuid.UUID, req dtos.OpenDisputeRequest) (*dtos.DisputeDTO, error) {
+	if req.JobInstanceID == nil && req.PayoutID == nil {
+		return nil, fmt.Errorf("%w: name a job_instance_id or payout_id", internal_utils.ErrInvalidDispute)
+	}
+	if req.JobInstanceID != nil {
+		ok, err := s.disputeRepo.WorkerHadJob(ctx, workerID, *req.JobInstanceID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: job not found", internal_utils.ErrInvalidDispute)
+		}
+	}
+	if req.PayoutID != nil {
+		p, err := s.payoutRepo.GetByID(ctx, *req.PayoutID)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil || p.WorkerID != workerID {
+			return nil, fmt.Errorf("%w: payout not found", internal_utils.ErrInvalidDispute)
+		}
+	}
+
+	d := &internal_models.WorkerDispute{
+		WorkerID:      workerID,
+		JobInstanceID: req.JobInstanceID,
+		PayoutID:      req.PayoutID,
+		Category:      req.Category,
+		Reason:        req.Reason,
+		Evidence:      req.Evidence,
+		Status:        internal_models.DisputeStatusOpen,
+	}
+	if req.RequestedAmount != nil {
+		m := models.MoneyFromDollars(*req.RequestedAmount)
+		d.RequestedAmount = &m
+	}
+	if err := s.disputeRepo.Create(ctx, d); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, internal_utils.ErrDisputeExists
+		}
+		return nil, err
+	}
+	utils.Logger.Infof("Worker %s opened %s dispute %s", workerID, d.Category, d.ID)
+	dto := disputeDTO(d)
+	return &dto, nil
+}
+
+// ListForWorker returns the worker's disputes newest first.
+func (s *DisputeService) ListForWorker(ctx context.Context, workerID uuid.UUID) (*dtos.DisputeListResponse, error) {
+	list, err := s.disputeRepo.List(ctx, &workerID, nil, maxDisputeListLimit)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dtos.DisputeListResponse{Disputes: make([]dtos.DisputeDTO, 0, len(list))}
+	for _, d := range list {
+		resp.Disputes = append(resp.Disputes, disputeDTO(d))
+	}
+	return resp, nil
+}
+
+// GetForWorker returns one of the worker's disputes with its timeline.
+func (s *DisputeService) GetForWorker(ctx context.Context, workerID, id uuid.UUID) (*dtos.DisputeDetailResponse, error) {
+	d, events, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d.WorkerID != workerID {
+		return nil, internal_utils.ErrDisputeNotFound
+	}
+	resp := &dtos.DisputeDetailResponse{
+		Dispute: disputeDTO(d),
+		Events:  make([]dtos.DisputeEventDTO, 0, len(events)),
+	}
+	for _, ev := range events {
+		resp.Events = append(resp.Events, dtos.DisputeEventDTO{
+			Action:    ev.Action,
+			Status:    ev.ToStatus,
+			Note:      ev.Note,
+			Evidence:  ev.Evidence,
+			ByWorker:  ev.ActorID != nil && *ev.ActorID == workerID,
+			CreatedAt: ev.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// Reply answers a request for information, sending the dispute back to
+// review.
+func (s *DisputeService) Reply(ctx context.Context, workerID uuid.UUID, req dtos.DisputeReplyRequest) (*dtos.DisputeDTO, error) {
+	d, err := s.disputeRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil || d.WorkerID != workerID {
+		return nil, internal_utils.ErrDisputeNotFound
+	}
+	d, err = s.disputeRepo.Transition(ctx, internal_repositories.DisputeTransition{
+		DisputeID: d.ID,
+		From:      []internal_models.DisputeStatusType{internal_models.DisputeStatusNeedsInfo},
+		To:        internal_models.DisputeStatusUnderReview,
+		Action:    internal_models.DisputeActionInfoProvided,
+		ActorID:   &workerID,
+		Note:      req.Note,
+		Evidence:  req.Evidence,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, internal_utils.ErrDisputeWrongStatus
+	}
+	dto := disputeDTO(d)
+	return &dto, nil
+}
+
+// RecentForWorker returns the worker's unresolved disputes and those
+// updated since since, for the earnings summary.
+func (s *DisputeService) RecentForWorker(ctx context.Context, workerID uuid.UUID, since time.Time) ([]dtos.DisputeDTO, error) {
+	list, err := s.disputeRepo.List(ctx, &workerID, nil, maxDisputeListLimit)
+	if err != nil {
+		return nil, err
+	}
+	out := []dtos.DisputeDTO{}
+	for _, d := range list {
+		if !d.Status.Resolved() || !d.UpdatedAt.Before(since) {
+			out = append(out, disputeDTO(d))
+		}
+	}
+	return out, nil
+}
+
+// ----------------------------------------------------------------
+// Staff
+// ----------------------------------------------------------------
+
+// List returns disputes newest first. Empty status lists the unresolved
+// ones.
+func (s *DisputeService) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	status internal_models.DisputeStatusType,
+	limit int,
+) (*dtos.OpsDisputeListResponse, error) {
+	if limit <= 0 || limit > maxDisputeListLimit {
+		limit = maxDisputeListLimit
+	}
+	statuses := unresolvedDisputeStatuses
+	if status != "" {
+		statuses = []internal_models.DisputeStatusType{status}
+	}
+	list, err := s.disputeRepo.List(ctx, workerID, statuses, limit)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []*internal_models.WorkerDispute{}
+	}
+	return &dtos.OpsDisputeListResponse{Disputes: list}, nil
+}
+
+// Get returns a dispute with its full timeline.
+func (s *DisputeService) Get(ctx context.Context, id uuid.UUID) (*dtos.OpsDisputeDetailResponse, error) {
+	d, events, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.OpsDisputeDetailResponse{Dispute: d, Events: events}, nil
+}
+
+// Triage takes an open dispute into review, assigning it to the caller.
+func (s *DisputeService) Triage(ctx context.Context, actorID uuid.UUID, req dtos.DisputeActionRequest) (*internal_models.WorkerDispute, error) {
+	return s.transition(ctx, internal_repositories.DisputeTransition{
+		DisputeID: req.ID,
+		From:      []internal_models.DisputeStatusType{internal_models.DisputeStatusOpen},
+		To:        internal_models.DisputeStatusUnderReview,
+		Action:    internal_models.DisputeActionTriaged,
+		ActorID:   &actorID,
+		Note:      req.Note,
+		AssignTo:  &actorID,
+	})
+}
+
+// RequestInfo asks the worker for more information.
+func (s *DisputeService) RequestInfo(ctx context.Context, actorID uuid.UUID, req dtos.DisputeActionRequest) (*internal_models.WorkerDispute, error) {
+	if req.Note == "" {
+		return nil, fmt.Errorf("%w: say what information is needed", internal_utils.ErrInvalidDispute)
+	}
+	return s.transition(ctx, internal_repositories.DisputeTransition{
+		DisputeID: req.ID,
+		From: []internal_models.DisputeStatusType{
+			internal_models.DisputeStatusOpen,
+			internal_models.DisputeStatusUnderReview,
+		},
+		To:       internal_models.DisputeStatusNeedsInfo,
+		Action:   internal_models.DisputeActionInfoRequested,
+		ActorID:  &actorID,
+		Note:     req.Note,
+		AssignTo: &actorID,
+	})
+}
+
+// Deny closes a dispute without paying it.
+func (s *DisputeService) Deny(ctx context.Context, actorID uuid.UUID, req dtos.DisputeActionRequest) (*internal_models.WorkerDispute, error) {
+	if req.Note == "" {
+		return nil, fmt.Errorf("%w: give the worker a reason", internal_utils.ErrInvalidDispute)
+	}
+	return s.transition(ctx, internal_repositories.DisputeTransition{
+		DisputeID: req.ID,
+		From:      unresolvedDisputeStatuses,
+		To:        internal_models.DisputeStatusDenied,
+		Action:    internal_models.DisputeActionDenied,
+		ActorID:   &actorID,
+		Note:      req.Note,
+	})
+}
+
+// Approve closes a dispute in the worker's favour and creates a pending
+// CORRECTION adjustment for the approved amount, in one transaction.
+func (s *DisputeService) Approve(ctx context.Context, actorID uuid.UUID, req dtos.ApproveDisputeRequest) (*internal_models.WorkerDispute, error) {
+	var approved *internal_models.WorkerDispute
+	err := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		disputes := internal_repositories.NewWorkerDisputeRepository(w)
+		d, err := disputes.GetByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			return internal_utils.ErrDisputeNotFound
+		}
+
+		reason := fmt.Sprintf("Approved pay dispute %s", d.ID)
+		if req.Note != "" {
+			reason += ": " + req.Note
+		}
+		adj := &internal_models.WorkerAdjustment{
+			WorkerID:        d.WorkerID,
+			Kind:            internal_models.AdjustmentKindCorrection,
+			Amount:          models.MoneyFromDollars(req.Amount),
+			Reason:          reason,
+			JobInstanceID:   d.JobInstanceID,
+			RelatedPayoutID: d.PayoutID,
+			Status:          internal_models.AdjustmentStatusPendingApproval,
+			CreatedBy:       &actorID,
+		}
+		if err := internal_repositories.NewWorkerAdjustmentRepository(w).Create(ctx, adj); err != nil {
+			return err
+		}
+
+		approved, err = disputes.Transition(ctx, internal_repositories.DisputeTransition{
+			DisputeID:    d.ID,
+			From:         unresolvedDisputeStatuses,
+			To:           internal_models.DisputeStatusApproved,
+			Action:       internal_models.DisputeActionApproved,
+			ActorID:      &actorID,
+			Note:         req.Note,
+			AdjustmentID: &adj.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if approved == nil {
+			return internal_utils.ErrDisputeWrongStatus
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s approved dispute %s for %s; adjustment %s awaits approval", actorID, approved.ID, models.MoneyFromDollars(req.Amount), *approved.AdjustmentID)
+	s.notifyWorker(ctx, approved)
+	return approved, nil
+}
+
+func (s *DisputeService) transition(ctx context.Context, t internal_repositories.DisputeTransition) (*internal_models.WorkerDispute, error) {
+	d, err := s.disputeRepo.Transition(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		existing, err := s.disputeRepo.GetByID(ctx, t.DisputeID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, internal_utils.ErrDisputeNotFound
+		}
+		return nil, internal_utils.ErrDisputeWrongStatus
+	}
+	utils.Logger.Infof("Dispute %s moved to %s by %s", d.ID, d.Status, *t.ActorID)
+	s.notifyWorker(ctx, d)
+	return d, nil
+}
+
+func (s *DisputeService) load(ctx context.Context, id uuid.UUID) (*internal_models.WorkerDispute, []*internal_models.WorkerDisputeEvent, error) {
+	d, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if d == nil {
+		return nil, nil, internal_utils.ErrDisputeNotFound
+	}
+	events, err := s.disputeRepo.ListEvents(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, events, nil
+}
+
+// notifyWorker emails the worker that staff moved their dispute.
+func (s *DisputeService) notifyWorker(ctx context.Context, d *internal_models.WorkerDispute) {
+	worker, err := s.workerRepo.GetByID(ctx, d.WorkerID)
+	if err != nil || worker == nil {
+		utils.Logger.WithError(err).Warnf("Could not load worker %s to notify about dispute %s", d.WorkerID, d.ID)
+		return
+	}
+
+	var update string
+	switch d.Status {
+	case internal_models.DisputeStatusUnderReview:
+		update = "Our team is now reviewing your pay dispute."
+	case internal_models.DisputeStatusNeedsInfo:
+		update = "We need a little more information to review your pay dispute. Please reply from the Earnings screen in the app."
+	case internal_models.DisputeStatusApproved:
+		update = "Your pay dispute was approved. The correction will be included in an upcoming payout."
+	case internal_models.DisputeStatusDenied:
+		update = "After review, we were unable to approve your pay dispute."
+	default:
+		return
+	}
+	note := d.ResolutionNote
+	if d.Status == internal_models.DisputeStatusNeedsInfo {
+		// The question for the worker; triage notes stay internal.
+		if events, err := s.disputeRepo.ListEvents(ctx, d.ID); err == nil && len(events) > 0 {
+			note = events[len(events)-1].Note
+		}
+	}
+	body := fmt.Sprintf("Hi %s,\n\n%s\n", worker.FirstName, update)
+	if note != "" {
+		body += fmt.Sprintf("\nNote from our team: %s\n", note)
+	}
+	body += fmt.Sprintf("\nDispute reference: %s\n\n- The Poof Team", d.ID)
+
+	s.notifier.Email(ctx, utils.EmailJob{
+		FromName:  s.cfg.OrganizationName,
+		FromEmail: s.cfg.LDFlag_SendgridFromEmail,
+		ToName:    worker.FirstName + " " + worker.LastName,
+		ToEmail:   worker.Email,
+		Subject:   constants.EmailSubjectDisputeUpdate,
+		PlainText: body,
+		Sandbox:   s.cfg.LDFlag_SendgridSandboxMode,
+	})
+}
@@ -13,46 +13,54 @@ import (
 	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
 	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
 	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
-	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
 const (
-	// PayoutStatusCurrent indicates the week is the ongoing, not-yet-payout-eligible week.
+	// PayoutStatusCurrent indicates the ongoing, not-yet-payout-eligible pay period.
 	PayoutStatusCurrent = "CURRENT"
 )
 
 type EarningsService struct {
-	jobInstRepo  repositories.JobInstanceRepository
-	payoutRepo   internal_repositories.WorkerPayoutRepository
-	defRepo      repositories.JobDefinitionRepository
-	propRepo     repositories.PropertyRepository
-	payoutSvc    *PayoutService // NEW: Dependency on PayoutService
-	cfg          *config.Config
+	jobInstRepo    repositories.JobInstanceRepository
+	payoutRepo     internal_repositories.WorkerPayoutRepository
+	defRepo        repositories.JobDefinitionRepository
+	propRepo       repositories.PropertyRepository
+	payItemRepo    repositories.JobPayItemRepository
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository
+	payoutSvc      *PayoutService // NEW: Dependency on PayoutService
+	disputeSvc     *DisputeService
+	cfg            *config.Config
 }
 
-func NewEarningsService(cfg *config.Config, jobInstRepo repositories.JobInstanceRepository, payoutRepo internal_repositories.WorkerPayoutRepository, defRepo repositories.JobDefinitionRepository, propRepo repositories.PropertyRepository, payoutSvc *PayoutService) *EarningsService {
+func NewEarningsService(cfg *config.Config, jobInstRepo repositories.JobInstanceRepository, payoutRepo internal_repositories.WorkerPayoutRepository, defRepo repositories.JobDefinitionRepository, propRepo repositories.PropertyRepository, payItemRepo repositories.JobPayItemRepository, adjustmentRepo internal_repositories.WorkerAdjustmentRepository, payoutSvc *PayoutService, disputeSvc *DisputeService) *EarningsService {
 	return &EarningsService{
-		jobInstRepo: jobInstRepo,
-		payoutRepo:  payoutRepo,
-		defRepo:     defRepo,
-		propRepo:    propRepo,
-		payoutSvc:   payoutSvc, // NEW
-		cfg:         cfg,
+		jobInstRepo:    jobInstRepo,
+		payoutRepo:     payoutRepo,
+		defRepo:        defRepo,
+		propRepo:       propRepo,
+		payItemRepo:    payItemRepo,
+		adjustmentRepo: adjustmentRepo,
+		payoutSvc:      payoutSvc, // NEW
+		disputeSvc:     disputeSvc,
+		cfg:            cfg,
 	}
 }
 
-// GetEarningsSummary provides a unified view of earnings, adapting to both weekly and daily payout cycles.
+// GetEarningsSummary provides a unified view of earnings, laid out in the
+// periods of the worker's pay schedule.
 func (s *EarningsService) GetEarningsSummary(ctx context.Context, workerIDStr string) (*dtos.EarningsSummaryResponse, error) {
 	workerID, err := uuid.Parse(workerIDStr)
 	if err != nil {
 		return nil, err
 	}
 
-	loc, _ := time.LoadLocation(constants.BusinessTimezone)
-	nowForLogic := time.Now().In(loc)
+	schedule, err := s.payoutSvc.schedules.ForWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
 	nowForQuery := time.Now().UTC()
 	endDate := nowForQuery.AddDate(0, 0, 1)
 	startDate := nowForQuery.AddDate(0, 0, -constants.EarningsSummaryDays)
@@ -66,6 +74,32 @@ func (s *EarningsService) GetEarningsSummary(ctx context.Context, workerIDStr st
 	if err != nil {
 		return nil, err
 	}
+	// A job's pay is what its pay ledger adds up to.
+	jobIDs := make([]uuid.UUID, len(completedJobs))
+	for i, job := range completedJobs {
+		jobIDs[i] = job.ID
+	}
+	payItems, err := s.payItemRepo.ListByInstances(ctx, jobIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Adjustments paid with each payout, and approved ones still waiting.
+	payoutIDs := make([]uuid.UUID, len(payouts))
+	for i, p := range payouts {
+		payoutIDs[i] = p.ID
+	}
+	adjustmentsByPayout, err := s.adjustmentRepo.ListByPayoutIDs(ctx, payoutIDs)
+	if err != nil {
+		return nil, err
+	}
+	approvedAdjustments, err := s.adjustmentRepo.List(ctx, &workerID, internal_models.AdjustmentStatusApproved, maxAdjustmentListLimit)
+	if err != nil {
+		return nil, err
+	}
+	disputes, err := s.disputeSvc.RecentForWorker(ctx, workerID, startDate)
+	if err != nil {
+		return nil, err
+	}
 
 	// --- NEW: Reconcile stale payouts ---
 	var reconciledPayouts []*internal_models.WorkerPayout
@@ -86,7 +120,7 @@ func (s *EarningsService) GetEarningsSummary(ctx context.Context, workerIDStr st
 	// --- End of New Logic ---
 
 	// 2. Group jobs by ID for efficient lookup and calculate initial total.
-	jobsByID, twoMonthTotal := s._groupJobsByIDAndCalcTotal(completedJobs)
+	jobsByID, twoMonthTotal := s._groupJobsByIDAndCalcTotal(completedJobs, payItems)
 
 	// Pre-fetch definitions and properties for efficiency.
 	defMap, propMap, err := s._fetchJobMetadata(ctx, completedJobs)
@@ -95,34 +129,72 @@ func (s *EarningsService) GetEarningsSummary(ctx context.Context, workerIDStr st
 	}
 
 	// 3. Process existing payouts to build the "Earnings History".
-	pastWeeksDTOs, processedJobIDs := s._processPaidHistory(reconciledPayouts, jobsByID, defMap, propMap)
+	pastWeeksDTOs, processedJobIDs := s._processPaidHistory(reconciledPayouts, schedule, jobsByID, payItems, adjustmentsByPayout, defMap, propMap)
+
+	// 4. Jobs on hold are listed apart and left out of every total.
+	var unpaidJobs []*models.JobInstance
+	for _, job := range completedJobs {
+		if !processedJobIDs[job.ID] {
+			unpaidJobs = append(unpaidJobs, job)
+		}
+	}
+	holds, err := s.payoutSvc.holds.Screen(ctx, unpaidJobs)
+	if err != nil {
+		return nil, err
+	}
+	payableJobs := make([]*models.JobInstance, 0, len(completedJobs))
+	heldJobs := []dtos.HeldJobDTO{}
+	for _, job := range completedJobs {
+		h := holds[job.ID]
+		if !h.Blocks() {
+			payableJobs = append(payableJobs, job)
+			continue
+		}
+		twoMonthTotal = twoMonthTotal.Sub(models.SumPayItems(payItems[job.ID]))
+		heldJobs = append(heldJobs, dtos.HeldJobDTO{
+			CompletedJobDTO: s._jobInstanceToCompletedDTO(job, payItems[job.ID], defMap, propMap),
+			HoldReason:      string(h.Reason),
+			HoldStatus:      string(h.Status),
+			HeldAt:          h.CreatedAt,
+			ResolutionNote:  h.ResolutionNote,
+		})
+	}
 
-	// 4. Build the "Current Period" DTO from all jobs that have NOT been processed in a payout.
-	currentPeriodDTO := s._buildCurrentPeriodDTO(completedJobs, processedJobIDs, defMap, propMap, nowForLogic)
+	// 5. Build the "Current Period" DTO from all jobs that have NOT been processed in a payout.
+	periodStart, periodEnd := schedule.PeriodAt(nowForQuery)
+	currentPeriodDTO := s._buildCurrentPeriodDTO(payableJobs, processedJobIDs, payItems, defMap, propMap, schedule, periodStart, periodEnd)
+	currentPeriodDTO.Adjustments = _adjustmentDTOs(approvedAdjustments)
 
-	// 5. Sort all past entries from most recent to oldest.
+	// 6. Sort all past entries from most recent to oldest.
 	sort.Slice(pastWeeksDTOs, func(i, j int) bool {
 		return pastWeeksDTOs[i].WeekStartDate > pastWeeksDTOs[j].WeekStartDate
 	})
 
-	// 6. Conditionally calculate the "Next Payout Date".
-	var nextPayoutDate time.Time
-	if s.cfg.LDFlag_UseShortPayPeriod {
-		nextPayoutDate = nowForLogic.AddDate(0, 0, 1)
-	} else {
-		currentWeekStartForPayoutCalc := internal_utils.GetPayPeriodStartForDate(nowForLogic)
-		nextPayoutDate = currentWeekStartForPayoutCalc.AddDate(0, 0, 8)
-	}
+	// 7. The current period is paid out its schedule's delay after it closes.
+	nextPayoutDate := schedule.PayoutAt(periodEnd).In(schedule.Location())
 
 	return &dtos.EarningsSummaryResponse{
 		TwoMonthTotal:  twoMonthTotal,
 		CurrentWeek:    currentPeriodDTO,
 		PastWeeks:      pastWeeksDTOs,
 		NextPayoutDate: nextPayoutDate.Format("2006-01-02"),
+		Disputes:       disputes,
+		HeldJobs:       heldJobs,
 	}, nil
 }
 
 func (s *EarningsService) _fetchJobMetadata(ctx context.Context, jobs []*models.JobInstance) (map[uuid.UUID]*models.JobDefinition, map[uuid.UUID]*models.Property, error) {
+	return fetchJobMetadata(ctx, s.defRepo, s.propRepo, jobs)
+}
+
+// fetchJobMetadata loads the definitions and properties of jobs. Ones that
+// fail to load are logged and left out.
+func fetchJobMetadata(
+	ctx context.Context,
+	defRepo repositories.JobDefinitionRepository,
+	propRepo repositories.PropertyRepository,
+	jobs []*models.JobInstance,
+) (map[uuid.UUID]*models.JobDefinition, map[uuid.UUID]*models.Property, error) {
 	defIDs := make(map[uuid.UUID]struct{})
 	for _, job := range jobs {
 		defIDs[job.DefinitionID] = struct{}{}
@@ -131,7 +203,7 @@ func (s *EarningsService) _fetchJobMetadata(ctx context.Context, jobs []*models.
 	defMap := make(map[uuid.UUID]*models.JobDefinition)
 	propIDs := make(map[uuid.UUID]struct{})
 	for id := range defIDs {
-		def, err := s.defRepo.GetByID(ctx, id)
+		def, err := defRepo.GetByID(ctx, id)
 		if err != nil {
 			utils.Logger.WithError(err).Warnf("Could not fetch definition %s", id)
 			continue
@@ -144,7 +216,7 @@ func (s *EarningsService) _fetchJobMetadata(ctx context.Context, jobs []*models.
 
 	propMap := make(map[uuid.UUID]*models.Property)
 	for id := range propIDs {
-		prop, err := s.propRepo.GetByID(ctx, id)
+		prop, err := propRepo.GetByID(ctx, id)
 		if err != nil {
 			utils.Logger.WithError(err).Warnf("Could not fetch property %s", id)
 			continue
@@ -157,19 +229,22 @@ func (s *EarningsService) _fetchJobMetadata(ctx context.Context, jobs []*models.
 	return defMap, propMap, nil
 }
 
-func (s *EarningsService) _groupJobsByIDAndCalcTotal(completedJobs []*models.JobInstance) (map[uuid.UUID]*models.JobInstance, float64) {
+func (s *EarningsService) _groupJobsByIDAndCalcTotal(completedJobs []*models.JobInstance, payItems map[uuid.UUID][]*models.JobPayItem) (map[uuid.UUID]*models.JobInstance, models.Money) {
 	jobsByID := make(map[uuid.UUID]*models.JobInstance, len(completedJobs))
-	var total float64
+	var total models.Money
 	for _, job := range completedJobs {
 		jobsByID[job.ID] = job
-		total += job.EffectivePay
+		total = total.Add(models.SumPayItems(payItems[job.ID]))
 	}
 	return jobsByID, total
 }
 
 func (s *EarningsService) _processPaidHistory(
 	payouts []*internal_models.WorkerPayout,
+	schedule *internal_models.PaySchedule,
 	jobsByID map[uuid.UUID]*models.JobInstance,
+	payItems map[uuid.UUID][]*models.JobPayItem,
+	adjustmentsByPayout map[uuid.UUID][]*internal_models.WorkerAdjustment,
 	defMap map[uuid.UUID]*models.JobDefinition,
 	propMap map[uuid.UUID]*models.Property,
 ) ([]dtos.WeeklyEarningsDTO, map[uuid.UUID]bool) {
@@ -195,11 +270,11 @@ func (s *EarningsService) _processPaidHistory(
 		var dailyBreakdown []dtos.DailyEarningDTO
 		var weeklyJobCount int
 		for dateKey, jobsForDay := range jobsByDateForPayout {
-			var dailyAmount float64
+			var dailyAmount models.Money
 			var completedJobDTOs []dtos.CompletedJobDTO
 			for _, job := range jobsForDay {
-				dailyAmount += job.EffectivePay
-				completedJobDTOs = append(completedJobDTOs, s._jobInstanceToCompletedDTO(job, defMap, propMap))
+				dailyAmount = dailyAmount.Add(models.SumPayItems(payItems[job.ID]))
+				completedJobDTOs = append(completedJobDTOs, s._jobInstanceToCompletedDTO(job, payItems[job.ID], defMap, propMap))
 			}
 
 			dailyBreakdown = append(dailyBreakdown, dtos.DailyEarningDTO{
@@ -222,13 +297,24 @@ func (s *EarningsService) _processPaidHistory(
 			requiresUserAction = reqUserAction
 		}
 
+		var fee *models.Money
+		if p.FeeCents > 0 {
+			f := models.USD(p.FeeCents)
+			fee = &f
+		}
+
+		firstDay, lastDay := schedule.Dates(p.PeriodStart, p.PeriodEnd)
 		pastWeeksDTOs = append(pastWeeksDTOs, dtos.WeeklyEarningsDTO{
-			WeekStartDate:      p.WeekStartDate.UTC().Format("2006-01-02"),
-			WeekEndDate:        p.WeekEndDate.UTC().Format("2006-01-02"),
-			WeeklyTotal:        float64(p.AmountCents) / 100.0,
+			WeekStartDate:      firstDay,
+			WeekEndDate:        lastDay,
+			WeeklyTotal:        models.USD(p.SentCents()),
 			JobCount:           weeklyJobCount,
 			PayoutStatus:       string(p.Status),
+			PayoutKind:         string(p.Kind),
+			PayoutMethod:       string(p.Method),
+			Fee:                fee,
 			DailyBreakdown:     dailyBreakdown,
+			Adjustments:        _adjustmentDTOs(adjustmentsByPayout[p.ID]),
 			FailureReason:      failureReason,
 			RequiresUserAction: requiresUserAction,
 		})
@@ -239,22 +325,22 @@ func (s *EarningsService) _processPaidHistory(
 func (s *EarningsService) _buildCurrentPeriodDTO(
 	allCompletedJobs []*models.JobInstance,
 	processedJobIDs map[uuid.UUID]bool,
+	payItems map[uuid.UUID][]*models.JobPayItem,
 	defMap map[uuid.UUID]*models.JobDefinition,
 	propMap map[uuid.UUID]*models.Property,
-	nowForLogic time.Time,
+	schedule *internal_models.PaySchedule,
+	periodStart, periodEnd time.Time,
 ) *dtos.WeeklyEarningsDTO {
 	dailyTallies := make(map[time.Time]struct {
-		amount float64
+		amount models.Money
 		jobs   []dtos.CompletedJobDTO
 	})
 
-	var periodTotal float64
+	var periodTotal models.Money
 	var periodJobCount int
 
-	// This ensures the UI always shows a full 7-day week for the "This Week" section,
-	// regardless of whether the pay cycle is daily or weekly.
-	currentPeriodStart := internal_utils.GetPayPeriodStartForDate(nowForLogic)
-	currentPeriodEnd := currentPeriodStart.AddDate(0, 0, 6)
+	// A job belongs to the period its service date falls in.
+	firstDay, lastDay := schedule.Dates(periodStart, periodEnd)
 
 	for _, job := range allCompletedJobs {
 		if processedJobIDs[job.ID] {
@@ -262,17 +348,18 @@ func (s *EarningsService) _buildCurrentPeriodDTO(
 		}
 
 		serviceDateOnly := job.ServiceDate.Truncate(24 * time.Hour)
-		if serviceDateOnly.Before(currentPeriodStart) || serviceDateOnly.After(currentPeriodEnd) {
-			continue // This job is from a past, unpaid week. Ignore it for the "current" total.
+		if day := serviceDateOnly.Format("2006-01-02"); day < firstDay || day > lastDay {
+			continue // This job is from a past, unpaid period. Ignore it for the "current" total.
 		}
 
 		// All remaining jobs are part of the "current" period's earnings.
-		periodTotal += job.EffectivePay
+		pay := models.SumPayItems(payItems[job.ID])
+		periodTotal = periodTotal.Add(pay)
 		periodJobCount++
 
 		tally := dailyTallies[serviceDateOnly]
-		tally.amount += job.EffectivePay
-		tally.jobs = append(tally.jobs, s._jobInstanceToCompletedDTO(job, defMap, propMap))
+		tally.amount = tally.amount.Add(pay)
+		tally.jobs = append(tally.jobs, s._jobInstanceToCompletedDTO(job, payItems[job.ID], defMap, propMap))
 		dailyTallies[serviceDateOnly] = tally
 	}
 
@@ -288,8 +375,8 @@ func (s *EarningsService) _buildCurrentPeriodDTO(
 	sort.Slice(dailyBreakdown, func(i, j int) bool { return dailyBreakdown[i].Date < dailyBreakdown[j].Date })
 
 	return &dtos.WeeklyEarningsDTO{
-		WeekStartDate:  currentPeriodStart.Format("2006-01-02"),
-		WeekEndDate:    currentPeriodEnd.Format("2006-01-02"),
+		WeekStartDate:  firstDay,
+		WeekEndDate:    lastDay,
 		WeeklyTotal:    periodTotal,
 		JobCount:       periodJobCount,
 		PayoutStatus:   PayoutStatusCurrent,
@@ -297,12 +384,34 @@ func (s *EarningsService) _buildCurrentPeriodDTO(
 	}
 }
 
-func (s *EarningsService) _jobInstanceToCompletedDTO(job *models.JobInstance, defMap map[uuid.UUID]*models.JobDefinition, propMap map[uuid.UUID]*models.Property) dtos.CompletedJobDTO {
+// _adjustmentDTOs lists adjustments for the worker; nil when there are none.
+func _adjustmentDTOs(adjs []*internal_models.WorkerAdjustment) []dtos.AdjustmentDTO {
+	var out []dtos.AdjustmentDTO
+	for _, a := range adjs {
+		out = append(out, dtos.AdjustmentDTO{
+			Kind:   string(a.Kind),
+			Amount: a.Amount,
+			Reason: a.Reason,
+		})
+	}
+	return out
+}
+
+func (s *EarningsService) _jobInstanceToCompletedDTO(job *models.JobInstance, items []*models.JobPayItem, defMap map[uuid.UUID]*models.JobDefinition, propMap map[uuid.UUID]*models.Property) dtos.CompletedJobDTO {
 	dto := dtos.CompletedJobDTO{
 		InstanceID:  job.ID,
-		Pay:         job.EffectivePay,
+		Pay:         models.SumPayItems(items),
+		PayItems:    make([]dtos.PayItemDTO, 0, len(items)),
 		CompletedAt: job.CheckOutAt,
 	}
+	for _, it := range items {
+		dto.PayItems = append(dto.PayItems, dtos.PayItemDTO{
+			Kind:        it.Kind,
+			Label:       it.Kind.Label(),
+			Amount:      it.Amount,
+			Description: it.Description,
+		})
+	}
 
 	if def, ok := defMap[job.DefinitionID]; ok {
 		if prop, ok := propMap[def.PropertyID]; ok {
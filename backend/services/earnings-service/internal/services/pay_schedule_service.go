@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+PayScheduleService decides which pay schedule each worker is paid on and
+lets ops manage schedules.
+
+A worker is on the schedule assigned to them, else the one assigned to
+their market (the market of the property they last completed a job at),
+else the default schedule. With use_short_pay_period on, everyone is on
+the daily schedule instead.
+
+Moving a worker to another schedule doesn't touch payouts already made;
+their unpaid jobs are paid with the new schedule's periods.
+*/
+type PayScheduleService struct {
+	cfg          *config.Config
+	workerRepo   repositories.WorkerRepository
+	scheduleRepo internal_repositories.PayScheduleRepository
+}
+
+func NewPayScheduleService(cfg *config.Config, workerRepo repositories.WorkerRepository, scheduleRepo internal_repositories.PayScheduleRepository) *PayScheduleService {
+	return &PayScheduleService{cfg: cfg, workerRepo: workerRepo, scheduleRepo: scheduleRepo}
+}
+
+// ForWorker returns the schedule the worker is paid on.
+func (s *PayScheduleService) ForWorker(ctx context.Context, workerID uuid.UUID) (*internal_models.PaySchedule, error) {
+	schedules, err := s.ForWorkers(ctx, []uuid.UUID{workerID})
+	if err != nil {
+		return nil, err
+	}
+	return schedules[workerID], nil
+}
+
+// ForWorkers returns the schedule each worker is paid on.
+func (s *PayScheduleService) ForWorkers(ctx context.Context, workerIDs []uuid.UUID) (map[uuid.UUID]*internal_models.PaySchedule, error) {
+	out := make(map[uuid.UUID]*internal_models.PaySchedule, len(workerIDs))
+	if s.cfg.LDFlag_UseShortPayPeriod {
+		daily := internal_models.DailyPaySchedule()
+		for _, id := range workerIDs {
+			out[id] = daily
+		}
+		return out, nil
+	}
+
+	list, err := s.scheduleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pay schedules: %w", err)
+	}
+	byID := make(map[uuid.UUID]*internal_models.PaySchedule, len(list))
+	fallback := internal_models.DefaultPaySchedule()
+	for _, sch := range list {
+		byID[sch.ID] = sch
+		if sch.IsDefault {
+			fallback = sch
+		}
+	}
+
+	assignments, err := s.scheduleRepo.ListAssignments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pay schedule assignments: %w", err)
+	}
+	byWorker := make(map[uuid.UUID]*internal_models.PaySchedule)
+	byMarket := make(map[string]*internal_models.PaySchedule)
+	for _, a := range assignments {
+		switch {
+		case a.WorkerID != nil:
+			byWorker[*a.WorkerID] = byID[a.PayScheduleID]
+		case a.Market != nil:
+			byMarket[*a.Market] = byID[a.PayScheduleID]
+		}
+	}
+
+	var needMarket []uuid.UUID
+	for _, id := range workerIDs {
+		if sch := byWorker[id]; sch != nil {
+			out[id] = sch
+		} else {
+			needMarket = append(needMarket, id)
+		}
+	}
+	var markets map[uuid.UUID]string
+	if len(byMarket) > 0 && len(needMarket) > 0 {
+		if markets, err = s.scheduleRepo.WorkerMarkets(ctx, needMarket); err != nil {
+			return nil, fmt.Errorf("look up worker markets: %w", err)
+		}
+	}
+	for _, id := range needMarket {
+		if sch := byMarket[markets[id]]; sch != nil {
+			out[id] = sch
+		} else {
+			out[id] = fallback
+		}
+	}
+	return out, nil
+}
+
+// List returns every schedule and assignment.
+func (s *PayScheduleService) List(ctx context.Context) (*dtos.PayScheduleListResponse, error) {
+	schedules, err := s.scheduleRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.scheduleRepo.ListAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedules == nil {
+		schedules = []*internal_models.PaySchedule{}
+	}
+	if assignments == nil {
+		assignments = []*internal_models.PayScheduleAssignment{}
+	}
+	return &dtos.PayScheduleListResponse{Schedules: schedules, Assignments: assignments}, nil
+}
+
+// Create adds a schedule.
+func (s *PayScheduleService) Create(ctx context.Context, actorID uuid.UUID, req dtos.CreatePayScheduleRequest) (*internal_models.PaySchedule, error) {
+	anchor, err := time.Parse("2006-01-02", req.AnchorDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: anchor_date must be YYYY-MM-DD", internal_utils.ErrInvalidPaySchedule)
+	}
+	sch := &internal_models.PaySchedule{
+		Name:             req.Name,
+		Frequency:        req.Frequency,
+		AnchorDate:       anchor,
+		StartHour:        req.StartHour,
+		Timezone:         req.Timezone,
+		PayoutDelayHours: req.PayoutDelayHours,
+		IsDefault:        req.IsDefault,
+	}
+	if err := sch.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", internal_utils.ErrInvalidPaySchedule, err)
+	}
+	if err := s.scheduleRepo.Create(ctx, sch); err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s created %s pay schedule %s (%q, default=%t)", actorID, sch.Frequency, sch.ID, sch.Name, sch.IsDefault)
+	return sch, nil
+}
+
+// Assign puts a worker or market on a schedule.
+func (s *PayScheduleService) Assign(ctx context.Context, actorID uuid.UUID, req dtos.AssignPayScheduleRequest) (*internal_models.PayScheduleAssignment, error) {
+	if (req.WorkerID == nil) == (req.Market == nil) {
+		return nil, fmt.Errorf("%w: give exactly one of worker_id and market", internal_utils.ErrInvalidPaySchedule)
+	}
+	sch, err := s.scheduleRepo.GetByID(ctx, req.PayScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if sch == nil {
+		return nil, internal_utils.ErrPayScheduleNotFound
+	}
+	if req.WorkerID != nil {
+		worker, err := s.workerRepo.GetByID(ctx, *req.WorkerID)
+		if err != nil {
+			return nil, err
+		}
+		if worker == nil {
+			return nil, fmt.Errorf("%w: worker not found", internal_utils.ErrInvalidPaySchedule)
+		}
+	}
+
+	a := &internal_models.PayScheduleAssignment{
+		PayScheduleID: sch.ID,
+		WorkerID:      req.WorkerID,
+		Market:        req.Market,
+		CreatedBy:     &actorID,
+	}
+	if err := s.scheduleRepo.Assign(ctx, a); err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s assigned pay schedule %s to %s", actorID, sch.ID, assignmentTarget(req.WorkerID, req.Market))
+	return a, nil
+}
+
+// Unassign takes a worker or market off its schedule.
+func (s *PayScheduleService) Unassign(ctx context.Context, actorID uuid.UUID, req dtos.UnassignPayScheduleRequest) error {
+	if (req.WorkerID == nil) == (req.Market == nil) {
+		return fmt.Errorf("%w: give exactly one of worker_id and market", internal_utils.ErrInvalidPaySchedule)
+	}
+	removed, err := s.scheduleRepo.Unassign(ctx, req.WorkerID, req.Market)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return internal_utils.ErrNotAssigned
+	}
+	utils.Logger.Infof("Ops %s removed the pay schedule assignment of %s", actorID, assignmentTarget(req.WorkerID, req.Market))
+	return nil
+}
+
+func assignmentTarget(workerID *uuid.UUID, market *string) string {
+	if workerID != nil {
+		return "worker " + workerID.String()
+	}
+	return fmt.Sprintf("market %q", *market)
+}
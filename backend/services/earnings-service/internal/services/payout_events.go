@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+Domain events from jobs-service, delivered through the outbox relay.
+
+AggregateAndCreatePayouts only looks at a pay period once, so a job that is
+completed (e.g. by an agent) after its period's payout was created would
+never be paid, a job canceled after being counted would be paid anyway, and
+an adjustment or clawback made after the period was aggregated would be
+missed. These handlers keep an unsent payout in step with its jobs, under
+the worker's payout lock so a cash-out can't take a job while it is being
+added to the period's payout. Payouts already
+being processed or paid are left alone. A job completed after its period's
+payout was sent is paid by an approved LATE_JOB adjustment, and a pay change
+on a job that was already paid out becomes an approved adjustment, so the
+next payout pays or recovers the difference.
+
+A change that takes an unsent payout to or below the minimum payout settles
+it the way aggregation does: nothing is sent and its amount, negative if a
+cancellation or clawback took more than it had, is carried forward to the
+next payout.
+*/
+
+var (
+	errPayoutSealed    = errors.New("payout already processing or paid")
+	errPayoutUnchanged = errors.New("payout unchanged")
+)
+
+// payoutChange changes an unsent payout p and reports whether it did. It
+// runs under the worker's payout lock and reads other payouts through
+// payouts.
+type payoutChange func(ctx context.Context, payouts internal_repositories.WorkerPayoutRepository, p *internal_models.WorkerPayout) (bool, error)
+
+// SubscribeEvents registers the service's outbox handlers.
+func (s *PayoutService) SubscribeEvents(relay *repositories.OutboxRelay) {
+	repositories.SubscribeEvent(relay, models.EventJobCompleted, s.onJobCompleted)
+	repositories.SubscribeEvent(relay, models.EventJobCanceled, s.onJobCanceled)
+	repositories.SubscribeEvent(relay, models.EventJobPayChanged, s.onJobPayChanged)
+}
+
+func (s *PayoutService) onJobCompleted(ctx context.Context, _ *models.OutboxEvent, ev models.JobInstanceEvent) error {
+	paidOut, err := s.payoutRepo.PaidOutJobIDs(ctx, []uuid.UUID{ev.InstanceID})
+	if err != nil || paidOut[ev.InstanceID] {
+		// Already in a payout, e.g. cashed out on demand.
+		return err
+	}
+	job, err := s.jobInstRepo.GetByID(ctx, ev.InstanceID)
+	if err != nil || job == nil {
+		return err
+	}
+	hold, err := s.holds.ScreenOne(ctx, job)
+	if err != nil || hold.Blocks() {
+		// Held jobs are paid once released, in a later payout.
+		return err
+	}
+	err = s.syncUnsentPayout(ctx, ev, func(ctx context.Context, payouts internal_repositories.WorkerPayoutRepository, p *internal_models.WorkerPayout) (bool, error) {
+		if slices.Contains(p.JobInstanceIDs, ev.InstanceID) {
+			return false, nil
+		}
+		// Again under the lock: a cash-out may have taken it since.
+		paidOut, err := payouts.PaidOutJobIDs(ctx, []uuid.UUID{ev.InstanceID})
+		if err != nil || paidOut[ev.InstanceID] {
+			return false, err
+		}
+		p.JobInstanceIDs = append(p.JobInstanceIDs, ev.InstanceID)
+		p.AmountCents += ev.EffectivePay.Cents
+		return true, nil
+	})
+	if errors.Is(err, errPayoutSealed) {
+		// Aggregation won't look at the period again.
+		return s.payLateJob(ctx, ev)
+	}
+	return err
+}
+
+func (s *PayoutService) onJobCanceled(ctx context.Context, _ *models.OutboxEvent, ev models.JobInstanceEvent) error {
+	err := s.syncUnsentPayout(ctx, ev, func(_ context.Context, _ internal_repositories.WorkerPayoutRepository, p *internal_models.WorkerPayout) (bool, error) {
+		i := slices.Index(p.JobInstanceIDs, ev.InstanceID)
+		if i < 0 {
+			return false, nil
+		}
+		p.JobInstanceIDs = slices.Delete(p.JobInstanceIDs, i, i+1)
+		p.AmountCents -= ev.EffectivePay.Cents
+		return true, nil
+	})
+	if errors.Is(err, errPayoutSealed) {
+		utils.Logger.Warnf("Job %s canceled after its period's payout was sent; not adjusting it", ev.InstanceID)
+		return nil
+	}
+	return err
+}
+
+func (s *PayoutService) onJobPayChanged(ctx context.Context, _ *models.OutboxEvent, ev models.JobPayChangedEvent) error {
+	synced := false
+	err := s.syncUnsentPayout(ctx, ev.JobInstanceEvent, func(_ context.Context, _ internal_repositories.WorkerPayoutRepository, p *internal_models.WorkerPayout) (bool, error) {
+		if !slices.Contains(p.JobInstanceIDs, ev.InstanceID) {
+			// Not counted yet; it is added at its new total when it is.
+			return false, nil
+		}
+		p.AmountCents += ev.Item.Amount.Cents
+		synced = true
+		return true, nil
+	})
+	if errors.Is(err, errPayoutSealed) {
+		err = nil
+	}
+	if err != nil || synced {
+		return err
+	}
+	return s.adjustPaidJob(ctx, ev)
+}
+
+// adjustPaidJob records a pay change on a job whose payout was already sent
+// (or cashed out) as an approved adjustment against that payout. The
+// adjustment takes the pay item's ID, so a redelivered event adds nothing.
+func (s *PayoutService) adjustPaidJob(ctx context.Context, ev models.JobPayChangedEvent) error {
+	if ev.AssignedWorkerID == nil || ev.Item.Amount.IsZero() {
+		return nil
+	}
+	paidOut, err := s.payoutRepo.PaidOutJobIDs(ctx, []uuid.UUID{ev.InstanceID})
+	if err != nil || !paidOut[ev.InstanceID] {
+		// Not paid yet; it is paid at its new total when it is.
+		return err
+	}
+	existing, err := s.adjustmentRepo.GetByID(ctx, ev.Item.ID)
+	if err != nil || existing != nil {
+		return err
+	}
+	payoutIDs, err := s.payoutRepo.PayoutIDsByJobs(ctx, []uuid.UUID{ev.InstanceID})
+	if err != nil {
+		return err
+	}
+
+	kind := internal_models.AdjustmentKindCorrection
+	if ev.Item.Amount.IsNegative() {
+		kind = internal_models.AdjustmentKindClawback
+	}
+	jobID := ev.InstanceID
+	a := &internal_models.WorkerAdjustment{
+		ID:            ev.Item.ID,
+		WorkerID:      *ev.AssignedWorkerID,
+		Kind:          kind,
+		Amount:        ev.Item.Amount,
+		Reason:        fmt.Sprintf("%s on job %s after it was paid", ev.Item.Description, jobID),
+		JobInstanceID: &jobID,
+		Status:        internal_models.AdjustmentStatusApproved,
+		CreatedBy:     ev.Item.ActorID,
+	}
+	// A job paid by a LATE_JOB adjustment is in no payout.
+	if ids := payoutIDs[ev.InstanceID]; len(ids) > 0 {
+		payoutID := ids[len(ids)-1]
+		a.Reason = fmt.Sprintf("%s on job %s after payout %s", ev.Item.Description, jobID, payoutID)
+		a.RelatedPayoutID = &payoutID
+	}
+	if err := s.adjustmentRepo.Create(ctx, a); err != nil {
+		return err
+	}
+	utils.Logger.Infof("Pay change of %s on paid job %s recorded as adjustment %s", a.Amount, jobID, a.ID)
+	return nil
+}
+
+// payLateJob pays a job completed after its period's payout was sent as an
+// approved LATE_JOB adjustment against that payout, which the worker's next
+// payout pays. The adjustment marks the job paid, so neither a cash-out nor
+// a redelivered event pays it again.
+func (s *PayoutService) payLateJob(ctx context.Context, ev models.JobInstanceEvent) error {
+	if !ev.EffectivePay.IsPositive() {
+		return nil
+	}
+	sch, err := s.schedules.ForWorker(ctx, *ev.AssignedWorkerID)
+	if err != nil {
+		return err
+	}
+	periodStart, _ := sch.PeriodForDate(ev.ServiceDate)
+
+	var a *internal_models.WorkerAdjustment
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		a = nil
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		if err := payouts.LockWorker(ctx, *ev.AssignedWorkerID); err != nil {
+			return fmt.Errorf("lock worker payouts: %w", err)
+		}
+		paidOut, err := payouts.PaidOutJobIDs(ctx, []uuid.UUID{ev.InstanceID})
+		if err != nil || paidOut[ev.InstanceID] {
+			return err
+		}
+		sent, err := payouts.GetScheduledByPeriod(ctx, *ev.AssignedWorkerID, periodStart)
+		if err != nil {
+			return err
+		}
+
+		jobID := ev.InstanceID
+		a = &internal_models.WorkerAdjustment{
+			WorkerID:      *ev.AssignedWorkerID,
+			Kind:          internal_models.AdjustmentKindLateJob,
+			Amount:        ev.EffectivePay,
+			Reason:        fmt.Sprintf("Job %s completed after its pay period was paid", jobID),
+			JobInstanceID: &jobID,
+			Status:        internal_models.AdjustmentStatusApproved,
+		}
+		if sent != nil {
+			a.Reason = fmt.Sprintf("Job %s completed after payout %s was sent", jobID, sent.ID)
+			a.RelatedPayoutID = &sent.ID
+		}
+		return internal_repositories.NewWorkerAdjustmentRepository(w).Create(ctx, a)
+	})
+	if err != nil || a == nil {
+		return err
+	}
+	utils.Logger.Infof("Job %s completed after its period's payout was sent; paying %s as adjustment %s", ev.InstanceID, a.Amount, a.ID)
+	return nil
+}
+
+// syncUnsentPayout applies change to the worker's payout for the job's pay
+// period if one exists and has not been sent, holding the worker's payout
+// lock like createPayoutForWorker and CashOut do. It returns
+// errPayoutSealed if the payout was already sent.
+func (s *PayoutService) syncUnsentPayout(ctx context.Context, ev models.JobInstanceEvent, change payoutChange) error {
+	if ev.AssignedWorkerID == nil {
+		return nil
+	}
+	sch, err := s.schedules.ForWorker(ctx, *ev.AssignedWorkerID)
+	if err != nil {
+		return err
+	}
+	periodStart, _ := sch.PeriodForDate(ev.ServiceDate)
+
+	var existing, settled *internal_models.WorkerPayout
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		existing, settled = nil, nil
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		if err := payouts.LockWorker(ctx, *ev.AssignedWorkerID); err != nil {
+			return fmt.Errorf("lock worker payouts: %w", err)
+		}
+		var err error
+		existing, err = payouts.GetScheduledByPeriod(ctx, *ev.AssignedWorkerID, periodStart)
+		if err != nil || existing == nil {
+			// No payout for the period, so nothing to keep in step.
+			return err
+		}
+		err = payouts.UpdateWithRetry(ctx, existing.ID, func(p *internal_models.WorkerPayout) error {
+			settled = nil
+			if p.Status != internal_models.PayoutStatusPending && p.Status != internal_models.PayoutStatusFailed {
+				return errPayoutSealed
+			}
+			changed, err := change(ctx, payouts, p)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				return errPayoutUnchanged
+			}
+			if p.AmountCents <= constants.MinimumPayoutAmountCents {
+				settleBelowMinimum(p)
+				settled = p
+			}
+			return nil
+		})
+		if err != nil || settled == nil {
+			return err
+		}
+		return carryForward(ctx, internal_repositories.NewWorkerAdjustmentRepository(w), settled,
+			fmt.Sprintf("Balance carried forward from payout %s", settled.ID))
+	})
+	switch {
+	case errors.Is(err, errPayoutUnchanged):
+		return nil
+	case errors.Is(err, errPayoutSealed):
+		return err
+	case err != nil:
+		return err
+	case existing == nil:
+		return nil
+	}
+	if settled != nil {
+		utils.Logger.Infof("Payout %s settled at %s for %s job %s; carried forward", existing.ID, models.USD(settled.AmountCents), ev.Status, ev.InstanceID)
+		return nil
+	}
+	utils.Logger.Infof("Payout %s updated for %s job %s", existing.ID, ev.Status, ev.InstanceID)
+	return nil
+}
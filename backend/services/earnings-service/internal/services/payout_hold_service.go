@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+const maxHoldListLimit = 200
+
+/*
+PayoutHoldService keeps the pay of jobs under review out of payouts and
+cash-outs.
+
+Completed jobs are screened against the payout_hold_rules flag whenever
+they are about to be paid or cashed out, and the first time one matches a
+rule it is held. Ops can also hold a job by hand for a property manager's
+dispute or a fraud signal, and release or reject every hold. A job with no
+hold is screened again next time, so a flag set later still holds it; a
+released job is not held by the rules again.
+*/
+type PayoutHoldService struct {
+	cfg         *config.Config
+	jobInstRepo repositories.JobInstanceRepository
+	holdRepo    internal_repositories.PayoutHoldRepository
+	uow         *repositories.UnitOfWork
+}
+
+func NewPayoutHoldService(cfg *config.Config, jobInstRepo repositories.JobInstanceRepository, holdRepo internal_repositories.PayoutHoldRepository, uow *repositories.UnitOfWork) *PayoutHoldService {
+	return &PayoutHoldService{cfg: cfg, jobInstRepo: jobInstRepo, holdRepo: holdRepo, uow: uow}
+}
+
+// Screen returns the hold on each of the jobs that has one, first holding
+// any job without one that matches a hold rule.
+func (s *PayoutHoldService) Screen(ctx context.Context, jobs []*models.JobInstance) (map[uuid.UUID]*internal_models.PayoutHold, error) {
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	holds, err := s.holdRepo.ByJobs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load payout holds: %w", err)
+	}
+
+	rules := s.cfg.LDFlag_PayoutHoldRules
+	if rules == nil || !rules.Enabled {
+		return holds, nil
+	}
+	var unscreened []uuid.UUID
+	for _, j := range jobs {
+		if holds[j.ID] == nil {
+			unscreened = append(unscreened, j.ID)
+		}
+	}
+	if len(unscreened) == 0 {
+		return holds, nil
+	}
+	var failed map[uuid.UUID]bool
+	if rules.FailedVerification {
+		if failed, err = s.holdRepo.FailedVerificationJobIDs(ctx, unscreened); err != nil {
+			return nil, fmt.Errorf("check unit verifications: %w", err)
+		}
+	}
+
+	for _, j := range jobs {
+		if holds[j.ID] != nil || j.AssignedWorkerID == nil {
+			continue
+		}
+		reason, details, ok := matchHoldRule(rules, j, failed[j.ID])
+		if !ok {
+			continue
+		}
+		h := &internal_models.PayoutHold{
+			JobInstanceID: j.ID,
+			WorkerID:      *j.AssignedWorkerID,
+			Reason:        reason,
+			Details:       details,
+		}
+		created, err := s.holdRepo.Create(ctx, h)
+		if err != nil {
+			return nil, fmt.Errorf("hold job %s: %w", j.ID, err)
+		}
+		if !created {
+			// Held concurrently; pick up whatever is there now.
+			existing, err := s.holdRepo.ByJobs(ctx, []uuid.UUID{j.ID})
+			if err != nil {
+				return nil, fmt.Errorf("load payout hold: %w", err)
+			}
+			h = existing[j.ID]
+		} else {
+			utils.Logger.Infof("Held pay for job %s of worker %s: %s", j.ID, h.WorkerID, reason)
+		}
+		if h != nil {
+			holds[j.ID] = h
+		}
+	}
+	return holds, nil
+}
+
+// ScreenOne is Screen for a single job. It returns nil when the job isn't
+// held.
+func (s *PayoutHoldService) ScreenOne(ctx context.Context, job *models.JobInstance) (*internal_models.PayoutHold, error) {
+	holds, err := s.Screen(ctx, []*models.JobInstance{job})
+	if err != nil {
+		return nil, err
+	}
+	return holds[job.ID], nil
+}
+
+// matchHoldRule returns the first rule the job breaks.
+func matchHoldRule(rules *internal_models.PayoutHoldRules, j *models.JobInstance, failedVerification bool) (internal_models.HoldReasonType, string, bool) {
+	switch {
+	case rules.FlaggedForReview && j.FlaggedForReview:
+		return internal_models.HoldReasonFlaggedForReview, "Job was flagged for review.", true
+	case rules.AgentCompleted && j.CompletedByAgentID != nil:
+		return internal_models.HoldReasonAgentCompleted, fmt.Sprintf("Job was completed by agent %s.", *j.CompletedByAgentID), true
+	case rules.FailedVerification && failedVerification:
+		return internal_models.HoldReasonFailedVerification, "A unit permanently failed photo verification.", true
+	}
+	if rules.MinMinutesOnSite > 0 && j.CheckInAt != nil && j.CheckOutAt != nil {
+		onSite := j.CheckOutAt.Sub(*j.CheckInAt)
+		if onSite < time.Duration(rules.MinMinutesOnSite)*time.Minute {
+			return internal_models.HoldReasonFraudSignal,
+				fmt.Sprintf("Checked out %d minutes after check-in; at least %d expected.", int(onSite.Minutes()), rules.MinMinutesOnSite), true
+		}
+	}
+	return "", "", false
+}
+
+// payPeriodFor returns the pay period a job is paid in. A job released
+// from a hold after its period closed is paid in the period it was
+// released in.
+func payPeriodFor(sch *internal_models.PaySchedule, job *models.JobInstance, hold *internal_models.PayoutHold) (time.Time, time.Time) {
+	start, end := sch.PeriodForDate(job.ServiceDate)
+	if hold != nil && hold.Status == internal_models.HoldStatusReleased && hold.ResolvedAt != nil && !hold.ResolvedAt.Before(end) {
+		return sch.PeriodAt(*hold.ResolvedAt)
+	}
+	return start, end
+}
+
+// releasedJobsSince returns the completed jobs released from a hold at or
+// after since, for payout aggregation to pick up jobs whose service dates
+// are older than it looks.
+func (s *PayoutHoldService) releasedJobsSince(ctx context.Context, since time.Time) ([]*models.JobInstance, error) {
+	released, err := s.holdRepo.ListReleasedSince(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("list released holds: %w", err)
+	}
+	var jobs []*models.JobInstance
+	for _, h := range released {
+		job, err := s.jobInstRepo.GetByID(ctx, h.JobInstanceID)
+		if err != nil {
+			return nil, fmt.Errorf("load released job %s: %w", h.JobInstanceID, err)
+		}
+		if job != nil && job.Status == models.InstanceStatusCompleted && job.AssignedWorkerID != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// ----------------------------------------------------------------
+// Ops
+// ----------------------------------------------------------------
+
+// List returns holds newest first. Empty status lists the ones still held.
+func (s *PayoutHoldService) List(
+	ctx context.Context,
+	workerID *uuid.UUID,
+	status internal_models.HoldStatusType,
+	limit int,
+) (*dtos.HoldListResponse, error) {
+	if limit <= 0 || limit > maxHoldListLimit {
+		limit = maxHoldListLimit
+	}
+	if status == "" {
+		status = internal_models.HoldStatusHeld
+	}
+	list, err := s.holdRepo.List(ctx, workerID, []internal_models.HoldStatusType{status}, limit)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []*internal_models.PayoutHold{}
+	}
+	return &dtos.HoldListResponse{Holds: list}, nil
+}
+
+// Place holds a completed, unpaid job by hand. It holds the worker's payout
+// lock while it checks, so a payout or cash-out can't take the job between
+// the check and the hold.
+func (s *PayoutHoldService) Place(ctx context.Context, actorID uuid.UUID, req dtos.PlaceHoldRequest) (*internal_models.PayoutHold, error) {
+	if !req.Reason.Manual() {
+		return nil, fmt.Errorf("%w: reason must be PM_DISPUTE or FRAUD_SIGNAL", internal_utils.ErrInvalidHold)
+	}
+	job, err := s.jobInstRepo.GetByID(ctx, req.JobInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.Status != models.InstanceStatusCompleted || job.AssignedWorkerID == nil {
+		return nil, fmt.Errorf("%w: job not found or not completed", internal_utils.ErrInvalidHold)
+	}
+
+	h := &internal_models.PayoutHold{
+		JobInstanceID: job.ID,
+		WorkerID:      *job.AssignedWorkerID,
+		Reason:        req.Reason,
+		Details:       req.Details,
+		CreatedBy:     &actorID,
+	}
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		if err := payouts.LockWorker(ctx, h.WorkerID); err != nil {
+			return err
+		}
+		paidOut, err := payouts.PaidOutJobIDs(ctx, []uuid.UUID{job.ID})
+		if err != nil {
+			return err
+		}
+		if paidOut[job.ID] {
+			return fmt.Errorf("%w: job was already paid out", internal_utils.ErrInvalidHold)
+		}
+		placed, err := internal_repositories.NewPayoutHoldRepository(w).Place(ctx, h)
+		if err != nil {
+			return err
+		}
+		if !placed {
+			return internal_utils.ErrAlreadyHeld
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s held pay for job %s of worker %s: %s", actorID, job.ID, h.WorkerID, h.Reason)
+	return h, nil
+}
+
+// Release lets a held job be paid with the worker's next payout.
+func (s *PayoutHoldService) Release(ctx context.Context, actorID uuid.UUID, req dtos.ResolveHoldRequest) (*internal_models.PayoutHold, error) {
+	return s.resolve(ctx, actorID, req, internal_models.HoldStatusReleased)
+}
+
+// Reject keeps a held job from ever being paid.
+func (s *PayoutHoldService) Reject(ctx context.Context, actorID uuid.UUID, req dtos.ResolveHoldRequest) (*internal_models.PayoutHold, error) {
+	return s.resolve(ctx, actorID, req, internal_models.HoldStatusRejected)
+}
+
+func (s *PayoutHoldService) resolve(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.ResolveHoldRequest,
+	status internal_models.HoldStatusType,
+) (*internal_models.PayoutHold, error) {
+	h, err := s.holdRepo.Resolve(ctx, req.ID, status, actorID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		existing, err := s.holdRepo.GetByID(ctx, req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, internal_utils.ErrHoldNotFound
+		}
+		return nil, internal_utils.ErrHoldResolved
+	}
+	utils.Logger.Infof("Ops %s %s hold %s on job %s", actorID, strings.ToLower(string(status)), h.ID, h.JobInstanceID)
+	return h, nil
+}
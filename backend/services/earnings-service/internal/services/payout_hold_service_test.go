@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func TestMatchHoldRule(t *testing.T) {
+	agent := uuid.New()
+	checkIn := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	onSite := func(minutes int) func(*models.JobInstance) {
+		return func(j *models.JobInstance) {
+			out := checkIn.Add(time.Duration(minutes) * time.Minute)
+			j.CheckInAt, j.CheckOutAt = &checkIn, &out
+		}
+	}
+	allRules := func() *internal_models.PayoutHoldRules {
+		return &internal_models.PayoutHoldRules{Enabled: true, FlaggedForReview: true, AgentCompleted: true, FailedVerification: true, MinMinutesOnSite: 10}
+	}
+
+	for name, tc := range map[string]struct {
+		rules  func(*internal_models.PayoutHoldRules)
+		job    func(*models.JobInstance)
+		failed bool
+		want   internal_models.HoldReasonType
+	}{
+		"clean job":                  {job: onSite(45)},
+		"flagged":                    {job: func(j *models.JobInstance) { j.FlaggedForReview = true }, want: internal_models.HoldReasonFlaggedForReview},
+		"flagged, rule off":          {job: func(j *models.JobInstance) { j.FlaggedForReview = true }, rules: func(r *internal_models.PayoutHoldRules) { r.FlaggedForReview = false }},
+		"agent completed":            {job: func(j *models.JobInstance) { j.CompletedByAgentID = &agent }, want: internal_models.HoldReasonAgentCompleted},
+		"agent completed, rule off":  {job: func(j *models.JobInstance) { j.CompletedByAgentID = &agent }, rules: func(r *internal_models.PayoutHoldRules) { r.AgentCompleted = false }},
+		"failed verification":        {failed: true, want: internal_models.HoldReasonFailedVerification},
+		"failed verification, off":   {failed: true, rules: func(r *internal_models.PayoutHoldRules) { r.FailedVerification = false }},
+		"too quick on site":          {job: onSite(9), want: internal_models.HoldReasonFraudSignal},
+		"exactly the minimum":        {job: onSite(10)},
+		"too quick, rule off":        {job: onSite(1), rules: func(r *internal_models.PayoutHoldRules) { r.MinMinutesOnSite = 0 }},
+		"never checked out":          {job: func(j *models.JobInstance) { j.CheckInAt = &checkIn }},
+		"flagged wins over the rest": {job: func(j *models.JobInstance) { j.FlaggedForReview = true; onSite(1)(j) }, failed: true, want: internal_models.HoldReasonFlaggedForReview},
+	} {
+		rules := allRules()
+		if tc.rules != nil {
+			tc.rules(rules)
+		}
+		job := &models.JobInstance{ID: uuid.New()}
+		if tc.job != nil {
+			tc.job(job)
+		}
+		reason, details, ok := matchHoldRule(rules, job, tc.failed)
+		if ok != (tc.want != "") || reason != tc.want {
+			t.Errorf("%s: expected %q, got %q (held %v)", name, tc.want, reason, ok)
+		}
+		if ok && details == "" {
+			t.Errorf("%s: expected details for the hold", name)
+		}
+	}
+}
+
+func TestPayPeriodForReleasedJob(t *testing.T) {
+	sch := internal_models.DefaultPaySchedule()
+	loc := sch.Location()
+	// Wednesday, in the week of Monday 2 March 2026.
+	job := &models.JobInstance{ServiceDate: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)}
+	start, end := sch.PeriodForDate(job.ServiceDate)
+	if want := time.Date(2026, 3, 2, 4, 0, 0, 0, loc); !start.Equal(want) {
+		t.Fatalf("expected the period to start %s, got %s", want, start)
+	}
+	released := func(at time.Time) *internal_models.PayoutHold {
+		return &internal_models.PayoutHold{Status: internal_models.HoldStatusReleased, ResolvedAt: &at}
+	}
+
+	for name, tc := range map[string]struct {
+		hold  *internal_models.PayoutHold
+		start time.Time
+	}{
+		"no hold":                 {start: start},
+		"still held":              {hold: &internal_models.PayoutHold{Status: internal_models.HoldStatusHeld}, start: start},
+		"released before close":   {hold: released(end.Add(-time.Minute)), start: start},
+		"released as it closes":   {hold: released(end), start: end},
+		"released weeks later":    {hold: released(end.AddDate(0, 0, 15)), start: end.AddDate(0, 0, 14)},
+		"released before 4AM Mon": {hold: released(end.AddDate(0, 0, 7).Add(-time.Hour)), start: end},
+	} {
+		gotStart, gotEnd := payPeriodFor(sch, job, tc.hold)
+		if !gotStart.Equal(tc.start) || !gotEnd.Equal(tc.start.AddDate(0, 0, 7)) {
+			t.Errorf("%s: expected the period starting %s, got [%s, %s)", name, tc.start, gotStart, gotEnd)
+		}
+	}
+}
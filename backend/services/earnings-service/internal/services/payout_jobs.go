@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/stripe/stripe-go/v82"
+)
+
+const (
+	payoutProcessJobKind   = "payout.process"
+	balanceRecoveryJobKind = "payout.balance-recovery"
+)
+
+type payoutJob struct {
+	PayoutID uuid.UUID `json:"payout_id"`
+}
+
+type balanceRecoveryJob struct{}
+
+var errPayoutNotDue = errors.New("payout not due")
+
+// queuePayout schedules one processing attempt for a payout at runAt. The key
+// includes the retry count so that a retry queued by the running attempt is
+// not mistaken for a duplicate of it.
+func (s *PayoutService) queuePayout(ctx context.Context, id uuid.UUID, retryCount int, runAt time.Time) {
+	_, err := s.queue.Enqueue(ctx, payoutProcessJobKind, payoutJob{PayoutID: id}, utils.EnqueueOptions{
+		UniqueKey: fmt.Sprintf("payout:%s:%d", id, retryCount),
+		RunAt:     runAt,
+	})
+	if err != nil {
+		utils.Logger.WithError(err).Errorf("Failed to queue processing of payout %s; the payout cron will pick it up", id)
+	}
+}
+
+// claimPayout moves a due payout (PENDING with no send time or one that has
+// passed, or FAILED with a retry time that has passed) to PROCESSING and
+// returns it. It returns nil if the payout is not due, e.g. because the cron
+// sweep or a webhook got there first.
+func (s *PayoutService) claimPayout(ctx context.Context, id uuid.UUID) (*internal_models.WorkerPayout, error) {
+	var claimed *internal_models.WorkerPayout
+	err := s.payoutRepo.UpdateWithRetry(ctx, id, func(p *internal_models.WorkerPayout) error {
+		now := time.Now().UTC()
+		due := (p.Status == internal_models.PayoutStatusPending && (p.NextAttemptAt == nil || !p.NextAttemptAt.After(now))) ||
+			(p.Status == internal_models.PayoutStatusFailed && p.NextAttemptAt != nil && !p.NextAttemptAt.After(now))
+		if !due {
+			return errPayoutNotDue
+		}
+		p.Status = internal_models.PayoutStatusProcessing
+		p.LastAttemptAt = &now
+		claimed = p
+		return nil
+	})
+	if errors.Is(err, errPayoutNotDue) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// runPayoutJob processes one payout. Stripe failures are recorded on the
+// payout by handleFailure, which queues the next attempt itself, so only
+// database errors are returned for the queue to retry.
+func (s *PayoutService) runPayoutJob(ctx context.Context, job payoutJob) error {
+	p, err := s.claimPayout(ctx, job.PayoutID)
+	if err != nil || p == nil {
+		return err
+	}
+	if payoutErr := s.processPayout(ctx, p); payoutErr != nil {
+		utils.Logger.WithError(payoutErr).Warnf("Queued attempt for payout %s failed", p.ID)
+	}
+	return nil
+}
+
+// runBalanceRecoveryJob puts payouts that failed on an empty platform balance
+// back to PENDING and processes them. If the balance is still short it fails,
+// and the queue retries it with backoff.
+func (s *PayoutService) runBalanceRecoveryJob(ctx context.Context, _ balanceRecoveryJob) error {
+	failedPayouts, err := s.payoutRepo.FindFailedByReason(ctx, string(stripe.ErrorCodeBalanceInsufficient))
+	if err != nil {
+		return fmt.Errorf("find payouts failed for insufficient balance: %w", err)
+	}
+	if len(failedPayouts) == 0 {
+		utils.Logger.Info("No payouts found that failed due to insufficient balance. Nothing to do.")
+		return nil
+	}
+
+	utils.Logger.Infof("Found %d payouts to re-queue for processing.", len(failedPayouts))
+	if err := s.requeueFailedPayouts(ctx, failedPayouts, false); err != nil {
+		return err
+	}
+
+	err = s.ProcessPendingPayouts(ctx)
+	if errors.Is(err, internal_utils.ErrBalanceInsufficient) {
+		utils.Logger.Warn("Payout processing still failed with insufficient balance after 'balance.available' event.")
+	}
+	return err
+}
+
+// requeueFailedPayouts moves failed payouts back to PENDING, all or none, so
+// a retried webhook or job never sees a half-applied reset. Payouts that are
+// no longer FAILED are left alone. Callers queue the payouts after it returns.
+func (s *PayoutService) requeueFailedPayouts(ctx context.Context, failed []*internal_models.WorkerPayout, resetRetries bool) error {
+	return s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		payouts := internal_repositories.NewWorkerPayoutRepository(w)
+		now := time.Now().UTC()
+		for _, f := range failed {
+			err := payouts.UpdateWithRetry(ctx, f.ID, func(p *internal_models.WorkerPayout) error {
+				if p.Status != internal_models.PayoutStatusFailed {
+					return errPayoutUnchanged
+				}
+				utils.Logger.Infof("Re-queueing payout %s for worker %s.", p.ID, p.WorkerID)
+				p.Status = internal_models.PayoutStatusPending
+				p.NextAttemptAt = &now
+				if resetRetries {
+					p.RetryCount = 0
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, errPayoutUnchanged) {
+				return fmt.Errorf("re-queue payout %s: %w", f.ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/account"
+	"github.com/stripe/stripe-go/v82/payout"
+	"github.com/stripe/stripe-go/v82/transfer"
+)
+
+// PayoutRequest is one attempt to send a payout to its worker.
+type PayoutRequest struct {
+	Payout *internal_models.WorkerPayout
+	Worker *models.Worker
+}
+
+// PayoutReceipt is what a provider recorded for a payout it accepted.
+// Providers that send later, like ACH, leave the IDs nil.
+type PayoutReceipt struct {
+	StripeTransferID *string
+	StripePayoutID   *string
+}
+
+// PayoutFailure is a payout a provider could not send. Reason becomes the
+// payout's LastFailureReason and decides whether it is retried, see
+// IsFailureRecoverable. TransferID is set when money already left the
+// platform balance.
+type PayoutFailure struct {
+	Reason     string
+	TransferID *string
+	Err        error
+}
+
+func (f *PayoutFailure) Error() string {
+	if f.Err != nil {
+		return fmt.Sprintf("%s: %v", f.Reason, f.Err)
+	}
+	return f.Reason
+}
+
+func (f *PayoutFailure) Unwrap() error {
+	return f.Err
+}
+
+// PayoutState is where a sent payout stands with its provider: PROCESSING
+// while on its way, then PAID or FAILED with FailureReason.
+type PayoutState struct {
+	Status        internal_models.PayoutStatusType
+	FailureReason string
+}
+
+// PayoutProvider moves a payout's money to the worker's bank. Send starts
+// it; the final status arrives later through the provider's own channel
+// (Stripe webhooks, ACH batches and return files) or by asking Status.
+// Failures are returned as *PayoutFailure.
+type PayoutProvider interface {
+	Name() internal_models.PayoutProviderType
+	Send(ctx context.Context, req PayoutRequest) (*PayoutReceipt, error)
+	Status(ctx context.Context, p *internal_models.WorkerPayout, worker *models.Worker) (*PayoutState, error)
+}
+
+// fallbackReasons are the failures that mean Stripe can't reach the worker
+// at all, so a standard payout may go over the fallback provider instead.
+var fallbackReasons = map[string]bool{
+	constants.ReasonMissingStripeID:              true,
+	constants.ReasonAccountPayoutsDisabled:       true,
+	constants.StripeFailureCodeAccountRestricted: true,
+	string(stripe.ErrorCodePayoutsNotAllowed):    true,
+}
+
+// sendPayout sends req over primary, or over fallback (which may be nil)
+// when primary can't reach the worker before any money moved. Instant
+// payouts never fall back. It returns the provider that took the payout;
+// if neither did, the primary's failure is the one reported, as that is
+// the one the worker can fix.
+func sendPayout(ctx context.Context, primary, fallback PayoutProvider, req PayoutRequest) (*PayoutReceipt, PayoutProvider, error) {
+	receipt, err := primary.Send(ctx, req)
+	var f *PayoutFailure
+	if err == nil || fallback == nil || req.Payout.Method == internal_models.PayoutMethodInstant ||
+		!errors.As(err, &f) || f.TransferID != nil || !fallbackReasons[f.Reason] {
+		return receipt, primary, err
+	}
+	fbReceipt, fbErr := fallback.Send(ctx, req)
+	if fbErr != nil {
+		utils.Logger.WithError(fbErr).Infof("Payout %s can't go over %s either", req.Payout.ID, fallback.Name())
+		return nil, primary, err
+	}
+	utils.Logger.Infof("Payout %s for worker %s sent over %s because %s", req.Payout.ID, req.Payout.WorkerID, fallback.Name(), f.Reason)
+	return fbReceipt, fallback, nil
+}
+
+/*──────────── Stripe ────────────*/
+
+type stripePayoutProvider struct {
+	generatedBy string
+}
+
+// NewStripePayoutProvider transfers payouts to the worker's Connect account
+// and pays them out from there, with the key PayoutService set.
+func NewStripePayoutProvider(cfg *config.Config) PayoutProvider {
+	return &stripePayoutProvider{generatedBy: generatedBy(cfg)}
+}
+
+func (s *stripePayoutProvider) Name() internal_models.PayoutProviderType {
+	return internal_models.PayoutProviderStripe
+}
+
+func (s *stripePayoutProvider) Send(ctx context.Context, req PayoutRequest) (*PayoutReceipt, error) {
+	p, worker := req.Payout, req.Worker
+	if worker.StripeConnectAccountID == nil || *worker.StripeConnectAccountID == "" {
+		return nil, &PayoutFailure{Reason: constants.ReasonMissingStripeID}
+	}
+	acctID := *worker.StripeConnectAccountID
+
+	acctParams := &stripe.AccountParams{}
+	acctParams.Context = ctx
+	acct, err := account.GetByID(acctID, acctParams)
+	if err != nil {
+		return nil, stripeFailure(err, constants.ReasonUnknownStripeAccountError, nil)
+	}
+
+	utils.Logger.Debugf("Processing payout amount $%.2f for worker %s (Stripe Account: %s)",
+		float64(p.AmountCents)/100.0, worker.ID, acctID)
+
+	if !acct.PayoutsEnabled {
+		return nil, &PayoutFailure{Reason: constants.ReasonAccountPayoutsDisabled}
+	}
+
+	// --- Step 1: Transfer funds from Platform Balance to Connected Account Balance ---
+	transferParams := &stripe.TransferParams{
+		Amount:      stripe.Int64(p.AmountCents),
+		Currency:    stripe.String(string(stripe.CurrencyUSD)),
+		Destination: stripe.String(acctID),
+		Metadata: map[string]string{
+			constants.WebhookMetadataPayoutIDKey:    p.ID.String(),
+			"worker_id":                             p.WorkerID.String(),
+			constants.WebhookMetadataGeneratedByKey: s.generatedBy,
+		},
+	}
+	transferParams.Context = ctx
+	transferParams.SetIdempotencyKey(fmt.Sprintf("%s-transfer-%d", p.ID.String(), p.RetryCount))
+
+	t, err := transfer.New(transferParams)
+	if err != nil {
+		return nil, stripeFailure(err, constants.ReasonUnknownStripeTransferError, nil)
+	}
+
+	utils.Logger.Infof("Successfully created Stripe Transfer %s for payout %s", t.ID, p.ID)
+
+	// --- Step 2: Create a Payout from the Connected Account Balance to their bank ---
+	payoutParams := &stripe.PayoutParams{
+		Amount:   stripe.Int64(p.AmountCents),
+		Currency: stripe.String(string(stripe.CurrencyUSD)),
+		Metadata: map[string]string{
+			constants.WebhookMetadataPayoutIDKey:    p.ID.String(),
+			constants.WebhookMetadataGeneratedByKey: s.generatedBy,
+		},
+	}
+	if p.Method == internal_models.PayoutMethodInstant {
+		payoutParams.Method = stripe.String(string(stripe.PayoutMethodInstant))
+	}
+	payoutParams.Context = ctx
+	payoutParams.SetStripeAccount(acctID)
+	payoutParams.SetIdempotencyKey(fmt.Sprintf("%s-payout-%d", p.ID.String(), p.RetryCount))
+
+	po, err := payout.New(payoutParams)
+	if err != nil {
+		// The transfer succeeded, but the payout failed synchronously.
+		// This is a critical state. We log it and mark the payout as failed.
+		// A reversal of the transfer might be needed in a more advanced implementation.
+		utils.Logger.WithError(err).Errorf("CRITICAL: Stripe Transfer %s succeeded but Payout initiation failed for payout %s.", t.ID, p.ID)
+		return nil, stripeFailure(err, constants.ReasonPayoutInitiationFailed, &t.ID)
+	}
+
+	utils.Logger.Infof("Successfully initiated Stripe Payout %s for payout %s", po.ID, p.ID)
+	return &PayoutReceipt{StripeTransferID: &t.ID, StripePayoutID: &po.ID}, nil
+}
+
+// Status polls the Stripe payout, scoped to the worker's Connect account.
+func (s *stripePayoutProvider) Status(ctx context.Context, p *internal_models.WorkerPayout, worker *models.Worker) (*PayoutState, error) {
+	if p.StripePayoutID == nil || *p.StripePayoutID == "" {
+		return nil, errors.New("missing stripe_payout_id for processing payout")
+	}
+	if worker.StripeConnectAccountID == nil || *worker.StripeConnectAccountID == "" {
+		return nil, errors.New("missing stripe connect account id for reconciliation")
+	}
+	params := &stripe.PayoutParams{}
+	params.Context = ctx
+	params.SetStripeAccount(*worker.StripeConnectAccountID)
+	po, err := payout.Get(*p.StripePayoutID, params)
+	if err != nil {
+		return nil, err
+	}
+	switch po.Status {
+	case stripe.PayoutStatusPaid:
+		return &PayoutState{Status: internal_models.PayoutStatusPaid}, nil
+	case stripe.PayoutStatusFailed:
+		return &PayoutState{Status: internal_models.PayoutStatusFailed, FailureReason: string(po.FailureCode)}, nil
+	case stripe.PayoutStatusCanceled:
+		return &PayoutState{Status: internal_models.PayoutStatusFailed, FailureReason: constants.ReasonStripePayoutCanceled}, nil
+	}
+	return &PayoutState{Status: internal_models.PayoutStatusProcessing}, nil
+}
+
+// stripeFailure takes the reason from a Stripe error's code, or uses
+// fallback for other errors.
+func stripeFailure(err error, fallback string, transferID *string) *PayoutFailure {
+	reason := fallback
+	if se, ok := err.(*stripe.Error); ok {
+		reason = string(se.Code)
+	}
+	return &PayoutFailure{Reason: reason, TransferID: transferID, Err: err}
+}
+
+/*──────────── fake ────────────*/
+
+// FakePayoutProvider is an in-memory provider for tests. Each payout sent
+// is recorded; Fail makes the next sends fail with a reason and SetStatus
+// decides what Status reports (PROCESSING by default).
+type FakePayoutProvider struct {
+	Kind internal_models.PayoutProviderType
+
+	mu       sync.Mutex
+	failWith string
+	sent     []PayoutRequest
+	statuses map[uuid.UUID]PayoutState
+}
+
+func (f *FakePayoutProvider) Name() internal_models.PayoutProviderType {
+	if f.Kind == "" {
+		return internal_models.PayoutProviderStripe
+	}
+	return f.Kind
+}
+
+// Fail makes sends fail with reason until it is called with "".
+func (f *FakePayoutProvider) Fail(reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failWith = reason
+}
+
+func (f *FakePayoutProvider) SetStatus(payoutID uuid.UUID, st PayoutState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.statuses == nil {
+		f.statuses = make(map[uuid.UUID]PayoutState)
+	}
+	f.statuses[payoutID] = st
+}
+
+// Sent returns the requests the provider accepted, in order.
+func (f *FakePayoutProvider) Sent() []PayoutRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]PayoutRequest(nil), f.sent...)
+}
+
+func (f *FakePayoutProvider) Send(_ context.Context, req PayoutRequest) (*PayoutReceipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failWith != "" {
+		return nil, &PayoutFailure{Reason: f.failWith}
+	}
+	f.sent = append(f.sent, req)
+	if f.Name() != internal_models.PayoutProviderStripe {
+		return &PayoutReceipt{}, nil
+	}
+	tr, po := "tr_fake_"+req.Payout.ID.String(), "po_fake_"+req.Payout.ID.String()
+	return &PayoutReceipt{StripeTransferID: &tr, StripePayoutID: &po}, nil
+}
+
+func (f *FakePayoutProvider) Status(_ context.Context, p *internal_models.WorkerPayout, _ *models.Worker) (*PayoutState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if st, ok := f.statuses[p.ID]; ok {
+		return &st, nil
+	}
+	return &PayoutState{Status: internal_models.PayoutStatusProcessing}, nil
+}
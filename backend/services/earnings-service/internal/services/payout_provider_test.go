@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func testPayoutRequest(method internal_models.PayoutMethodType) PayoutRequest {
+	p := testPayout(internal_models.PayoutStatusProcessing, 4000)
+	p.Method = method
+	return PayoutRequest{Payout: p, Worker: &models.Worker{ID: testWorkerID}}
+}
+
+func TestSendPayoutFallsBackWhenStripeCantReachWorker(t *testing.T) {
+	ctx := context.Background()
+	stripe := &FakePayoutProvider{}
+	ach := &FakePayoutProvider{Kind: internal_models.PayoutProviderACH}
+
+	receipt, used, err := sendPayout(ctx, stripe, ach, testPayoutRequest(internal_models.PayoutMethodStandard))
+	if err != nil || used != stripe || receipt.StripePayoutID == nil {
+		t.Fatalf("expected Stripe to send it, got %v %v %+v", used, err, receipt)
+	}
+
+	stripe.Fail(constants.ReasonMissingStripeID)
+	receipt, used, err = sendPayout(ctx, stripe, ach, testPayoutRequest(internal_models.PayoutMethodStandard))
+	if err != nil || used != ach || receipt.StripePayoutID != nil {
+		t.Fatalf("expected ACH to take it, got %v %v %+v", used, err, receipt)
+	}
+	if len(ach.Sent()) != 1 {
+		t.Fatalf("expected one ACH send, got %d", len(ach.Sent()))
+	}
+
+	// Instant payouts need Stripe.
+	_, used, err = sendPayout(ctx, stripe, ach, testPayoutRequest(internal_models.PayoutMethodInstant))
+	if err == nil || used != stripe {
+		t.Fatalf("expected the instant payout to fail over Stripe, got %v %v", used, err)
+	}
+	// Nor is there a fallback without a fallback provider.
+	if _, _, err = sendPayout(ctx, stripe, nil, testPayoutRequest(internal_models.PayoutMethodStandard)); err == nil {
+		t.Fatal("expected the payout to fail without a fallback")
+	}
+}
+
+func TestSendPayoutKeepsOtherFailures(t *testing.T) {
+	ctx := context.Background()
+	ach := &FakePayoutProvider{Kind: internal_models.PayoutProviderACH}
+
+	// A transfer already went out, so a second route would pay twice.
+	transferred := &transferFailProvider{}
+	_, used, err := sendPayout(ctx, transferred, ach, testPayoutRequest(internal_models.PayoutMethodStandard))
+	var f *PayoutFailure
+	if !errors.As(err, &f) || f.TransferID == nil || used != transferred || len(ach.Sent()) != 0 {
+		t.Fatalf("expected the transfer failure without a fallback, got %v %v", used, err)
+	}
+
+	// When ACH can't pay either, the worker sees Stripe's reason.
+	stripe := &FakePayoutProvider{}
+	stripe.Fail(constants.ReasonAccountPayoutsDisabled)
+	ach.Fail(constants.ReasonMissingBankAccount)
+	_, _, err = sendPayout(ctx, stripe, ach, testPayoutRequest(internal_models.PayoutMethodStandard))
+	if !errors.As(err, &f) || f.Reason != constants.ReasonAccountPayoutsDisabled {
+		t.Fatalf("expected Stripe's failure, got %v", err)
+	}
+}
+
+func TestACHReturnReason(t *testing.T) {
+	if got := achReturnReason("R02"); got != "account_closed" {
+		t.Fatalf("expected R02 to map to account_closed, got %q", got)
+	}
+	if got := achReturnReason("R85"); got != constants.ACHReturnReasonPrefix+"R85" {
+		t.Fatalf("expected an unmapped code to keep its code, got %q", got)
+	}
+}
+
+// transferFailProvider fails after moving money, the way a Stripe payout
+// fails once its transfer went through.
+type transferFailProvider struct{ FakePayoutProvider }
+
+func (p *transferFailProvider) Send(context.Context, PayoutRequest) (*PayoutReceipt, error) {
+	tr := "tr_" + uuid.NewString()
+	return nil, &PayoutFailure{Reason: constants.ReasonAccountPayoutsDisabled, TransferID: &tr}
+}
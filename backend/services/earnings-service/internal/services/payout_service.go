@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -25,10 +26,6 @@ import (
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/stripe/stripe-go/v82"
-	"github.com/stripe/stripe-go/v82/account"
-	"github.com/stripe/stripe-go/v82/balancetransaction"
-	"github.com/stripe/stripe-go/v82/payout"
-	"github.com/stripe/stripe-go/v82/transfer"
 	"github.com/stripe/stripe-go/v82/webhookendpoint"
 )
 
@@ -60,7 +57,7 @@ p { margin-bottom: 15px; }
 <div class="container">
 <p class="header">Action Required: Your Payout Failed</p>
 <p>Hi %s,</p>
-<p>We were unable to process your payout of <strong>$%.2f</strong> for the week of %s. This was due to an issue with your connected bank account.</p>
+<p>We were unable to process your payout of <strong>$%.2f</strong> for the pay period starting %s. This was due to an issue with your connected bank account.</p>
 <p><strong>Reason:</strong> %s</p>
 <p>To ensure you receive your earnings, please update your payout information in the Stripe Express Dashboard by clicking the button below.</p>
 <div class="button-container">
@@ -117,71 +114,108 @@ type PayoutService struct {
 	cfg                   *config.Config
 	workerRepo            repositories.WorkerRepository
 	jobInstRepo           repositories.JobInstanceRepository
+	payItemRepo           repositories.JobPayItemRepository
 	payoutRepo            internal_repositories.WorkerPayoutRepository
-	sendgridClient        *sendgrid.Client
+	adjustmentRepo        internal_repositories.WorkerAdjustmentRepository
+	schedules             *PayScheduleService
+	holds                 *PayoutHoldService
+	uow                   *repositories.UnitOfWork
+	notifier              *utils.Notifier
+	queue                 *utils.JobQueue
+	provider              PayoutProvider
+	fallback              PayoutProvider // nil when there is none
+	ledger                StripeLedger
 	generatedBy           string
 	webhookPlatformID     string
 	webhookConnectID      string
 	webhookPlatformSecret string
 	webhookConnectSecret  string
 	mu                    sync.Mutex
-	recoveryMu            sync.Mutex
 }
 
-func NewPayoutService(cfg *config.Config, workerRepo repositories.WorkerRepository, jobInstRepo repositories.JobInstanceRepository, payoutRepo internal_repositories.WorkerPayoutRepository) *PayoutService {
+func NewPayoutService(cfg *config.Config, workerRepo repositories.WorkerRepository, jobInstRepo repositories.JobInstanceRepository, payItemRepo repositories.JobPayItemRepository, payoutRepo internal_repositories.WorkerPayoutRepository, adjustmentRepo internal_repositories.WorkerAdjustmentRepository, schedules *PayScheduleService, holds *PayoutHoldService, uow *repositories.UnitOfWork, queue *utils.JobQueue, provider PayoutProvider, fallback PayoutProvider, ledger StripeLedger) *PayoutService {
 	stripe.Key = cfg.StripeSecretKey
-	generated := fmt.Sprintf("%s-%s-%s", cfg.AppName, cfg.UniqueRunnerID, cfg.UniqueRunNumber)
-	return &PayoutService{
+	s := &PayoutService{
 		cfg:            cfg,
 		workerRepo:     workerRepo,
 		jobInstRepo:    jobInstRepo,
+		payItemRepo:    payItemRepo,
 		payoutRepo:     payoutRepo,
-		sendgridClient: sendgrid.NewSendClient(cfg.SendgridAPIKey),
-		generatedBy:    generated,
+		adjustmentRepo: adjustmentRepo,
+		schedules:      schedules,
+		holds:          holds,
+		uow:            uow,
+		notifier:       utils.NewNotifier(queue, sendgrid.NewSendClient(cfg.SendgridAPIKey), nil),
+		queue:          queue,
+		provider:       provider,
+		fallback:       fallback,
+		ledger:         ledger,
+		generatedBy:    generatedBy(cfg),
+	}
+	utils.RegisterJobHandler(queue, payoutProcessJobKind, s.runPayoutJob)
+	utils.RegisterJobHandler(queue, balanceRecoveryJobKind, s.runBalanceRecoveryJob)
+	return s
+}
+
+// generatedBy tags what this instance sends to Stripe, so webhooks and
+// reconciliation can tell it from other environments' payouts.
+func generatedBy(cfg *config.Config) string {
+	return fmt.Sprintf("%s-%s-%s", cfg.AppName, cfg.UniqueRunnerID, cfg.UniqueRunNumber)
+}
+
+// providerFor returns the provider that last sent p.
+func (s *PayoutService) providerFor(p *internal_models.WorkerPayout) PayoutProvider {
+	if s.fallback != nil && p.Provider == s.fallback.Name() {
+		return s.fallback
 	}
+	return s.provider
 }
 
-// NEW: ReconcileStalePayout polls Stripe for a payout's status and updates the local DB.
+// ReconcileStalePayout asks the payout's provider for its status and updates
+// the local DB, in case the webhook that should have finished it was missed.
 func (s *PayoutService) ReconcileStalePayout(ctx context.Context, p *internal_models.WorkerPayout) (*internal_models.WorkerPayout, error) {
-    if p.StripePayoutID == nil || *p.StripePayoutID == "" {
-        // Cannot reconcile without a Stripe Payout ID. This is an inconsistent state.
-        utils.Logger.Warnf("Payout %s is in PROCESSING state but has no Stripe Payout ID. Cannot reconcile.", p.ID)
-        return p, errors.New("missing stripe_payout_id for processing payout")
-    }
-
-    // Look up the worker to scope the Stripe call to the correct Connect account.
-    worker, err := s.workerRepo.GetByID(ctx, p.WorkerID)
-    if err != nil || worker == nil || worker.StripeConnectAccountID == nil || *worker.StripeConnectAccountID == "" {
-        utils.Logger.WithError(err).Warnf("Cannot reconcile payout %s: missing worker or connect account id (worker=%s)", p.ID, p.WorkerID)
-        return p, errors.New("missing stripe connect account id for reconciliation")
-    }
-
-    utils.Logger.Infof("Polling Stripe (Connect acct=%s) for status of stale payout %s (Stripe ID: %s)", *worker.StripeConnectAccountID, p.ID, *p.StripePayoutID)
-    poParams := &stripe.PayoutParams{}
-    poParams.SetStripeAccount(*worker.StripeConnectAccountID)
-    stripePayout, err := payout.Get(*p.StripePayoutID, poParams)
-    if err != nil {
-        utils.Logger.WithError(err).Errorf("Failed to retrieve payout %s from Stripe during reconciliation", *p.StripePayoutID)
-        return p, err
-    }
-
-    // If Stripe still reports a non-final state, keep local PROCESSING and return.
-    if stripePayout.Status == stripe.PayoutStatusPending || stripePayout.Status == stripe.PayoutStatusInTransit {
-        return p, nil
-    }
-
-    // If Stripe reports a final state different from our PROCESSING, a webhook was likely missed.
-    if stripePayout.Status == stripe.PayoutStatusPaid || stripePayout.Status == stripe.PayoutStatusFailed || stripePayout.Status == stripe.PayoutStatusCanceled {
-        s.sendWebhookMissAlert("Stripe Payout", stripePayout.ID, p.WorkerID)
-        // Reuse the webhook handler to apply the authoritative state transition.
-        if err := s.HandlePayoutEvent(ctx, stripePayout); err != nil {
-            utils.Logger.WithError(err).Errorf("Failed to self-heal state for reconciled payout %s", p.ID)
-            return p, err
-        }
-    }
-
-    // Re-fetch the payout from our DB to return the latest state.
-    return s.payoutRepo.GetByID(ctx, p.ID)
+	worker, err := s.workerRepo.GetByID(ctx, p.WorkerID)
+	if err != nil || worker == nil {
+		utils.Logger.WithError(err).Warnf("Cannot reconcile payout %s: missing worker (worker=%s)", p.ID, p.WorkerID)
+		return p, errors.New("missing worker for reconciliation")
+	}
+
+	provider := s.providerFor(p)
+	utils.Logger.Infof("Polling %s for status of stale payout %s", provider.Name(), p.ID)
+	st, err := provider.Status(ctx, p, worker)
+	if err != nil {
+		utils.Logger.WithError(err).Errorf("Failed to retrieve payout %s from %s during reconciliation", p.ID, provider.Name())
+		return p, err
+	}
+
+	// If the provider still reports a non-final state, keep local PROCESSING and return.
+	if st.Status != internal_models.PayoutStatusPaid && st.Status != internal_models.PayoutStatusFailed {
+		return p, nil
+	}
+
+	// The provider reports a final state different from our PROCESSING, so a webhook was likely missed.
+	ref := p.ID.String()
+	if p.StripePayoutID != nil {
+		ref = *p.StripePayoutID
+	}
+	s.sendWebhookMissAlert(fmt.Sprintf("%s payout", provider.Name()), ref, p.WorkerID)
+	if st.Status == internal_models.PayoutStatusFailed {
+		s.handleFailure(ctx, p, st.FailureReason, p.StripeTransferID)
+	} else {
+		err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
+			if payoutToUpdate.Status == internal_models.PayoutStatusProcessing {
+				payoutToUpdate.Status = internal_models.PayoutStatusPaid
+			}
+			return nil
+		})
+		if err != nil {
+			utils.Logger.WithError(err).Errorf("Failed to self-heal state for reconciled payout %s", p.ID)
+			return p, err
+		}
+	}
+
+	// Re-fetch the payout from our DB to return the latest state.
+	return s.payoutRepo.GetByID(ctx, p.ID)
 }
 
 func (s *PayoutService) PlatformWebhookSecret() string {
@@ -369,92 +403,292 @@ func (s *PayoutService) removeOldestStripeEndpoint(ctx context.Context, targetUR
 	return fmt.Errorf("could not free a webhook slot; all candidates were deleted by other processes")
 }
 
+/*
+AggregateAndCreatePayouts writes each worker's payout for every pay period of
+theirs that has closed without one. Periods close at different times on
+different schedules, so it runs hourly and looks back PayoutCatchUpDays,
+which also makes up for missed runs. A period that already has its payout is
+left alone; jobs completed for it afterwards are handled by the job events.
+Each new payout is queued to be sent PayoutDelayHours after its period
+closes. Jobs on hold are left out until released (see PayoutHoldService).
+*/
 func (s *PayoutService) AggregateAndCreatePayouts(ctx context.Context) error {
-	var payPeriodStart, payPeriodEnd, weekStartDate, weekEndDate time.Time
-	loc, _ := time.LoadLocation(constants.BusinessTimezone)
-
-	if s.cfg.LDFlag_UseShortPayPeriod {
-		utils.Logger.Info("Using short pay period (1 day) for payout aggregation.")
-		nowInLoc := time.Now().In(loc)
-		todayStart := time.Date(nowInLoc.Year(), nowInLoc.Month(), nowInLoc.Day(), 0, 0, 0, 0, loc)
-		payPeriodStart = todayStart.AddDate(0, 0, -1) // Yesterday
-		payPeriodEnd = todayStart                     // Range is [start, end)
-
-		weekStartDate = payPeriodStart.UTC()
-		weekEndDate = weekStartDate // For daily payouts, start and end are the same.
-	} else {
-		now := time.Now().In(loc)
-
-		// Determine the exact pay period to aggregate.
-		// This is always the one *before* the period the current time falls into.
-		thisWeekPayPeriodStart := internal_utils.GetPayPeriodStartForDate(now)
-		prevWeekPayPeriodStart := thisWeekPayPeriodStart.AddDate(0, 0, -constants.DaysInWeek)
-
-		// The period to query runs from Monday 4AM to the following Monday 4AM.
-		payPeriodStart = time.Date(prevWeekPayPeriodStart.Year(), prevWeekPayPeriodStart.Month(), prevWeekPayPeriodStart.Day(), constants.PayPeriodStartHourEST, 0, 0, 0, loc)
-		payPeriodEnd = payPeriodStart.AddDate(0, 0, constants.DaysInWeek)
-
-		// The database key is the Monday date that starts the pay period.
-		weekStartDate = prevWeekPayPeriodStart
-		weekEndDate = weekStartDate.AddDate(0, 0, 6)
-	}
-
-	utils.Logger.Infof("Aggregating payouts for pay period: %s to %s", payPeriodStart.Format(time.RFC3339), payPeriodEnd.Format(time.RFC3339))
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -constants.PayoutCatchUpDays)
+	utils.Logger.Infof("Aggregating payouts for pay periods closed between %s and %s", from.Format(time.RFC3339), now.Format(time.RFC3339))
 
 	statuses := []models.InstanceStatusType{models.InstanceStatusCompleted}
-	jobs, err := s.jobInstRepo.ListInstancesByDateRange(ctx, nil, statuses, payPeriodStart, payPeriodEnd)
+	jobs, err := s.jobInstRepo.ListInstancesByDateRange(ctx, nil, statuses, from, now)
 	if err != nil {
 		return fmt.Errorf("could not fetch jobs for payout aggregation: %w", err)
 	}
-
-	type workerEarnings struct {
-		amountCents int64
-		jobIDs      []uuid.UUID
+	// A job released from a hold may have been done before the lookback.
+	released, err := s.holds.releasedJobsSince(ctx, from)
+	if err != nil {
+		return fmt.Errorf("could not fetch released jobs for payout aggregation: %w", err)
+	}
+	for _, job := range released {
+		if !slices.ContainsFunc(jobs, func(j *models.JobInstance) bool { return j.ID == job.ID }) {
+			jobs = append(jobs, job)
+		}
 	}
-	earningsByWorker := make(map[uuid.UUID]workerEarnings)
 
+	jobIDs := make([]uuid.UUID, 0, len(jobs))
 	for _, job := range jobs {
 		if job.AssignedWorkerID != nil {
-			workerID := *job.AssignedWorkerID
-			current := earningsByWorker[workerID]
-			current.amountCents += int64(job.EffectivePay * 100)
-			current.jobIDs = append(current.jobIDs, job.ID)
-			earningsByWorker[workerID] = current
+			jobIDs = append(jobIDs, job.ID)
+		}
+	}
+	paidOut, err := s.payoutRepo.PaidOutJobIDs(ctx, jobIDs)
+	if err != nil {
+		return fmt.Errorf("could not check for paid-out jobs: %w", err)
+	}
+	var unpaid []*models.JobInstance
+	for _, job := range jobs {
+		if job.AssignedWorkerID != nil && !paidOut[job.ID] {
+			unpaid = append(unpaid, job)
 		}
 	}
+	// Jobs under review wait for ops to release them.
+	holds, err := s.holds.Screen(ctx, unpaid)
+	if err != nil {
+		return fmt.Errorf("could not screen jobs for payout holds: %w", err)
+	}
+	pay, err := s.payItemRepo.TotalsByInstances(ctx, jobIDs)
+	if err != nil {
+		return fmt.Errorf("could not total pay ledgers for payout aggregation: %w", err)
+	}
+
+	// Approved adjustments are paid with the worker's next payout, so a
+	// worker with only adjustments still gets one for their last closed
+	// period.
+	adjWorkerIDs, err := s.adjustmentRepo.ListWorkerIDsWithApproved(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list workers with approved adjustments: %w", err)
+	}
+
+	workerIDs := slices.Clone(adjWorkerIDs)
+	for _, job := range unpaid {
+		if !holds[job.ID].Blocks() {
+			workerIDs = append(workerIDs, *job.AssignedWorkerID)
+		}
+	}
+	slices.SortFunc(workerIDs, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	workerIDs = slices.Compact(workerIDs)
+	schedules, err := s.schedules.ForWorkers(ctx, workerIDs)
+	if err != nil {
+		return fmt.Errorf("could not resolve pay schedules: %w", err)
+	}
 
-	for workerID, earnings := range earningsByWorker {
-		if earnings.amountCents <= constants.MinimumPayoutAmountCents {
+	type periodKey struct {
+		workerID uuid.UUID
+		start    time.Time
+	}
+	periods := make(map[periodKey]*payPeriod)
+	for _, job := range unpaid {
+		if holds[job.ID].Blocks() {
 			continue
 		}
+		sch := schedules[*job.AssignedWorkerID]
+		start, end := payPeriodFor(sch, job, holds[job.ID])
+		if end.After(now) || start.Before(from) {
+			continue // still open, or too old to be this run's business
+		}
+		key := periodKey{*job.AssignedWorkerID, start}
+		if periods[key] == nil {
+			periods[key] = &payPeriod{schedule: sch, start: start, end: end}
+		}
+		periods[key].jobIDs = append(periods[key].jobIDs, job.ID)
+	}
+	for _, workerID := range adjWorkerIDs {
+		sch := schedules[workerID]
+		current, _ := sch.PeriodAt(now)
+		start, end := sch.PeriodAt(current.Add(-time.Minute))
+		key := periodKey{workerID, start}
+		if periods[key] == nil {
+			periods[key] = &payPeriod{schedule: sch, start: start, end: end}
+		}
+	}
+
+	// Oldest periods first, so approved adjustments go with the first payout.
+	keys := make([]periodKey, 0, len(periods))
+	for key := range periods {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].start.Before(keys[j].start) })
 
-		existing, err := s.payoutRepo.GetByWorkerAndWeek(ctx, workerID, weekStartDate)
+	for _, key := range keys {
+		period := periods[key]
+		var created *internal_models.WorkerPayout
+		err := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+			var err error
+			created, err = s.createPayoutForWorker(ctx, w, key.workerID, period, pay)
+			return err
+		})
 		if err != nil {
-			utils.Logger.WithError(err).Warnf("Could not check for existing payout for worker %s", workerID)
+			utils.Logger.WithError(err).Errorf("Failed to create payout record for worker %s", key.workerID)
 			continue
 		}
-		if existing != nil {
-			continue
+		if created != nil && created.Status == internal_models.PayoutStatusPending {
+			s.queuePayout(ctx, created.ID, 0, *created.NextAttemptAt)
 		}
+	}
+	return nil
+}
 
-		payout := &internal_models.WorkerPayout{
-			ID:             uuid.New(),
-			WorkerID:       workerID,
-			WeekStartDate:  weekStartDate,
-			WeekEndDate:    weekEndDate,
-			AmountCents:    earnings.amountCents,
-			Status:         internal_models.PayoutStatusPending,
-			JobInstanceIDs: earnings.jobIDs,
+// payPeriod is one closed period of a worker's pay schedule and the unpaid
+// jobs in it.
+type payPeriod struct {
+	schedule   *internal_models.PaySchedule
+	start, end time.Time
+	jobIDs     []uuid.UUID
+}
+
+// payScheduleRef is the schedule to record on a payout; nil for the unstored
+// daily schedule.
+func payScheduleRef(sch *internal_models.PaySchedule) *uuid.UUID {
+	if sch.ID == uuid.Nil {
+		return nil
+	}
+	id := sch.ID
+	return &id
+}
+
+/*
+createPayoutForWorker writes the worker's payout for the period: their job
+earnings plus every approved adjustment not yet paid. Jobs the worker already
+cashed out are left out, under the worker's payout lock so a concurrent
+cash-out can't take them too. Adjustments are marked APPLIED to the payout
+in the same transaction. It returns the payout written, if any.
+
+A net at or below the minimum payout is never transferred. Without both job
+earnings and adjustments nothing is written and the adjustments wait for a
+later period. With both, the payout is written settled at its net with
+nothing sent, and the net is carried forward as an approved CARRY_FORWARD
+adjustment, so a clawback never fails a transfer: the next payout recovers
+or pays the balance.
+*/
+func (s *PayoutService) createPayoutForWorker(
+	ctx context.Context,
+	w *repositories.Work,
+	workerID uuid.UUID,
+	period *payPeriod,
+	pay map[uuid.UUID]models.Money,
+) (*internal_models.WorkerPayout, error) {
+	payouts := internal_repositories.NewWorkerPayoutRepository(w)
+	adjustments := internal_repositories.NewWorkerAdjustmentRepository(w)
+
+	if err := payouts.LockWorker(ctx, workerID); err != nil {
+		return nil, fmt.Errorf("lock worker payouts: %w", err)
+	}
+	existing, err := payouts.GetScheduledByPeriod(ctx, workerID, period.start)
+	if err != nil {
+		return nil, fmt.Errorf("check for existing payout: %w", err)
+	}
+	if existing != nil {
+		return nil, nil
+	}
+
+	paidOut, err := payouts.PaidOutJobIDs(ctx, period.jobIDs)
+	if err != nil {
+		return nil, fmt.Errorf("check for cashed-out jobs: %w", err)
+	}
+	jobEarnings := models.USD(0)
+	jobIDs := make([]uuid.UUID, 0, len(period.jobIDs))
+	for _, id := range period.jobIDs {
+		if !paidOut[id] {
+			jobEarnings = jobEarnings.Add(pay[id])
+			jobIDs = append(jobIDs, id)
 		}
-		if err := s.payoutRepo.Create(ctx, payout); err != nil {
-			utils.Logger.WithError(err).Errorf("Failed to create payout record for worker %s", workerID)
-		} else {
-			utils.Logger.Infof("Created PENDING payout of %d cents for worker %s for period starting %s", earnings.amountCents, workerID, weekStartDate.Format(time.RFC3339))
+	}
+
+	pending, err := adjustments.ListApprovedForWorker(ctx, workerID)
+	if err != nil {
+		return nil, fmt.Errorf("list approved adjustments: %w", err)
+	}
+	adjTotal := models.USD(0)
+	adjIDs := make([]uuid.UUID, 0, len(pending))
+	for _, a := range pending {
+		adjTotal = adjTotal.Add(a.Amount)
+		adjIDs = append(adjIDs, a.ID)
+	}
+	net := jobEarnings.Add(adjTotal)
+	settle := net.Cents <= constants.MinimumPayoutAmountCents
+	if settle && (len(pending) == 0 || len(jobIDs) == 0) {
+		return nil, nil
+	}
+
+	sendAt := period.schedule.PayoutAt(period.end)
+	payout := &internal_models.WorkerPayout{
+		ID:              uuid.New(),
+		WorkerID:        workerID,
+		PeriodStart:     period.start,
+		PeriodEnd:       period.end,
+		PayScheduleID:   payScheduleRef(period.schedule),
+		AmountCents:     net.Cents,
+		AdjustmentCents: adjTotal.Cents,
+		Status:          internal_models.PayoutStatusPending,
+		JobInstanceIDs:  jobIDs,
+		NextAttemptAt:   &sendAt,
+	}
+	if settle {
+		settleBelowMinimum(payout)
+	}
+	if err := payouts.Create(ctx, payout); err != nil {
+		return nil, err
+	}
+	if err := adjustments.MarkApplied(ctx, adjIDs, payout.ID); err != nil {
+		return nil, fmt.Errorf("apply adjustments: %w", err)
+	}
+
+	first, _ := period.schedule.Dates(period.start, period.end)
+	if settle {
+		if net.IsZero() {
+			utils.Logger.Infof("Payout for worker %s for period starting %s nets to zero", workerID, period.start.Format(time.RFC3339))
+			return payout, nil
+		}
+		if err := carryForward(ctx, adjustments, payout, fmt.Sprintf("Balance carried forward from the pay period starting %s", first)); err != nil {
+			return nil, err
 		}
+		utils.Logger.Infof("Payout for worker %s for period starting %s nets %s; carried forward", workerID, period.start.Format(time.RFC3339), net)
+		return payout, nil
+	}
+	utils.Logger.Infof("Created PENDING payout of %s (adjustments %s) for worker %s for period starting %s, to be sent at %s",
+		net, adjTotal, workerID, period.start.Format(time.RFC3339), sendAt.Format(time.RFC3339))
+	return payout, nil
+}
+
+// settleBelowMinimum marks p as paid with nothing sent, carrying its whole
+// amount forward.
+func settleBelowMinimum(p *internal_models.WorkerPayout) {
+	p.CarriedCents = p.AmountCents
+	p.Status = internal_models.PayoutStatusPaid
+	p.NextAttemptAt = nil
+}
+
+// carryForward writes what p carried forward as an approved CARRY_FORWARD
+// adjustment, which the worker's next payout pays or recovers.
+func carryForward(ctx context.Context, adjustments internal_repositories.WorkerAdjustmentRepository, p *internal_models.WorkerPayout, reason string) error {
+	if p.CarriedCents == 0 {
+		return nil
+	}
+	carry := &internal_models.WorkerAdjustment{
+		WorkerID:        p.WorkerID,
+		Kind:            internal_models.AdjustmentKindCarryForward,
+		Amount:          models.USD(p.CarriedCents),
+		Reason:          reason,
+		RelatedPayoutID: &p.ID,
+		Status:          internal_models.AdjustmentStatusApproved,
+	}
+	if err := adjustments.Create(ctx, carry); err != nil {
+		return fmt.Errorf("carry forward balance: %w", err)
 	}
 	return nil
 }
 
+// ProcessPendingPayouts runs every payout that is due. New payouts and
+// retries are queued as they are written, so this hourly sweep only catches
+// anything a queued job missed.
 func (s *PayoutService) ProcessPendingPayouts(ctx context.Context) error {
 	utils.Logger.Info("Starting payout processing for pending payouts...")
 
@@ -472,18 +706,18 @@ func (s *PayoutService) ProcessPendingPayouts(ctx context.Context) error {
 	for _, p := range payouts {
 		utils.Logger.Debugf("Processing payout %s for worker %s (amount: $%.2f)", p.ID, p.WorkerID, float64(p.AmountCents)/100.0)
 
-		err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-			payoutToUpdate.Status = internal_models.PayoutStatusProcessing
-			payoutToUpdate.LastAttemptAt = utils.Ptr(time.Now().UTC())
-			return nil
-		})
-
+		claimed, err := s.claimPayout(ctx, p.ID)
 		if err != nil {
 			utils.Logger.WithError(err).Errorf("Failed to update payout %s to PROCESSING", p.ID)
 			continue
 		}
+		if claimed == nil {
+			utils.Logger.Debugf("Payout %s was picked up elsewhere; skipping", p.ID)
+			continue
+		}
 
-		payoutErr := s.processPayout(ctx, p)
+		// claimed carries the PROCESSING status and version the claim wrote.
+		payoutErr := s.processPayout(ctx, claimed)
 		if errors.Is(payoutErr, internal_utils.ErrBalanceInsufficient) {
 			utils.Logger.WithError(payoutErr).Warnf("Encountered insufficient balance for payout %s. Will continue processing other payouts.", p.ID)
 			encounteredInsufficentBalance = true
@@ -506,92 +740,30 @@ func (s *PayoutService) processPayout(ctx context.Context, p *internal_models.Wo
 		return err
 	}
 
-	if worker.StripeConnectAccountID == nil || *worker.StripeConnectAccountID == "" {
-		s.handleFailure(ctx, p, constants.ReasonMissingStripeID, nil)
-		return errors.New(constants.ReasonMissingStripeID)
-	}
-
-	acct, err := account.GetByID(*worker.StripeConnectAccountID, nil)
+	receipt, provider, err := sendPayout(ctx, s.provider, s.fallback, PayoutRequest{Payout: p, Worker: worker})
 	if err != nil {
-		if stripeErr, ok := err.(*stripe.Error); ok {
-			s.handleFailure(ctx, p, string(stripeErr.Code), nil)
-		} else {
-			s.handleFailure(ctx, p, constants.ReasonUnknownStripeAccountError, nil)
+		var f *PayoutFailure
+		if !errors.As(err, &f) {
+			f = &PayoutFailure{Reason: constants.ReasonPayoutInitiationFailed, Err: err}
 		}
-		return err
-	}
-
-	utils.Logger.Debugf("Processing payout amount $%.2f for worker %s (Stripe Account: %s)",
-		float64(p.AmountCents)/100.0, worker.ID, *worker.StripeConnectAccountID)
-
-	if !acct.PayoutsEnabled {
-		s.handleFailure(ctx, p, constants.ReasonAccountPayoutsDisabled, nil)
-		return errors.New(constants.ReasonAccountPayoutsDisabled)
-	}
-
-	// --- Step 1: Transfer funds from Platform Balance to Connected Account Balance ---
-	transferParams := &stripe.TransferParams{
-		Amount:      stripe.Int64(p.AmountCents),
-		Currency:    stripe.String(string(stripe.CurrencyUSD)),
-		Destination: stripe.String(*worker.StripeConnectAccountID),
-		Metadata: map[string]string{
-			constants.WebhookMetadataPayoutIDKey:    p.ID.String(),
-			"worker_id":                             p.WorkerID.String(),
-			constants.WebhookMetadataGeneratedByKey: s.generatedBy,
-		},
-	}
-	transferParams.SetIdempotencyKey(fmt.Sprintf("%s-transfer-%d", p.ID.String(), p.RetryCount))
-
-	t, transferErr := transfer.New(transferParams)
-	if transferErr != nil {
-		if stripeErr, ok := transferErr.(*stripe.Error); ok {
-			if stripeErr.Code == stripe.ErrorCodeBalanceInsufficient {
-				s.handleFailure(ctx, p, string(stripeErr.Code), nil)
-				return internal_utils.ErrBalanceInsufficient
-			}
-			s.handleFailure(ctx, p, string(stripeErr.Code), nil)
-		} else {
-			s.handleFailure(ctx, p, constants.ReasonUnknownStripeTransferError, nil)
-		}
-		return transferErr
-	}
-
-	utils.Logger.Infof("Successfully created Stripe Transfer %s for payout %s", t.ID, p.ID)
-
-	// --- Step 2: Create a Payout from the Connected Account Balance to their bank ---
-	payoutParams := &stripe.PayoutParams{
-		Amount:   stripe.Int64(p.AmountCents),
-		Currency: stripe.String(string(stripe.CurrencyUSD)),
-		Metadata: map[string]string{
-			constants.WebhookMetadataPayoutIDKey:    p.ID.String(),
-			constants.WebhookMetadataGeneratedByKey: s.generatedBy,
-		},
-	}
-	payoutParams.SetStripeAccount(*worker.StripeConnectAccountID)
-	payoutParams.SetIdempotencyKey(fmt.Sprintf("%s-payout-%d", p.ID.String(), p.RetryCount))
-
-	po, payoutErr := payout.New(payoutParams)
-	if payoutErr != nil {
-		// The transfer succeeded, but the payout failed synchronously.
-		// This is a critical state. We log it and mark the payout as failed.
-		// A reversal of the transfer might be needed in a more advanced implementation.
-		utils.Logger.WithError(payoutErr).Errorf("CRITICAL: Stripe Transfer %s succeeded but Payout initiation failed for payout %s.", t.ID, p.ID)
-		reason := constants.ReasonPayoutInitiationFailed
-		if stripeErr, ok := payoutErr.(*stripe.Error); ok {
-			reason = string(stripeErr.Code)
+		s.handleFailure(ctx, p, f.Reason, f.TransferID)
+		if f.Reason == string(stripe.ErrorCodeBalanceInsufficient) {
+			return internal_utils.ErrBalanceInsufficient
 		}
-		s.handleFailure(ctx, p, reason, &t.ID)
-		return payoutErr
+		return err
 	}
 
-	utils.Logger.Infof("Successfully initiated Stripe Payout %s for payout %s", po.ID, p.ID)
-
-	// --- Step 3: Update internal record with Stripe IDs.
-	// The final PAID/FAILED status will be set by a webhook.
+	// Record how the payout was sent. The final PAID/FAILED status will be
+	// set by a webhook, or for ACH by its batch and return files.
 	err = s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-		// Persist the Stripe IDs from the successful API calls.
-		payoutToUpdate.StripeTransferID = &t.ID
-		payoutToUpdate.StripePayoutID = &po.ID
+		payoutToUpdate.Provider = provider.Name()
+		if receipt.StripeTransferID != nil {
+			payoutToUpdate.StripeTransferID = receipt.StripeTransferID
+			payoutToUpdate.StripePayoutID = receipt.StripePayoutID
+		}
+		// An ACH attempt goes out in the next batch file, even if an
+		// earlier attempt was returned from one.
+		payoutToUpdate.ACHBatchID, payoutToUpdate.ACHTraceNumber = nil, nil
 
 		// CRITICAL: Only clear failure reasons if the status has NOT been changed
 		// by a concurrent webhook. If a webhook already marked this as FAILED,
@@ -603,20 +775,23 @@ func (s *PayoutService) processPayout(ctx context.Context, p *internal_models.Wo
 			payoutToUpdate.NextAttemptAt = nil
 		}
 		// If status is already FAILED or PAID, we do nothing to it.
-		// The Stripe IDs will still be saved by the update, but the authoritative
+		// The provider's IDs will still be saved by the update, but the authoritative
 		// final status from the webhook is preserved.
 		return nil
 	})
 
 	if err != nil {
-		utils.Logger.WithError(err).Errorf("CRITICAL: Stripe Transfer %s and Payout %s succeeded but failed to update internal payout %s with IDs", t.ID, po.ID, p.ID)
+		utils.Logger.WithError(err).Errorf("CRITICAL: payout %s was sent over %s but recording it failed", p.ID, provider.Name())
 	} else {
-		utils.Logger.Infof("Successfully initiated payout %s (Stripe Transfer: %s, Stripe Payout: %s). Awaiting webhook for final status.", p.ID, t.ID, po.ID)
+		utils.Logger.Infof("Successfully initiated payout %s over %s. Awaiting final status.", p.ID, provider.Name())
 	}
 	return nil
 }
 
 func (s *PayoutService) handleFailure(ctx context.Context, p *internal_models.WorkerPayout, reason string, transferID *string) {
+	var retryAt *time.Time
+	var retryCount int
+	var released bool
 	err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
 		utils.Logger.Warnf("Payout %s for worker %s failed. Reason: %s", payoutToUpdate.ID, payoutToUpdate.WorkerID, reason)
 		payoutToUpdate.Status = internal_models.PayoutStatusFailed
@@ -629,7 +804,10 @@ func (s *PayoutService) handleFailure(ctx context.Context, p *internal_models.Wo
 		if reason == string(stripe.ErrorCodeBalanceInsufficient) {
 			payoutToUpdate.NextAttemptAt = nil
 			payoutToUpdate.RetryCount++
-			utils.Logger.Warnf("Payout %s failed due to insufficient balance. It will be retried upon 'balance.available' event.", payoutToUpdate.ID)
+			released = payoutToUpdate.Kind == internal_models.PayoutKindOnDemand
+			if !released {
+				utils.Logger.Warnf("Payout %s failed due to insufficient balance. It will be retried upon 'balance.available' event.", payoutToUpdate.ID)
+			}
 			s.sendFailureNotification(ctx, payoutToUpdate, false)
 			return nil
 		}
@@ -649,16 +827,38 @@ func (s *PayoutService) handleFailure(ctx context.Context, p *internal_models.Wo
 			delay := baseRetryDelay * time.Duration(math.Pow(2, float64(payoutToUpdate.RetryCount-1)))
 			nextAttempt := time.Now().UTC().Add(delay)
 			payoutToUpdate.NextAttemptAt = &nextAttempt
+			retryAt, retryCount = &nextAttempt, payoutToUpdate.RetryCount
 			utils.Logger.Warnf("Scheduling retry #%d for payout %s at %s", payoutToUpdate.RetryCount, payoutToUpdate.ID, nextAttempt)
 		}
+		released = payoutToUpdate.Kind == internal_models.PayoutKindOnDemand && payoutToUpdate.NextAttemptAt == nil
 		return nil
 	})
 
 	if err != nil {
 		utils.Logger.WithError(err).Errorf("Failed to update payout %s after failure", p.ID)
+		return
+	}
+	if retryAt != nil {
+		s.queuePayout(ctx, p.ID, retryCount, *retryAt)
+	}
+	if released {
+		s.releaseCashOut(ctx, p)
 	}
 }
 
+// releaseCashOut hands a cash-out that failed for good back to the worker's
+// balance. Its jobs are released by the payout's status (see
+// PaidOutJobIDs); its adjustments go back to APPROVED here. A failed
+// cash-out is never resent, so the next payout can't pay them twice.
+func (s *PayoutService) releaseCashOut(ctx context.Context, p *internal_models.WorkerPayout) {
+	n, err := s.adjustmentRepo.ReleaseFromPayout(ctx, p.ID)
+	if err != nil {
+		utils.Logger.WithError(err).Errorf("CRITICAL: failed cash-out %s could not release its adjustments", p.ID)
+		return
+	}
+	utils.Logger.Infof("Failed cash-out %s for worker %s released %d jobs and %d adjustments", p.ID, p.WorkerID, len(p.JobInstanceIDs), n)
+}
+
 func (s *PayoutService) sendFailureNotification(ctx context.Context, p *internal_models.WorkerPayout, isUserFault bool) {
 	worker, err := s.workerRepo.GetByID(ctx, p.WorkerID)
 	if err != nil {
@@ -670,16 +870,21 @@ func (s *PayoutService) sendFailureNotification(ctx context.Context, p *internal
 	var subject, plainTextContent, htmlContent string
 	var to *mail.Email
 
+	periodStart := p.PeriodStart.Format("January 2, 2006")
+	if sch, err := s.schedules.ForWorker(ctx, p.WorkerID); err == nil {
+		periodStart = p.PeriodStart.In(sch.Location()).Format("January 2, 2006")
+	}
+
 	// UPDATED: Use HTML templates
 	if isUserFault {
 		to = mail.NewEmail(worker.FirstName+" "+worker.LastName, worker.Email)
 		subject = constants.EmailSubjectPayoutFailureActionRequired
 
 		plainTextContent = fmt.Sprintf(
-			"Hi %s,\n\nYour payout of $%.2f for the week of %s could not be processed due to an issue with your connected bank account. Reason: %s\n\nPlease update your payout information in the Stripe Express Dashboard to ensure you receive your earnings.\n\nLink to Stripe: %s\n\nIf you continue to have issues, please contact support.\n\n- The Poof Team",
+			"Hi %s,\n\nYour payout of $%.2f for the pay period starting %s could not be processed due to an issue with your connected bank account. Reason: %s\n\nPlease update your payout information in the Stripe Express Dashboard to ensure you receive your earnings.\n\nLink to Stripe: %s\n\nIf you continue to have issues, please contact support.\n\n- The Poof Team",
 			worker.FirstName,
 			float64(p.AmountCents)/100.0,
-			p.WeekStartDate.Format("January 2, 2006"),
+			periodStart,
 			*p.LastFailureReason,
 			constants.StripeExpressDashboardURL,
 		)
@@ -688,7 +893,7 @@ func (s *PayoutService) sendFailureNotification(ctx context.Context, p *internal
 			userFacingFailureEmailHTML,
 			worker.FirstName,
 			float64(p.AmountCents)/100.0,
-			p.WeekStartDate.Format("January 2, 2006"),
+			periodStart,
 			*p.LastFailureReason,
 			constants.StripeExpressDashboardURL,
 		)
@@ -714,15 +919,16 @@ func (s *PayoutService) sendFailureNotification(ctx context.Context, p *internal
 		)
 	}
 
-	msg := mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent)
-	if s.cfg.LDFlag_SendgridSandboxMode {
-		ms := mail.NewMailSettings()
-		ms.SetSandboxMode(mail.NewSetting(true))
-		msg.MailSettings = ms
-	}
-	if _, err := s.sendgridClient.Send(msg); err != nil {
-		utils.Logger.WithError(err).Error("Failed to send payout failure notification")
-	}
+	s.notifier.Email(ctx, utils.EmailJob{
+		FromName:  from.Name,
+		FromEmail: from.Address,
+		ToName:    to.Name,
+		ToEmail:   to.Address,
+		Subject:   subject,
+		PlainText: plainTextContent,
+		HTML:      htmlContent,
+		Sandbox:   s.cfg.LDFlag_SendgridSandboxMode,
+	})
 }
 
 // NEW: Internal alert for missed webhooks, sent to the dev team.
@@ -733,19 +939,17 @@ func (s *PayoutService) sendWebhookMissAlert(objectType string, objectID string,
 	plainText := fmt.Sprintf("A %s event for ID %s (Worker ID: %s) was not received. The system self-healed by polling the API. Please investigate potential webhook delivery issues.", objectType, objectID, workerID)
 	htmlContent := fmt.Sprintf("<p>%s</p>", plainText)
 
-	msg := mail.NewSingleEmail(from, subject, to, plainText, htmlContent)
-	if s.cfg.LDFlag_SendgridSandboxMode {
-		ms := mail.NewMailSettings()
-		ms.SetSandboxMode(mail.NewSetting(true))
-		msg.MailSettings = ms
-	}
-
-	_, err := s.sendgridClient.Send(msg)
-	if err != nil {
-		utils.Logger.WithError(err).Errorf("Failed to send webhook miss alert email for %s ID %s", objectType, objectID)
-	} else {
-		utils.Logger.Infof("Sent webhook miss alert for %s ID %s.", objectType, objectID)
-	}
+	s.notifier.Email(context.Background(), utils.EmailJob{
+		FromName:  from.Name,
+		FromEmail: from.Address,
+		ToName:    to.Name,
+		ToEmail:   to.Address,
+		Subject:   subject,
+		PlainText: plainText,
+		HTML:      htmlContent,
+		Sandbox:   s.cfg.LDFlag_SendgridSandboxMode,
+	})
+	utils.Logger.Infof("Queued webhook miss alert for %s ID %s.", objectType, objectID)
 }
 
 func (s *PayoutService) HandlePayoutEvent(ctx context.Context, payout *stripe.Payout) error {
@@ -809,37 +1013,34 @@ func (s *PayoutService) HandlePayoutEvent(ctx context.Context, payout *stripe.Pa
 }
 
 func (s *PayoutService) handleLegacyPayoutEvent(ctx context.Context, payout *stripe.Payout) error {
-	var transfer *stripe.Transfer
+	var transfer *LedgerTransfer
 	if payout.BalanceTransaction != nil && payout.BalanceTransaction.Source != nil && payout.BalanceTransaction.Source.Transfer != nil {
-		transfer = payout.BalanceTransaction.Source.Transfer
+		t := ledgerTransfer(payout.BalanceTransaction.Source.Transfer)
+		transfer = &t
 	} else if payout.BalanceTransaction != nil {
-		bt, err := balancetransaction.Get(payout.BalanceTransaction.ID, &stripe.BalanceTransactionParams{
-			Params: stripe.Params{Expand: []*string{stripe.String("source")}},
-		})
-		if err != nil || bt == nil || bt.Source == nil || bt.Source.Transfer == nil {
+		t, err := s.ledger.SourceTransfer(ctx, payout.BalanceTransaction.ID)
+		if err != nil || t == nil {
 			utils.Logger.WithError(err).Warnf("Could not get balance_transaction or its source transfer for payout: %s", payout.ID)
 			return nil
 		}
-		transfer = bt.Source.Transfer
+		transfer = t
 	} else {
 		utils.Logger.Warnf("Payout event %s for Payout %s missing balance_transaction, cannot trace source. Ignoring.", payout.Status, payout.ID)
 		return nil
 	}
 
 	// Check if the source transfer was generated by this service instance.
-	if generator, ok := transfer.Metadata[constants.WebhookMetadataGeneratedByKey]; !ok || generator != s.generatedBy {
-		var g string
-		if !ok {
+	if transfer.GeneratedBy != s.generatedBy {
+		g := transfer.GeneratedBy
+		if g == "" {
 			g = notSetLog
-		} else {
-			g = generator
 		}
 		utils.Logger.Infof("Ignoring legacy payout event %s for Payout %s because its source transfer's 'generated_by' is '%s' (does not match current instance).", payout.Status, payout.ID, g)
 		return nil
 	}
 
-	payoutIDStr, ok := transfer.Metadata[constants.WebhookMetadataPayoutIDKey]
-	if !ok {
+	payoutIDStr := transfer.PayoutID
+	if payoutIDStr == "" {
 		utils.Logger.Warnf("Stripe Transfer %s (from Payout %s) missing payout_id in metadata. Ignoring.", transfer.ID, payout.ID)
 		return nil
 	}
@@ -892,26 +1093,35 @@ func (s *PayoutService) HandleAccountUpdatedEvent(ctx context.Context, acct *str
 	}
 
 	if acct.PayoutsEnabled {
-		failedPayouts, err := s.payoutRepo.FindFailedPayoutsForWorkerByAccountError(ctx, worker.ID)
-		if err != nil {
-			utils.Logger.WithError(err).Errorf("Error finding failed payouts for worker %s", worker.ID)
-			return err
-		}
+		return s.requeueAccountFailures(ctx, worker.ID, nil)
+	}
 
-		for _, p := range failedPayouts {
-			err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-				utils.Logger.Infof("Worker %s updated their account. Re-queueing failed payout %s.", worker.ID, payoutToUpdate.ID)
-				payoutToUpdate.Status = internal_models.PayoutStatusPending
-				payoutToUpdate.RetryCount = 0
-				payoutToUpdate.NextAttemptAt = utils.Ptr(time.Now().UTC())
-				return nil
-			})
-			if err != nil {
-				utils.Logger.WithError(err).Errorf("Failed to re-queue payout %s", p.ID)
-			}
-		}
+	return nil
+}
+
+// requeueAccountFailures re-queues the worker's payouts that failed for a
+// problem with their account, now that it may be fixed. A non-nil keep
+// picks which of them to re-queue.
+func (s *PayoutService) requeueAccountFailures(ctx context.Context, workerID uuid.UUID, keep func(*internal_models.WorkerPayout) bool) error {
+	failedPayouts, err := s.payoutRepo.FindFailedPayoutsForWorkerByAccountError(ctx, workerID)
+	if err != nil {
+		utils.Logger.WithError(err).Errorf("Error finding failed payouts for worker %s", workerID)
+		return err
+	}
+	if keep != nil {
+		failedPayouts = slices.DeleteFunc(failedPayouts, func(p *internal_models.WorkerPayout) bool { return !keep(p) })
 	}
 
+	if len(failedPayouts) > 0 {
+		utils.Logger.Infof("Worker %s updated their account. Re-queueing %d failed payouts.", workerID, len(failedPayouts))
+		if err := s.requeueFailedPayouts(ctx, failedPayouts, true); err != nil {
+			utils.Logger.WithError(err).Errorf("Failed to re-queue failed payouts for worker %s", workerID)
+			return err
+		}
+	}
+	for _, p := range failedPayouts {
+		s.queuePayout(ctx, p.ID, 0, time.Now().UTC())
+	}
 	return nil
 }
 
@@ -934,18 +1144,16 @@ func (s *PayoutService) HandleCapabilityUpdatedEvent(ctx context.Context, cap *s
 			utils.Logger.WithError(err).Errorf("Error finding failed payouts for worker %s", worker.ID)
 			return err
 		}
-		for _, p := range failedPayouts {
-			err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-				utils.Logger.Infof("Worker %s transfers capability became active. Re-queueing failed payout %s.", worker.ID, payoutToUpdate.ID)
-				payoutToUpdate.Status = internal_models.PayoutStatusPending
-				payoutToUpdate.RetryCount = 0
-				payoutToUpdate.NextAttemptAt = utils.Ptr(time.Now().UTC())
-				return nil
-			})
-			if err != nil {
-				utils.Logger.WithError(err).Errorf("Failed to re-queue payout %s", p.ID)
+		if len(failedPayouts) > 0 {
+			utils.Logger.Infof("Worker %s transfers capability became active. Re-queueing %d failed payouts.", worker.ID, len(failedPayouts))
+			if err := s.requeueFailedPayouts(ctx, failedPayouts, true); err != nil {
+				utils.Logger.WithError(err).Errorf("Failed to re-queue failed payouts for worker %s", worker.ID)
+				return err
 			}
 		}
+		for _, p := range failedPayouts {
+			s.queuePayout(ctx, p.ID, 0, time.Now().UTC())
+		}
 
 	} else if cap.ID == constants.StripeCapabilityTransfers && cap.Status == stripe.CapabilityStatusInactive {
 		utils.Logger.Warnf("CRITICAL: Transfers capability for account %s has become inactive. Notifying worker and finance.", cap.Account.ID)
@@ -1010,111 +1218,21 @@ func (s *PayoutService) HandleTransferEvent(ctx context.Context, t *stripe.Trans
 	return nil
 }
 
+// HandleBalanceAvailableEvent queues a recovery run for payouts that failed
+// on an empty platform balance. Bursts of events collapse into one queued run.
 func (s *PayoutService) HandleBalanceAvailableEvent(ctx context.Context, b *stripe.Balance) error {
-	// Use TryLock to ensure that only one recovery process can run at a time,
-	// preventing race conditions from multiple concurrent `balance.available` webhooks.
-	if !s.recoveryMu.TryLock() {
-		utils.Logger.Info("Balance recovery process is already running. Skipping this event.")
-		return nil
-	}
-	utils.Logger.Debug("Acquired balance recovery lock.")
-
-	// This function now releases the lock on all exit paths using defer.
-	defer func() {
-		s.recoveryMu.Unlock()
-		utils.Logger.Debug("Released balance recovery lock.")
-	}()
+	utils.Logger.Info("Received balance.available event. Queueing recovery of payouts that failed due to insufficient funds.")
 
-	utils.Logger.Info("Received balance.available event. Checking for payouts that failed due to insufficient funds.")
-
-	failedPayouts, err := s.payoutRepo.FindFailedByReason(ctx, string(stripe.ErrorCodeBalanceInsufficient))
+	// The delay gives Stripe's systems a moment to sync before we retry.
+	_, err := s.queue.Enqueue(ctx, balanceRecoveryJobKind, balanceRecoveryJob{}, utils.EnqueueOptions{
+		UniqueKey:   balanceRecoveryJobKind,
+		RunAt:       time.Now().UTC().Add(constants.BalanceRecoveryInitialDelay),
+		MaxAttempts: constants.BalanceRecoveryMaxAttempts,
+	})
 	if err != nil {
-		utils.Logger.WithError(err).Error("Failed to find payouts that failed due to insufficient balance")
-		return err
+		utils.Logger.WithError(err).Error("Failed to queue balance recovery")
 	}
-
-	if len(failedPayouts) == 0 {
-		utils.Logger.Info("No payouts found that failed due to insufficient balance. Nothing to do.")
-		return nil
-	}
-
-	utils.Logger.Infof("Found %d payouts to re-queue for processing.", len(failedPayouts))
-	for _, p := range failedPayouts {
-		err := s.payoutRepo.UpdateWithRetry(ctx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-			utils.Logger.Infof("Re-queueing payout %s for worker %s.", payoutToUpdate.ID, payoutToUpdate.WorkerID)
-			payoutToUpdate.Status = internal_models.PayoutStatusPending
-			payoutToUpdate.NextAttemptAt = utils.Ptr(time.Now().UTC())
-			return nil
-		})
-		if err != nil {
-			utils.Logger.WithError(err).Errorf("Failed to re-queue payout %s", p.ID)
-		}
-	}
-
-	// Trigger processing in a goroutine to not block the webhook response.
-	// We pass a new, detached context to the goroutine.
-	go func() {
-		// This goroutine now manages its own lifecycle without holding the lock.
-		bgCtx, cancel := context.WithTimeout(context.Background(), constants.BalanceRecoveryProcessTimeout)
-		defer cancel()
-
-		// Start with an initial delay to give Stripe's systems a moment to sync.
-		utils.Logger.Debugf("Balance recovery goroutine started. Waiting for initial delay.")
-		time.Sleep(constants.BalanceRecoveryInitialDelay)
-
-		const maxRetries = 5
-		var backoff = constants.BalanceRecoveryInitialBackoff
-
-		for i := range maxRetries {
-			utils.Logger.Infof("Attempt %d/%d: Triggering payout processing after 'balance.available' event.", i+1, maxRetries)
-			err := s.ProcessPendingPayouts(bgCtx)
-
-			if err == nil {
-				utils.Logger.Info("Successfully processed payouts after 'balance.available' event.")
-				return // Success!
-			}
-
-			// If it's not the specific insufficient balance error, fail fast.
-			if !errors.Is(err, internal_utils.ErrBalanceInsufficient) {
-				utils.Logger.WithError(err).Error("Failed to process pending payouts with a non-recoverable error after balance top-up.")
-				return
-			}
-
-			// If it was `errBalanceInsufficient` and we have retries left, re-queue and try again.
-			if i < maxRetries-1 {
-				utils.Logger.Warnf("Payout processing still failed with insufficient balance. Re-queueing for another attempt in %v...", backoff)
-
-				// The previous attempt marked the payout as FAILED. We must find it and put it back
-				// into the PENDING state so the next iteration of this loop can pick it up.
-				payoutsToRequeue, findErr := s.payoutRepo.FindFailedByReason(bgCtx, string(stripe.ErrorCodeBalanceInsufficient))
-				if findErr != nil {
-					utils.Logger.WithError(findErr).Error("Could not re-query for failed payouts during recovery retry loop.")
-					return // Cannot continue if we can't query the database.
-				}
-
-				if len(payoutsToRequeue) == 0 {
-					utils.Logger.Warn("A balance insufficient error was reported, but no payouts are in the corresponding failed state. Assuming process is complete.")
-					return
-				}
-
-				for _, p := range payoutsToRequeue {
-					updateErr := s.payoutRepo.UpdateWithRetry(bgCtx, p.ID, func(payoutToUpdate *internal_models.WorkerPayout) error {
-						payoutToUpdate.Status = internal_models.PayoutStatusPending
-						return nil
-					})
-					if updateErr != nil {
-						utils.Logger.WithError(updateErr).Errorf("Failed to re-queue payout %s during recovery retry loop", p.ID)
-					}
-				}
-
-				time.Sleep(backoff)
-				backoff *= 2
-			}
-		}
-		utils.Logger.Error("Gave up processing payouts after multiple retries due to persistent 'balance_insufficient' error.")
-	}()
-
-	return nil
+	return err
 }
 
 // IsFailureRecoverable determines if a failure is transient and can be retried automatically,
@@ -1129,6 +1247,7 @@ func IsFailureRecoverable(reason string) (isSystemRecoverable bool, requiresUser
 		constants.ReasonMissingStripeID,
 		constants.ReasonAccountPayoutsDisabled,
 		constants.StripeFailureCodeAccountRestricted,
+		constants.ReasonMissingBankAccount,
 		string(stripe.PayoutFailureCodeNoAccount),
 		string(stripe.PayoutFailureCodeDebitNotAuthorized),
 		string(stripe.PayoutFailureCodeInvalidCurrency),
@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/config"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/stripe/stripe-go/v82"
+)
+
+/*
+ReconciliationService checks, once a day, that the money we meant to pay
+is the money we paid. Over the payouts created and jobs serviced in the
+last ReconciliationLookbackDays it reports:
+
+  - completed jobs that should have been paid but are in no payout
+    (orphaned), and jobs in more than one payout (double-paid);
+  - payouts listing jobs that aren't the worker's completed jobs;
+  - payouts whose amount isn't their jobs' pay plus their adjustments, less
+    their fee. Pay changed after a job was paid is its own adjustment, so
+    it is left out of the job's pay;
+  - payouts sent over Stripe without a transfer and payout, or whose transfer
+    or payout differs from them, and Stripe transfers made for a payout
+    that doesn't record them.
+
+Stripe is read through a StripeLedger. Each run is stored as a report; ops
+read the latest. Nothing is corrected automatically.
+*/
+type ReconciliationService struct {
+	workerRepo        repositories.WorkerRepository
+	jobInstRepo       repositories.JobInstanceRepository
+	payItemRepo       repositories.JobPayItemRepository
+	payoutRepo        internal_repositories.WorkerPayoutRepository
+	adjustmentRepo    internal_repositories.WorkerAdjustmentRepository
+	holdRepo          internal_repositories.PayoutHoldRepository
+	reportRepo        internal_repositories.ReconciliationReportRepository
+	schedules         *PayScheduleService
+	ledger            StripeLedger
+	generatedByPrefix string
+}
+
+func NewReconciliationService(
+	cfg *config.Config,
+	workerRepo repositories.WorkerRepository,
+	jobInstRepo repositories.JobInstanceRepository,
+	payItemRepo repositories.JobPayItemRepository,
+	payoutRepo internal_repositories.WorkerPayoutRepository,
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository,
+	holdRepo internal_repositories.PayoutHoldRepository,
+	reportRepo internal_repositories.ReconciliationReportRepository,
+	schedules *PayScheduleService,
+	ledger StripeLedger,
+) *ReconciliationService {
+	return &ReconciliationService{
+		workerRepo:     workerRepo,
+		jobInstRepo:    jobInstRepo,
+		payItemRepo:    payItemRepo,
+		payoutRepo:     payoutRepo,
+		adjustmentRepo: adjustmentRepo,
+		holdRepo:       holdRepo,
+		reportRepo:     reportRepo,
+		schedules:      schedules,
+		ledger:         ledger,
+		// Transfers made by any run of this deployment; see PayoutService.generatedBy.
+		generatedByPrefix: fmt.Sprintf("%s-%s-", cfg.AppName, cfg.UniqueRunnerID),
+	}
+}
+
+// Run reconciles the lookback window and stores the report. A run that
+// stops early is still stored, as FAILED, before its error is returned.
+func (s *ReconciliationService) Run(ctx context.Context) error {
+	now := time.Now().UTC()
+	rep := &internal_models.ReconciliationReport{
+		WindowStart: now.AddDate(0, 0, -constants.ReconciliationLookbackDays),
+		WindowEnd:   now,
+		StartedAt:   now,
+	}
+	runErr := s.reconcile(ctx, rep)
+	rep.FinishedAt = time.Now().UTC()
+	switch {
+	case runErr != nil:
+		rep.Status = internal_models.ReconciliationStatusFailed
+		rep.Error = runErr.Error()
+	case len(rep.Findings) > 0:
+		rep.Status = internal_models.ReconciliationStatusDiscrepancies
+	default:
+		rep.Status = internal_models.ReconciliationStatusClean
+	}
+
+	if err := s.reportRepo.Create(ctx, rep); err != nil {
+		return fmt.Errorf("store reconciliation report: %w", err)
+	}
+	if runErr != nil {
+		return fmt.Errorf("reconciliation: %w", runErr)
+	}
+	if len(rep.Findings) > 0 {
+		counts := make(map[internal_models.ReconciliationFindingType]int)
+		for _, f := range rep.Findings {
+			counts[f.Type]++
+		}
+		utils.Logger.Errorf("Reconciliation %s found %d discrepancies: %v", rep.ID, len(rep.Findings), counts)
+	} else {
+		utils.Logger.Infof("Reconciliation %s is clean: %d payouts, %d jobs, %d transfers",
+			rep.ID, rep.PayoutsChecked, rep.JobsChecked, rep.TransfersChecked)
+	}
+	return nil
+}
+
+// Latest returns the most recent report, or nil if reconciliation hasn't
+// run yet.
+func (s *ReconciliationService) Latest(ctx context.Context) (*internal_models.ReconciliationReport, error) {
+	return s.reportRepo.Latest(ctx)
+}
+
+// reconcile fills in rep's counts and findings for its window.
+func (s *ReconciliationService) reconcile(ctx context.Context, rep *internal_models.ReconciliationReport) error {
+	payouts, err := s.payoutRepo.FindCreatedBetween(ctx, rep.WindowStart, rep.WindowEnd)
+	if err != nil {
+		return fmt.Errorf("list payouts: %w", err)
+	}
+	rep.PayoutsChecked = len(payouts)
+
+	// Payouts against the jobs they list.
+	var payoutJobIDs []uuid.UUID
+	for _, p := range payouts {
+		payoutJobIDs = append(payoutJobIDs, p.JobInstanceIDs...)
+	}
+	payoutJobs, err := s.jobInstRepo.ListInstancesByIDs(ctx, payoutJobIDs)
+	if err != nil {
+		return fmt.Errorf("load payout jobs: %w", err)
+	}
+	jobsByID := make(map[uuid.UUID]*models.JobInstance, len(payoutJobs))
+	for _, j := range payoutJobs {
+		jobsByID[j.ID] = j
+	}
+	payoutsByJob, err := s.payoutRepo.PayoutIDsByJobs(ctx, payoutJobIDs)
+	if err != nil {
+		return fmt.Errorf("load payouts by job: %w", err)
+	}
+	paidLater, err := s.payChangedAfterPaid(ctx, payoutJobIDs)
+	if err != nil {
+		return err
+	}
+	rep.Findings = append(rep.Findings, checkPayoutJobs(payouts, jobsByID, payoutsByJob, paidLater)...)
+
+	// Completed jobs against the payouts they should be in.
+	completed, err := s.jobInstRepo.ListInstancesByDateRange(ctx, nil,
+		[]models.InstanceStatusType{models.InstanceStatusCompleted}, rep.WindowStart, rep.WindowEnd)
+	if err != nil {
+		return fmt.Errorf("list completed jobs: %w", err)
+	}
+	rep.JobsChecked = len(completed)
+	orphans, err := s.orphanedJobs(ctx, completed, rep.WindowEnd)
+	if err != nil {
+		return err
+	}
+	rep.Findings = append(rep.Findings, orphans...)
+
+	// Payouts against Stripe.
+	accounts, err := s.connectAccounts(ctx, payouts)
+	if err != nil {
+		return err
+	}
+	stripeFindings, err := checkStripePayouts(ctx, s.ledger, payouts, accounts)
+	rep.Findings = append(rep.Findings, stripeFindings...)
+	if err != nil {
+		return fmt.Errorf("check payouts against Stripe: %w", err)
+	}
+	transfers, err := s.ledger.Transfers(ctx, rep.WindowStart, rep.WindowEnd)
+	if err != nil {
+		return fmt.Errorf("list Stripe transfers: %w", err)
+	}
+	rep.TransfersChecked = len(transfers)
+	unmatched, err := s.unmatchedTransfers(ctx, transfers, payouts)
+	rep.Findings = append(rep.Findings, unmatched...)
+	return err
+}
+
+// payChangedAfterPaid totals, per job, the pay items recorded as
+// adjustments because the job was already paid (see adjustPaidJob). The
+// adjustment takes the item's ID; those items aren't in the job's payout.
+func (s *ReconciliationService) payChangedAfterPaid(ctx context.Context, jobIDs []uuid.UUID) (map[uuid.UUID]models.Money, error) {
+	items, err := s.payItemRepo.ListByInstances(ctx, jobIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load job pay items: %w", err)
+	}
+	var itemIDs []uuid.UUID
+	for _, list := range items {
+		for _, it := range list {
+			itemIDs = append(itemIDs, it.ID)
+		}
+	}
+	adjustments, err := s.adjustmentRepo.ListByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load pay change adjustments: %w", err)
+	}
+	later := make(map[uuid.UUID]models.Money)
+	for jobID, list := range items {
+		for _, it := range list {
+			if adjustments[it.ID] != nil {
+				later[jobID] = later[jobID].Add(it.Amount)
+			}
+		}
+	}
+	return later, nil
+}
+
+// orphanedJobs returns findings for completed jobs whose pay period closed
+// at least ReconciliationOrphanGrace before now without them landing in a
+// payout. Held and rejected jobs, and jobs with no pay, are expected to be
+// unpaid.
+func (s *ReconciliationService) orphanedJobs(ctx context.Context, jobs []*models.JobInstance, now time.Time) ([]internal_models.ReconciliationFinding, error) {
+	var ids, workerIDs []uuid.UUID
+	for _, j := range jobs {
+		if j.AssignedWorkerID != nil && j.EffectivePay.IsPositive() {
+			ids = append(ids, j.ID)
+			workerIDs = append(workerIDs, *j.AssignedWorkerID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	paidOut, err := s.payoutRepo.PaidOutJobIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("check paid-out jobs: %w", err)
+	}
+	holds, err := s.holdRepo.ByJobs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("load payout holds: %w", err)
+	}
+	schedules, err := s.schedules.ForWorkers(ctx, workerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("resolve pay schedules: %w", err)
+	}
+
+	var findings []internal_models.ReconciliationFinding
+	for _, j := range jobs {
+		if j.AssignedWorkerID == nil || !j.EffectivePay.IsPositive() || paidOut[j.ID] || holds[j.ID].Blocks() {
+			continue
+		}
+		_, end := payPeriodFor(schedules[*j.AssignedWorkerID], j, holds[j.ID])
+		if end.Add(constants.ReconciliationOrphanGrace).After(now) {
+			continue
+		}
+		pay := j.EffectivePay
+		findings = append(findings, internal_models.ReconciliationFinding{
+			Type:          internal_models.FindingOrphanedJob,
+			WorkerID:      j.AssignedWorkerID,
+			JobInstanceID: ptr(j.ID),
+			Expected:      &pay,
+			Details:       fmt.Sprintf("Completed %s; its pay period closed %s but it is in no payout.", j.ServiceDate.Format("2006-01-02"), end.Format(time.RFC3339)),
+		})
+	}
+	return findings, nil
+}
+
+// connectAccounts returns the Stripe account of each worker with a payout.
+func (s *ReconciliationService) connectAccounts(ctx context.Context, payouts []*internal_models.WorkerPayout) (map[uuid.UUID]string, error) {
+	accounts := make(map[uuid.UUID]string)
+	for _, p := range payouts {
+		if _, seen := accounts[p.WorkerID]; seen {
+			continue
+		}
+		worker, err := s.workerRepo.GetByID(ctx, p.WorkerID)
+		if err != nil {
+			return nil, fmt.Errorf("load worker %s: %w", p.WorkerID, err)
+		}
+		accounts[p.WorkerID] = ""
+		if worker != nil && worker.StripeConnectAccountID != nil {
+			accounts[p.WorkerID] = *worker.StripeConnectAccountID
+		}
+	}
+	return accounts, nil
+}
+
+// unmatchedTransfers returns findings for this deployment's transfers that
+// no payout records. Payouts created before the window are looked up.
+func (s *ReconciliationService) unmatchedTransfers(ctx context.Context, transfers []LedgerTransfer, payouts []*internal_models.WorkerPayout) ([]internal_models.ReconciliationFinding, error) {
+	byID := make(map[uuid.UUID]*internal_models.WorkerPayout, len(payouts))
+	for _, p := range payouts {
+		byID[p.ID] = p
+	}
+	var findings []internal_models.ReconciliationFinding
+	for _, t := range transfers {
+		if !strings.HasPrefix(t.GeneratedBy, s.generatedByPrefix) {
+			continue // another environment's, or not a payout
+		}
+		payoutID, err := uuid.Parse(t.PayoutID)
+		if err != nil {
+			findings = append(findings, unmatchedTransfer(t, nil, "Transfer has no payout ID in its metadata."))
+			continue
+		}
+		p, ok := byID[payoutID]
+		if !ok {
+			if p, err = s.payoutRepo.GetByID(ctx, payoutID); err != nil {
+				return findings, fmt.Errorf("load payout %s: %w", payoutID, err)
+			}
+		}
+		if f, bad := matchTransfer(t, payoutID, p); bad {
+			findings = append(findings, f)
+		}
+	}
+	return findings, nil
+}
+
+// checkPayoutJobs compares each payout with the jobs it lists: that they
+// are the worker's completed jobs, in no other payout, and that their pay
+// plus the payout's adjustments, less its fee, is its amount. A job's pay
+// leaves out paidLater, the pay it gained or lost after it was paid.
+func checkPayoutJobs(
+	payouts []*internal_models.WorkerPayout,
+	jobsByID map[uuid.UUID]*models.JobInstance,
+	payoutsByJob map[uuid.UUID][]uuid.UUID,
+	paidLater map[uuid.UUID]models.Money,
+) []internal_models.ReconciliationFinding {
+	var findings []internal_models.ReconciliationFinding
+	reported := make(map[uuid.UUID]bool)
+	for _, p := range payouts {
+		jobsPay := models.USD(0)
+		for _, id := range p.JobInstanceIDs {
+			job := jobsByID[id]
+			switch {
+			case job == nil:
+				findings = append(findings, payoutJobFinding(internal_models.FindingInvalidPayoutJob, p, id, "Job does not exist."))
+				continue
+			case job.AssignedWorkerID == nil || *job.AssignedWorkerID != p.WorkerID:
+				findings = append(findings, payoutJobFinding(internal_models.FindingInvalidPayoutJob, p, id, "Job is not assigned to the payout's worker."))
+			case job.Status != models.InstanceStatusCompleted:
+				findings = append(findings, payoutJobFinding(internal_models.FindingInvalidPayoutJob, p, id, fmt.Sprintf("Job is %s, not completed.", job.Status)))
+			}
+			jobsPay = jobsPay.Add(job.EffectivePay)
+			if later, ok := paidLater[id]; ok {
+				jobsPay = jobsPay.Sub(later)
+			}
+
+			if in := payoutsByJob[id]; len(in) > 1 && !reported[id] {
+				reported[id] = true
+				ids := make([]string, len(in))
+				for i, pid := range in {
+					ids[i] = pid.String()
+				}
+				findings = append(findings, payoutJobFinding(internal_models.FindingDoublePaidJob, p, id,
+					fmt.Sprintf("Job is in %d payouts: %s.", len(in), strings.Join(ids, ", "))))
+			}
+		}
+
+		expected := jobsPay.Add(models.USD(p.AdjustmentCents)).Sub(models.USD(p.FeeCents))
+		actual := models.USD(p.AmountCents)
+		if expected.Cmp(actual) == 0 {
+			continue
+		}
+		findings = append(findings, internal_models.ReconciliationFinding{
+			Type:     internal_models.FindingAmountMismatch,
+			WorkerID: ptr(p.WorkerID),
+			PayoutID: ptr(p.ID),
+			Expected: &expected,
+			Actual:   &actual,
+			Details: fmt.Sprintf("%s payout is %s; its %d jobs pay %s, with %s adjustments and a %s fee.",
+				p.Status, actual, len(p.JobInstanceIDs), jobsPay, models.USD(p.AdjustmentCents), models.USD(p.FeeCents)),
+		})
+	}
+	return findings
+}
+
+// settledBelowMinimum reports whether p is a payout settled with nothing
+// sent because its net was at or below the minimum payout; the net was
+// carried forward.
+func settledBelowMinimum(p *internal_models.WorkerPayout) bool {
+	return p.Status == internal_models.PayoutStatusPaid && p.SentCents() == 0 && p.StripeTransferID == nil &&
+		p.AmountCents <= constants.MinimumPayoutAmountCents
+}
+
+// checkStripePayouts compares each payout sent over Stripe with its transfer
+// and payout; ACH payouts are left to their batch and return files. It
+// returns what it found before any error.
+func checkStripePayouts(
+	ctx context.Context,
+	ledger StripeLedger,
+	payouts []*internal_models.WorkerPayout,
+	accounts map[uuid.UUID]string,
+) ([]internal_models.ReconciliationFinding, error) {
+	var findings []internal_models.ReconciliationFinding
+	for _, p := range payouts {
+		sent := p.Status == internal_models.PayoutStatusPaid || p.Status == internal_models.PayoutStatusProcessing
+		if !sent || settledBelowMinimum(p) || p.Provider == internal_models.PayoutProviderACH {
+			continue
+		}
+		if p.Status == internal_models.PayoutStatusPaid && (p.StripeTransferID == nil || p.StripePayoutID == nil) {
+			findings = append(findings, stripeFinding(p, "", "Payout is PAID but has no Stripe transfer or payout recorded."))
+			continue
+		}
+
+		if p.StripeTransferID != nil {
+			t, err := ledger.GetTransfer(ctx, *p.StripeTransferID)
+			if err != nil {
+				return findings, fmt.Errorf("get transfer %s: %w", *p.StripeTransferID, err)
+			}
+			switch {
+			case t == nil:
+				findings = append(findings, stripeFinding(p, *p.StripeTransferID, "Stripe has no such transfer."))
+			case t.AmountCents != p.SentCents():
+				findings = append(findings, stripeAmountFinding(p, t.ID, t.AmountCents, "Transfer amount differs from the payout."))
+			case t.Reversed:
+				findings = append(findings, stripeFinding(p, t.ID, "Transfer was reversed."))
+			case accounts[p.WorkerID] != "" && t.Destination != accounts[p.WorkerID]:
+				findings = append(findings, stripeFinding(p, t.ID,
+					fmt.Sprintf("Transfer went to %s, not the worker's account %s.", t.Destination, accounts[p.WorkerID])))
+			}
+		}
+
+		if p.StripePayoutID != nil && accounts[p.WorkerID] != "" {
+			po, err := ledger.GetPayout(ctx, accounts[p.WorkerID], *p.StripePayoutID)
+			if err != nil {
+				return findings, fmt.Errorf("get payout %s: %w", *p.StripePayoutID, err)
+			}
+			switch {
+			case po == nil:
+				findings = append(findings, stripeFinding(p, *p.StripePayoutID, "Stripe has no such payout on the worker's account."))
+			case po.AmountCents != p.SentCents():
+				findings = append(findings, stripeAmountFinding(p, po.ID, po.AmountCents, "Stripe payout amount differs from the payout."))
+			case p.Status == internal_models.PayoutStatusPaid && po.Status != string(stripe.PayoutStatusPaid):
+				findings = append(findings, stripeFinding(p, po.ID, fmt.Sprintf("Payout is PAID but the Stripe payout is %s.", po.Status)))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// matchTransfer reports a finding when t, made for payoutID, isn't the
+// transfer p records. p is nil when the payout doesn't exist. A reversed
+// transfer moved no money, so it needs no payout.
+func matchTransfer(t LedgerTransfer, payoutID uuid.UUID, p *internal_models.WorkerPayout) (internal_models.ReconciliationFinding, bool) {
+	switch {
+	case t.Reversed:
+		return internal_models.ReconciliationFinding{}, false
+	case p == nil:
+		return unmatchedTransfer(t, &payoutID, "Transfer names a payout that does not exist."), true
+	case p.StripeTransferID == nil:
+		return unmatchedTransfer(t, &payoutID, "Payout records no transfer."), true
+	case *p.StripeTransferID != t.ID:
+		return unmatchedTransfer(t, &payoutID, fmt.Sprintf("Payout records transfer %s instead.", *p.StripeTransferID)), true
+	}
+	return internal_models.ReconciliationFinding{}, false
+}
+
+func payoutJobFinding(typ internal_models.ReconciliationFindingType, p *internal_models.WorkerPayout, jobID uuid.UUID, details string) internal_models.ReconciliationFinding {
+	return internal_models.ReconciliationFinding{
+		Type:          typ,
+		WorkerID:      ptr(p.WorkerID),
+		PayoutID:      ptr(p.ID),
+		JobInstanceID: ptr(jobID),
+		Details:       details,
+	}
+}
+
+func stripeFinding(p *internal_models.WorkerPayout, stripeID, details string) internal_models.ReconciliationFinding {
+	typ := internal_models.FindingStripeMismatch
+	if stripeID == "" {
+		typ = internal_models.FindingMissingStripeReference
+	}
+	return internal_models.ReconciliationFinding{
+		Type:     typ,
+		WorkerID: ptr(p.WorkerID),
+		PayoutID: ptr(p.ID),
+		StripeID: stripeID,
+		Details:  details,
+	}
+}
+
+func stripeAmountFinding(p *internal_models.WorkerPayout, stripeID string, stripeCents int64, details string) internal_models.ReconciliationFinding {
+	f := stripeFinding(p, stripeID, details)
+	expected, actual := models.USD(p.SentCents()), models.USD(stripeCents)
+	f.Expected, f.Actual = &expected, &actual
+	return f
+}
+
+func unmatchedTransfer(t LedgerTransfer, payoutID *uuid.UUID, details string) internal_models.ReconciliationFinding {
+	amount := models.USD(t.AmountCents)
+	return internal_models.ReconciliationFinding{
+		Type:     internal_models.FindingUnmatchedStripeTransfer,
+		PayoutID: payoutID,
+		StripeID: t.ID,
+		Actual:   &amount,
+		Details:  details,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
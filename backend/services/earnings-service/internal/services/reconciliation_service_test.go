@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+var (
+	testWorkerID = uuid.New()
+	testAccount  = "acct_worker"
+)
+
+func testJob(payCents int64) *models.JobInstance {
+	return &models.JobInstance{
+		ID:               uuid.New(),
+		AssignedWorkerID: &testWorkerID,
+		Status:           models.InstanceStatusCompleted,
+		EffectivePay:     models.USD(payCents),
+	}
+}
+
+func testPayout(status internal_models.PayoutStatusType, amountCents int64, jobs ...*models.JobInstance) *internal_models.WorkerPayout {
+	p := &internal_models.WorkerPayout{
+		ID:          uuid.New(),
+		WorkerID:    testWorkerID,
+		AmountCents: amountCents,
+		Status:      status,
+	}
+	for _, j := range jobs {
+		p.JobInstanceIDs = append(p.JobInstanceIDs, j.ID)
+	}
+	return p
+}
+
+func findingTypes(findings []internal_models.ReconciliationFinding) []internal_models.ReconciliationFindingType {
+	out := make([]internal_models.ReconciliationFindingType, len(findings))
+	for i, f := range findings {
+		out[i] = f.Type
+	}
+	return out
+}
+
+func TestCheckPayoutJobs(t *testing.T) {
+	a, b := testJob(2500), testJob(1500)
+	jobs := map[uuid.UUID]*models.JobInstance{a.ID: a, b.ID: b}
+
+	good := testPayout(internal_models.PayoutStatusPaid, 4000, a, b)
+	if got := checkPayoutJobs([]*internal_models.WorkerPayout{good}, jobs, nil, nil); len(got) != 0 {
+		t.Fatalf("expected no findings, got %v", findingTypes(got))
+	}
+
+	// Adjustments and fees are part of the expected amount.
+	cashOut := testPayout(internal_models.PayoutStatusPaid, 2500-150+300, a)
+	cashOut.AdjustmentCents, cashOut.FeeCents = 300, 150
+	if got := checkPayoutJobs([]*internal_models.WorkerPayout{cashOut}, jobs, nil, nil); len(got) != 0 {
+		t.Fatalf("expected no findings for cash-out, got %v", findingTypes(got))
+	}
+
+	// A payout settled below the minimum records its net and sends nothing.
+	small := testJob(40)
+	jobs[small.ID] = small
+	settled := testPayout(internal_models.PayoutStatusPaid, 40, small)
+	settled.CarriedCents = 40
+	if got := checkPayoutJobs([]*internal_models.WorkerPayout{settled}, jobs, nil, nil); len(got) != 0 {
+		t.Fatalf("expected settled payout to reconcile, got %v", findingTypes(got))
+	}
+
+	short := testPayout(internal_models.PayoutStatusPending, 3000, a, b)
+	got := checkPayoutJobs([]*internal_models.WorkerPayout{short}, jobs, nil, nil)
+	if len(got) != 1 || got[0].Type != internal_models.FindingAmountMismatch {
+		t.Fatalf("expected an amount mismatch, got %v", findingTypes(got))
+	}
+	if got[0].Expected.Cents != 4000 || got[0].Actual.Cents != 3000 {
+		t.Fatalf("expected 40.00 vs 30.00, got %s vs %s", got[0].Expected, got[0].Actual)
+	}
+}
+
+func TestCheckPayoutJobsInvalidAndDoublePaid(t *testing.T) {
+	a := testJob(2000)
+	canceled := testJob(1000)
+	canceled.Status = models.InstanceStatusCanceled
+	missing := uuid.New()
+	jobs := map[uuid.UUID]*models.JobInstance{a.ID: a, canceled.ID: canceled}
+
+	p := testPayout(internal_models.PayoutStatusPaid, 3000, a, canceled)
+	p.JobInstanceIDs = append(p.JobInstanceIDs, missing)
+	other := uuid.New()
+	byJob := map[uuid.UUID][]uuid.UUID{a.ID: {p.ID, other}}
+
+	got := findingTypes(checkPayoutJobs([]*internal_models.WorkerPayout{p}, jobs, byJob, nil))
+	want := []internal_models.ReconciliationFindingType{
+		internal_models.FindingDoublePaidJob,
+		internal_models.FindingInvalidPayoutJob,
+		internal_models.FindingInvalidPayoutJob,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestCheckPayoutJobsPayChangedAfterPaid(t *testing.T) {
+	// Paid at 25.00; a 5.00 bonus then a 2.00 deduction were each recorded
+	// as adjustments, leaving the job at 28.00.
+	a := testJob(2800)
+	jobs := map[uuid.UUID]*models.JobInstance{a.ID: a}
+	p := testPayout(internal_models.PayoutStatusPaid, 2500, a)
+
+	paidLater := map[uuid.UUID]models.Money{a.ID: models.USD(300)}
+	if got := checkPayoutJobs([]*internal_models.WorkerPayout{p}, jobs, nil, paidLater); len(got) != 0 {
+		t.Fatalf("expected no findings, got %v", findingTypes(got))
+	}
+	got := checkPayoutJobs([]*internal_models.WorkerPayout{p}, jobs, nil, nil)
+	if len(got) != 1 || got[0].Type != internal_models.FindingAmountMismatch {
+		t.Fatalf("expected an amount mismatch without the later pay, got %v", findingTypes(got))
+	}
+}
+
+func TestCheckPayoutJobsPaidAfterReleasedCashOut(t *testing.T) {
+	// The cash-out failed for good and gave its job back; the next payout
+	// paid it. Only live payouts are listed against the job.
+	a := testJob(2000)
+	jobs := map[uuid.UUID]*models.JobInstance{a.ID: a}
+	released := testPayout(internal_models.PayoutStatusFailed, 2000, a)
+	released.Kind = internal_models.PayoutKindOnDemand
+	paid := testPayout(internal_models.PayoutStatusPaid, 2000, a)
+	byJob := map[uuid.UUID][]uuid.UUID{a.ID: {paid.ID}}
+
+	if got := checkPayoutJobs([]*internal_models.WorkerPayout{released, paid}, jobs, byJob, nil); len(got) != 0 {
+		t.Fatalf("expected no findings, got %v", findingTypes(got))
+	}
+}
+
+func TestCheckStripePayoutsAgainstFake(t *testing.T) {
+	ctx := context.Background()
+	ledger := &FakeStripeLedger{}
+	accounts := map[uuid.UUID]string{testWorkerID: testAccount}
+	ref := func(s string) *string { return &s }
+
+	ok := testPayout(internal_models.PayoutStatusPaid, 4000)
+	ok.StripeTransferID, ok.StripePayoutID = ref("tr_ok"), ref("po_ok")
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_ok", AmountCents: 4000, Destination: testAccount})
+	ledger.AddPayout(LedgerPayout{ID: "po_ok", Account: testAccount, AmountCents: 4000, Status: "paid"})
+
+	wrongAmount := testPayout(internal_models.PayoutStatusPaid, 4000)
+	wrongAmount.StripeTransferID, wrongAmount.StripePayoutID = ref("tr_short"), ref("po_short")
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_short", AmountCents: 3500, Destination: testAccount})
+	ledger.AddPayout(LedgerPayout{ID: "po_short", Account: testAccount, AmountCents: 4000, Status: "paid"})
+
+	notPaid := testPayout(internal_models.PayoutStatusPaid, 1000)
+	notPaid.StripeTransferID, notPaid.StripePayoutID = ref("tr_np"), ref("po_np")
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_np", AmountCents: 1000, Destination: testAccount})
+	ledger.AddPayout(LedgerPayout{ID: "po_np", Account: testAccount, AmountCents: 1000, Status: "failed"})
+
+	noRefs := testPayout(internal_models.PayoutStatusPaid, 1000)
+	unsent := testPayout(internal_models.PayoutStatusPending, 1000)
+	overACH := testPayout(internal_models.PayoutStatusPaid, 1000)
+	overACH.Provider = internal_models.PayoutProviderACH
+	settled := testPayout(internal_models.PayoutStatusPaid, -500)
+	settled.CarriedCents = -500
+
+	payouts := []*internal_models.WorkerPayout{ok, wrongAmount, notPaid, noRefs, unsent, overACH, settled}
+	got, err := checkStripePayouts(ctx, ledger, payouts, accounts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[uuid.UUID]internal_models.ReconciliationFindingType{
+		wrongAmount.ID: internal_models.FindingStripeMismatch,
+		notPaid.ID:     internal_models.FindingStripeMismatch,
+		noRefs.ID:      internal_models.FindingMissingStripeReference,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d findings, got %v", len(want), findingTypes(got))
+	}
+	for _, f := range got {
+		if f.PayoutID == nil || want[*f.PayoutID] != f.Type {
+			t.Fatalf("unexpected finding %+v", f)
+		}
+	}
+
+	ledger.Err = errors.New("stripe down")
+	if _, err := checkStripePayouts(ctx, ledger, payouts, accounts); err == nil {
+		t.Fatal("expected the ledger error to be returned")
+	}
+}
+
+func TestMatchTransfer(t *testing.T) {
+	p := testPayout(internal_models.PayoutStatusPaid, 4000)
+	current := "tr_current"
+	p.StripeTransferID = &current
+
+	if _, bad := matchTransfer(LedgerTransfer{ID: current}, p.ID, p); bad {
+		t.Fatal("the payout's own transfer should match")
+	}
+	if _, bad := matchTransfer(LedgerTransfer{ID: "tr_old", Reversed: true}, p.ID, p); bad {
+		t.Fatal("a reversed transfer moved no money")
+	}
+	f, bad := matchTransfer(LedgerTransfer{ID: "tr_old", AmountCents: 4000}, p.ID, p)
+	if !bad || f.Type != internal_models.FindingUnmatchedStripeTransfer || f.StripeID != "tr_old" {
+		t.Fatalf("expected tr_old to be unmatched, got %+v", f)
+	}
+	if _, bad := matchTransfer(LedgerTransfer{ID: "tr_ghost"}, uuid.New(), nil); !bad {
+		t.Fatal("a transfer for a missing payout should be unmatched")
+	}
+}
+
+func TestFakeStripeLedgerTransfersWindow(t *testing.T) {
+	ledger := &FakeStripeLedger{}
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_before", Created: day.Add(-time.Second)})
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_in", Created: day})
+	ledger.AddTransfer(LedgerTransfer{ID: "tr_after", Created: day.Add(24 * time.Hour)})
+
+	got, err := ledger.Transfers(context.Background(), day, day.Add(24*time.Hour))
+	if err != nil || len(got) != 1 || got[0].ID != "tr_in" {
+		t.Fatalf("expected only tr_in, got %v (err %v)", got, err)
+	}
+}
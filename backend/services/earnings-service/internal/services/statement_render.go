@@ -0,0 +1,312 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// ----------------------------------------------------------------
+// CSV
+// ----------------------------------------------------------------
+
+// Every statement CSV has these columns, one row per pay component,
+// adjustment, fee and balance carried forward, and a TOTAL row at the end.
+var statementCSVHeader = []string{
+	"record", "payout_id", "period_start", "period_end", "paid_at",
+	"date", "property", "job_instance_id", "component", "description", "amount",
+	"stripe_transfer_id", "stripe_payout_id",
+}
+
+// PayoutStatementCSV renders a payout statement as CSV.
+func PayoutStatementCSV(st *dtos.PayoutStatementDTO) ([]byte, error) {
+	return statementCSV([]dtos.StatementSummaryDTO{st.Payout}, st.Jobs, st.Adjustments, st.Net.Sub(st.Payout.Carried))
+}
+
+// AnnualSummaryCSV renders an annual summary as CSV.
+func AnnualSummaryCSV(sum *dtos.AnnualSummaryDTO) ([]byte, error) {
+	return statementCSV(sum.Payouts, sum.Jobs, sum.Adjustments, sum.PaidTotal)
+}
+
+func statementCSV(
+	payouts []dtos.StatementSummaryDTO,
+	jobs []dtos.StatementJobDTO,
+	adjustments []dtos.StatementAdjustmentDTO,
+	total models.Money,
+) ([]byte, error) {
+	byID := make(map[uuid.UUID]dtos.StatementSummaryDTO, len(payouts))
+	for _, p := range payouts {
+		byID[p.PayoutID] = p
+	}
+	// payoutCols fills the payout columns of a row for payoutID.
+	payoutCols := func(record string, payoutID uuid.UUID) []string {
+		p := byID[payoutID]
+		return []string{
+			record, p.PayoutID.String(), p.PeriodStartDate, p.PeriodEndDate, formatPaidAt(p.PaidAt),
+		}
+	}
+	refCols := func(payoutID uuid.UUID) []string {
+		p := byID[payoutID]
+		return []string{derefString(p.StripeTransferID), derefString(p.StripePayoutID)}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{statementCSVHeader}
+	for _, j := range jobs {
+		for _, it := range j.PayItems {
+			row := payoutCols("JOB", j.PayoutID)
+			row = append(row, j.ServiceDate, csvText(j.PropertyName), j.InstanceID.String(), csvText(it.Label), csvText(it.Description), csvAmount(it.Amount))
+			rows = append(rows, append(row, refCols(j.PayoutID)...))
+		}
+	}
+	for _, a := range adjustments {
+		row := payoutCols("ADJUSTMENT", a.PayoutID)
+		row = append(row, "", "", "", csvText(a.Kind), csvText(a.Reason), csvAmount(a.Amount))
+		rows = append(rows, append(row, refCols(a.PayoutID)...))
+	}
+	for _, p := range payouts {
+		if p.Fee.IsZero() {
+			continue
+		}
+		row := payoutCols("FEE", p.PayoutID)
+		row = append(row, "", "", "", "Cash-out fee", csvText(p.Method), csvAmount(p.Fee.Neg()))
+		rows = append(rows, append(row, refCols(p.PayoutID)...))
+	}
+	for _, p := range payouts {
+		if p.Carried.IsZero() {
+			continue
+		}
+		row := payoutCols("CARRIED", p.PayoutID)
+		row = append(row, "", "", "", "Carried forward", "Below the minimum payout", csvAmount(p.Carried.Neg()))
+		rows = append(rows, append(row, refCols(p.PayoutID)...))
+	}
+	rows = append(rows, []string{"TOTAL", "", "", "", "", "", "", "", "", "", csvAmount(total), "", ""})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("write statement csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvText keeps free text (property names, descriptions, reasons) from
+// being read as a formula when the CSV is opened in a spreadsheet.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvAmount is m in dollars with two places and no currency sign.
+func csvAmount(m models.Money) string {
+	return fmt.Sprintf("%.2f", m.Dollars())
+}
+
+func formatPaidAt(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ----------------------------------------------------------------
+// PDF
+// ----------------------------------------------------------------
+
+// PayoutStatementPDF renders a payout statement as a PDF.
+func PayoutStatementPDF(st *dtos.PayoutStatementDTO) []byte {
+	p := internal_utils.NewPDF()
+	pdfHeader(p, "Payout Statement", st.WorkerName, st.GeneratedAt)
+
+	po := st.Payout
+	pdfField(p, "Pay period", po.PeriodStartDate+" to "+po.PeriodEndDate)
+	pdfField(p, "Payout", fmt.Sprintf("%s, %s (%s)", po.Kind, po.Method, po.Status))
+	if po.PaidAt != nil {
+		pdfField(p, "Paid", po.PaidAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+	pdfField(p, "Payout ID", po.PayoutID.String())
+	if po.StripeTransferID != nil {
+		pdfField(p, "Transfer", *po.StripeTransferID)
+	}
+	if po.StripePayoutID != nil {
+		pdfField(p, "Bank payout", *po.StripePayoutID)
+	}
+	p.Gap(10)
+
+	pdfJobs(p, st.Jobs)
+	pdfAdjustments(p, st.Adjustments)
+
+	p.Gap(6)
+	pdfTotal(p, "Jobs", st.JobsTotal, false)
+	if !po.Fee.IsZero() {
+		pdfTotal(p, "Cash-out fee", po.Fee.Neg(), false)
+	}
+	pdfTotal(p, "Net pay", st.Net, true)
+	if !po.Carried.IsZero() {
+		pdfTotal(p, "Carried forward", po.Carried.Neg(), false)
+	}
+	if st.Net.Cmp(po.Amount) != 0 {
+		pdfTotal(p, "Amount sent", po.Amount, true)
+	}
+	return p.Bytes()
+}
+
+// AnnualSummaryPDF renders an annual summary as a PDF.
+func AnnualSummaryPDF(sum *dtos.AnnualSummaryDTO) []byte {
+	p := internal_utils.NewPDF()
+	title := fmt.Sprintf("%d Earnings Summary", sum.Year)
+	if sum.YearToDate {
+		title = fmt.Sprintf("%d Year-to-Date Earnings Summary", sum.Year)
+	}
+	pdfHeader(p, title, sum.WorkerName, sum.GeneratedAt)
+	pdfField(p, "Period", fmt.Sprintf("%d-01-01 to %s", sum.Year, sum.Through))
+	pdfField(p, "Payouts", fmt.Sprintf("%d", len(sum.Payouts)))
+	pdfField(p, "Jobs", fmt.Sprintf("%d", sum.JobCount))
+	p.Gap(6)
+	pdfTotal(p, "Jobs", sum.JobsTotal, false)
+	pdfTotal(p, "Adjustments", sum.AdjustmentTotal, false)
+	pdfTotal(p, "Cash-out fees", sum.FeeTotal.Neg(), false)
+	if !sum.CarriedTotal.IsZero() {
+		pdfTotal(p, "Carried forward", sum.CarriedTotal.Neg(), false)
+	}
+	pdfTotal(p, "Total paid", sum.PaidTotal, true)
+	p.Gap(10)
+
+	right := p.Right()
+	p.Line(11, true, internal_utils.PDFColumn{X: p.Left(), Text: "By month"})
+	p.Rule()
+	for _, m := range sum.Months {
+		p.Line(9, false,
+			internal_utils.PDFColumn{X: p.Left(), Text: m.Month},
+			internal_utils.PDFColumn{X: right, Text: m.Paid.String(), Right: true},
+		)
+	}
+	p.Gap(10)
+
+	left := p.Left()
+	p.Line(11, true, internal_utils.PDFColumn{X: left, Text: "Payouts"})
+	p.Rule()
+	p.Line(8, true,
+		internal_utils.PDFColumn{X: left, Text: "Paid"},
+		internal_utils.PDFColumn{X: left + 70, Text: "Pay period"},
+		internal_utils.PDFColumn{X: left + 200, Text: "Payout"},
+		internal_utils.PDFColumn{X: left + 330, Text: "Reference"},
+		internal_utils.PDFColumn{X: right - 60, Text: "Fee", Right: true},
+		internal_utils.PDFColumn{X: right, Text: "Amount", Right: true},
+	)
+	for _, po := range sum.Payouts {
+		paid := ""
+		if po.PaidAt != nil {
+			paid = po.PaidAt.UTC().Format("2006-01-02")
+		}
+		p.Line(8, false,
+			internal_utils.PDFColumn{X: left, Text: paid},
+			internal_utils.PDFColumn{X: left + 70, Text: po.PeriodStartDate + " - " + po.PeriodEndDate},
+			internal_utils.PDFColumn{X: left + 200, Text: po.Kind + ", " + po.Method, MaxWidth: 125},
+			internal_utils.PDFColumn{X: left + 330, Text: derefString(po.StripeTransferID), MaxWidth: 120},
+			internal_utils.PDFColumn{X: right - 60, Text: po.Fee.String(), Right: true},
+			internal_utils.PDFColumn{X: right, Text: po.Amount.String(), Right: true},
+		)
+	}
+	p.Gap(10)
+
+	pdfJobs(p, sum.Jobs)
+	pdfAdjustments(p, sum.Adjustments)
+	return p.Bytes()
+}
+
+func pdfHeader(p *internal_utils.PDF, title, workerName string, generatedAt time.Time) {
+	p.Line(16, true, internal_utils.PDFColumn{X: p.Left(), Text: "Poof " + title})
+	p.Gap(4)
+	pdfField(p, "Worker", workerName)
+	pdfField(p, "Generated", generatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+}
+
+func pdfField(p *internal_utils.PDF, label, value string) {
+	p.Line(9, false,
+		internal_utils.PDFColumn{X: p.Left(), Text: label + ":"},
+		internal_utils.PDFColumn{X: p.Left() + 80, Text: value, MaxWidth: p.ContentWidth() - 80},
+	)
+}
+
+// pdfJobs lists jobs with a line per pay component.
+func pdfJobs(p *internal_utils.PDF, jobs []dtos.StatementJobDTO) {
+	left, right := p.Left(), p.Right()
+	p.Line(11, true, internal_utils.PDFColumn{X: left, Text: "Jobs"})
+	p.Rule()
+	p.Line(8, true,
+		internal_utils.PDFColumn{X: left, Text: "Date"},
+		internal_utils.PDFColumn{X: left + 60, Text: "Property"},
+		internal_utils.PDFColumn{X: left + 240, Text: "Component"},
+		internal_utils.PDFColumn{X: right, Text: "Amount", Right: true},
+	)
+	if len(jobs) == 0 {
+		p.Line(8, false, internal_utils.PDFColumn{X: left, Text: "No jobs."})
+	}
+	for _, j := range jobs {
+		if len(j.PayItems) == 0 {
+			p.Line(8, false,
+				internal_utils.PDFColumn{X: left, Text: j.ServiceDate},
+				internal_utils.PDFColumn{X: left + 60, Text: j.PropertyName, MaxWidth: 175},
+				internal_utils.PDFColumn{X: right, Text: j.Pay.String(), Right: true},
+			)
+			continue
+		}
+		for i, it := range j.PayItems {
+			date, property := "", ""
+			if i == 0 {
+				date, property = j.ServiceDate, j.PropertyName
+			}
+			component := it.Label
+			if it.Description != "" {
+				component += " - " + it.Description
+			}
+			p.Line(8, false,
+				internal_utils.PDFColumn{X: left, Text: date},
+				internal_utils.PDFColumn{X: left + 60, Text: property, MaxWidth: 175},
+				internal_utils.PDFColumn{X: left + 240, Text: component, MaxWidth: right - left - 310},
+				internal_utils.PDFColumn{X: right, Text: it.Amount.String(), Right: true},
+			)
+		}
+	}
+	p.Gap(10)
+}
+
+func pdfAdjustments(p *internal_utils.PDF, adjustments []dtos.StatementAdjustmentDTO) {
+	if len(adjustments) == 0 {
+		return
+	}
+	left, right := p.Left(), p.Right()
+	p.Line(11, true, internal_utils.PDFColumn{X: left, Text: "Adjustments"})
+	p.Rule()
+	for _, a := range adjustments {
+		p.Line(8, false,
+			internal_utils.PDFColumn{X: left, Text: a.Kind},
+			internal_utils.PDFColumn{X: left + 90, Text: a.Reason, MaxWidth: right - left - 160},
+			internal_utils.PDFColumn{X: right, Text: a.Amount.String(), Right: true},
+		)
+	}
+	p.Gap(10)
+}
+
+func pdfTotal(p *internal_utils.PDF, label string, amount models.Money, bold bool) {
+	p.Line(10, bold,
+		internal_utils.PDFColumn{X: p.Right() - 200, Text: label},
+		internal_utils.PDFColumn{X: p.Right(), Text: amount.String(), Right: true},
+	)
+}
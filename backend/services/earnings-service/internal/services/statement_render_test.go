@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var (
+	testStatementPayoutID = uuid.MustParse("6f1c2a4e-0b7d-4c1e-9a53-2d8e4f6a7b10")
+	testStatementJobID    = uuid.MustParse("0c9b8a7d-6e5f-4a3b-8c2d-1e0f9a8b7c6d")
+)
+
+func testPayoutStatement() *dtos.PayoutStatementDTO {
+	paidAt := time.Date(2026, 3, 6, 16, 0, 0, 0, time.UTC)
+	transfer, bank := "tr_123", "po_456"
+	return &dtos.PayoutStatementDTO{
+		WorkerName: "Jane Doe",
+		Payout: dtos.StatementSummaryDTO{
+			PayoutID:         testStatementPayoutID,
+			PeriodStartDate:  "2026-02-23",
+			PeriodEndDate:    "2026-03-01",
+			Kind:             "SCHEDULED",
+			Method:           "STANDARD",
+			Status:           "PAID",
+			Amount:           models.USD(5250),
+			Fee:              models.USD(0),
+			JobCount:         1,
+			PaidAt:           &paidAt,
+			StripeTransferID: &transfer,
+			StripePayoutID:   &bank,
+		},
+		Jobs: []dtos.StatementJobDTO{{
+			InstanceID:  testStatementJobID,
+			ServiceDate: "2026-02-24",
+			// Free text a spreadsheet would otherwise run as a formula.
+			PropertyName: `=HYPERLINK("http://example.com","Oak Ridge")`,
+			PayItems: []dtos.PayItemDTO{
+				{Kind: models.PayItemBase, Label: "Base", Amount: models.USD(4000), Description: "Base pay"},
+				{Kind: models.PayItemSurge, Label: "Surge", Amount: models.USD(1000), Description: "+25% for a late job"},
+			},
+			Pay:      models.USD(5000),
+			PayoutID: testStatementPayoutID,
+		}},
+		Adjustments: []dtos.StatementAdjustmentDTO{
+			{Kind: "BONUS", Reason: "@mention bonus", Amount: models.USD(500), PayoutID: testStatementPayoutID},
+			{Kind: "CLAWBACK", Reason: "-1 unit missed", Amount: models.USD(-250), PayoutID: testStatementPayoutID},
+		},
+		JobsTotal:   models.USD(5000),
+		Net:         models.USD(5250),
+		GeneratedAt: time.Date(2026, 3, 7, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func testAnnualSummary() *dtos.AnnualSummaryDTO {
+	st := testPayoutStatement()
+	settled := dtos.StatementSummaryDTO{
+		PayoutID:        uuid.MustParse("9a8b7c6d-5e4f-4a3b-9c2d-1e0f2a3b4c5d"),
+		PeriodStartDate: "2026-03-02",
+		PeriodEndDate:   "2026-03-08",
+		Kind:            "SCHEDULED",
+		Method:          "STANDARD",
+		Status:          "PAID",
+		Carried:         models.USD(300),
+		PaidAt:          st.Payout.PaidAt,
+	}
+	return &dtos.AnnualSummaryDTO{
+		WorkerName:      st.WorkerName,
+		Year:            2026,
+		Through:         "2026-03-31",
+		YearToDate:      true,
+		JobCount:        1,
+		JobsTotal:       st.JobsTotal,
+		AdjustmentTotal: models.USD(250),
+		CarriedTotal:    models.USD(300),
+		PaidTotal:       st.Payout.Amount,
+		Months: []dtos.MonthTotalDTO{
+			{Month: "2026-01"},
+			{Month: "2026-02"},
+			{Month: "2026-03", Paid: st.Payout.Amount},
+		},
+		Payouts:     []dtos.StatementSummaryDTO{st.Payout, settled},
+		Jobs:        st.Jobs,
+		Adjustments: st.Adjustments,
+		GeneratedAt: st.GeneratedAt,
+	}
+}
+
+// checkGolden compares got with testdata/name, rewriting it under -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v (run with -update to create it)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file; run with -update if the change is intended\ngot:\n%s", name, got)
+	}
+}
+
+func TestPayoutStatementCSVGolden(t *testing.T) {
+	got, err := PayoutStatementCSV(testPayoutStatement())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "payout_statement.csv", got)
+}
+
+func TestAnnualSummaryCSVGolden(t *testing.T) {
+	got, err := AnnualSummaryCSV(testAnnualSummary())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "annual_summary.csv", got)
+}
+
+func TestPayoutStatementPDFGolden(t *testing.T) {
+	checkGolden(t, "payout_statement.pdf", PayoutStatementPDF(testPayoutStatement()))
+}
+
+func TestAnnualSummaryPDFGolden(t *testing.T) {
+	checkGolden(t, "annual_summary.pdf", AnnualSummaryPDF(testAnnualSummary()))
+}
+
+func TestCSVTextNeutralizesFormulas(t *testing.T) {
+	for in, want := range map[string]string{
+		"=SUM(A1:A9)":  "'=SUM(A1:A9)",
+		"+1":           "'+1",
+		"-1 unit":      "'-1 unit",
+		"@cmd":         "'@cmd",
+		"\tindented":   "'\tindented",
+		"Oak Ridge":    "Oak Ridge",
+		"":             "",
+		"a=b":          "a=b",
+		"Base pay -50": "Base pay -50",
+	} {
+		if got := csvText(in); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	// Amounts are numbers and keep their sign.
+	out, err := PayoutStatementCSV(testPayoutStatement())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), ",-2.50,") {
+		t.Errorf("expected the clawback amount unprefixed:\n%s", out)
+	}
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/dtos"
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_repositories "github.com/poofware/mono-repo/backend/services/earnings-service/internal/repositories"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+// Formats a statement can be downloaded in.
+const (
+	StatementFormatJSON = "json"
+	StatementFormatCSV  = "csv"
+	StatementFormatPDF  = "pdf"
+)
+
+// firstStatementYear is the earliest year an annual summary can be asked for.
+const firstStatementYear = 2020
+
+/*
+StatementService builds workers' proof-of-income documents: a statement
+for any one of their payouts, a history of payouts over any range, and a
+summary of what they were paid in a calendar year or the year so far.
+
+Unlike the earnings summary, which lays out the last few weeks by service
+date, annual summaries count payouts by the day they were paid, in the
+worker's pay schedule timezone, so they match the worker's bank.
+*/
+type StatementService struct {
+	workerRepo     repositories.WorkerRepository
+	jobInstRepo    repositories.JobInstanceRepository
+	defRepo        repositories.JobDefinitionRepository
+	propRepo       repositories.PropertyRepository
+	payItemRepo    repositories.JobPayItemRepository
+	payoutRepo     internal_repositories.WorkerPayoutRepository
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository
+	schedules      *PayScheduleService
+}
+
+func NewStatementService(
+	workerRepo repositories.WorkerRepository,
+	jobInstRepo repositories.JobInstanceRepository,
+	defRepo repositories.JobDefinitionRepository,
+	propRepo repositories.PropertyRepository,
+	payItemRepo repositories.JobPayItemRepository,
+	payoutRepo internal_repositories.WorkerPayoutRepository,
+	adjustmentRepo internal_repositories.WorkerAdjustmentRepository,
+	schedules *PayScheduleService,
+) *StatementService {
+	return &StatementService{
+		workerRepo:     workerRepo,
+		jobInstRepo:    jobInstRepo,
+		defRepo:        defRepo,
+		propRepo:       propRepo,
+		payItemRepo:    payItemRepo,
+		payoutRepo:     payoutRepo,
+		adjustmentRepo: adjustmentRepo,
+		schedules:      schedules,
+	}
+}
+
+// List returns the worker's payouts for pay periods starting between the
+// from and to dates (YYYY-MM-DD, inclusive), newest first. Empty to is
+// today; empty from is a year before to.
+func (s *StatementService) List(ctx context.Context, workerID uuid.UUID, from, to string) (*dtos.StatementListResponse, error) {
+	sch, err := s.schedules.ForWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	fromDay, toDay, err := statementRange(from, to, sch.Location(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	payouts, err := s.payoutRepo.FindForWorkerByDateRange(ctx, workerID, fromDay, toDay.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]dtos.StatementSummaryDTO, 0, len(payouts))
+	for _, p := range payouts {
+		list = append(list, statementSummary(p, sch))
+	}
+	return &dtos.StatementListResponse{
+		From:       fromDay.Format("2006-01-02"),
+		To:         toDay.Format("2006-01-02"),
+		Statements: list,
+	}, nil
+}
+
+// Payout returns the statement for one of the worker's payouts.
+func (s *StatementService) Payout(ctx context.Context, workerID, payoutID uuid.UUID) (*dtos.PayoutStatementDTO, error) {
+	p, err := s.payoutRepo.GetByID(ctx, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.WorkerID != workerID {
+		return nil, internal_utils.ErrStatementNotFound
+	}
+	sch, err := s.schedules.ForWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	name, err := s.workerName(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	jobs, adjustments, err := s.lines(ctx, []*internal_models.WorkerPayout{p})
+	if err != nil {
+		return nil, err
+	}
+
+	st := &dtos.PayoutStatementDTO{
+		WorkerName:  name,
+		Payout:      statementSummary(p, sch),
+		Jobs:        jobs,
+		Adjustments: adjustments,
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, j := range jobs {
+		st.JobsTotal = st.JobsTotal.Add(j.Pay)
+	}
+	st.Net = st.JobsTotal
+	for _, a := range adjustments {
+		st.Net = st.Net.Add(a.Amount)
+	}
+	st.Net = st.Net.Sub(models.USD(p.FeeCents))
+	return st, nil
+}
+
+// Annual returns what the worker was paid in year, or so far this year.
+func (s *StatementService) Annual(ctx context.Context, workerID uuid.UUID, year int) (*dtos.AnnualSummaryDTO, error) {
+	sch, err := s.schedules.ForWorker(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	loc := sch.Location()
+	start, end, ytd, err := annualRange(year, loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := s.workerName(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := s.payoutRepo.FindPaidForWorker(ctx, workerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	jobs, adjustments, err := s.lines(ctx, payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	lastDay := end.Add(-time.Nanosecond)
+	sum := &dtos.AnnualSummaryDTO{
+		WorkerName:  name,
+		Year:        year,
+		Through:     lastDay.Format("2006-01-02"),
+		YearToDate:  ytd,
+		JobCount:    len(jobs),
+		Months:      monthTotals(lastDay, payouts, loc),
+		Payouts:     make([]dtos.StatementSummaryDTO, 0, len(payouts)),
+		Jobs:        jobs,
+		Adjustments: adjustments,
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, p := range payouts {
+		sum.Payouts = append(sum.Payouts, statementSummary(p, sch))
+		sum.FeeTotal = sum.FeeTotal.Add(models.USD(p.FeeCents))
+		sum.CarriedTotal = sum.CarriedTotal.Add(models.USD(p.CarriedCents))
+		sum.PaidTotal = sum.PaidTotal.Add(models.USD(p.SentCents()))
+	}
+	for _, j := range jobs {
+		sum.JobsTotal = sum.JobsTotal.Add(j.Pay)
+	}
+	for _, a := range adjustments {
+		sum.AdjustmentTotal = sum.AdjustmentTotal.Add(a.Amount)
+	}
+	return sum, nil
+}
+
+// statementRange parses List's from and to dates in loc. Empty to is the
+// day of now; empty from is a year before to.
+func statementRange(from, to string, loc *time.Location, now time.Time) (fromDay, toDay time.Time, err error) {
+	toDay = now.In(loc)
+	if to != "" {
+		if toDay, err = time.ParseInLocation("2006-01-02", to, loc); err != nil {
+			return fromDay, toDay, fmt.Errorf("%w: to must be YYYY-MM-DD", internal_utils.ErrInvalidStatement)
+		}
+	}
+	toDay = time.Date(toDay.Year(), toDay.Month(), toDay.Day(), 0, 0, 0, 0, loc)
+	fromDay = toDay.AddDate(-1, 0, 0)
+	if from != "" {
+		if fromDay, err = time.ParseInLocation("2006-01-02", from, loc); err != nil {
+			return fromDay, toDay, fmt.Errorf("%w: from must be YYYY-MM-DD", internal_utils.ErrInvalidStatement)
+		}
+	}
+	if fromDay.After(toDay) {
+		return fromDay, toDay, fmt.Errorf("%w: from is after to", internal_utils.ErrInvalidStatement)
+	}
+	if toDay.Sub(fromDay) > constants.StatementMaxRangeDays*24*time.Hour {
+		return fromDay, toDay, fmt.Errorf("%w: range is longer than %d days", internal_utils.ErrInvalidStatement, constants.StatementMaxRangeDays)
+	}
+	return fromDay, toDay, nil
+}
+
+// annualRange is the part of year an annual summary covers in loc: the
+// whole year, or up to now while it is the current one.
+func annualRange(year int, loc *time.Location, now time.Time) (start, end time.Time, ytd bool, err error) {
+	now = now.In(loc)
+	if year < firstStatementYear || year > now.Year() {
+		return start, end, false, fmt.Errorf("%w: year must be between %d and %d", internal_utils.ErrInvalidStatement, firstStatementYear, now.Year())
+	}
+	start = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end = start.AddDate(1, 0, 0)
+	if now.Before(end) {
+		return start, now, true, nil
+	}
+	return start, end, false, nil
+}
+
+// monthTotals buckets what payouts sent by the month they were paid in, in
+// loc, with a bucket for every month from January to lastDay's.
+func monthTotals(lastDay time.Time, payouts []*internal_models.WorkerPayout, loc *time.Location) []dtos.MonthTotalDTO {
+	months := make([]dtos.MonthTotalDTO, 0, int(lastDay.Month()))
+	for m := time.January; m <= lastDay.Month(); m++ {
+		months = append(months, dtos.MonthTotalDTO{Month: fmt.Sprintf("%d-%02d", lastDay.Year(), m)})
+	}
+	for _, p := range payouts {
+		if p.PaidAt == nil {
+			continue
+		}
+		if m := int(p.PaidAt.In(loc).Month()) - 1; m < len(months) {
+			months[m].Paid = months[m].Paid.Add(models.USD(p.SentCents()))
+		}
+	}
+	return months
+}
+
+func (s *StatementService) workerName(ctx context.Context, workerID uuid.UUID) (string, error) {
+	worker, err := s.workerRepo.GetByID(ctx, workerID)
+	if err != nil {
+		return "", err
+	}
+	if worker == nil {
+		return "", internal_utils.ErrStatementNotFound
+	}
+	return strings.TrimSpace(worker.FirstName + " " + worker.LastName), nil
+}
+
+// lines returns the jobs and adjustments paid with payouts, jobs by service
+// date and adjustments in the order they were applied.
+func (s *StatementService) lines(ctx context.Context, payouts []*internal_models.WorkerPayout) ([]dtos.StatementJobDTO, []dtos.StatementAdjustmentDTO, error) {
+	jobPayout := make(map[uuid.UUID]uuid.UUID)
+	var jobIDs []uuid.UUID
+	payoutIDs := make([]uuid.UUID, 0, len(payouts))
+	for _, p := range payouts {
+		payoutIDs = append(payoutIDs, p.ID)
+		for _, id := range p.JobInstanceIDs {
+			if _, seen := jobPayout[id]; !seen {
+				jobPayout[id] = p.ID
+				jobIDs = append(jobIDs, id)
+			}
+		}
+	}
+
+	jobs := []dtos.StatementJobDTO{}
+	if len(jobIDs) > 0 {
+		instances, err := s.jobInstRepo.ListInstancesByIDs(ctx, jobIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		payItems, err := s.payItemRepo.ListByInstances(ctx, jobIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		defMap, propMap, err := fetchJobMetadata(ctx, s.defRepo, s.propRepo, instances)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, job := range instances {
+			items := payItems[job.ID]
+			dto := dtos.StatementJobDTO{
+				InstanceID:  job.ID,
+				ServiceDate: job.ServiceDate.UTC().Format("2006-01-02"),
+				PayItems:    make([]dtos.PayItemDTO, 0, len(items)),
+				Pay:         models.SumPayItems(items),
+				PayoutID:    jobPayout[job.ID],
+			}
+			for _, it := range items {
+				dto.PayItems = append(dto.PayItems, dtos.PayItemDTO{
+					Kind:        it.Kind,
+					Label:       it.Kind.Label(),
+					Amount:      it.Amount,
+					Description: it.Description,
+				})
+			}
+			if def, ok := defMap[job.DefinitionID]; ok {
+				if prop, ok := propMap[def.PropertyID]; ok {
+					dto.PropertyName = prop.PropertyName
+				}
+			}
+			jobs = append(jobs, dto)
+		}
+	}
+
+	adjustments := []dtos.StatementAdjustmentDTO{}
+	byPayout, err := s.adjustmentRepo.ListByPayoutIDs(ctx, payoutIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range payouts {
+		for _, a := range byPayout[p.ID] {
+			adjustments = append(adjustments, dtos.StatementAdjustmentDTO{
+				Kind:     string(a.Kind),
+				Reason:   a.Reason,
+				Amount:   a.Amount,
+				PayoutID: p.ID,
+			})
+		}
+	}
+	return jobs, adjustments, nil
+}
+
+func statementSummary(p *internal_models.WorkerPayout, sch *internal_models.PaySchedule) dtos.StatementSummaryDTO {
+	firstDay, lastDay := sch.Dates(p.PeriodStart, p.PeriodEnd)
+	return dtos.StatementSummaryDTO{
+		PayoutID:         p.ID,
+		PeriodStartDate:  firstDay,
+		PeriodEndDate:    lastDay,
+		Kind:             string(p.Kind),
+		Method:           string(p.Method),
+		Status:           string(p.Status),
+		Amount:           models.USD(p.SentCents()),
+		Fee:              models.USD(p.FeeCents),
+		Carried:          models.USD(p.CarriedCents),
+		JobCount:         len(p.JobInstanceIDs),
+		PaidAt:           p.PaidAt,
+		StripeTransferID: p.StripeTransferID,
+		StripePayoutID:   p.StripePayoutID,
+	}
+}
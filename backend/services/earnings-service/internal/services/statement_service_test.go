@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	internal_models "github.com/poofware/mono-repo/backend/services/earnings-service/internal/models"
+	internal_utils "github.com/poofware/mono-repo/backend/services/earnings-service/internal/utils"
+)
+
+func TestStatementRange(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// Still the 5th in Chicago.
+	now := time.Date(2026, 3, 6, 3, 0, 0, 0, time.UTC)
+
+	from, to, err := statementRange("", "", loc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := to.Format("2006-01-02"); got != "2026-03-05" {
+		t.Errorf("expected to to default to today in the worker's zone, got %s", got)
+	}
+	if got := from.Format("2006-01-02"); got != "2025-03-05" {
+		t.Errorf("expected from to default to a year before to, got %s", got)
+	}
+
+	if _, _, err := statementRange("2026-01-01", "2026-01-01", loc, now); err != nil {
+		t.Errorf("a single day is a valid range, got %v", err)
+	}
+	if _, _, err := statementRange("2023-03-05", "2026-03-05", loc, now); err != nil {
+		t.Errorf("three years fit in a range, got %v", err)
+	}
+
+	for name, tc := range map[string]struct{ from, to string }{
+		"bad from":      {from: "03/01/2026"},
+		"bad to":        {to: "2026-3-1"},
+		"from after to": {from: "2026-02-02", to: "2026-02-01"},
+		"too long":      {from: "2023-03-01", to: "2026-03-05"},
+	} {
+		if _, _, err := statementRange(tc.from, tc.to, loc, now); !errors.Is(err, internal_utils.ErrInvalidStatement) {
+			t.Errorf("%s: expected ErrInvalidStatement, got %v", name, err)
+		}
+	}
+}
+
+func TestAnnualRange(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// New Year's Eve in New York.
+	now := time.Date(2027, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	start, end, ytd, err := annualRange(2026, loc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ytd || !end.Equal(now) {
+		t.Errorf("expected 2026 to still be the current year, got ytd=%v end=%s", ytd, end)
+	}
+	if want := time.Date(2026, 1, 1, 0, 0, 0, 0, loc); !start.Equal(want) {
+		t.Errorf("expected the year to start at local midnight, got %s", start)
+	}
+
+	_, end, ytd, err = annualRange(2025, loc, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ytd || !end.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, loc)) {
+		t.Errorf("expected all of 2025, got ytd=%v end=%s", ytd, end)
+	}
+
+	for _, year := range []int{firstStatementYear - 1, 2027} {
+		if _, _, _, err := annualRange(year, loc, now); !errors.Is(err, internal_utils.ErrInvalidStatement) {
+			t.Errorf("%d: expected ErrInvalidStatement, got %v", year, err)
+		}
+	}
+}
+
+func TestMonthTotals(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	paid := func(at time.Time, amount, carried int64) *internal_models.WorkerPayout {
+		return &internal_models.WorkerPayout{Status: internal_models.PayoutStatusPaid, AmountCents: amount, CarriedCents: carried, PaidAt: &at}
+	}
+	lastDay := time.Date(2026, 4, 15, 0, 0, 0, 0, loc)
+	months := monthTotals(lastDay, []*internal_models.WorkerPayout{
+		paid(time.Date(2026, 1, 9, 18, 0, 0, 0, time.UTC), 4000, 0),
+		paid(time.Date(2026, 1, 30, 18, 0, 0, 0, time.UTC), 2500, 0),
+		// Still February in Los Angeles.
+		paid(time.Date(2026, 3, 1, 5, 0, 0, 0, time.UTC), 1000, 0),
+		// Settled below the minimum: nothing was sent.
+		paid(time.Date(2026, 3, 13, 18, 0, 0, 0, time.UTC), 300, 300),
+	}, loc)
+
+	want := []struct {
+		month string
+		cents int64
+	}{
+		{"2026-01", 6500},
+		{"2026-02", 1000},
+		{"2026-03", 0},
+		{"2026-04", 0},
+	}
+	if len(months) != len(want) {
+		t.Fatalf("expected a bucket for January to April, got %v", months)
+	}
+	for i, w := range want {
+		if months[i].Month != w.month || months[i].Paid.Cents != w.cents {
+			t.Errorf("bucket %d: expected %s %d, got %s %s", i, w.month, w.cents, months[i].Month, months[i].Paid)
+		}
+	}
+}
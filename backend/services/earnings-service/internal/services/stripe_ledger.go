@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/poofware/mono-repo/backend/services/earnings-service/internal/constants"
+	"github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/balancetransaction"
+	"github.com/stripe/stripe-go/v82/payout"
+	"github.com/stripe/stripe-go/v82/transfer"
+)
+
+// LedgerTransfer is a Stripe transfer from the platform to a worker's
+// connected account.
+type LedgerTransfer struct {
+	ID          string
+	AmountCents int64
+	Destination string // connected account ID
+	Reversed    bool
+	PayoutID    string // our payout, from the transfer's metadata
+	GeneratedBy string // the instance that made it, from the metadata
+	Created     time.Time
+}
+
+// LedgerPayout is a Stripe payout from a connected account to its bank.
+type LedgerPayout struct {
+	ID          string
+	Account     string
+	AmountCents int64
+	Status      string // pending, in_transit, paid, failed or canceled
+	Created     time.Time
+}
+
+// StripeLedger reads the money Stripe actually moved, for reconciliation.
+// Lookups of something Stripe doesn't have return nil without an error.
+type StripeLedger interface {
+	// Transfers returns the platform's transfers created within [from, to),
+	// from its balance transactions.
+	Transfers(ctx context.Context, from, to time.Time) ([]LedgerTransfer, error)
+	GetTransfer(ctx context.Context, id string) (*LedgerTransfer, error)
+	GetPayout(ctx context.Context, account, id string) (*LedgerPayout, error)
+	// SourceTransfer returns the transfer a balance transaction came from,
+	// or nil if it didn't come from one.
+	SourceTransfer(ctx context.Context, balanceTransactionID string) (*LedgerTransfer, error)
+}
+
+/*──────────── Stripe ────────────*/
+
+type stripeLedger struct{}
+
+// NewStripeLedger reads from Stripe with the key PayoutService set.
+func NewStripeLedger() StripeLedger {
+	return stripeLedger{}
+}
+
+func (stripeLedger) Transfers(ctx context.Context, from, to time.Time) ([]LedgerTransfer, error) {
+	params := &stripe.BalanceTransactionListParams{
+		Type: stripe.String("transfer"),
+		CreatedRange: &stripe.RangeQueryParams{
+			GreaterThanOrEqual: from.Unix(),
+			LesserThan:         to.Unix(),
+		},
+	}
+	params.Context = ctx
+	params.Limit = stripe.Int64(100)
+	params.AddExpand("data.source")
+
+	var out []LedgerTransfer
+	it := balancetransaction.List(params)
+	for it.Next() {
+		bt := it.BalanceTransaction()
+		if bt.Source != nil && bt.Source.Transfer != nil {
+			out = append(out, ledgerTransfer(bt.Source.Transfer))
+		}
+	}
+	return out, it.Err()
+}
+
+func (stripeLedger) GetTransfer(ctx context.Context, id string) (*LedgerTransfer, error) {
+	params := &stripe.TransferParams{}
+	params.Context = ctx
+	t, err := transfer.Get(id, params)
+	if err != nil {
+		return nil, ignoreMissing(err)
+	}
+	lt := ledgerTransfer(t)
+	return &lt, nil
+}
+
+func (stripeLedger) GetPayout(ctx context.Context, account, id string) (*LedgerPayout, error) {
+	params := &stripe.PayoutParams{}
+	params.Context = ctx
+	params.SetStripeAccount(account)
+	po, err := payout.Get(id, params)
+	if err != nil {
+		return nil, ignoreMissing(err)
+	}
+	return &LedgerPayout{
+		ID:          po.ID,
+		Account:     account,
+		AmountCents: po.Amount,
+		Status:      string(po.Status),
+		Created:     time.Unix(po.Created, 0).UTC(),
+	}, nil
+}
+
+func (stripeLedger) SourceTransfer(ctx context.Context, balanceTransactionID string) (*LedgerTransfer, error) {
+	params := &stripe.BalanceTransactionParams{}
+	params.Context = ctx
+	params.AddExpand("source")
+	bt, err := balancetransaction.Get(balanceTransactionID, params)
+	if err != nil {
+		return nil, ignoreMissing(err)
+	}
+	if bt.Source == nil || bt.Source.Transfer == nil {
+		return nil, nil
+	}
+	lt := ledgerTransfer(bt.Source.Transfer)
+	return &lt, nil
+}
+
+func ledgerTransfer(t *stripe.Transfer) LedgerTransfer {
+	lt := LedgerTransfer{
+		ID:          t.ID,
+		AmountCents: t.Amount,
+		Reversed:    t.Reversed,
+		PayoutID:    t.Metadata[constants.WebhookMetadataPayoutIDKey],
+		GeneratedBy: t.Metadata[constants.WebhookMetadataGeneratedByKey],
+		Created:     time.Unix(t.Created, 0).UTC(),
+	}
+	if t.Destination != nil {
+		lt.Destination = t.Destination.ID
+	}
+	return lt
+}
+
+// ignoreMissing turns Stripe's resource_missing into a nil error.
+func ignoreMissing(err error) error {
+	var se *stripe.Error
+	if errors.As(err, &se) && se.Code == stripe.ErrorCodeResourceMissing {
+		return nil
+	}
+	return err
+}
+
+/*──────────── fake ────────────*/
+
+// FakeStripeLedger is an in-memory ledger for tests. Add what Stripe should
+// hold with AddTransfer, AddPayout and AddSourceTransfer; setting Err makes
+// every call fail.
+type FakeStripeLedger struct {
+	Err error
+
+	mu        sync.Mutex
+	transfers []LedgerTransfer
+	payouts   []LedgerPayout
+	sources   map[string]LedgerTransfer
+}
+
+func (f *FakeStripeLedger) AddTransfer(t LedgerTransfer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transfers = append(f.transfers, t)
+}
+
+func (f *FakeStripeLedger) AddPayout(p LedgerPayout) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.payouts = append(f.payouts, p)
+}
+
+// AddSourceTransfer records t as the source of a balance transaction.
+func (f *FakeStripeLedger) AddSourceTransfer(balanceTransactionID string, t LedgerTransfer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sources == nil {
+		f.sources = make(map[string]LedgerTransfer)
+	}
+	f.sources[balanceTransactionID] = t
+}
+
+func (f *FakeStripeLedger) Transfers(_ context.Context, from, to time.Time) ([]LedgerTransfer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var out []LedgerTransfer
+	for _, t := range f.transfers {
+		if !t.Created.Before(from) && t.Created.Before(to) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *FakeStripeLedger) GetTransfer(_ context.Context, id string) (*LedgerTransfer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, t := range f.transfers {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeStripeLedger) GetPayout(_ context.Context, account, id string) (*LedgerPayout, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	for _, p := range f.payouts {
+		if p.ID == id && p.Account == account {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeStripeLedger) SourceTransfer(_ context.Context, balanceTransactionID string) (*LedgerTransfer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if t, ok := f.sources[balanceTransactionID]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
@@ -6,5 +6,30 @@ import (
 
 var (
 	ErrBalanceInsufficient = errors.New("platform balance is insufficient")
+	ErrInvalidAdjustment   = errors.New("invalid adjustment")
+	ErrAdjustmentNotFound  = errors.New("adjustment not found")
+	ErrAdjustmentDecided   = errors.New("adjustment was already approved or rejected")
+	ErrSelfApproval        = errors.New("adjustments must be decided by someone other than their creator")
+	ErrInvalidDispute      = errors.New("invalid dispute")
+	ErrDisputeNotFound     = errors.New("dispute not found")
+	ErrDisputeExists       = errors.New("an unresolved dispute for this job already exists")
+	ErrDisputeWrongStatus  = errors.New("dispute is not in a status that allows this")
+	ErrCashOutIneligible   = errors.New("not eligible to cash out")
+	ErrCashOutLimit        = errors.New("daily cash-out limit reached")
+	ErrNothingToCashOut    = errors.New("no earnings available to cash out")
+	ErrInvalidPaySchedule  = errors.New("invalid pay schedule")
+	ErrPayScheduleNotFound = errors.New("pay schedule not found")
+	ErrNotAssigned         = errors.New("no pay schedule assignment to remove")
+	ErrInvalidHold         = errors.New("invalid payout hold")
+	ErrHoldNotFound        = errors.New("payout hold not found")
+	ErrAlreadyHeld         = errors.New("job is already held or its pay was rejected")
+	ErrHoldResolved        = errors.New("hold was already released or rejected")
+	ErrInvalidStatement    = errors.New("invalid statement request")
+	ErrStatementNotFound   = errors.New("statement not found")
+	ErrACHDisabled         = errors.New("ACH payouts are not configured")
+	ErrInvalidBankAccount  = errors.New("invalid bank account")
+	ErrNothingToBatch      = errors.New("no ACH payouts are waiting for a batch")
+	ErrACHBatchNotFound    = errors.New("ACH batch not found")
+	ErrACHBatchSubmitted   = errors.New("ACH batch was already submitted")
+	ErrInvalidReturnFile   = errors.New("invalid ACH return file")
 )
-	
@@ -0,0 +1,260 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	achRecordLen   = 94
+	achBlockingFac = 10
+	achMaxAmount   = 9_999_999_999 // ten digits of cents
+
+	achServiceCreditsOnly = "220"
+	achCheckingCredit     = "22"
+	achSavingsCredit      = "32"
+	achReturnAddenda      = "99"
+)
+
+// ACHEntry is one credit to a worker's bank account.
+type ACHEntry struct {
+	RoutingNumber string // the receiving bank's 9-digit routing number
+	AccountNumber string
+	Savings       bool
+	AmountCents   int64
+	IndividualID  string // our reference for the receiver, up to 15 characters
+	Name          string
+	TraceNumber   string // 15 digits, see ACHTraceNumber
+}
+
+/*
+ACHFile is a NACHA file of PPD credits in one batch, for upload to the bank
+that originates them (the ODFI). Only what our payouts need is supported:
+no debits, no addenda and no offsetting entry.
+
+ImmediateDestination is the ODFI's routing number. Names and descriptions
+are upper-cased and cut to their field widths; characters NACHA doesn't
+allow become spaces.
+*/
+type ACHFile struct {
+	ImmediateDestination     string
+	ImmediateDestinationName string
+	ImmediateOrigin          string
+	ImmediateOriginName      string
+	FileIDModifier           byte // A-Z or 0-9, telling apart files made the same day
+	CompanyName              string
+	CompanyID                string
+	EntryDescription         string
+	Created                  time.Time
+	EffectiveDate            time.Time
+	Entries                  []ACHEntry
+}
+
+// ACHTraceNumber builds an entry's trace number from the ODFI's routing
+// number and a sequence, which wraps after seven digits.
+func ACHTraceNumber(odfiRoutingNumber string, seq int64) string {
+	return fmt.Sprintf("%.8s%07d", odfiRoutingNumber, seq%10_000_000)
+}
+
+// ValidRoutingNumber reports whether s is nine digits with a correct ABA
+// check digit.
+func ValidRoutingNumber(s string) bool {
+	if len(s) != 9 || strings.Trim(s, "0123456789") != "" {
+		return false
+	}
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i := range s {
+		sum += int(s[i]-'0') * weights[i]
+	}
+	return sum%10 == 0
+}
+
+// Bytes writes the file: 94-character records, padded with 9s to a whole
+// number of 10-record blocks.
+func (f *ACHFile) Bytes() ([]byte, error) {
+	if !ValidRoutingNumber(f.ImmediateDestination) {
+		return nil, fmt.Errorf("ach: invalid immediate destination %q", f.ImmediateDestination)
+	}
+	if !strings.ContainsRune("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", rune(f.FileIDModifier)) {
+		return nil, fmt.Errorf("ach: invalid file ID modifier %q", f.FileIDModifier)
+	}
+	if len(f.Entries) == 0 {
+		return nil, fmt.Errorf("ach: no entries")
+	}
+	odfi := f.ImmediateDestination[:8]
+	const batchNumber = 1
+
+	var records []string
+	records = append(records, "101"+
+		" "+f.ImmediateDestination+
+		fmt.Sprintf("%10.10s", achText(f.ImmediateOrigin))+
+		f.Created.Format("060102")+
+		f.Created.Format("1504")+
+		string(f.FileIDModifier)+
+		"094"+"10"+"1"+
+		achField(f.ImmediateDestinationName, 23)+
+		achField(f.ImmediateOriginName, 23)+
+		achField("", 8))
+	records = append(records, "5"+achServiceCreditsOnly+
+		achField(f.CompanyName, 16)+
+		achField("", 20)+
+		achField(f.CompanyID, 10)+
+		"PPD"+
+		achField(f.EntryDescription, 10)+
+		achField("", 6)+
+		f.EffectiveDate.Format("060102")+
+		achField("", 3)+
+		"1"+odfi+
+		fmt.Sprintf("%07d", batchNumber))
+
+	var hash, credits int64
+	for i, e := range f.Entries {
+		if !ValidRoutingNumber(e.RoutingNumber) {
+			return nil, fmt.Errorf("ach: entry %d: invalid routing number", i)
+		}
+		if strings.TrimSpace(e.AccountNumber) == "" || len(e.AccountNumber) > 17 {
+			return nil, fmt.Errorf("ach: entry %d: invalid account number", i)
+		}
+		if e.AmountCents <= 0 || e.AmountCents > achMaxAmount {
+			return nil, fmt.Errorf("ach: entry %d: amount out of range", i)
+		}
+		if len(e.TraceNumber) != 15 || strings.Trim(e.TraceNumber, "0123456789") != "" {
+			return nil, fmt.Errorf("ach: entry %d: invalid trace number", i)
+		}
+		code := achCheckingCredit
+		if e.Savings {
+			code = achSavingsCredit
+		}
+		rdfi, _ := strconv.ParseInt(e.RoutingNumber[:8], 10, 64)
+		hash += rdfi
+		credits += e.AmountCents
+		records = append(records, "6"+code+
+			e.RoutingNumber+
+			achField(e.AccountNumber, 17)+
+			fmt.Sprintf("%010d", e.AmountCents)+
+			achField(e.IndividualID, 15)+
+			achField(e.Name, 22)+
+			achField("", 2)+
+			"0"+
+			e.TraceNumber)
+	}
+	hash %= 10_000_000_000
+
+	records = append(records, "8"+achServiceCreditsOnly+
+		fmt.Sprintf("%06d%010d%012d%012d", len(f.Entries), hash, 0, credits)+
+		achField(f.CompanyID, 10)+
+		achField("", 19)+
+		achField("", 6)+
+		odfi+
+		fmt.Sprintf("%07d", batchNumber))
+
+	blocks := (len(records) + 1 + achBlockingFac - 1) / achBlockingFac
+	records = append(records, "9"+
+		fmt.Sprintf("%06d%06d%08d%010d%012d%012d", 1, blocks, len(f.Entries), hash, 0, credits)+
+		achField("", 39))
+	for len(records)%achBlockingFac != 0 {
+		records = append(records, strings.Repeat("9", achRecordLen))
+	}
+
+	var buf bytes.Buffer
+	for _, r := range records {
+		if len(r) != achRecordLen {
+			return nil, fmt.Errorf("ach: built a %d-character record", len(r))
+		}
+		buf.WriteString(r)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// ACHReturnEntry is a credit the receiving bank sent back.
+type ACHReturnEntry struct {
+	TraceNumber string // the original entry's trace number
+	ReturnCode  string // R01, R02, ...
+	AmountCents int64
+}
+
+// ParseACHReturns reads the returned entries from a NACHA return file:
+// each entry with a type 99 addenda record. Notifications of change and
+// anything else in the file are skipped.
+func ParseACHReturns(data []byte) ([]ACHReturnEntry, error) {
+	var (
+		out       []ACHReturnEntry
+		amount    int64
+		haveEntry bool
+		sawHeader bool
+		line      int
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line++
+		r := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(r) == "" || strings.Trim(r, "9") == "" {
+			continue
+		}
+		if len(r) < achRecordLen {
+			return nil, fmt.Errorf("ach: line %d is %d characters, want %d", line, len(r), achRecordLen)
+		}
+		switch r[0] {
+		case '1':
+			sawHeader = true
+		case '6':
+			n, err := strconv.ParseInt(r[29:39], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("ach: line %d: invalid amount", line)
+			}
+			amount, haveEntry = n, true
+		case '7':
+			if r[1:3] != achReturnAddenda {
+				continue
+			}
+			if !haveEntry {
+				return nil, fmt.Errorf("ach: line %d: return addenda without an entry", line)
+			}
+			trace := r[6:21]
+			if strings.Trim(trace, "0123456789") != "" {
+				return nil, fmt.Errorf("ach: line %d: invalid original trace number", line)
+			}
+			out = append(out, ACHReturnEntry{
+				TraceNumber: trace,
+				ReturnCode:  strings.TrimSpace(r[3:6]),
+				AmountCents: amount,
+			})
+			haveEntry = false
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !sawHeader {
+		return nil, fmt.Errorf("ach: no file header record")
+	}
+	return out, nil
+}
+
+// achField upper-cases s, clears characters NACHA doesn't allow and pads
+// or cuts it to n characters, left-justified.
+func achField(s string, n int) string {
+	s = achText(s)
+	if len(s) > n {
+		return s[:n]
+	}
+	return s + strings.Repeat(" ", n-len(s))
+}
+
+func achText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= ' ' && r <= '~':
+			return r
+		}
+		return ' '
+	}, s)
+}
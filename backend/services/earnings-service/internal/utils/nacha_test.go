@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testACHFile() *ACHFile {
+	created := time.Date(2026, 3, 6, 14, 30, 0, 0, time.UTC)
+	return &ACHFile{
+		ImmediateDestination:     "011000015",
+		ImmediateDestinationName: "First Bank",
+		ImmediateOrigin:          "1234567890",
+		ImmediateOriginName:      "Poof Inc",
+		FileIDModifier:           'A',
+		CompanyName:              "Poof Inc",
+		CompanyID:                "1234567890",
+		EntryDescription:         "PAYROLL",
+		Created:                  created,
+		EffectiveDate:            created.AddDate(0, 0, 3),
+		Entries: []ACHEntry{
+			{RoutingNumber: "021000021", AccountNumber: "12345678", AmountCents: 4250, IndividualID: "w1", Name: "Jane Doe", TraceNumber: ACHTraceNumber("011000015", 1)},
+			{RoutingNumber: "011000015", AccountNumber: "9876", Savings: true, AmountCents: 1000, IndividualID: "w2", Name: "José Ruiz", TraceNumber: ACHTraceNumber("011000015", 2)},
+		},
+	}
+}
+
+func TestACHFileBytes(t *testing.T) {
+	data, err := testACHFile().Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("expected one 10-record block, got %d records", len(lines))
+	}
+	for i, l := range lines {
+		if len(l) != achRecordLen {
+			t.Fatalf("record %d is %d characters", i, len(l))
+		}
+	}
+
+	if got := lines[0][:13]; got != "101 011000015" {
+		t.Fatalf("unexpected file header start %q", got)
+	}
+	if got := lines[2][:12]; got != "622021000021" {
+		t.Fatalf("expected a checking credit to 021000021, got %q", got)
+	}
+	if got := lines[3][:3]; got != "632" {
+		t.Fatalf("expected a savings credit, got %q", got)
+	}
+	if got := lines[3][54:76]; got != "JOS  RUIZ             " {
+		t.Fatalf("expected a cleaned, upper-cased name, got %q", got)
+	}
+	if got := lines[2][79:]; got != "011000010000001" {
+		t.Fatalf("unexpected trace number %q", got)
+	}
+
+	// Entry hash is the sum of the RDFI routing prefixes: 02100002 + 01100001.
+	if got := lines[4][:44]; got != "8220"+"000002"+"0003200003"+"000000000000"+"000000005250" {
+		t.Fatalf("unexpected batch control %q", lines[4])
+	}
+	if got := lines[5][:55]; got != "9"+"000001"+"000001"+"00000002"+"0003200003"+"000000000000"+"000000005250" {
+		t.Fatalf("unexpected file control %q", lines[5])
+	}
+	for _, l := range lines[6:] {
+		if strings.Trim(l, "9") != "" {
+			t.Fatalf("expected 9s padding, got %q", l)
+		}
+	}
+}
+
+func TestACHFileRejectsBadEntries(t *testing.T) {
+	f := testACHFile()
+	f.Entries[0].RoutingNumber = "021000022"
+	if _, err := f.Bytes(); err == nil {
+		t.Fatal("expected an invalid routing number to be rejected")
+	}
+	f = testACHFile()
+	f.Entries[1].AmountCents = 0
+	if _, err := f.Bytes(); err == nil {
+		t.Fatal("expected a zero amount to be rejected")
+	}
+}
+
+func TestParseACHReturns(t *testing.T) {
+	data, err := testACHFile().Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	addenda := "799R03" + lines[2][79:] + strings.Repeat(" ", achRecordLen-21)
+	noc := "798C01" + lines[3][79:] + strings.Repeat(" ", achRecordLen-21)
+	ret := strings.Join([]string{lines[0], lines[1], lines[2], addenda, lines[3], noc, lines[4], lines[5]}, "\r\n")
+
+	got, err := ParseACHReturns([]byte(ret))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected one return, got %+v", got)
+	}
+	want := ACHReturnEntry{TraceNumber: "011000010000001", ReturnCode: "R03", AmountCents: 4250}
+	if got[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, got[0])
+	}
+
+	if _, err := ParseACHReturns([]byte("not a NACHA file")); err == nil {
+		t.Fatal("expected garbage to be rejected")
+	}
+}
+
+func TestValidRoutingNumber(t *testing.T) {
+	for s, want := range map[string]bool{
+		"011000015":  true,
+		"021000021":  true,
+		"021000022":  false,
+		"02100002":   false,
+		"02100002a":  false,
+		"0210000210": false,
+	} {
+		if got := ValidRoutingNumber(s); got != want {
+			t.Errorf("ValidRoutingNumber(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// US Letter in points, with 0.6in margins.
+const (
+	pdfPageWidth  = 612.0
+	pdfPageHeight = 792.0
+	pdfMargin     = 43.0
+)
+
+// PDFColumn is one piece of text on a PDF line. X is where it starts, or
+// where it ends when Right is set. Text wider than MaxWidth (if set) is cut
+// short with an ellipsis.
+type PDFColumn struct {
+	X        float64
+	Text     string
+	Right    bool
+	MaxWidth float64
+}
+
+/*
+PDF writes simple text documents: lines of Helvetica on Letter pages,
+starting a new page when one fills up. It is just enough for earnings
+statements without pulling in a PDF library. Text outside Latin-1 is
+replaced.
+*/
+type PDF struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func NewPDF() *PDF {
+	p := &PDF{}
+	p.newPage()
+	return p
+}
+
+// ContentWidth is the usable width between the margins.
+func (p *PDF) ContentWidth() float64 {
+	return pdfPageWidth - 2*pdfMargin
+}
+
+// Left is the x of the left margin; Right that of the right one.
+func (p *PDF) Left() float64  { return pdfMargin }
+func (p *PDF) Right() float64 { return pdfPageWidth - pdfMargin }
+
+func (p *PDF) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pdfPageHeight - pdfMargin
+}
+
+func (p *PDF) page() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// Line writes one line of text at size points.
+func (p *PDF) Line(size float64, bold bool, cols ...PDFColumn) {
+	lead := size * 1.35
+	if p.y-lead < pdfMargin {
+		p.newPage()
+	}
+	p.y -= lead
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	for _, c := range cols {
+		text := c.Text
+		if c.MaxWidth > 0 {
+			text = pdfFit(text, size, c.MaxWidth)
+		}
+		x := c.X
+		if c.Right {
+			x -= pdfTextWidth(text, size)
+		}
+		fmt.Fprintf(p.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, p.y, pdfEscape(text))
+	}
+}
+
+// Gap moves down by points.
+func (p *PDF) Gap(points float64) {
+	p.y -= points
+	if p.y < pdfMargin {
+		p.newPage()
+	}
+}
+
+// Rule draws a thin line across the page.
+func (p *PDF) Rule() {
+	p.Gap(4)
+	fmt.Fprintf(p.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, p.y, pdfPageWidth-pdfMargin, p.y)
+	p.Gap(2)
+}
+
+// Bytes returns the finished document.
+func (p *PDF) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	// 1 catalog, 2 page tree, 3-4 fonts, then a page and its content per page.
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range p.pages {
+		obj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i,
+		))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// pdfEscape maps s to WinAnsi bytes and escapes it for a PDF string.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '‘' || r == '’':
+			b.WriteByte('\'')
+		case r == '“' || r == '”':
+			b.WriteByte('"')
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		case r >= 0x20 && r < 0x7f:
+			b.WriteByte(byte(r))
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// pdfTextWidth estimates the width of s in Helvetica at size points. Digits
+// and the punctuation in amounts are exact, so right-aligned amounts line
+// up.
+func pdfTextWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9', r == '$':
+			units += 556
+		case r == '.' || r == ',' || r == ' ' || r == ':' || r == '/':
+			units += 278
+		case r == '-' || r == '(' || r == ')':
+			units += 333
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		case r == 'i' || r == 'l' || r == 'j':
+			units += 222
+		default:
+			units += 520
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// pdfFit cuts s short so it fits in width.
+func pdfFit(s string, size, width float64) string {
+	if pdfTextWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdfTextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/poofware/mono-repo/backend/shared/go-middleware"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
-	cron "github.com/robfig/cron/v3"
 	"github.com/rs/cors"
 	"github.com/sendgrid/sendgrid-go"
 	twilio "github.com/twilio/twilio-go"
@@ -43,10 +42,17 @@ func main() {
 	workerRepo := repositories.NewWorkerRepository(application.DB, cfg.DBEncryptionKey)
 	agentRepo := repositories.NewAgentRepository(application.DB)
 	ajcRepo := repositories.NewAgentJobCompletionRepository(application.DB)
+	lotteryRepo := repositories.NewJobLotteryRepository(application.DB)
+	surgeRepo := repositories.NewSurgePolicyRepository(application.DB)
+	escalationRepo := repositories.NewEscalationPolicyRepository(application.DB)
+	scoreEventRepo := repositories.NewWorkerScoreEventRepository(application.DB)
+	payItemRepo := repositories.NewJobPayItemRepository(application.DB)
+	photoRepo := repositories.NewVerificationPhotoRepository(application.DB)
 
 	// MODIFIED: unitRepo is now required by more services.
 	unitRepo := repositories.NewUnitRepository(application.DB)
 	juvRepo := repositories.NewJobUnitVerificationRepository(application.DB)
+	uow := repositories.NewUnitOfWork(application.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{})
 
 	openaiSvc := services.NewOpenAIService(cfg.OpenAIAPIKey)
 
@@ -56,6 +62,16 @@ func main() {
 	})
 	sgClient := sendgrid.NewSendClient(cfg.SendGridAPIKey)
 
+	// Durable background work (notifications, photo re-checks, EMA updates).
+	queue := utils.NewPostgresJobQueue(cfg.AppName, application.DB, utils.JobQueueOptions{})
+	notifier := utils.NewNotifier(queue, sgClient, twClient)
+
+	var driveTimeProvider utils.DriveTimeProvider = utils.CrowFliesProvider{}
+	if cfg.LDFlag_UseGMapsRoutesAPI && cfg.GMapsRoutesAPIKey != "" {
+		driveTimeProvider = utils.NewGoogleRoutesProvider(cfg.GMapsRoutesAPIKey)
+	}
+	driveTimes := utils.NewDriveTimeCache(driveTimeProvider, utils.DriveTimeCacheOptions{})
+
 	// UPDATED: pass unitRepo and ajcRepo to jobService
 	jobService := services.NewJobService(
 		cfg,
@@ -70,9 +86,16 @@ func main() {
 		unitRepo,
 		juvRepo,
 		ajcRepo, // MODIFIED
+		lotteryRepo,
+		surgeRepo,
+		scoreEventRepo,
+		payItemRepo,
+		photoRepo,
+		uow,
 		openaiSvc,
-		twClient,
-		sgClient,
+		notifier,
+		queue,
+		driveTimes,
 	)
 
 	if err := app.SeedDemoData(
@@ -117,6 +140,7 @@ func main() {
 		ajcRepo,
 		bldgRepo,
 		unitRepo,
+		escalationRepo,
 		jobService,
 	)
 	jobScheduler := services.NewJobSchedulerService(cfg, defRepo, instRepo, propRepo)
@@ -126,6 +150,36 @@ func main() {
 	jobsController := controllers.NewJobsController(jobService, agentCompletionSvc)
 	healthController := controllers.NewHealthController(application)
 	jobDefsController := controllers.NewJobDefinitionsController(jobService)
+	surgeController := controllers.NewSurgeController(jobService)
+	allocationController := controllers.NewAllocationController(jobService)
+	escalationController := controllers.NewEscalationController(jobService, escalationService)
+	scoreController := controllers.NewScoreController(jobService)
+	payController := controllers.NewPayController(jobService)
+
+	queue.Start()
+	defer queue.Stop()
+
+	sched := utils.NewPostgresScheduler(cfg.AppName, application.DB, utils.SchedulerOptions{})
+	queueController := controllers.NewQueueController(jobService, queue, sched)
+	for _, job := range []struct {
+		name, spec string
+		run        func(ctx context.Context) error
+	}{
+		{"daily-window-maintenance", "5 0 * * *", jobScheduler.RunDailyWindowMaintenance},
+		{"escalation-check", "@every 2m", escalationService.RunEscalationCheck},
+		{"lottery-draws", "@every 15s", jobService.RunLotteryDraws},
+		{"score-decay", "30 3 * * *", jobService.RunScoreDecay},
+		{"agent-completion-cleanup", "0 4 * * *", func(ctx context.Context) error {
+			_, err := agentCompletionSvc.CleanupExpired(ctx)
+			return err
+		}},
+	} {
+		if err := sched.AddJob(job.name, job.spec, 0, job.run); err != nil {
+			utils.Logger.WithError(err).Fatal("Failed to schedule cron job")
+		}
+	}
+	sched.Start()
+	defer sched.Stop()
 
 	router := mux.NewRouter()
 
@@ -138,11 +192,39 @@ func main() {
 
 	secured.HandleFunc(routes.JobsOpen, jobsController.ListJobsHandler).Methods(http.MethodGet)
 	secured.HandleFunc(routes.JobsMy, jobsController.ListMyJobsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.JobsSearch, jobsController.SearchJobsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.JobsRelease, jobsController.ReleaseTimeHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.JobsLottery, jobsController.LotteryEntryHandler).Methods(http.MethodGet)
 	secured.HandleFunc(routes.JobsUnaccept, jobsController.UnacceptJobHandler).Methods(http.MethodPost)
 	secured.HandleFunc(routes.JobsCancel, jobsController.CancelJobHandler).Methods(http.MethodPost)
 
+	secured.HandleFunc(routes.JobsScoreHistory, scoreController.MyScoreHistoryHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.JobsPay, payController.MyBreakdownHandler).Methods(http.MethodGet)
+
 	secured.HandleFunc(routes.JobsDefinitionStatus, jobDefsController.SetDefinitionStatusHandler).Methods(http.MethodPatch, http.MethodPut)
 	secured.HandleFunc(routes.JobsDefinitionCreate, jobDefsController.CreateDefinitionHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.JobsDefinitionRollup, jobDefsController.GetDefinitionRollupHandler).Methods(http.MethodGet)
+
+	secured.HandleFunc(routes.OpsSurgePolicies, surgeController.ListPoliciesHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsSurgePolicies, surgeController.UpsertPolicyHandler).Methods(http.MethodPost, http.MethodPut)
+	secured.HandleFunc(routes.OpsSurgeManual, surgeController.ManualSurgeHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsSurgeAudit, surgeController.AuditHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsAllocationMarkets, allocationController.ListMarketsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsAllocationMarkets, allocationController.SetMarketHandler).Methods(http.MethodPut)
+	secured.HandleFunc(routes.OpsEscalationPolicies, escalationController.ListPoliciesHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsEscalationPolicies, escalationController.UpsertPolicyHandler).Methods(http.MethodPost, http.MethodPut)
+	secured.HandleFunc(routes.OpsEscalationExecutions, escalationController.ExecutionsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsEscalationAudit, escalationController.AuditHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.JobsSchedulerRuns, queueController.SchedulerRunsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsQueueDead, queueController.DeadJobsHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsQueueRequeue, queueController.RequeueHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsWorkerScoreHistory, scoreController.OpsScoreHistoryHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsWorkerScoreAdjust, scoreController.AdjustHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsWorkerScoreReverse, scoreController.ReverseHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsWorkerScoreRecompute, scoreController.RecomputeHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsJobsPay, payController.OpsBreakdownHandler).Methods(http.MethodGet)
+	secured.HandleFunc(routes.OpsJobsPayItems, payController.AddItemHandler).Methods(http.MethodPost)
+	secured.HandleFunc(routes.OpsPaySummary, payController.SummaryHandler).Methods(http.MethodGet)
 
 	attestationRepo := repositories.NewAttestationRepository(application.DB)
 	challengeRepo := repositories.NewAttestationChallengeRepository(application.DB)
@@ -169,35 +251,6 @@ func main() {
 	locationSecured.HandleFunc(routes.JobsVerifyUnitPhoto, jobsController.VerifyPhotoHandler).Methods(http.MethodPost)
 	locationSecured.HandleFunc(routes.JobsDumpBags, jobsController.DumpBagsHandler).Methods(http.MethodPost)
 
-	c := cron.New()
-	_, dailyErr := c.AddFunc("5 0 * * *", func() {
-		if e := jobScheduler.RunDailyWindowMaintenance(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("Scheduled daily maintenance failed")
-		}
-	})
-	if dailyErr != nil {
-		utils.Logger.WithError(dailyErr).Fatal("Failed to schedule daily maintenance cron")
-	}
-
-	_, jcasErr := c.AddFunc("@every 2m", func() {
-		if e := escalationService.RunEscalationCheck(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("JCAS escalation check failed")
-		}
-	})
-	if jcasErr != nil {
-		utils.Logger.WithError(jcasErr).Fatal("Failed to schedule JCAS escalation cron")
-	}
-
-	_, cleanupErr := c.AddFunc("0 4 * * *", func() {
-		if _, e := agentCompletionSvc.CleanupExpired(context.Background()); e != nil {
-			utils.Logger.WithError(e).Error("agent completion cleanup failed")
-		}
-	})
-	if cleanupErr != nil {
-		utils.Logger.WithError(cleanupErr).Fatal("Failed to schedule agent completion cleanup cron")
-	}
-	c.Start()
-
 	allowedOrigins := []string{cfg.AppUrl}
 	if !cfg.LDFlag_CORSHighSecurity {
 		allowedOrigins = append(allowedOrigins, utils.CORSLowSecurityAllowedOriginLocalhost)
@@ -825,7 +825,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// UPDATED: Use a hardcoded, predictable UUID
 			ID:           uuid.MustParse(seeding.HistoricalJobWeekBeforeLast1),
 			ServiceDate:  weekBeforeLastStart.AddDate(0, 0, 1),
-			EffectivePay: 20.00,
+			EffectivePay: models.USD(2000),
 			CheckInAt:    utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 1).Add(17 * time.Hour)),
 			CheckOutAt:   utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 1).Add(17 * time.Hour).Add(50 * time.Minute)),
 		},
@@ -833,7 +833,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// UPDATED: Use a hardcoded, predictable UUID
 			ID:           uuid.MustParse(seeding.HistoricalJobWeekBeforeLast2),
 			ServiceDate:  weekBeforeLastStart.AddDate(0, 0, 3),
-			EffectivePay: 25.00,
+			EffectivePay: models.USD(2500),
 			CheckInAt:    utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 3).Add(18 * time.Hour)),
 			CheckOutAt:   utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 3).Add(18 * time.Hour).Add(60 * time.Minute)),
 		},
@@ -841,7 +841,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// UPDATED: Use a hardcoded, predictable UUID
 			ID:           uuid.MustParse(seeding.HistoricalJobWeekBeforeLast3),
 			ServiceDate:  weekBeforeLastStart.AddDate(0, 0, 5),
-			EffectivePay: 22.00,
+			EffectivePay: models.USD(2200),
 			CheckInAt:    utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 5).Add(19 * time.Hour)),
 			CheckOutAt:   utils.Ptr(weekBeforeLastStart.AddDate(0, 0, 5).Add(19 * time.Hour).Add(55 * time.Minute)),
 		},
@@ -854,7 +854,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// UPDATED: Use a hardcoded, predictable UUID
 			ID:           uuid.MustParse(seeding.HistoricalJobLastWeek1),
 			ServiceDate:  lastWeekStart.AddDate(0, 0, 0),
-			EffectivePay: 30.00,
+			EffectivePay: models.USD(3000),
 			CheckInAt:    utils.Ptr(lastWeekStart.AddDate(0, 0, 0).Add(16 * time.Hour)),
 			CheckOutAt:   utils.Ptr(lastWeekStart.AddDate(0, 0, 0).Add(16 * time.Hour).Add(65 * time.Minute)),
 		},
@@ -862,7 +862,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// UPDATED: Use a hardcoded, predictable UUID
 			ID:           uuid.MustParse(seeding.HistoricalJobLastWeek2),
 			ServiceDate:  lastWeekStart.AddDate(0, 0, 2),
-			EffectivePay: 28.00,
+			EffectivePay: models.USD(2800),
 			CheckInAt:    utils.Ptr(lastWeekStart.AddDate(0, 0, 2).Add(20 * time.Hour)),
 			CheckOutAt:   utils.Ptr(lastWeekStart.AddDate(0, 0, 2).Add(20 * time.Hour).Add(62 * time.Minute)),
 		},
@@ -873,7 +873,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 		// UPDATED: Use a hardcoded, predictable UUID
 		ID:           uuid.MustParse("cccccccc-cccc-4ccc-cccc-cccccccccccc"),
 		ServiceDate:  todayInBusinessTZ,
-		EffectivePay: 25.00,
+		EffectivePay: models.USD(2500),
 		CheckInAt:    utils.Ptr(nowInBusinessTZ.Add(-45 * time.Minute)),
 		CheckOutAt:   utils.Ptr(nowInBusinessTZ.Add(-5 * time.Minute)),
 	}
@@ -894,15 +894,21 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 
 		// Use raw SQL to insert, avoiding ON CONFLICT issues with existing active jobs.
 		_, err := db.Exec(ctx, `
-            INSERT INTO job_instances (
-                id, definition_id, service_date, status,
-                assigned_worker_id, effective_pay, check_in_at, check_out_at,
-                excluded_worker_ids, assign_unassign_count, flagged_for_review,
-                created_at, updated_at, row_version
-            ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, '{}', 0, FALSE, NOW(), NOW(), 1)
+            WITH ins AS (
+                INSERT INTO job_instances (
+                    id, definition_id, service_date, status,
+                    assigned_worker_id, effective_pay_cents, check_in_at, check_out_at,
+                    excluded_worker_ids, assign_unassign_count, flagged_for_review,
+                    created_at, updated_at, row_version
+                ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, '{}', 0, FALSE, NOW(), NOW(), 1)
+                RETURNING id, effective_pay_cents
+            )
+            INSERT INTO job_pay_items (id, job_instance_id, kind, amount_cents, description)
+            SELECT gen_random_uuid(), id, $9, effective_pay_cents, $10 FROM ins
         `,
 			job.ID, job.DefinitionID, job.ServiceDate, job.Status,
 			job.AssignedWorkerID, job.EffectivePay, job.CheckInAt, job.CheckOutAt,
+			models.PayItemBase, models.PayItemBase.Label(),
 		)
 		if err != nil {
 			// It's possible an active job for this day already exists. Log as a warning and continue.
@@ -914,7 +920,7 @@ func seedPastCompletedInstances(ctx context.Context, db repositories.DB, defID u
 			// Return the error to halt seeding if it's not a unique violation.
 			return err
 		} else {
-			utils.Logger.Infof("Seeded COMPLETED job instance for date %s with pay $%.2f", job.ServiceDate.Format("2006-01-02"), job.EffectivePay)
+			utils.Logger.Infof("Seeded COMPLETED job instance for date %s with pay %s", job.ServiceDate.Format("2006-01-02"), job.EffectivePay)
 		}
 	}
 
@@ -8,11 +8,13 @@ import (
 	"encoding/pem"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/launchdarkly/go-sdk-common/v3/ldcontext"
 	ld "github.com/launchdarkly/go-server-sdk/v7"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
@@ -55,6 +57,8 @@ type Config struct {
 	LDFlag_CORSHighSecurity              bool
 	LDFlag_OpenAIPhotoVerification        bool
 	LDFlag_NotifyJobStatuses             bool
+	LDFlag_OpsUserIDs                    []string // may manage surge policies and manual surges
+	LDFlag_ScoringModel                  *models.ScoringModel
 }
 
 const (
@@ -252,6 +256,27 @@ func LoadConfig() *Config {
 	}
 	utils.Logger.Debugf("notify_job_statuses flag: %t", notifyJobStatusesFlag)
 
+	// Comma-separated user IDs allowed to use ops endpoints
+	opsUserIDsFlag, err := ldClient.StringVariation("ops_user_ids", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving ops_user_ids flag")
+	}
+	utils.Logger.Debugf("ops_user_ids flag: %s", opsUserIDsFlag)
+	opsUserIDs := utils.ParseOpsUserIDs(opsUserIDsFlag)
+
+	// Reliability scoring model as JSON; empty keeps the built-in default
+	scoringModelFlag, err := ldClient.StringVariation("scoring_model", ctx, "")
+	if err != nil {
+		utils.Logger.WithError(err).Fatal("Error retrieving scoring_model flag")
+	}
+	utils.Logger.Debugf("scoring_model flag: %s", scoringModelFlag)
+	scoringModel := models.DefaultScoringModel()
+	if strings.TrimSpace(scoringModelFlag) != "" {
+		if scoringModel, err = models.ParseScoringModel([]byte(scoringModelFlag)); err != nil {
+			utils.Logger.WithError(err).Fatal("Invalid scoring_model flag")
+		}
+	}
+
 	var openaiKey string
 	if openaiPhotoFlag {
 		val, ok := appSecrets["OPENAI_API_KEY"]
@@ -336,6 +361,8 @@ func LoadConfig() *Config {
 		LDFlag_CORSHighSecurity:              corsHighSecurityFlag,
 		LDFlag_OpenAIPhotoVerification:        openaiPhotoFlag,
 		LDFlag_NotifyJobStatuses:             notifyJobStatusesFlag,
+		LDFlag_OpsUserIDs:                    opsUserIDs,
+		LDFlag_ScoringModel:                  scoringModel,
 	}
 }
 
@@ -14,6 +14,25 @@ const (
 	DaysToSeedAhead                = 7 // How many days ahead to seed new instances
 	MinJobDefinitionStartWindowMinutes       = 90 // Min duration between earliest/latest start
 	MinTimeBeforeLatestStartForHintMinutes = 50 // Hint must be at least this many mins before latest start
+	MaxJobSegments                 = 10 // Upper bound on workers a single definition can be split across
+)
+
+// Staggered release of jobs entering the listing window
+const (
+	MaxShadowReleaseDelay  = 120 * time.Minute // score 0 waits this long; score 100 waits none
+	TenantReleaseAdvantage = time.Hour         // residents see jobs at their own property early
+)
+
+// Lottery allocation: weight = (score/100)^2 / (1 + miles/LotteryDistanceScaleMiles)
+const (
+	LotteryDistanceScaleMiles = 10.0
+	LotteryDrawBatchSize      = 100 // instances resolved per draw tick
+)
+
+// Job search: a max_travel_minutes filter is first narrowed by straight-line
+// distance at this speed, then checked exactly against the routed time.
+const (
+	SearchMaxTravelSpeedMPH = 80.0
 )
 
 // Time windows relative to a job's LATEST_START_TIME
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// AllocationController serves the ops-only endpoints that choose how a
+// market's released jobs are handed out.
+type AllocationController struct {
+	jobService *services.JobService
+}
+
+func NewAllocationController(js *services.JobService) *AllocationController {
+	return &AllocationController{jobService: js}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/allocation/markets
+// ----------------------------------------------------------------
+func (c *AllocationController) ListMarketsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	markets, err := c.jobService.ListMarketAllocations(r.Context())
+	if err != nil {
+		utils.Logger.WithError(err).Error("ListMarketAllocations error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to list market allocation modes", nil, err)
+		return
+	}
+	if markets == nil {
+		markets = []*models.MarketAllocation{}
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.MarketAllocationsResponse{Markets: markets})
+}
+
+// ----------------------------------------------------------------
+// PUT /api/v1/ops/allocation/markets
+// ----------------------------------------------------------------
+func (c *AllocationController) SetMarketHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.MarketAllocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	a, err := c.jobService.SetMarketAllocation(r.Context(), actorID, req)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInvalidPayload) {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("SetMarketAllocation error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to save market allocation mode", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, a)
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// EscalationController serves the ops-only escalation policy endpoints.
+type EscalationController struct {
+	jobService        *services.JobService
+	escalationService *services.JobEscalationService
+}
+
+func NewEscalationController(js *services.JobService, es *services.JobEscalationService) *EscalationController {
+	return &EscalationController{jobService: js, escalationService: es}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/escalation/policies
+// ----------------------------------------------------------------
+func (c *EscalationController) ListPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	policies, err := c.escalationService.ListEscalationPolicies(r.Context())
+	if err != nil {
+		utils.Logger.WithError(err).Error("ListEscalationPolicies error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to list escalation policies", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.EscalationPoliciesResponse{Policies: policies})
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/escalation/policies  (create)
+// PUT  /api/v1/ops/escalation/policies  (replace; body.id required)
+// ----------------------------------------------------------------
+func (c *EscalationController) UpsertPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.EscalationPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+	if (r.Method == http.MethodPut) != (req.ID != nil) {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "id is required for PUT and not allowed for POST", nil, nil)
+		return
+	}
+
+	policy, err := c.escalationService.UpsertEscalationPolicy(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrInvalidPayload):
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+		case errors.Is(err, internal_utils.ErrEscalationPolicyNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Escalation policy not found", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("UpsertEscalationPolicy error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to save escalation policy", nil, err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	utils.RespondWithJSON(w, status, policy)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/escalation/executions?instance_id=
+// ----------------------------------------------------------------
+func (c *EscalationController) ExecutionsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+
+	instanceID, err := uuid.Parse(r.URL.Query().Get("instance_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "instance_id is required", nil, err)
+		return
+	}
+
+	resp, err := c.escalationService.ListEscalationExecutions(r.Context(), instanceID)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInstanceNotFound) {
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Job instance not found", nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("ListEscalationExecutions error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load escalation history", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/escalation/audit?policy_id=&limit=
+// ----------------------------------------------------------------
+func (c *EscalationController) AuditHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	var policyID *uuid.UUID
+	if v := q.Get("policy_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "invalid policy_id", nil, err)
+			return
+		}
+		policyID = &id
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	events, err := c.escalationService.ListEscalationAuditEvents(r.Context(), policyID, limit)
+	if err != nil {
+		utils.Logger.WithError(err).Error("ListEscalationAuditEvents error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load escalation audit log", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.EscalationAuditResponse{Events: events})
+}
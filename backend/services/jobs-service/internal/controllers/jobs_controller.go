@@ -19,6 +19,7 @@ import (
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
 	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
@@ -127,6 +128,138 @@ func (c *JobsController) ListMyJobsHandler(w http.ResponseWriter, r *http.Reques
 	utils.RespondWithJSON(w, http.StatusOK, resp)
 }
 
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/search
+// ----------------------------------------------------------------
+func (c *JobsController) SearchJobsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctxUserID := ctx.Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusUnauthorized, utils.ErrCodeUnauthorized,
+			"No userID in context", nil, nil,
+		)
+		return
+	}
+
+	q, loc, err := parseSearchQueryAndLocation(r)
+	if err != nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusBadRequest, utils.ErrCodeInvalidPayload,
+			err.Error(), nil, nil,
+		)
+		return
+	}
+
+	resp, svcErr := c.jobService.SearchOpenJobs(ctx, ctxUserID.(string), q, loc)
+	if svcErr != nil {
+		if errors.Is(svcErr, internal_utils.ErrInvalidCursor) || errors.Is(svcErr, internal_utils.ErrInvalidPayload) {
+			utils.RespondErrorWithCode(
+				w, http.StatusBadRequest, utils.ErrCodeInvalidPayload,
+				svcErr.Error(), nil, svcErr,
+			)
+			return
+		}
+		utils.Logger.WithError(svcErr).Error("Failed to search jobs")
+		utils.RespondErrorWithCode(
+			w, http.StatusInternalServerError, utils.ErrCodeInternal,
+			"Failed to search jobs", nil, svcErr,
+		)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/release-time
+// ?instance_id=...            – a specific job
+// ?date=YYYY-MM-DD&lat=&lng=  – any job on that date, in the worker's zone
+// ----------------------------------------------------------------
+func (c *JobsController) ReleaseTimeHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctxUserID := ctx.Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusUnauthorized, utils.ErrCodeUnauthorized,
+			"No userID in context", nil, nil,
+		)
+		return
+	}
+
+	q, loc, err := parseReleaseTimeQuery(r)
+	if err != nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusBadRequest, utils.ErrCodeInvalidPayload,
+			err.Error(), nil, nil,
+		)
+		return
+	}
+
+	resp, svcErr := c.jobService.GetReleaseTime(ctx, ctxUserID.(string), q, loc)
+	if svcErr != nil {
+		if errors.Is(svcErr, internal_utils.ErrInstanceNotFound) {
+			utils.RespondErrorWithCode(
+				w, http.StatusNotFound, utils.ErrCodeNotFound,
+				"Job instance not found", nil, svcErr,
+			)
+			return
+		}
+		utils.Logger.WithError(svcErr).Error("Failed to compute release time")
+		utils.RespondErrorWithCode(
+			w, http.StatusInternalServerError, utils.ErrCodeInternal,
+			"Failed to compute release time", nil, svcErr,
+		)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/lottery?instance_id=...
+// Status of the caller's lottery entry; LOST entries carry a loss_reason.
+// ----------------------------------------------------------------
+func (c *JobsController) LotteryEntryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctxUserID := ctx.Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusUnauthorized, utils.ErrCodeUnauthorized,
+			"No userID in context", nil, nil,
+		)
+		return
+	}
+
+	instanceID, err := uuid.Parse(r.URL.Query().Get("instance_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(
+			w, http.StatusBadRequest, utils.ErrCodeInvalidPayload,
+			"instance_id is required", nil, err,
+		)
+		return
+	}
+
+	resp, svcErr := c.jobService.GetLotteryEntry(ctx, ctxUserID.(string), instanceID)
+	if svcErr != nil {
+		if errors.Is(svcErr, internal_utils.ErrLotteryEntryNotFound) {
+			utils.RespondErrorWithCode(
+				w, http.StatusNotFound, utils.ErrCodeNotFound,
+				"No lottery entry for this job", nil, svcErr,
+			)
+			return
+		}
+		utils.Logger.WithError(svcErr).Error("Failed to load lottery entry")
+		utils.RespondErrorWithCode(
+			w, http.StatusInternalServerError, utils.ErrCodeInternal,
+			"Failed to load lottery entry", nil, svcErr,
+		)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
 // ----------------------------------------------------------------
 // POST /api/v1/jobs/accept
 // *** device-attested + minimal location check
@@ -175,6 +308,10 @@ func (c *JobsController) AcceptJobHandler(w http.ResponseWriter, r *http.Request
 	)
 	if err != nil {
 		switch e := err.(type) {
+		case *internal_utils.LotteryPendingError:
+			// Not an error for the client: the accept was entered into the draw.
+			utils.RespondWithJSON(w, http.StatusAccepted, dtos.NewLotteryEntryDTO(e.Entry))
+			return
 		case *internal_utils.RowVersionConflictError:
 			utils.RespondErrorWithCode(
 				w,
@@ -754,3 +891,117 @@ func parseListQueryAndLocation(r *http.Request) (dtos.ListJobsQuery, *time.Locat
 	}
 	return q, loc, nil
 }
+
+// ----------------------------------------------------------------
+// parseSearchQueryAndLocation ...
+// ----------------------------------------------------------------
+func parseSearchQueryAndLocation(r *http.Request) (dtos.SearchJobsQuery, *time.Location, error) {
+	base, loc, err := parseListQueryAndLocation(r)
+	if err != nil {
+		return dtos.SearchJobsQuery{}, nil, err
+	}
+	v := r.URL.Query()
+
+	q := dtos.SearchJobsQuery{
+		Lat:    base.Lat,
+		Lng:    base.Lng,
+		Size:   min(base.Size, 100),
+		Cursor: v.Get("cursor"),
+		Sort:   v.Get("sort"),
+	}
+
+	if s := v.Get("min_pay"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f < 0 {
+			return q, nil, fmt.Errorf("invalid min_pay param")
+		}
+		q.MinPay = &f
+	}
+	if s := v.Get("max_distance_miles"); s != "" {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || f <= 0 {
+			return q, nil, fmt.Errorf("invalid max_distance_miles param")
+		}
+		q.MaxDistanceMiles = &f
+	}
+	if s := v.Get("max_travel_minutes"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return q, nil, fmt.Errorf("invalid max_travel_minutes param")
+		}
+		q.MaxTravelMinutes = &n
+	}
+	if s := v.Get("max_estimated_minutes"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return q, nil, fmt.Errorf("invalid max_estimated_minutes param")
+		}
+		q.MaxEstimatedMinutes = &n
+	}
+	for key, dst := range map[string]**time.Time{"start_date": &q.StartDate, "end_date": &q.EndDate} {
+		if s := v.Get(key); s != "" {
+			d, err := time.ParseInLocation("2006-01-02", s, loc)
+			if err != nil {
+				return q, nil, fmt.Errorf("invalid %s param, expected YYYY-MM-DD", key)
+			}
+			*dst = &d
+		}
+	}
+	for _, s := range v["property_id"] {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return q, nil, fmt.Errorf("invalid property_id param: %s", s)
+		}
+		q.PropertyIDs = append(q.PropertyIDs, id)
+	}
+	for _, s := range v["vehicle_requirement"] {
+		switch vr := models.VehicleRequirementType(s); vr {
+		case models.VehicleNone, models.VehicleTruck, models.VehicleLargeTruck:
+			q.VehicleRequirements = append(q.VehicleRequirements, vr)
+		default:
+			return q, nil, fmt.Errorf("invalid vehicle_requirement param: %s", s)
+		}
+	}
+
+	return q, loc, nil
+}
+
+func parseReleaseTimeQuery(r *http.Request) (dtos.ReleaseTimeQuery, *time.Location, error) {
+	params := r.URL.Query()
+	instStr, dateStr := params.Get("instance_id"), params.Get("date")
+	if (instStr == "") == (dateStr == "") {
+		return dtos.ReleaseTimeQuery{}, nil,
+			fmt.Errorf("exactly one of instance_id or date is required")
+	}
+
+	if instStr != "" {
+		id, err := uuid.Parse(instStr)
+		if err != nil {
+			return dtos.ReleaseTimeQuery{}, nil,
+				fmt.Errorf("invalid instance_id param: %w", err)
+		}
+		return dtos.ReleaseTimeQuery{InstanceID: &id}, nil, nil
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return dtos.ReleaseTimeQuery{}, nil,
+			fmt.Errorf("invalid date param, expected YYYY-MM-DD: %w", err)
+	}
+	lat, latErr := strconv.ParseFloat(params.Get("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(params.Get("lng"), 64)
+	if latErr != nil || lngErr != nil {
+		return dtos.ReleaseTimeQuery{}, nil,
+			fmt.Errorf("lat and lng are required with date")
+	}
+
+	tzName := latlong.LookupZoneName(lat, lng)
+	if tzName == "" {
+		tzName = "UTC"
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		loc = time.UTC
+	}
+	return dtos.ReleaseTimeQuery{Date: date}, loc, nil
+}
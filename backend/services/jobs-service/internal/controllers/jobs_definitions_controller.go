@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -113,3 +114,42 @@ func (c *JobDefinitionsController) SetDefinitionStatusHandler(w http.ResponseWri
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GET /api/v1/manager/jobs/definition/rollup?definition_id=...&date=YYYY-MM-DD
+// Returns one roll-up status for the night across every segment of the definition.
+func (c *JobDefinitionsController) GetDefinitionRollupHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pmUserID := ctx.Value(middleware.ContextKeyUserID)
+	if pmUserID == nil {
+		utils.RespondErrorWithCode(w, http.StatusForbidden, utils.ErrCodeUnauthorized, "No manager ID in context", nil, nil)
+		return
+	}
+
+	q := r.URL.Query()
+	defID, err := uuid.Parse(q.Get("definition_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "definition_id is required", nil, err)
+		return
+	}
+	serviceDate, err := time.Parse("2006-01-02", q.Get("date"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "date must be YYYY-MM-DD", nil, err)
+		return
+	}
+
+	resp, err := c.jobService.GetDefinitionRollup(ctx, pmUserID.(string), defID, serviceDate)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrDefinitionNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Job definition not found", nil, err)
+		case errors.Is(err, internal_utils.ErrNotDefinitionOwner):
+			utils.RespondErrorWithCode(w, http.StatusForbidden, utils.ErrCodeUnauthorized, "Job definition belongs to another manager", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("GetDefinitionRollup error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Could not load job roll-up", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
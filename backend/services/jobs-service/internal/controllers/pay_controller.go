@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// PayController serves the per-job pay ledger: a worker's breakdown of
+// their own job, and the ops views for reading any job's ledger, adding
+// adjustments and clawbacks, and totalling pay by kind.
+type PayController struct {
+	jobService *services.JobService
+}
+
+func NewPayController(js *services.JobService) *PayController {
+	return &PayController{jobService: js}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/pay?instance_id=
+// ----------------------------------------------------------------
+func (c *PayController) MyBreakdownHandler(w http.ResponseWriter, r *http.Request) {
+	ctxUserID := r.Context().Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "No userID in context", nil, nil)
+		return
+	}
+	workerID, err := uuid.Parse(ctxUserID.(string))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "Invalid userID in context", nil, err)
+		return
+	}
+	c.respondBreakdown(w, r, workerID, false)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/jobs/pay?instance_id=
+// ----------------------------------------------------------------
+func (c *PayController) OpsBreakdownHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+	c.respondBreakdown(w, r, actorID, true)
+}
+
+func (c *PayController) respondBreakdown(w http.ResponseWriter, r *http.Request, callerID uuid.UUID, ops bool) {
+	instanceID, err := uuid.Parse(r.URL.Query().Get("instance_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "instance_id is required", nil, err)
+		return
+	}
+
+	resp, err := c.jobService.PayBreakdown(r.Context(), callerID, instanceID, ops)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrInstanceNotFound), errors.Is(err, internal_utils.ErrNotAssignedWorker):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Job instance not found", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("PayBreakdown error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load pay breakdown", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/jobs/pay/items
+// ----------------------------------------------------------------
+func (c *PayController) AddItemHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.PayItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	item, err := c.jobService.AddPayItem(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrInvalidPayload):
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+		case errors.Is(err, internal_utils.ErrInstanceNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Job instance not found", nil, err)
+		case errors.Is(err, internal_utils.ErrWrongStatus):
+			utils.RespondErrorWithCode(w, http.StatusConflict, err.Error(), "Pay can't be changed on canceled or retired jobs", nil, err)
+		case errors.Is(err, utils.ErrPayBelowZero):
+			utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, "The job's pay can't go below zero", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("AddPayItem error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to add pay item", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, item)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/pay/summary?start=YYYY-MM-DD&end=YYYY-MM-DD
+// ----------------------------------------------------------------
+func (c *PayController) SummaryHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	start, err := time.Parse("2006-01-02", q.Get("start"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "start must be YYYY-MM-DD", nil, err)
+		return
+	}
+	end, err := time.Parse("2006-01-02", q.Get("end"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "end must be YYYY-MM-DD", nil, err)
+		return
+	}
+
+	resp, err := c.jobService.PaySummary(r.Context(), start, end)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInvalidPayload) {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("PaySummary error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to summarize pay", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// QueueController exposes the service's background work to ops: dead-lettered
+// jobs and the scheduler's run history.
+type QueueController struct {
+	jobService *services.JobService
+	queue      *utils.JobQueue
+	sched      *utils.Scheduler
+}
+
+func NewQueueController(js *services.JobService, q *utils.JobQueue, sched *utils.Scheduler) *QueueController {
+	return &QueueController{jobService: js, queue: q, sched: sched}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/scheduler/runs?limit=
+// ----------------------------------------------------------------
+func (c *QueueController) SchedulerRunsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	c.sched.RunsHandler(w, r)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/queue/dead?limit=
+// ----------------------------------------------------------------
+func (c *QueueController) DeadJobsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	c.queue.DeadJobsHandler(w, r)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/queue/requeue?id=
+// ----------------------------------------------------------------
+func (c *QueueController) RequeueHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+	utils.Logger.Infof("Ops user %s requeueing background job %s", actorID, r.URL.Query().Get("id"))
+	c.queue.RequeueDeadJobHandler(w, r)
+}
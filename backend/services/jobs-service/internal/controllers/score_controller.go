@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// ScoreController serves the reliability score ledger: a worker's own
+// timeline, and the ops views for reading, adjusting and reversing, and for
+// trying a scoring model out against real history.
+type ScoreController struct {
+	jobService *services.JobService
+}
+
+func NewScoreController(js *services.JobService) *ScoreController {
+	return &ScoreController{jobService: js}
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/jobs/score/history?cursor=&limit=
+// ----------------------------------------------------------------
+func (c *ScoreController) MyScoreHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctxUserID := r.Context().Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "No userID in context", nil, nil)
+		return
+	}
+	workerID, err := uuid.Parse(ctxUserID.(string))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "Invalid userID in context", nil, err)
+		return
+	}
+	c.respondHistory(w, r, workerID, false)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/workers/score/history?worker_id=&cursor=&limit=
+// ----------------------------------------------------------------
+func (c *ScoreController) OpsScoreHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	workerID, err := uuid.Parse(r.URL.Query().Get("worker_id"))
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "worker_id is required", nil, err)
+		return
+	}
+	c.respondHistory(w, r, workerID, true)
+}
+
+func (c *ScoreController) respondHistory(w http.ResponseWriter, r *http.Request, workerID uuid.UUID, ops bool) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	resp, err := c.jobService.ScoreHistory(r.Context(), workerID, q.Get("cursor"), limit, ops)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInvalidCursor) {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid cursor", nil, err)
+			return
+		}
+		if errors.Is(err, internal_utils.ErrWorkerNotFound) {
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Worker not found", nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("ScoreHistory error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load score history", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/workers/score/adjust
+// ----------------------------------------------------------------
+func (c *ScoreController) AdjustHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.ManualScoreAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	entry, err := c.jobService.AdjustScoreManually(r.Context(), actorID, req)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrWorkerNotFound) {
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Worker not found", nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("AdjustScoreManually error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to adjust score", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, entry)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/workers/score/reverse
+// ----------------------------------------------------------------
+func (c *ScoreController) ReverseHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.ScoreReversalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	entry, err := c.jobService.ReverseScoreEvent(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrScoreEventNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Score event not found", nil, err)
+		case errors.Is(err, utils.ErrScoreEventAlreadyReversed):
+			utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, "Score event was already reversed", nil, err)
+		case errors.Is(err, utils.ErrScoreEventNotReversible):
+			utils.RespondErrorWithCode(w, http.StatusConflict, utils.ErrCodeConflict, "A reversal can't itself be reversed", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("ReverseScoreEvent error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to reverse score event", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusCreated, entry)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/workers/score/recompute
+// ----------------------------------------------------------------
+func (c *ScoreController) RecomputeHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+
+	var req dtos.ScoreRecomputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	resp, err := c.jobService.RecomputeScores(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, internal_utils.ErrInvalidPayload) {
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+			return
+		}
+		utils.Logger.WithError(err).Error("RecomputeScores error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to recompute scores", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
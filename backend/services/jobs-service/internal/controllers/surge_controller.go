@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-middleware"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// SurgeController serves the ops-only surge pricing endpoints. Every write
+// is recorded in surge_audit_events with the caller as actor.
+type SurgeController struct {
+	jobService *services.JobService
+}
+
+func NewSurgeController(js *services.JobService) *SurgeController {
+	return &SurgeController{jobService: js}
+}
+
+var opsValidate = validator.New()
+
+// opsActor returns the caller's ID if they are an ops user, writing the
+// error response otherwise. Shared by every /api/v1/ops handler.
+func opsActor(w http.ResponseWriter, r *http.Request, js *services.JobService) (uuid.UUID, bool) {
+	ctxUserID := r.Context().Value(middleware.ContextKeyUserID)
+	if ctxUserID == nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "No userID in context", nil, nil)
+		return uuid.Nil, false
+	}
+	userID := ctxUserID.(string)
+	if !utils.RequireOpsUser(w, js.OpsUserIDs(), userID) {
+		return uuid.Nil, false
+	}
+	actorID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.RespondErrorWithCode(w, http.StatusUnauthorized, utils.ErrCodeUnauthorized, "Invalid userID in context", nil, err)
+		return uuid.Nil, false
+	}
+	return actorID, true
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/surge/policies
+// ----------------------------------------------------------------
+func (c *SurgeController) ListPoliciesHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+	policies, err := c.jobService.ListSurgePolicies(r.Context())
+	if err != nil {
+		utils.Logger.WithError(err).Error("ListSurgePolicies error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to list surge policies", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.SurgePoliciesResponse{Policies: policies})
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/surge/policies  (create)
+// PUT  /api/v1/ops/surge/policies  (replace; body.id required)
+// ----------------------------------------------------------------
+func (c *SurgeController) UpsertPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.SurgePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+	if (r.Method == http.MethodPut) != (req.ID != nil) {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "id is required for PUT and not allowed for POST", nil, nil)
+		return
+	}
+
+	policy, err := c.jobService.UpsertSurgePolicy(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrInvalidPayload):
+			utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, err.Error(), nil, err)
+		case errors.Is(err, internal_utils.ErrSurgePolicyNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Surge policy not found", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("UpsertSurgePolicy error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to save surge policy", nil, err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	utils.RespondWithJSON(w, status, policy)
+}
+
+// ----------------------------------------------------------------
+// POST /api/v1/ops/surge/manual
+// ----------------------------------------------------------------
+func (c *SurgeController) ManualSurgeHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := opsActor(w, r, c.jobService)
+	if !ok {
+		return
+	}
+
+	var req dtos.ManualSurgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Invalid JSON body", nil, err)
+		return
+	}
+	if err := opsValidate.StructCtx(r.Context(), req); err != nil {
+		utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "Validation failed", err.Error(), nil)
+		return
+	}
+
+	resp, err := c.jobService.ApplyManualSurge(r.Context(), actorID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, internal_utils.ErrInstanceNotFound):
+			utils.RespondErrorWithCode(w, http.StatusNotFound, utils.ErrCodeNotFound, "Job instance not found", nil, err)
+		case errors.Is(err, internal_utils.ErrWrongStatus):
+			utils.RespondErrorWithCode(w, http.StatusConflict, err.Error(), "Only open jobs can be surged", nil, err)
+		default:
+			utils.Logger.WithError(err).Error("ApplyManualSurge error")
+			utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to apply surge", nil, err)
+		}
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, resp)
+}
+
+// ----------------------------------------------------------------
+// GET /api/v1/ops/surge/audit?policy_id=&instance_id=&limit=
+// ----------------------------------------------------------------
+func (c *SurgeController) AuditHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := opsActor(w, r, c.jobService); !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	var policyID, instanceID *uuid.UUID
+	for param, dst := range map[string]**uuid.UUID{"policy_id": &policyID, "instance_id": &instanceID} {
+		if v := q.Get(param); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				utils.RespondErrorWithCode(w, http.StatusBadRequest, utils.ErrCodeInvalidPayload, "invalid "+param, nil, err)
+				return
+			}
+			*dst = &id
+		}
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+
+	events, err := c.jobService.ListSurgeAuditEvents(r.Context(), policyID, instanceID, limit)
+	if err != nil {
+		utils.Logger.WithError(err).Error("ListSurgeAuditEvents error")
+		utils.RespondErrorWithCode(w, http.StatusInternalServerError, utils.ErrCodeInternal, "Failed to load surge audit log", nil, err)
+		return
+	}
+	utils.RespondWithJSON(w, http.StatusOK, dtos.SurgeAuditResponse{Events: events})
+}
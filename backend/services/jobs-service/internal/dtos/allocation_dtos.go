@@ -0,0 +1,20 @@
+package dtos
+
+import (
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+MarketAllocationRequest sets how every property in a market hands out newly
+released jobs via PUT /api/v1/ops/allocation/markets. LotteryWindowSeconds
+defaults to two minutes.
+*/
+type MarketAllocationRequest struct {
+	Market               string                    `json:"market" validate:"required,max=100"`
+	AllocationMode       models.AllocationModeType `json:"allocation_mode" validate:"required,oneof=FIRST_COME LOTTERY"`
+	LotteryWindowSeconds int                       `json:"lottery_window_seconds" validate:"gte=0,lte=3600"`
+}
+
+type MarketAllocationsResponse struct {
+	Markets []*models.MarketAllocation `json:"markets"`
+}
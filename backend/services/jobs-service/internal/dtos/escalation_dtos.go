@@ -0,0 +1,34 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+EscalationPolicyRequest creates (ID nil) or replaces (ID set) an escalation
+policy via POST/PUT /api/v1/ops/escalation/policies.
+*/
+type EscalationPolicyRequest struct {
+	ID       *uuid.UUID              `json:"id,omitempty"`
+	Name     string                  `json:"name" validate:"required"`
+	Scope    models.PolicyScopeType  `json:"scope" validate:"required,oneof=GLOBAL MARKET PROPERTY DEFINITION"`
+	ScopeKey string                  `json:"scope_key"`
+	Steps    []models.EscalationStep `json:"steps" validate:"required,min=1"`
+	Active   *bool                   `json:"active,omitempty"`
+	Reason   string                  `json:"reason" validate:"required"`
+}
+
+type EscalationPoliciesResponse struct {
+	Policies []*models.EscalationPolicy `json:"policies"`
+}
+
+type EscalationAuditResponse struct {
+	Events []*models.EscalationPolicyAuditEvent `json:"events"`
+}
+
+type EscalationExecutionsResponse struct {
+	InstanceID uuid.UUID                         `json:"instance_id"`
+	PolicyID   *uuid.UUID                        `json:"policy_id,omitempty"` // policy that applies now; nil for the built-in one
+	Executions []*models.EscalationStepExecution `json:"executions"`
+}
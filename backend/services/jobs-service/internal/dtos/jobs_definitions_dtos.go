@@ -39,6 +39,11 @@ type CreateJobDefinitionRequest struct {
 	CompletionRules   *models.JobCompletionRules `json:"completion_rules,omitempty" validate:"omitempty"`
 	SupportContact    *models.SupportContact     `json:"support_contact,omitempty" validate:"omitempty"`
 
+	// Optional: split each service date into SegmentCount separately acceptable
+	// instances, grouped by BUILDING or FLOOR. Pay is pro-rated by unit count.
+	SegmentStrategy models.SegmentStrategyType `json:"segment_strategy,omitempty" validate:"omitempty,oneof=NONE BUILDING FLOOR"`
+	SegmentCount    int                        `json:"segment_count,omitempty" validate:"omitempty,gte=1"`
+
 	// Option 1: Provide estimates for each day of the week explicitly.
 	// If this is provided and valid, it takes precedence.
 	DailyPayEstimates []DailyPayEstimateRequest `json:"daily_pay_estimates,omitempty" validate:"omitempty,dive"`
@@ -57,3 +62,28 @@ type SetDefinitionStatusRequest struct {
 	DefinitionID uuid.UUID `json:"definition_id"`
 	NewStatus    string    `json:"new_status"` // e.g. "PAUSED", "ARCHIVED", "DELETED"
 }
+
+// DefinitionRollupSegmentDTO describes one segment of a split job for a single
+// service date.
+type DefinitionRollupSegmentDTO struct {
+	InstanceID       uuid.UUID    `json:"instance_id"`
+	SegmentIndex     int          `json:"segment_index"`
+	Status           string       `json:"status"`
+	AssignedWorkerID *uuid.UUID   `json:"assigned_worker_id,omitempty"`
+	UnitCount        int          `json:"unit_count"`
+	Pay              models.Money `json:"pay"`
+	CheckInAt        *time.Time   `json:"check_in_at,omitempty"`
+	CheckOutAt       *time.Time   `json:"check_out_at,omitempty"`
+}
+
+// DefinitionRollupDTO is the PM-facing view of a definition's night: a single
+// status derived from every segment, plus the per-segment breakdown.
+type DefinitionRollupDTO struct {
+	DefinitionID      uuid.UUID                    `json:"definition_id"`
+	ServiceDate       string                       `json:"service_date"`
+	Status            string                       `json:"status"`
+	SegmentCount      int                          `json:"segment_count"`
+	SegmentsCompleted int                          `json:"segments_completed"`
+	TotalUnits        int                          `json:"total_units"`
+	Segments          []DefinitionRollupSegmentDTO `json:"segments"`
+}
@@ -2,6 +2,7 @@ package dtos
 
 import (
 	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"time" // Import time package
 )
 
@@ -15,6 +16,27 @@ type ListJobsQuery struct {
 	Size int
 }
 
+/*
+SearchJobsQuery is the "request DTO" for GET /api/v1/jobs/search.
+Every filter is optional; Cursor is the opaque NextCursor of a previous page.
+*/
+type SearchJobsQuery struct {
+	Lat    float64
+	Lng    float64
+	Size   int
+	Cursor string
+	Sort   string // distance (straight-line, default), pay (highest first), date
+
+	MinPay              *float64
+	StartDate           *time.Time
+	EndDate             *time.Time
+	MaxDistanceMiles    *float64
+	MaxTravelMinutes    *int
+	PropertyIDs         []uuid.UUID
+	VehicleRequirements []models.VehicleRequirementType
+	MaxEstimatedMinutes *int
+}
+
 /*
 JobInstanceDTO is used by responses listing or returning a single job instance.
 */
@@ -24,7 +46,7 @@ type JobInstanceDTO struct {
 	PropertyID        uuid.UUID     `json:"property_id"`
 	ServiceDate       string        `json:"service_date"`
 	Status            string        `json:"status"`
-	Pay               float64       `json:"pay"`
+	Pay               models.Money  `json:"pay"`
 	Property          PropertyDTO   `json:"property"`
 	NumberOfBuildings int           `json:"number_of_buildings"`
 	Buildings         []BuildingDTO `json:"buildings,omitempty"`
@@ -33,6 +55,10 @@ type JobInstanceDTO struct {
 	Floors            []int16       `json:"floors,omitempty"`
 	TotalUnits        int           `json:"total_units"`
 
+	// Set when the definition is split across several workers.
+	SegmentIndex int `json:"segment_index"`
+	SegmentCount int `json:"segment_count"`
+
 	// NEW: flattened list of units and their verification status
 	UnitVerifications []UnitVerificationDTO `json:"unit_verifications,omitempty"`
 
@@ -110,6 +136,16 @@ type ListJobsResponse struct {
 	Total   int              `json:"total"`
 }
 
+/*
+SearchJobsResponse is the response for GET /api/v1/jobs/search. NextCursor is
+omitted on the last page.
+*/
+type SearchJobsResponse struct {
+	Results    []JobInstanceDTO `json:"results"`
+	Size       int              `json:"size"`
+	NextCursor *string          `json:"next_cursor,omitempty"`
+}
+
 /*
 JobInstanceActionRequest is the simple "instance_id" payload for endpoints like
 accept, unaccept, cancel, etc. that don’t require location data.
@@ -145,3 +181,76 @@ type JobLocationActionRequest struct {
 	Timestamp  int64     `json:"timestamp"`
 	IsMock     bool      `json:"is_mock"`
 }
+
+/*
+ReleaseTimeQuery is the "request DTO" for GET /api/v1/jobs/release-time.
+Exactly one of InstanceID or Date is set.
+*/
+type ReleaseTimeQuery struct {
+	InstanceID *uuid.UUID
+	Date       time.Time
+}
+
+/*
+ReleaseTimeDTO tells a worker when a job becomes visible to them.
+*/
+type ReleaseTimeDTO struct {
+	InstanceID          *uuid.UUID        `json:"instance_id,omitempty"`
+	PropertyID          *uuid.UUID        `json:"property_id,omitempty"`
+	ServiceDate         string            `json:"service_date"`
+	ReleaseAt           time.Time         `json:"release_at"`
+	IsReleased          bool              `json:"is_released"`
+	SecondsUntilRelease int64             `json:"seconds_until_release"`
+	Factors             ReleaseFactorsDTO `json:"factors"`
+}
+
+/*
+ReleaseFactorsDTO breaks ReleaseAt down into the inputs that produced it:
+ReleaseAt = BaseReleaseAt - TenantAdvantageMinutes + ScoreDelayMinutes,
+unless InOpenWindow, in which case the job is visible immediately.
+*/
+type ReleaseFactorsDTO struct {
+	InOpenWindow           bool      `json:"in_open_window"`
+	BaseReleaseAt          time.Time `json:"base_release_at"`
+	ReliabilityScore       int       `json:"reliability_score"`
+	ScoreDelayMinutes      int       `json:"score_delay_minutes"`
+	MaxScoreDelayMinutes   int       `json:"max_score_delay_minutes"`
+	TenantAdvantageMinutes int       `json:"tenant_advantage_minutes"`
+	ReleaseAtPerfectScore  time.Time `json:"release_at_perfect_score"`
+
+	// Only set for date queries by workers who live at a Poof property.
+	TenantPropertyID        *uuid.UUID `json:"tenant_property_id,omitempty"`
+	TenantPropertyReleaseAt *time.Time `json:"tenant_property_release_at,omitempty"`
+}
+
+/*
+LotteryEntryDTO is returned (202) when an accept is entered into a lottery,
+and by GET /api/v1/jobs/lottery while it is pending or once resolved.
+*/
+type LotteryEntryDTO struct {
+	InstanceID       uuid.UUID  `json:"instance_id"`
+	Status           string     `json:"status"`
+	WindowClosesAt   time.Time  `json:"window_closes_at"`
+	ReliabilityScore int        `json:"reliability_score"`
+	DistanceMiles    float64    `json:"distance_miles"`
+	Weight           float64    `json:"weight"`
+	LossReason       *string    `json:"loss_reason,omitempty"`
+	DrawID           *uuid.UUID `json:"draw_id,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	EntrantCount     *int       `json:"entrant_count,omitempty"`
+	TotalWeight      *float64   `json:"total_weight,omitempty"`
+}
+
+func NewLotteryEntryDTO(e *models.JobLotteryEntry) LotteryEntryDTO {
+	return LotteryEntryDTO{
+		InstanceID:       e.InstanceID,
+		Status:           string(e.Status),
+		WindowClosesAt:   e.WindowClosesAt,
+		ReliabilityScore: e.ReliabilityScore,
+		DistanceMiles:    e.DistanceMiles,
+		Weight:           e.Weight,
+		LossReason:       e.LossReason,
+		DrawID:           e.DrawID,
+		ResolvedAt:       e.ResolvedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// PayItem is one line of a job's pay breakdown. ActorID and Details are
+// only filled in for ops.
+type PayItem struct {
+	ID            uuid.UUID          `json:"id"`
+	Kind          models.PayItemKind `json:"kind"`
+	Label         string             `json:"label"`
+	Amount        models.Money       `json:"amount"`
+	Description   string             `json:"description,omitempty"`
+	SurgePolicyID *uuid.UUID         `json:"surge_policy_id,omitempty"`
+	ActorID       *uuid.UUID         `json:"actor_id,omitempty"`
+	Details       map[string]any     `json:"details,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// PayBreakdownResponse is a job's pay ledger. Total is the job's pay.
+type PayBreakdownResponse struct {
+	InstanceID uuid.UUID                 `json:"instance_id"`
+	Status     models.InstanceStatusType `json:"status"`
+	Total      models.Money              `json:"total"`
+	Items      []PayItem                 `json:"items"`
+}
+
+/*
+PayItemRequest adds an ops correction to a job's pay ledger via
+POST /api/v1/ops/jobs/pay/items. Amount is signed dollars: an ADJUSTMENT
+goes either way and a CLAWBACK is negative.
+*/
+type PayItemRequest struct {
+	InstanceID uuid.UUID          `json:"instance_id" validate:"required"`
+	Kind       models.PayItemKind `json:"kind" validate:"required,oneof=ADJUSTMENT CLAWBACK"`
+	Amount     float64            `json:"amount" validate:"required"`
+	Reason     string             `json:"reason" validate:"required"`
+}
+
+// PaySummaryResponse totals completed jobs' pay by item kind for service
+// dates in [Start, End]; SURGE and MANUAL_BONUS are the surge spend.
+type PaySummaryResponse struct {
+	Start      string                `json:"start"`
+	End        string                `json:"end"`
+	Total      models.Money          `json:"total"`
+	SurgeSpend models.Money          `json:"surge_spend"`
+	Kinds      []models.PayKindTotal `json:"kinds"`
+}
@@ -0,0 +1,95 @@
+package dtos
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+ScoreLedgerEntry is one reliability score change. Reason explains the event
+type in worker-facing terms; ActorID and Note are only filled in for ops.
+*/
+type ScoreLedgerEntry struct {
+	ID              uuid.UUID  `json:"id"`
+	EventType       string     `json:"event_type"`
+	Reason          string     `json:"reason"`
+	Delta           int        `json:"delta"`
+	AppliedDelta    int        `json:"applied_delta"`
+	OldScore        int        `json:"old_score"`
+	NewScore        int        `json:"new_score"`
+	JobInstanceID   *uuid.UUID `json:"job_instance_id,omitempty"`
+	ReversesEventID *uuid.UUID `json:"reverses_event_id,omitempty"`
+	ReversedByID    *uuid.UUID `json:"reversed_by_id,omitempty"`
+	ActorID         *uuid.UUID `json:"actor_id,omitempty"`
+	Note            string     `json:"note,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// ScoreHistoryResponse pages back through a worker's score ledger. Pass
+// NextCursor as ?cursor= to get the next page.
+type ScoreHistoryResponse struct {
+	WorkerID         uuid.UUID          `json:"worker_id"`
+	ReliabilityScore int                `json:"reliability_score"`
+	IsBanned         bool               `json:"is_banned"`
+	SuspendedUntil   *time.Time         `json:"suspended_until,omitempty"`
+	Events           []ScoreLedgerEntry `json:"events"`
+	NextCursor       *string            `json:"next_cursor,omitempty"`
+}
+
+// ManualScoreAdjustmentRequest is POST /api/v1/ops/workers/score/adjust.
+type ManualScoreAdjustmentRequest struct {
+	WorkerID      uuid.UUID  `json:"worker_id" validate:"required"`
+	Delta         int        `json:"delta" validate:"required,min=-100,max=100"`
+	JobInstanceID *uuid.UUID `json:"job_instance_id,omitempty"`
+	Note          string     `json:"note" validate:"required"`
+}
+
+// ScoreReversalRequest is POST /api/v1/ops/workers/score/reverse.
+type ScoreReversalRequest struct {
+	EventID uuid.UUID `json:"event_id" validate:"required"`
+	Note    string    `json:"note" validate:"required"`
+}
+
+// ScoreRecomputeRequest is POST /api/v1/ops/workers/score/recompute. Model
+// is a scoring model in the same JSON form as the scoring_model flag; leave
+// it out to replay under the live model. Without WorkerIDs, every worker
+// with score history is replayed, up to Limit.
+type ScoreRecomputeRequest struct {
+	Model     json.RawMessage `json:"model,omitempty"`
+	WorkerIDs []uuid.UUID     `json:"worker_ids,omitempty" validate:"omitempty,max=1000"`
+	Limit     int             `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// ScoreRecomputeWorker compares a worker's score with what the model would
+// give them.
+type ScoreRecomputeWorker struct {
+	WorkerID         uuid.UUID `json:"worker_id"`
+	CurrentScore     int       `json:"current_score"`
+	RecomputedScore  int       `json:"recomputed_score"`
+	Difference       int       `json:"difference"`
+	IsBanned         bool      `json:"is_banned"`
+	WouldBeBanned    bool      `json:"would_be_banned"`
+	WouldBeSuspended bool      `json:"would_be_suspended"`
+}
+
+type ScoreRecomputeSummary struct {
+	Workers        int     `json:"workers"`
+	Raised         int     `json:"raised"`
+	Lowered        int     `json:"lowered"`
+	Unchanged      int     `json:"unchanged"`
+	NewlyBanned    int     `json:"newly_banned"`
+	NoLongerBanned int     `json:"no_longer_banned"`
+	MeanDifference float64 `json:"mean_difference"`
+}
+
+// ScoreRecomputeResponse is a what-if report; nothing is written.
+type ScoreRecomputeResponse struct {
+	Model     *models.ScoringModel   `json:"model"`
+	At        time.Time              `json:"at"`
+	Summary   ScoreRecomputeSummary  `json:"summary"`
+	Workers   []ScoreRecomputeWorker `json:"workers"`
+	Truncated bool                   `json:"truncated"`
+}
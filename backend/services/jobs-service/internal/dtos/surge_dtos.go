@@ -0,0 +1,51 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+SurgePolicyRequest creates (ID nil) or replaces (ID set) a surge policy via
+POST/PUT /api/v1/ops/surge/policies.
+*/
+type SurgePolicyRequest struct {
+	ID            *uuid.UUID             `json:"id,omitempty"`
+	Name          string                 `json:"name" validate:"required"`
+	Scope         models.PolicyScopeType `json:"scope" validate:"required,oneof=GLOBAL MARKET PROPERTY DEFINITION"`
+	ScopeKey      string                 `json:"scope_key"`
+	Stages        []models.SurgeStage    `json:"stages" validate:"required,min=1,dive"`
+	MaxMultiplier float64                `json:"max_multiplier" validate:"gte=0"`
+	MaxPay        *float64               `json:"max_pay,omitempty" validate:"omitempty,gt=0"`
+	Overrides     []models.SurgeOverride `json:"overrides,omitempty"`
+	Active        *bool                  `json:"active,omitempty"`
+	Reason        string                 `json:"reason" validate:"required"`
+}
+
+/*
+ManualSurgeRequest raises pay on one open instance via
+POST /api/v1/ops/surge/manual. The effective policy's caps still apply.
+*/
+type ManualSurgeRequest struct {
+	InstanceID uuid.UUID `json:"instance_id" validate:"required"`
+	Multiplier float64   `json:"multiplier" validate:"gte=1"`
+	Bonus      float64   `json:"bonus" validate:"gte=0"`
+	Reason     string    `json:"reason" validate:"required"`
+}
+
+type ManualSurgeResponse struct {
+	InstanceID uuid.UUID    `json:"instance_id"`
+	Applied    bool         `json:"applied"`
+	OldPay     models.Money `json:"old_pay"`
+	NewPay     models.Money `json:"new_pay"`
+	PolicyID   *uuid.UUID   `json:"policy_id,omitempty"`
+	AuditID    *uuid.UUID   `json:"audit_id,omitempty"`
+}
+
+type SurgePoliciesResponse struct {
+	Policies []*models.SurgePolicy `json:"policies"`
+}
+
+type SurgeAuditResponse struct {
+	Events []*models.SurgeAuditEvent `json:"events"`
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
 	"github.com/stretchr/testify/require"
 )
@@ -88,8 +89,7 @@ func TestManualAgentFlows(t *testing.T) {
 			h.AgentJobCompletionRepo,
 			h.BldgRepo,
 			h.UnitRepo,
-			h.TwilioClient,
-			h.SendGridClient,
+			utils.NewNotifier(nil, h.SendGridClient, h.TwilioClient),
 			cfg,
 		)
 		t.Log("NotifyOnCallAgents function executed.")
@@ -0,0 +1,56 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+func TestEscalationStepAbandonedClaimIsRetried(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	earliest, latest, _ := h.WindowActiveNowInTZ("UTC")
+	p := h.CreateTestProperty(ctx, "EscalationLeaseProp", testPM.ID, 0, 0)
+	defn := h.CreateTestJobDefinition(t, ctx, testPM.ID, p.ID, "EscalationLease",
+		nil, nil, earliest, latest, models.JobStatusActive, nil, models.JobFreqDaily, nil)
+	inst := h.CreateTestJobInstance(t, ctx, defn.ID, time.Now().UTC(), models.InstanceStatusOpen, nil)
+	repo := repositories.NewEscalationPolicyRepository(h.DB)
+
+	claim := func() bool {
+		won, err := repo.ClaimStep(ctx, &models.EscalationStepExecution{
+			ID:         uuid.New(),
+			InstanceID: inst.ID,
+			StepKey:    "expired_cancel",
+			Action:     models.EscalationActionCancel,
+		})
+		require.NoError(t, err)
+		return won
+	}
+	executedKeys := func() map[string]bool {
+		keys, err := repo.ListExecutedStepKeys(ctx, []uuid.UUID{inst.ID})
+		require.NoError(t, err)
+		return keys[inst.ID]
+	}
+
+	require.True(t, claim())
+	// Another worker can't take a claim that is still running.
+	require.False(t, claim())
+	require.True(t, executedKeys()["expired_cancel"])
+
+	// The claimant died before FinishStep; once the lease runs out the step
+	// is due again and can be claimed.
+	_, err := h.DB.Exec(ctx, `
+		UPDATE escalation_step_executions SET executed_at = NOW() - $2 * INTERVAL '1 second'
+		WHERE instance_id = $1`, inst.ID, (repositories.EscalationStepLease + time.Minute).Seconds())
+	require.NoError(t, err)
+	require.False(t, executedKeys()["expired_cancel"])
+	require.True(t, claim())
+	require.False(t, claim())
+}
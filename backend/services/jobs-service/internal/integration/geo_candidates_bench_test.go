@@ -0,0 +1,222 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*────────────────────────────────────────────────────────────────────────────
+  Candidate selection against the database: the geohash prefix query in
+  job_instance_geo.go versus the scan ListOpenJobs did before it (every open
+  instance in the date range, then its definition and property by ID, then
+  the distance check in Go). Each size seeds that many properties spread
+  over the continental US with one open job each.
+
+    go test -tags 'dev_test integration' -run '^$' -bench CandidateSelection ./internal/integration/
+────────────────────────────────────────────────────────────────────────────*/
+
+const benchPropPrefix = "GeoBench "
+
+var benchOrigin = [2]float64{33.5186, -86.8104}
+
+func BenchmarkCandidateSelection(b *testing.B) {
+	ctx := h.Ctx
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	end := start.AddDate(0, 0, constants.DaysToListOpenJobsRange)
+	radius := float64(constants.RadiusMiles)
+
+	for _, n := range []int{1_000, 10_000} {
+		seedBenchCandidates(b, ctx, n, start.AddDate(0, 0, 1))
+
+		previous := func() int {
+			insts, err := h.JobInstRepo.ListInstancesByDateRange(ctx, nil,
+				[]models.InstanceStatusType{models.InstanceStatusOpen}, start, end)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defs := make(map[uuid.UUID]*models.JobDefinition)
+			props := make(map[uuid.UUID]*models.Property)
+			hits := 0
+			for _, inst := range insts {
+				defn, ok := defs[inst.DefinitionID]
+				if !ok {
+					if defn, err = h.JobDefRepo.GetByID(ctx, inst.DefinitionID); err != nil || defn == nil {
+						continue
+					}
+					defs[inst.DefinitionID] = defn
+				}
+				prop, ok := props[defn.PropertyID]
+				if !ok {
+					if prop, err = h.PropertyRepo.GetByID(ctx, defn.PropertyID); err != nil || prop == nil {
+						continue
+					}
+					props[defn.PropertyID] = prop
+				}
+				if !prop.IsDemo && utils.DistanceMiles(benchOrigin[0], benchOrigin[1], prop.Latitude, prop.Longitude) <= radius {
+					hits++
+				}
+			}
+			return hits
+		}
+		geohash := func() int {
+			got, err := h.JobInstRepo.ListInstancesNear(ctx, repositories.NearbyInstanceQuery{
+				Lat:            benchOrigin[0],
+				Lng:            benchOrigin[1],
+				RadiusMiles:    &radius,
+				Statuses:       []models.InstanceStatusType{models.InstanceStatusOpen},
+				StartDate:      start,
+				EndDate:        end,
+				DemoProperties: utils.Ptr(false),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			return len(got)
+		}
+
+		// Both must find the same jobs for the timings to mean anything.
+		if p, g := previous(), geohash(); p != g || g == 0 {
+			b.Fatalf("%d properties: previous scan found %d candidates, geohash query %d", n, p, g)
+		}
+		b.Run(fmt.Sprintf("PreviousScan/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				previous()
+			}
+		})
+		b.Run(fmt.Sprintf("GeohashPrefix/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				geohash()
+			}
+		})
+		deleteBenchCandidates(b, ctx)
+	}
+}
+
+// seedBenchCandidates adds n properties at seeded random points, each with
+// one open job on serviceDate cloned from a template definition.
+func seedBenchCandidates(b *testing.B, ctx context.Context, n int, serviceDate time.Time) {
+	b.Helper()
+	deleteBenchCandidates(b, ctx)
+	b.Cleanup(func() { deleteBenchCandidates(b, ctx) })
+
+	tmplProp := &models.Property{
+		ID:           uuid.New(),
+		ManagerID:    testPM.ID,
+		PropertyName: benchPropPrefix + "template",
+		Address:      "1 Bench St",
+		City:         "Testville",
+		State:        "TS",
+		ZipCode:      "00000",
+		TimeZone:     "UTC",
+		Latitude:     benchOrigin[0],
+		Longitude:    benchOrigin[1],
+	}
+	benchMust(b, h.PropertyRepo.Create(ctx, tmplProp))
+	bldg := &models.PropertyBuilding{ID: uuid.New(), PropertyID: tmplProp.ID, BuildingName: "GeoBench Bldg"}
+	benchMust(b, h.BldgRepo.Create(ctx, bldg))
+	dump := &models.Dumpster{ID: uuid.New(), PropertyID: tmplProp.ID, DumpsterNumber: "GeoBench"}
+	benchMust(b, h.DumpsterRepo.Create(ctx, dump))
+
+	earliest, latest, _ := h.WindowActiveNowInTZ("UTC")
+	estimates := make([]models.DailyPayEstimate, 7)
+	for i := range estimates {
+		estimates[i] = models.DailyPayEstimate{
+			DayOfWeek: time.Weekday(i), BasePay: models.USD(5000), InitialBasePay: models.USD(5000),
+			EstimatedTimeMinutes: 60, InitialEstimatedTimeMinutes: 60,
+		}
+	}
+	tmplDef := &models.JobDefinition{
+		ID:                      uuid.New(),
+		ManagerID:               testPM.ID,
+		PropertyID:              tmplProp.ID,
+		Title:                   "GeoBench",
+		AssignedUnitsByBuilding: []models.AssignedUnitGroup{{BuildingID: bldg.ID, UnitIDs: []uuid.UUID{}, Floors: []int16{1}}},
+		Floors:                  []int16{1},
+		DumpsterIDs:             []uuid.UUID{dump.ID},
+		Frequency:               models.JobFreqDaily,
+		Status:                  models.JobStatusActive,
+		StartDate:               serviceDate.AddDate(0, 0, -1),
+		EarliestStartTime:       earliest,
+		LatestStartTime:         latest,
+		StartTimeHint:           earliest.Add(latest.Sub(earliest) / 2),
+		DailyPayEstimates:       estimates,
+	}
+	benchMust(b, h.JobDefRepo.Create(ctx, tmplDef))
+	tmplInst := &models.JobInstance{
+		ID:           uuid.New(),
+		DefinitionID: tmplDef.ID,
+		ServiceDate:  serviceDate,
+		Status:       models.InstanceStatusOpen,
+		EffectivePay: models.USD(5000),
+	}
+	benchMust(b, h.JobInstRepo.Create(ctx, tmplInst))
+
+	// Bulk copies of the template, one per property, so seeding 10k rows
+	// takes seconds rather than minutes.
+	rng := rand.New(rand.NewSource(42))
+	lats, lngs := make([]float64, n), make([]float64, n)
+	for i := range lats {
+		lats[i], lngs[i] = 25+rng.Float64()*24, -124+rng.Float64()*57
+	}
+	_, err := h.DB.Exec(ctx, `
+		INSERT INTO properties (
+			id, manager_id, property_name, address, city, state, zip_code, time_zone,
+			latitude, longitude, is_demo, created_at
+		)
+		SELECT gen_random_uuid(), $1, $2 || g, '1 Bench St', 'Testville', 'TS', '00000', 'UTC',
+			lat, lng, FALSE, NOW()
+		FROM unnest($3::float8[], $4::float8[]) WITH ORDINALITY AS c(lat, lng, g)`,
+		testPM.ID, benchPropPrefix, lats, lngs)
+	benchMust(b, err)
+	_, err = h.DB.Exec(ctx, `
+		INSERT INTO job_definitions
+		SELECT (jsonb_populate_record(jd, jsonb_build_object('id', gen_random_uuid(), 'property_id', p.id))).*
+		FROM job_definitions jd, properties p
+		WHERE jd.id = $1 AND p.property_name LIKE $2 || '%' AND p.id <> jd.property_id`,
+		tmplDef.ID, benchPropPrefix)
+	benchMust(b, err)
+	_, err = h.DB.Exec(ctx, `
+		INSERT INTO job_instances
+		SELECT (jsonb_populate_record(ji, jsonb_build_object('id', gen_random_uuid(), 'definition_id', jd.id))).*
+		FROM job_instances ji, job_definitions jd
+		WHERE ji.id = $1 AND jd.title = 'GeoBench' AND jd.id <> ji.definition_id`,
+		tmplInst.ID)
+	benchMust(b, err)
+	_, err = h.DB.Exec(ctx, `ANALYZE properties, job_definitions, job_instances`)
+	benchMust(b, err)
+}
+
+func deleteBenchCandidates(b *testing.B, ctx context.Context) {
+	b.Helper()
+	for _, stmt := range []string{
+		`DELETE FROM job_instances WHERE definition_id IN (
+			SELECT jd.id FROM job_definitions jd JOIN properties p ON p.id = jd.property_id
+			WHERE p.property_name LIKE $1 || '%')`,
+		`DELETE FROM job_definitions WHERE property_id IN (SELECT id FROM properties WHERE property_name LIKE $1 || '%')`,
+		`DELETE FROM dumpsters WHERE property_id IN (SELECT id FROM properties WHERE property_name LIKE $1 || '%')`,
+		`DELETE FROM property_buildings WHERE property_id IN (SELECT id FROM properties WHERE property_name LIKE $1 || '%')`,
+		`DELETE FROM properties WHERE property_name LIKE $1 || '%'`,
+	} {
+		_, err := h.DB.Exec(ctx, stmt, benchPropPrefix)
+		benchMust(b, err)
+	}
+}
+
+func benchMust(b *testing.B, err error) {
+	b.Helper()
+	if err != nil {
+		b.Fatal(err)
+	}
+}
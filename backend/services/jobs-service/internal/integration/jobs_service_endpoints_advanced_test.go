@@ -311,14 +311,14 @@ func TestJobSchedulerService(t *testing.T) {
 		nil, nil, earliest, latest, models.JobStatusActive, nil, models.JobFreqDaily, nil)
 
 	targetWeekday := time.Now().UTC().AddDate(0, 0, 7).Weekday()
-	var expectedPay float64
+	var expectedPay models.Money
 	for _, est := range defn.DailyPayEstimates {
 		if est.DayOfWeek == targetWeekday {
 			expectedPay = est.BasePay
 			break
 		}
 	}
-	require.Greater(t, expectedPay, 0.0, "Test setup requires a non-zero base pay for the target weekday")
+	require.True(t, expectedPay.IsPositive(), "Test setup requires a non-zero base pay for the target weekday")
 
 	jobScheduler := services.NewJobSchedulerService(
 		nil,
@@ -331,14 +331,14 @@ func TestJobSchedulerService(t *testing.T) {
 
 	dayPlus7 := services.DateOnly(time.Now().UTC().AddDate(0, 0, 7))
 
-	query := `SELECT effective_pay FROM job_instances WHERE definition_id = $1 AND service_date = $2`
-	var effectivePay float64
+	query := `SELECT effective_pay_cents FROM job_instances WHERE definition_id = $1 AND service_date = $2`
+	var effectivePay models.Money
 	err = h.DB.QueryRow(ctx, query, defn.ID, dayPlus7).Scan(&effectivePay)
 
 	require.NoError(t, err, "Should find the newly created instance for day+7 in the database")
 	require.Equal(t, expectedPay, effectivePay, "The effective_pay of the created instance should match the definition's base_pay")
 
-	t.Logf("JobSchedulerService correctly created an instance for %s with effective_pay=%.2f", dayPlus7.Format("2006-01-02"), effectivePay)
+	t.Logf("JobSchedulerService correctly created an instance for %s with effective_pay=%s", dayPlus7.Format("2006-01-02"), effectivePay)
 }
 
 /*
@@ -125,7 +125,7 @@ func TestListOpenJobs(t *testing.T) {
 			jobDTO := out.Results[0]
 			require.NotEmpty(t, jobDTO.Property.PropertyName)
 			require.NotEmpty(t, jobDTO.Property.Address)
-			require.Greater(t, jobDTO.Pay, 0.0, "Job pay should be positive")
+			require.True(t, jobDTO.Pay.IsPositive(), "Job pay should be positive")
 			require.Greater(t, jobDTO.EstimatedTimeMinutes, 0, "Job estimated time should be positive")
 			require.GreaterOrEqual(t, jobDTO.TotalUnits, 0)
 			require.NotNil(t, jobDTO.Floors)
@@ -343,7 +343,7 @@ func TestJobDefinitionFlow(t *testing.T) {
 		require.NotNil(t, createdDef)
 		require.Len(t, createdDef.DailyPayEstimates, 7, "Should have 7 daily estimates from global init")
 		for _, est := range createdDef.DailyPayEstimates {
-			require.Equal(t, 80.0, est.BasePay)
+			require.Equal(t, models.USD(8000), est.BasePay)
 			require.Equal(t, 75, est.EstimatedTimeMinutes)
 			require.Equal(t, 75, est.InitialEstimatedTimeMinutes)
 		}
@@ -393,12 +393,12 @@ func TestJobDefinitionFlow(t *testing.T) {
 
 		monEst := createdDef.GetDailyEstimate(time.Monday)
 		require.NotNil(t, monEst)
-		require.Equal(t, 70.0, monEst.BasePay)
+		require.Equal(t, models.USD(7000), monEst.BasePay)
 		require.Equal(t, 65, monEst.EstimatedTimeMinutes)
 
 		wedEst := createdDef.GetDailyEstimate(time.Wednesday)
 		require.NotNil(t, wedEst)
-		require.Equal(t, 75.0, wedEst.BasePay)
+		require.Equal(t, models.USD(7500), wedEst.BasePay)
 
 		tueEst := createdDef.GetDailyEstimate(time.Tuesday)
 		require.Nil(t, tueEst, "Tuesday estimate should not exist for this CUSTOM definition")
@@ -0,0 +1,131 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/routes"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func TestSearchOpenJobs(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	earliest, latest, _ := h.WindowActiveNowInTZ("UTC")
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+
+	// Three properties about 1, 5 and 20 miles north of the worker, paying
+	// 40, 60 and 80 dollars.
+	originLat, originLng := 41.8781, -87.6298
+	var propIDs []uuid.UUID
+	var instIDs []uuid.UUID
+	for i, miles := range []float64{1, 5, 20} {
+		p := h.CreateTestProperty(ctx, fmt.Sprintf("Search Prop %d", i), testPM.ID, originLat+miles/69.0, originLng)
+		defn := h.CreateTestJobDefinition(t, ctx, testPM.ID, p.ID, fmt.Sprintf("SearchJob%d", i),
+			nil, nil, earliest, latest, models.JobStatusActive, nil, models.JobFreqDaily, nil)
+		inst := h.CreateTestJobInstance(t, ctx, defn.ID, tomorrow, models.InstanceStatusOpen, nil, 40+float64(i)*20)
+		propIDs = append(propIDs, p.ID)
+		instIDs = append(instIDs, inst.ID)
+	}
+
+	w := h.CreateTestWorker(ctx, "search-jobs")
+	wJWT := h.CreateMobileJWT(w.ID, "search-jobs-dev", "FAKE-PLAY")
+
+	// search keeps to this test's properties so other tests' jobs don't
+	// show up.
+	search := func(t *testing.T, lat float64, params url.Values) (int, dtos.SearchJobsResponse) {
+		v := url.Values{}
+		v.Set("lat", fmt.Sprintf("%f", lat))
+		v.Set("lng", fmt.Sprintf("%f", originLng))
+		for _, id := range propIDs {
+			v.Add("property_id", id.String())
+		}
+		for k, vals := range params {
+			v[k] = vals
+		}
+		req := h.BuildAuthRequest("GET", h.BaseURL+routes.JobsSearch+"?"+v.Encode(), wJWT, nil, "android", "search-jobs-dev")
+		resp := h.DoRequest(req, h.NewHTTPClient())
+		defer resp.Body.Close()
+		var out dtos.SearchJobsResponse
+		raw, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.Unmarshal(raw, &out))
+		}
+		return resp.StatusCode, out
+	}
+	ids := func(out dtos.SearchJobsResponse) []uuid.UUID {
+		var got []uuid.UUID
+		for _, r := range out.Results {
+			got = append(got, r.InstanceID)
+		}
+		return got
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		h.T = t
+		for name, tc := range map[string]struct {
+			params url.Values
+			want   []uuid.UUID
+		}{
+			"no filters, nearest first": {want: instIDs},
+			"min pay":                   {params: url.Values{"min_pay": {"50"}}, want: instIDs[1:]},
+			"max distance":              {params: url.Values{"max_distance_miles": {"10"}}, want: instIDs[:2]},
+			"highest pay first":         {params: url.Values{"sort": {"pay"}}, want: []uuid.UUID{instIDs[2], instIDs[1], instIDs[0]}},
+			"nothing matches":           {params: url.Values{"min_pay": {"500"}}},
+		} {
+			status, out := search(t, originLat, tc.params)
+			require.Equal(t, http.StatusOK, status, name)
+			require.Equal(t, tc.want, ids(out), name)
+		}
+	})
+
+	t.Run("CursorPagesThroughEveryJobOnce", func(t *testing.T) {
+		h.T = t
+		var got []uuid.UUID
+		params := url.Values{"size": {"1"}}
+		for page := 0; page < 5; page++ {
+			status, out := search(t, originLat, params)
+			require.Equal(t, http.StatusOK, status)
+			got = append(got, ids(out)...)
+			if out.NextCursor == nil {
+				break
+			}
+			params.Set("cursor", *out.NextCursor)
+		}
+		require.Equal(t, instIDs, got)
+	})
+
+	t.Run("CursorRefusedForAnotherSearch", func(t *testing.T) {
+		h.T = t
+		status, first := search(t, originLat, url.Values{"size": {"1"}})
+		require.Equal(t, http.StatusOK, status)
+		require.NotNil(t, first.NextCursor)
+
+		for name, params := range map[string]url.Values{
+			"other sort":      {"size": {"1"}, "sort": {"pay"}, "cursor": {*first.NextCursor}},
+			"added filter":    {"size": {"1"}, "min_pay": {"50"}, "cursor": {*first.NextCursor}},
+			"other page size": {"size": {"2"}, "cursor": {*first.NextCursor}},
+		} {
+			status, _ := search(t, originLat, params)
+			if name == "other page size" {
+				require.Equal(t, http.StatusOK, status, name)
+				continue
+			}
+			require.Equal(t, http.StatusBadRequest, status, name)
+		}
+		// Distances from a moved origin don't continue the old order.
+		status, _ = search(t, originLat+0.05, url.Values{"size": {"1"}, "cursor": {*first.NextCursor}})
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
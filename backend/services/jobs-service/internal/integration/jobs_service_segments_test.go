@@ -0,0 +1,192 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/routes"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/services"
+)
+
+// createSegmentedDefinition creates a daily definition over three buildings
+// of 3, 2 and 2 units, split by building into three segments.
+func createSegmentedDefinition(t *testing.T, pmJWT string) *models.JobDefinition {
+	ctx := h.Ctx
+	earliest, latest, _ := h.WindowActiveNowInTZ("UTC")
+
+	p := h.CreateTestProperty(ctx, "SegmentProp", testPM.ID, 0, 0)
+	dID := h.CreateTestDumpster(ctx, p.ID, "Segment Dumpster").ID
+	var groups []models.AssignedUnitGroup
+	for b, units := range []int{3, 2, 2} {
+		bldg := h.CreateTestBuilding(ctx, p.ID, fmt.Sprintf("Segment Bldg %d", b))
+		g := models.AssignedUnitGroup{BuildingID: bldg.ID}
+		for u := range units {
+			g.UnitIDs = append(g.UnitIDs, h.CreateTestUnit(ctx, p.ID, bldg.ID, fmt.Sprintf("%d0%d", b+1, u+1)).ID)
+		}
+		groups = append(groups, g)
+	}
+
+	reqDTO := dtos.CreateJobDefinitionRequest{
+		PropertyID:                 p.ID,
+		Title:                      "Segmented Nightly",
+		AssignedUnitsByBuilding:    groups,
+		DumpsterIDs:                []uuid.UUID{dID},
+		Frequency:                  models.JobFreqDaily,
+		StartDate:                  time.Now(),
+		EarliestStartTime:          earliest,
+		LatestStartTime:            latest,
+		GlobalBasePay:              utils.Ptr(100.01), // doesn't split evenly by unit count
+		GlobalEstimatedTimeMinutes: utils.Ptr(90),
+		SegmentStrategy:            models.SegmentStrategyBuilding,
+		SegmentCount:               3,
+	}
+	body, _ := json.Marshal(reqDTO)
+	req := h.BuildAuthRequest("POST", h.BaseURL+routes.JobsDefinitionCreate, pmJWT, body, "web", "127.0.0.1")
+	resp := h.DoRequest(req, h.NewHTTPClient())
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var cResp dtos.CreateJobDefinitionResponse
+	data, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(data, &cResp))
+
+	defn, err := h.JobDefRepo.GetByID(ctx, cResp.DefinitionID)
+	require.NoError(t, err)
+	require.NotNil(t, defn)
+	return defn
+}
+
+func TestSegmentPaySumsToDefinitionPay(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	defn := createSegmentedDefinition(t, h.CreateWebJWT(testPM.ID, "127.0.0.1"))
+
+	require.Equal(t, models.SegmentStrategyBuilding, defn.SegmentStrategy)
+	require.Len(t, defn.Segments, 3)
+	require.Equal(t, 7, defn.TotalUnits)
+	unitTotal := 0
+	for _, seg := range defn.Segments {
+		unitTotal += seg.UnitCount
+	}
+	require.Equal(t, defn.TotalUnits, unitTotal, "segments must cover every unit once")
+
+	from := time.Now().UTC().AddDate(0, 0, -1)
+	insts, err := h.JobInstRepo.ListInstancesByDefinitionIDs(ctx, []uuid.UUID{defn.ID}, nil, from, from.AddDate(0, 0, 10))
+	require.NoError(t, err)
+	require.NotEmpty(t, insts)
+
+	byDate := make(map[string][]*models.JobInstance)
+	for _, inst := range insts {
+		k := inst.ServiceDate.Format("2006-01-02")
+		byDate[k] = append(byDate[k], inst)
+	}
+	for day, dayInsts := range byDate {
+		require.Len(t, dayInsts, 3, "one instance per segment on %s", day)
+		est := defn.GetDailyEstimate(dayInsts[0].ServiceDate.Weekday())
+		require.NotNil(t, est)
+
+		var sum models.Money
+		seen := make(map[int]bool)
+		for _, inst := range dayInsts {
+			require.Equal(t, 3, inst.SegmentCount)
+			require.False(t, seen[inst.SegmentIndex], "duplicate segment %d on %s", inst.SegmentIndex, day)
+			seen[inst.SegmentIndex] = true
+			require.Equal(t, defn.SegmentPay(est.BasePay, inst.SegmentIndex), inst.EffectivePay)
+			sum = sum.Add(inst.EffectivePay)
+		}
+		require.Equal(t, est.BasePay, sum, "segment pay on %s must add up to the definition's pay", day)
+	}
+}
+
+func TestSegmentRollupForManager(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	pmJWT := h.CreateWebJWT(testPM.ID, "127.0.0.1")
+	defn := createSegmentedDefinition(t, pmJWT)
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	insts, err := h.JobInstRepo.ListInstancesByDefinitionIDs(ctx, []uuid.UUID{defn.ID}, nil, day, day)
+	require.NoError(t, err)
+	require.Len(t, insts, 3)
+
+	getRollup := func(jwt string) (*http.Response, *dtos.DefinitionRollupDTO) {
+		ep := fmt.Sprintf("%s%s?definition_id=%s&date=%s", h.BaseURL, routes.JobsDefinitionRollup, defn.ID, day.Format("2006-01-02"))
+		resp := h.DoRequest(h.BuildAuthRequest("GET", ep, jwt, nil, "web", "127.0.0.1"), h.NewHTTPClient())
+		defer resp.Body.Close()
+		var out dtos.DefinitionRollupDTO
+		data, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(data, &out)
+		return resp, &out
+	}
+	setStatus := func(inst *models.JobInstance, status models.InstanceStatusType, workerID *uuid.UUID) {
+		_, err := h.DB.Exec(ctx, `UPDATE job_instances SET status=$2, assigned_worker_id=$3 WHERE id=$1`, inst.ID, status, workerID)
+		require.NoError(t, err)
+	}
+
+	t.Run("AllOpen", func(t *testing.T) {
+		h.T = t
+		resp, r := getRollup(pmJWT)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, string(models.InstanceStatusOpen), r.Status)
+		require.Equal(t, 3, r.SegmentCount)
+		require.Equal(t, defn.TotalUnits, r.TotalUnits)
+		require.Len(t, r.Segments, 3)
+
+		var pay models.Money
+		units := 0
+		for _, seg := range r.Segments {
+			pay = pay.Add(seg.Pay)
+			units += seg.UnitCount
+		}
+		require.Equal(t, defn.GetDailyEstimate(day.Weekday()).BasePay, pay)
+		require.Equal(t, defn.TotalUnits, units)
+	})
+
+	w := h.CreateTestWorker(ctx, "segment-rollup")
+
+	t.Run("PartiallyAssigned", func(t *testing.T) {
+		h.T = t
+		setStatus(insts[0], models.InstanceStatusAssigned, &w.ID)
+		resp, r := getRollup(pmJWT)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, services.RollupStatusPartiallyAssigned, r.Status)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		h.T = t
+		setStatus(insts[0], models.InstanceStatusCompleted, &w.ID)
+		resp, r := getRollup(pmJWT)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, string(models.InstanceStatusInProgress), r.Status)
+		require.Equal(t, 1, r.SegmentsCompleted)
+	})
+
+	t.Run("PartiallyCompleted", func(t *testing.T) {
+		h.T = t
+		setStatus(insts[1], models.InstanceStatusCompleted, &w.ID)
+		setStatus(insts[2], models.InstanceStatusCanceled, nil)
+		resp, r := getRollup(pmJWT)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, services.RollupStatusPartiallyCompleted, r.Status)
+		require.Equal(t, 2, r.SegmentsCompleted)
+	})
+
+	t.Run("OtherManagerForbidden", func(t *testing.T) {
+		h.T = t
+		resp, _ := getRollup(h.CreateWebJWT(uuid.New(), "127.0.0.1"))
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
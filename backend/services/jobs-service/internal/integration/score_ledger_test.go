@@ -0,0 +1,169 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/routes"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// scoreWorker creates a worker whose score starts at 80, clear of both
+// bounds and of the suspension threshold.
+func scoreWorker(t *testing.T, prefix string) *models.Worker {
+	worker := h.CreateTestWorker(h.Ctx, prefix)
+	_, err := h.DB.Exec(h.Ctx, `UPDATE workers SET reliability_score = 80 WHERE id = $1`, worker.ID)
+	require.NoError(t, err)
+	return worker
+}
+
+func adjustScore(t *testing.T, workerID uuid.UUID, delta int, note string) *models.WorkerScoreLedgerEntry {
+	actor := uuid.New()
+	e, err := h.WorkerRepo.AdjustWorkerScore(h.Ctx, models.ScoreAdjustment{
+		WorkerID:  workerID,
+		Delta:     delta,
+		EventType: models.ScoreEventManualAdjustment,
+		ActorID:   &actor,
+		Note:      note,
+	})
+	require.NoError(t, err)
+	return e
+}
+
+func TestScoreLedgerRecordsEveryChange(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	worker := scoreWorker(t, "score-ledger")
+	events := repositories.NewWorkerScoreEventRepository(h.DB)
+
+	first := adjustScore(t, worker.ID, -10, "first")
+	require.Equal(t, 80, first.OldScore)
+	require.Equal(t, 70, first.NewScore)
+	second := adjustScore(t, worker.ID, 50, "second")
+	// The score is clamped at its maximum, and the entry says how much
+	// actually applied.
+	require.Equal(t, 50, second.Delta)
+	require.Equal(t, 30, second.AppliedDelta())
+	require.Equal(t, utils.WorkerScoreMax, second.NewScore)
+
+	ledger, err := events.ListByWorker(ctx, worker.ID, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, ledger, 2)
+	require.Equal(t, second.ID, ledger[0].ID)
+	require.Equal(t, first.ID, ledger[1].ID)
+	require.Equal(t, "first", ledger[1].Note)
+	require.NotNil(t, ledger[1].ActorID)
+
+	updated, err := h.WorkerRepo.GetByID(ctx, worker.ID)
+	require.NoError(t, err)
+	require.Equal(t, ledger[0].NewScore, updated.ReliabilityScore)
+}
+
+func TestScoreEventReversal(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	worker := scoreWorker(t, "score-reverse")
+	events := repositories.NewWorkerScoreEventRepository(h.DB)
+
+	penalty := adjustScore(t, worker.ID, -15, "penalty")
+	adjustScore(t, worker.ID, 30, "raise")
+
+	// A reversal gives back what the event applied, not what it asked for.
+	actor := uuid.New()
+	rev, err := h.WorkerRepo.ReverseScoreEvent(ctx, penalty.ID, &actor, "overturned")
+	require.NoError(t, err)
+	require.Equal(t, models.ScoreEventReversal, rev.EventType)
+	require.Equal(t, 15, rev.Delta)
+	require.Equal(t, 95, rev.OldScore)
+	require.Equal(t, utils.WorkerScoreMax, rev.NewScore)
+	require.Equal(t, penalty.ID, *rev.ReversesEventID)
+
+	orig, err := events.GetByID(ctx, penalty.ID)
+	require.NoError(t, err)
+	require.Equal(t, rev.ID, *orig.ReversedByID)
+
+	_, err = h.WorkerRepo.ReverseScoreEvent(ctx, penalty.ID, &actor, "again")
+	require.ErrorIs(t, err, utils.ErrScoreEventAlreadyReversed)
+	_, err = h.WorkerRepo.ReverseScoreEvent(ctx, rev.ID, &actor, "reverse the reversal")
+	require.ErrorIs(t, err, utils.ErrScoreEventNotReversible)
+	_, err = h.WorkerRepo.ReverseScoreEvent(ctx, uuid.New(), &actor, "missing")
+	require.ErrorIs(t, err, utils.ErrScoreEventNotFound)
+
+	ledger, err := events.ListByWorker(ctx, worker.ID, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, ledger, 3, "failed reversals must not write to the ledger")
+}
+
+func TestScoreHistoryPagination(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	worker := scoreWorker(t, "score-pages")
+
+	// Written in one transaction, so every entry has the same created_at
+	// and only the cursor's ID keeps the pages apart.
+	tx, err := h.DB.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+	var written []uuid.UUID
+	for i := range 5 {
+		e, err := repositories.NewWorkerRepository(tx, cfg.DBEncryptionKey).AdjustWorkerScore(ctx, models.ScoreAdjustment{
+			WorkerID:  worker.ID,
+			Delta:     -1,
+			EventType: models.ScoreEventManualAdjustment,
+			Note:      fmt.Sprintf("event %d", i),
+		})
+		require.NoError(t, err)
+		written = append(written, e.ID)
+	}
+	require.NoError(t, tx.Commit(ctx))
+
+	jwt := h.CreateMobileJWT(worker.ID, "score-pages-dev", "FAKE-PLAY")
+	client := h.NewHTTPClient()
+	page := func(cursor string) dtos.ScoreHistoryResponse {
+		q := url.Values{"limit": {"2"}}
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+		req := h.BuildAuthRequest("GET", h.BaseURL+routes.JobsScoreHistory+"?"+q.Encode(), jwt, nil, "android", "score-pages-dev")
+		resp := h.DoRequest(req, client)
+		defer resp.Body.Close()
+		require.Equal(t, 200, resp.StatusCode)
+		var out dtos.ScoreHistoryResponse
+		raw, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(raw, &out))
+		return out
+	}
+
+	var seen []uuid.UUID
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5, "pagination must end")
+		out := page(cursor)
+		for _, e := range out.Events {
+			require.Empty(t, e.Note, "workers don't see ops notes")
+			seen = append(seen, e.ID)
+		}
+		if out.NextCursor == nil {
+			break
+		}
+		cursor = *out.NextCursor
+	}
+	require.Len(t, seen, len(written))
+	require.ElementsMatch(t, written, seen, "every entry exactly once")
+
+	req := h.BuildAuthRequest("GET", h.BaseURL+routes.JobsScoreHistory+"?cursor=not-a-cursor", jwt, nil, "android", "score-pages-dev")
+	resp := h.DoRequest(req, client)
+	defer resp.Body.Close()
+	require.Equal(t, 400, resp.StatusCode)
+}
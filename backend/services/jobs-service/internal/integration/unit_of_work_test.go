@@ -0,0 +1,129 @@
+//go:build (dev_test || staging_test) && integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+)
+
+var errSerialization = &pgconn.PgError{Code: "40001", Message: "could not serialize access"}
+
+// bumpScore adds n to the worker's reliability score on the unit's
+// transaction.
+func bumpScore(ctx context.Context, w *repositories.Work, workerID uuid.UUID, n int) error {
+	_, err := w.Exec(ctx, `UPDATE workers SET reliability_score = reliability_score + $2 WHERE id = $1`, workerID, n)
+	return err
+}
+
+func readScore(t *testing.T, workerID uuid.UUID) int {
+	var score int
+	require.NoError(t, h.DB.QueryRow(h.Ctx, `SELECT reliability_score FROM workers WHERE id = $1`, workerID).Scan(&score))
+	return score
+}
+
+func TestUnitOfWorkRetriesSerializationFailures(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	worker := h.CreateTestWorker(ctx, "uow-retry")
+	start := readScore(t, worker.ID)
+	uow := repositories.NewUnitOfWork(h.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{MaxAttempts: 3})
+
+	t.Run("RetriedUntilCommitted", func(t *testing.T) {
+		h.T = t
+		attempts := 0
+		err := uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+			attempts++
+			if err := bumpScore(ctx, w, worker.ID, 1); err != nil {
+				return err
+			}
+			if attempts == 1 {
+				return errSerialization
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+		// The failed attempt's write was rolled back.
+		require.Equal(t, start+1, readScore(t, worker.ID))
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		h.T = t
+		attempts := 0
+		err := uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+			attempts++
+			if err := bumpScore(ctx, w, worker.ID, 1); err != nil {
+				return err
+			}
+			return errSerialization
+		})
+		require.True(t, repositories.IsRetryableTxError(err))
+		require.Equal(t, 3, attempts)
+		require.Equal(t, start+1, readScore(t, worker.ID))
+	})
+
+	t.Run("OtherErrorsNotRetried", func(t *testing.T) {
+		h.T = t
+		attempts := 0
+		boom := errors.New("boom")
+		err := uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+			attempts++
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+		require.Equal(t, 1, attempts)
+	})
+}
+
+func TestUnitOfWorkSavepointRollback(t *testing.T) {
+	h.T = t
+	ctx := h.Ctx
+	worker := h.CreateTestWorker(ctx, "uow-savepoint")
+	start := readScore(t, worker.ID)
+	uow := repositories.NewUnitOfWork(h.DB, cfg.DBEncryptionKey, repositories.UnitOfWorkOptions{})
+	boom := errors.New("boom")
+
+	err := uow.Run(ctx, func(ctx context.Context, outer *repositories.Work) error {
+		if err := bumpScore(ctx, outer, worker.ID, 1); err != nil {
+			return err
+		}
+		// A Run on the unit's context joins it as a savepoint, and only
+		// the savepoint's writes are undone when it fails.
+		err := uow.Run(ctx, func(ctx context.Context, inner *repositories.Work) error {
+			require.NotSame(t, outer, inner)
+			require.Same(t, inner, repositories.WorkFromContext(ctx))
+			if err := bumpScore(ctx, inner, worker.ID, 10); err != nil {
+				return err
+			}
+			return boom
+		})
+		require.ErrorIs(t, err, boom)
+
+		// The unit carries on after the failed savepoint.
+		return uow.Run(ctx, func(ctx context.Context, inner *repositories.Work) error {
+			return bumpScore(ctx, inner, worker.ID, 100)
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, start+101, readScore(t, worker.ID))
+
+	// A failed outer unit undoes its committed savepoints too.
+	err = uow.Run(ctx, func(ctx context.Context, outer *repositories.Work) error {
+		if err := uow.Run(ctx, func(ctx context.Context, inner *repositories.Work) error {
+			return bumpScore(ctx, inner, worker.ID, 1000)
+		}); err != nil {
+			return err
+		}
+		return boom
+	})
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, start+101, readScore(t, worker.ID))
+}
@@ -8,9 +8,21 @@ const (
 	JobsBase     = "/api/v1/jobs"
 	JobsOpen     = "/api/v1/jobs/open"
 	JobsMy       = "/api/v1/jobs/my"
+	JobsSearch   = "/api/v1/jobs/search"
+	JobsRelease  = "/api/v1/jobs/release-time"
+	JobsLottery  = "/api/v1/jobs/lottery"
 	JobsAccept   = "/api/v1/jobs/accept"
 	JobsUnaccept = "/api/v1/jobs/unaccept"
 
+	// Worker reliability score timeline
+	JobsScoreHistory = "/api/v1/jobs/score/history"
+
+	// Pay breakdown of one of the worker's jobs
+	JobsPay = "/api/v1/jobs/pay"
+
+	// Scheduled job run history (all replicas)
+	JobsSchedulerRuns = "/api/v1/jobs/scheduler/runs"
+
 	// NEW endpoints for unit verification workflow
 	JobsVerifyUnitPhoto = "/api/v1/jobs/verify-unit-photo"
 	JobsDumpBags        = "/api/v1/jobs/dump-bags"
@@ -24,6 +36,30 @@ const (
 	// Manager or system endpoint
 	JobsDefinitionStatus = "/api/v1/jobs/definition/status"
 	JobsDefinitionCreate = "/api/v1/manager/jobs/definition"
+	JobsDefinitionRollup = "/api/v1/manager/jobs/definition/rollup"
+
+	// Ops endpoints
+	OpsSurgePolicies = "/api/v1/ops/surge/policies"
+	OpsSurgeManual   = "/api/v1/ops/surge/manual"
+	OpsSurgeAudit    = "/api/v1/ops/surge/audit"
+
+	OpsAllocationMarkets = "/api/v1/ops/allocation/markets"
+
+	OpsEscalationPolicies   = "/api/v1/ops/escalation/policies"
+	OpsEscalationExecutions = "/api/v1/ops/escalation/executions"
+	OpsEscalationAudit      = "/api/v1/ops/escalation/audit"
+
+	OpsQueueDead    = "/api/v1/ops/queue/dead"
+	OpsQueueRequeue = "/api/v1/ops/queue/requeue"
+
+	OpsWorkerScoreHistory   = "/api/v1/ops/workers/score/history"
+	OpsWorkerScoreAdjust    = "/api/v1/ops/workers/score/adjust"
+	OpsWorkerScoreReverse   = "/api/v1/ops/workers/score/reverse"
+	OpsWorkerScoreRecompute = "/api/v1/ops/workers/score/recompute"
+
+	OpsJobsPay      = "/api/v1/ops/jobs/pay"
+	OpsJobsPayItems = "/api/v1/ops/jobs/pay/items"
+	OpsPaySummary   = "/api/v1/ops/pay/summary"
 
 	// Public agent completion endpoint
 	JobsAgentComplete = "/api/v1/jobs/agent-complete/{token}"
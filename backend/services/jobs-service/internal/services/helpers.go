@@ -16,10 +16,6 @@ import (
 	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"github.com/twilio/twilio-go"
-	twilioApi "github.com/twilio/twilio-go/rest/api/v2010"
 )
 
 // MODIFIED: Updated HTML with a more modern design, more details, and a prominent "I'm On It" button.
@@ -174,6 +170,16 @@ func ContainsUUID(list []uuid.UUID, val uuid.UUID) bool {
 	return slices.Contains(list, val)
 }
 
+// visibleToWorker reports whether the worker may see inst at prop at all:
+// they aren't excluded from it, and reviewers see only demo properties
+// while everyone else sees none.
+func visibleToWorker(inst *models.JobInstance, prop *models.Property, workerID uuid.UUID, isReviewer bool) bool {
+	if ContainsUUID(inst.ExcludedWorkerIDs, workerID) {
+		return false
+	}
+	return prop.IsDemo == isReviewer
+}
+
 // CombineDateTime combines a UTC date (d) with a time-of-day (t).
 // `d` should be a date at midnight UTC.
 // `t` is a time.Time where only the Hour, Minute, and Second are relevant.
@@ -196,8 +202,7 @@ func NotifyOnCallAgents(
 	ajcRepo repositories.AgentJobCompletionRepository,
 	bldgRepo repositories.PropertyBuildingRepository,
 	unitRepo repositories.UnitRepository,
-	twClient *twilio.RestClient,
-	sgClient *sendgrid.Client,
+	notifier *utils.Notifier,
 	cfg *config.Config,
 ) {
 	if !cfg.LDFlag_NotifyJobStatuses {
@@ -318,78 +323,54 @@ func NotifyOnCallAgents(
 			confirmationLink,
 		)
 
-		// ---------- Twilio SMS ----------
-		if twClient != nil {
-			params := &twilioApi.CreateMessageParams{}
-			params.SetTo(r.PhoneNumber)
-			params.SetFrom(fromPhone)
-			params.SetBody(subject + " :: " + plainTextBody)
-			_, smsErr := twClient.Api.CreateMessage(params)
-			if smsErr != nil {
-				utils.Logger.WithError(smsErr).Warnf("Failed to send on-call SMS to rep %s", r.ID)
-			}
-		} else {
-			utils.Logger.Warnf("Twilio client is nil, skipping SMS to rep %s", r.ID)
-		}
-
-		// ---------- SendGrid Email ----------
-		if sgClient != nil {
-			from := mail.NewEmail(orgName, fromEmail)
-			to := mail.NewEmail(r.Name, r.Email)
-			msg := mail.NewSingleEmail(from, subject, to, plainTextBody, htmlBody)
-			msg.TrackingSettings = &mail.TrackingSettings{
-				ClickTracking: &mail.ClickTrackingSetting{
-					Enable: utils.Ptr(false),
-				},
-			}
-			if sendgridSandbox {
-				ms := mail.NewMailSettings()
-				ms.SetSandboxMode(mail.NewSetting(true))
-				msg.MailSettings = ms
-			}
-			if _, sgErr := sgClient.Send(msg); sgErr != nil {
-				utils.Logger.WithError(sgErr).Warnf("Email send failure to rep %s", r.ID)
-			}
-		} else {
-			utils.Logger.Warnf("SendGrid client is nil, skipping email to rep %s", r.ID)
-		}
+		// ---------- SMS + Email (queued, retried on provider errors) ----------
+		notifier.SMS(ctx, utils.SMSJob{
+			From: fromPhone,
+			To:   r.PhoneNumber,
+			Body: subject + " :: " + plainTextBody,
+		})
+		notifier.Email(ctx, utils.EmailJob{
+			FromName:     orgName,
+			FromEmail:    fromEmail,
+			ToName:       r.Name,
+			ToEmail:      r.Email,
+			Subject:      subject,
+			PlainText:    plainTextBody,
+			HTML:         htmlBody,
+			Sandbox:      sendgridSandbox,
+			NoClickTrack: true,
+		})
 	}
 
 	// ---------- MODIFIED: Always send a detailed notification to the internal team ----------
-	if sgClient != nil {
-		teamEmail := "team@thepoofapp.com"
-		teamSubject := fmt.Sprintf("[Internal Alert] %s", subject)
-		teamPlainText := fmt.Sprintf(
-			"An automated alert was triggered.\n\nTitle: %s\nProperty: %s\nAddress: %s\nDefinition ID: %s\nDetails: %s\nUnits:%s",
-			messageTitle, propertyName, propertyAddress, def.ID.String(), messageBody, buildingsAndUnitsPlainText.String(),
-		)
-		teamHtmlBody := fmt.Sprintf(
-			teamNotificationEmailHTML,
-			teamSubject,
-			propertyName,
-			propertyAddress,
-			def.ID.String(),
-			messageBody,
-			buildingsAndUnits.String(),
-			time.Now().UTC().Format(time.RFC1123Z),
-		)
+	teamEmail := "team@thepoofapp.com"
+	teamSubject := fmt.Sprintf("[Internal Alert] %s", subject)
+	teamPlainText := fmt.Sprintf(
+		"An automated alert was triggered.\n\nTitle: %s\nProperty: %s\nAddress: %s\nDefinition ID: %s\nDetails: %s\nUnits:%s",
+		messageTitle, propertyName, propertyAddress, def.ID.String(), messageBody, buildingsAndUnitsPlainText.String(),
+	)
+	teamHtmlBody := fmt.Sprintf(
+		teamNotificationEmailHTML,
+		teamSubject,
+		propertyName,
+		propertyAddress,
+		def.ID.String(),
+		messageBody,
+		buildingsAndUnits.String(),
+		time.Now().UTC().Format(time.RFC1123Z),
+	)
 
-		from := mail.NewEmail(fmt.Sprintf("%s Bot", orgName), fromEmail)
-		to := mail.NewEmail("Poof Operations Team", teamEmail)
-		msg := mail.NewSingleEmail(from, teamSubject, to, teamPlainText, teamHtmlBody)
-		if sendgridSandbox {
-			ms := mail.NewMailSettings()
-			ms.SetSandboxMode(mail.NewSetting(true))
-			msg.MailSettings = ms
-		}
-		if _, sgErr := sgClient.Send(msg); sgErr != nil {
-			utils.Logger.WithError(sgErr).Errorf("Failed to send internal team notification to %s", teamEmail)
-		} else {
-			utils.Logger.Infof("Successfully sent internal team notification to %s for event: %s", teamEmail, messageTitle)
-		}
-	} else {
-		utils.Logger.Warn("SendGrid client is nil, skipping internal team notification.")
-	}
+	notifier.Email(ctx, utils.EmailJob{
+		FromName:  fmt.Sprintf("%s Bot", orgName),
+		FromEmail: fromEmail,
+		ToName:    "Poof Operations Team",
+		ToEmail:   teamEmail,
+		Subject:   teamSubject,
+		PlainText: teamPlainText,
+		HTML:      teamHtmlBody,
+		Sandbox:   sendgridSandbox,
+	})
+	utils.Logger.Infof("Queued internal team notification to %s for event: %s", teamEmail, messageTitle)
 }
 
 func NotifyInternalTeamOnly(
@@ -401,17 +382,13 @@ func NotifyInternalTeamOnly(
 	messageBody string,
 	bldgRepo repositories.PropertyBuildingRepository,
 	unitRepo repositories.UnitRepository,
-	sgClient *sendgrid.Client,
+	notifier *utils.Notifier,
 	cfg *config.Config,
 ) {
 	if !cfg.LDFlag_NotifyJobStatuses {
 		utils.Logger.Info("NotifyInternalTeamOnly skipped due to feature flag.")
 		return
 	}
-	if sgClient == nil {
-		utils.Logger.Warn("SendGrid client is nil, skipping internal team notification.")
-		return
-	}
 
 	fromEmail := cfg.LDFlag_SendgridFromEmail
 	orgName := cfg.OrganizationName
@@ -466,17 +443,15 @@ func NotifyInternalTeamOnly(
 		time.Now().UTC().Format(time.RFC1123Z),
 	)
 
-	from := mail.NewEmail(fmt.Sprintf("%s Bot", orgName), fromEmail)
-	to := mail.NewEmail("Poof Operations Team", teamEmail)
-	msg := mail.NewSingleEmail(from, teamSubject, to, teamPlainText, teamHtmlBody)
-	if sendgridSandbox {
-		ms := mail.NewMailSettings()
-		ms.SetSandboxMode(mail.NewSetting(true))
-		msg.MailSettings = ms
-	}
-	if _, sgErr := sgClient.Send(msg); sgErr != nil {
-		utils.Logger.WithError(sgErr).Errorf("Failed to send internal team notification to %s", teamEmail)
-	} else {
-		utils.Logger.Infof("Successfully sent internal team notification to %s for event: %s", teamEmail, messageTitle)
-	}
+	notifier.Email(ctx, utils.EmailJob{
+		FromName:  fmt.Sprintf("%s Bot", orgName),
+		FromEmail: fromEmail,
+		ToName:    "Poof Operations Team",
+		ToEmail:   teamEmail,
+		Subject:   teamSubject,
+		PlainText: teamPlainText,
+		HTML:      teamHtmlBody,
+		Sandbox:   sendgridSandbox,
+	})
+	utils.Logger.Infof("Queued internal team notification to %s for event: %s", teamEmail, messageTitle)
 }
@@ -12,15 +12,18 @@ import (
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
 	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
+// ForceReopenNoShow reopens a job its worker failed to start and applies
+// the no-show penalty. It does nothing if the job is no longer assigned to
+// oldWorkerID.
 func (s *JobService) ForceReopenNoShow(
 	ctx context.Context,
 	instanceID uuid.UUID,
 	oldWorkerID uuid.UUID,
 ) (*models.JobInstance, error) {
-	// unchanged ...
 	inst, err := s.instRepo.GetByID(ctx, instanceID)
 	if err != nil {
 		return nil, err
@@ -28,68 +31,10 @@ func (s *JobService) ForceReopenNoShow(
 	if inst == nil {
 		return nil, nil
 	}
-	if inst.Status != models.InstanceStatusAssigned {
-		return nil, nil
-	}
-
-	expectedVersion := inst.RowVersion
-	newCount := inst.AssignUnassignCount + 1
-	flagged := inst.FlaggedForReview
-	if newCount > constants.MaxAssignUnassignCountForFlag {
-		flagged = true
-	}
-	reopened, err := s.instRepo.UnassignInstanceAtomic(ctx, instanceID, expectedVersion, newCount, flagged)
-	if err != nil {
-		return nil, err
-	}
-	if reopened == nil {
+	if inst.Status != models.InstanceStatusAssigned || inst.AssignedWorkerID == nil || *inst.AssignedWorkerID != oldWorkerID {
 		return nil, nil
 	}
-	_ = s.instRepo.AddExcludedWorker(ctx, instanceID, oldWorkerID)
-
-	defn, err := s.defRepo.GetByID(ctx, reopened.DefinitionID)
-	if err != nil || defn == nil {
-		return reopened, nil
-	}
-
-	return reopened, nil
-}
-
-// ApplyManualSurge ...
-func (s *JobService) ApplyManualSurge(
-	ctx context.Context,
-	inst *models.JobInstance,
-	defn *models.JobDefinition,
-	multiplier float64,
-) {
-	if inst.Status != models.InstanceStatusOpen {
-		return
-	}
-
-	dayOfWeek := inst.ServiceDate.Weekday()
-	dailyEstimate := defn.GetDailyEstimate(dayOfWeek)
-	if dailyEstimate == nil {
-		utils.Logger.Warnf("ApplyManualSurge: No daily estimate found for job_definition_id=%s, day_of_week=%s", defn.ID, dayOfWeek)
-		return
-	}
-
-	basePay := dailyEstimate.BasePay
-	if basePay <= 0 {
-		return
-	}
-	if multiplier > constants.SurgeMultiplierStage4 {
-		multiplier = constants.SurgeMultiplierStage4
-	}
-	newPay := basePay * multiplier
-	if newPay <= inst.EffectivePay {
-		return
-	}
-
-	latest, _ := s.instRepo.GetByID(ctx, inst.ID)
-	if latest == nil || latest.Status != models.InstanceStatusOpen {
-		return
-	}
-	_ = s.instRepo.UpdateEffectivePayAtomic(ctx, latest.ID, latest.RowVersion, newPay)
+	return s.unassignWorker(ctx, inst, oldWorkerID, true, constants.WorkerPenaltyNoShow, models.ScoreEventNoShow)
 }
 
 // SetDefinitionStatus ...
@@ -199,16 +144,18 @@ func (s *JobService) CreateJobDefinition(
 		}
 		dailyEstimatesToUse = make([]models.DailyPayEstimate, len(req.DailyPayEstimates))
 		for i, dpeReq := range req.DailyPayEstimates {
+			basePay := models.MoneyFromDollars(dpeReq.BasePay)
 			dailyEstimatesToUse[i] = models.DailyPayEstimate{
 				DayOfWeek:                   time.Weekday(dpeReq.DayOfWeek),
-				BasePay:                     dpeReq.BasePay,
-				InitialBasePay:              dpeReq.BasePay,
+				BasePay:                     basePay,
+				InitialBasePay:              basePay,
 				EstimatedTimeMinutes:        dpeReq.EstimatedTimeMinutes,
 				InitialEstimatedTimeMinutes: dpeReq.EstimatedTimeMinutes,
 			}
 		}
 	} else if req.GlobalBasePay != nil && req.GlobalEstimatedTimeMinutes != nil {
-		if *req.GlobalBasePay <= 0 {
+		basePay := models.MoneyFromDollars(*req.GlobalBasePay)
+		if !basePay.IsPositive() {
 			return uuid.Nil, fmt.Errorf("%w: global_base_pay must be positive", internal_utils.ErrInvalidPayload)
 		}
 		if *req.GlobalEstimatedTimeMinutes <= 0 {
@@ -219,8 +166,8 @@ func (s *JobService) CreateJobDefinition(
 		for i := range 7 {
 			dailyEstimatesToUse[i] = models.DailyPayEstimate{
 				DayOfWeek:                   time.Weekday(i),
-				BasePay:                     *req.GlobalBasePay,
-				InitialBasePay:              *req.GlobalBasePay,
+				BasePay:                     basePay,
+				InitialBasePay:              basePay,
 				EstimatedTimeMinutes:        *req.GlobalEstimatedTimeMinutes,
 				InitialEstimatedTimeMinutes: *req.GlobalEstimatedTimeMinutes,
 			}
@@ -246,6 +193,15 @@ func (s *JobService) CreateJobDefinition(
 	}
 	slices.Sort(floors)
 
+	segStrategy := req.SegmentStrategy
+	if segStrategy == "" {
+		segStrategy = models.SegmentStrategyNone
+	}
+	segments, err := buildJobSegments(req.AssignedUnitsByBuilding, segStrategy, req.SegmentCount)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %v", internal_utils.ErrInvalidPayload, err)
+	}
+
 	newDef := &models.JobDefinition{
 		ID:                      uuid.New(),
 		ManagerID:               pmID,
@@ -268,6 +224,8 @@ func (s *JobService) CreateJobDefinition(
 		SkipHolidays:            req.SkipHolidays,
 		HolidayExceptions:       req.HolidayExceptions,
 		DailyPayEstimates:       dailyEstimatesToUse,
+		SegmentStrategy:         segStrategy,
+		Segments:                segments,
 	}
 
 	if req.Details != nil {
@@ -315,20 +273,9 @@ func (s *JobService) CreateJobDefinition(
 					}
 				}
 
-				dailyEstimate := newDef.GetDailyEstimate(day.Weekday())
-				var initialPay float64
-				if dailyEstimate != nil {
-					initialPay = dailyEstimate.BasePay
-				}
-
-				inst := &models.JobInstance{
-					ID:           uuid.New(),
-					DefinitionID: newDef.ID,
-					ServiceDate:  day,
-					Status:       models.InstanceStatusOpen,
-					EffectivePay: initialPay,
+				for _, inst := range newInstancesForDate(newDef, day) {
+					_ = s.instRepo.CreateIfNotExists(ctx, inst)
 				}
-				_ = s.instRepo.CreateIfNotExists(ctx, inst)
 			}
 		}
 	}
@@ -381,7 +328,27 @@ func (s *JobService) CancelJobInstance(
 		assignCount := inst.AssignUnassignCount + 1
 		flagged := inst.FlaggedForReview || (assignCount > constants.MaxAssignUnassignCountForFlag)
 
-		rev, err2 := s.instRepo.RevertInProgressToOpenAtomic(ctx, instanceID, expectedVersion, assignCount, flagged)
+		var rev *models.JobInstance
+		err2 := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+			instRepo := w.JobInstances()
+			var err error
+			rev, err = instRepo.RevertInProgressToOpenAtomic(ctx, instanceID, expectedVersion, assignCount, flagged)
+			if err != nil {
+				return err
+			}
+			if rev == nil {
+				return utils.ErrNoRowsUpdated
+			}
+			if excludeWorker {
+				if err := instRepo.AddExcludedWorker(ctx, rev.ID, wUUID); err != nil {
+					return err
+				}
+			}
+			if penaltyDelta != 0 {
+				return s.scoreJobEvent(ctx, w, wUUID, rev.ID, models.ScoreEventCancelInProgressRevert, penaltyDelta)
+			}
+			return nil
+		})
 		if err2 != nil {
 			if strings.Contains(err2.Error(), utils.ErrRowVersionConflict.Error()) {
 				latest, _ := s.instRepo.GetByID(ctx, instanceID)
@@ -391,16 +358,6 @@ func (s *JobService) CancelJobInstance(
 			}
 			return nil, err2
 		}
-		if rev == nil {
-			return nil, utils.ErrNoRowsUpdated
-		}
-
-		if excludeWorker {
-			_ = s.instRepo.AddExcludedWorker(ctx, rev.ID, wUUID)
-		}
-		if penaltyDelta != 0 && s.workerRepo != nil {
-			_ = s.workerRepo.AdjustWorkerScoreAtomic(ctx, wUUID, penaltyDelta, "CANCEL_IN_PROGRESS_REVERT")
-		}
 
 		// MODIFIED: More descriptive message body.
 		messageBody := fmt.Sprintf(
@@ -421,8 +378,7 @@ func (s *JobService) CancelJobInstance(
 			s.agentJobCompletionRepo,
 			s.bldgRepo,
 			s.unitRepo,
-			s.twilioClient,
-			s.sendgridClient,
+			s.notifier,
 			s.cfg,
 		)
 
@@ -431,7 +387,22 @@ func (s *JobService) CancelJobInstance(
 	}
 
 	expectedVersion := inst.RowVersion
-	cancelled, err2 := s.instRepo.UpdateStatusToCancelled(ctx, instanceID, expectedVersion)
+	var cancelled *models.JobInstance
+	err2 := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		instRepo := w.JobInstances()
+		var err error
+		cancelled, err = instRepo.UpdateStatusToCancelled(ctx, instanceID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if cancelled == nil {
+			return utils.ErrNoRowsUpdated
+		}
+		if err := instRepo.AddExcludedWorker(ctx, cancelled.ID, wUUID); err != nil {
+			return err
+		}
+		return s.scoreJobEvent(ctx, w, wUUID, cancelled.ID, models.ScoreEventCancelInProgressLate, constants.WorkerPenaltyNoShow)
+	})
 	if err2 != nil {
 		if strings.Contains(err2.Error(), utils.ErrRowVersionConflict.Error()) {
 			latest, _ := s.instRepo.GetByID(ctx, instanceID)
@@ -441,14 +412,6 @@ func (s *JobService) CancelJobInstance(
 		}
 		return nil, err2
 	}
-	if cancelled == nil {
-		return nil, utils.ErrNoRowsUpdated
-	}
-
-	if s.workerRepo != nil {
-		_ = s.instRepo.AddExcludedWorker(ctx, cancelled.ID, wUUID)
-		_ = s.workerRepo.AdjustWorkerScoreAtomic(ctx, wUUID, constants.WorkerPenaltyNoShow, "CANCEL_IN_PROGRESS_LATE")
-	}
 
 	// MODIFIED: More descriptive message body.
 	messageBody := fmt.Sprintf(
@@ -466,7 +429,7 @@ func (s *JobService) CancelJobInstance(
 		messageBody,
 		s.bldgRepo,
 		s.unitRepo,
-		s.sendgridClient,
+		s.notifier,
 		s.cfg,
 	)
 
@@ -553,7 +516,7 @@ func validateDailyPayEstimates(
 		if _, exists := providedDaysMap[dayEnum]; exists {
 			return fmt.Errorf("duplicate day_of_week %s in daily_pay_estimates", dayEnum)
 		}
-		if est.BasePay <= 0 {
+		if !models.MoneyFromDollars(est.BasePay).IsPositive() {
 			return fmt.Errorf("base_pay must be positive for day %s", dayEnum)
 		}
 		if est.EstimatedTimeMinutes <= 0 {
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+Background jobs run by the service's job queue (see utils.JobQueue):
+
+  - photo re-verification, when OpenAI is unreachable while a worker submits
+    a unit photo. The photo is kept in verification_photos and the job
+    carries its key, so queue rows (and the dead-job listing) hold no images;
+  - the completion-time EMA update after a job completes, which used to be
+    fire-and-forget.
+*/
+
+const (
+	photoVerifyJobKind   = "jobs.verify-photo"
+	completionEmaJobKind = "jobs.completion-ema"
+
+	photoVerifyMaxAttempts = 6
+)
+
+type photoVerifyJob struct {
+	InstanceID      uuid.UUID `json:"instance_id"`
+	UnitID          uuid.UUID `json:"unit_id"`
+	UnitNumber      string    `json:"unit_number"`
+	MissingTrashCan bool      `json:"missing_trash_can"`
+	PhotoKey        string    `json:"photo_key"`
+}
+
+type completionEmaJob struct {
+	DefinitionID uuid.UUID    `json:"definition_id"`
+	Weekday      time.Weekday `json:"weekday"`
+	ActualMins   int          `json:"actual_mins"`
+}
+
+func (s *JobService) registerJobHandlers() {
+	if s.queue == nil {
+		return
+	}
+	utils.RegisterJobHandler(s.queue, photoVerifyJobKind, s.runPhotoVerifyJob)
+	utils.RegisterJobHandler(s.queue, completionEmaJobKind, s.runCompletionEmaJob)
+}
+
+// queuePhotoVerification schedules another OpenAI check of a unit photo. One
+// check per unit is queued at a time.
+func (s *JobService) queuePhotoVerification(
+	ctx context.Context,
+	instanceID, unitID uuid.UUID,
+	unitNumber string,
+	missingTrashCan bool,
+	photo []byte,
+) error {
+	if s.queue == nil {
+		return errors.New("job queue not configured")
+	}
+	// A photo already waiting for this unit is replaced by the newer one.
+	key := fmt.Sprintf("verify-photo/%s/%s", instanceID, unitID)
+	if err := s.photoRepo.Put(ctx, key, photo); err != nil {
+		return err
+	}
+	_, err := s.queue.Enqueue(ctx, photoVerifyJobKind, photoVerifyJob{
+		InstanceID:      instanceID,
+		UnitID:          unitID,
+		UnitNumber:      unitNumber,
+		MissingTrashCan: missingTrashCan,
+		PhotoKey:        key,
+	}, utils.EnqueueOptions{
+		UniqueKey:   fmt.Sprintf("verify-photo:%s:%s", instanceID, unitID),
+		MaxAttempts: photoVerifyMaxAttempts,
+	})
+	return err
+}
+
+// runPhotoVerifyJob deletes the stored photo once the unit no longer needs
+// it; a dead job keeps its photo so ops can requeue it.
+func (s *JobService) runPhotoVerifyJob(ctx context.Context, job photoVerifyJob) error {
+	if err := s.verifyQueuedPhoto(ctx, job); err != nil {
+		return err
+	}
+	if err := s.photoRepo.Delete(ctx, job.PhotoKey); err != nil {
+		utils.Logger.WithError(err).Warnf("Failed to delete verified photo %s", job.PhotoKey)
+	}
+	return nil
+}
+
+func (s *JobService) verifyQueuedPhoto(ctx context.Context, job photoVerifyJob) error {
+	v, err := s.juvRepo.GetByInstanceAndUnit(ctx, job.InstanceID, job.UnitID)
+	if err != nil {
+		return err
+	}
+	// The worker has since submitted a photo that was judged directly.
+	if v == nil || v.Status != models.UnitVerificationPending {
+		return nil
+	}
+	inst, err := s.instRepo.GetByID(ctx, job.InstanceID)
+	if err != nil {
+		return err
+	}
+	if inst == nil || inst.Status != models.InstanceStatusInProgress {
+		return nil
+	}
+
+	photo, err := s.photoRepo.Get(ctx, job.PhotoKey)
+	if err != nil {
+		return err
+	}
+	if photo == nil {
+		return utils.Permanent(fmt.Errorf("photo %s is gone", job.PhotoKey))
+	}
+	result, err := s.openai.VerifyPhoto(ctx, photo, job.UnitNumber)
+	if err != nil {
+		return err
+	}
+	status, reasonCodes := photoVerificationOutcome(result, job.MissingTrashCan)
+	utils.Logger.Infof("Queued photo verification for unit %s on instance %s: %s", job.UnitID, job.InstanceID, status)
+	return s.recordUnitVerification(ctx, job.InstanceID, job.UnitID, status, reasonCodes, job.MissingTrashCan)
+}
+
+// queueCompletionTimeEma folds a completed instance's duration into its
+// definition's time estimate in the background, once per instance.
+func (s *JobService) queueCompletionTimeEma(
+	ctx context.Context,
+	instanceID, definitionID uuid.UUID,
+	weekday time.Weekday,
+	actualMins int,
+) {
+	job := completionEmaJob{DefinitionID: definitionID, Weekday: weekday, ActualMins: actualMins}
+	if s.queue == nil {
+		if err := s.applyCompletionTimeEma(ctx, definitionID, weekday, actualMins); err != nil {
+			utils.Logger.WithError(err).Warnf("applyCompletionTimeEma failed for definition %s", definitionID)
+		}
+		return
+	}
+	if _, err := s.queue.Enqueue(ctx, completionEmaJobKind, job, utils.EnqueueOptions{
+		UniqueKey: "completion-ema:" + instanceID.String(),
+	}); err != nil {
+		utils.Logger.WithError(err).Errorf("Failed to queue completion-time EMA update for instance %s", instanceID)
+	}
+}
+
+func (s *JobService) runCompletionEmaJob(ctx context.Context, job completionEmaJob) error {
+	err := s.applyCompletionTimeEma(ctx, job.DefinitionID, job.Weekday, job.ActualMins)
+	if errors.Is(err, internal_utils.ErrNoDailyEstimate) {
+		return utils.Permanent(err)
+	}
+	return err
+}
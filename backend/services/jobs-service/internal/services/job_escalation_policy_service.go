@@ -0,0 +1,513 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*──────────────────────────────────────────────────────────────────────────
+  Escalation policies
+
+  What happens to an unfilled or abandoned job as its latest start time
+  approaches is data: a policy is an ordered list of steps, each firing once
+  per instance at an offset from the latest start. Every step that runs is
+  recorded in escalation_step_executions keyed by (instance, step key), so a
+  new kind of step needs no schema change. With no stored policy the
+  built-in steps below reproduce the original fixed timers.
+──────────────────────────────────────────────────────────────────────────*/
+
+// Step keys of the built-in policy. The first two match the executions
+// carried over from the old warning_*_sent_at columns.
+const (
+	escalationStepInternalWarning = "internal_warning_90"
+	escalationStepOnCallWarning   = "on_call_warning_40"
+	escalationStepNoShowReopen    = "no_show_reopen"
+	escalationStepExpiredCancel   = "expired_cancel"
+)
+
+func defaultEscalationPolicy() *models.EscalationPolicy {
+	return &models.EscalationPolicy{
+		Name:   "built-in default",
+		Scope:  models.PolicyScopeGlobal,
+		Active: true,
+		Steps: []models.EscalationStep{
+			{
+				Key:           escalationStepInternalWarning,
+				OffsetMinutes: -int(constants.Warning90MinBeforeLatestStart / time.Minute),
+				Statuses:      []models.InstanceStatusType{models.InstanceStatusOpen},
+				Action:        models.EscalationActionNotify,
+				Audience:      models.EscalationAudienceInternal,
+				Channel:       models.EscalationChannelEmail,
+				Title:         "[Warning] Unassigned Job (90 Min)",
+				Message:       "This job at {property} is unassigned and is approaching its latest start time of {latest_start}.",
+			},
+			{
+				Key:           escalationStepOnCallWarning,
+				OffsetMinutes: -int(constants.Warning40MinBeforeLatestStart / time.Minute),
+				Statuses:      []models.InstanceStatusType{models.InstanceStatusOpen},
+				Action:        models.EscalationActionNotify,
+				Audience:      models.EscalationAudienceOnCallAgents,
+				Channel:       models.EscalationChannelAll,
+				Title:         "[Urgent] Unassigned Job (40 Min)",
+				Message:       "This job at {property} is unassigned and is approaching its latest start time of {latest_start}. This is the final warning.",
+			},
+			{
+				Key:           escalationStepNoShowReopen,
+				OffsetMinutes: -int(constants.NoShowCutoffBeforeLatestStart / time.Minute),
+				Statuses:      []models.InstanceStatusType{models.InstanceStatusAssigned},
+				Action:        models.EscalationActionReopen,
+				Audience:      models.EscalationAudienceOnCallAgents,
+				Channel:       models.EscalationChannelAll,
+				Title:         "[Escalation] Worker No-Show",
+				Message:       "The worker assigned to this job at {property} was a no-show. The job has been reopened and requires immediate coverage.",
+			},
+			{
+				Key:           escalationStepExpiredCancel,
+				OffsetMinutes: 0,
+				Statuses:      []models.InstanceStatusType{models.InstanceStatusOpen, models.InstanceStatusAssigned},
+				Action:        models.EscalationActionCancel,
+				Audience:      models.EscalationAudienceInternal,
+				Channel:       models.EscalationChannelEmail,
+				Title:         "Job Auto-Canceled After Expiry",
+				Message:       "This job at {property} exceeded its latest start time of {latest_start} and has been automatically canceled.",
+			},
+		},
+	}
+}
+
+// effectiveEscalationPolicy resolves the policy for a job from the given
+// active policies, falling back to the built-in default.
+func effectiveEscalationPolicy(
+	policies []*models.EscalationPolicy,
+	defn *models.JobDefinition,
+	prop *models.Property,
+) *models.EscalationPolicy {
+	if p := models.ResolveEscalationPolicy(policies, defn.ID, defn.PropertyID, propertyMarket(prop)); p != nil {
+		return p
+	}
+	return defaultEscalationPolicy()
+}
+
+// activeEscalationPolicies loads stored policies; on error it logs and
+// returns none so the built-in default keeps escalations running.
+func (s *JobEscalationService) activeEscalationPolicies(ctx context.Context) []*models.EscalationPolicy {
+	policies, err := s.escalationRepo.ListActive(ctx)
+	if err != nil {
+		utils.Logger.WithError(err).Warn("Failed to load escalation policies; using built-in default")
+		return nil
+	}
+	return policies
+}
+
+// nextEscalationStep returns the earliest due step of policy that applies
+// to inst and is not in done.
+func nextEscalationStep(
+	policy *models.EscalationPolicy,
+	inst *models.JobInstance,
+	done map[string]bool,
+	lStart, now time.Time,
+) (models.EscalationStep, bool) {
+	for _, step := range policy.OrderedSteps() {
+		if !done[step.Key] && step.Due(lStart, now) && step.AppliesTo(inst) {
+			return step, true
+		}
+	}
+	return models.EscalationStep{}, false
+}
+
+// runEscalationSteps fires every due step of policy that has not run yet
+// for inst, earliest offset first. inst.Status is kept current and the
+// steps are looked at again after each one, so a reopened job gets the
+// open-job warnings it was too late for in the same tick.
+func (s *JobEscalationService) runEscalationSteps(
+	ctx context.Context,
+	inst *models.JobInstance,
+	defn *models.JobDefinition,
+	prop *models.Property,
+	policy *models.EscalationPolicy,
+	surgePolicies []*models.SurgePolicy,
+	executed map[string]bool,
+	lStart, now time.Time,
+) {
+	done := make(map[string]bool, len(executed))
+	for k := range executed {
+		done[k] = true
+	}
+	for {
+		step, ok := nextEscalationStep(policy, inst, done, lStart, now)
+		if !ok {
+			return
+		}
+		done[step.Key] = true
+
+		exec := &models.EscalationStepExecution{
+			ID:         uuid.New(),
+			InstanceID: inst.ID,
+			StepKey:    step.Key,
+			Action:     step.Action,
+		}
+		if policy.ID != uuid.Nil {
+			exec.PolicyID = &policy.ID
+		}
+		claimed, err := s.escalationRepo.ClaimStep(ctx, exec)
+		if err != nil {
+			utils.Logger.WithError(err).Errorf("Escalation: failed to claim step %s for job=%s", step.Key, inst.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		outcome, details := s.executeEscalationStep(ctx, inst, defn, prop, surgePolicies, step, lStart)
+		if err := s.escalationRepo.FinishStep(ctx, exec.ID, outcome, details); err != nil {
+			utils.Logger.WithError(err).Errorf("Escalation: failed to record step %s for job=%s", step.Key, inst.ID)
+		}
+	}
+}
+
+// executeEscalationStep performs the step's action and then notifies its
+// audience. Notifications only go out when the action took effect.
+func (s *JobEscalationService) executeEscalationStep(
+	ctx context.Context,
+	inst *models.JobInstance,
+	defn *models.JobDefinition,
+	prop *models.Property,
+	surgePolicies []*models.SurgePolicy,
+	step models.EscalationStep,
+	lStart time.Time,
+) (models.EscalationOutcomeType, map[string]any) {
+	details := map[string]any{}
+
+	switch step.Action {
+	case models.EscalationActionSurge:
+		policy := effectiveSurgePolicy(surgePolicies, defn, prop)
+		oldPay, newPay, applied := s.jobService.applySurge(ctx, s.jobInstRepo, inst, defn, policy, step.Multiplier, step.Bonus, models.PayItemSurge, nil)
+		details["old_pay"], details["new_pay"] = oldPay, newPay
+		if !applied {
+			return models.EscalationOutcomeSkipped, details
+		}
+		inst.EffectivePay = newPay
+
+	case models.EscalationActionReopen:
+		if err := s.forceReopenNoShow(ctx, inst); err != nil {
+			details["error"] = err.Error()
+			return models.EscalationOutcomeFailed, details
+		}
+		inst.Status = models.InstanceStatusOpen
+
+	case models.EscalationActionCancel:
+		canceled, err := s.jobInstRepo.UpdateStatusToCancelled(ctx, inst.ID, inst.RowVersion)
+		if err != nil {
+			utils.Logger.WithError(err).Error("RunEscalationCheck: auto-cancel failed")
+			details["error"] = err.Error()
+			return models.EscalationOutcomeFailed, details
+		}
+		if canceled == nil {
+			utils.Logger.Warnf("RunEscalationCheck: no rows updated for job=%s", inst.ID)
+			details["error"] = "instance changed concurrently"
+			return models.EscalationOutcomeFailed, details
+		}
+		inst.Status = models.InstanceStatusCanceled
+	}
+
+	if step.Audience != models.EscalationAudienceNone {
+		replacer := strings.NewReplacer(
+			"{property}", prop.PropertyName,
+			"{latest_start}", lStart.Format("3:04 PM MST"),
+		)
+		details["notified"] = s.notifyEscalationAudience(
+			ctx, defn, inst, prop, step.Audience, step.Channel,
+			replacer.Replace(step.Title), replacer.Replace(step.Message),
+		)
+	}
+	return models.EscalationOutcomeExecuted, details
+}
+
+// notifyEscalationAudience sends title/body to audience and returns how many
+// direct recipients were contacted (internal and on-call sends count as one).
+func (s *JobEscalationService) notifyEscalationAudience(
+	ctx context.Context,
+	defn *models.JobDefinition,
+	inst *models.JobInstance,
+	prop *models.Property,
+	audience models.EscalationAudienceType,
+	channel models.EscalationChannelType,
+	title, body string,
+) int {
+	switch audience {
+	case models.EscalationAudienceInternal:
+		s.notifyInternalTeam(ctx, defn, inst, title, body)
+		return 1
+
+	case models.EscalationAudienceOnCallAgents:
+		s.notifyOnCallStaff(ctx, defn, inst, title, body)
+		return 1
+
+	case models.EscalationAudiencePropertyManager:
+		pm, err := s.jobService.pmRepo.GetByID(ctx, prop.ManagerID)
+		if err != nil || pm == nil {
+			utils.Logger.WithError(err).Warnf("Escalation: property manager not found for property=%s", prop.ID)
+			return 0
+		}
+		phone := ""
+		if pm.PhoneNumber != nil {
+			phone = *pm.PhoneNumber
+		}
+		if s.sendDirectNotification(ctx, channel, pm.BusinessName, pm.Email, phone, title, body) {
+			return 1
+		}
+		return 0
+
+	case models.EscalationAudienceOfferedWorkers:
+		// Workers who asked for the job through its lottery and did not end
+		// up with it are the ones known to want it.
+		entries, err := s.jobService.lotteryRepo.ListEntries(ctx, inst.ID)
+		if err != nil {
+			utils.Logger.WithError(err).Warnf("Escalation: failed to list offered workers for job=%s", inst.ID)
+			return 0
+		}
+		sent := 0
+		for _, e := range entries {
+			if e.Status == models.LotteryEntryWon {
+				continue
+			}
+			w, wErr := s.workerRepo.GetByID(ctx, e.WorkerID)
+			if wErr != nil || w == nil {
+				continue
+			}
+			name := strings.TrimSpace(w.FirstName + " " + w.LastName)
+			if s.sendDirectNotification(ctx, channel, name, w.Email, w.PhoneNumber, title, body) {
+				sent++
+			}
+		}
+		return sent
+	}
+	return 0
+}
+
+// sendDirectNotification queues an email and/or text to one recipient
+// according to channel and reports whether anything was queued.
+func (s *JobEscalationService) sendDirectNotification(
+	ctx context.Context,
+	channel models.EscalationChannelType,
+	name, email, phone string,
+	title, body string,
+) bool {
+	if !s.cfg.LDFlag_NotifyJobStatuses {
+		utils.Logger.Info("Escalation notification skipped due to feature flag.")
+		return false
+	}
+	sent := false
+
+	if channel != models.EscalationChannelEmail && phone != "" {
+		s.jobService.notifier.SMS(ctx, utils.SMSJob{
+			From: s.cfg.LDFlag_TwilioFromPhone,
+			To:   phone,
+			Body: title + " :: " + body,
+		})
+		sent = true
+	}
+
+	if channel != models.EscalationChannelSMS && email != "" {
+		s.jobService.notifier.Email(ctx, utils.EmailJob{
+			FromName:  s.cfg.OrganizationName,
+			FromEmail: s.cfg.LDFlag_SendgridFromEmail,
+			ToName:    name,
+			ToEmail:   email,
+			Subject:   title,
+			PlainText: body,
+			Sandbox:   s.cfg.LDFlag_SendgridSandboxMode,
+		})
+		sent = true
+	}
+	return sent
+}
+
+/*──────────── ops API ────────────*/
+
+var (
+	validEscalationActions = map[models.EscalationActionType]bool{
+		models.EscalationActionNotify: true,
+		models.EscalationActionSurge:  true,
+		models.EscalationActionReopen: true,
+		models.EscalationActionCancel: true,
+	}
+	validEscalationAudiences = map[models.EscalationAudienceType]bool{
+		models.EscalationAudienceNone:            true,
+		models.EscalationAudienceInternal:        true,
+		models.EscalationAudienceOnCallAgents:    true,
+		models.EscalationAudiencePropertyManager: true,
+		models.EscalationAudienceOfferedWorkers:  true,
+	}
+	validEscalationChannels = map[models.EscalationChannelType]bool{
+		"":                            true,
+		models.EscalationChannelEmail: true,
+		models.EscalationChannelSMS:   true,
+		models.EscalationChannelAll:   true,
+	}
+	validEscalationStatuses = map[models.InstanceStatusType]bool{
+		models.InstanceStatusOpen:       true,
+		models.InstanceStatusAssigned:   true,
+		models.InstanceStatusInProgress: true,
+	}
+)
+
+func validateEscalationPolicy(req dtos.EscalationPolicyRequest) error {
+	if err := validatePolicyScope(req.Scope, req.ScopeKey); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(req.Steps))
+	for _, st := range req.Steps {
+		if st.Key == "" || seen[st.Key] {
+			return fmt.Errorf("%w: every step needs a unique key", internal_utils.ErrInvalidPayload)
+		}
+		seen[st.Key] = true
+
+		if !validEscalationActions[st.Action] {
+			return fmt.Errorf("%w: step %q has unknown action %q", internal_utils.ErrInvalidPayload, st.Key, st.Action)
+		}
+		if !validEscalationAudiences[st.Audience] || !validEscalationChannels[st.Channel] {
+			return fmt.Errorf("%w: step %q has unknown audience or channel", internal_utils.ErrInvalidPayload, st.Key)
+		}
+		if st.Action == models.EscalationActionNotify && st.Audience == models.EscalationAudienceNone {
+			return fmt.Errorf("%w: NOTIFY step %q needs an audience", internal_utils.ErrInvalidPayload, st.Key)
+		}
+		if st.Audience != models.EscalationAudienceNone && st.Title == "" {
+			return fmt.Errorf("%w: step %q notifies and needs a title", internal_utils.ErrInvalidPayload, st.Key)
+		}
+		if len(st.Statuses) == 0 {
+			return fmt.Errorf("%w: step %q needs at least one status", internal_utils.ErrInvalidPayload, st.Key)
+		}
+		for _, status := range st.Statuses {
+			if !validEscalationStatuses[status] {
+				return fmt.Errorf("%w: step %q may only watch OPEN, ASSIGNED or IN_PROGRESS jobs", internal_utils.ErrInvalidPayload, st.Key)
+			}
+		}
+		if st.Action == models.EscalationActionSurge && (st.Multiplier < 1 || st.Bonus.IsNegative()) {
+			return fmt.Errorf("%w: SURGE step %q needs multiplier >= 1 and bonus >= 0", internal_utils.ErrInvalidPayload, st.Key)
+		}
+	}
+	return nil
+}
+
+// UpsertEscalationPolicy creates or replaces a policy on behalf of actorID
+// and records who did it, with the policy before and after.
+func (s *JobEscalationService) UpsertEscalationPolicy(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.EscalationPolicyRequest,
+) (*models.EscalationPolicy, error) {
+	if err := validateEscalationPolicy(req); err != nil {
+		return nil, err
+	}
+
+	action := models.EscalationAuditPolicyCreated
+	policy := &models.EscalationPolicy{ID: uuid.New(), CreatedBy: &actorID, Active: true}
+	var before *models.EscalationPolicy
+	if req.ID != nil {
+		existing, err := s.escalationRepo.GetByID(ctx, *req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, internal_utils.ErrEscalationPolicyNotFound
+		}
+		snapshot := *existing
+		before, policy = &snapshot, existing
+		action = models.EscalationAuditPolicyUpdated
+	}
+
+	policy.Name = req.Name
+	policy.Scope = req.Scope
+	policy.ScopeKey = req.ScopeKey
+	policy.Steps = req.Steps
+	if req.Active != nil {
+		policy.Active = *req.Active
+	}
+	if before != nil && before.Active && !policy.Active {
+		action = models.EscalationAuditPolicyDeactivated
+	}
+
+	// The policy and its audit event are saved together or not at all.
+	err := s.jobService.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		repo := w.EscalationPolicies()
+		var err error
+		if req.ID == nil {
+			err = repo.Create(ctx, policy)
+		} else {
+			err = repo.Update(ctx, policy)
+		}
+		if err != nil {
+			return err
+		}
+
+		details := map[string]any{"after": policy}
+		if before != nil {
+			details["before"] = before
+		}
+		return repo.CreateAuditEvent(ctx, &models.EscalationPolicyAuditEvent{
+			ID:       uuid.New(),
+			Action:   action,
+			ActorID:  actorID,
+			PolicyID: &policy.ID,
+			Reason:   req.Reason,
+			Details:  details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Escalation policy %s (%s) saved by %s", policy.ID, policy.Name, actorID)
+	return policy, nil
+}
+
+func (s *JobEscalationService) ListEscalationPolicies(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	return s.escalationRepo.ListAll(ctx)
+}
+
+func (s *JobEscalationService) ListEscalationAuditEvents(
+	ctx context.Context,
+	policyID *uuid.UUID,
+	limit int,
+) ([]*models.EscalationPolicyAuditEvent, error) {
+	return s.escalationRepo.ListAuditEvents(ctx, policyID, limit)
+}
+
+// ListEscalationExecutions returns the steps run so far for an instance and
+// the policy that currently governs it.
+func (s *JobEscalationService) ListEscalationExecutions(
+	ctx context.Context,
+	instanceID uuid.UUID,
+) (*dtos.EscalationExecutionsResponse, error) {
+	inst, err := s.jobInstRepo.GetByID(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if inst == nil {
+		return nil, internal_utils.ErrInstanceNotFound
+	}
+	execs, err := s.escalationRepo.ListExecutions(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dtos.EscalationExecutionsResponse{InstanceID: instanceID, Executions: execs}
+	if defn, _ := s.jobDefRepo.GetByID(ctx, inst.DefinitionID); defn != nil {
+		prop, _ := s.propRepo.GetByID(ctx, defn.PropertyID)
+		if p := effectiveEscalationPolicy(s.activeEscalationPolicies(ctx), defn, prop); p.ID != uuid.Nil {
+			resp.PolicyID = &p.ID
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// tickEscalation runs the built-in policy for one check at now the way
+// runEscalationSteps does, applying each action's effect on the status, and
+// returns the keys of the steps that fired.
+func tickEscalation(inst *models.JobInstance, done map[string]bool, lStart, now time.Time) []string {
+	var fired []string
+	for {
+		step, ok := nextEscalationStep(defaultEscalationPolicy(), inst, done, lStart, now)
+		if !ok {
+			return fired
+		}
+		done[step.Key] = true
+		fired = append(fired, step.Key)
+		switch step.Action {
+		case models.EscalationActionReopen:
+			inst.Status, inst.AssignedWorkerID = models.InstanceStatusOpen, nil
+		case models.EscalationActionCancel:
+			inst.Status = models.InstanceStatusCanceled
+		}
+	}
+}
+
+func TestDefaultEscalationPolicyMatchesOldTimers(t *testing.T) {
+	lStart := time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return lStart.Add(d) }
+	worker := uuid.New()
+	checkedIn := at(-30 * time.Minute)
+
+	type tick struct {
+		now  time.Time
+		want []string
+	}
+	for name, tc := range map[string]struct {
+		status    models.InstanceStatusType
+		checkedIn bool
+		ticks     []tick
+	}{
+		"open job warns at 90 and 40 minutes then expires": {
+			status: models.InstanceStatusOpen,
+			ticks: []tick{
+				{now: at(-2 * time.Hour)},
+				{now: at(-constants.Warning90MinBeforeLatestStart), want: []string{escalationStepInternalWarning}},
+				{now: at(-60 * time.Minute)},
+				{now: at(-constants.Warning40MinBeforeLatestStart), want: []string{escalationStepOnCallWarning}},
+				{now: at(-time.Minute)},
+				{now: at(time.Minute), want: []string{escalationStepExpiredCancel}},
+				{now: at(2 * time.Minute)},
+			},
+		},
+		"no-show is reopened and warned about in the same check": {
+			status: models.InstanceStatusAssigned,
+			ticks: []tick{
+				{now: at(-constants.Warning90MinBeforeLatestStart)},
+				{now: at(-constants.Warning40MinBeforeLatestStart)},
+				{now: at(-constants.NoShowCutoffBeforeLatestStart), want: []string{
+					escalationStepNoShowReopen, escalationStepInternalWarning, escalationStepOnCallWarning,
+				}},
+				{now: at(time.Minute), want: []string{escalationStepExpiredCancel}},
+			},
+		},
+		"checked-in worker is not a no-show": {
+			status:    models.InstanceStatusAssigned,
+			checkedIn: true,
+			ticks: []tick{
+				{now: at(-constants.NoShowCutoffBeforeLatestStart)},
+				{now: at(time.Minute), want: []string{escalationStepExpiredCancel}},
+			},
+		},
+		"first check after expiry catches up on everything": {
+			status: models.InstanceStatusOpen,
+			ticks: []tick{
+				{now: at(5 * time.Minute), want: []string{
+					escalationStepInternalWarning, escalationStepOnCallWarning, escalationStepExpiredCancel,
+				}},
+			},
+		},
+	} {
+		inst := &models.JobInstance{ID: uuid.New(), Status: tc.status}
+		if tc.status == models.InstanceStatusAssigned {
+			inst.AssignedWorkerID = &worker
+		}
+		if tc.checkedIn {
+			inst.CheckInAt = &checkedIn
+		}
+		done := map[string]bool{}
+		for _, tk := range tc.ticks {
+			if got := tickEscalation(inst, done, lStart, tk.now); !slices.Equal(got, tk.want) {
+				t.Errorf("%s: at %s expected %v, got %v", name, tk.now.Sub(lStart), tk.want, got)
+			}
+		}
+	}
+}
+
+func TestDefaultEscalationPolicySkipsStepsAlreadyRun(t *testing.T) {
+	lStart := time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+	inst := &models.JobInstance{ID: uuid.New(), Status: models.InstanceStatusOpen}
+	// Carried over from the old warning_90_min_sent_at column.
+	done := map[string]bool{escalationStepInternalWarning: true}
+
+	got := tickEscalation(inst, done, lStart, lStart.Add(-constants.Warning40MinBeforeLatestStart))
+	if want := []string{escalationStepOnCallWarning}; !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDefaultSurgePolicyMatchesOldStages(t *testing.T) {
+	// The fixed stages surge used before policies were stored.
+	old := func(timeLeft time.Duration) (float64, bool) {
+		switch {
+		case timeLeft < constants.SurgeWindowStage4:
+			return constants.SurgeMultiplierStage4, true
+		case timeLeft < constants.SurgeWindowStage3:
+			return constants.SurgeMultiplierStage3, true
+		case timeLeft < constants.SurgeWindowStage2:
+			return constants.SurgeMultiplierStage2, true
+		case timeLeft < constants.SurgeWindowStage1:
+			return constants.SurgeMultiplierStage1, true
+		}
+		return 0, false
+	}
+
+	policy := defaultSurgePolicy()
+	serviceDate := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	for _, left := range []time.Duration{
+		7 * time.Hour, 6 * time.Hour, 6*time.Hour - time.Second, 4 * time.Hour,
+		3 * time.Hour, 2 * time.Hour, 90 * time.Minute, 60 * time.Minute,
+		45 * time.Minute, 44 * time.Minute, time.Minute, 0,
+	} {
+		wantMult, wantOK := old(left)
+		mult, bonus, ok := policy.Evaluate(serviceDate, left)
+		if ok != wantOK || mult != wantMult || !bonus.IsZero() {
+			t.Errorf("%s before no-show: expected %.2f (%v), got %.2f (%v) with bonus %s", left, wantMult, wantOK, mult, ok, bonus)
+		}
+	}
+}
@@ -2,16 +2,15 @@ package services
 
 import (
 	"context"
-	"time"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/config"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/twilio/twilio-go"
 )
 
 // MODIFIED: Added building and unit repos for detailed notifications.
@@ -25,8 +24,7 @@ type JobEscalationService struct {
 	agentJobCompletionRepo repositories.AgentJobCompletionRepository
 	bldgRepo               repositories.PropertyBuildingRepository
 	unitRepo               repositories.UnitRepository
-	twilioClient           *twilio.RestClient
-	sendgridClient         *sendgrid.Client
+	escalationRepo         repositories.EscalationPolicyRepository
 	jobService             *JobService
 }
 
@@ -41,14 +39,9 @@ func NewJobEscalationService(
 	ajcRepo repositories.AgentJobCompletionRepository,
 	bldgRepo repositories.PropertyBuildingRepository,
 	unitRepo repositories.UnitRepository,
+	escalationRepo repositories.EscalationPolicyRepository,
 	jobService *JobService,
 ) *JobEscalationService {
-	twClient := twilio.NewRestClientWithParams(twilio.ClientParams{
-		Username: cfg.TwilioAccountSID,
-		Password: cfg.TwilioAuthToken,
-	})
-	sgClient := sendgrid.NewSendClient(cfg.SendGridAPIKey)
-
 	return &JobEscalationService{
 		cfg:                    cfg,
 		jobDefRepo:             defRepo,
@@ -59,13 +52,13 @@ func NewJobEscalationService(
 		agentJobCompletionRepo: ajcRepo,
 		bldgRepo:               bldgRepo,
 		unitRepo:               unitRepo,
-		twilioClient:           twClient,
-		sendgridClient:         sgClient,
+		escalationRepo:         escalationRepo,
 		jobService:             jobService,
 	}
 }
 
-// RunEscalationCheck scans jobs for surges, no-shows, etc., including forcibly canceling an open job at T-20.
+// RunEscalationCheck applies surge pricing to open jobs and runs each job's
+// escalation policy steps (warnings, no-show reopen, expiry cancel, ...).
 func (s *JobEscalationService) RunEscalationCheck(ctx context.Context) error {
 	utils.Logger.Debug("Running JCAS escalation checks...")
 
@@ -83,6 +76,19 @@ func (s *JobEscalationService) RunEscalationCheck(ctx context.Context) error {
 		return err
 	}
 
+	surgePolicies := s.jobService.activeSurgePolicies(ctx)
+	escalationPolicies := s.activeEscalationPolicies(ctx)
+
+	instanceIDs := make([]uuid.UUID, 0, len(openOrAssigned))
+	for _, inst := range openOrAssigned {
+		instanceIDs = append(instanceIDs, inst.ID)
+	}
+	// Only a prefilter; ClaimStep is what guarantees a step runs once.
+	executed, err := s.escalationRepo.ListExecutedStepKeys(ctx, instanceIDs)
+	if err != nil {
+		utils.Logger.WithError(err).Warn("RunEscalationCheck: failed to load executed steps")
+	}
+
 	for _, inst := range openOrAssigned {
 		defn, err := s.jobDefRepo.GetByID(ctx, inst.DefinitionID)
 		if err != nil || defn == nil {
@@ -102,63 +108,22 @@ func (s *JobEscalationService) RunEscalationCheck(ctx context.Context) error {
 		lStart := time.Date(inst.ServiceDate.Year(), inst.ServiceDate.Month(), inst.ServiceDate.Day(), defn.LatestStartTime.Hour(), defn.LatestStartTime.Minute(), 0, 0, propLoc)
 
 		// Possibly apply surge multipliers to any open job, comparing against the consistent UTC `now`.
-		s.applySurgeIfNeeded(ctx, inst, defn, lStart, nowUTC)
-
-		// If assigned but not yet started, check the "no-show" cutoff
-		if inst.Status == models.InstanceStatusAssigned && inst.CheckInAt == nil {
-			cutoff := lStart.Add(-constants.NoShowCutoffBeforeLatestStart)
-			if nowUTC.After(cutoff) {
-				s.forceReopenNoShow(ctx, inst, defn, prop) // MODIFIED: Pass defn and prop
-				// Update local status to OPEN for subsequent checks
-				inst.Status = models.InstanceStatusOpen
-			}
-		}
-
-		// If the job is still open, check for 90- and 40-minute warnings.
-		if inst.Status == models.InstanceStatusOpen {
-			// Check for 90-minute warning (internal team only)
-			warning90MinCutoff := lStart.Add(-constants.Warning90MinBeforeLatestStart)
-			if nowUTC.After(warning90MinCutoff) && inst.Warning90MinSentAt == nil {
-				msgBody := fmt.Sprintf("This job at %s is unassigned and is approaching its latest start time of %s.", prop.PropertyName, lStart.Format("3:04 PM MST"))
-				s.notifyInternalTeam(ctx, defn, inst, "[Warning] Unassigned Job (90 Min)", msgBody)
-				s.jobInstRepo.SetWarning90MinSent(ctx, inst.ID)
-			}
+		s.applySurgeIfNeeded(ctx, inst, defn, prop, surgePolicies, lStart, nowUTC)
 
-			// Check for 40-minute warning (all-call)
-			warning40MinCutoff := lStart.Add(-constants.Warning40MinBeforeLatestStart)
-			if nowUTC.After(warning40MinCutoff) && inst.Warning40MinSentAt == nil {
-				msgBody := fmt.Sprintf("This job at %s is unassigned and is approaching its latest start time of %s. This is the final warning.", prop.PropertyName, lStart.Format("3:04 PM MST"))
-				s.notifyOnCallStaff(ctx, defn, inst, "[Urgent] Unassigned Job (40 Min)", msgBody)
-				s.jobInstRepo.SetWarning40MinSent(ctx, inst.ID)
-			}
-		}
-
-		// Cancel any job whose latest start time has passed
-		if (inst.Status == models.InstanceStatusOpen || inst.Status == models.InstanceStatusAssigned) && nowUTC.After(lStart) {
-			rowVersion := inst.RowVersion
-			canceled, err := s.jobInstRepo.UpdateStatusToCancelled(ctx, inst.ID, rowVersion)
-			if err != nil {
-				utils.Logger.WithError(err).Error("RunEscalationCheck: auto-cancel failed")
-				continue
-			}
-			if canceled == nil {
-				utils.Logger.Warnf("RunEscalationCheck: no rows updated for job=%s", inst.ID)
-				continue
-			}
-			// MODIFIED: More descriptive message body.
-			msgBody := fmt.Sprintf("This job at %s exceeded its latest start time of %s and has been automatically canceled.", prop.PropertyName, lStart.Format("3:04 PM MST"))
-			s.notifyInternalTeam(ctx, defn, inst, "Job Auto-Canceled After Expiry", msgBody)
-		}
+		policy := effectiveEscalationPolicy(escalationPolicies, defn, prop)
+		s.runEscalationSteps(ctx, inst, defn, prop, policy, surgePolicies, executed[inst.ID], lStart, nowUTC)
 	}
 	return nil
 }
 
-// applySurgeIfNeeded checks if an open job is within a surge window and applies the
-// corresponding pay multiplier. The windows are anchored to the job's no-show time.
+// applySurgeIfNeeded evaluates the job's effective surge policy and raises pay
+// when a stage applies. Stages are anchored to the job's no-show time.
 func (s *JobEscalationService) applySurgeIfNeeded(
 	ctx context.Context,
 	inst *models.JobInstance,
 	defn *models.JobDefinition,
+	prop *models.Property,
+	policies []*models.SurgePolicy,
 	lStart, now time.Time,
 ) {
 	if inst.Status != models.InstanceStatusOpen || lStart.IsZero() {
@@ -170,48 +135,23 @@ func (s *JobEscalationService) applySurgeIfNeeded(
 		return // No surges after the no-show time has passed
 	}
 
-	timeLeft := noShowTime.Sub(now)
-	var multiplier float64
-
-	// Logic checks from highest surge (closest to no-show time) to lowest.
-	// The first match is the one that applies.
-	if timeLeft < constants.SurgeWindowStage4 { // T-30m -> no-show time
-		multiplier = constants.SurgeMultiplierStage4
-	} else if timeLeft < constants.SurgeWindowStage3 { // T-90m -> T-30m
-		multiplier = constants.SurgeMultiplierStage3
-	} else if timeLeft < constants.SurgeWindowStage2 { // T-3h -> T-90m
-		multiplier = constants.SurgeMultiplierStage2
-	} else if timeLeft < constants.SurgeWindowStage1 { // T-6h -> T-3h
-		multiplier = constants.SurgeMultiplierStage1
-	}
-
-	if multiplier > 0 {
-		s.jobService.ApplyManualSurge(ctx, inst, defn, multiplier)
+	policy := effectiveSurgePolicy(policies, defn, prop)
+	multiplier, bonus, ok := policy.Evaluate(inst.ServiceDate, noShowTime.Sub(now))
+	if ok {
+		s.jobService.applySurge(ctx, s.jobInstRepo, inst, defn, policy, multiplier, bonus, models.PayItemSurge, nil)
 	}
 }
 
-// MODIFIED: Now triggers a notification for the no-show event.
-func (s *JobEscalationService) forceReopenNoShow(ctx context.Context, inst *models.JobInstance, defn *models.JobDefinition, prop *models.Property) {
+// forceReopenNoShow penalizes the assigned worker and puts the job back up.
+func (s *JobEscalationService) forceReopenNoShow(ctx context.Context, inst *models.JobInstance) error {
 	if inst.Status != models.InstanceStatusAssigned || inst.AssignedWorkerID == nil {
-		return
+		return fmt.Errorf("job %s is not assigned", inst.ID)
 	}
-	assignedWorkerID := *inst.AssignedWorkerID
-	err := s.workerRepo.AdjustWorkerScoreAtomic(ctx, assignedWorkerID, constants.WorkerPenaltyNoShow, "NOSHOW")
-	if err != nil {
-		utils.Logger.WithError(err).Errorf("Failed to penalize no-show for worker=%s job=%s", assignedWorkerID, inst.ID)
-	}
-	_, err2 := s.jobService.ForceReopenNoShow(ctx, inst.ID, assignedWorkerID)
-	if err2 != nil {
-		utils.Logger.WithError(err2).Error("forceReopenNoShow: Unassign failed")
-		return // Do not notify if the unassign failed
+	if _, err := s.jobService.ForceReopenNoShow(ctx, inst.ID, *inst.AssignedWorkerID); err != nil {
+		utils.Logger.WithError(err).Error("forceReopenNoShow: Unassign failed")
+		return err
 	}
-
-	// NEW: Send notification after successfully reopening the job.
-	messageBody := fmt.Sprintf(
-		"The worker assigned to this job at %s was a no-show. The job has been reopened and requires immediate coverage.",
-		prop.PropertyName,
-	)
-	s.notifyOnCallStaff(ctx, defn, inst, "[Escalation] Worker No-Show", messageBody)
+	return nil
 }
 
 // MODIFIED: Updated to pass building and unit repos to the notification helper.
@@ -239,8 +179,7 @@ func (s *JobEscalationService) notifyOnCallStaff(
 		s.agentJobCompletionRepo,
 		s.bldgRepo,
 		s.unitRepo,
-		s.twilioClient,
-		s.sendgridClient,
+		s.jobService.notifier,
 		s.cfg,
 	)
 }
@@ -266,7 +205,7 @@ func (s *JobEscalationService) notifyInternalTeam(
 		msgBody,
 		s.bldgRepo,
 		s.unitRepo,
-		s.sendgridClient,
+		s.jobService.notifier,
 		s.cfg,
 	)
 }
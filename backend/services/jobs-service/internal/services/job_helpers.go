@@ -12,6 +12,7 @@ import (
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
 )
 
 func CalculatePenaltyForUnassign(now, eStart, noShowTime time.Time) (int, bool) {
@@ -84,7 +85,8 @@ func (s *JobService) buildInstanceDTO(
 	var unitDTOs []dtos.UnitVerificationDTO
 	floorSet := make(map[int16]struct{})
 	totalUnits := 0
-	if len(jdef.AssignedUnitsByBuilding) > 0 {
+	unitGroups := jdef.UnitGroupsForSegment(inst.SegmentIndex)
+	if len(unitGroups) > 0 {
 		if bCache == nil {
 			bCache = make(map[uuid.UUID]*models.PropertyBuilding)
 		}
@@ -98,7 +100,7 @@ func (s *JobService) buildInstanceDTO(
 			verifMap[v.UnitID] = v
 		}
 
-		for _, grp := range jdef.AssignedUnitsByBuilding {
+		for _, grp := range unitGroups {
 			b, ok := bCache[grp.BuildingID]
 			if !ok {
 				b, _ = s.bldgRepo.GetByID(ctx, grp.BuildingID)
@@ -233,6 +235,9 @@ func (s *JobService) buildInstanceDTO(
 	if dailyEstimate != nil {
 		estimatedTimeMins = dailyEstimate.EstimatedTimeMinutes
 	}
+	if jdef.IsSegmented() {
+		estimatedTimeMins = max(int(math.Round(float64(estimatedTimeMins)*jdef.SegmentPayShare(inst.SegmentIndex))), MinJobTimeEstimateInt)
+	}
 
 	dto := &dtos.JobInstanceDTO{
 		InstanceID:   inst.ID,
@@ -258,6 +263,8 @@ func (s *JobService) buildInstanceDTO(
 		UnitVerifications:          unitDTOs,
 		Floors:                     floors,
 		TotalUnits:                 totalUnits,
+		SegmentIndex:               inst.SegmentIndex,
+		SegmentCount:               jdef.SegmentCount(),
 		StartTimeHint:              sthProp,
 		WorkerStartTimeHint:        sthWorker,
 		PropertyServiceWindowStart: pwws,
@@ -288,7 +295,7 @@ func (s *JobService) applyCompletionTimeEma(ctx context.Context, defID uuid.UUID
 		dailyEstimate := d.GetDailyEstimate(dayOfWeek)
 		if dailyEstimate == nil {
 			utils.Logger.Warnf("applyCompletionTimeEma: No daily estimate found for job_definition_id=%s, day_of_week=%s. Cannot update EMA.", d.ID, dayOfWeek)
-			return fmt.Errorf("no daily estimate found for day %s in job definition %s", dayOfWeek, d.ID)
+			return fmt.Errorf("%w: day %s in job definition %s", internal_utils.ErrNoDailyEstimate, dayOfWeek, d.ID)
 		}
 
 		oldEstimateFloat := float64(dailyEstimate.EstimatedTimeMinutes)
@@ -319,13 +326,12 @@ func (s *JobService) applyCompletionTimeEma(ctx context.Context, defID uuid.UUID
 
 		// Also update base_pay proportionally
 		initialBasePay := dailyEstimate.InitialBasePay
-		initialEstTime := float64(dailyEstimate.InitialEstimatedTimeMinutes)
+		initialEstTime := dailyEstimate.InitialEstimatedTimeMinutes
 		if initialEstTime <= 0 {
-			initialEstTime = MinJobTimeEstimateFloat
+			initialEstTime = MinJobTimeEstimateInt
 		}
 
-		proportionalPay := (float64(newEstimateInt) / initialEstTime) * initialBasePay
-		newBasePay := math.Round(proportionalPay*100) / 100 // round to 2 decimal places
+		newBasePay := initialBasePay.MulRatio(int64(newEstimateInt), int64(initialEstTime))
 
 		// Update the values on the definition object
 		dailyEstimate.EstimatedTimeMinutes = newEstimateInt
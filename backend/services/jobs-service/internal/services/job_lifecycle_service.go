@@ -14,6 +14,7 @@ import (
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
 	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	logrus "github.com/sirupsen/logrus"
 )
@@ -115,6 +116,11 @@ func (s *JobService) AcceptJobInstanceWithLocation(
 		if distMiles > float64(constants.RadiusMiles) {
 			return nil, internal_utils.ErrLocationOutOfBounds
 		}
+		// Lottery properties collect accepts until the draw instead of
+		// assigning the first caller.
+		if lErr := s.maybeEnterLottery(ctx, inst, prop, worker); lErr != nil {
+			return nil, lErr
+		}
 	}
 
 	newAssignCount := inst.AssignUnassignCount + 1
@@ -158,20 +164,7 @@ func (s *JobService) AcceptJobInstanceWithLocation(
 	// Compute and include routing info so client sees distance/time immediately
 	var route *routeInfo
 	if locReq.Lat != 0 || locReq.Lng != 0 {
-		var distMiles float64
-		var travelMins *int
-		if s.cfg.LDFlag_UseGMapsRoutesAPI && s.cfg.GMapsRoutesAPIKey != "" {
-			if dMiles, dMins, gErr := utils.ComputeDriveDistanceTimeMiles(locReq.Lat, locReq.Lng, prop.Latitude, prop.Longitude, s.cfg.GMapsRoutesAPIKey); gErr == nil {
-				distMiles = dMiles
-				travelMins = &dMins
-			}
-		}
-		if travelMins == nil {
-			distMiles = utils.DistanceMiles(locReq.Lat, locReq.Lng, prop.Latitude, prop.Longitude)
-			estMins := int(distMiles * utils.CrowFliesDriveTimeMultiplier)
-			travelMins = &estMins
-		}
-		route = &routeInfo{DistanceMiles: distMiles, TravelMinutes: travelMins}
+		route = s.computeRoute(ctx, locReq.Lat, locReq.Lng, prop)
 	}
 
 	dto, _ := s.buildInstanceDTO(ctx, updated, route, workerLoc, nil, nil, nil, nil, nil)
@@ -298,7 +291,7 @@ func (s *JobService) VerifyUnitPhoto(
 
 	// Ensure unit is part of the assignment
 	allowed := false
-	for _, grp := range defn.AssignedUnitsByBuilding {
+	for _, grp := range defn.UnitGroupsForSegment(inst.SegmentIndex) {
 		if slices.Contains(grp.UnitIDs, unitID) {
 			allowed = true
 		}
@@ -330,61 +323,93 @@ func (s *JobService) VerifyUnitPhoto(
 	if !isReviewer && s.cfg.LDFlag_OpenAIPhotoVerification {
 		result, err := s.openai.VerifyPhoto(ctx, photo, unit.UnitNumber)
 		if err != nil {
-			return nil, err
-		}
-		utils.Logger.WithFields(logrus.Fields{
-			"unit_id":              unitID,
-			"trash_can_present":    result.TrashCanPresent,
-			"no_trash_bag_visible": result.NoTrashBagVisible,
-			"door_number_matches":  result.DoorNumberMatches,
-			"door_number_detected": result.DoorNumberDetected,
-			"door_fully_visible":   result.DoorFullyVisible,
-		}).Debug("openai verification result")
-
-		pass := false
-		if missingTrashCan {
-			pass = result.DoorNumberMatches && result.DoorFullyVisible
-			if !result.DoorNumberMatches {
-				if result.DoorNumberDetected != "" {
-					reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISMATCH")
-				} else {
-					reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISSING")
-				}
-			}
-			if !result.DoorFullyVisible {
-				reasonCodes = append(reasonCodes, "DOOR_NOT_FULLY_VISIBLE")
+			// Don't strand the worker on an OpenAI outage: hold the unit as
+			// PENDING and let the job queue retry the check.
+			utils.Logger.WithError(err).Warnf("Photo verification unavailable for unit %s; queueing a retry", unitID)
+			if qErr := s.queuePhotoVerification(ctx, instanceID, unitID, unit.UnitNumber, missingTrashCan, photo); qErr != nil {
+				utils.Logger.WithError(qErr).Error("Failed to queue photo verification retry")
+				return nil, err
 			}
+			status = models.UnitVerificationPending
 		} else {
-			pass = result.TrashCanPresent && result.NoTrashBagVisible && result.DoorNumberMatches && result.DoorFullyVisible
-			if !result.TrashCanPresent {
-				reasonCodes = append(reasonCodes, "TRASH_CAN_NOT_VISIBLE")
-			}
-			if !result.NoTrashBagVisible {
-				reasonCodes = append(reasonCodes, "TRASH_BAG_VISIBLE")
-			}
-			if !result.DoorNumberMatches {
-				if result.DoorNumberDetected != "" {
-					reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISMATCH")
-				} else {
-					reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISSING")
-				}
+			utils.Logger.WithFields(logrus.Fields{
+				"unit_id":              unitID,
+				"trash_can_present":    result.TrashCanPresent,
+				"no_trash_bag_visible": result.NoTrashBagVisible,
+				"door_number_matches":  result.DoorNumberMatches,
+				"door_number_detected": result.DoorNumberDetected,
+				"door_fully_visible":   result.DoorFullyVisible,
+			}).Debug("openai verification result")
+			status, reasonCodes = photoVerificationOutcome(result, missingTrashCan)
+		}
+	}
+
+	if err := s.recordUnitVerification(ctx, instanceID, unitID, status, reasonCodes, missingTrashCan); err != nil {
+		return nil, err
+	}
+
+	dto, _ := s.buildInstanceDTO(ctx, inst, nil, nil, nil, nil, nil, nil, nil)
+	return dto, nil
+}
+
+// photoVerificationOutcome turns OpenAI's findings into a unit status and the
+// reason codes shown to the worker.
+func photoVerificationOutcome(result *VerificationResult, missingTrashCan bool) (models.UnitVerificationStatus, []string) {
+	var reasonCodes []string
+	pass := false
+	if missingTrashCan {
+		pass = result.DoorNumberMatches && result.DoorFullyVisible
+		if !result.DoorNumberMatches {
+			if result.DoorNumberDetected != "" {
+				reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISMATCH")
+			} else {
+				reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISSING")
 			}
-			if !result.DoorFullyVisible {
-				reasonCodes = append(reasonCodes, "DOOR_NOT_FULLY_VISIBLE")
+		}
+		if !result.DoorFullyVisible {
+			reasonCodes = append(reasonCodes, "DOOR_NOT_FULLY_VISIBLE")
+		}
+	} else {
+		pass = result.TrashCanPresent && result.NoTrashBagVisible && result.DoorNumberMatches && result.DoorFullyVisible
+		if !result.TrashCanPresent {
+			reasonCodes = append(reasonCodes, "TRASH_CAN_NOT_VISIBLE")
+		}
+		if !result.NoTrashBagVisible {
+			reasonCodes = append(reasonCodes, "TRASH_BAG_VISIBLE")
+		}
+		if !result.DoorNumberMatches {
+			if result.DoorNumberDetected != "" {
+				reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISMATCH")
+			} else {
+				reasonCodes = append(reasonCodes, "DOOR_NUMBER_MISSING")
 			}
 		}
-		if !pass {
-			status = models.UnitVerificationFailed
+		if !result.DoorFullyVisible {
+			reasonCodes = append(reasonCodes, "DOOR_NOT_FULLY_VISIBLE")
 		}
 	}
+	if !pass {
+		return models.UnitVerificationFailed, reasonCodes
+	}
+	return models.UnitVerificationVerified, reasonCodes
+}
 
+// recordUnitVerification stores a unit's verification result. Three failed
+// photos fail the unit permanently; a PENDING result leaves the attempt count
+// alone until the queued check decides it.
+func (s *JobService) recordUnitVerification(
+	ctx context.Context,
+	instanceID, unitID uuid.UUID,
+	status models.UnitVerificationStatus,
+	reasonCodes []string,
+	missingTrashCan bool,
+) error {
 	v, err := s.juvRepo.GetByInstanceAndUnit(ctx, instanceID, unitID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if v != nil && v.PermanentFailure {
-		dto, _ := s.buildInstanceDTO(ctx, inst, nil, nil, nil, nil, nil, nil, nil)
-		return dto, nil
+		return nil
 	}
 	if v == nil {
 		v = &models.JobUnitVerification{
@@ -400,14 +425,15 @@ func (s *JobService) VerifyUnitPhoto(
 		}
 	}
 
-	if status == models.UnitVerificationFailed {
+	switch status {
+	case models.UnitVerificationFailed:
 		v.AttemptCount++
 		v.FailureReasons = reasonCodes
 		v.FailureReasonHistory = append(v.FailureReasonHistory, reasonCodes...)
 		if v.AttemptCount >= 3 {
 			v.PermanentFailure = true
 		}
-	} else {
+	case models.UnitVerificationVerified:
 		v.AttemptCount = 0
 		// Use an empty slice to satisfy the NOT NULL constraint on
 		// job_unit_verifications.failure_reasons. Using nil would
@@ -421,17 +447,10 @@ func (s *JobService) VerifyUnitPhoto(
 	v.MissingTrashCan = missingTrashCan
 
 	if v.RowVersion == 0 {
-		if err := s.juvRepo.Create(ctx, v); err != nil {
-			return nil, err
-		}
-	} else {
-		if _, err := s.juvRepo.UpdateIfVersion(ctx, v, v.RowVersion); err != nil {
-			return nil, err
-		}
+		return s.juvRepo.Create(ctx, v)
 	}
-
-	dto, _ := s.buildInstanceDTO(ctx, inst, nil, nil, nil, nil, nil, nil, nil)
-	return dto, nil
+	_, err = s.juvRepo.UpdateIfVersion(ctx, v, v.RowVersion)
+	return err
 }
 
 // ProcessDumpTrip marks verified units as dumped and completes the job if all are dumped.
@@ -487,7 +506,7 @@ func (s *JobService) ProcessDumpTrip(
 	
 	// Count the total number of units assigned to this job definition.
 	totalUnits := 0
-	for _, grp := range defn.AssignedUnitsByBuilding {
+	for _, grp := range defn.UnitGroupsForSegment(inst.SegmentIndex) {
 		totalUnits += len(grp.UnitIDs)
 	}
 	
@@ -522,45 +541,72 @@ func (s *JobService) ProcessDumpTrip(
 	}
 	// --- END: Enhanced Validation Logic ---
 
-	for _, v := range verifs {
-		if v.Status == models.UnitVerificationVerified {
-			v.Status = models.UnitVerificationDumped
-			_, _ = s.juvRepo.UpdateIfVersion(ctx, v, v.RowVersion)
+	// Dumping the bags and completing the job commit together, so a failed
+	// completion doesn't leave units DUMPED on an IN_PROGRESS job.
+	// The unit may run more than once, so it dumps copies and leaves verifs
+	// as read.
+	var updated *models.JobInstance
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		updated = nil
+		juvRepo := w.JobUnitVerifications()
+		for _, v := range verifs {
+			if v.Status == models.UnitVerificationVerified {
+				dumped := *v
+				dumped.Status = models.UnitVerificationDumped
+				tag, err := juvRepo.UpdateIfVersion(ctx, &dumped, v.RowVersion)
+				if err != nil {
+					return err
+				}
+				if tag.RowsAffected() == 0 {
+					return utils.ErrRowVersionConflict
+				}
+			}
 		}
-	}
 
-	// check if all units are in a final state (dumped or permanently failed)
-	total := 0
-	completedUnits := 0
-	for _, grp := range defn.AssignedUnitsByBuilding {
-		total += len(grp.UnitIDs)
-	}
-	// Re-fetch verifications to get the latest status after updates
-	verifsAfterUpdate, _ := s.juvRepo.ListByInstanceID(ctx, inst.ID)
-	for _, v := range verifsAfterUpdate {
-		if v.Status == models.UnitVerificationDumped || (v.Status == models.UnitVerificationFailed && v.PermanentFailure) {
-			completedUnits++
+		// check if all units are in a final state (dumped or permanently failed)
+		total := 0
+		completedUnits := 0
+		for _, grp := range defn.UnitGroupsForSegment(inst.SegmentIndex) {
+			total += len(grp.UnitIDs)
 		}
-	}
-
-	var updated *models.JobInstance
-	if completedUnits >= total {
-		rv := inst.RowVersion
-		updated, err = s.instRepo.UpdateStatusToCompleted(ctx, inst.ID, rv)
+		// Re-fetch verifications to get the latest status after updates
+		verifsAfterUpdate, err := juvRepo.ListByInstanceID(ctx, inst.ID)
 		if err != nil {
-			if strings.Contains(err.Error(), utils.ErrRowVersionConflict.Error()) {
-				latest, _ := s.instRepo.GetByID(ctx, inst.ID)
-				if latest != nil {
-					return nil, internal_utils.NewRowVersionConflictError(latest)
-				}
+			return err
+		}
+		for _, v := range verifsAfterUpdate {
+			if v.Status == models.UnitVerificationDumped || (v.Status == models.UnitVerificationFailed && v.PermanentFailure) {
+				completedUnits++
 			}
-			return nil, err
 		}
-		if updated != nil && updated.CheckInAt != nil && updated.CheckOutAt != nil {
-			timeSpent := max(updated.CheckOutAt.Sub(*updated.CheckInAt).Minutes(), 1)
-			actualMins := int(math.Round(timeSpent))
-			_ = s.applyCompletionTimeEma(ctx, defn.ID, updated.ServiceDate.Weekday(), actualMins)
+		if completedUnits < total {
+			return nil
 		}
+		updated, err = w.JobInstances().UpdateStatusToCompleted(ctx, inst.ID, inst.RowVersion)
+		if err != nil || updated == nil {
+			return err
+		}
+		return s.scoreCompletion(ctx, w, defn, prop, updated, verifsAfterUpdate)
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), utils.ErrRowVersionConflict.Error()) {
+			latest, _ := s.instRepo.GetByID(ctx, inst.ID)
+			if latest != nil {
+				return nil, internal_utils.NewRowVersionConflictError(latest)
+			}
+		}
+		return nil, err
+	}
+
+	if updated != nil && updated.CheckInAt != nil && updated.CheckOutAt != nil {
+		timeSpent := max(updated.CheckOutAt.Sub(*updated.CheckInAt).Minutes(), 1)
+		// A segment only covers part of the property, so scale its time up
+		// to a whole-job equivalent before feeding the definition's EMA.
+		if share := defn.SegmentPayShare(updated.SegmentIndex); share > 0 && share < 1 {
+			timeSpent = timeSpent / share
+		}
+		actualMins := int(math.Round(timeSpent))
+		s.queueCompletionTimeEma(ctx, updated.ID, defn.ID, updated.ServiceDate.Weekday(), actualMins)
 	}
 
 	if updated == nil {
@@ -616,9 +662,7 @@ func (s *JobService) UnacceptJobInstance(
 
 			if now.After(acceptanceCutoffTime) {
 				// Re-open the job but penalize and exclude the worker
-				assignCount := inst.AssignUnassignCount + 1
-				flagged := inst.FlaggedForReview || (assignCount > constants.MaxAssignUnassignCountForFlag)
-				updatedInst, err2 := s.instRepo.UnassignInstanceAtomic(ctx, instanceID, inst.RowVersion, assignCount, flagged)
+				updatedInst, err2 := s.unassignWorker(ctx, inst, wUUID, true, constants.WorkerPenaltyNoShow, models.ScoreEventUnacceptLateCancel)
 				if err2 != nil {
 					if strings.Contains(err2.Error(), utils.ErrRowVersionConflict.Error()) {
 						latest, _ := s.instRepo.GetByID(ctx, instanceID)
@@ -628,20 +672,12 @@ func (s *JobService) UnacceptJobInstance(
 					}
 					return nil, err2
 				}
-				if updatedInst == nil {
-					return nil, utils.ErrNoRowsUpdated
-				}
-
-				if s.workerRepo != nil {
-					_ = s.instRepo.AddExcludedWorker(ctx, updatedInst.ID, wUUID)
-					_ = s.workerRepo.AdjustWorkerScoreAtomic(ctx, wUUID, constants.WorkerPenaltyNoShow, "UNACCEPT_LATE_CANCEL")
-				}
 
 				// MODIFIED: More descriptive message body.
 				messageBody := fmt.Sprintf("The assigned worker un-assigned from this job at %s after the acceptance cutoff time. The job has been reopened and may need urgent coverage.", prop.PropertyName)
 				NotifyOnCallAgents(
 					ctx, s.cfg.AppUrl, prop, defn, inst, "[Escalation] Worker Unassigned Late", messageBody,
-					s.agentRepo, s.agentJobCompletionRepo, s.bldgRepo, s.unitRepo, s.twilioClient, s.sendgridClient,
+					s.agentRepo, s.agentJobCompletionRepo, s.bldgRepo, s.unitRepo, s.notifier,
 					s.cfg,
 				)
 
@@ -653,13 +689,7 @@ func (s *JobService) UnacceptJobInstance(
 		}
 	}
 
-	assignCount := inst.AssignUnassignCount + 1
-	flagged := inst.FlaggedForReview
-	if assignCount > constants.MaxAssignUnassignCountForFlag {
-		flagged = true
-	}
-	expectedVersion := inst.RowVersion
-	updated, err2 := s.instRepo.UnassignInstanceAtomic(ctx, instanceID, expectedVersion, assignCount, flagged)
+	updated, err2 := s.unassignWorker(ctx, inst, wUUID, excludeWorker, penaltyDelta, models.ScoreEventUnaccept)
 	if err2 != nil {
 		if strings.Contains(err2.Error(), utils.ErrRowVersionConflict.Error()) {
 			latest, _ := s.instRepo.GetByID(ctx, instanceID)
@@ -669,17 +699,49 @@ func (s *JobService) UnacceptJobInstance(
 		}
 		return nil, err2
 	}
-	if updated == nil {
-		return nil, utils.ErrNoRowsUpdated
-	}
-
-	if excludeWorker {
-		_ = s.instRepo.AddExcludedWorker(ctx, updated.ID, wUUID)
-	}
-	if penaltyDelta != 0 && s.workerRepo != nil {
-		_ = s.workerRepo.AdjustWorkerScoreAtomic(ctx, wUUID, penaltyDelta, "UNACCEPT")
-	}
 
 	dto, _ := s.buildInstanceDTO(ctx, updated, nil, nil, nil, nil, nil, nil, nil)
 	return dto, nil
 }
+
+// unassignWorker reopens an ASSIGNED instance and applies the worker's
+// exclusion and score penalty in the same transaction, so the job can't be
+// reopened without the penalty or the worker penalized for a job they still
+// hold.
+func (s *JobService) unassignWorker(
+	ctx context.Context,
+	inst *models.JobInstance,
+	workerID uuid.UUID,
+	exclude bool,
+	penaltyDelta int,
+	penaltyEvent string,
+) (*models.JobInstance, error) {
+	assignCount := inst.AssignUnassignCount + 1
+	flagged := inst.FlaggedForReview || assignCount > constants.MaxAssignUnassignCountForFlag
+
+	var reopened *models.JobInstance
+	err := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		instRepo := w.JobInstances()
+		var err error
+		reopened, err = instRepo.UnassignInstanceAtomic(ctx, inst.ID, inst.RowVersion, assignCount, flagged)
+		if err != nil {
+			return err
+		}
+		if reopened == nil {
+			return utils.ErrNoRowsUpdated
+		}
+		if exclude {
+			if err := instRepo.AddExcludedWorker(ctx, inst.ID, workerID); err != nil {
+				return err
+			}
+		}
+		if penaltyDelta != 0 {
+			return s.scoreJobEvent(ctx, w, workerID, inst.ID, penaltyEvent, penaltyDelta)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reopened, nil
+}
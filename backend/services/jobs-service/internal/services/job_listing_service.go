@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
@@ -23,13 +24,6 @@ func (s *JobService) ListOpenJobs(
 	startUTC := dateOnlyInLocation(nowLocal.AddDate(0, 0, -1), workerLoc)
 	endUTC := startUTC.AddDate(0, 0, constants.DaysToListOpenJobsRange)
 
-	statuses := []models.InstanceStatusType{models.InstanceStatusOpen}
-	// The repo query is inclusive on both start and end, so this covers the full 9 days.
-	instances, err := s.instRepo.ListInstancesByDateRange(ctx, nil, statuses, startUTC, endUTC)
-	if err != nil {
-		return nil, err
-	}
-
 	var isReviewer bool
 	wID, parseErr := uuid.Parse(userID)
 	if parseErr == nil {
@@ -50,6 +44,24 @@ func (s *JobService) ListOpenJobs(
 		}
 	}
 
+	// One geohash-indexed query returns candidates already joined with their
+	// definitions and properties. Reviewers see demo properties at any distance.
+	nearby := repositories.NearbyInstanceQuery{
+		Lat:            q.Lat,
+		Lng:            q.Lng,
+		Statuses:       []models.InstanceStatusType{models.InstanceStatusOpen},
+		StartDate:      startUTC,
+		EndDate:        endUTC, // inclusive, so this covers the full 9 days
+		DemoProperties: &isReviewer,
+	}
+	if !isReviewer && (q.Lat != 0 || q.Lng != 0) {
+		nearby.RadiusMiles = utils.Ptr(float64(constants.RadiusMiles))
+	}
+	candidates, err := s.instRepo.ListInstancesNear(ctx, nearby)
+	if err != nil {
+		return nil, err
+	}
+
 	// --- Start of Optimization ---
 
 	// 1. Group instances by property ID after filtering.
@@ -57,40 +69,13 @@ func (s *JobService) ListOpenJobs(
 	propDefs := make(map[uuid.UUID]*models.JobDefinition)
 	propsCache := make(map[uuid.UUID]*models.Property)
 
-	for _, inst := range instances {
-		if parseErr == nil && ContainsUUID(inst.ExcludedWorkerIDs, wID) {
+	for _, c := range candidates {
+		inst, defn, prop := c.Instance, c.Definition, c.Property
+		if !visibleToWorker(inst, prop, wID, isReviewer) {
 			continue
 		}
-
-		defn, ok := propDefs[inst.DefinitionID]
-		if !ok {
-			var dErr error
-			defn, dErr = s.defRepo.GetByID(ctx, inst.DefinitionID)
-			if dErr != nil || defn == nil {
-				continue
-			}
-			propDefs[inst.DefinitionID] = defn
-		}
-
-		prop, ok := propsCache[defn.PropertyID]
-		if !ok {
-			var pErr error
-			prop, pErr = s.propRepo.GetByID(ctx, defn.PropertyID)
-			if pErr != nil || prop == nil {
-				continue
-			}
-			propsCache[defn.PropertyID] = prop
-		}
-
-		if isReviewer {
-			if !prop.IsDemo {
-				continue // Reviewer sees only demo properties
-			}
-		} else {
-			if prop.IsDemo {
-				continue // Regular user doesn't see demo properties
-			}
-		}
+		propDefs[defn.ID] = defn
+		propsCache[prop.ID] = prop
 
 		propLoc := loadPropertyLocation(prop.TimeZone)
 		propNow := time.Now().In(propLoc)
@@ -112,6 +97,15 @@ func (s *JobService) ListOpenJobs(
 
 	// 2. Precompute routes and preload property data once per property.
 	routeCache := make(map[uuid.UUID]*routeInfo)
+	if q.Lat != 0 || q.Lng != 0 {
+		routeProps := make([]*models.Property, 0, len(instancesByPropID))
+		for propID := range instancesByPropID {
+			if prop := propsCache[propID]; prop != nil {
+				routeProps = append(routeProps, prop)
+			}
+		}
+		routeCache = s.computeRoutes(ctx, q.Lat, q.Lng, routeProps)
+	}
 	bldgCache := make(map[uuid.UUID]map[uuid.UUID]*models.PropertyBuilding)
 	unitCache := make(map[uuid.UUID]map[uuid.UUID][]*models.Unit)
 	dumpCache := make(map[uuid.UUID][]*models.Dumpster)
@@ -121,23 +115,6 @@ func (s *JobService) ListOpenJobs(
 			continue
 		}
 
-		if q.Lat != 0 || q.Lng != 0 {
-			var distMiles float64
-			var travelMins *int
-			if s.cfg.LDFlag_UseGMapsRoutesAPI && s.cfg.GMapsRoutesAPIKey != "" {
-				dMiles, dMins, gErr := utils.ComputeDriveDistanceTimeMiles(q.Lat, q.Lng, prop.Latitude, prop.Longitude, s.cfg.GMapsRoutesAPIKey)
-				if gErr == nil {
-					distMiles = dMiles
-					travelMins = &dMins
-				}
-			}
-			if travelMins == nil {
-				distMiles = utils.DistanceMiles(q.Lat, q.Lng, prop.Latitude, prop.Longitude)
-				estMins := int(distMiles * utils.CrowFliesDriveTimeMultiplier)
-				travelMins = &estMins
-			}
-			routeCache[propID] = &routeInfo{DistanceMiles: distMiles, TravelMinutes: travelMins}
-		}
 
 		bldgs, _ := s.bldgRepo.ListByPropertyID(ctx, propID)
 		bMap := make(map[uuid.UUID]*models.PropertyBuilding, len(bldgs))
@@ -270,6 +247,15 @@ func (s *JobService) ListMyJobs(
 	}
 
 	routeCache := make(map[uuid.UUID]*routeInfo)
+	if q.Lat != 0 || q.Lng != 0 {
+		routeProps := make([]*models.Property, 0, len(instancesByPropID))
+		for propID := range instancesByPropID {
+			if prop := propsCache[propID]; prop != nil {
+				routeProps = append(routeProps, prop)
+			}
+		}
+		routeCache = s.computeRoutes(ctx, q.Lat, q.Lng, routeProps)
+	}
 	bldgCache := make(map[uuid.UUID]map[uuid.UUID]*models.PropertyBuilding)
 	unitCache := make(map[uuid.UUID]map[uuid.UUID][]*models.Unit)
 	dumpCache := make(map[uuid.UUID][]*models.Dumpster)
@@ -278,23 +264,6 @@ func (s *JobService) ListMyJobs(
 		if prop == nil {
 			continue
 		}
-		if q.Lat != 0 || q.Lng != 0 {
-			var distMiles float64
-			var travelMins *int
-			if s.cfg.LDFlag_UseGMapsRoutesAPI && s.cfg.GMapsRoutesAPIKey != "" {
-				dMiles, dMins, gErr := utils.ComputeDriveDistanceTimeMiles(q.Lat, q.Lng, prop.Latitude, prop.Longitude, s.cfg.GMapsRoutesAPIKey)
-				if gErr == nil {
-					distMiles = dMiles
-					travelMins = &dMins
-				}
-			}
-			if travelMins == nil {
-				distMiles = utils.DistanceMiles(q.Lat, q.Lng, prop.Latitude, prop.Longitude)
-				estMins := int(distMiles * utils.CrowFliesDriveTimeMultiplier)
-				travelMins = &estMins
-			}
-			routeCache[propID] = &routeInfo{DistanceMiles: distMiles, TravelMinutes: travelMins}
-		}
 
 		bldgs, _ := s.bldgRepo.ListByPropertyID(ctx, propID)
 		bMap := make(map[uuid.UUID]*models.PropertyBuilding, len(bldgs))
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*──────────────────────────────────────────────────────────────────────────
+  Lottery allocation
+
+  Properties in LOTTERY mode, or following a market ops set to LOTTERY,
+  do not hand a newly released job to the first accept. Accepts arriving
+  before the entry window closes (batch release + LotteryWindowSeconds)
+  are recorded as entries; once the window closes a draw picks one winner
+  with probability proportional to its weight and every other entrant is
+  told they lost. Each draw stores its seed, roll and weight snapshot so the
+  outcome can be replayed.
+
+  Distance is measured from the worker's geocoded home address, never from
+  the location sent with the accept: that only has to fall inside the
+  acceptance radius, and claiming to stand at the door would buy odds.
+──────────────────────────────────────────────────────────────────────────*/
+
+// lotteryWeight favours reliable, nearby workers. Score is squared so the gap
+// between 60 and 95 matters more than distance within a few miles.
+func lotteryWeight(score int, distMiles float64) float64 {
+	if score < 1 {
+		score = 1
+	}
+	if score > 100 {
+		score = 100
+	}
+	s := float64(score) / 100
+	return s * s / (1 + distMiles/constants.LotteryDistanceScaleMiles)
+}
+
+// pickWeighted returns the index whose cumulative weight range contains
+// u*total, or -1 when nothing has positive weight.
+func pickWeighted(weights []float64, u float64) (idx int, roll float64, total float64) {
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1, 0, 0
+	}
+	roll = u * total
+	acc := 0.0
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		acc += w
+		last = i
+		if roll < acc {
+			return i, roll, total
+		}
+	}
+	return last, roll, total
+}
+
+// drawWinner makes the draw for seed. The same seed and weights always pick
+// the same entry, so a stored draw can be replayed.
+func drawWinner(seed int64, weights []float64) (idx int, roll float64, total float64) {
+	rng := rand.New(rand.NewSource(seed))
+	return pickWeighted(weights, rng.Float64())
+}
+
+func newLotterySeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]) >> 1)
+}
+
+// lotteryDistanceMiles is how far from prop a worker's entry counts as.
+// Workers without a geocoded home count as at the edge of the radius.
+func lotteryDistanceMiles(worker *models.Worker, prop *models.Property) float64 {
+	if worker.HomeLatitude == nil || worker.HomeLongitude == nil {
+		return float64(constants.RadiusMiles)
+	}
+	return utils.DistanceMiles(*worker.HomeLatitude, *worker.HomeLongitude, prop.Latitude, prop.Longitude)
+}
+
+// effectiveAllocation is how prop's released jobs are handed out and how
+// long a lottery collects entries. A property with its own mode uses it,
+// so a FIRST_COME property opts out of a lottery market; a property with
+// no mode follows its market, and first-come is the default.
+func effectiveAllocation(prop *models.Property, market *models.MarketAllocation) (models.AllocationModeType, time.Duration) {
+	if prop.AllocationMode != nil {
+		if *prop.AllocationMode == models.AllocationModeLottery {
+			return models.AllocationModeLottery, time.Duration(prop.LotteryWindowSeconds) * time.Second
+		}
+		return models.AllocationModeFirstCome, 0
+	}
+	if market != nil && market.AllocationMode == models.AllocationModeLottery {
+		return models.AllocationModeLottery, time.Duration(market.LotteryWindowSeconds) * time.Second
+	}
+	return models.AllocationModeFirstCome, 0
+}
+
+// lotteryWindowClosesAt returns when lottery entries stop being collected for
+// inst, or the zero time when prop allocates first-come.
+func (s *JobService) lotteryWindowClosesAt(ctx context.Context, inst *models.JobInstance, prop *models.Property) (time.Time, error) {
+	var market *models.MarketAllocation
+	if key := propertyMarket(prop); key != "" && prop.AllocationMode == nil {
+		var err error
+		if market, err = s.lotteryRepo.GetMarketAllocation(ctx, key); err != nil {
+			return time.Time{}, err
+		}
+	}
+	mode, window := effectiveAllocation(prop, market)
+	if mode != models.AllocationModeLottery {
+		return time.Time{}, nil
+	}
+	propLoc := loadPropertyLocation(prop.TimeZone)
+	base := dateOnlyInLocation(time.Now().In(propLoc), propLoc)
+	b := s.computeReleaseBreakdown(inst.ServiceDate, base, 0, nil, prop.ID)
+	return b.BaseRelease.Add(window), nil
+}
+
+// maybeEnterLottery records an accept as a lottery entry when the job is
+// still in (or awaiting) its draw. It returns nil when the accept should
+// proceed first-come.
+func (s *JobService) maybeEnterLottery(
+	ctx context.Context,
+	inst *models.JobInstance,
+	prop *models.Property,
+	worker *models.Worker,
+) error {
+	closesAt, err := s.lotteryWindowClosesAt(ctx, inst, prop)
+	if err != nil {
+		return err
+	}
+	if closesAt.IsZero() {
+		return nil
+	}
+	if !time.Now().Before(closesAt) {
+		pending, err := s.lotteryRepo.HasPendingEntries(ctx, inst.ID)
+		if err != nil {
+			return err
+		}
+		if !pending {
+			return nil
+		}
+	}
+
+	distMiles := lotteryDistanceMiles(worker, prop)
+	entry, err := s.lotteryRepo.CreateEntry(ctx, &models.JobLotteryEntry{
+		ID:               uuid.New(),
+		InstanceID:       inst.ID,
+		WorkerID:         worker.ID,
+		ReliabilityScore: worker.ReliabilityScore,
+		DistanceMiles:    distMiles,
+		Weight:           lotteryWeight(worker.ReliabilityScore, distMiles),
+		WindowClosesAt:   closesAt,
+	})
+	if err != nil {
+		return err
+	}
+	return internal_utils.NewLotteryPendingError(entry)
+}
+
+// RunLotteryDraws resolves every lottery whose entry window has closed.
+func (s *JobService) RunLotteryDraws(ctx context.Context) error {
+	ids, err := s.lotteryRepo.ListDueInstanceIDs(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(ids) > constants.LotteryDrawBatchSize {
+		ids = ids[:constants.LotteryDrawBatchSize]
+	}
+	for _, id := range ids {
+		if dErr := s.drawLottery(ctx, id); dErr != nil {
+			utils.Logger.WithError(dErr).WithField("instance_id", id).Error("Lottery draw failed")
+		}
+	}
+	return nil
+}
+
+func (s *JobService) drawLottery(ctx context.Context, instanceID uuid.UUID) error {
+	entries, err := s.lotteryRepo.ListPendingEntries(ctx, instanceID)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	inst, err := s.instRepo.GetByID(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if inst == nil {
+		return fmt.Errorf("instance %s not found", instanceID)
+	}
+
+	draw := &models.JobLotteryDraw{
+		ID:         uuid.New(),
+		InstanceID: instanceID,
+		Seed:       newLotterySeed(),
+		DrawnAt:    time.Now().UTC(),
+	}
+	lossReasons := make(map[uuid.UUID]string, len(entries))
+	weights := make([]float64, len(entries))
+	for i, e := range entries {
+		eligible := !ContainsUUID(inst.ExcludedWorkerIDs, e.WorkerID)
+		if eligible {
+			weights[i] = e.Weight
+		} else {
+			lossReasons[e.ID] = models.LotteryLossNotEligible
+		}
+		draw.Entries = append(draw.Entries, models.LotteryDrawEntry{
+			EntryID:          e.ID,
+			WorkerID:         e.WorkerID,
+			ReliabilityScore: e.ReliabilityScore,
+			DistanceMiles:    e.DistanceMiles,
+			Weight:           e.Weight,
+			Eligible:         eligible,
+		})
+	}
+
+	// The job left OPEN outside the lottery (or a previous draw assigned it
+	// but failed to record): honour an existing assignment, everyone else loses.
+	if inst.Status != models.InstanceStatusOpen {
+		var winnerEntryID *uuid.UUID
+		for _, e := range entries {
+			if inst.AssignedWorkerID != nil && *inst.AssignedWorkerID == e.WorkerID {
+				winnerEntryID = &e.ID
+				draw.WinnerWorkerID = &e.WorkerID
+				delete(lossReasons, e.ID)
+				continue
+			}
+			if _, ok := lossReasons[e.ID]; !ok {
+				lossReasons[e.ID] = models.LotteryLossJobNotOpen
+			}
+		}
+		return s.lotteryRepo.RecordDraw(ctx, draw, winnerEntryID, lossReasons)
+	}
+
+	idx, roll, total := drawWinner(draw.Seed, weights)
+	draw.Roll, draw.TotalWeight = roll, total
+	if idx < 0 {
+		return s.lotteryRepo.RecordDraw(ctx, draw, nil, lossReasons)
+	}
+
+	winner := entries[idx]
+	newAssignCount := inst.AssignUnassignCount + 1
+	flagged := inst.FlaggedForReview || newAssignCount > constants.MaxAssignUnassignCountForFlag
+	if _, aErr := s.instRepo.AcceptInstanceAtomic(ctx, inst.ID, winner.WorkerID, inst.RowVersion, newAssignCount, flagged); aErr != nil {
+		if strings.Contains(aErr.Error(), utils.ErrRowVersionConflict.Error()) {
+			return aErr // instance changed underneath us; retry on the next tick
+		}
+		return fmt.Errorf("assign lottery winner: %w", aErr)
+	}
+	draw.WinnerWorkerID = &winner.WorkerID
+	return s.lotteryRepo.RecordDraw(ctx, draw, &winner.ID, lossReasons)
+}
+
+// GetLotteryEntry reports the caller's lottery entry for an instance,
+// including their share of the total weight once drawn.
+func (s *JobService) GetLotteryEntry(
+	ctx context.Context,
+	workerID string,
+	instanceID uuid.UUID,
+) (*dtos.LotteryEntryDTO, error) {
+	wUUID, err := uuid.Parse(workerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker ID format: %w", err)
+	}
+	entry, err := s.lotteryRepo.GetEntry(ctx, instanceID, wUUID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, internal_utils.ErrLotteryEntryNotFound
+	}
+
+	out := dtos.NewLotteryEntryDTO(entry)
+	if entry.DrawID != nil {
+		draw, dErr := s.lotteryRepo.GetDraw(ctx, *entry.DrawID)
+		if dErr != nil {
+			return nil, dErr
+		}
+		if draw != nil {
+			n := len(draw.Entries)
+			out.EntrantCount = &n
+			out.TotalWeight = &draw.TotalWeight
+		}
+	}
+	return &out, nil
+}
+
+// ListMarketAllocations returns every market's allocation mode.
+func (s *JobService) ListMarketAllocations(ctx context.Context) ([]*models.MarketAllocation, error) {
+	return s.lotteryRepo.ListMarketAllocations(ctx)
+}
+
+// SetMarketAllocation sets how a market's properties hand out newly
+// released jobs. Properties with their own allocation mode are unaffected.
+func (s *JobService) SetMarketAllocation(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.MarketAllocationRequest,
+) (*models.MarketAllocation, error) {
+	market := strings.TrimSpace(req.Market)
+	if market == "" {
+		return nil, fmt.Errorf("%w: market is required", internal_utils.ErrInvalidPayload)
+	}
+	a := &models.MarketAllocation{
+		Market:               market,
+		AllocationMode:       req.AllocationMode,
+		LotteryWindowSeconds: req.LotteryWindowSeconds,
+		UpdatedBy:            &actorID,
+	}
+	if err := s.lotteryRepo.UpsertMarketAllocation(ctx, a); err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s set market %q to %s allocation (%ds window)", actorID, a.Market, a.AllocationMode, a.LotteryWindowSeconds)
+	return a, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func TestLotteryWeight(t *testing.T) {
+	for name, tc := range map[string]struct {
+		more, less [2]float64 // {score, miles}
+	}{
+		"higher score":            {more: [2]float64{95, 5}, less: [2]float64{60, 5}},
+		"closer":                  {more: [2]float64{80, 1}, less: [2]float64{80, 20}},
+		"score beats a few miles": {more: [2]float64{95, 4}, less: [2]float64{60, 0}},
+	} {
+		more := lotteryWeight(int(tc.more[0]), tc.more[1])
+		less := lotteryWeight(int(tc.less[0]), tc.less[1])
+		if more <= less {
+			t.Errorf("%s: expected %v to outweigh %v, got %f <= %f", name, tc.more, tc.less, more, less)
+		}
+	}
+	if got := lotteryWeight(-5, 0); got != lotteryWeight(1, 0) || got <= 0 {
+		t.Errorf("expected scores below 1 to count as 1, got %f", got)
+	}
+	if lotteryWeight(150, 0) != 1 {
+		t.Errorf("expected scores above 100 to count as 100")
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	for name, tc := range map[string]struct {
+		weights []float64
+		u       float64
+		want    int
+	}{
+		"start of the range":     {weights: []float64{1, 0, 3}, u: 0, want: 0},
+		"end of the first range": {weights: []float64{1, 0, 3}, u: 0.2499, want: 0},
+		"skips zero weights":     {weights: []float64{1, 0, 3}, u: 0.25, want: 2},
+		"last range":             {weights: []float64{1, 0, 3}, u: 0.9999, want: 2},
+		"ignores negatives":      {weights: []float64{-4, 2}, u: 0, want: 1},
+		"nothing to pick":        {weights: []float64{0, 0}, u: 0.5, want: -1},
+		"no entrants":            {u: 0.5, want: -1},
+	} {
+		if got, _, _ := pickWeighted(tc.weights, tc.u); got != tc.want {
+			t.Errorf("%s: expected index %d, got %d", name, tc.want, got)
+		}
+	}
+}
+
+func TestLotteryDrawHonoursWeights(t *testing.T) {
+	weights := []float64{
+		lotteryWeight(95, 2),
+		lotteryWeight(60, 2),
+		lotteryWeight(95, 25),
+		0, // not eligible
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+
+	const draws = 20000
+	wins := make([]int, len(weights))
+	for seed := int64(1); seed <= draws; seed++ {
+		idx, _, _ := drawWinner(seed, weights)
+		wins[idx]++
+	}
+	for i, w := range weights {
+		share := float64(wins[i]) / draws
+		if math.Abs(share-w/total) > 0.015 {
+			t.Errorf("entry %d: expected to win about %.3f of draws, won %.3f", i, w/total, share)
+		}
+	}
+	if wins[3] != 0 {
+		t.Errorf("an entry with no weight won %d draws", wins[3])
+	}
+}
+
+func TestSeededDrawIsReproducible(t *testing.T) {
+	weights := []float64{lotteryWeight(95, 2), lotteryWeight(60, 2), lotteryWeight(95, 25)}
+	winners := map[int]bool{}
+	for seed := int64(1); seed <= 100; seed++ {
+		idx, roll, total := drawWinner(seed, weights)
+		again, againRoll, againTotal := drawWinner(seed, append([]float64(nil), weights...))
+		if idx != again || roll != againRoll || total != againTotal {
+			t.Fatalf("seed %d: drew %d (roll %f of %f), then %d (roll %f of %f)", seed, idx, roll, total, again, againRoll, againTotal)
+		}
+		// The stored roll alone names the winner.
+		if replayed, _, _ := pickWeighted(weights, roll/total); replayed != idx {
+			t.Errorf("seed %d: roll %f replays to %d, drew %d", seed, roll, replayed, idx)
+		}
+		winners[idx] = true
+	}
+	if len(winners) < 2 {
+		t.Errorf("expected different seeds to pick different winners, got %v", winners)
+	}
+}
+
+func TestEffectiveAllocation(t *testing.T) {
+	lottery := func(secs int) *models.MarketAllocation {
+		return &models.MarketAllocation{Market: "atl", AllocationMode: models.AllocationModeLottery, LotteryWindowSeconds: secs}
+	}
+	firstCome := &models.MarketAllocation{Market: "atl", AllocationMode: models.AllocationModeFirstCome, LotteryWindowSeconds: 120}
+
+	firstComeProp, lotteryProp := models.AllocationModeFirstCome, models.AllocationModeLottery
+
+	for name, tc := range map[string]struct {
+		prop   *models.AllocationModeType
+		market *models.MarketAllocation
+		mode   models.AllocationModeType
+		window time.Duration
+	}{
+		"first come everywhere":          {mode: models.AllocationModeFirstCome},
+		"market lottery":                 {market: lottery(300), mode: models.AllocationModeLottery, window: 5 * time.Minute},
+		"market first come":              {market: firstCome, mode: models.AllocationModeFirstCome},
+		"property lottery":               {prop: &lotteryProp, mode: models.AllocationModeLottery, window: time.Minute},
+		"property window wins":           {prop: &lotteryProp, market: lottery(300), mode: models.AllocationModeLottery, window: time.Minute},
+		"property lottery in first come": {prop: &lotteryProp, market: firstCome, mode: models.AllocationModeLottery, window: time.Minute},
+		"property opts out of lottery":   {prop: &firstComeProp, market: lottery(300), mode: models.AllocationModeFirstCome},
+	} {
+		prop := &models.Property{AllocationMode: tc.prop, LotteryWindowSeconds: 60}
+		mode, window := effectiveAllocation(prop, tc.market)
+		if mode != tc.mode || window != tc.window {
+			t.Errorf("%s: expected %s with a %s window, got %s with %s", name, tc.mode, tc.window, mode, window)
+		}
+	}
+}
+
+func TestLotteryDistanceIgnoresClaimedLocation(t *testing.T) {
+	prop := &models.Property{Latitude: 36.16, Longitude: -86.78}
+	home := func(lat float64) *models.Worker {
+		lng := prop.Longitude
+		return &models.Worker{HomeLatitude: &lat, HomeLongitude: &lng}
+	}
+
+	near := lotteryDistanceMiles(home(prop.Latitude+2.0/69), prop)
+	far := lotteryDistanceMiles(home(prop.Latitude+30.0/69), prop)
+	if math.Abs(near-2) > 0.1 || math.Abs(far-30) > 0.5 {
+		t.Errorf("expected about 2 and 30 miles from home, got %.2f and %.2f", near, far)
+	}
+	// Without a geocoded home nothing vouches for the worker being close, so
+	// they count as at the edge of the radius.
+	if got := lotteryDistanceMiles(&models.Worker{}, prop); got != float64(constants.RadiusMiles) {
+		t.Errorf("expected a worker with no home to count as %d miles away, got %.2f", constants.RadiusMiles, got)
+	}
+}
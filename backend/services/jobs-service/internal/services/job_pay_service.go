@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*──────────────────────────────────────────────────────────────────────────
+  Pay ledger
+
+  A job's pay is the sum of the items in job_pay_items: the base pay it was
+  created with, then a SURGE item for each surge stage or escalation step
+  that raised it, a MANUAL_BONUS for each manual surge, and any ADJUSTMENT
+  or CLAWBACK ops make afterwards. Items are never edited. Corrections to a
+  completed job publish job.pay_changed so earnings-service can update an
+  unsent payout.
+──────────────────────────────────────────────────────────────────────────*/
+
+// payAdjustableStatuses are the statuses ops may correct pay in.
+var payAdjustableStatuses = []models.InstanceStatusType{
+	models.InstanceStatusOpen,
+	models.InstanceStatusAssigned,
+	models.InstanceStatusInProgress,
+	models.InstanceStatusCompleted,
+}
+
+func payItemDTO(it *models.JobPayItem, ops bool) dtos.PayItem {
+	dto := dtos.PayItem{
+		ID:            it.ID,
+		Kind:          it.Kind,
+		Label:         it.Kind.Label(),
+		Amount:        it.Amount,
+		Description:   it.Description,
+		SurgePolicyID: it.SurgePolicyID,
+		CreatedAt:     it.CreatedAt,
+	}
+	if ops {
+		dto.ActorID = it.ActorID
+		dto.Details = it.Details
+	}
+	return dto
+}
+
+// PayBreakdown returns a job's pay ledger. A worker (ops false) may only
+// read jobs assigned to them.
+func (s *JobService) PayBreakdown(
+	ctx context.Context,
+	callerID, instanceID uuid.UUID,
+	ops bool,
+) (*dtos.PayBreakdownResponse, error) {
+	inst, err := s.instRepo.GetByID(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if inst == nil {
+		return nil, internal_utils.ErrInstanceNotFound
+	}
+	if !ops && (inst.AssignedWorkerID == nil || *inst.AssignedWorkerID != callerID) {
+		return nil, internal_utils.ErrNotAssignedWorker
+	}
+
+	items, err := s.payItemRepo.ListByInstance(ctx, inst.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dtos.PayBreakdownResponse{
+		InstanceID: inst.ID,
+		Status:     inst.Status,
+		Total:      models.SumPayItems(items),
+		Items:      make([]dtos.PayItem, 0, len(items)),
+	}
+	for _, it := range items {
+		resp.Items = append(resp.Items, payItemDTO(it, ops))
+	}
+	if resp.Total != inst.EffectivePay {
+		utils.Logger.Errorf("Pay ledger for job %s adds up to %s but effective pay is %s", inst.ID, resp.Total, inst.EffectivePay)
+	}
+	return resp, nil
+}
+
+// AddPayItem records an ops adjustment or clawback on a job.
+func (s *JobService) AddPayItem(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.PayItemRequest,
+) (*dtos.PayItem, error) {
+	item := &models.JobPayItem{
+		JobInstanceID: req.InstanceID,
+		Kind:          req.Kind,
+		Amount:        models.MoneyFromDollars(req.Amount),
+		Description:   req.Reason,
+		ActorID:       &actorID,
+	}
+	if item.Amount.IsZero() || !item.Kind.Valid(item.Amount) {
+		return nil, fmt.Errorf("%w: %s amount must be non-zero and a clawback negative", internal_utils.ErrInvalidPayload, req.Kind)
+	}
+
+	inst, err := s.instRepo.AddPayItemAtomic(ctx, item, 0, payAdjustableStatuses)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, internal_utils.ErrInstanceNotFound
+	case errors.Is(err, utils.ErrPayItemNotAllowed):
+		return nil, internal_utils.ErrWrongStatus
+	case err != nil:
+		return nil, err
+	}
+	utils.Logger.Infof("Ops %s added %s of %s to job %s; pay is now %s", actorID, item.Kind, item.Amount, inst.ID, inst.EffectivePay)
+	dto := payItemDTO(item, true)
+	return &dto, nil
+}
+
+// PaySummary totals completed jobs' pay by item kind over service dates.
+func (s *JobService) PaySummary(ctx context.Context, start, end time.Time) (*dtos.PaySummaryResponse, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("%w: end is before start", internal_utils.ErrInvalidPayload)
+	}
+	kinds, err := s.payItemRepo.SummarizeByKind(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dtos.PaySummaryResponse{
+		Start:      start.Format("2006-01-02"),
+		End:        end.Format("2006-01-02"),
+		Total:      models.USD(0),
+		SurgeSpend: models.USD(0),
+		Kinds:      kinds,
+	}
+	if resp.Kinds == nil {
+		resp.Kinds = []models.PayKindTotal{}
+	}
+	for _, k := range kinds {
+		resp.Total = resp.Total.Add(k.Amount)
+		if k.Kind == models.PayItemSurge || k.Kind == models.PayItemManualBonus {
+			resp.SurgeSpend = resp.SurgeSpend.Add(k.Amount)
+		}
+	}
+	return resp, nil
+}
@@ -1,26 +1,61 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
 )
 
-func (s *JobService) computeEffectiveReleaseTimeForBatch(
-	baseTimeForRelease time.Time,
+// releaseBreakdown explains when a service date becomes visible to a worker.
+// Dates already inside the open window are visible regardless of the rest.
+type releaseBreakdown struct {
+	InOpenWindow    bool
+	BaseRelease     time.Time // batch release before worker adjustments
+	ScoreDelay      time.Duration
+	TenantAdvantage time.Duration
+	ReleaseAt       time.Time
+}
+
+func (b releaseBreakdown) releasedAt(now time.Time) bool {
+	return b.InOpenWindow || !now.Before(b.ReleaseAt)
+}
+
+func (s *JobService) computeReleaseBreakdown(
+	serviceDate time.Time,
+	baseMidnightForProp time.Time,
 	workerScore int,
 	workerTenantPropertyID *uuid.UUID,
 	jobPropertyID uuid.UUID,
-) time.Time {
-	effectiveBase := baseTimeForRelease
+) releaseBreakdown {
+	standardReleaseDate := baseMidnightForProp.AddDate(0, 0, constants.DaysToListOpenJobsRange-2)
+
+	instY, instM, instD := serviceDate.Date()
+	stdY, stdM, stdD := standardReleaseDate.Date()
+
+	isBefore := instY < stdY ||
+		(instY == stdY && instM < stdM) ||
+		(instY == stdY && instM == stdM && instD < stdD)
+
+	durationHours := serviceDate.Truncate(24 * time.Hour).Sub(baseMidnightForProp.Truncate(24 * time.Hour)).Hours()
+	daysAhead := int(math.Round(durationHours / 24.0))
+
+	b := releaseBreakdown{
+		InOpenWindow: isBefore,
+		BaseRelease:  baseMidnightForProp.AddDate(0, 0, daysAhead-(constants.DaysToListOpenJobsRange-2)),
+		ScoreDelay:   ComputeShadowDelay(workerScore),
+	}
 	if workerTenantPropertyID != nil && *workerTenantPropertyID == jobPropertyID {
-		effectiveBase = effectiveBase.Add(-1 * time.Hour)
+		b.TenantAdvantage = constants.TenantReleaseAdvantage
 	}
-	delay := ComputeShadowDelay(workerScore)
-	return effectiveBase.Add(delay)
+	b.ReleaseAt = b.BaseRelease.Add(-b.TenantAdvantage).Add(b.ScoreDelay)
+	return b
 }
 
 func ComputeShadowDelay(score int) time.Duration {
@@ -30,7 +65,7 @@ func ComputeShadowDelay(score int) time.Duration {
 	if score > 100 {
 		score = 100
 	}
-	delayMinutes := 120 * (100 - score) / 100
+	delayMinutes := int(constants.MaxShadowReleaseDelay/time.Minute) * (100 - score) / 100
 	return time.Duration(delayMinutes) * time.Minute
 }
 
@@ -42,25 +77,101 @@ func (s *JobService) isJobReleasedToWorker(
 	workerScore int,
 	workerTenantPropertyID *uuid.UUID,
 ) bool {
-	standardReleaseDate := baseMidnightForProp.AddDate(0, 0, constants.DaysToListOpenJobsRange-2)
-
-	instY, instM, instD := inst.ServiceDate.Date()
-	stdY, stdM, stdD := standardReleaseDate.Date()
+	b := s.computeReleaseBreakdown(inst.ServiceDate, baseMidnightForProp, workerScore, workerTenantPropertyID, prop.ID)
+	return b.releasedAt(propNow)
+}
 
-	isBefore := instY < stdY ||
-		(instY == stdY && instM < stdM) ||
-		(instY == stdY && instM == stdM && instD < stdD)
+/*──────────────────────────────────────────────────────────────────────────
+  Release-time transparency
+──────────────────────────────────────────────────────────────────────────*/
 
-	if isBefore {
-		return true
+// GetReleaseTime tells the calling worker when a job (or, without an
+// instance, any job on a service date) is released to them and why.
+// Date queries are evaluated in workerLoc; the tenant advantage is reported
+// separately because it only applies at the worker's own property.
+func (s *JobService) GetReleaseTime(
+	ctx context.Context,
+	workerID string,
+	q dtos.ReleaseTimeQuery,
+	workerLoc *time.Location,
+) (*dtos.ReleaseTimeDTO, error) {
+	wUUID, err := uuid.Parse(workerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker ID format: %w", err)
+	}
+	worker, err := s.workerRepo.GetByID(ctx, wUUID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, fmt.Errorf("authenticated worker with ID %s not found in database", workerID)
 	}
 
-	durationHours := inst.ServiceDate.Truncate(24 * time.Hour).Sub(baseMidnightForProp.Truncate(24 * time.Hour)).Hours()
-	daysAhead := int(math.Round(durationHours / 24.0))
+	var tenantPropID *uuid.UUID
+	if worker.TenantToken != nil && *worker.TenantToken != "" {
+		tenantPropID, _ = s.lookupTenantPropertyID(ctx, *worker.TenantToken)
+	}
 
-	baseDateForReleaseCalc := baseMidnightForProp.AddDate(0, 0, daysAhead-(constants.DaysToListOpenJobsRange-2))
+	out := &dtos.ReleaseTimeDTO{}
+	var (
+		loc         *time.Location
+		serviceDate time.Time
+		propID      uuid.UUID
+	)
+	if q.InstanceID != nil {
+		inst, iErr := s.instRepo.GetByID(ctx, *q.InstanceID)
+		if iErr != nil {
+			return nil, iErr
+		}
+		if inst == nil {
+			return nil, internal_utils.ErrInstanceNotFound
+		}
+		defn, dErr := s.defRepo.GetByID(ctx, inst.DefinitionID)
+		if dErr != nil || defn == nil {
+			return nil, fmt.Errorf("job definition not found")
+		}
+		prop, pErr := s.propRepo.GetByID(ctx, defn.PropertyID)
+		if pErr != nil || prop == nil {
+			return nil, fmt.Errorf("property not found")
+		}
+		// Jobs the listing hides don't exist as far as this worker knows.
+		if !visibleToWorker(inst, prop, wUUID, s.IsReviewer(ctx, workerID)) {
+			return nil, internal_utils.ErrInstanceNotFound
+		}
+		loc = loadPropertyLocation(prop.TimeZone)
+		serviceDate = inst.ServiceDate
+		propID = prop.ID
+		out.InstanceID = &inst.ID
+		out.PropertyID = &prop.ID
+	} else {
+		loc = workerLoc
+		serviceDate = q.Date
+	}
 
-	effectiveReleaseT := s.computeEffectiveReleaseTimeForBatch(baseDateForReleaseCalc, workerScore, workerTenantPropertyID, prop.ID)
+	now := time.Now().In(loc)
+	baseMidnight := dateOnlyInLocation(now, loc)
+	b := s.computeReleaseBreakdown(serviceDate, baseMidnight, worker.ReliabilityScore, tenantPropID, propID)
 
-	return !propNow.Before(effectiveReleaseT)
+	out.ServiceDate = serviceDate.Format("2006-01-02")
+	out.ReleaseAt = b.ReleaseAt
+	out.IsReleased = b.releasedAt(now)
+	if !out.IsReleased {
+		out.SecondsUntilRelease = int64(b.ReleaseAt.Sub(now).Seconds() + 0.5)
+	}
+	out.Factors = dtos.ReleaseFactorsDTO{
+		InOpenWindow:           b.InOpenWindow,
+		BaseReleaseAt:          b.BaseRelease,
+		ReliabilityScore:       worker.ReliabilityScore,
+		ScoreDelayMinutes:      int(b.ScoreDelay / time.Minute),
+		MaxScoreDelayMinutes:   int(constants.MaxShadowReleaseDelay / time.Minute),
+		TenantAdvantageMinutes: int(b.TenantAdvantage / time.Minute),
+		ReleaseAtPerfectScore:  b.BaseRelease.Add(-b.TenantAdvantage),
+	}
+	if q.InstanceID == nil && tenantPropID != nil {
+		// Date queries have no property; show what residents get at home.
+		out.Factors.TenantPropertyID = tenantPropID
+		tenantAt := b.ReleaseAt.Add(-constants.TenantReleaseAdvantage)
+		out.Factors.TenantPropertyReleaseAt = &tenantAt
+	}
+	return out, nil
 }
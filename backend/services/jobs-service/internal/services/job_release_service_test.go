@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func TestComputeReleaseBreakdown(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("no tz database: %v", err)
+	}
+	today := time.Date(2026, 3, 3, 0, 0, 0, 0, chicago)
+	date := func(daysAhead int) time.Time {
+		return time.Date(2026, 3, 3+daysAhead, 0, 0, 0, 0, time.UTC)
+	}
+	home, elsewhere := uuid.New(), uuid.New()
+
+	for name, tc := range map[string]struct {
+		daysAhead  int
+		score      int
+		tenantProp *uuid.UUID
+		openWindow bool
+		base       time.Time
+		releaseAt  time.Time
+	}{
+		"inside the open window": {
+			daysAhead: 5, score: 100, openWindow: true, base: today.AddDate(0, 0, -1), releaseAt: today.AddDate(0, 0, -1),
+		},
+		"first day past the window": {
+			daysAhead: 6, score: 100, base: today, releaseAt: today,
+		},
+		"a week out releases tomorrow": {
+			daysAhead: 7, score: 100, base: today.AddDate(0, 0, 1), releaseAt: today.AddDate(0, 0, 1),
+		},
+		"half score waits half the delay": {
+			daysAhead: 6, score: 50, base: today, releaseAt: today.Add(time.Hour),
+		},
+		"zero score waits the full delay": {
+			daysAhead: 6, score: 0, base: today, releaseAt: today.Add(2 * time.Hour),
+		},
+		"residents see their own property early": {
+			daysAhead: 6, score: 100, tenantProp: &home, base: today, releaseAt: today.Add(-time.Hour),
+		},
+		"tenant advantage and score delay combine": {
+			daysAhead: 6, score: 50, tenantProp: &home, base: today, releaseAt: today,
+		},
+		"no advantage at another property": {
+			daysAhead: 6, score: 100, tenantProp: &elsewhere, base: today, releaseAt: today,
+		},
+		// DST starts on 2026-03-08; the release is still local midnight.
+		"across a DST change": {
+			daysAhead: 12, score: 100, base: time.Date(2026, 3, 9, 0, 0, 0, 0, chicago), releaseAt: time.Date(2026, 3, 9, 0, 0, 0, 0, chicago),
+		},
+	} {
+		b := (&JobService{}).computeReleaseBreakdown(date(tc.daysAhead), today, tc.score, tc.tenantProp, home)
+		if b.InOpenWindow != tc.openWindow || !b.BaseRelease.Equal(tc.base) || !b.ReleaseAt.Equal(tc.releaseAt) {
+			t.Errorf("%s: expected open=%v base=%s release=%s, got open=%v base=%s release=%s",
+				name, tc.openWindow, tc.base, tc.releaseAt, b.InOpenWindow, b.BaseRelease, b.ReleaseAt)
+		}
+	}
+}
+
+func TestReleaseBreakdownReleasedAt(t *testing.T) {
+	releaseAt := time.Date(2026, 3, 3, 1, 0, 0, 0, time.UTC)
+	b := releaseBreakdown{ReleaseAt: releaseAt}
+	if b.releasedAt(releaseAt.Add(-time.Second)) {
+		t.Errorf("expected the job to be hidden a second before release")
+	}
+	if !b.releasedAt(releaseAt) {
+		t.Errorf("expected the job to be released at its release time")
+	}
+	b.InOpenWindow = true
+	if !b.releasedAt(releaseAt.AddDate(0, 0, -3)) {
+		t.Errorf("expected open-window dates to be released whatever the time")
+	}
+}
+
+func TestVisibleToWorker(t *testing.T) {
+	worker, other := uuid.New(), uuid.New()
+	inst := &models.JobInstance{ExcludedWorkerIDs: []uuid.UUID{other}}
+	excluded := &models.JobInstance{ExcludedWorkerIDs: []uuid.UUID{worker}}
+	live, demo := &models.Property{}, &models.Property{IsDemo: true}
+
+	for name, tc := range map[string]struct {
+		inst     *models.JobInstance
+		prop     *models.Property
+		reviewer bool
+		want     bool
+	}{
+		"worker sees a real job":           {inst: inst, prop: live, want: true},
+		"excluded worker doesn't":          {inst: excluded, prop: live},
+		"workers don't see demo jobs":      {inst: inst, prop: demo},
+		"reviewers see demo jobs":          {inst: inst, prop: demo, reviewer: true, want: true},
+		"reviewers don't see real jobs":    {inst: inst, prop: live, reviewer: true},
+		"excluded reviewer doesn't see it": {inst: excluded, prop: demo, reviewer: true},
+	} {
+		if got := visibleToWorker(tc.inst, tc.prop, worker, tc.reviewer); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, got)
+		}
+	}
+}
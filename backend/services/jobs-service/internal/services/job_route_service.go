@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// computeRoutes returns drive distance/time from (lat, lng) to every property
+// in one cached, batched lookup. Properties that cannot be routed get the
+// crow-flies estimate, so every property has an entry.
+func (s *JobService) computeRoutes(
+	ctx context.Context,
+	lat, lng float64,
+	props []*models.Property,
+) map[uuid.UUID]*routeInfo {
+	out := make(map[uuid.UUID]*routeInfo, len(props))
+	if len(props) == 0 {
+		return out
+	}
+
+	dests := make([]utils.LatLng, len(props))
+	for i, p := range props {
+		dests[i] = utils.LatLng{Lat: p.Latitude, Lng: p.Longitude}
+	}
+	origin := utils.LatLng{Lat: lat, Lng: lng}
+
+	var times []utils.DriveTime
+	if s.driveTimes != nil {
+		times = s.driveTimes.FromOrigin(ctx, origin, dests)
+	} else {
+		times = make([]utils.DriveTime, len(dests))
+		for i, d := range dests {
+			times[i] = utils.CrowFliesDriveTime(origin, d)
+		}
+	}
+
+	for i, p := range props {
+		mins := times[i].Minutes
+		out[p.ID] = &routeInfo{DistanceMiles: times[i].DistanceMiles, TravelMinutes: &mins}
+	}
+	return out
+}
+
+// computeRoute is computeRoutes for a single property.
+func (s *JobService) computeRoute(ctx context.Context, lat, lng float64, prop *models.Property) *routeInfo {
+	return s.computeRoutes(ctx, lat, lng, []*models.Property{prop})[prop.ID]
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
 	"github.com/poofware/mono-repo/backend/shared/go-utils"
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/config"
@@ -84,20 +83,9 @@ func (s *JobSchedulerService) RunDailyWindowMaintenance(ctx context.Context) err
 		// generate day+7 if needed
 		for _, d := range propDefs {
 			if shouldCreateOnDate(d, dayPlus7) {
-				dailyEstimate := d.GetDailyEstimate(dayPlus7.Weekday())
-				var initialPay float64
-				if dailyEstimate != nil {
-					initialPay = dailyEstimate.BasePay
-				}
-
-				inst := &models.JobInstance{
-					ID:           uuid.New(),
-					DefinitionID: d.ID,
-					ServiceDate:  dayPlus7,
-					Status:       models.InstanceStatusOpen,
-					EffectivePay: initialPay,
+				for _, inst := range newInstancesForDate(d, dayPlus7) {
+					_ = s.instRepo.CreateIfNotExists(ctx, inst)
 				}
-				_ = s.instRepo.CreateIfNotExists(ctx, inst)
 			}
 		}
 
@@ -106,20 +94,9 @@ func (s *JobSchedulerService) RunDailyWindowMaintenance(ctx context.Context) err
 			day := today.AddDate(0,0,dayOffset)
 			for _, d := range propDefs {
 				if shouldCreateOnDate(d, day) {
-					dailyEstimate := d.GetDailyEstimate(day.Weekday())
-					var initialPay float64
-					if dailyEstimate != nil {
-						initialPay = dailyEstimate.BasePay
+					for _, inst := range newInstancesForDate(d, day) {
+						_ = s.instRepo.CreateIfNotExists(ctx, inst)
 					}
-
-					inst := &models.JobInstance{
-						ID:           uuid.New(),
-						DefinitionID: d.ID,
-						ServiceDate:  day,
-						Status:       models.InstanceStatusOpen,
-						EffectivePay: initialPay,
-					}
-					_ = s.instRepo.CreateIfNotExists(ctx, inst)
 				}
 			}
 		}
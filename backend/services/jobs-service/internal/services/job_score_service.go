@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*──────────────────────────────────────────────────────────────────────────
+  Reliability score ledger
+
+  Every score change is a row in worker_score_events, written together with
+  the score. Workers read their own timeline; ops read anyone's, adjust a
+  score by hand with a note, and reverse an event when a penalty is
+  overturned.
+
+  How much each event is worth comes from the scoring model (the
+  scoring_model flag). Completed jobs earn small rewards, penalties fade
+  back over time through a nightly decay run, and ops can replay everyone's
+  ledger under a candidate model to see what it would do before rolling
+  it out.
+──────────────────────────────────────────────────────────────────────────*/
+
+const (
+	maxScoreHistoryLimit     = 100
+	maxScoreRecomputeWorkers = 1000
+	scoreReplayBatch         = 200
+)
+
+// scoringModel is the live scoring model.
+func (s *JobService) scoringModel() *models.ScoringModel {
+	if s.cfg.LDFlag_ScoringModel != nil {
+		return s.cfg.LDFlag_ScoringModel
+	}
+	return models.DefaultScoringModel()
+}
+
+// scoreJobEvent records a job's score event on w, worth what the scoring
+// model says; recorded is used for events the model has no value for.
+func (s *JobService) scoreJobEvent(
+	ctx context.Context,
+	w *repositories.Work,
+	workerID, instanceID uuid.UUID,
+	eventType string,
+	recorded int,
+) error {
+	model := s.scoringModel()
+	delta := model.PointsFor(eventType, recorded)
+	if delta == 0 {
+		return nil
+	}
+	_, err := w.Workers().AdjustWorkerScore(ctx, models.ScoreAdjustment{
+		WorkerID:      workerID,
+		Delta:         delta,
+		EventType:     eventType,
+		JobInstanceID: &instanceID,
+		Model:         model,
+	})
+	return err
+}
+
+// scoreCompletion awards what a just-completed job earned its worker: for
+// starting before the no-show cutoff, for every unit passing verification
+// the first time, and for covering a job at surge pay.
+func (s *JobService) scoreCompletion(
+	ctx context.Context,
+	w *repositories.Work,
+	defn *models.JobDefinition,
+	prop *models.Property,
+	inst *models.JobInstance,
+	verifs []*models.JobUnitVerification,
+) error {
+	if inst.AssignedWorkerID == nil {
+		return nil
+	}
+	workerID := *inst.AssignedWorkerID
+
+	if inst.CheckInAt != nil {
+		loc := loadPropertyLocation(prop.TimeZone)
+		d := inst.ServiceDate
+		lStart := time.Date(d.Year(), d.Month(), d.Day(), defn.LatestStartTime.Hour(), defn.LatestStartTime.Minute(), 0, 0, loc)
+		if !inst.CheckInAt.After(lStart.Add(-constants.NoShowCutoffBeforeLatestStart)) {
+			if err := s.scoreJobEvent(ctx, w, workerID, inst.ID, models.ScoreEventOnTimeCompletion, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A unit that was ever rejected keeps its failure history even once it
+	// passes, so an empty history means it passed first time.
+	clean := len(verifs) > 0
+	for _, v := range verifs {
+		if v.Status != models.UnitVerificationDumped || len(v.FailureReasonHistory) > 0 {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		if err := s.scoreJobEvent(ctx, w, workerID, inst.ID, models.ScoreEventCleanVerification, 0); err != nil {
+			return err
+		}
+	}
+
+	if daily := defn.GetDailyEstimate(inst.ServiceDate.Weekday()); daily != nil && daily.BasePay.IsPositive() {
+		if inst.EffectivePay.Cmp(defn.SegmentPay(daily.BasePay, inst.SegmentIndex)) > 0 {
+			if err := s.scoreJobEvent(ctx, w, workerID, inst.ID, models.ScoreEventSurgePickup, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func encodeScoreCursor(e *models.WorkerScoreLedgerEntry) string {
+	b, _ := json.Marshal(repositories.ScoreEventCursor{CreatedAt: e.CreatedAt, ID: e.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeScoreCursor(raw string) (*repositories.ScoreEventCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, internal_utils.ErrInvalidCursor
+	}
+	var c repositories.ScoreEventCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return nil, internal_utils.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// ScoreHistory returns the worker's score and ledger, newest first. ops
+// includes who made manual changes and their notes.
+func (s *JobService) ScoreHistory(
+	ctx context.Context,
+	workerID uuid.UUID,
+	cursor string,
+	limit int,
+	ops bool,
+) (*dtos.ScoreHistoryResponse, error) {
+	var after *repositories.ScoreEventCursor
+	if cursor != "" {
+		var err error
+		if after, err = decodeScoreCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+	worker, err := s.workerRepo.GetByID(ctx, workerID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, internal_utils.ErrWorkerNotFound
+	}
+
+	if limit <= 0 || limit > maxScoreHistoryLimit {
+		limit = maxScoreHistoryLimit
+	}
+	events, err := s.scoreEventRepo.ListByWorker(ctx, workerID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dtos.ScoreHistoryResponse{
+		WorkerID:         worker.ID,
+		ReliabilityScore: worker.ReliabilityScore,
+		IsBanned:         worker.IsBanned,
+		SuspendedUntil:   worker.SuspendedUntil,
+		Events:           make([]dtos.ScoreLedgerEntry, 0, len(events)),
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, scoreLedgerEntryDTO(e, ops))
+	}
+	if len(events) == limit {
+		next := encodeScoreCursor(events[len(events)-1])
+		resp.NextCursor = &next
+	}
+	return resp, nil
+}
+
+// AdjustScoreManually applies an ops adjustment to a worker's score.
+func (s *JobService) AdjustScoreManually(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.ManualScoreAdjustmentRequest,
+) (*dtos.ScoreLedgerEntry, error) {
+	worker, err := s.workerRepo.GetByID(ctx, req.WorkerID)
+	if err != nil {
+		return nil, err
+	}
+	if worker == nil {
+		return nil, internal_utils.ErrWorkerNotFound
+	}
+
+	entry, err := s.workerRepo.AdjustWorkerScore(ctx, models.ScoreAdjustment{
+		WorkerID:      req.WorkerID,
+		Delta:         req.Delta,
+		EventType:     models.ScoreEventManualAdjustment,
+		JobInstanceID: req.JobInstanceID,
+		ActorID:       &actorID,
+		Note:          req.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops user %s adjusted worker %s score by %d (%d → %d): %s",
+		actorID, req.WorkerID, req.Delta, entry.OldScore, entry.NewScore, req.Note)
+	dto := scoreLedgerEntryDTO(entry, true)
+	return &dto, nil
+}
+
+// ReverseScoreEvent gives back what a score event took (or takes back what
+// it gave). Returns utils.ErrScoreEventNotFound, ErrScoreEventNotReversible
+// or ErrScoreEventAlreadyReversed when it can't.
+func (s *JobService) ReverseScoreEvent(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.ScoreReversalRequest,
+) (*dtos.ScoreLedgerEntry, error) {
+	entry, err := s.workerRepo.ReverseScoreEvent(ctx, req.EventID, &actorID, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	utils.Logger.Infof("Ops user %s reversed score event %s for worker %s (%d → %d): %s",
+		actorID, req.EventID, entry.WorkerID, entry.OldScore, entry.NewScore, req.Note)
+	dto := scoreLedgerEntryDTO(entry, true)
+	return &dto, nil
+}
+
+func scoreLedgerEntryDTO(e *models.WorkerScoreLedgerEntry, ops bool) dtos.ScoreLedgerEntry {
+	dto := dtos.ScoreLedgerEntry{
+		ID:              e.ID,
+		EventType:       e.EventType,
+		Reason:          models.ScoreEventReason(e.EventType),
+		Delta:           e.Delta,
+		AppliedDelta:    e.AppliedDelta(),
+		OldScore:        e.OldScore,
+		NewScore:        e.NewScore,
+		JobInstanceID:   e.JobInstanceID,
+		ReversesEventID: e.ReversesEventID,
+		ReversedByID:    e.ReversedByID,
+		CreatedAt:       e.CreatedAt,
+	}
+	if ops {
+		dto.ActorID = e.ActorID
+		dto.Note = e.Note
+	}
+	return dto
+}
+
+// RunScoreDecay gives back the part of each worker's penalties that has
+// faded under the scoring model. The amount comes only from the aged
+// penalty events in the worker's ledger; when a replay of the whole ledger
+// disagrees with where that leaves the score, the difference is logged for
+// ops rather than applied. It only ever raises a score, and leaves banned
+// workers for ops to review.
+func (s *JobService) RunScoreDecay(ctx context.Context) error {
+	model := s.scoringModel()
+	if model.PenaltyHalfLifeDays <= 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	// Ten half-lives on, less than 0.1% of a penalty is left.
+	since := now.Add(-time.Duration(10 * model.PenaltyHalfLifeDays * 24 * float64(time.Hour)))
+	ids, err := s.scoreEventRepo.ListWorkerIDs(ctx, &since, 0)
+	if err != nil {
+		return err
+	}
+
+	raised, diverged := 0, 0
+	for start := 0; start < len(ids); start += scoreReplayBatch {
+		batch := ids[start:min(start+scoreReplayBatch, len(ids))]
+		ledgers, err := s.scoreEventRepo.ListForReplay(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for _, id := range batch {
+			worker, err := s.workerRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if worker == nil || worker.IsBanned {
+				continue
+			}
+			owed := model.DecayOwed(ledgers[id], now)
+			after := min(worker.ReliabilityScore+max(owed, 0), model.MaxScore)
+			if replayed := model.Score(ledgers[id], worker.CreatedAt, now); replayed != after {
+				diverged++
+				utils.Logger.Warnf(
+					"Score decay: worker %s goes from %d to %d (%+d owed), but replaying their ledger under model %q gives %d",
+					id, worker.ReliabilityScore, after, owed, model.Name, replayed,
+				)
+			}
+			if owed <= 0 {
+				continue
+			}
+			_, err = s.workerRepo.AdjustWorkerScore(ctx, models.ScoreAdjustment{
+				WorkerID:  id,
+				Delta:     owed,
+				EventType: models.ScoreEventPenaltyDecay,
+				Model:     model,
+			})
+			if err != nil {
+				utils.Logger.WithError(err).Errorf("Score decay failed for worker %s", id)
+				continue
+			}
+			raised++
+		}
+	}
+	utils.Logger.Infof("Score decay: %d of %d workers with recent penalties raised; %d diverge from a ledger replay", raised, len(ids), diverged)
+	return nil
+}
+
+// RecomputeScores replays worker ledgers under a scoring model and reports
+// how scores, bans and suspensions would change. It writes nothing.
+func (s *JobService) RecomputeScores(
+	ctx context.Context,
+	req dtos.ScoreRecomputeRequest,
+) (*dtos.ScoreRecomputeResponse, error) {
+	model := s.scoringModel()
+	if len(req.Model) > 0 {
+		m, err := models.ParseScoringModel(req.Model)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", internal_utils.ErrInvalidPayload, err)
+		}
+		model = m
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > maxScoreRecomputeWorkers {
+		limit = maxScoreRecomputeWorkers
+	}
+	ids := req.WorkerIDs
+	truncated := false
+	if len(ids) == 0 {
+		var err error
+		if ids, err = s.scoreEventRepo.ListWorkerIDs(ctx, nil, limit+1); err != nil {
+			return nil, err
+		}
+	}
+	if len(ids) > limit {
+		ids, truncated = ids[:limit], true
+	}
+
+	resp := &dtos.ScoreRecomputeResponse{
+		Model:     model,
+		At:        time.Now().UTC(),
+		Workers:   make([]dtos.ScoreRecomputeWorker, 0, len(ids)),
+		Truncated: truncated,
+	}
+	sum := &resp.Summary
+	totalDiff := 0
+	for start := 0; start < len(ids); start += scoreReplayBatch {
+		batch := ids[start:min(start+scoreReplayBatch, len(ids))]
+		ledgers, err := s.scoreEventRepo.ListForReplay(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range batch {
+			worker, err := s.workerRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if worker == nil {
+				continue
+			}
+			score := model.Score(ledgers[id], worker.CreatedAt, resp.At)
+			row := dtos.ScoreRecomputeWorker{
+				WorkerID:         id,
+				CurrentScore:     worker.ReliabilityScore,
+				RecomputedScore:  score,
+				Difference:       score - worker.ReliabilityScore,
+				IsBanned:         worker.IsBanned,
+				WouldBeBanned:    score <= utils.WorkerBanThresholdScore,
+				WouldBeSuspended: score > utils.WorkerBanThresholdScore && score <= utils.WorkerSuspendThresholdScore,
+			}
+			resp.Workers = append(resp.Workers, row)
+
+			sum.Workers++
+			totalDiff += row.Difference
+			switch {
+			case row.Difference > 0:
+				sum.Raised++
+			case row.Difference < 0:
+				sum.Lowered++
+			default:
+				sum.Unchanged++
+			}
+			if row.WouldBeBanned && !row.IsBanned {
+				sum.NewlyBanned++
+			} else if !row.WouldBeBanned && row.IsBanned {
+				sum.NoLongerBanned++
+			}
+		}
+	}
+	if sum.Workers > 0 {
+		sum.MeanDifference = float64(totalDiff) / float64(sum.Workers)
+	}
+	return resp, nil
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+// searchCursor is the payload behind the opaque cursor string. The sort and
+// a digest of the origin and filters are embedded so a cursor cannot be
+// replayed against a different ordering or result set: a distance key from
+// one origin means nothing from another.
+type searchCursor struct {
+	Sort  repositories.JobSearchSort `json:"s"`
+	Query string                     `json:"q"`
+	repositories.JobSearchCursor
+}
+
+// searchQueryDigest identifies everything in q that decides which jobs
+// match and in what order; the page size and cursor don't.
+func searchQueryDigest(q dtos.SearchJobsQuery, sort repositories.JobSearchSort) string {
+	q.Cursor, q.Size, q.Sort = "", 0, string(sort)
+	b, _ := json.Marshal(q)
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func encodeSearchCursor(sort repositories.JobSearchSort, query string, row *repositories.JobInstanceSearchRow) string {
+	b, _ := json.Marshal(searchCursor{
+		Sort:            sort,
+		Query:           query,
+		JobSearchCursor: repositories.JobSearchCursor{SortKey: row.SortKey, ID: row.Instance.ID},
+	})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSearchCursor(raw string, sort repositories.JobSearchSort, query string) (*repositories.JobSearchCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, internal_utils.ErrInvalidCursor
+	}
+	var c searchCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil || c.Sort != sort || c.Query != query {
+		return nil, internal_utils.ErrInvalidCursor
+	}
+	return &c.JobSearchCursor, nil
+}
+
+func parseSearchSort(s string) (repositories.JobSearchSort, error) {
+	switch repositories.JobSearchSort(s) {
+	case "", repositories.JobSearchSortDistance:
+		return repositories.JobSearchSortDistance, nil
+	case repositories.JobSearchSortPay:
+		return repositories.JobSearchSortPay, nil
+	case repositories.JobSearchSortDate:
+		return repositories.JobSearchSortDate, nil
+	default:
+		return "", fmt.Errorf("%w: unknown sort %q", internal_utils.ErrInvalidPayload, s)
+	}
+}
+
+// searchMaxDistance is the straight-line radius to search. Workers are held
+// to RadiusMiles; reviewers may search anywhere. Road distance is never
+// shorter than straight-line distance, so a travel-time limit at the fastest
+// plausible highway speed also bounds how far a job can be. The exact
+// travel-time check happens after routing.
+func searchMaxDistance(q dtos.SearchJobsQuery, isReviewer bool) *float64 {
+	maxDistance := q.MaxDistanceMiles
+	if !isReviewer && (maxDistance == nil || *maxDistance > constants.RadiusMiles) {
+		maxDistance = utils.Ptr(float64(constants.RadiusMiles))
+	}
+	if q.MaxTravelMinutes != nil {
+		bound := float64(*q.MaxTravelMinutes) * constants.SearchMaxTravelSpeedMPH / 60
+		if maxDistance == nil || bound < *maxDistance {
+			maxDistance = &bound
+		}
+	}
+	return maxDistance
+}
+
+// SearchOpenJobs is the filtered, cursor-paginated counterpart of ListOpenJobs.
+// Static filters run in SQL; release timing, acceptance cutoff and drive time
+// depend on the worker and the clock, so they are applied here while reading
+// the keyset in batches until the page is full.
+func (s *JobService) SearchOpenJobs(
+	ctx context.Context,
+	userID string,
+	q dtos.SearchJobsQuery,
+	workerLoc *time.Location,
+) (*dtos.SearchJobsResponse, error) {
+	sort, err := parseSearchSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	query := searchQueryDigest(q, sort)
+	var after *repositories.JobSearchCursor
+	if q.Cursor != "" {
+		after, err = decodeSearchCursor(q.Cursor, sort, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker ID format: %w", err)
+	}
+	isReviewer := s.IsReviewer(ctx, userID)
+	var wScore int
+	var wTenantPropID *uuid.UUID
+	if w, wErr := s.workerRepo.GetByID(ctx, wID); wErr == nil && w != nil {
+		wScore = w.ReliabilityScore
+		if w.TenantToken != nil && *w.TenantToken != "" {
+			wTenantPropID, _ = s.lookupTenantPropertyID(ctx, *w.TenantToken)
+		}
+	}
+
+	// Clamp the requested dates to the same window ListOpenJobs serves.
+	nowLocal := time.Now().In(workerLoc)
+	startDate := dateOnlyInLocation(nowLocal.AddDate(0, 0, -1), workerLoc)
+	endDate := startDate.AddDate(0, 0, constants.DaysToListOpenJobsRange)
+	if q.StartDate != nil && q.StartDate.After(startDate) {
+		startDate = *q.StartDate
+	}
+	if q.EndDate != nil && q.EndDate.Before(endDate) {
+		endDate = *q.EndDate
+	}
+
+	maxDistance := searchMaxDistance(q, isReviewer)
+	batchSize := max(q.Size*2, 20)
+	var minPay *models.Money
+	if q.MinPay != nil {
+		m := models.MoneyFromDollars(*q.MinPay)
+		minPay = &m
+	}
+	filter := repositories.JobInstanceSearchFilter{
+		Statuses:            []models.InstanceStatusType{models.InstanceStatusOpen},
+		StartDate:           startDate,
+		EndDate:             endDate,
+		OriginLat:           &q.Lat,
+		OriginLng:           &q.Lng,
+		MaxDistanceMiles:    maxDistance,
+		MinPay:              minPay,
+		PropertyIDs:         q.PropertyIDs,
+		VehicleRequirements: q.VehicleRequirements,
+		MaxEstimatedMinutes: q.MaxEstimatedMinutes,
+		ExcludeWorkerID:     &wID,
+		DemoProperties:      &isReviewer,
+		Sort:                sort,
+		After:               after,
+		Limit:               batchSize,
+	}
+
+	defCache := make(map[uuid.UUID]*models.JobDefinition)
+	propCache := make(map[uuid.UUID]*models.Property)
+	routeCache := make(map[uuid.UUID]*routeInfo)
+
+	results := make([]dtos.JobInstanceDTO, 0, q.Size)
+	var lastConsumed *repositories.JobInstanceSearchRow
+	exhausted := false
+
+	for len(results) < q.Size && !exhausted {
+		rows, err := s.instRepo.SearchInstances(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		exhausted = len(rows) < batchSize
+
+		for _, row := range rows {
+			if len(results) >= q.Size {
+				exhausted = false
+				break
+			}
+			lastConsumed = row
+			inst := row.Instance
+
+			defn, ok := defCache[inst.DefinitionID]
+			if !ok {
+				defn, _ = s.defRepo.GetByID(ctx, inst.DefinitionID)
+				defCache[inst.DefinitionID] = defn
+			}
+			prop, ok := propCache[row.PropertyID]
+			if !ok {
+				prop, _ = s.propRepo.GetByID(ctx, row.PropertyID)
+				propCache[row.PropertyID] = prop
+			}
+			if defn == nil || prop == nil {
+				continue
+			}
+
+			propLoc := loadPropertyLocation(prop.TimeZone)
+			propNow := time.Now().In(propLoc)
+			if !s.isJobReleasedToWorker(inst, prop, propNow, dateOnlyInLocation(propNow, propLoc), wScore, wTenantPropID) {
+				continue
+			}
+			latestStartLocal := time.Date(inst.ServiceDate.Year(), inst.ServiceDate.Month(), inst.ServiceDate.Day(), defn.LatestStartTime.Hour(), defn.LatestStartTime.Minute(), 0, 0, propLoc)
+			acceptanceCutoffTime := latestStartLocal.Add(-constants.NoShowCutoffBeforeLatestStart).Add(-constants.AcceptanceCutoffBeforeNoShow)
+			if time.Now().After(acceptanceCutoffTime) {
+				continue
+			}
+
+			route, ok := routeCache[prop.ID]
+			if !ok {
+				route = s.computeRoute(ctx, q.Lat, q.Lng, prop)
+				routeCache[prop.ID] = route
+			}
+			if q.MaxTravelMinutes != nil && route.TravelMinutes != nil && *route.TravelMinutes > *q.MaxTravelMinutes {
+				continue
+			}
+
+			dto, err := s.buildInstanceDTO(ctx, inst, route, workerLoc, defn, prop, nil, nil, nil)
+			if err == nil && dto != nil {
+				results = append(results, *dto)
+			}
+		}
+
+		if lastConsumed != nil {
+			filter.After = &repositories.JobSearchCursor{SortKey: lastConsumed.SortKey, ID: lastConsumed.Instance.ID}
+		}
+	}
+
+	resp := &dtos.SearchJobsResponse{Results: results, Size: q.Size}
+	if !exhausted && lastConsumed != nil {
+		next := encodeSearchCursor(sort, query, lastConsumed)
+		resp.NextCursor = &next
+	}
+	return resp, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+func TestSearchCursorIsTiedToItsQuery(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	base := dtos.SearchJobsQuery{
+		Lat: 36.16, Lng: -86.78, Size: 10,
+		MinPay:              utils.Ptr(40.0),
+		StartDate:           &start,
+		PropertyIDs:         []uuid.UUID{uuid.New()},
+		VehicleRequirements: []models.VehicleRequirementType{models.VehicleTruck},
+	}
+	row := &repositories.JobInstanceSearchRow{
+		Instance: &models.JobInstance{ID: uuid.New()},
+		SortKey:  3.25,
+	}
+	cursor := encodeSearchCursor(repositories.JobSearchSortDistance, searchQueryDigest(base, repositories.JobSearchSortDistance), row)
+
+	decode := func(q dtos.SearchJobsQuery, sort repositories.JobSearchSort) (*repositories.JobSearchCursor, error) {
+		return decodeSearchCursor(cursor, sort, searchQueryDigest(q, sort))
+	}
+
+	// The next page of the same search picks up after the row, whatever
+	// the page size.
+	next := base
+	next.Cursor, next.Size = cursor, 50
+	got, err := decode(next, repositories.JobSearchSortDistance)
+	if err != nil || got.ID != row.Instance.ID || got.SortKey != row.SortKey {
+		t.Fatalf("expected the cursor to resume after %s at %v, got %+v (%v)", row.Instance.ID, row.SortKey, got, err)
+	}
+
+	for name, change := range map[string]func(q *dtos.SearchJobsQuery){
+		"moved origin":        func(q *dtos.SearchJobsQuery) { q.Lat += 0.1 },
+		"higher min pay":      func(q *dtos.SearchJobsQuery) { q.MinPay = utils.Ptr(50.0) },
+		"later start date":    func(q *dtos.SearchJobsQuery) { d := start.AddDate(0, 0, 1); q.StartDate = &d },
+		"other property":      func(q *dtos.SearchJobsQuery) { q.PropertyIDs = []uuid.UUID{uuid.New()} },
+		"no vehicle filter":   func(q *dtos.SearchJobsQuery) { q.VehicleRequirements = nil },
+		"added distance cap":  func(q *dtos.SearchJobsQuery) { q.MaxDistanceMiles = utils.Ptr(10.0) },
+		"added travel limit":  func(q *dtos.SearchJobsQuery) { q.MaxTravelMinutes = utils.Ptr(30) },
+		"added duration cap":  func(q *dtos.SearchJobsQuery) { q.MaxEstimatedMinutes = utils.Ptr(60) },
+		"later end date":      func(q *dtos.SearchJobsQuery) { d := start.AddDate(0, 0, 3); q.EndDate = &d },
+		"unchanged (control)": nil,
+	} {
+		q := base
+		if change != nil {
+			change(&q)
+		}
+		_, err := decode(q, repositories.JobSearchSortDistance)
+		if change == nil {
+			if err != nil {
+				t.Errorf("%s: expected the cursor to be accepted, got %v", name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, internal_utils.ErrInvalidCursor) {
+			t.Errorf("%s: expected ErrInvalidCursor, got %v", name, err)
+		}
+	}
+
+	if _, err := decode(base, repositories.JobSearchSortPay); !errors.Is(err, internal_utils.ErrInvalidCursor) {
+		t.Errorf("expected a distance cursor to be refused for a pay sort, got %v", err)
+	}
+	if _, err := decodeSearchCursor("not a cursor", repositories.JobSearchSortDistance, ""); !errors.Is(err, internal_utils.ErrInvalidCursor) {
+		t.Errorf("expected garbage to be refused, got %v", err)
+	}
+}
+
+func TestSearchMaxDistance(t *testing.T) {
+	for name, tc := range map[string]struct {
+		q        dtos.SearchJobsQuery
+		reviewer bool
+		want     *float64
+	}{
+		"workers default to the listing radius": {want: utils.Ptr(75.0)},
+		"workers can't search wider": {
+			q: dtos.SearchJobsQuery{MaxDistanceMiles: utils.Ptr(200.0)}, want: utils.Ptr(75.0),
+		},
+		"narrower distance is kept": {
+			q: dtos.SearchJobsQuery{MaxDistanceMiles: utils.Ptr(12.0)}, want: utils.Ptr(12.0),
+		},
+		"reviewers are unbounded": {reviewer: true},
+		// 30 minutes by highway can cover more than 30 straight-line miles.
+		"travel time allows highway speed": {
+			q: dtos.SearchJobsQuery{MaxTravelMinutes: utils.Ptr(30)}, want: utils.Ptr(40.0),
+		},
+		"travel time never widens the radius": {
+			q: dtos.SearchJobsQuery{MaxTravelMinutes: utils.Ptr(90)}, want: utils.Ptr(75.0),
+		},
+		"tighter distance beats travel time": {
+			q: dtos.SearchJobsQuery{MaxDistanceMiles: utils.Ptr(10.0), MaxTravelMinutes: utils.Ptr(30)}, want: utils.Ptr(10.0),
+		},
+		"reviewer travel time": {
+			q: dtos.SearchJobsQuery{MaxTravelMinutes: utils.Ptr(120)}, reviewer: true, want: utils.Ptr(160.0),
+		},
+	} {
+		got := searchMaxDistance(tc.q, tc.reviewer)
+		switch {
+		case got == nil && tc.want == nil:
+		case got == nil || tc.want == nil || *got != *tc.want:
+			t.Errorf("%s: expected %v, got %v", name, deref(tc.want), deref(got))
+		}
+	}
+}
+
+func deref(f *float64) any {
+	if f == nil {
+		return "unbounded"
+	}
+	return *f
+}
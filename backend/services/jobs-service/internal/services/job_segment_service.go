@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+// Roll-up statuses that only exist in the PM view of a split job. When every
+// segment shares a status the roll-up simply reports that InstanceStatusType.
+const (
+	RollupStatusPartiallyAssigned  = "PARTIALLY_ASSIGNED"
+	RollupStatusPartiallyCompleted = "PARTIALLY_COMPLETED"
+)
+
+/*
+──────────────────────────────────────────────────────────────────────────────
+
+	Segment planning
+
+──────────────────────────────────────────────────────────────────────────────
+*/
+
+// buildJobSegments splits a definition's assigned units into count segments.
+// BUILDING keeps each building whole and balances unit counts greedily.
+// FLOOR may split a building; it walks unit_ids in the order supplied (which
+// is expected to be floor order) and cuts contiguous runs of roughly equal size.
+// Returns nil when the definition should not be segmented.
+func buildJobSegments(
+	groups []models.AssignedUnitGroup,
+	strategy models.SegmentStrategyType,
+	count int,
+) ([]models.JobSegment, error) {
+	if strategy == models.SegmentStrategyNone || count <= 1 {
+		return nil, nil
+	}
+	if count > constants.MaxJobSegments {
+		return nil, fmt.Errorf("segment_count must be at most %d", constants.MaxJobSegments)
+	}
+
+	totalUnits := 0
+	for _, g := range groups {
+		totalUnits += len(g.UnitIDs)
+	}
+	if count > totalUnits {
+		return nil, fmt.Errorf("segment_count (%d) exceeds total units (%d)", count, totalUnits)
+	}
+
+	switch strategy {
+	case models.SegmentStrategyBuilding:
+		return segmentByBuilding(groups, count)
+	case models.SegmentStrategyFloor:
+		return segmentByFloor(groups, count, totalUnits), nil
+	default:
+		return nil, fmt.Errorf("unknown segment_strategy: %s", strategy)
+	}
+}
+
+func segmentByBuilding(groups []models.AssignedUnitGroup, count int) ([]models.JobSegment, error) {
+	nonEmpty := make([]models.AssignedUnitGroup, 0, len(groups))
+	for _, g := range groups {
+		if len(g.UnitIDs) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	if count > len(nonEmpty) {
+		return nil, fmt.Errorf("segment_count (%d) exceeds number of buildings (%d)", count, len(nonEmpty))
+	}
+
+	// Largest buildings first, each into the currently lightest segment.
+	sort.SliceStable(nonEmpty, func(i, j int) bool {
+		return len(nonEmpty[i].UnitIDs) > len(nonEmpty[j].UnitIDs)
+	})
+	segments := make([]models.JobSegment, count)
+	for i := range segments {
+		segments[i].Index = i
+	}
+	for _, g := range nonEmpty {
+		lightest := 0
+		for i := 1; i < count; i++ {
+			if segments[i].UnitCount < segments[lightest].UnitCount {
+				lightest = i
+			}
+		}
+		segments[lightest].Units = append(segments[lightest].Units, g)
+		segments[lightest].UnitCount += len(g.UnitIDs)
+	}
+	return segments, nil
+}
+
+func segmentByFloor(groups []models.AssignedUnitGroup, count, totalUnits int) []models.JobSegment {
+	segments := make([]models.JobSegment, count)
+	for i := range segments {
+		segments[i].Index = i
+	}
+
+	seen := 0
+	for _, g := range groups {
+		n := len(g.UnitIDs)
+		start := 0
+		for start < n {
+			seg := seen * count / totalUnits
+			// Units remaining before this segment's quota is met.
+			quotaEnd := ((seg+1)*totalUnits + count - 1) / count
+			end := min(start+(quotaEnd-seen), n)
+
+			segments[seg].Units = append(segments[seg].Units, models.AssignedUnitGroup{
+				BuildingID: g.BuildingID,
+				UnitIDs:    append([]uuid.UUID(nil), g.UnitIDs[start:end]...),
+				Floors:     floorsForRun(g.Floors, start, end, n),
+			})
+			segments[seg].UnitCount += end - start
+			seen += end - start
+			start = end
+		}
+	}
+	return segments
+}
+
+// floorsForRun picks the floors covered by units [start, end) of a building,
+// assuming the building's units are spread evenly over its sorted floors.
+func floorsForRun(floors []int16, start, end, n int) []int16 {
+	if len(floors) == 0 || n == 0 {
+		return nil
+	}
+	sorted := append([]int16(nil), floors...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	first := start * len(sorted) / n
+	last := (end - 1) * len(sorted) / n
+	return sorted[first : last+1]
+}
+
+/*
+──────────────────────────────────────────────────────────────────────────────
+
+	Instance creation
+
+──────────────────────────────────────────────────────────────────────────────
+*/
+
+// newInstancesForDate returns the OPEN instances a definition needs on day:
+// one for an ordinary definition, or one per segment with pro-rated pay.
+func newInstancesForDate(def *models.JobDefinition, day time.Time) []*models.JobInstance {
+	var basePay models.Money
+	if dailyEstimate := def.GetDailyEstimate(day.Weekday()); dailyEstimate != nil {
+		basePay = dailyEstimate.BasePay
+	}
+
+	count := def.SegmentCount()
+	out := make([]*models.JobInstance, 0, count)
+	for i := range count {
+		out = append(out, &models.JobInstance{
+			ID:           uuid.New(),
+			DefinitionID: def.ID,
+			ServiceDate:  day,
+			Status:       models.InstanceStatusOpen,
+			EffectivePay: def.SegmentPay(basePay, i),
+			SegmentIndex: i,
+			SegmentCount: count,
+		})
+	}
+	return out
+}
+
+/*
+──────────────────────────────────────────────────────────────────────────────
+
+	PM roll-up
+
+──────────────────────────────────────────────────────────────────────────────
+*/
+
+// GetDefinitionRollup summarises every segment of a definition on one
+// service date into a single status for the property manager.
+func (s *JobService) GetDefinitionRollup(
+	ctx context.Context,
+	pmUserID string,
+	defID uuid.UUID,
+	serviceDate time.Time,
+) (*dtos.DefinitionRollupDTO, error) {
+	defn, err := s.defRepo.GetByID(ctx, defID)
+	if err != nil {
+		return nil, err
+	}
+	if defn == nil {
+		return nil, internal_utils.ErrDefinitionNotFound
+	}
+	if defn.ManagerID.String() != pmUserID {
+		return nil, internal_utils.ErrNotDefinitionOwner
+	}
+
+	insts, err := s.instRepo.ListInstancesByDefinitionIDs(ctx, []uuid.UUID{defID}, nil, serviceDate, serviceDate)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dtos.DefinitionRollupDTO{
+		DefinitionID: defID,
+		ServiceDate:  serviceDate.Format("2006-01-02"),
+		Status:       rollupStatus(insts),
+		SegmentCount: defn.SegmentCount(),
+		TotalUnits:   defn.TotalUnits,
+		Segments:     make([]dtos.DefinitionRollupSegmentDTO, 0, len(insts)),
+	}
+	for _, inst := range insts {
+		if inst.Status == models.InstanceStatusCompleted {
+			out.SegmentsCompleted++
+		}
+		unitCount := defn.TotalUnits
+		if defn.IsSegmented() && inst.SegmentIndex < len(defn.Segments) {
+			unitCount = defn.Segments[inst.SegmentIndex].UnitCount
+		}
+		out.Segments = append(out.Segments, dtos.DefinitionRollupSegmentDTO{
+			InstanceID:       inst.ID,
+			SegmentIndex:     inst.SegmentIndex,
+			Status:           string(inst.Status),
+			AssignedWorkerID: inst.AssignedWorkerID,
+			UnitCount:        unitCount,
+			Pay:              inst.EffectivePay,
+			CheckInAt:        inst.CheckInAt,
+			CheckOutAt:       inst.CheckOutAt,
+		})
+	}
+	return out, nil
+}
+
+// rollupStatus folds segment statuses into one. Identical statuses pass
+// through unchanged; otherwise any work underway reports IN_PROGRESS.
+func rollupStatus(insts []*models.JobInstance) string {
+	if len(insts) == 0 {
+		return ""
+	}
+	counts := make(map[models.InstanceStatusType]int)
+	for _, inst := range insts {
+		counts[inst.Status]++
+	}
+	if len(counts) == 1 {
+		return string(insts[0].Status)
+	}
+
+	terminal := counts[models.InstanceStatusCompleted] + counts[models.InstanceStatusCanceled] + counts[models.InstanceStatusRetired]
+	switch {
+	case terminal == len(insts) && counts[models.InstanceStatusCompleted] > 0:
+		return RollupStatusPartiallyCompleted
+	case terminal == len(insts):
+		return string(models.InstanceStatusCanceled)
+	case counts[models.InstanceStatusInProgress] > 0 || counts[models.InstanceStatusCompleted] > 0:
+		return string(models.InstanceStatusInProgress)
+	case counts[models.InstanceStatusAssigned] > 0:
+		return RollupStatusPartiallyAssigned
+	default:
+		return string(models.InstanceStatusOpen)
+	}
+}
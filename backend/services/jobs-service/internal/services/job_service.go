@@ -3,8 +3,7 @@ package services
 import (
 	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/config"
 	"github.com/poofware/mono-repo/backend/shared/go-repositories"
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/twilio/twilio-go"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
 const (
@@ -28,9 +27,16 @@ type JobService struct {
 	unitRepo               repositories.UnitRepository
 	juvRepo                repositories.JobUnitVerificationRepository
 	agentJobCompletionRepo repositories.AgentJobCompletionRepository
+	lotteryRepo            repositories.JobLotteryRepository
+	surgeRepo              repositories.SurgePolicyRepository
+	scoreEventRepo         repositories.WorkerScoreEventRepository
+	payItemRepo            repositories.JobPayItemRepository
+	photoRepo              repositories.VerificationPhotoRepository
+	uow                    *repositories.UnitOfWork
 	openai                 *OpenAIService
-	twilioClient           *twilio.RestClient
-	sendgridClient         *sendgrid.Client
+	notifier               *utils.Notifier
+	queue                  *utils.JobQueue
+	driveTimes             *utils.DriveTimeCache
 }
 
 // routeInfo is a helper struct to cache the results of a single routing API call.
@@ -52,11 +58,18 @@ func NewJobService(
 	unitRepo repositories.UnitRepository,
 	juvRepo repositories.JobUnitVerificationRepository,
 	ajcRepo repositories.AgentJobCompletionRepository,
+	lotteryRepo repositories.JobLotteryRepository,
+	surgeRepo repositories.SurgePolicyRepository,
+	scoreEventRepo repositories.WorkerScoreEventRepository,
+	payItemRepo repositories.JobPayItemRepository,
+	photoRepo repositories.VerificationPhotoRepository,
+	uow *repositories.UnitOfWork,
 	openai *OpenAIService,
-	twilioClient *twilio.RestClient,
-	sendgridClient *sendgrid.Client,
+	notifier *utils.Notifier,
+	queue *utils.JobQueue,
+	driveTimes *utils.DriveTimeCache,
 ) *JobService {
-	return &JobService{
+	s := &JobService{
 		cfg:                    cfg,
 		defRepo:                defRepo,
 		instRepo:               instRepo,
@@ -69,8 +82,17 @@ func NewJobService(
 		unitRepo:               unitRepo,
 		juvRepo:                juvRepo,
 		agentJobCompletionRepo: ajcRepo,
+		lotteryRepo:            lotteryRepo,
+		surgeRepo:              surgeRepo,
+		scoreEventRepo:         scoreEventRepo,
+		payItemRepo:            payItemRepo,
+		photoRepo:              photoRepo,
+		uow:                    uow,
 		openai:                 openai,
-		twilioClient:           twilioClient,
-		sendgridClient:         sendgridClient,
+		notifier:               notifier,
+		queue:                  queue,
+		driveTimes:             driveTimes,
 	}
+	s.registerJobHandlers()
+	return s
 }
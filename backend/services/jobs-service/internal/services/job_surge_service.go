@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/constants"
+	"github.com/poofware/mono-repo/backend/services/jobs-service/internal/dtos"
+	internal_utils "github.com/poofware/mono-repo/backend/services/jobs-service/internal/utils"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-repositories"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*──────────────────────────────────────────────────────────────────────────
+  Surge policies
+
+  Surge pricing is data: a policy lists stages (time before the no-show
+  cutoff → multiplier and optional bonus), caps, and date/weekday overrides,
+  and is attached globally, to a market, a property or a definition. With no
+  stored policy the built-in four-stage schedule from constants applies.
+──────────────────────────────────────────────────────────────────────────*/
+
+// defaultSurgePolicy mirrors the original fixed four-stage schedule.
+func defaultSurgePolicy() *models.SurgePolicy {
+	return &models.SurgePolicy{
+		Name:   "built-in default",
+		Scope:  models.PolicyScopeGlobal,
+		Active: true,
+		Stages: []models.SurgeStage{
+			{BeforeNoShowMinutes: int(constants.SurgeWindowStage1 / time.Minute), Multiplier: constants.SurgeMultiplierStage1},
+			{BeforeNoShowMinutes: int(constants.SurgeWindowStage2 / time.Minute), Multiplier: constants.SurgeMultiplierStage2},
+			{BeforeNoShowMinutes: int(constants.SurgeWindowStage3 / time.Minute), Multiplier: constants.SurgeMultiplierStage3},
+			{BeforeNoShowMinutes: int(constants.SurgeWindowStage4 / time.Minute), Multiplier: constants.SurgeMultiplierStage4},
+		},
+		MaxMultiplier: constants.SurgeMultiplierStage4,
+	}
+}
+
+// propertyMarket is prop's market key, or "" when it has none.
+func propertyMarket(prop *models.Property) string {
+	if prop == nil || prop.Market == nil {
+		return ""
+	}
+	return *prop.Market
+}
+
+// effectiveSurgePolicy resolves the policy for a job from the given active
+// policies, falling back to the built-in default.
+func effectiveSurgePolicy(
+	policies []*models.SurgePolicy,
+	defn *models.JobDefinition,
+	prop *models.Property,
+) *models.SurgePolicy {
+	if p := models.ResolveSurgePolicy(policies, defn.ID, defn.PropertyID, propertyMarket(prop)); p != nil {
+		return p
+	}
+	return defaultSurgePolicy()
+}
+
+// activeSurgePolicies loads stored policies; on error it logs and returns
+// none so the built-in default keeps surges running.
+func (s *JobService) activeSurgePolicies(ctx context.Context) []*models.SurgePolicy {
+	if s.surgeRepo == nil {
+		return nil
+	}
+	policies, err := s.surgeRepo.ListActive(ctx)
+	if err != nil {
+		utils.Logger.WithError(err).Warn("Failed to load surge policies; using built-in default")
+		return nil
+	}
+	return policies
+}
+
+// surgedPay is the instance pay for a multiplier and bonus under policy's
+// caps. Bonus and MaxPay refer to a whole job and are pro-rated for segments.
+func surgedPay(
+	defn *models.JobDefinition,
+	inst *models.JobInstance,
+	policy *models.SurgePolicy,
+	multiplier float64,
+	bonus models.Money,
+) (models.Money, bool) {
+	daily := defn.GetDailyEstimate(inst.ServiceDate.Weekday())
+	if daily == nil || !daily.BasePay.IsPositive() {
+		return models.Money{}, false
+	}
+	if policy.MaxMultiplier > 0 && multiplier > policy.MaxMultiplier {
+		multiplier = policy.MaxMultiplier
+	}
+	full := daily.BasePay.Mul(multiplier).Add(bonus)
+	if policy.MaxPay != nil {
+		full = models.MinMoney(full, *policy.MaxPay)
+	}
+	return defn.SegmentPay(full, inst.SegmentIndex), true
+}
+
+// applySurge raises inst's pay through instRepo if the surged amount beats
+// its current pay, recording the raise in the pay ledger as an item of kind
+// (SURGE, or MANUAL_BONUS when ops asked for it).
+func (s *JobService) applySurge(
+	ctx context.Context,
+	instRepo repositories.JobInstanceRepository,
+	inst *models.JobInstance,
+	defn *models.JobDefinition,
+	policy *models.SurgePolicy,
+	multiplier float64,
+	bonus models.Money,
+	kind models.PayItemKind,
+	actorID *uuid.UUID,
+) (oldPay, newPay models.Money, applied bool) {
+	if inst.Status != models.InstanceStatusOpen {
+		return inst.EffectivePay, inst.EffectivePay, false
+	}
+	newPay, ok := surgedPay(defn, inst, policy, multiplier, bonus)
+	if !ok {
+		utils.Logger.Warnf("applySurge: No daily estimate found for job_definition_id=%s, day_of_week=%s", defn.ID, inst.ServiceDate.Weekday())
+		return inst.EffectivePay, inst.EffectivePay, false
+	}
+
+	latest, _ := instRepo.GetByID(ctx, inst.ID)
+	if latest == nil || latest.Status != models.InstanceStatusOpen || newPay.Cmp(latest.EffectivePay) <= 0 {
+		return inst.EffectivePay, inst.EffectivePay, false
+	}
+	item := &models.JobPayItem{
+		JobInstanceID: latest.ID,
+		Kind:          kind,
+		Amount:        newPay.Sub(latest.EffectivePay),
+		Description:   fmt.Sprintf("%s (%gx)", kind.Label(), multiplier),
+		ActorID:       actorID,
+		Details: map[string]any{
+			"multiplier": multiplier,
+			"bonus":      bonus,
+			"policy":     policy.Name,
+		},
+	}
+	if policy.ID != uuid.Nil {
+		item.SurgePolicyID = &policy.ID
+	}
+	if _, err := instRepo.AddPayItemAtomic(ctx, item, latest.RowVersion, []models.InstanceStatusType{models.InstanceStatusOpen}); err != nil {
+		return latest.EffectivePay, latest.EffectivePay, false
+	}
+	return latest.EffectivePay, newPay, true
+}
+
+/*──────────── ops API ────────────*/
+
+// OpsUserIDs lists the users who may manage surge pricing.
+func (s *JobService) OpsUserIDs() []string {
+	return s.cfg.LDFlag_OpsUserIDs
+}
+
+// validatePolicyScope checks that scopeKey fits scope for any ops policy.
+func validatePolicyScope(scope models.PolicyScopeType, scopeKey string) error {
+	switch scope {
+	case models.PolicyScopeGlobal:
+		if scopeKey != "" {
+			return fmt.Errorf("%w: GLOBAL policies take no scope_key", internal_utils.ErrInvalidPayload)
+		}
+	case models.PolicyScopeMarket:
+		if scopeKey == "" {
+			return fmt.Errorf("%w: MARKET policies need a market name in scope_key", internal_utils.ErrInvalidPayload)
+		}
+	case models.PolicyScopeProperty, models.PolicyScopeDefinition:
+		if _, err := uuid.Parse(scopeKey); err != nil {
+			return fmt.Errorf("%w: scope_key must be a UUID for %s policies", internal_utils.ErrInvalidPayload, scope)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scope %q", internal_utils.ErrInvalidPayload, scope)
+	}
+	return nil
+}
+
+func validateSurgePolicy(req dtos.SurgePolicyRequest) error {
+	if err := validatePolicyScope(req.Scope, req.ScopeKey); err != nil {
+		return err
+	}
+
+	checkStages := func(stages []models.SurgeStage) error {
+		for _, st := range stages {
+			if st.BeforeNoShowMinutes <= 0 || st.Multiplier < 1 || st.Bonus.IsNegative() {
+				return fmt.Errorf("%w: stages need before_no_show_minutes > 0, multiplier >= 1, bonus >= 0", internal_utils.ErrInvalidPayload)
+			}
+		}
+		return nil
+	}
+	if err := checkStages(req.Stages); err != nil {
+		return err
+	}
+	for _, o := range req.Overrides {
+		if len(o.Dates) == 0 && len(o.DaysOfWeek) == 0 {
+			return fmt.Errorf("%w: override %q needs dates or days_of_week", internal_utils.ErrInvalidPayload, o.Name)
+		}
+		for _, d := range o.Dates {
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return fmt.Errorf("%w: override date %q must be YYYY-MM-DD", internal_utils.ErrInvalidPayload, d)
+			}
+		}
+		for _, d := range o.DaysOfWeek {
+			if d < 0 || d > 6 {
+				return fmt.Errorf("%w: days_of_week must be 0 (Sunday) to 6", internal_utils.ErrInvalidPayload)
+			}
+		}
+		if err := checkStages(o.Stages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertSurgePolicy creates or replaces a policy and records who did it.
+func (s *JobService) UpsertSurgePolicy(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.SurgePolicyRequest,
+) (*models.SurgePolicy, error) {
+	if err := validateSurgePolicy(req); err != nil {
+		return nil, err
+	}
+
+	action := models.SurgeAuditPolicyCreated
+	policy := &models.SurgePolicy{ID: uuid.New(), CreatedBy: &actorID, Active: true}
+	var before *models.SurgePolicy
+	if req.ID != nil {
+		existing, err := s.surgeRepo.GetByID(ctx, *req.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, internal_utils.ErrSurgePolicyNotFound
+		}
+		snapshot := *existing
+		before, policy = &snapshot, existing
+		action = models.SurgeAuditPolicyUpdated
+	}
+
+	policy.Name = req.Name
+	policy.Scope = req.Scope
+	policy.ScopeKey = req.ScopeKey
+	policy.Stages = req.Stages
+	policy.MaxMultiplier = req.MaxMultiplier
+	policy.MaxPay = nil
+	if req.MaxPay != nil {
+		maxPay := models.MoneyFromDollars(*req.MaxPay)
+		policy.MaxPay = &maxPay
+	}
+	policy.Overrides = req.Overrides
+	if req.Active != nil {
+		policy.Active = *req.Active
+	}
+	if before != nil && before.Active && !policy.Active {
+		action = models.SurgeAuditPolicyDeactivated
+	}
+
+	// The policy and its audit event are saved together or not at all.
+	err := s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		repo := w.SurgePolicies()
+		var err error
+		if req.ID == nil {
+			err = repo.Create(ctx, policy)
+		} else {
+			err = repo.Update(ctx, policy)
+		}
+		if err != nil {
+			return err
+		}
+
+		details := map[string]any{"after": policy}
+		if before != nil {
+			details["before"] = before
+		}
+		return repo.CreateAuditEvent(ctx, &models.SurgeAuditEvent{
+			ID:       uuid.New(),
+			Action:   action,
+			ActorID:  actorID,
+			PolicyID: &policy.ID,
+			Reason:   req.Reason,
+			Details:  details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *JobService) ListSurgePolicies(ctx context.Context) ([]*models.SurgePolicy, error) {
+	return s.surgeRepo.ListAll(ctx)
+}
+
+func (s *JobService) ListSurgeAuditEvents(
+	ctx context.Context,
+	policyID, instanceID *uuid.UUID,
+	limit int,
+) ([]*models.SurgeAuditEvent, error) {
+	return s.surgeRepo.ListAuditEvents(ctx, policyID, instanceID, limit)
+}
+
+// ApplyManualSurge lets ops raise pay on one open job. The job's effective
+// policy still caps the result, and the change is recorded in the audit log
+// whether or not it raised pay. The raise and its audit event commit
+// together; if the event can't be written, pay is left alone.
+func (s *JobService) ApplyManualSurge(
+	ctx context.Context,
+	actorID uuid.UUID,
+	req dtos.ManualSurgeRequest,
+) (*dtos.ManualSurgeResponse, error) {
+	inst, err := s.instRepo.GetByID(ctx, req.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	if inst == nil {
+		return nil, internal_utils.ErrInstanceNotFound
+	}
+	if inst.Status != models.InstanceStatusOpen {
+		return nil, internal_utils.ErrWrongStatus
+	}
+	defn, err := s.defRepo.GetByID(ctx, inst.DefinitionID)
+	if err != nil || defn == nil {
+		return nil, fmt.Errorf("job definition not found")
+	}
+	prop, _ := s.propRepo.GetByID(ctx, defn.PropertyID)
+
+	policy := effectiveSurgePolicy(s.activeSurgePolicies(ctx), defn, prop)
+	var resp *dtos.ManualSurgeResponse
+	err = s.uow.Run(ctx, func(ctx context.Context, w *repositories.Work) error {
+		oldPay, newPay, applied := s.applySurge(ctx, w.JobInstances(), inst, defn, policy, req.Multiplier, models.MoneyFromDollars(req.Bonus), models.PayItemManualBonus, &actorID)
+		resp = &dtos.ManualSurgeResponse{
+			InstanceID: inst.ID,
+			Applied:    applied,
+			OldPay:     oldPay,
+			NewPay:     newPay,
+		}
+		if policy.ID != uuid.Nil {
+			resp.PolicyID = &policy.ID
+		}
+
+		event := &models.SurgeAuditEvent{
+			ID:         uuid.New(),
+			Action:     models.SurgeAuditManualSurge,
+			ActorID:    actorID,
+			PolicyID:   resp.PolicyID,
+			InstanceID: &inst.ID,
+			OldPay:     &oldPay,
+			NewPay:     &newPay,
+			Reason:     req.Reason,
+			Details: map[string]any{
+				"requested_multiplier": req.Multiplier,
+				"requested_bonus":      req.Bonus,
+				"applied":              applied,
+			},
+		}
+		if err := w.SurgePolicies().CreateAuditEvent(ctx, event); err != nil {
+			return err
+		}
+		resp.AuditID = &event.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+func TestSurgedPayCaps(t *testing.T) {
+	serviceDate := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	defn := &models.JobDefinition{
+		ID: uuid.New(),
+		DailyPayEstimates: []models.DailyPayEstimate{
+			{DayOfWeek: serviceDate.Weekday(), BasePay: models.USD(10000)},
+		},
+	}
+	segmented := *defn
+	segmented.Segments = []models.JobSegment{{Index: 0, UnitCount: 3}, {Index: 1, UnitCount: 1}}
+	segmented.TotalUnits = 4
+	maxPay := models.USD(13000)
+
+	for name, tc := range map[string]struct {
+		defn    *models.JobDefinition
+		segment int
+		policy  *models.SurgePolicy
+		mult    float64
+		bonus   int64
+		want    int64
+	}{
+		"multiplier and bonus":       {defn: defn, policy: &models.SurgePolicy{}, mult: 1.2, bonus: 250, want: 12250},
+		"multiplier capped":          {defn: defn, policy: &models.SurgePolicy{MaxMultiplier: 1.5}, mult: 2, want: 15000},
+		"max pay caps the total":     {defn: defn, policy: &models.SurgePolicy{MaxPay: &maxPay}, mult: 1.2, bonus: 2000, want: 13000},
+		"under max pay is unchanged": {defn: defn, policy: &models.SurgePolicy{MaxPay: &maxPay}, mult: 1.1, want: 11000},
+		"max pay is pro-rated": {
+			defn: &segmented, segment: 0, policy: &models.SurgePolicy{MaxPay: &maxPay}, mult: 1.5, want: 9750,
+		},
+		"bonus is pro-rated": {defn: &segmented, segment: 1, policy: &models.SurgePolicy{}, mult: 1, bonus: 400, want: 2600},
+	} {
+		inst := &models.JobInstance{ServiceDate: serviceDate, SegmentIndex: tc.segment}
+		got, ok := surgedPay(tc.defn, inst, tc.policy, tc.mult, models.USD(tc.bonus))
+		if !ok || got.Cents != tc.want {
+			t.Errorf("%s: expected %d cents, got %d (ok=%v)", name, tc.want, got.Cents, ok)
+		}
+	}
+
+	if _, ok := surgedPay(defn, &models.JobInstance{ServiceDate: serviceDate.AddDate(0, 0, 1)}, &models.SurgePolicy{}, 1.2, models.USD(0)); ok {
+		t.Errorf("expected no surge without a daily estimate")
+	}
+}
@@ -33,6 +33,17 @@ var (
 	ErrMismatchedPayEstimatesFrequency = errors.New("mismatched_pay_estimates_frequency")
 	ErrMissingPayEstimateInput         = errors.New("missing_pay_estimate_input")
 	ErrInvalidPayload                  = errors.New("invalid_payload") // More generic for other payload issues
+
+	ErrDefinitionNotFound   = errors.New("definition_not_found")
+	ErrNotDefinitionOwner   = errors.New("not_definition_owner")
+	ErrInvalidCursor        = errors.New("invalid_cursor")
+	ErrInstanceNotFound     = errors.New("instance_not_found")
+	ErrLotteryEntryNotFound = errors.New("lottery_entry_not_found")
+	ErrSurgePolicyNotFound  = errors.New("surge_policy_not_found")
+	ErrWorkerNotFound       = errors.New("worker_not_found")
+
+	ErrEscalationPolicyNotFound = errors.New("escalation_policy_not_found")
+	ErrNoDailyEstimate          = errors.New("no_daily_estimate")
 )
 
 /*
@@ -51,3 +62,20 @@ func (e *RowVersionConflictError) Error() string {
 func NewRowVersionConflictError(current *models.JobInstance) error {
 	return &RowVersionConflictError{Current: current}
 }
+
+/*
+   LotteryPendingError is returned by accept when the job is allocated by
+   lottery: the request was recorded as Entry and will be resolved when the
+   entry window closes.
+*/
+type LotteryPendingError struct {
+	Entry *models.JobLotteryEntry
+}
+
+func (e *LotteryPendingError) Error() string {
+	return "lottery_pending"
+}
+
+func NewLotteryPendingError(entry *models.JobLotteryEntry) error {
+	return &LotteryPendingError{Entry: entry}
+}
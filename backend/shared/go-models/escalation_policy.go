@@ -0,0 +1,168 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationAudienceType is who a step notifies.
+type EscalationAudienceType string
+
+const (
+	EscalationAudienceNone            EscalationAudienceType = ""
+	EscalationAudienceInternal        EscalationAudienceType = "INTERNAL"
+	EscalationAudienceOnCallAgents    EscalationAudienceType = "ON_CALL_AGENTS"
+	EscalationAudiencePropertyManager EscalationAudienceType = "PROPERTY_MANAGER"
+	EscalationAudienceOfferedWorkers  EscalationAudienceType = "OFFERED_WORKERS"
+)
+
+// EscalationChannelType is how a step reaches its audience. The internal team
+// is always emailed and on-call agents always get both, so the channel only
+// narrows delivery to property managers and workers.
+type EscalationChannelType string
+
+const (
+	EscalationChannelEmail EscalationChannelType = "EMAIL"
+	EscalationChannelSMS   EscalationChannelType = "SMS"
+	EscalationChannelAll   EscalationChannelType = "ALL"
+)
+
+// EscalationActionType is what a step does to the job before notifying.
+type EscalationActionType string
+
+const (
+	EscalationActionNotify EscalationActionType = "NOTIFY"
+	EscalationActionSurge  EscalationActionType = "SURGE"
+	EscalationActionReopen EscalationActionType = "REOPEN"
+	EscalationActionCancel EscalationActionType = "CANCEL"
+)
+
+// EscalationStep fires once per instance when the current time reaches the
+// job's latest start plus OffsetMinutes (negative = before) while the instance
+// is in one of Statuses. REOPEN only fires for assigned jobs that have not
+// been checked in. Title and Message may use {property} and {latest_start}.
+type EscalationStep struct {
+	Key           string                 `json:"key"`
+	OffsetMinutes int                    `json:"offset_minutes"`
+	Statuses      []InstanceStatusType   `json:"statuses"`
+	Action        EscalationActionType   `json:"action"`
+	Audience      EscalationAudienceType `json:"audience,omitempty"`
+	Channel       EscalationChannelType  `json:"channel,omitempty"`
+	Title         string                 `json:"title,omitempty"`
+	Message       string                 `json:"message,omitempty"`
+
+	// SURGE only: raise pay to base * Multiplier + Bonus, capped by the job's
+	// surge policy.
+	Multiplier float64 `json:"multiplier,omitempty"`
+	Bonus      Money   `json:"bonus,omitzero"`
+}
+
+// Due reports whether the step's time has come for a job with latest start
+// lStart.
+func (st EscalationStep) Due(lStart, now time.Time) bool {
+	return !now.Before(lStart.Add(time.Duration(st.OffsetMinutes) * time.Minute))
+}
+
+// AppliesTo reports whether the step fires for an instance in its current state.
+func (st EscalationStep) AppliesTo(inst *JobInstance) bool {
+	if st.Action == EscalationActionReopen &&
+		(inst.Status != InstanceStatusAssigned || inst.CheckInAt != nil) {
+		return false
+	}
+	for _, s := range st.Statuses {
+		if s == inst.Status {
+			return true
+		}
+	}
+	return false
+}
+
+type EscalationPolicy struct {
+	ID       uuid.UUID        `json:"id"`
+	Name     string           `json:"name"`
+	Scope    PolicyScopeType  `json:"scope"`
+	ScopeKey string           `json:"scope_key"` // market name or property/definition ID; empty for GLOBAL
+	Steps    []EscalationStep `json:"steps"`
+	Active   bool             `json:"active"`
+
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (p *EscalationPolicy) GetID() string {
+	return p.ID.String()
+}
+
+type EscalationAuditActionType string
+
+const (
+	EscalationAuditPolicyCreated     EscalationAuditActionType = "POLICY_CREATED"
+	EscalationAuditPolicyUpdated     EscalationAuditActionType = "POLICY_UPDATED"
+	EscalationAuditPolicyDeactivated EscalationAuditActionType = "POLICY_DEACTIVATED"
+)
+
+// EscalationPolicyAuditEvent records who changed an escalation policy and
+// how; Details holds the policy "before" (on updates) and "after".
+type EscalationPolicyAuditEvent struct {
+	ID        uuid.UUID                 `json:"id"`
+	Action    EscalationAuditActionType `json:"action"`
+	ActorID   uuid.UUID                 `json:"actor_id"`
+	PolicyID  *uuid.UUID                `json:"policy_id,omitempty"`
+	Reason    string                    `json:"reason"`
+	Details   map[string]any            `json:"details,omitempty"`
+	CreatedAt time.Time                 `json:"created_at"`
+}
+
+// OrderedSteps returns the steps sorted by offset, earliest first.
+func (p *EscalationPolicy) OrderedSteps() []EscalationStep {
+	steps := append([]EscalationStep(nil), p.Steps...)
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].OffsetMinutes < steps[j].OffsetMinutes
+	})
+	return steps
+}
+
+// ResolveEscalationPolicy picks the most specific active policy for a job.
+func ResolveEscalationPolicy(
+	policies []*EscalationPolicy,
+	definitionID, propertyID uuid.UUID,
+	market string,
+) *EscalationPolicy {
+	var best *EscalationPolicy
+	bestRank := -1
+	for _, p := range policies {
+		if !p.Active {
+			continue
+		}
+		rank := PolicyScopeRank(p.Scope, p.ScopeKey, definitionID, propertyID, market)
+		if rank > bestRank || (rank == bestRank && rank >= 0 && p.UpdatedAt.After(best.UpdatedAt)) {
+			best, bestRank = p, rank
+		}
+	}
+	return best
+}
+
+type EscalationOutcomeType string
+
+const (
+	EscalationOutcomePending  EscalationOutcomeType = "PENDING"
+	EscalationOutcomeExecuted EscalationOutcomeType = "EXECUTED"
+	EscalationOutcomeSkipped  EscalationOutcomeType = "SKIPPED"
+	EscalationOutcomeFailed   EscalationOutcomeType = "FAILED"
+)
+
+// EscalationStepExecution records that a step ran for an instance. The
+// (instance, step key) pair is unique, which is what makes steps fire once.
+type EscalationStepExecution struct {
+	ID         uuid.UUID             `json:"id"`
+	InstanceID uuid.UUID             `json:"instance_id"`
+	PolicyID   *uuid.UUID            `json:"policy_id,omitempty"` // nil for the built-in policy
+	StepKey    string                `json:"step_key"`
+	Action     EscalationActionType  `json:"action"`
+	Outcome    EscalationOutcomeType `json:"outcome"`
+	Details    map[string]any        `json:"details,omitempty"`
+	ExecutedAt time.Time             `json:"executed_at"`
+}
@@ -33,6 +33,16 @@ const (
 	JobFreqCustom   JobFrequencyType = "CUSTOM"
 )
 
+// SegmentStrategyType controls how a definition's units are split into
+// separately acceptable segments for each service date.
+type SegmentStrategyType string
+
+const (
+	SegmentStrategyNone     SegmentStrategyType = "NONE"
+	SegmentStrategyBuilding SegmentStrategyType = "BUILDING"
+	SegmentStrategyFloor    SegmentStrategyType = "FLOOR"
+)
+
 /*
 ──────────────────────────────────────────────────────────────────────────────
 
@@ -92,12 +102,20 @@ type JobRequirements struct {
 // NEW: DailyPayEstimate stores pay and time specific to a day of the week.
 type DailyPayEstimate struct {
 	DayOfWeek                   time.Weekday `json:"day_of_week"` // Sunday = 0, ... , Saturday = 6
-	BasePay                     float64      `json:"base_pay"`
-	InitialBasePay              float64      `json:"initial_base_pay"`
+	BasePay                     Money        `json:"base_pay"`
+	InitialBasePay              Money        `json:"initial_base_pay"`
 	EstimatedTimeMinutes        int          `json:"estimated_time_minutes"`         // Current estimate, subject to EMA
 	InitialEstimatedTimeMinutes int          `json:"initial_estimated_time_minutes"` // Estimate at creation, for proportional pay
 }
 
+// JobSegment is one slice of a definition's assigned units. Every service date
+// gets one JobInstance per segment, each paid pro-rata by UnitCount.
+type JobSegment struct {
+	Index     int                 `json:"index"`
+	Units     []AssignedUnitGroup `json:"units"`
+	UnitCount int                 `json:"unit_count"`
+}
+
 type JobCompletionRules struct {
 	ProofPhotosRequired         bool `json:"proof_photos_required"`
 	GPSCheckinRequired          bool `json:"gps_checkin_required"`
@@ -132,6 +150,9 @@ type JobDefinition struct {
 	TotalUnits              int                 `json:"total_units"`
 	DumpsterIDs             []uuid.UUID         `json:"dumpster_ids"`
 
+	SegmentStrategy SegmentStrategyType `json:"segment_strategy"`
+	Segments        []JobSegment        `json:"segments,omitempty"`
+
 	Status    JobStatusType    `json:"status"`
 	Frequency JobFrequencyType `json:"frequency"`
 
@@ -174,3 +195,48 @@ func (j *JobDefinition) GetDailyEstimate(dayOfWeek time.Weekday) *DailyPayEstima
 	}
 	return nil
 }
+
+// IsSegmented reports whether instances of this definition are split across
+// multiple workers.
+func (j *JobDefinition) IsSegmented() bool {
+	return len(j.Segments) > 1
+}
+
+// SegmentCount returns the number of instances created per service date.
+func (j *JobDefinition) SegmentCount() int {
+	if !j.IsSegmented() {
+		return 1
+	}
+	return len(j.Segments)
+}
+
+// UnitGroupsForSegment returns the units a given segment is responsible for.
+// Unsegmented definitions always return the full assignment.
+func (j *JobDefinition) UnitGroupsForSegment(segmentIndex int) []AssignedUnitGroup {
+	if !j.IsSegmented() || segmentIndex < 0 || segmentIndex >= len(j.Segments) {
+		return j.AssignedUnitsByBuilding
+	}
+	return j.Segments[segmentIndex].Units
+}
+
+// SegmentPayShare returns the fraction of the daily base pay that belongs to
+// a segment, proportional to its unit count.
+func (j *JobDefinition) SegmentPayShare(segmentIndex int) float64 {
+	if !j.IsSegmented() || segmentIndex < 0 || segmentIndex >= len(j.Segments) || j.TotalUnits <= 0 {
+		return 1
+	}
+	return float64(j.Segments[segmentIndex].UnitCount) / float64(j.TotalUnits)
+}
+
+// SegmentPay returns a segment's part of a whole-job amount, split by unit
+// count so that the segments' pay adds up to the amount exactly.
+func (j *JobDefinition) SegmentPay(full Money, segmentIndex int) Money {
+	if !j.IsSegmented() || segmentIndex < 0 || segmentIndex >= len(j.Segments) || j.TotalUnits <= 0 {
+		return full
+	}
+	weights := make([]int64, len(j.Segments))
+	for i, seg := range j.Segments {
+		weights[i] = int64(seg.UnitCount)
+	}
+	return full.Allocate(weights...)[segmentIndex]
+}
@@ -25,7 +25,11 @@ type JobInstance struct {
 	ServiceDate      time.Time          `json:"service_date"`
 	Status           InstanceStatusType `json:"status"`
 	AssignedWorkerID *uuid.UUID         `json:"assigned_worker_id,omitempty"`
-	EffectivePay     float64            `json:"effective_pay"`
+	EffectivePay     Money              `json:"effective_pay"`
+
+	// Segmented definitions create one instance per segment for a service date.
+	SegmentIndex int `json:"segment_index"`
+	SegmentCount int `json:"segment_count"`
 
 	CheckInAt  *time.Time `json:"check_in_at,omitempty"`
 	CheckOutAt *time.Time `json:"check_out_at,omitempty"`
@@ -38,6 +42,8 @@ type JobInstance struct {
 	UpdatedAt time.Time `json:"updated_at"`
 
 	CompletedByAgentID *uuid.UUID `json:"completed_by_agent_id,omitempty"`
+	// Deprecated: warnings are now escalation_step_executions rows; these
+	// only hold values written before escalation policies existed.
 	Warning90MinSentAt *time.Time `json:"warning_90_min_sent_at,omitempty"`
 	Warning40MinSentAt *time.Time `json:"warning_40_min_sent_at,omitempty"`
 }
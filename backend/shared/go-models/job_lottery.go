@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LotteryEntryStatusType string
+
+const (
+	LotteryEntryPending LotteryEntryStatusType = "PENDING"
+	LotteryEntryWon     LotteryEntryStatusType = "WON"
+	LotteryEntryLost    LotteryEntryStatusType = "LOST"
+)
+
+// Reasons recorded on LOST entries.
+const (
+	LotteryLossNotSelected = "not_selected"
+	LotteryLossJobNotOpen  = "job_no_longer_open"
+	LotteryLossNotEligible = "not_eligible"
+)
+
+// JobLotteryEntry is one worker's accept request for a lottery-allocated
+// instance. Weight is fixed when the entry is created so the draw can be
+// replayed from the stored values.
+type JobLotteryEntry struct {
+	ID               uuid.UUID              `json:"id"`
+	InstanceID       uuid.UUID              `json:"instance_id"`
+	WorkerID         uuid.UUID              `json:"worker_id"`
+	Status           LotteryEntryStatusType `json:"status"`
+	ReliabilityScore int                    `json:"reliability_score"`
+	DistanceMiles    float64                `json:"distance_miles"`
+	Weight           float64                `json:"weight"`
+	WindowClosesAt   time.Time              `json:"window_closes_at"`
+	DrawID           *uuid.UUID             `json:"draw_id,omitempty"`
+	LossReason       *string                `json:"loss_reason,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	ResolvedAt       *time.Time             `json:"resolved_at,omitempty"`
+}
+
+func (e *JobLotteryEntry) GetID() string {
+	return e.ID.String()
+}
+
+// JobLotteryDraw is the audit record of one draw: the seed, the roll against
+// the cumulative weights, and a snapshot of every entry considered.
+type JobLotteryDraw struct {
+	ID             uuid.UUID          `json:"id"`
+	InstanceID     uuid.UUID          `json:"instance_id"`
+	Seed           int64              `json:"seed"`
+	Roll           float64            `json:"roll"`
+	TotalWeight    float64            `json:"total_weight"`
+	WinnerWorkerID *uuid.UUID         `json:"winner_worker_id,omitempty"`
+	Entries        []LotteryDrawEntry `json:"entries"`
+	DrawnAt        time.Time          `json:"drawn_at"`
+}
+
+type LotteryDrawEntry struct {
+	EntryID          uuid.UUID `json:"entry_id"`
+	WorkerID         uuid.UUID `json:"worker_id"`
+	ReliabilityScore int       `json:"reliability_score"`
+	DistanceMiles    float64   `json:"distance_miles"`
+	Weight           float64   `json:"weight"`
+	Eligible         bool      `json:"eligible"`
+}
+
+func (d *JobLotteryDraw) GetID() string {
+	return d.ID.String()
+}
+
+// MarketAllocation is the allocation mode for every property in a market.
+// A property in LOTTERY mode keeps its own lottery and window whatever its
+// market says.
+type MarketAllocation struct {
+	Market               string             `json:"market"`
+	AllocationMode       AllocationModeType `json:"allocation_mode"`
+	LotteryWindowSeconds int                `json:"lottery_window_seconds"`
+	UpdatedBy            *uuid.UUID         `json:"updated_by,omitempty"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayItemKind is the kind of a line in a job instance's pay ledger.
+type PayItemKind string
+
+const (
+	// PayItemBase is the pay the instance was created with.
+	PayItemBase PayItemKind = "BASE"
+	// PayItemSurge is a raise from a surge policy stage or escalation step.
+	PayItemSurge PayItemKind = "SURGE"
+	// PayItemManualBonus is a raise ops applied with a manual surge.
+	PayItemManualBonus PayItemKind = "MANUAL_BONUS"
+	// PayItemAdjustment is an ops correction in either direction.
+	PayItemAdjustment PayItemKind = "ADJUSTMENT"
+	// PayItemClawback takes back pay that should not have been paid.
+	PayItemClawback PayItemKind = "CLAWBACK"
+)
+
+var payItemLabels = map[PayItemKind]string{
+	PayItemBase:        "Base pay",
+	PayItemSurge:       "Surge",
+	PayItemManualBonus: "Bonus",
+	PayItemAdjustment:  "Adjustment",
+	PayItemClawback:    "Clawback",
+}
+
+// Label is a worker-facing name for the kind.
+func (k PayItemKind) Label() string {
+	if l, ok := payItemLabels[k]; ok {
+		return l
+	}
+	return "Pay"
+}
+
+// Valid reports whether amount has the sign the kind allows: clawbacks take
+// pay away, adjustments go either way, everything else adds.
+func (k PayItemKind) Valid(amount Money) bool {
+	switch k {
+	case PayItemBase, PayItemSurge, PayItemManualBonus:
+		return !amount.IsNegative()
+	case PayItemClawback:
+		return amount.IsNegative()
+	case PayItemAdjustment:
+		return true
+	}
+	return false
+}
+
+/*
+JobPayItem is one line of a job instance's append-only pay ledger. An
+instance's EffectivePay is the sum of its items; the repository keeps the
+two in step, so items are never edited and a correction is a new item.
+*/
+type JobPayItem struct {
+	ID            uuid.UUID      `json:"id"`
+	JobInstanceID uuid.UUID      `json:"job_instance_id"`
+	Kind          PayItemKind    `json:"kind"`
+	Amount        Money          `json:"amount"`
+	Description   string         `json:"description,omitempty"`
+	ActorID       *uuid.UUID     `json:"actor_id,omitempty"`
+	SurgePolicyID *uuid.UUID     `json:"surge_policy_id,omitempty"`
+	Details       map[string]any `json:"details,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// SumPayItems is the pay the items add up to.
+func SumPayItems(items []*JobPayItem) Money {
+	total := USD(0)
+	for _, it := range items {
+		total = total.Add(it.Amount)
+	}
+	return total
+}
+
+// PayKindTotal is what items of one kind added up to over a period.
+type PayKindTotal struct {
+	Kind      PayItemKind `json:"kind"`
+	Amount    Money       `json:"amount"`
+	ItemCount int         `json:"item_count"`
+	JobCount  int         `json:"job_count"`
+}
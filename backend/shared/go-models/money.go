@@ -0,0 +1,311 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const CurrencyUSD Currency = "USD"
+
+/*
+Money is an amount in whole cents (minor units) of a currency.
+
+Adding and subtracting Money is exact. Rounding only happens when an amount
+is scaled, by Mul or MulRatio, and always goes to the nearest cent with
+halves rounded away from zero. Allocate splits an amount by weights so the
+parts add back up to it exactly; use it wherever pay is shared out.
+
+The zero value is $0.00, and an empty Currency reads as USD. USD is the only
+currency stored today: database columns hold bare cents (BIGINT *_cents),
+and JSON holds the decimal amount in major units (12.34), the same wire form
+as the float fields Money replaced.
+*/
+type Money struct {
+	Cents    int64
+	Currency Currency
+}
+
+// USD is cents US cents.
+func USD(cents int64) Money {
+	return Money{Cents: cents, Currency: CurrencyUSD}
+}
+
+// MoneyFromDollars rounds a dollar amount to the nearest cent. The float is
+// read as the shortest decimal that prints it, so 0.285 is 29 cents and not
+// the 28 that 0.285*100 gives. Use it for amounts taken in as floats, such as
+// request fields and query parameters.
+func MoneyFromDollars(dollars float64) Money {
+	if math.IsNaN(dollars) || math.IsInf(dollars, 0) {
+		return USD(0)
+	}
+	m, err := ParseMoney(strconv.FormatFloat(dollars, 'f', -1, 64))
+	if err != nil {
+		return USD(int64(math.Round(dollars * 100)))
+	}
+	return m
+}
+
+// ParseMoney reads a decimal dollar amount such as "12.34" or "-0.5",
+// rounding anything past the cent.
+func ParseMoney(s string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount %q", s)
+	}
+	cents, err := roundRat(r.Mul(r, big.NewRat(100, 1)))
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid money amount %q: %w", s, err)
+	}
+	return USD(cents), nil
+}
+
+func (m Money) currency() Currency {
+	if m.Currency == "" {
+		return CurrencyUSD
+	}
+	return m.Currency
+}
+
+// sameCurrency panics when m and o are in different currencies; mixing them
+// is a programming error, not something to round away.
+func (m Money) sameCurrency(o Money) Currency {
+	if m.currency() != o.currency() {
+		panic(fmt.Sprintf("models: money currency mismatch: %s and %s", m.currency(), o.currency()))
+	}
+	return m.currency()
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Cents: m.Cents + o.Cents, Currency: m.sameCurrency(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Cents: m.Cents - o.Cents, Currency: m.sameCurrency(o)}
+}
+
+func (m Money) Neg() Money {
+	return Money{Cents: -m.Cents, Currency: m.currency()}
+}
+
+// SumMoney adds amounts up; the sum of none is $0.00.
+func SumMoney(amounts ...Money) Money {
+	total := USD(0)
+	for i, a := range amounts {
+		if i == 0 {
+			total.Currency = a.currency()
+		}
+		total = total.Add(a)
+	}
+	return total
+}
+
+// Mul scales m by factor, rounding to the nearest cent. factor is read as the
+// shortest decimal that prints it, so a 1.15 multiplier is exactly 1.15.
+func (m Money) Mul(factor float64) Money {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'g', -1, 64))
+	if !ok {
+		panic(fmt.Sprintf("models: invalid money factor %v", factor))
+	}
+	return m.mulRat(r)
+}
+
+// MulRatio is m × num / den rounded to the nearest cent, computed exactly.
+func (m Money) MulRatio(num, den int64) Money {
+	if den == 0 {
+		panic("models: money ratio with zero denominator")
+	}
+	return m.mulRat(big.NewRat(num, den))
+}
+
+func (m Money) mulRat(r *big.Rat) Money {
+	cents, err := roundRat(new(big.Rat).Mul(new(big.Rat).SetInt64(m.Cents), r))
+	if err != nil {
+		panic("models: " + err.Error())
+	}
+	return Money{Cents: cents, Currency: m.currency()}
+}
+
+// roundRat rounds r to the nearest integer, halves away from zero.
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(r.Num())
+	q, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("amount out of range")
+	}
+	return q.Int64(), nil
+}
+
+/*
+Allocate splits m into len(weights) parts in proportion to the weights. Each
+part is rounded down to the cent and the cents left over go, one each, to the
+parts that lost the most to rounding (earlier parts first on a tie), so the
+parts always add up to m.
+
+Weights must not be negative. If they add up to zero, m is split evenly.
+*/
+func (m Money) Allocate(weights ...int64) []Money {
+	if len(weights) == 0 {
+		return nil
+	}
+	var total int64
+	for _, w := range weights {
+		if w < 0 {
+			panic("models: negative money allocation weight")
+		}
+		total += w
+	}
+	if total == 0 {
+		weights = make([]int64, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = int64(len(weights))
+	}
+
+	sign := int64(1)
+	cents := m.Cents
+	if cents < 0 {
+		sign, cents = -1, -cents
+	}
+
+	parts := make([]Money, len(weights))
+	rems := make([]*big.Int, len(weights))
+	bigCents, bigTotal := big.NewInt(cents), big.NewInt(total)
+	left := cents
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(bigCents, big.NewInt(w)), bigTotal, new(big.Int))
+		parts[i] = Money{Cents: q.Int64(), Currency: m.currency()}
+		rems[i] = r
+		left -= q.Int64()
+	}
+	for ; left > 0; left-- {
+		best := -1
+		for i, r := range rems {
+			if r.Sign() >= 0 && (best < 0 || r.Cmp(rems[best]) > 0) {
+				best = i
+			}
+		}
+		parts[best].Cents++
+		rems[best].SetInt64(-1) // one extra cent per part at most
+	}
+	if sign < 0 {
+		for i := range parts {
+			parts[i].Cents = -parts[i].Cents
+		}
+	}
+	return parts
+}
+
+// Cmp compares m and o: -1 if m is less, 0 if equal, +1 if more.
+func (m Money) Cmp(o Money) int {
+	m.sameCurrency(o)
+	switch {
+	case m.Cents < o.Cents:
+		return -1
+	case m.Cents > o.Cents:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool     { return m.Cents == 0 }
+func (m Money) IsPositive() bool { return m.Cents > 0 }
+func (m Money) IsNegative() bool { return m.Cents < 0 }
+
+// MinMoney returns the smaller of a and b.
+func MinMoney(a, b Money) Money {
+	if a.Cmp(b) <= 0 {
+		return a
+	}
+	return b
+}
+
+// Dollars is m in major units, for display and logging only.
+func (m Money) Dollars() float64 {
+	return float64(m.Cents) / 100
+}
+
+// decimal formats m in major units with two places, e.g. "-12.05".
+func (m Money) decimal() string {
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+	}
+	abs := uint64(cents)
+	if cents < 0 {
+		abs = uint64(-cents)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, abs/100, abs%100)
+}
+
+// String is "$12.34" for USD and "12.34 EUR" otherwise.
+func (m Money) String() string {
+	if m.currency() == CurrencyUSD {
+		if m.Cents < 0 {
+			return "-$" + m.Neg().decimal()
+		}
+		return "$" + m.decimal()
+	}
+	return m.decimal() + " " + string(m.currency())
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.decimal()), nil
+}
+
+// UnmarshalJSON accepts a decimal amount as a JSON number or string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(bytes.TrimSpace(data), `"`)
+	if string(data) == "null" {
+		return nil
+	}
+	v, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan reads a *_cents column.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*m = USD(0)
+	case int64:
+		*m = USD(v)
+	case int32:
+		*m = USD(int64(v))
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		c, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("scan money: %w", err)
+		}
+		*m = USD(c)
+	default:
+		return fmt.Errorf("scan money: unsupported type %T", src)
+	}
+	return nil
+}
+
+// Value writes m as cents.
+func (m Money) Value() (driver.Value, error) {
+	return m.Cents, nil
+}
@@ -0,0 +1,273 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+var quickCfg = &quick.Config{MaxCount: 2000}
+
+// cents keeps generated amounts in a realistic range (±$10M).
+func cents(r *rand.Rand) int64 {
+	return r.Int63n(2_000_000_000) - 1_000_000_000
+}
+
+// withinHalfCent reports whether got is exact rounded to the nearest cent.
+func withinHalfCent(got int64, exact *big.Rat) bool {
+	diff := new(big.Rat).Sub(new(big.Rat).SetInt64(got), exact)
+	return diff.Abs(diff).Cmp(big.NewRat(1, 2)) <= 0
+}
+
+func TestMoneyAllocateAddsUp(t *testing.T) {
+	prop := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		m := USD(cents(r))
+		weights := make([]int64, 1+r.Intn(12))
+		var total int64
+		for i := range weights {
+			weights[i] = r.Int63n(500)
+			total += weights[i]
+		}
+
+		parts := m.Allocate(weights...)
+		if SumMoney(parts...) != m {
+			t.Logf("parts of %s by %v add up to %s", m, weights, SumMoney(parts...))
+			return false
+		}
+		if total == 0 {
+			return true
+		}
+		for i, p := range parts {
+			// Each part is its exact share, give or take a cent.
+			exact := big.NewRat(m.Cents*weights[i], total)
+			diff := new(big.Rat).Sub(new(big.Rat).SetInt64(p.Cents), exact)
+			if diff.Abs(diff).Cmp(big.NewRat(1, 1)) >= 0 {
+				t.Logf("part %d of %s by %v is %s", i, m, weights, p)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(prop, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyMulRoundsToNearestCent(t *testing.T) {
+	prop := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		m := USD(cents(r))
+		thousandths := 1000 + r.Int63n(4001) // surge multipliers 1.000–5.000
+		factor := float64(thousandths) / 1000
+
+		got := m.Mul(factor)
+		exact := big.NewRat(m.Cents*thousandths, 1000)
+		if !withinHalfCent(got.Cents, exact) {
+			t.Logf("%s × %v = %s", m, factor, got)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(prop, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyMulRoundsHalvesAwayFromZero(t *testing.T) {
+	cases := []struct {
+		cents  int64
+		factor float64
+		want   int64
+	}{
+		{10, 1.15, 12},   // 11.5; float math gives 11.499…
+		{-10, 1.15, -12}, // symmetric
+		{1005, 1.5, 1508},
+		{333, 1.0, 333},
+		{1, 0.5, 1},
+		{1, 0.49, 0},
+	}
+	for _, c := range cases {
+		if got := USD(c.cents).Mul(c.factor); got.Cents != c.want {
+			t.Errorf("USD(%d).Mul(%v) = %d cents, want %d", c.cents, c.factor, got.Cents, c.want)
+		}
+	}
+}
+
+// Surged pay for every segment of a job adds up to the surged whole-job
+// pay, to the cent.
+func TestSurgedSegmentPayReconciles(t *testing.T) {
+	prop := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		defn := &JobDefinition{}
+		for i := range 2 + r.Intn(6) {
+			n := 1 + r.Intn(40)
+			defn.Segments = append(defn.Segments, JobSegment{Index: i, UnitCount: n})
+			defn.TotalUnits += n
+		}
+		base := USD(100 + r.Int63n(50_000))
+		multiplier := float64(1000+r.Int63n(2001)) / 1000
+		bonus := USD(r.Int63n(2_000))
+
+		full := base.Mul(multiplier).Add(bonus)
+		var sum Money
+		for i := range defn.Segments {
+			sum = sum.Add(defn.SegmentPay(full, i))
+		}
+		if sum != full {
+			t.Logf("segments of %s add up to %s", full, sum)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(prop, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+// EMA-proportional pay (initial pay × new estimate / initial estimate) is
+// the exact ratio rounded to the cent, and an unchanged estimate leaves pay
+// untouched.
+func TestProportionalPayIsExact(t *testing.T) {
+	prop := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		initial := USD(1 + r.Int63n(100_000))
+		initialMins := 1 + r.Int63n(240)
+		newMins := 1 + r.Int63n(480)
+
+		got := initial.MulRatio(newMins, initialMins)
+		if !withinHalfCent(got.Cents, big.NewRat(initial.Cents*newMins, initialMins)) {
+			t.Logf("%s × %d/%d = %s", initial, newMins, initialMins, got)
+			return false
+		}
+		return initial.MulRatio(initialMins, initialMins) == initial
+	}
+	if err := quick.Check(prop, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+// A payout is the sum of its jobs' pay, whether summed as Money or as the
+// cents that reach the payout row, and survives the JSON round trip jobs
+// take through the outbox.
+func TestPayoutSumReconciles(t *testing.T) {
+	prop := func(seed int64) bool {
+		r := rand.New(rand.NewSource(seed))
+		jobs := make([]Money, r.Intn(60))
+		var cents int64
+		for i := range jobs {
+			pay := USD(r.Int63n(20_000))
+			data, err := json.Marshal(JobInstanceEvent{EffectivePay: pay})
+			if err != nil {
+				return false
+			}
+			var ev JobInstanceEvent
+			if err := json.Unmarshal(data, &ev); err != nil || ev.EffectivePay != pay {
+				t.Logf("%s came back from JSON as %s", pay, ev.EffectivePay)
+				return false
+			}
+			jobs[i] = ev.EffectivePay
+			cents += ev.EffectivePay.Cents
+		}
+		return SumMoney(jobs...).Cents == cents
+	}
+	if err := quick.Check(prop, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyFromDollars(t *testing.T) {
+	cases := map[float64]int64{
+		0.29:   29, // int64(0.29 * 100) is 28
+		0.285:  29,
+		-0.285: -29,
+		19.99:  1999,
+		1e-9:   0,
+		100:    10000,
+	}
+	for d, want := range cases {
+		if got := MoneyFromDollars(d); got != USD(want) {
+			t.Errorf("MoneyFromDollars(%v) = %d cents, want %d", d, got.Cents, want)
+		}
+	}
+
+	roundTrip := func(c int32) bool {
+		m := USD(int64(c))
+		return MoneyFromDollars(m.Dollars()) == m
+	}
+	if err := quick.Check(roundTrip, quickCfg); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want int64
+	}{
+		{`12.34`, 1234},
+		{`"12.34"`, 1234},
+		{`10`, 1000},
+		{`0.005`, 1},
+		{`-3.1`, -310},
+	} {
+		var m Money
+		if err := json.Unmarshal([]byte(c.in), &m); err != nil {
+			t.Fatalf("unmarshal %s: %v", c.in, err)
+		}
+		if m != USD(c.want) {
+			t.Errorf("unmarshal %s = %d cents, want %d", c.in, m.Cents, c.want)
+		}
+	}
+	if err := json.Unmarshal([]byte(`"abc"`), new(Money)); err == nil {
+		t.Error("unmarshal of a non-number succeeded")
+	}
+
+	data, _ := json.Marshal(struct {
+		Pay Money `json:"pay"`
+	}{USD(-1205)})
+	if string(data) != `{"pay":-12.05}` {
+		t.Errorf("marshal = %s", data)
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	for m, want := range map[Money]string{
+		USD(0):                        "$0.00",
+		USD(1234):                     "$12.34",
+		USD(-5):                       "-$0.05",
+		{Cents: 100}:                  "$1.00",
+		{Cents: 250, Currency: "EUR"}: "2.50 EUR",
+	} {
+		if got := m.String(); got != want {
+			t.Errorf("%#v.String() = %q, want %q", m, got, want)
+		}
+	}
+}
+
+func TestMoneyCurrencyMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("adding USD and EUR did not panic")
+		}
+	}()
+	USD(1).Add(Money{Cents: 1, Currency: "EUR"})
+}
+
+func TestMoneyScan(t *testing.T) {
+	var m Money
+	for _, src := range []any{int64(1234), []byte("1234"), "1234"} {
+		if err := m.Scan(src); err != nil || m != USD(1234) {
+			t.Errorf("Scan(%#v) = %v, %v", src, m, err)
+		}
+	}
+	if err := m.Scan(nil); err != nil || !m.IsZero() {
+		t.Errorf("Scan(nil) = %v, %v", m, err)
+	}
+	if v, _ := USD(99).Value(); !reflect.DeepEqual(v, int64(99)) {
+		t.Errorf("Value() = %#v", v)
+	}
+}
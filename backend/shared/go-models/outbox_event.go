@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event topics written to the outbox. A topic names what happened,
+// not who should react to it.
+const (
+	EventJobCompleted       = "job.completed"
+	EventJobCanceled        = "job.canceled"
+	EventJobPayChanged      = "job.pay_changed"
+	EventWorkerScoreChanged = "worker.score_changed"
+	EventWorkerBanned       = "worker.banned"
+	EventPayoutPaid         = "payout.paid"
+	EventPayoutFailed       = "payout.failed"
+)
+
+// OutboxEvent is a domain event stored in outbox_events. Events are ordered
+// by (TxID, ID): TxID is the id of the transaction that wrote the event, so
+// an event can never appear behind a consumer's offset once it is readable.
+type OutboxEvent struct {
+	ID          int64           `json:"id"`
+	TxID        int64           `json:"tx_id"`
+	Topic       string          `json:"topic"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// JobInstanceEvent is the payload of job.completed and job.canceled.
+type JobInstanceEvent struct {
+	InstanceID         uuid.UUID          `json:"instance_id"`
+	DefinitionID       uuid.UUID          `json:"definition_id"`
+	ServiceDate        time.Time          `json:"service_date"`
+	Status             InstanceStatusType `json:"status"`
+	AssignedWorkerID   *uuid.UUID         `json:"assigned_worker_id,omitempty"`
+	CompletedByAgentID *uuid.UUID         `json:"completed_by_agent_id,omitempty"`
+	EffectivePay       Money              `json:"effective_pay"`
+}
+
+// NewJobInstanceEvent captures the event payload for inst.
+func NewJobInstanceEvent(inst *JobInstance) JobInstanceEvent {
+	return JobInstanceEvent{
+		InstanceID:         inst.ID,
+		DefinitionID:       inst.DefinitionID,
+		ServiceDate:        inst.ServiceDate,
+		Status:             inst.Status,
+		AssignedWorkerID:   inst.AssignedWorkerID,
+		CompletedByAgentID: inst.CompletedByAgentID,
+		EffectivePay:       inst.EffectivePay,
+	}
+}
+
+// JobPayChangedEvent is the payload of job.pay_changed, published when an
+// item is added to a completed job's pay ledger. EffectivePay is the new
+// total and Item the line that changed it.
+type JobPayChangedEvent struct {
+	JobInstanceEvent
+	Item JobPayItem `json:"item"`
+}
+
+// WorkerScoreEvent is the payload of worker.score_changed and worker.banned.
+type WorkerScoreEvent struct {
+	ScoreEventID   uuid.UUID  `json:"score_event_id"`
+	WorkerID       uuid.UUID  `json:"worker_id"`
+	EventType      string     `json:"event_type"`
+	Delta          int        `json:"delta"`
+	OldScore       int        `json:"old_score"`
+	NewScore       int        `json:"new_score"`
+	JobInstanceID  *uuid.UUID `json:"job_instance_id,omitempty"`
+	IsBanned       bool       `json:"is_banned"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+}
+
+// PayoutEvent is the payload of payout.paid and payout.failed.
+type PayoutEvent struct {
+	PayoutID       uuid.UUID   `json:"payout_id"`
+	WorkerID       uuid.UUID   `json:"worker_id"`
+	PeriodStart    time.Time   `json:"period_start"`
+	PeriodEnd      time.Time   `json:"period_end"`
+	AmountCents    int64       `json:"amount_cents"`
+	Status         string      `json:"status"`
+	FailureReason  *string     `json:"failure_reason,omitempty"`
+	JobInstanceIDs []uuid.UUID `json:"job_instance_ids"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "github.com/google/uuid"
+
+// PolicyScopeType says what an ops-managed policy (surge, escalation) is
+// attached to. When several policies match a job the most specific one wins:
+// DEFINITION, PROPERTY, MARKET, then GLOBAL.
+type PolicyScopeType string
+
+const (
+	PolicyScopeGlobal     PolicyScopeType = "GLOBAL"
+	PolicyScopeMarket     PolicyScopeType = "MARKET"
+	PolicyScopeProperty   PolicyScopeType = "PROPERTY"
+	PolicyScopeDefinition PolicyScopeType = "DEFINITION"
+)
+
+// PolicyScopeRank returns how specifically a policy with the given scope and
+// key matches a job, or -1 if it does not match at all.
+func PolicyScopeRank(
+	scope PolicyScopeType,
+	scopeKey string,
+	definitionID, propertyID uuid.UUID,
+	market string,
+) int {
+	switch scope {
+	case PolicyScopeDefinition:
+		if scopeKey == definitionID.String() {
+			return 3
+		}
+	case PolicyScopeProperty:
+		if scopeKey == propertyID.String() {
+			return 2
+		}
+	case PolicyScopeMarket:
+		if market != "" && scopeKey == market {
+			return 1
+		}
+	case PolicyScopeGlobal:
+		return 0
+	}
+	return -1
+}
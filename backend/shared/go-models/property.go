@@ -5,6 +5,15 @@ import (
     "github.com/google/uuid"
 )
 
+// AllocationModeType controls how a property's newly released jobs are handed
+// out: first accept wins, or a weighted lottery over a short entry window.
+type AllocationModeType string
+
+const (
+    AllocationModeFirstCome AllocationModeType = "FIRST_COME"
+    AllocationModeLottery   AllocationModeType = "LOTTERY"
+)
+
 type Property struct {
     ID           uuid.UUID         `json:"id"`
     ManagerID    uuid.UUID         `json:"manager_id"`
@@ -17,5 +26,8 @@ type Property struct {
     Latitude     float64          `json:"latitude"`
     Longitude    float64          `json:"longitude"`
     IsDemo       bool              `json:"is_demo"`
+    Market       *string           `json:"market,omitempty"` // groups properties for market-wide policies
+    AllocationMode       *AllocationModeType `json:"allocation_mode,omitempty"` // nil follows the market
+    LotteryWindowSeconds int                `json:"lottery_window_seconds"`
     CreatedAt    time.Time         `json:"created_at"`
 }
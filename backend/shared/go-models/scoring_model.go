@@ -0,0 +1,212 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+/*
+ScoringModel decides how score events move a worker's reliability score.
+
+Events named in Points are worth that many points under the model, whatever
+was recorded. Penalties sized by timing (UNACCEPT,
+CANCEL_IN_PROGRESS_REVERT) and ops adjustments keep their recorded delta.
+
+A penalty fades: after PenaltyHalfLifeDays half of it has been given back,
+after twice that three quarters, and so on. Ops adjustments don't fade.
+During a worker's first NewWorkerDays days no event takes the score below
+NewWorkerFloor.
+
+Score replays a ledger under the model, which is how a candidate model is
+tried out against real history. DecayOwed works out how much faded penalty
+is still to be given back, which is all the nightly decay applies.
+*/
+type ScoringModel struct {
+	Name                string         `json:"name"`
+	InitialScore        int            `json:"initial_score"`
+	MinScore            int            `json:"min_score"`
+	MaxScore            int            `json:"max_score"`
+	Points              map[string]int `json:"points"`
+	PenaltyHalfLifeDays float64        `json:"penalty_half_life_days"` // 0 = penalties never fade
+	NewWorkerDays       int            `json:"new_worker_days"`
+	NewWorkerFloor      int            `json:"new_worker_floor"`
+}
+
+// DefaultScoringModel keeps the original penalty sizes and adds small
+// rewards for good work. New workers can be suspended but not banned in
+// their first 30 days.
+func DefaultScoringModel() *ScoringModel {
+	return &ScoringModel{
+		Name:         "default",
+		InitialScore: 100,
+		MinScore:     0,
+		MaxScore:     100,
+		Points: map[string]int{
+			ScoreEventNoShow:               -20,
+			ScoreEventUnacceptLateCancel:   -20,
+			ScoreEventCancelInProgressLate: -20,
+			ScoreEventOnTimeCompletion:     1,
+			ScoreEventCleanVerification:    1,
+			ScoreEventSurgePickup:          2,
+		},
+		PenaltyHalfLifeDays: 30,
+		NewWorkerDays:       30,
+		NewWorkerFloor:      61,
+	}
+}
+
+// ParseScoringModel reads a model from JSON. Fields left out keep their
+// DefaultScoringModel values; Points entries are merged over the defaults.
+func ParseScoringModel(data []byte) (*ScoringModel, error) {
+	m := DefaultScoringModel()
+	defaults := m.Points
+	m.Points = nil
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid scoring model: %w", err)
+	}
+	for k, v := range m.Points {
+		defaults[k] = v
+	}
+	m.Points = defaults
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *ScoringModel) Validate() error {
+	switch {
+	case m.MinScore > m.MaxScore:
+		return fmt.Errorf("scoring model %q: min_score above max_score", m.Name)
+	case m.InitialScore < m.MinScore || m.InitialScore > m.MaxScore:
+		return fmt.Errorf("scoring model %q: initial_score outside [min_score, max_score]", m.Name)
+	case m.NewWorkerFloor > m.MaxScore:
+		return fmt.Errorf("scoring model %q: new_worker_floor above max_score", m.Name)
+	case m.PenaltyHalfLifeDays < 0 || m.NewWorkerDays < 0:
+		return fmt.Errorf("scoring model %q: negative duration", m.Name)
+	}
+	return nil
+}
+
+// PointsFor is what an event of this type is worth; recorded is the delta
+// to use when the model has no fixed value for it.
+func (m *ScoringModel) PointsFor(eventType string, recorded int) int {
+	if eventType == ScoreEventManualAdjustment {
+		return recorded
+	}
+	if p, ok := m.Points[eventType]; ok {
+		return p
+	}
+	return recorded
+}
+
+// Floor is the lowest score an event at time at may leave.
+func (m *ScoringModel) Floor(workerCreatedAt, at time.Time) int {
+	if m.NewWorkerDays > 0 && at.Before(workerCreatedAt.AddDate(0, 0, m.NewWorkerDays)) {
+		return max(m.NewWorkerFloor, m.MinScore)
+	}
+	return m.MinScore
+}
+
+// fades reports whether an event's points are given back over time.
+func (m *ScoringModel) fades(eventType string, points int) bool {
+	return points < 0 && m.PenaltyHalfLifeDays > 0 && eventType != ScoreEventManualAdjustment
+}
+
+// remaining is the fraction of a fading penalty still in force after age.
+func (m *ScoringModel) remaining(age time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	halfLife := m.PenaltyHalfLifeDays * 24 * float64(time.Hour)
+	return math.Pow(0.5, float64(age)/halfLife)
+}
+
+// Score replays the ledger under the model and returns the score at now.
+// Decay entries are the model's own output and are ignored, as are reversed
+// events together with their reversals.
+func (m *ScoringModel) Score(entries []*WorkerScoreLedgerEntry, workerCreatedAt, now time.Time) int {
+	events := make([]*WorkerScoreLedgerEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.EventType == ScoreEventPenaltyDecay || e.EventType == ScoreEventReversal || e.ReversedByID != nil {
+			continue
+		}
+		if e.CreatedAt.After(now) {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+
+	type penalty struct {
+		points float64
+		at     time.Time
+	}
+	var (
+		score   = float64(m.InitialScore)
+		fading  []penalty
+		last    time.Time
+		ceiling = float64(m.MaxScore)
+	)
+	// recover gives back what the fading penalties lost between last and t.
+	recover := func(t time.Time) {
+		if last.IsZero() || !t.After(last) {
+			return
+		}
+		for _, p := range fading {
+			score += p.points * (m.remaining(last.Sub(p.at)) - m.remaining(t.Sub(p.at)))
+		}
+		score = math.Min(score, ceiling)
+		last = t
+	}
+
+	for _, e := range events {
+		recover(e.CreatedAt)
+		pts := m.PointsFor(e.EventType, e.Delta)
+		floor := float64(m.Floor(workerCreatedAt, e.CreatedAt))
+		before := score
+		score = math.Max(math.Min(score+float64(pts), ceiling), math.Min(floor, before))
+		if m.fades(e.EventType, pts) {
+			// Only what was actually taken can be given back.
+			if taken := before - score; taken > 0 {
+				fading = append(fading, penalty{points: taken, at: e.CreatedAt})
+			}
+		}
+		last = e.CreatedAt
+	}
+	recover(now)
+
+	return max(min(int(math.Round(score)), m.MaxScore), m.MinScore)
+}
+
+// DecayOwed is how many points the ledger's penalties have faded by now,
+// less what earlier decay entries already gave back. Each penalty the model
+// fades counts for what it actually took from the score; reversed ones
+// don't count. Nothing else in the ledger moves the result, so a negative
+// value means more was given back than the penalties have earned.
+func (m *ScoringModel) DecayOwed(entries []*WorkerScoreLedgerEntry, now time.Time) int {
+	if m.PenaltyHalfLifeDays <= 0 {
+		return 0
+	}
+	var faded float64
+	given := 0
+	for _, e := range entries {
+		if e.CreatedAt.After(now) {
+			continue
+		}
+		switch {
+		case e.EventType == ScoreEventPenaltyDecay:
+			given += e.Delta
+		case e.EventType == ScoreEventReversal || e.ReversedByID != nil:
+		case m.fades(e.EventType, m.PointsFor(e.EventType, e.Delta)):
+			if taken := -e.AppliedDelta(); taken > 0 {
+				faded += float64(taken) * (1 - m.remaining(now.Sub(e.CreatedAt)))
+			}
+		}
+	}
+	// The epsilon keeps a fully faded point from flooring to one less.
+	return int(math.Floor(faded+1e-9)) - given
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var scoreT0 = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+// ledgerEntry is an event that moved the score from old to new at.
+func ledgerEntry(eventType string, delta, old, new int, at time.Time) *WorkerScoreLedgerEntry {
+	return &WorkerScoreLedgerEntry{ID: uuid.New(), EventType: eventType, Delta: delta, OldScore: old, NewScore: new, CreatedAt: at}
+}
+
+func halfLives(n float64) time.Duration {
+	return time.Duration(n * 30 * 24 * float64(time.Hour))
+}
+
+func TestParseScoringModel(t *testing.T) {
+	m, err := ParseScoringModel([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	def := DefaultScoringModel()
+	if m.Name != def.Name || m.InitialScore != def.InitialScore || m.PenaltyHalfLifeDays != def.PenaltyHalfLifeDays || len(m.Points) != len(def.Points) {
+		t.Errorf("expected an empty model to be the default, got %+v", m)
+	}
+
+	m, err = ParseScoringModel([]byte(`{"name":"strict","penalty_half_life_days":0,"points":{"NOSHOW":-30,"UNACCEPT":-5}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Name != "strict" || m.PenaltyHalfLifeDays != 0 || m.MaxScore != def.MaxScore {
+		t.Errorf("expected named fields overridden and the rest defaulted, got %+v", m)
+	}
+	for event, want := range map[string]int{
+		ScoreEventNoShow:           -30,
+		ScoreEventUnaccept:         -5,
+		ScoreEventSurgePickup:      2,
+		ScoreEventOnTimeCompletion: 1,
+	} {
+		if got := m.Points[event]; got != want {
+			t.Errorf("%s: expected %d points, got %d", event, want, got)
+		}
+	}
+	if DefaultScoringModel().Points[ScoreEventNoShow] != -20 {
+		t.Errorf("parsing must not change the default model's points")
+	}
+
+	for name, data := range map[string]string{
+		"not json":             `{"points":`,
+		"wrong type":           `{"min_score":"low"}`,
+		"min above max":        `{"min_score":90,"max_score":80,"initial_score":85}`,
+		"initial out of range": `{"initial_score":101}`,
+		"floor above max":      `{"new_worker_floor":150}`,
+		"negative half-life":   `{"penalty_half_life_days":-1}`,
+	} {
+		if _, err := ParseScoringModel([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestScoringModelFloor(t *testing.T) {
+	created := scoreT0
+	for name, tc := range map[string]struct {
+		model func(*ScoringModel)
+		at    time.Time
+		want  int
+	}{
+		"new worker":              {at: created.AddDate(0, 0, 29), want: 61},
+		"on the thirtieth day":    {at: created.AddDate(0, 0, 30), want: 0},
+		"established worker":      {at: created.AddDate(1, 0, 0), want: 0},
+		"no new worker window":    {model: func(m *ScoringModel) { m.NewWorkerDays = 0 }, at: created, want: 0},
+		"floor below the minimum": {model: func(m *ScoringModel) { m.MinScore, m.NewWorkerFloor = 20, 10 }, at: created, want: 20},
+		"raised minimum":          {model: func(m *ScoringModel) { m.MinScore = 20 }, at: created.AddDate(1, 0, 0), want: 20},
+	} {
+		m := DefaultScoringModel()
+		if tc.model != nil {
+			tc.model(m)
+		}
+		if got := m.Floor(created, tc.at); got != tc.want {
+			t.Errorf("%s: expected floor %d, got %d", name, tc.want, got)
+		}
+	}
+}
+
+func TestScoringModelScore(t *testing.T) {
+	established := scoreT0.AddDate(-1, 0, 0)
+	reversed := ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0)
+	reversal := ledgerEntry(ScoreEventReversal, 20, 80, 100, scoreT0.Add(time.Hour))
+	reversed.ReversedByID = &reversal.ID
+
+	for name, tc := range map[string]struct {
+		created time.Time
+		entries []*WorkerScoreLedgerEntry
+		now     time.Time
+		want    int
+	}{
+		"no events": {now: scoreT0, want: 100},
+		"fresh penalty": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0)},
+			now:     scoreT0, want: 80,
+		},
+		"one half-life on": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0)},
+			now:     scoreT0.Add(halfLives(1)), want: 90,
+		},
+		"two half-lives on": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0)},
+			now:     scoreT0.Add(halfLives(2)), want: 95,
+		},
+		"model points win over the recorded delta": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -5, 100, 95, scoreT0)},
+			now:     scoreT0, want: 80,
+		},
+		"recorded delta without model points": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventUnaccept, -7, 100, 93, scoreT0)},
+			now:     scoreT0, want: 93,
+		},
+		"manual adjustments don't fade": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventManualAdjustment, -10, 100, 90, scoreT0)},
+			now:     scoreT0.Add(halfLives(3)), want: 90,
+		},
+		"reversed penalty": {
+			entries: []*WorkerScoreLedgerEntry{reversed, reversal},
+			now:     scoreT0.Add(2 * time.Hour), want: 100,
+		},
+		"decay entries are the model's own": {
+			entries: []*WorkerScoreLedgerEntry{
+				ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0),
+				ledgerEntry(ScoreEventPenaltyDecay, 5, 80, 85, scoreT0.Add(halfLives(1)/2)),
+			},
+			now: scoreT0.Add(halfLives(1)), want: 90,
+		},
+		"rewards stop at the maximum": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventSurgePickup, 2, 100, 100, scoreT0)},
+			now:     scoreT0, want: 100,
+		},
+		"future events are ignored": {
+			entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0.Add(time.Hour))},
+			now:     scoreT0, want: 100,
+		},
+		"established worker can fall far": {
+			entries: []*WorkerScoreLedgerEntry{
+				ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0),
+				ledgerEntry(ScoreEventNoShow, -20, 80, 60, scoreT0.Add(time.Minute)),
+				ledgerEntry(ScoreEventNoShow, -20, 60, 40, scoreT0.Add(2*time.Minute)),
+			},
+			now: scoreT0.Add(2 * time.Minute), want: 40,
+		},
+		"new worker stops at the floor": {
+			created: scoreT0.AddDate(0, 0, -1),
+			entries: []*WorkerScoreLedgerEntry{
+				ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0),
+				ledgerEntry(ScoreEventNoShow, -20, 80, 61, scoreT0.Add(time.Minute)),
+				ledgerEntry(ScoreEventNoShow, -20, 61, 61, scoreT0.Add(2*time.Minute)),
+			},
+			now: scoreT0.Add(2 * time.Minute), want: 61,
+		},
+		"only what was taken fades back": {
+			created: scoreT0.AddDate(0, 0, -1),
+			entries: []*WorkerScoreLedgerEntry{
+				ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0),
+				ledgerEntry(ScoreEventNoShow, -20, 80, 61, scoreT0),
+			},
+			// 20 and 19 taken; half of each is back.
+			now: scoreT0.Add(halfLives(1)), want: 81,
+		},
+	} {
+		created := tc.created
+		if created.IsZero() {
+			created = established
+		}
+		if got := DefaultScoringModel().Score(tc.entries, created, tc.now); got != tc.want {
+			t.Errorf("%s: expected score %d, got %d", name, tc.want, got)
+		}
+	}
+}
+
+func TestScoringModelDecayOwed(t *testing.T) {
+	noShow := func() *WorkerScoreLedgerEntry { return ledgerEntry(ScoreEventNoShow, -20, 100, 80, scoreT0) }
+	reversed := noShow()
+	reversed.ReversedByID = &uuid.UUID{}
+
+	for name, tc := range map[string]struct {
+		model   func(*ScoringModel)
+		entries []*WorkerScoreLedgerEntry
+		now     time.Time
+		want    int
+	}{
+		"fresh penalty":       {entries: []*WorkerScoreLedgerEntry{noShow()}, now: scoreT0, want: 0},
+		"one half-life on":    {entries: []*WorkerScoreLedgerEntry{noShow()}, now: scoreT0.Add(halfLives(1)), want: 10},
+		"partly given back":   {entries: []*WorkerScoreLedgerEntry{noShow(), ledgerEntry(ScoreEventPenaltyDecay, 4, 80, 84, scoreT0.Add(halfLives(0.5)))}, now: scoreT0.Add(halfLives(1)), want: 6},
+		"rounded down":        {entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventNoShow, -20, 66, 61, scoreT0)}, now: scoreT0.Add(halfLives(1)), want: 2},
+		"ten half-lives on":   {entries: []*WorkerScoreLedgerEntry{noShow()}, now: scoreT0.Add(halfLives(10)), want: 19},
+		"reversed penalty":    {entries: []*WorkerScoreLedgerEntry{reversed}, now: scoreT0.Add(halfLives(1)), want: 0},
+		"manual adjustment":   {entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventManualAdjustment, -20, 100, 80, scoreT0)}, now: scoreT0.Add(halfLives(1)), want: 0},
+		"rewards don't count": {entries: []*WorkerScoreLedgerEntry{ledgerEntry(ScoreEventSurgePickup, 2, 80, 82, scoreT0)}, now: scoreT0.Add(halfLives(1)), want: 0},
+		"over given":          {entries: []*WorkerScoreLedgerEntry{noShow(), ledgerEntry(ScoreEventPenaltyDecay, 15, 80, 95, scoreT0.Add(halfLives(1)))}, now: scoreT0.Add(halfLives(1)), want: -5},
+		"penalties never fade": {
+			model:   func(m *ScoringModel) { m.PenaltyHalfLifeDays = 0 },
+			entries: []*WorkerScoreLedgerEntry{noShow()}, now: scoreT0.Add(halfLives(1)), want: 0,
+		},
+	} {
+		m := DefaultScoringModel()
+		if tc.model != nil {
+			tc.model(m)
+		}
+		if got := m.DecayOwed(tc.entries, tc.now); got != tc.want {
+			t.Errorf("%s: expected %d owed, got %d", name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SurgeStage applies while the time left before the no-show cutoff is under
+// BeforeNoShowMinutes. Bonus is an absolute amount added on top of the
+// multiplied base pay.
+type SurgeStage struct {
+	BeforeNoShowMinutes int     `json:"before_no_show_minutes"`
+	Multiplier          float64 `json:"multiplier"`
+	Bonus               Money   `json:"bonus,omitzero"`
+}
+
+// SurgeOverride replaces a policy's stages on specific service dates
+// (holidays, YYYY-MM-DD) or weekdays (0 = Sunday). Dates take precedence.
+type SurgeOverride struct {
+	Name       string       `json:"name,omitempty"`
+	Dates      []string     `json:"dates,omitempty"`
+	DaysOfWeek []int        `json:"days_of_week,omitempty"`
+	Stages     []SurgeStage `json:"stages"`
+}
+
+type SurgePolicy struct {
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name"`
+	Scope    PolicyScopeType `json:"scope"`
+	ScopeKey string          `json:"scope_key"` // market name or property/definition ID; empty for GLOBAL
+
+	Stages        []SurgeStage    `json:"stages"`
+	MaxMultiplier float64         `json:"max_multiplier"`
+	MaxPay        *Money          `json:"max_pay,omitempty"` // absolute cap on a full (unsegmented) job
+	Overrides     []SurgeOverride `json:"overrides,omitempty"`
+	Active        bool            `json:"active"`
+
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (p *SurgePolicy) GetID() string {
+	return p.ID.String()
+}
+
+// StagesFor returns the stages in effect on serviceDate after overrides.
+func (p *SurgePolicy) StagesFor(serviceDate time.Time) []SurgeStage {
+	date := serviceDate.Format("2006-01-02")
+	for _, o := range p.Overrides {
+		for _, d := range o.Dates {
+			if d == date {
+				return o.Stages
+			}
+		}
+	}
+	dow := int(serviceDate.Weekday())
+	for _, o := range p.Overrides {
+		for _, d := range o.DaysOfWeek {
+			if d == dow {
+				return o.Stages
+			}
+		}
+	}
+	return p.Stages
+}
+
+// Evaluate returns the multiplier and bonus for a job with timeLeft before
+// its no-show cutoff. The tightest matching stage applies; ok is false when
+// no stage has started yet.
+func (p *SurgePolicy) Evaluate(serviceDate time.Time, timeLeft time.Duration) (multiplier float64, bonus Money, ok bool) {
+	stages := append([]SurgeStage(nil), p.StagesFor(serviceDate)...)
+	sort.Slice(stages, func(i, j int) bool {
+		return stages[i].BeforeNoShowMinutes < stages[j].BeforeNoShowMinutes
+	})
+	for _, st := range stages {
+		if timeLeft < time.Duration(st.BeforeNoShowMinutes)*time.Minute {
+			multiplier, bonus = st.Multiplier, st.Bonus
+			if p.MaxMultiplier > 0 && multiplier > p.MaxMultiplier {
+				multiplier = p.MaxMultiplier
+			}
+			return multiplier, bonus, true
+		}
+	}
+	return 0, Money{}, false
+}
+
+// ResolveSurgePolicy picks the most specific active policy for a job.
+func ResolveSurgePolicy(
+	policies []*SurgePolicy,
+	definitionID, propertyID uuid.UUID,
+	market string,
+) *SurgePolicy {
+	var best *SurgePolicy
+	bestRank := -1
+	for _, p := range policies {
+		if !p.Active {
+			continue
+		}
+		rank := PolicyScopeRank(p.Scope, p.ScopeKey, definitionID, propertyID, market)
+		if rank > bestRank || (rank == bestRank && rank >= 0 && p.UpdatedAt.After(best.UpdatedAt)) {
+			best, bestRank = p, rank
+		}
+	}
+	return best
+}
+
+type SurgeAuditActionType string
+
+const (
+	SurgeAuditPolicyCreated     SurgeAuditActionType = "POLICY_CREATED"
+	SurgeAuditPolicyUpdated     SurgeAuditActionType = "POLICY_UPDATED"
+	SurgeAuditPolicyDeactivated SurgeAuditActionType = "POLICY_DEACTIVATED"
+	SurgeAuditManualSurge       SurgeAuditActionType = "MANUAL_SURGE"
+)
+
+// SurgeAuditEvent records who changed surge pricing and how.
+type SurgeAuditEvent struct {
+	ID         uuid.UUID            `json:"id"`
+	Action     SurgeAuditActionType `json:"action"`
+	ActorID    uuid.UUID            `json:"actor_id"`
+	PolicyID   *uuid.UUID           `json:"policy_id,omitempty"`
+	InstanceID *uuid.UUID           `json:"instance_id,omitempty"`
+	OldPay     *Money               `json:"old_pay,omitempty"`
+	NewPay     *Money               `json:"new_pay,omitempty"`
+	Reason     string               `json:"reason"`
+	Details    map[string]any       `json:"details,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveSurgePolicyScopeRanking(t *testing.T) {
+	defnID, propID := uuid.New(), uuid.New()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := func(name string, scope PolicyScopeType, key string, active bool, updated time.Time) *SurgePolicy {
+		return &SurgePolicy{ID: uuid.New(), Name: name, Scope: scope, ScopeKey: key, Active: active, UpdatedAt: updated}
+	}
+	global := policy("global", PolicyScopeGlobal, "", true, t0)
+	market := policy("market", PolicyScopeMarket, "nashville", true, t0)
+	otherMarket := policy("other market", PolicyScopeMarket, "memphis", true, t0)
+	property := policy("property", PolicyScopeProperty, propID.String(), true, t0)
+	definition := policy("definition", PolicyScopeDefinition, defnID.String(), true, t0)
+	inactiveDefinition := policy("inactive definition", PolicyScopeDefinition, defnID.String(), false, t0.Add(time.Hour))
+	newerMarket := policy("newer market", PolicyScopeMarket, "nashville", true, t0.Add(time.Hour))
+
+	for name, tc := range map[string]struct {
+		policies []*SurgePolicy
+		market   string
+		want     *SurgePolicy
+	}{
+		"definition beats everything": {policies: []*SurgePolicy{global, market, property, definition}, market: "nashville", want: definition},
+		"property beats market":       {policies: []*SurgePolicy{global, market, property}, market: "nashville", want: property},
+		"market beats global":         {policies: []*SurgePolicy{global, market}, market: "nashville", want: market},
+		"other markets don't match":   {policies: []*SurgePolicy{global, otherMarket}, market: "nashville", want: global},
+		"no market matches no market": {policies: []*SurgePolicy{market}, market: "", want: nil},
+		"inactive policies are skipped": {
+			policies: []*SurgePolicy{property, inactiveDefinition}, market: "nashville", want: property,
+		},
+		"newest wins a tie": {policies: []*SurgePolicy{market, newerMarket}, market: "nashville", want: newerMarket},
+		"nothing stored":    {want: nil},
+	} {
+		if got := ResolveSurgePolicy(tc.policies, defnID, propID, tc.market); got != tc.want {
+			gotName, wantName := "<nil>", "<nil>"
+			if got != nil {
+				gotName = got.Name
+			}
+			if tc.want != nil {
+				wantName = tc.want.Name
+			}
+			t.Errorf("%s: expected %s, got %s", name, wantName, gotName)
+		}
+	}
+}
+
+func TestSurgePolicyEvaluate(t *testing.T) {
+	p := &SurgePolicy{
+		Stages: []SurgeStage{
+			// Out of order on purpose; the tightest stage must still win.
+			{BeforeNoShowMinutes: 60, Multiplier: 1.2},
+			{BeforeNoShowMinutes: 240, Multiplier: 1.1},
+			{BeforeNoShowMinutes: 30, Multiplier: 1.8, Bonus: USD(500)},
+		},
+		MaxMultiplier: 1.5,
+	}
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		left  time.Duration
+		mult  float64
+		bonus int64
+		ok    bool
+	}{
+		"before any stage":           {left: 5 * time.Hour},
+		"at a stage's boundary":      {left: 4 * time.Hour},
+		"first stage":                {left: 3 * time.Hour, mult: 1.1, ok: true},
+		"second stage":               {left: 59 * time.Minute, mult: 1.2, ok: true},
+		"capped at max, keeps bonus": {left: 10 * time.Minute, mult: 1.5, bonus: 500, ok: true},
+	} {
+		mult, bonus, ok := p.Evaluate(monday, tc.left)
+		if ok != tc.ok || mult != tc.mult || bonus.Cents != tc.bonus {
+			t.Errorf("%s: expected %.2f + %d (%v), got %.2f + %d (%v)", name, tc.mult, tc.bonus, tc.ok, mult, bonus.Cents, ok)
+		}
+	}
+}
+
+func TestSurgePolicyOverrides(t *testing.T) {
+	p := &SurgePolicy{
+		Stages: []SurgeStage{{BeforeNoShowMinutes: 60, Multiplier: 1.1}},
+		Overrides: []SurgeOverride{
+			{Name: "weekends", DaysOfWeek: []int{0, 6}, Stages: []SurgeStage{{BeforeNoShowMinutes: 120, Multiplier: 1.3}}},
+			{Name: "july 4th", Dates: []string{"2026-07-04"}, Stages: []SurgeStage{{BeforeNoShowMinutes: 180, Multiplier: 2}}},
+		},
+	}
+	for name, tc := range map[string]struct {
+		date time.Time
+		mult float64
+	}{
+		"weekday uses the policy's stages": {date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), mult: 1.1},
+		"weekday override":                 {date: time.Date(2026, 7, 5, 0, 0, 0, 0, time.UTC), mult: 1.3},
+		"date beats weekday":               {date: time.Date(2026, 7, 4, 0, 0, 0, 0, time.UTC), mult: 2},
+	} {
+		if mult, _, _ := p.Evaluate(tc.date, 30*time.Minute); mult != tc.mult {
+			t.Errorf("%s: expected %.2f, got %.2f", name, tc.mult, mult)
+		}
+	}
+
+	// An override's stages replace the policy's, including when they start.
+	if _, _, ok := p.Evaluate(time.Date(2026, 7, 5, 0, 0, 0, 0, time.UTC), 100*time.Minute); !ok {
+		t.Errorf("expected the weekend override's 120-minute stage to apply")
+	}
+	if _, _, ok := p.Evaluate(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), 100*time.Minute); ok {
+		t.Errorf("expected no surge 100 minutes out on a weekday")
+	}
+}
@@ -63,6 +63,8 @@ type Worker struct {
 	City                      string              `json:"city"`
 	State                     string              `json:"state"`
 	ZipCode                   string              `json:"zip_code"`
+	HomeLatitude              *float64            `json:"-"` // geocoded from the address
+	HomeLongitude             *float64            `json:"-"`
 	VehicleYear               int                 `json:"vehicle_year"`
 	VehicleMake               string              `json:"vehicle_make"`
 	VehicleModel              string              `json:"vehicle_model"`
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Score event types recorded in worker_score_events.
+const (
+	ScoreEventNoShow                 = "NOSHOW"
+	ScoreEventUnaccept               = "UNACCEPT"
+	ScoreEventUnacceptLateCancel     = "UNACCEPT_LATE_CANCEL"
+	ScoreEventCancelInProgressRevert = "CANCEL_IN_PROGRESS_REVERT"
+	ScoreEventCancelInProgressLate   = "CANCEL_IN_PROGRESS_LATE"
+	ScoreEventOnTimeCompletion       = "ON_TIME_COMPLETION"
+	ScoreEventCleanVerification      = "CLEAN_VERIFICATION"
+	ScoreEventSurgePickup            = "SURGE_PICKUP"
+	ScoreEventPenaltyDecay           = "PENALTY_DECAY"
+	ScoreEventManualAdjustment       = "MANUAL_ADJUSTMENT"
+	ScoreEventReversal               = "REVERSAL"
+)
+
+var scoreEventReasons = map[string]string{
+	ScoreEventNoShow:                 "You didn't complete an accepted job before its no-show cutoff.",
+	ScoreEventUnaccept:               "You released an accepted job.",
+	ScoreEventUnacceptLateCancel:     "You released an accepted job after its acceptance cutoff.",
+	ScoreEventCancelInProgressRevert: "You canceled a job you had started; it was reopened for others.",
+	ScoreEventCancelInProgressLate:   "You canceled a job you had started too late for anyone else to take it.",
+	ScoreEventOnTimeCompletion:       "You completed a job on time.",
+	ScoreEventCleanVerification:      "Every unit on the job passed photo verification the first time.",
+	ScoreEventSurgePickup:            "You covered a job that was paying surge rates.",
+	ScoreEventPenaltyDecay:           "Older penalties are fading.",
+	ScoreEventManualAdjustment:       "Your score was adjusted by Poof support.",
+	ScoreEventReversal:               "An earlier score change was reversed by Poof support.",
+}
+
+// ScoreEventReason is a worker-facing explanation of an event type.
+func ScoreEventReason(eventType string) string {
+	if r, ok := scoreEventReasons[eventType]; ok {
+		return r
+	}
+	return "Your reliability score changed."
+}
+
+// WorkerScoreLedgerEntry is one row of a worker's reliability score history.
+// Delta is what was asked for; NewScore-OldScore is what applied after the
+// score was clamped to its bounds.
+type WorkerScoreLedgerEntry struct {
+	ID              uuid.UUID  `json:"id"`
+	WorkerID        uuid.UUID  `json:"worker_id"`
+	EventType       string     `json:"event_type"`
+	Delta           int        `json:"delta"`
+	OldScore        int        `json:"old_score"`
+	NewScore        int        `json:"new_score"`
+	JobInstanceID   *uuid.UUID `json:"job_instance_id,omitempty"`
+	ActorID         *uuid.UUID `json:"actor_id,omitempty"`
+	Note            string     `json:"note,omitempty"`
+	ReversesEventID *uuid.UUID `json:"reverses_event_id,omitempty"`
+	ReversedByID    *uuid.UUID `json:"reversed_by_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AppliedDelta is the change the event made to the score.
+func (e *WorkerScoreLedgerEntry) AppliedDelta() int {
+	return e.NewScore - e.OldScore
+}
+
+// ScoreAdjustment describes one change to a worker's reliability score.
+// JobInstanceID ties a penalty to the job that caused it; ActorID and Note
+// record who made a manual change or reversal and why.
+type ScoreAdjustment struct {
+	WorkerID        uuid.UUID
+	Delta           int
+	EventType       string
+	JobInstanceID   *uuid.UUID
+	ActorID         *uuid.UUID
+	Note            string
+	ReversesEventID *uuid.UUID
+	// Model, if set, keeps a new worker's score from falling below its floor.
+	Model *ScoringModel
+}
@@ -0,0 +1,296 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/* ------------------------------------------------------------------
+   Public interface
+------------------------------------------------------------------ */
+
+type EscalationPolicyRepository interface {
+	Create(ctx context.Context, p *models.EscalationPolicy) error
+	Update(ctx context.Context, p *models.EscalationPolicy) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.EscalationPolicy, error)
+	ListActive(ctx context.Context) ([]*models.EscalationPolicy, error)
+	ListAll(ctx context.Context) ([]*models.EscalationPolicy, error)
+
+	CreateAuditEvent(ctx context.Context, e *models.EscalationPolicyAuditEvent) error
+	ListAuditEvents(ctx context.Context, policyID *uuid.UUID, limit int) ([]*models.EscalationPolicyAuditEvent, error)
+
+	// ClaimStep inserts a PENDING execution for (instance, step key), or
+	// re-claims a FAILED one or a PENDING one older than
+	// EscalationStepLease, and reports whether this caller won it. A false
+	// return means the step already ran or another worker is running it.
+	// On success e.ID is the stored row's ID.
+	ClaimStep(ctx context.Context, e *models.EscalationStepExecution) (bool, error)
+	FinishStep(ctx context.Context, id uuid.UUID, outcome models.EscalationOutcomeType, details map[string]any) error
+	// ListExecutedStepKeys returns, per instance, the steps that ran or are
+	// running. Failed and abandoned steps are left out so they are retried.
+	ListExecutedStepKeys(ctx context.Context, instanceIDs []uuid.UUID) (map[uuid.UUID]map[string]bool, error)
+	ListExecutions(ctx context.Context, instanceID uuid.UUID) ([]*models.EscalationStepExecution, error)
+}
+
+// EscalationStepLease is how long a step may stay PENDING. A claim older
+// than this was abandoned between ClaimStep and FinishStep (the process
+// died or timed out) and the step may be claimed again.
+const EscalationStepLease = 10 * time.Minute
+
+/* ------------------------------------------------------------------
+   Implementation
+------------------------------------------------------------------ */
+
+type escalationPolicyRepo struct {
+	db DB
+}
+
+func NewEscalationPolicyRepository(db DB) EscalationPolicyRepository {
+	return &escalationPolicyRepo{db: db}
+}
+
+const escalationPolicyColumns = `
+    id, name, scope, scope_key, steps, active, created_by, created_at, updated_at`
+
+func scanEscalationPolicy(row pgx.Row) (*models.EscalationPolicy, error) {
+	var p models.EscalationPolicy
+	var steps []byte
+	err := row.Scan(
+		&p.ID, &p.Name, &p.Scope, &p.ScopeKey, &steps, &p.Active,
+		&p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(steps, &p.Steps)
+	return &p, nil
+}
+
+func (r *escalationPolicyRepo) Create(ctx context.Context, p *models.EscalationPolicy) error {
+	steps, _ := json.Marshal(p.Steps)
+	return r.db.QueryRow(ctx, `
+        INSERT INTO escalation_policies (
+            id, name, scope, scope_key, steps, active, created_by, created_at, updated_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7, NOW(), NOW())
+        RETURNING created_at, updated_at
+    `,
+		p.ID, p.Name, p.Scope, p.ScopeKey, steps, p.Active, p.CreatedBy,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *escalationPolicyRepo) Update(ctx context.Context, p *models.EscalationPolicy) error {
+	steps, _ := json.Marshal(p.Steps)
+	return r.db.QueryRow(ctx, `
+        UPDATE escalation_policies SET
+            name=$1, scope=$2, scope_key=$3, steps=$4, active=$5, updated_at=NOW()
+        WHERE id=$6
+        RETURNING updated_at
+    `,
+		p.Name, p.Scope, p.ScopeKey, steps, p.Active, p.ID,
+	).Scan(&p.UpdatedAt)
+}
+
+func (r *escalationPolicyRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.EscalationPolicy, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+escalationPolicyColumns+` FROM escalation_policies WHERE id=$1`, id)
+	return scanEscalationPolicy(row)
+}
+
+func (r *escalationPolicyRepo) ListActive(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	return r.list(ctx, `SELECT `+escalationPolicyColumns+` FROM escalation_policies WHERE active ORDER BY created_at`)
+}
+
+func (r *escalationPolicyRepo) ListAll(ctx context.Context) ([]*models.EscalationPolicy, error) {
+	return r.list(ctx, `SELECT `+escalationPolicyColumns+` FROM escalation_policies ORDER BY created_at`)
+}
+
+func (r *escalationPolicyRepo) list(ctx context.Context, query string) ([]*models.EscalationPolicy, error) {
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.EscalationPolicy
+	for rows.Next() {
+		p, err := scanEscalationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+/* ---------- audit ---------- */
+
+func (r *escalationPolicyRepo) CreateAuditEvent(ctx context.Context, e *models.EscalationPolicyAuditEvent) error {
+	return r.db.QueryRow(ctx, `
+        INSERT INTO escalation_policy_audit_events (
+            id, action, actor_id, policy_id, reason, details, created_at
+        ) VALUES ($1,$2,$3,$4,$5,$6, NOW())
+        RETURNING created_at
+    `,
+		e.ID, e.Action, e.ActorID, e.PolicyID, e.Reason, jsonObject(e.Details),
+	).Scan(&e.CreatedAt)
+}
+
+func (r *escalationPolicyRepo) ListAuditEvents(
+	ctx context.Context,
+	policyID *uuid.UUID,
+	limit int,
+) ([]*models.EscalationPolicyAuditEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, `
+        SELECT id, action, actor_id, policy_id, reason, details, created_at
+        FROM escalation_policy_audit_events
+        WHERE ($1::uuid IS NULL OR policy_id = $1)
+        ORDER BY created_at DESC
+        LIMIT $2
+    `, policyID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.EscalationPolicyAuditEvent
+	for rows.Next() {
+		var e models.EscalationPolicyAuditEvent
+		var details []byte
+		if err := rows.Scan(
+			&e.ID, &e.Action, &e.ActorID, &e.PolicyID, &e.Reason, &details, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(details, &e.Details)
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
+
+/* ---------- step executions ---------- */
+
+// jsonObject marshals m, using {} for nil so jsonb concatenation stays an object.
+func jsonObject(m map[string]any) []byte {
+	if m == nil {
+		return []byte(`{}`)
+	}
+	raw, _ := json.Marshal(m)
+	return raw
+}
+
+func (r *escalationPolicyRepo) ClaimStep(ctx context.Context, e *models.EscalationStepExecution) (bool, error) {
+	details := jsonObject(e.Details)
+	err := r.db.QueryRow(ctx, `
+        INSERT INTO escalation_step_executions (
+            id, instance_id, policy_id, step_key, action, outcome, details, executed_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7, NOW())
+        ON CONFLICT (instance_id, step_key) DO UPDATE
+        SET outcome=EXCLUDED.outcome,
+            policy_id=EXCLUDED.policy_id,
+            action=EXCLUDED.action,
+            executed_at=NOW()
+        WHERE escalation_step_executions.outcome='FAILED'
+           OR (escalation_step_executions.outcome='PENDING'
+               AND escalation_step_executions.executed_at < NOW() - make_interval(secs => $8))
+        RETURNING id, executed_at
+    `,
+		e.ID, e.InstanceID, e.PolicyID, e.StepKey, e.Action, models.EscalationOutcomePending, details,
+		EscalationStepLease.Seconds(),
+	).Scan(&e.ID, &e.ExecutedAt)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	e.Outcome = models.EscalationOutcomePending
+	return true, nil
+}
+
+func (r *escalationPolicyRepo) FinishStep(
+	ctx context.Context,
+	id uuid.UUID,
+	outcome models.EscalationOutcomeType,
+	details map[string]any,
+) error {
+	_, err := r.db.Exec(ctx, `
+        UPDATE escalation_step_executions
+        SET outcome=$1, details=details || $2::jsonb
+        WHERE id=$3
+    `, outcome, jsonObject(details), id)
+	return err
+}
+
+func (r *escalationPolicyRepo) ListExecutedStepKeys(
+	ctx context.Context,
+	instanceIDs []uuid.UUID,
+) (map[uuid.UUID]map[string]bool, error) {
+	out := make(map[uuid.UUID]map[string]bool)
+	if len(instanceIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `
+        SELECT instance_id, step_key
+        FROM escalation_step_executions
+        WHERE instance_id = ANY($1) AND outcome <> 'FAILED'
+          AND NOT (outcome = 'PENDING' AND executed_at < NOW() - make_interval(secs => $2))
+    `, instanceIDs, EscalationStepLease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var key string
+		if err := rows.Scan(&id, &key); err != nil {
+			return nil, err
+		}
+		if out[id] == nil {
+			out[id] = make(map[string]bool)
+		}
+		out[id][key] = true
+	}
+	return out, rows.Err()
+}
+
+func (r *escalationPolicyRepo) ListExecutions(
+	ctx context.Context,
+	instanceID uuid.UUID,
+) ([]*models.EscalationStepExecution, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT id, instance_id, policy_id, step_key, action, outcome, details, executed_at
+        FROM escalation_step_executions
+        WHERE instance_id=$1
+        ORDER BY executed_at
+    `, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.EscalationStepExecution
+	for rows.Next() {
+		var e models.EscalationStepExecution
+		var details []byte
+		if err := rows.Scan(
+			&e.ID, &e.InstanceID, &e.PolicyID, &e.StepKey, &e.Action,
+			&e.Outcome, &details, &e.ExecutedAt,
+		); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(details, &e.Details)
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
@@ -57,6 +57,10 @@ func (r *jobRepo) Create(ctx context.Context, j *models.JobDefinition) error {
 	comp, _ := json.Marshal(j.CompletionRules)
 	support, _ := json.Marshal(j.SupportContact)
 	assigned, _ := json.Marshal(j.AssignedUnitsByBuilding)
+	segments, _ := json.Marshal(j.Segments)
+	if j.SegmentStrategy == "" {
+		j.SegmentStrategy = models.SegmentStrategyNone
+	}
 
 	_, err := r.db.Exec(ctx, `
         INSERT INTO job_definitions (
@@ -66,6 +70,7 @@ func (r *jobRepo) Create(ctx context.Context, j *models.JobDefinition) error {
             earliest_start_time, latest_start_time, start_time_hint,
             skip_holidays, holiday_exceptions,
             details, requirements, daily_pay_estimates, completion_rules, support_contact, -- UPDATED
+            segment_strategy, segments,
             created_at, updated_at, row_version
         ) VALUES (
             $1,$2,$3,$4,$5,
@@ -74,6 +79,7 @@ func (r *jobRepo) Create(ctx context.Context, j *models.JobDefinition) error {
             $16,$17,$18,
             $19,$20,
             $21,$22,$23,$24,$25, -- UPDATED
+            $26,$27,
             NOW(),NOW(),1
         )
     `,
@@ -83,6 +89,7 @@ func (r *jobRepo) Create(ctx context.Context, j *models.JobDefinition) error {
 		j.EarliestStartTime, j.LatestStartTime, j.StartTimeHint,
 		j.SkipHolidays, j.HolidayExceptions,
 		details, reqs, dailyPayEstimates, comp, support, // UPDATED
+		j.SegmentStrategy, segments,
 	)
 	return err
 }
@@ -214,6 +221,7 @@ func (r *jobRepo) update(
 	comp, _ := json.Marshal(j.CompletionRules)
 	support, _ := json.Marshal(j.SupportContact)
 	assigned, _ := json.Marshal(j.AssignedUnitsByBuilding)
+	segments, _ := json.Marshal(j.Segments)
 
 	sql := `
         UPDATE job_definitions SET
@@ -225,6 +233,7 @@ func (r *jobRepo) update(
             earliest_start_time=$13, latest_start_time=$14, start_time_hint=$15,
             skip_holidays=$16, holiday_exceptions=$17,
             details=$18, requirements=$19, daily_pay_estimates=$20, completion_rules=$21, support_contact=$22, -- UPDATED
+            segment_strategy=$23, segments=$24,
             updated_at=NOW()`
 	args := []any{
 		j.Title, j.Description,
@@ -235,87 +244,90 @@ func (r *jobRepo) update(
 		j.EarliestStartTime, j.LatestStartTime, j.StartTimeHint,
 		j.SkipHolidays, j.HolidayExceptions,
 		details, reqs, dailyPayEstimates, comp, support, // UPDATED
+		j.SegmentStrategy, segments,
 	}
 
 	if check {
-		sql += `, row_version=row_version+1 WHERE id=$23 AND row_version=$24`
+		sql += `, row_version=row_version+1 WHERE id=$25 AND row_version=$26`
 		args = append(args, j.ID, expected)
 	} else {
-		sql += ` WHERE id=$23`
+		sql += ` WHERE id=$25`
 		args = append(args, j.ID)
 	}
 	return r.db.Exec(ctx, sql, args...)
 }
 
+var jobDefinitionColumns = []string{
+	"id", "manager_id", "property_id", "title", "description",
+	"assigned_units_by_building", "floors", "total_units", "dumpster_ids", "status", "frequency",
+	"weekdays", "interval_weeks", "start_date", "end_date",
+	"earliest_start_time", "latest_start_time", "start_time_hint",
+	"skip_holidays", "holiday_exceptions",
+	"details", "requirements", "daily_pay_estimates", "completion_rules", "support_contact",
+	"segment_strategy", "segments",
+	"row_version", "created_at", "updated_at",
+}
+
 func baseSelectJob() string {
-	return `
-        SELECT
-            id, manager_id, property_id, title, description,
-            assigned_units_by_building, floors, total_units, dumpster_ids, status, frequency,
-            weekdays, interval_weeks, start_date, end_date,
-            earliest_start_time, latest_start_time, start_time_hint,
-            skip_holidays, holiday_exceptions,
-            details, requirements, daily_pay_estimates, completion_rules, support_contact, -- UPDATED
-            row_version, created_at, updated_at
-        FROM job_definitions
-    `
+	return "SELECT " + qualifyColumns("", jobDefinitionColumns) + " FROM job_definitions "
 }
 
-func (r *jobRepo) scanJob(row pgx.Row) (*models.JobDefinition, error) {
-	var j models.JobDefinition
-
-	var desc *string
-	var assignedB []byte
-	var floors []int16
-	var totalUnits int
-	var dumpsters []uuid.UUID
-	var status, freq string
-	var weekdays []int16
-	var interval *int
-	var startDate, endDate *time.Time
-	var eStart, lStart, sHint time.Time
-	var holExc []time.Time
-	var detailsB, reqB, dailyPayEstB, compB, suppB []byte // UPDATED: dailyPayEstB
-	// REMOVED: var estTime int
-
-	err := row.Scan(
-		&j.ID, &j.ManagerID, &j.PropertyID, &j.Title, &desc,
-		&assignedB, &floors, &totalUnits, &dumpsters, &status, &freq,
-		&weekdays, &interval, &startDate, &endDate,
-		&eStart, &lStart, &sHint,
-		&j.SkipHolidays, &holExc,
-		&detailsB, &reqB, &dailyPayEstB, &compB, &suppB, // UPDATED
-		// REMOVED: &estTime,
+// jobDefinitionScan holds the raw column values for one job_definitions row,
+// so joined queries can scan a definition alongside other tables.
+type jobDefinitionScan struct {
+	j models.JobDefinition
+
+	desc                                       *string
+	assignedB                                  []byte
+	status, freq, segStrategy                  string
+	startDate                                  *time.Time
+	detailsB, reqB, dailyPayEstB, compB, suppB []byte
+	segmentsB                                  []byte
+}
+
+// dest returns scan targets matching jobDefinitionColumns.
+func (s *jobDefinitionScan) dest() []any {
+	j := &s.j
+	return []any{
+		&j.ID, &j.ManagerID, &j.PropertyID, &j.Title, &s.desc,
+		&s.assignedB, &j.Floors, &j.TotalUnits, &j.DumpsterIDs, &s.status, &s.freq,
+		&j.Weekdays, &j.IntervalWeeks, &s.startDate, &j.EndDate,
+		&j.EarliestStartTime, &j.LatestStartTime, &j.StartTimeHint,
+		&j.SkipHolidays, &j.HolidayExceptions,
+		&s.detailsB, &s.reqB, &s.dailyPayEstB, &s.compB, &s.suppB,
+		&s.segStrategy, &s.segmentsB,
 		&j.RowVersion, &j.CreatedAt, &j.UpdatedAt,
-	)
-	if err != nil {
+	}
+}
+
+func (s *jobDefinitionScan) result() *models.JobDefinition {
+	j := &s.j
+	j.Description = s.desc
+	_ = json.Unmarshal(s.assignedB, &j.AssignedUnitsByBuilding)
+	j.Status = models.JobStatusType(s.status)
+	j.Frequency = models.JobFrequencyType(s.freq)
+	if s.startDate != nil {
+		j.StartDate = *s.startDate
+	}
+
+	_ = json.Unmarshal(s.detailsB, &j.Details)
+	_ = json.Unmarshal(s.reqB, &j.Requirements)
+	_ = json.Unmarshal(s.dailyPayEstB, &j.DailyPayEstimates)
+	_ = json.Unmarshal(s.compB, &j.CompletionRules)
+	_ = json.Unmarshal(s.suppB, &j.SupportContact)
+
+	j.SegmentStrategy = models.SegmentStrategyType(s.segStrategy)
+	_ = json.Unmarshal(s.segmentsB, &j.Segments)
+	return j
+}
+
+func (r *jobRepo) scanJob(row pgx.Row) (*models.JobDefinition, error) {
+	var s jobDefinitionScan
+	if err := row.Scan(s.dest()...); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-
-	j.Description = desc
-	_ = json.Unmarshal(assignedB, &j.AssignedUnitsByBuilding)
-	j.Floors = floors
-	j.TotalUnits = totalUnits
-	j.DumpsterIDs = dumpsters
-	j.Status = models.JobStatusType(status)
-	j.Frequency = models.JobFrequencyType(freq)
-	j.Weekdays = weekdays
-	j.IntervalWeeks = interval
-	j.StartDate = *startDate
-	j.EndDate = endDate
-	j.EarliestStartTime = eStart
-	j.LatestStartTime = lStart
-	j.StartTimeHint = sHint
-	j.HolidayExceptions = holExc
-
-	_ = json.Unmarshal(detailsB, &j.Details)
-	_ = json.Unmarshal(reqB, &j.Requirements)
-	_ = json.Unmarshal(dailyPayEstB, &j.DailyPayEstimates) // NEW
-	_ = json.Unmarshal(compB, &j.CompletionRules)
-	_ = json.Unmarshal(suppB, &j.SupportContact)
-
-	return &j, nil
+	return s.result(), nil
 }
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/* ------------------------------------------------------------------
+   Geospatial candidate selection
+------------------------------------------------------------------ */
+
+// NearbyInstanceQuery selects instances whose property lies within RadiusMiles
+// of (Lat, Lng). A nil RadiusMiles disables the geo filter entirely.
+type NearbyInstanceQuery struct {
+	Lat         float64
+	Lng         float64
+	RadiusMiles *float64
+
+	Statuses       []models.InstanceStatusType
+	StartDate      time.Time
+	EndDate        time.Time
+	DemoProperties *bool
+}
+
+// NearbyInstance is one instance with its definition and property loaded in
+// the same round trip.
+type NearbyInstance struct {
+	Instance      *models.JobInstance
+	Definition    *models.JobDefinition
+	Property      *models.Property
+	DistanceMiles float64
+}
+
+func (r *jobInstanceRepo) ListInstancesNear(
+	ctx context.Context,
+	q NearbyInstanceQuery,
+) ([]*NearbyInstance, error) {
+	var (
+		qb   strings.Builder
+		args []any
+		idx  = 1
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		p := "$" + strconv.Itoa(idx)
+		idx++
+		return p
+	}
+
+	lat := arg(q.Lat) + "::float8"
+	lng := arg(q.Lng) + "::float8"
+	distanceExpr := haversineMilesExpr("p", lat, lng)
+
+	qb.WriteString(`
+            SELECT
+                ` + qualifyColumns("ji", instanceColumns) + `,
+                ` + qualifyColumns("jd", jobDefinitionColumns) + `,
+                ` + qualifyColumns("p", propertyColumns) + `,
+                ` + distanceExpr + ` AS distance_miles
+            FROM job_instances ji
+            JOIN job_definitions jd ON jd.id = ji.definition_id
+            JOIN properties p ON p.id = jd.property_id
+            WHERE ji.service_date >= ` + arg(q.StartDate.Format("2006-01-02")) + `
+              AND ji.service_date <= ` + arg(q.EndDate.Format("2006-01-02")))
+
+	if len(q.Statuses) > 0 {
+		st := make([]string, 0, len(q.Statuses))
+		for _, s := range q.Statuses {
+			st = append(st, string(s))
+		}
+		qb.WriteString(" AND ji.status = ANY(" + arg(st) + ")")
+	}
+	if q.DemoProperties != nil {
+		qb.WriteString(" AND p.is_demo = " + arg(*q.DemoProperties))
+	}
+	if q.RadiusMiles != nil {
+		cells := utils.GeohashCoverRadius(q.Lat, q.Lng, *q.RadiusMiles)
+		qb.WriteString(" AND " + geohashPrefixClause("p.geohash", cells, arg))
+		qb.WriteString(" AND " + distanceExpr + " <= " + arg(*q.RadiusMiles))
+	}
+	qb.WriteString(" ORDER BY distance_miles, ji.service_date, ji.id")
+
+	rows, err := r.db.Query(ctx, qb.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*NearbyInstance
+	for rows.Next() {
+		var inst models.JobInstance
+		var def jobDefinitionScan
+		var prop models.Property
+		var dist float64
+
+		dest := instanceScanDest(&inst)
+		dest = append(dest, def.dest()...)
+		dest = append(dest, propertyScanDest(&prop)...)
+		dest = append(dest, &dist)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		out = append(out, &NearbyInstance{
+			Instance:      &inst,
+			Definition:    def.result(),
+			Property:      &prop,
+			DistanceMiles: dist,
+		})
+	}
+	return out, rows.Err()
+}
+
+/* ---------- SQL helpers ---------- */
+
+// qualifyColumns renders a select list, prefixing each column with alias when
+// one is given.
+func qualifyColumns(alias string, cols []string) string {
+	if alias == "" {
+		return strings.Join(cols, ", ")
+	}
+	qualified := make([]string, len(cols))
+	for i, c := range cols {
+		qualified[i] = alias + "." + c
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// haversineMilesExpr is the great-circle distance in miles between a
+// properties row (by alias) and the given lat/lng SQL expressions.
+func haversineMilesExpr(alias, lat, lng string) string {
+	return strconv.FormatFloat(earthRadiusMiles, 'f', -1, 64) + ` * 2 * ASIN(SQRT(
+                POWER(SIN(RADIANS(` + alias + `.latitude::float8 - ` + lat + `) / 2), 2)
+                + COS(RADIANS(` + lat + `)) * COS(RADIANS(` + alias + `.latitude::float8))
+                * POWER(SIN(RADIANS(` + alias + `.longitude::float8 - ` + lng + `) / 2), 2)))`
+}
+
+// geohashPrefixClause matches column against any of the prefixes as
+// half-open ranges, which the C-collated btree index on geohash can serve.
+func geohashPrefixClause(column string, prefixes []string, arg func(any) string) string {
+	parts := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		parts = append(parts, "("+column+" >= "+arg(p)+" AND "+column+" < "+arg(p+"~")+")")
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
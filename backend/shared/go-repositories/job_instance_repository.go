@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -10,12 +11,16 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
 	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
 )
 
 type JobInstanceRepository interface {
 	Create(ctx context.Context, inst *models.JobInstance) error
 	CreateIfNotExists(ctx context.Context, inst *models.JobInstance) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.JobInstance, error)
+	// ListInstancesByIDs returns the instances that exist among ids, by
+	// service date.
+	ListInstancesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.JobInstance, error)
 
 	ListInstancesByDateRange(
 		ctx context.Context,
@@ -31,11 +36,23 @@ type JobInstanceRepository interface {
 		startDate, endDate time.Time,
 	) ([]*models.JobInstance, error)
 
+	// SearchInstances runs a filtered, keyset-paginated search joined with
+	// definitions and properties. See JobInstanceSearchFilter.
+	SearchInstances(ctx context.Context, f JobInstanceSearchFilter) ([]*JobInstanceSearchRow, error)
+
+	// ListInstancesNear joins instances with their definitions and properties,
+	// limited by a geohash-indexed radius around the caller.
+	ListInstancesNear(ctx context.Context, q NearbyInstanceQuery) ([]*NearbyInstance, error)
+
 	AcceptInstanceAtomic(ctx context.Context, instanceID uuid.UUID, workerID uuid.UUID, expectedVersion int64, newAssignCount int, flagged bool) (*models.JobInstance, error)
 	UnassignInstanceAtomic(ctx context.Context, instanceID uuid.UUID, expectedVersion int64, newAssignCount int, flagged bool) (*models.JobInstance, error)
 	UpdateStatusAtomic(ctx context.Context, instanceID uuid.UUID, newStatus models.InstanceStatusType, expectedVersion int64) (*models.JobInstance, error)
 
-	UpdateEffectivePayAtomic(ctx context.Context, instanceID uuid.UUID, expectedVersion int64, newPay float64) error
+	// AddPayItemAtomic appends item to the instance's pay ledger and moves
+	// its effective pay by item.Amount. expectedVersion 0 skips the version
+	// check; the job's status must be one of statuses. Items on completed
+	// jobs publish job.pay_changed.
+	AddPayItemAtomic(ctx context.Context, item *models.JobPayItem, expectedVersion int64, statuses []models.InstanceStatusType) (*models.JobInstance, error)
 	UpdateStatusToInProgress(ctx context.Context, instanceID uuid.UUID, expectedVersion int64) (*models.JobInstance, error)
 	UpdateStatusToCompleted(ctx context.Context, instanceID uuid.UUID, expectedVersion int64) (*models.JobInstance, error)
 
@@ -52,8 +69,6 @@ type JobInstanceRepository interface {
 	DeleteFutureOpenInstances(ctx context.Context, defID uuid.UUID, today time.Time) error
 
 	AddExcludedWorker(ctx context.Context, instanceID uuid.UUID, workerID uuid.UUID) error
-	SetWarning90MinSent(ctx context.Context, instanceID uuid.UUID) error
-	SetWarning40MinSent(ctx context.Context, instanceID uuid.UUID) error
 }
 
 type jobInstanceRepo struct {
@@ -64,67 +79,73 @@ func NewJobInstanceRepository(db DB) JobInstanceRepository {
 	return &jobInstanceRepo{db: db}
 }
 
+var instanceColumns = []string{
+	"id", "definition_id", "service_date", "status",
+	"assigned_worker_id", "effective_pay_cents",
+	"segment_index", "segment_count",
+	"check_in_at", "check_out_at",
+	"excluded_worker_ids", "assign_unassign_count", "flagged_for_review",
+	"row_version", "created_at", "updated_at", "completed_by_agent_id",
+	"warning_90_min_sent_at", "warning_40_min_sent_at",
+}
+
 func baseSelectInstance() string {
-	return `
-        SELECT
-            id, definition_id, service_date, status,
-            assigned_worker_id, effective_pay,
-            check_in_at, check_out_at,
-            excluded_worker_ids, assign_unassign_count, flagged_for_review,
-            row_version, created_at, updated_at, completed_by_agent_id,
-            warning_90_min_sent_at, warning_40_min_sent_at
-        FROM job_instances
-    `
+	return "SELECT " + qualifyColumns("", instanceColumns) + " FROM job_instances "
 }
 
-func scanInstance(row pgx.Row) (*models.JobInstance, error) {
-	var inst models.JobInstance
-	var excluded []uuid.UUID
-	var checkIn, checkOut *time.Time
-	var warn90, warn40 *time.Time
-	err := row.Scan(
+// instanceScanDest returns scan targets matching instanceColumns.
+func instanceScanDest(inst *models.JobInstance) []any {
+	return []any{
 		&inst.ID,
 		&inst.DefinitionID,
 		&inst.ServiceDate,
 		&inst.Status,
 		&inst.AssignedWorkerID,
 		&inst.EffectivePay,
-		&checkIn,
-		&checkOut,
-		&excluded,
+		&inst.SegmentIndex,
+		&inst.SegmentCount,
+		&inst.CheckInAt,
+		&inst.CheckOutAt,
+		&inst.ExcludedWorkerIDs,
 		&inst.AssignUnassignCount,
 		&inst.FlaggedForReview,
 		&inst.RowVersion,
 		&inst.CreatedAt,
 		&inst.UpdatedAt,
 		&inst.CompletedByAgentID,
-		&warn90,
-		&warn40,
-	)
+		&inst.Warning90MinSentAt,
+		&inst.Warning40MinSentAt,
+	}
+}
+
+func scanInstance(row pgx.Row) (*models.JobInstance, error) {
+	var inst models.JobInstance
+	err := row.Scan(instanceScanDest(&inst)...)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	inst.CheckInAt = checkIn
-	inst.CheckOutAt = checkOut
-	inst.ExcludedWorkerIDs = excluded
-	inst.Warning90MinSentAt = warn90
-	inst.Warning40MinSentAt = warn40
 	return &inst, nil
 }
 
 func (r *jobInstanceRepo) Create(ctx context.Context, inst *models.JobInstance) error {
 	_, err := r.db.Exec(ctx, `
-        INSERT INTO job_instances (
-            id, definition_id, service_date, status,
-            assigned_worker_id, effective_pay,
-            excluded_worker_ids, assign_unassign_count, flagged_for_review,
-            created_at, updated_at, row_version
-        ) VALUES (
-            $1,$2,$3,$4,$5,$6,'{}',0,FALSE,NOW(),NOW(),1
+        WITH ins AS (
+            INSERT INTO job_instances (
+                id, definition_id, service_date, status,
+                assigned_worker_id, effective_pay_cents,
+                segment_index, segment_count,
+                excluded_worker_ids, assign_unassign_count, flagged_for_review,
+                created_at, updated_at, row_version
+            ) VALUES (
+                $1,$2,$3,$4,$5,$6,$7,$8,'{}',0,FALSE,NOW(),NOW(),1
+            )
+            RETURNING id, effective_pay_cents
         )
+        INSERT INTO job_pay_items (id, job_instance_id, kind, amount_cents, description)
+        SELECT gen_random_uuid(), id, $9, effective_pay_cents, $10 FROM ins
     `,
 		inst.ID,
 		inst.DefinitionID,
@@ -132,21 +153,31 @@ func (r *jobInstanceRepo) Create(ctx context.Context, inst *models.JobInstance)
 		inst.Status,
 		inst.AssignedWorkerID,
 		inst.EffectivePay,
+		inst.SegmentIndex,
+		segmentCountOrOne(inst.SegmentCount),
+		models.PayItemBase,
+		models.PayItemBase.Label(),
 	)
 	return err
 }
 
 func (r *jobInstanceRepo) CreateIfNotExists(ctx context.Context, inst *models.JobInstance) error {
 	_, err := r.db.Exec(ctx, `
-        INSERT INTO job_instances (
-            id, definition_id, service_date, status,
-            assigned_worker_id, effective_pay,
-            excluded_worker_ids, assign_unassign_count, flagged_for_review,
-            created_at, updated_at, row_version
-        ) VALUES (
-            $1,$2,$3,$4,$5,$6,'{}',0,FALSE,NOW(),NOW(),1
+        WITH ins AS (
+            INSERT INTO job_instances (
+                id, definition_id, service_date, status,
+                assigned_worker_id, effective_pay_cents,
+                segment_index, segment_count,
+                excluded_worker_ids, assign_unassign_count, flagged_for_review,
+                created_at, updated_at, row_version
+            ) VALUES (
+                $1,$2,$3,$4,$5,$6,$7,$8,'{}',0,FALSE,NOW(),NOW(),1
+            )
+            ON CONFLICT (definition_id, service_date, segment_index) DO NOTHING
+            RETURNING id, effective_pay_cents
         )
-        ON CONFLICT (definition_id, service_date) DO NOTHING
+        INSERT INTO job_pay_items (id, job_instance_id, kind, amount_cents, description)
+        SELECT gen_random_uuid(), id, $9, effective_pay_cents, $10 FROM ins
     `,
 		inst.ID,
 		inst.DefinitionID,
@@ -154,6 +185,10 @@ func (r *jobInstanceRepo) CreateIfNotExists(ctx context.Context, inst *models.Jo
 		inst.Status,
 		inst.AssignedWorkerID,
 		inst.EffectivePay,
+		inst.SegmentIndex,
+		segmentCountOrOne(inst.SegmentCount),
+		models.PayItemBase,
+		models.PayItemBase.Label(),
 	)
 	return err
 }
@@ -206,7 +241,7 @@ func (r *jobInstanceRepo) ListInstancesByDateRange(
 		idx++
 	}
 
-	qb.WriteString(" ORDER BY service_date")
+	qb.WriteString(" ORDER BY service_date, segment_index")
 	query := qb.String()
 
 	rows, err := r.db.Query(ctx, query, args...)
@@ -226,6 +261,27 @@ func (r *jobInstanceRepo) ListInstancesByDateRange(
 	return out, rows.Err()
 }
 
+func (r *jobInstanceRepo) ListInstancesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.JobInstance, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.Query(ctx, baseSelectInstance()+" WHERE id = ANY($1) ORDER BY service_date, segment_index", ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.JobInstance
+	for rows.Next() {
+		inst, err := scanInstance(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inst)
+	}
+	return out, rows.Err()
+}
+
 func (r *jobInstanceRepo) ListInstancesByDefinitionIDs(
 	ctx context.Context,
 	defIDs []uuid.UUID,
@@ -271,7 +327,7 @@ func (r *jobInstanceRepo) ListInstancesByDefinitionIDs(
 		idx++
 	}
 
-	qb.WriteString(" ORDER BY service_date")
+	qb.WriteString(" ORDER BY service_date, segment_index")
 	query := qb.String()
 
 	rows, err := r.db.Query(ctx, query, args...)
@@ -436,45 +492,71 @@ func (r *jobInstanceRepo) UpdateStatusAtomic(
 	return scanInstance(newRow)
 }
 
-func (r *jobInstanceRepo) UpdateEffectivePayAtomic(
+func (r *jobInstanceRepo) AddPayItemAtomic(
 	ctx context.Context,
-	instanceID uuid.UUID,
+	item *models.JobPayItem,
 	expectedVersion int64,
-	newPay float64,
-) error {
+	statuses []models.InstanceStatusType,
+) (_ *models.JobInstance, err error) {
+	if !item.Kind.Valid(item.Amount) || item.Kind == models.PayItemBase {
+		return nil, utils.ErrPayItemInvalid
+	}
+
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
-			tx.Rollback(ctx)
+			_ = tx.Rollback(ctx)
 		} else {
-			tx.Commit(ctx)
+			err = tx.Commit(ctx)
 		}
 	}()
 
-	row := tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1 FOR UPDATE", instanceID)
+	row := tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1 FOR UPDATE", item.JobInstanceID)
 	inst, err := scanInstance(row)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if inst == nil {
-		return fmt.Errorf("no rows found for job_instance=%s", instanceID)
+		return nil, pgx.ErrNoRows
 	}
-	if inst.RowVersion != expectedVersion {
-		return fmt.Errorf("row_version_conflict")
+	if expectedVersion != 0 && inst.RowVersion != expectedVersion {
+		err = utils.ErrRowVersionConflict
+		return inst, err
 	}
-	if inst.Status != models.InstanceStatusOpen {
-		return nil // do nothing if not open
+	if !slices.Contains(statuses, inst.Status) {
+		err = utils.ErrPayItemNotAllowed
+		return inst, err
+	}
+	if inst.EffectivePay.Add(item.Amount).IsNegative() {
+		err = utils.ErrPayBelowZero
+		return inst, err
 	}
 
+	if err = insertJobPayItem(ctx, tx, item); err != nil {
+		return nil, err
+	}
 	_, err = tx.Exec(ctx, `
         UPDATE job_instances
-        SET effective_pay=$1, row_version=row_version+1, updated_at=NOW()
+        SET effective_pay_cents=effective_pay_cents+$1, row_version=row_version+1, updated_at=NOW()
         WHERE id=$2
-    `, newPay, instanceID)
-	return err
+    `, item.Amount, item.JobInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := scanInstance(tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", item.JobInstanceID))
+	if err != nil {
+		return nil, err
+	}
+	if updated.Status == models.InstanceStatusCompleted {
+		ev := models.JobPayChangedEvent{JobInstanceEvent: models.NewJobInstanceEvent(updated), Item: *item}
+		if err = PublishEvent(ctx, tx, models.EventJobPayChanged, updated.ID, ev); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
 }
 
 func (r *jobInstanceRepo) UpdateStatusToInProgress(
@@ -561,8 +643,14 @@ func (r *jobInstanceRepo) UpdateStatusToCompleted(
 	if err != nil {
 		return nil, err
 	}
-	newRow := tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID)
-	return scanInstance(newRow)
+	updated, err := scanInstance(tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if err = PublishEvent(ctx, tx, models.EventJobCompleted, updated.ID, models.NewJobInstanceEvent(updated)); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 // CompleteByAgent sets a job instance to COMPLETED and records the agent responsible.
@@ -603,8 +691,14 @@ func (r *jobInstanceRepo) CompleteByAgent(ctx context.Context, instanceID uuid.U
 	if err != nil {
 		return nil, err
 	}
-	newRow := tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID)
-	return scanInstance(newRow)
+	updated, err := scanInstance(tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if err = PublishEvent(ctx, tx, models.EventJobCompleted, updated.ID, models.NewJobInstanceEvent(updated)); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 // NEW: UpdateStatusToCancelled
@@ -648,8 +742,14 @@ func (r *jobInstanceRepo) UpdateStatusToCancelled(
 	if err != nil {
 		return nil, err
 	}
-	newRow := tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID)
-	return scanInstance(newRow)
+	updated, err := scanInstance(tx.QueryRow(ctx, baseSelectInstance()+" WHERE id=$1", instanceID))
+	if err != nil {
+		return nil, err
+	}
+	if err = PublishEvent(ctx, tx, models.EventJobCanceled, updated.ID, models.NewJobInstanceEvent(updated)); err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
 // NEW: RevertInProgressToOpenAtomic
@@ -756,22 +856,9 @@ func (r *jobInstanceRepo) AddExcludedWorker(ctx context.Context, instanceID uuid
 	return err
 }
 
-func (r *jobInstanceRepo) SetWarning90MinSent(ctx context.Context, instanceID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
-        UPDATE job_instances
-        SET warning_90_min_sent_at=NOW(),
-            updated_at=NOW()
-        WHERE id=$1
-    `, instanceID)
-	return err
-}
-
-func (r *jobInstanceRepo) SetWarning40MinSent(ctx context.Context, instanceID uuid.UUID) error {
-	_, err := r.db.Exec(ctx, `
-        UPDATE job_instances
-        SET warning_40_min_sent_at=NOW(),
-            updated_at=NOW()
-        WHERE id=$1
-    `, instanceID)
-	return err
+func segmentCountOrOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
 }
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/* ------------------------------------------------------------------
+   Filtered, keyset-paginated instance search
+------------------------------------------------------------------ */
+
+type JobSearchSort string
+
+const (
+	JobSearchSortDistance JobSearchSort = "distance"
+	JobSearchSortPay      JobSearchSort = "pay"
+	JobSearchSortDate     JobSearchSort = "date"
+)
+
+// JobSearchCursor is the keyset position of the last row a caller consumed.
+// SortKey is always ascending; descending sorts store the negated value.
+type JobSearchCursor struct {
+	SortKey float64   `json:"k"`
+	ID      uuid.UUID `json:"id"`
+}
+
+// JobInstanceSearchFilter narrows a search over job instances joined with their
+// definitions and properties. Zero values mean "no filter".
+type JobInstanceSearchFilter struct {
+	Statuses  []models.InstanceStatusType
+	StartDate time.Time
+	EndDate   time.Time
+
+	// Origin enables distance filtering and sorting (great-circle miles).
+	OriginLat        *float64
+	OriginLng        *float64
+	MaxDistanceMiles *float64
+
+	MinPay              *models.Money
+	PropertyIDs         []uuid.UUID
+	VehicleRequirements []models.VehicleRequirementType
+	MaxEstimatedMinutes *int
+
+	ExcludeWorkerID *uuid.UUID
+	DemoProperties  *bool
+
+	Sort  JobSearchSort
+	After *JobSearchCursor
+	Limit int
+}
+
+// JobInstanceSearchRow carries the keyset values alongside each instance so
+// callers can mint the next cursor without recomputing them.
+type JobInstanceSearchRow struct {
+	Instance         *models.JobInstance
+	PropertyID       uuid.UUID
+	DistanceMiles    *float64
+	EstimatedMinutes int
+	SortKey          float64
+}
+
+const earthRadiusMiles = 3958.8
+
+func (r *jobInstanceRepo) SearchInstances(
+	ctx context.Context,
+	f JobInstanceSearchFilter,
+) ([]*JobInstanceSearchRow, error) {
+	var (
+		qb   strings.Builder
+		args []any
+		idx  = 1
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		p := "$" + strconv.Itoa(idx)
+		idx++
+		return p
+	}
+
+	distanceExpr := "NULL::float8"
+	if f.OriginLat != nil && f.OriginLng != nil {
+		lat := arg(*f.OriginLat) + "::float8"
+		lng := arg(*f.OriginLng) + "::float8"
+		distanceExpr = haversineMilesExpr("p", lat, lng)
+	}
+
+	// Per-day estimate from the definition, scaled down for split-job segments.
+	estimateExpr := `COALESCE((
+                SELECT (e->>'estimated_time_minutes')::numeric
+                FROM jsonb_array_elements(jd.daily_pay_estimates) e
+                WHERE (e->>'day_of_week')::int = EXTRACT(DOW FROM ji.service_date)::int
+                LIMIT 1
+            ), 0)
+            * CASE WHEN ji.segment_count > 1 AND jd.total_units > 0
+                   THEN COALESCE((jd.segments->ji.segment_index->>'unit_count')::numeric, jd.total_units) / jd.total_units
+                   ELSE 1 END`
+
+	var sortExpr string
+	switch f.Sort {
+	case JobSearchSortPay:
+		sortExpr = "-(ji.effective_pay_cents::float8)"
+	case JobSearchSortDistance:
+		if f.OriginLat != nil && f.OriginLng != nil {
+			sortExpr = "(" + distanceExpr + ")"
+			break
+		}
+		fallthrough
+	default:
+		sortExpr = "EXTRACT(EPOCH FROM ji.service_date)::float8"
+	}
+
+	qb.WriteString(`
+        SELECT * FROM (
+            SELECT
+                ` + qualifyColumns("ji", instanceColumns) + `,
+                jd.property_id,
+                ` + distanceExpr + ` AS distance_miles,
+                ROUND(` + estimateExpr + `)::int AS estimated_minutes,
+                ` + sortExpr + ` AS sort_key
+            FROM job_instances ji
+            JOIN job_definitions jd ON jd.id = ji.definition_id
+            JOIN properties p ON p.id = jd.property_id
+            WHERE ji.service_date >= ` + arg(f.StartDate.Format("2006-01-02")) + `
+              AND ji.service_date <= ` + arg(f.EndDate.Format("2006-01-02")))
+
+	if len(f.Statuses) > 0 {
+		st := make([]string, 0, len(f.Statuses))
+		for _, s := range f.Statuses {
+			st = append(st, string(s))
+		}
+		qb.WriteString(" AND ji.status = ANY(" + arg(st) + ")")
+	}
+	if f.MinPay != nil {
+		qb.WriteString(" AND ji.effective_pay_cents >= " + arg(*f.MinPay))
+	}
+	if len(f.PropertyIDs) > 0 {
+		qb.WriteString(" AND jd.property_id = ANY(" + arg(f.PropertyIDs) + ")")
+	}
+	if len(f.VehicleRequirements) > 0 {
+		vr := make([]string, 0, len(f.VehicleRequirements))
+		for _, v := range f.VehicleRequirements {
+			vr = append(vr, string(v))
+		}
+		qb.WriteString(" AND COALESCE(NULLIF(jd.requirements->>'vehicle_requirement', ''), '" +
+			string(models.VehicleNone) + "') = ANY(" + arg(vr) + ")")
+	}
+	if f.ExcludeWorkerID != nil {
+		qb.WriteString(" AND NOT (" + arg(*f.ExcludeWorkerID) + "::uuid = ANY(ji.excluded_worker_ids))")
+	}
+	if f.DemoProperties != nil {
+		qb.WriteString(" AND p.is_demo = " + arg(*f.DemoProperties))
+	}
+	if f.MaxDistanceMiles != nil && f.OriginLat != nil && f.OriginLng != nil {
+		cells := utils.GeohashCoverRadius(*f.OriginLat, *f.OriginLng, *f.MaxDistanceMiles)
+		qb.WriteString(" AND " + geohashPrefixClause("p.geohash", cells, arg))
+	}
+	qb.WriteString("\n        ) s WHERE TRUE")
+
+	if f.MaxDistanceMiles != nil && f.OriginLat != nil && f.OriginLng != nil {
+		qb.WriteString(" AND s.distance_miles <= " + arg(*f.MaxDistanceMiles))
+	}
+	if f.MaxEstimatedMinutes != nil {
+		qb.WriteString(" AND s.estimated_minutes <= " + arg(*f.MaxEstimatedMinutes))
+	}
+	if f.After != nil {
+		qb.WriteString(" AND (s.sort_key, s.id) > (" + arg(f.After.SortKey) + "::float8, " + arg(f.After.ID) + "::uuid)")
+	}
+
+	qb.WriteString(" ORDER BY s.sort_key, s.id")
+	if f.Limit > 0 {
+		qb.WriteString(" LIMIT " + arg(f.Limit))
+	}
+
+	rows, err := r.db.Query(ctx, qb.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*JobInstanceSearchRow
+	for rows.Next() {
+		var inst models.JobInstance
+		var row JobInstanceSearchRow
+		dest := append(instanceScanDest(&inst),
+			&row.PropertyID,
+			&row.DistanceMiles,
+			&row.EstimatedMinutes,
+			&row.SortKey,
+		)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row.Instance = &inst
+		out = append(out, &row)
+	}
+	return out, rows.Err()
+}
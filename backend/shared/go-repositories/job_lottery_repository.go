@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/* ------------------------------------------------------------------
+   Public interface
+------------------------------------------------------------------ */
+
+type JobLotteryRepository interface {
+	// CreateEntry inserts e unless the worker already entered for the
+	// instance; either way the stored entry is returned.
+	CreateEntry(ctx context.Context, e *models.JobLotteryEntry) (*models.JobLotteryEntry, error)
+	GetEntry(ctx context.Context, instanceID, workerID uuid.UUID) (*models.JobLotteryEntry, error)
+	ListPendingEntries(ctx context.Context, instanceID uuid.UUID) ([]*models.JobLotteryEntry, error)
+	ListEntries(ctx context.Context, instanceID uuid.UUID) ([]*models.JobLotteryEntry, error)
+	HasPendingEntries(ctx context.Context, instanceID uuid.UUID) (bool, error)
+
+	// ListDueInstanceIDs returns instances with pending entries whose entry
+	// window closed at or before now.
+	ListDueInstanceIDs(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+
+	// RecordDraw stores the draw and resolves every pending entry for its
+	// instance: the winner (if any) is WON, the rest LOST with lossReasons
+	// keyed by entry ID (defaulting to not_selected).
+	RecordDraw(ctx context.Context, d *models.JobLotteryDraw, winnerEntryID *uuid.UUID, lossReasons map[uuid.UUID]string) error
+	GetDraw(ctx context.Context, id uuid.UUID) (*models.JobLotteryDraw, error)
+
+	// GetMarketAllocation returns the market's allocation mode, or nil when
+	// none has been set.
+	GetMarketAllocation(ctx context.Context, market string) (*models.MarketAllocation, error)
+	UpsertMarketAllocation(ctx context.Context, a *models.MarketAllocation) error
+	ListMarketAllocations(ctx context.Context) ([]*models.MarketAllocation, error)
+}
+
+/* ------------------------------------------------------------------
+   Implementation
+------------------------------------------------------------------ */
+
+type jobLotteryRepo struct {
+	db DB
+}
+
+func NewJobLotteryRepository(db DB) JobLotteryRepository {
+	return &jobLotteryRepo{db: db}
+}
+
+const lotteryEntryColumns = `
+    id, instance_id, worker_id, status, reliability_score, distance_miles,
+    weight, window_closes_at, draw_id, loss_reason, created_at, resolved_at`
+
+func scanLotteryEntry(row pgx.Row) (*models.JobLotteryEntry, error) {
+	var e models.JobLotteryEntry
+	err := row.Scan(
+		&e.ID, &e.InstanceID, &e.WorkerID, &e.Status, &e.ReliabilityScore, &e.DistanceMiles,
+		&e.Weight, &e.WindowClosesAt, &e.DrawID, &e.LossReason, &e.CreatedAt, &e.ResolvedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *jobLotteryRepo) CreateEntry(ctx context.Context, e *models.JobLotteryEntry) (*models.JobLotteryEntry, error) {
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO job_lottery_entries (
+            id, instance_id, worker_id, status, reliability_score,
+            distance_miles, weight, window_closes_at
+        ) VALUES ($1,$2,$3,'PENDING',$4,$5,$6,$7)
+        ON CONFLICT (instance_id, worker_id) DO NOTHING
+    `, e.ID, e.InstanceID, e.WorkerID, e.ReliabilityScore, e.DistanceMiles, e.Weight, e.WindowClosesAt)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetEntry(ctx, e.InstanceID, e.WorkerID)
+}
+
+func (r *jobLotteryRepo) GetEntry(ctx context.Context, instanceID, workerID uuid.UUID) (*models.JobLotteryEntry, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+lotteryEntryColumns+`
+        FROM job_lottery_entries WHERE instance_id=$1 AND worker_id=$2`, instanceID, workerID)
+	return scanLotteryEntry(row)
+}
+
+func (r *jobLotteryRepo) ListPendingEntries(ctx context.Context, instanceID uuid.UUID) ([]*models.JobLotteryEntry, error) {
+	return r.listEntries(ctx, `SELECT `+lotteryEntryColumns+`
+        FROM job_lottery_entries
+        WHERE instance_id=$1 AND status='PENDING'
+        ORDER BY created_at, id`, instanceID)
+}
+
+func (r *jobLotteryRepo) ListEntries(ctx context.Context, instanceID uuid.UUID) ([]*models.JobLotteryEntry, error) {
+	return r.listEntries(ctx, `SELECT `+lotteryEntryColumns+`
+        FROM job_lottery_entries
+        WHERE instance_id=$1
+        ORDER BY created_at, id`, instanceID)
+}
+
+func (r *jobLotteryRepo) listEntries(ctx context.Context, query string, args ...any) ([]*models.JobLotteryEntry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.JobLotteryEntry
+	for rows.Next() {
+		e, err := scanLotteryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *jobLotteryRepo) HasPendingEntries(ctx context.Context, instanceID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM job_lottery_entries WHERE instance_id=$1 AND status='PENDING'
+        )`, instanceID).Scan(&exists)
+	return exists, err
+}
+
+func (r *jobLotteryRepo) ListDueInstanceIDs(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT instance_id
+        FROM job_lottery_entries
+        WHERE status='PENDING'
+        GROUP BY instance_id
+        HAVING MAX(window_closes_at) <= $1
+    `, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (r *jobLotteryRepo) RecordDraw(
+	ctx context.Context,
+	d *models.JobLotteryDraw,
+	winnerEntryID *uuid.UUID,
+	lossReasons map[uuid.UUID]string,
+) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	entries, _ := json.Marshal(d.Entries)
+	_, err = tx.Exec(ctx, `
+        INSERT INTO job_lottery_draws (
+            id, instance_id, seed, roll, total_weight, winner_worker_id, entries, drawn_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+    `, d.ID, d.InstanceID, d.Seed, d.Roll, d.TotalWeight, d.WinnerWorkerID, entries, d.DrawnAt)
+	if err != nil {
+		return err
+	}
+
+	if winnerEntryID != nil {
+		_, err = tx.Exec(ctx, `
+            UPDATE job_lottery_entries
+            SET status='WON', draw_id=$1, resolved_at=$2
+            WHERE id=$3 AND status='PENDING'
+        `, d.ID, d.DrawnAt, *winnerEntryID)
+		if err != nil {
+			return err
+		}
+	}
+	for entryID, reason := range lossReasons {
+		_, err = tx.Exec(ctx, `
+            UPDATE job_lottery_entries
+            SET status='LOST', loss_reason=$1, draw_id=$2, resolved_at=$3
+            WHERE id=$4 AND status='PENDING'
+        `, reason, d.ID, d.DrawnAt, entryID)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = tx.Exec(ctx, `
+        UPDATE job_lottery_entries
+        SET status='LOST', loss_reason=$1, draw_id=$2, resolved_at=$3
+        WHERE instance_id=$4 AND status='PENDING'
+    `, models.LotteryLossNotSelected, d.ID, d.DrawnAt, d.InstanceID)
+	return err
+}
+
+func (r *jobLotteryRepo) GetDraw(ctx context.Context, id uuid.UUID) (*models.JobLotteryDraw, error) {
+	var d models.JobLotteryDraw
+	var entries []byte
+	err := r.db.QueryRow(ctx, `
+        SELECT id, instance_id, seed, roll, total_weight, winner_worker_id, entries, drawn_at
+        FROM job_lottery_draws WHERE id=$1
+    `, id).Scan(&d.ID, &d.InstanceID, &d.Seed, &d.Roll, &d.TotalWeight, &d.WinnerWorkerID, &entries, &d.DrawnAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(entries, &d.Entries)
+	return &d, nil
+}
+
+const marketAllocationColumns = `market, allocation_mode, lottery_window_seconds, updated_by, updated_at`
+
+func scanMarketAllocation(row pgx.Row) (*models.MarketAllocation, error) {
+	var a models.MarketAllocation
+	err := row.Scan(&a.Market, &a.AllocationMode, &a.LotteryWindowSeconds, &a.UpdatedBy, &a.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *jobLotteryRepo) GetMarketAllocation(ctx context.Context, market string) (*models.MarketAllocation, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+marketAllocationColumns+`
+        FROM market_allocation_modes WHERE market=$1`, market)
+	return scanMarketAllocation(row)
+}
+
+func (r *jobLotteryRepo) UpsertMarketAllocation(ctx context.Context, a *models.MarketAllocation) error {
+	a.LotteryWindowSeconds = lotteryWindowOrDefault(a.LotteryWindowSeconds)
+	return r.db.QueryRow(ctx, `
+        INSERT INTO market_allocation_modes (
+            market, allocation_mode, lottery_window_seconds, updated_by, updated_at
+        ) VALUES ($1,$2,$3,$4,NOW())
+        ON CONFLICT (market) DO UPDATE SET
+            allocation_mode=EXCLUDED.allocation_mode,
+            lottery_window_seconds=EXCLUDED.lottery_window_seconds,
+            updated_by=EXCLUDED.updated_by,
+            updated_at=EXCLUDED.updated_at
+        RETURNING updated_at
+    `, a.Market, string(a.AllocationMode), a.LotteryWindowSeconds, a.UpdatedBy).Scan(&a.UpdatedAt)
+}
+
+func (r *jobLotteryRepo) ListMarketAllocations(ctx context.Context) ([]*models.MarketAllocation, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+marketAllocationColumns+`
+        FROM market_allocation_modes ORDER BY market`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.MarketAllocation
+	for rows.Next() {
+		a, err := scanMarketAllocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
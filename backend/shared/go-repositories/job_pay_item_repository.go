@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+JobPayItemRepository reads the per-instance pay ledger. Items are written
+by JobInstanceRepository (Create, CreateIfNotExists and AddPayItemAtomic)
+together with the instance's effective pay, so there is no write method
+here.
+*/
+type JobPayItemRepository interface {
+	// ListByInstance returns the instance's items oldest first.
+	ListByInstance(ctx context.Context, instanceID uuid.UUID) ([]*models.JobPayItem, error)
+	// ListByInstances returns each instance's items oldest first.
+	ListByInstances(ctx context.Context, instanceIDs []uuid.UUID) (map[uuid.UUID][]*models.JobPayItem, error)
+	// TotalsByInstances sums each instance's items. Instances without items
+	// are left out.
+	TotalsByInstances(ctx context.Context, instanceIDs []uuid.UUID) (map[uuid.UUID]models.Money, error)
+	// SummarizeByKind totals the items of completed jobs with a service date
+	// in [start, end], by kind.
+	SummarizeByKind(ctx context.Context, start, end time.Time) ([]models.PayKindTotal, error)
+}
+
+type jobPayItemRepo struct {
+	db DB
+}
+
+func NewJobPayItemRepository(db DB) JobPayItemRepository {
+	return &jobPayItemRepo{db: db}
+}
+
+const jobPayItemSelect = `
+    SELECT id, job_instance_id, kind, amount_cents, description,
+           actor_id, surge_policy_id, details, created_at
+    FROM job_pay_items`
+
+func scanJobPayItem(row pgx.Row) (*models.JobPayItem, error) {
+	var (
+		it      models.JobPayItem
+		details []byte
+	)
+	err := row.Scan(
+		&it.ID, &it.JobInstanceID, &it.Kind, &it.Amount, &it.Description,
+		&it.ActorID, &it.SurgePolicyID, &details, &it.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(details, &it.Details)
+	return &it, nil
+}
+
+// insertJobPayItem writes it, filling in its ID, description and created_at
+// when unset. Callers move the instance's effective pay in the same
+// transaction.
+func insertJobPayItem(ctx context.Context, q DB, it *models.JobPayItem) error {
+	if it.ID == uuid.Nil {
+		it.ID = uuid.New()
+	}
+	if it.Description == "" {
+		it.Description = it.Kind.Label()
+	}
+	details, _ := json.Marshal(it.Details)
+	if it.Details == nil {
+		details = []byte("{}")
+	}
+	return q.QueryRow(ctx, `
+        INSERT INTO job_pay_items (
+            id, job_instance_id, kind, amount_cents, description,
+            actor_id, surge_policy_id, details, created_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NOW())
+        RETURNING created_at
+    `, it.ID, it.JobInstanceID, it.Kind, it.Amount, it.Description,
+		it.ActorID, it.SurgePolicyID, details).Scan(&it.CreatedAt)
+}
+
+func (r *jobPayItemRepo) ListByInstance(ctx context.Context, instanceID uuid.UUID) ([]*models.JobPayItem, error) {
+	items, err := r.ListByInstances(ctx, []uuid.UUID{instanceID})
+	if err != nil {
+		return nil, err
+	}
+	return items[instanceID], nil
+}
+
+func (r *jobPayItemRepo) ListByInstances(
+	ctx context.Context,
+	instanceIDs []uuid.UUID,
+) (map[uuid.UUID][]*models.JobPayItem, error) {
+	out := make(map[uuid.UUID][]*models.JobPayItem, len(instanceIDs))
+	if len(instanceIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, jobPayItemSelect+`
+        WHERE job_instance_id = ANY($1)
+        ORDER BY job_instance_id, created_at, id
+    `, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		it, err := scanJobPayItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[it.JobInstanceID] = append(out[it.JobInstanceID], it)
+	}
+	return out, rows.Err()
+}
+
+func (r *jobPayItemRepo) TotalsByInstances(
+	ctx context.Context,
+	instanceIDs []uuid.UUID,
+) (map[uuid.UUID]models.Money, error) {
+	out := make(map[uuid.UUID]models.Money, len(instanceIDs))
+	if len(instanceIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, `
+        SELECT job_instance_id, SUM(amount_cents)::BIGINT
+        FROM job_pay_items
+        WHERE job_instance_id = ANY($1)
+        GROUP BY job_instance_id
+    `, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    uuid.UUID
+			total models.Money
+		)
+		if err := rows.Scan(&id, &total); err != nil {
+			return nil, err
+		}
+		out[id] = total
+	}
+	return out, rows.Err()
+}
+
+func (r *jobPayItemRepo) SummarizeByKind(ctx context.Context, start, end time.Time) ([]models.PayKindTotal, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT p.kind, SUM(p.amount_cents)::BIGINT, COUNT(*), COUNT(DISTINCT p.job_instance_id)
+        FROM job_pay_items p
+        JOIN job_instances ji ON ji.id = p.job_instance_id
+        WHERE ji.status = 'COMPLETED'
+          AND ji.service_date BETWEEN $1 AND $2
+        GROUP BY p.kind
+        ORDER BY p.kind
+    `, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.PayKindTotal
+	for rows.Next() {
+		var t models.PayKindTotal
+		if err := rows.Scan(&t.Kind, &t.Amount, &t.ItemCount, &t.JobCount); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
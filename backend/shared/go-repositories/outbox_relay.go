@@ -0,0 +1,373 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+	"github.com/poofware/mono-repo/backend/shared/go-utils"
+)
+
+/*
+OutboxRelay delivers outbox events to the handlers a service subscribes.
+It LISTENs on OutboxChannel to wake up as soon as an event commits, and polls
+as a fallback for notifications lost while it was disconnected.
+
+Each relay is a named consumer with an offset in outbox_consumer_offsets.
+Replicas of one service share the consumer name; the offset row is locked
+while a batch is delivered, so only one replica delivers at a time. The
+offset is saved after the handlers return, so delivery is at-least-once: a
+crash between a handler and the commit repeats the event, and handlers must
+be idempotent. A handler error stops the batch, the offset up to the failed
+event is saved, and the failed event is retried after RetryDelay. After
+MaxAttempts consecutive failures the event is copied to outbox_dead_letters
+and skipped, so one poison event can't hold up the consumer. Events on
+topics with no handler are skipped.
+
+Only events from transactions older than every transaction still in flight
+are read, so an event with a lower (tx_id, id) can't commit after the
+offset has moved past it.
+*/
+
+const (
+	DefaultOutboxBatchSize    = 100
+	DefaultOutboxPollInterval = 30 * time.Second
+	DefaultOutboxRetryDelay   = 10 * time.Second
+	DefaultOutboxMaxAttempts  = 10
+	DefaultOutboxRetention    = 14 * 24 * time.Hour
+	outboxPurgeInterval       = time.Hour
+)
+
+type OutboxHandler func(ctx context.Context, ev *models.OutboxEvent) error
+
+type OutboxRelayOptions struct {
+	BatchSize    int
+	PollInterval time.Duration // wake-up when no notification arrives
+	RetryDelay   time.Duration // wait after a handler or database error
+	MaxAttempts  int           // failures before an event is dead-lettered
+	Retention    time.Duration // delivered events older than this are purged
+}
+
+type OutboxRelay struct {
+	consumer string
+	pool     *pgxpool.Pool
+	outbox   OutboxRepository
+	opts     OutboxRelayOptions
+
+	mu       sync.RWMutex
+	handlers map[string]OutboxHandler
+
+	wake      chan struct{}
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	lastPurge time.Time
+}
+
+func NewOutboxRelay(consumer string, pool *pgxpool.Pool, opts OutboxRelayOptions) *OutboxRelay {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultOutboxBatchSize
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultOutboxPollInterval
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = DefaultOutboxRetryDelay
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultOutboxRetention
+	}
+	return &OutboxRelay{
+		consumer: consumer,
+		pool:     pool,
+		outbox:   NewOutboxRepository(pool),
+		opts:     opts,
+		handlers: make(map[string]OutboxHandler),
+		wake:     make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Subscribe registers the handler for a topic. Call it before Start.
+func (r *OutboxRelay) Subscribe(topic string, h OutboxHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[topic] = h
+}
+
+// SubscribeEvent registers a handler that receives the decoded payload. An
+// event whose payload does not decode is logged and skipped.
+func SubscribeEvent[T any](r *OutboxRelay, topic string, fn func(ctx context.Context, ev *models.OutboxEvent, payload T) error) {
+	r.Subscribe(topic, func(ctx context.Context, ev *models.OutboxEvent) error {
+		var payload T
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			utils.Logger.WithError(err).Errorf("Outbox %s: skipping event %d with bad %s payload", r.consumer, ev.ID, topic)
+			return nil
+		}
+		return fn(ctx, ev, payload)
+	})
+}
+
+func (r *OutboxRelay) Start() {
+	go r.listenLoop()
+	go r.deliverLoop()
+}
+
+// Stop waits for the batch in progress to finish.
+func (r *OutboxRelay) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		<-r.doneCh
+	})
+}
+
+func (r *OutboxRelay) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// listenLoop holds a dedicated connection in LISTEN and turns notifications
+// into wake-ups for deliverLoop.
+func (r *OutboxRelay) listenLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-r.stopCh
+		cancel()
+	}()
+
+	for ctx.Err() == nil {
+		if err := r.listen(ctx); err != nil && ctx.Err() == nil {
+			utils.Logger.WithError(err).Warnf("Outbox %s: LISTEN connection lost; relying on polling", r.consumer)
+			select {
+			case <-ctx.Done():
+			case <-time.After(r.opts.RetryDelay):
+			}
+		}
+	}
+}
+
+func (r *OutboxRelay) listen(ctx context.Context) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// A connection left in LISTEN must not go back to the pool.
+		_ = conn.Conn().Close(context.Background())
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{OutboxChannel}.Sanitize()); err != nil {
+		return err
+	}
+	// Events may have committed while we were not listening.
+	r.signal()
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		r.signal()
+	}
+}
+
+func (r *OutboxRelay) deliverLoop() {
+	defer close(r.doneCh)
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		wait := time.Duration(0)
+		if err := r.drain(); err != nil {
+			utils.Logger.WithError(err).Warnf("Outbox %s: delivery stopped; retrying in %s", r.consumer, r.opts.RetryDelay)
+			wait = r.opts.RetryDelay
+		}
+		r.purge()
+
+		if wait > 0 {
+			select {
+			case <-r.stopCh:
+				return
+			case <-time.After(wait):
+			}
+			continue
+		}
+		select {
+		case <-r.stopCh:
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain delivers batches until the outbox is caught up.
+func (r *OutboxRelay) drain() error {
+	for {
+		select {
+		case <-r.stopCh:
+			return nil
+		default:
+		}
+		n, handlerErr, err := r.deliverBatch(context.Background())
+		if err != nil {
+			return err
+		}
+		if handlerErr != nil {
+			return handlerErr
+		}
+		if n < r.opts.BatchSize {
+			return nil
+		}
+	}
+}
+
+// deliverBatch hands the next batch of events to their handlers and returns
+// how many events it read. A handler error stops the batch and is returned
+// as handlerErr; the offset up to the failed event is still committed. err
+// is only set when the batch could not be read or the offset not saved.
+func (r *OutboxRelay) deliverBatch(ctx context.Context) (n int, handlerErr error, err error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	// Read the horizon before taking any lock, which gives this
+	// transaction an id of its own.
+	var horizon int64
+	if err = tx.QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`).Scan(&horizon); err != nil {
+		return 0, nil, err
+	}
+	if _, err = tx.Exec(ctx, `
+        INSERT INTO outbox_consumer_offsets (consumer) VALUES ($1)
+        ON CONFLICT (consumer) DO NOTHING
+    `, r.consumer); err != nil {
+		return 0, nil, err
+	}
+	var (
+		lastTx, lastID int64
+		failingID      *int64
+		attempts       int
+	)
+	if err = tx.QueryRow(ctx, `
+        SELECT last_tx_id, last_event_id, failing_event_id, failed_attempts
+        FROM outbox_consumer_offsets
+        WHERE consumer=$1 FOR UPDATE
+    `, r.consumer).Scan(&lastTx, &lastID, &failingID, &attempts); err != nil {
+		return 0, nil, err
+	}
+
+	events, err := r.loadEvents(ctx, tx, lastTx, lastID, horizon)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, ev := range events {
+		r.mu.RLock()
+		h := r.handlers[ev.Topic]
+		r.mu.RUnlock()
+		if h != nil {
+			if hErr := r.handle(ctx, h, ev); hErr != nil {
+				if failingID == nil || *failingID != ev.ID {
+					failingID, attempts = &ev.ID, 0
+				}
+				attempts++
+				if attempts < r.opts.MaxAttempts {
+					handlerErr = fmt.Errorf("%s event %d (attempt %d of %d): %w", ev.Topic, ev.ID, attempts, r.opts.MaxAttempts, hErr)
+					break
+				}
+				if err = r.deadLetter(ctx, tx, ev, hErr, attempts); err != nil {
+					return 0, nil, err
+				}
+			}
+		}
+		lastTx, lastID = ev.TxID, ev.ID
+		failingID, attempts = nil, 0
+	}
+
+	if _, err = tx.Exec(ctx, `
+        UPDATE outbox_consumer_offsets
+        SET last_tx_id=$2, last_event_id=$3, failing_event_id=$4, failed_attempts=$5, updated_at=NOW()
+        WHERE consumer=$1
+    `, r.consumer, lastTx, lastID, failingID, attempts); err != nil {
+		return 0, nil, err
+	}
+	return len(events), handlerErr, nil
+}
+
+// deadLetter records an event the consumer gives up on so delivery can move
+// past it.
+func (r *OutboxRelay) deadLetter(ctx context.Context, tx pgx.Tx, ev *models.OutboxEvent, cause error, attempts int) error {
+	utils.Logger.WithError(cause).Errorf("Outbox %s: giving up on %s event %d after %d attempts; moved to outbox_dead_letters", r.consumer, ev.Topic, ev.ID, attempts)
+	_, err := tx.Exec(ctx, `
+        INSERT INTO outbox_dead_letters (consumer, event_id, topic, aggregate_id, payload, error, attempts)
+        VALUES ($1,$2,$3,$4,$5,$6,$7)
+        ON CONFLICT (consumer, event_id) DO NOTHING
+    `, r.consumer, ev.ID, ev.Topic, ev.AggregateID, ev.Payload, cause.Error(), attempts)
+	return err
+}
+
+func (r *OutboxRelay) loadEvents(ctx context.Context, tx pgx.Tx, lastTx, lastID, horizon int64) ([]*models.OutboxEvent, error) {
+	rows, err := tx.Query(ctx, `
+        SELECT id, tx_id, topic, aggregate_id, payload, created_at
+        FROM outbox_events
+        WHERE (tx_id, id) > ($1, $2) AND tx_id < $3
+        ORDER BY tx_id, id
+        LIMIT $4
+    `, lastTx, lastID, horizon, r.opts.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.OutboxEvent
+	for rows.Next() {
+		ev := &models.OutboxEvent{}
+		if err := rows.Scan(&ev.ID, &ev.TxID, &ev.Topic, &ev.AggregateID, &ev.Payload, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+func (r *OutboxRelay) handle(ctx context.Context, h OutboxHandler, ev *models.OutboxEvent) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panic: %v", p)
+		}
+	}()
+	return h(ctx, ev)
+}
+
+func (r *OutboxRelay) purge() {
+	if time.Since(r.lastPurge) < outboxPurgeInterval {
+		return
+	}
+	r.lastPurge = time.Now()
+	n, err := r.outbox.PurgeDelivered(context.Background(), time.Now().Add(-r.opts.Retention))
+	if err != nil {
+		utils.Logger.WithError(err).Warnf("Outbox %s: purge failed", r.consumer)
+		return
+	}
+	if n > 0 {
+		utils.Logger.Infof("Outbox %s: purged %d delivered events", r.consumer, n)
+	}
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+)
+
+/*
+The transactional outbox: a state change and the domain event describing it
+are written in the same transaction, so an event exists if and only if the
+change was committed. OutboxRelay delivers the events to subscribers in other
+services.
+*/
+
+// OutboxChannel is the LISTEN/NOTIFY channel signalled when events commit.
+const OutboxChannel = "outbox_events"
+
+// Execer is satisfied by DB and by pgx.Tx.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// PublishEvent writes an event to the outbox. Pass the pgx.Tx that makes the
+// state change; the notification is only sent if that transaction commits.
+func PublishEvent(ctx context.Context, q Execer, topic string, aggregateID uuid.UUID, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", topic, err)
+	}
+	_, err = q.Exec(ctx, `
+        WITH ev AS (
+            INSERT INTO outbox_events (topic, aggregate_id, payload)
+            VALUES ($1,$2,$3)
+            RETURNING id
+        )
+        SELECT pg_notify($4, $1) FROM ev
+    `, topic, aggregateID, b, OutboxChannel)
+	if err != nil {
+		return fmt.Errorf("publish %s event: %w", topic, err)
+	}
+	return nil
+}
+
+type OutboxRepository interface {
+	// Publish writes an event outside of any other state change.
+	Publish(ctx context.Context, topic string, aggregateID uuid.UUID, payload any) error
+	// PurgeDelivered deletes events older than before that every consumer has
+	// moved past.
+	PurgeDelivered(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outboxRepo struct {
+	db DB
+}
+
+func NewOutboxRepository(db DB) OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+func (r *outboxRepo) Publish(ctx context.Context, topic string, aggregateID uuid.UUID, payload any) error {
+	return PublishEvent(ctx, r.db, topic, aggregateID, payload)
+}
+
+func (r *outboxRepo) PurgeDelivered(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+        DELETE FROM outbox_events e
+        WHERE e.created_at < $1
+          AND NOT EXISTS (
+              SELECT 1 FROM outbox_consumer_offsets o
+              WHERE (o.last_tx_id, o.last_event_id) < (e.tx_id, e.id)
+          )
+    `, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -43,8 +43,10 @@ func (r *propertyRepo) Create(ctx context.Context, p *models.Property) error {
         INSERT INTO properties (
             id, manager_id, property_name, address, city, state, zip_code, time_zone,
             latitude, longitude, is_demo,
+            allocation_mode, lottery_window_seconds, market,
             created_at
-        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10, $11, NOW())
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10, $11,
+            $12, $13, $14, NOW())
     `,
 		p.ID,
 		p.ManagerID,
@@ -57,6 +59,9 @@ func (r *propertyRepo) Create(ctx context.Context, p *models.Property) error {
 		p.Latitude,
 		p.Longitude,
 		p.IsDemo,
+		p.AllocationMode,
+		lotteryWindowOrDefault(p.LotteryWindowSeconds),
+		p.Market,
 	)
 	return err
 }
@@ -127,8 +132,10 @@ func (r *propertyRepo) Update(ctx context.Context, p *models.Property) error {
 	_, err := r.db.Exec(ctx, `
         UPDATE properties SET
             property_name=$1, address=$2, city=$3, state=$4, zip_code=$5,
-            time_zone=$6, latitude=$7, longitude=$8, is_demo=$9
-        WHERE id=$10
+            time_zone=$6, latitude=$7, longitude=$8, is_demo=$9,
+            allocation_mode=$10,
+            lottery_window_seconds=$11, market=$12
+        WHERE id=$13
     `,
 		p.PropertyName,
 		p.Address,
@@ -139,6 +146,9 @@ func (r *propertyRepo) Update(ctx context.Context, p *models.Property) error {
 		p.Latitude,
 		p.Longitude,
 		p.IsDemo,
+		p.AllocationMode,
+		lotteryWindowOrDefault(p.LotteryWindowSeconds),
+		p.Market,
 		p.ID,
 	)
 	return err
@@ -167,20 +177,21 @@ func (r *propertyRepo) ListAllProperties(ctx context.Context) ([]*models.Propert
 	return out, rows.Err()
 }
 
+var propertyColumns = []string{
+	"id", "manager_id", "property_name",
+	"address", "city", "state", "zip_code", "time_zone",
+	"latitude", "longitude", "is_demo",
+	"allocation_mode", "lottery_window_seconds", "market",
+	"created_at",
+}
+
 func baseSelectProperty() string {
-	return `
-        SELECT
-            id, manager_id, property_name,
-            address, city, state, zip_code, time_zone,
-            latitude, longitude, is_demo,
-            created_at
-        FROM properties
-    `
+	return "SELECT " + qualifyColumns("", propertyColumns) + " FROM properties "
 }
 
-func scanProperty(row pgx.Row) (*models.Property, error) {
-	var p models.Property
-	err := row.Scan(
+// propertyScanDest returns scan targets matching propertyColumns.
+func propertyScanDest(p *models.Property) []any {
+	return []any{
 		&p.ID,
 		&p.ManagerID,
 		&p.PropertyName,
@@ -192,8 +203,16 @@ func scanProperty(row pgx.Row) (*models.Property, error) {
 		&p.Latitude,
 		&p.Longitude,
 		&p.IsDemo,
+		&p.AllocationMode,
+		&p.LotteryWindowSeconds,
+		&p.Market,
 		&p.CreatedAt,
-	)
+	}
+}
+
+func scanProperty(row pgx.Row) (*models.Property, error) {
+	var p models.Property
+	err := row.Scan(propertyScanDest(&p)...)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -202,3 +221,14 @@ func scanProperty(row pgx.Row) (*models.Property, error) {
 	}
 	return &p, nil
 }
+
+// DefaultLotteryWindowSeconds is used when a property enables lottery
+// allocation without choosing a window.
+const DefaultLotteryWindowSeconds = 120
+
+func lotteryWindowOrDefault(secs int) int {
+	if secs <= 0 {
+		return DefaultLotteryWindowSeconds
+	}
+	return secs
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/* ------------------------------------------------------------------
+   Public interface
+------------------------------------------------------------------ */
+
+type SurgePolicyRepository interface {
+	Create(ctx context.Context, p *models.SurgePolicy) error
+	Update(ctx context.Context, p *models.SurgePolicy) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SurgePolicy, error)
+	ListActive(ctx context.Context) ([]*models.SurgePolicy, error)
+	ListAll(ctx context.Context) ([]*models.SurgePolicy, error)
+
+	CreateAuditEvent(ctx context.Context, e *models.SurgeAuditEvent) error
+	ListAuditEvents(ctx context.Context, policyID, instanceID *uuid.UUID, limit int) ([]*models.SurgeAuditEvent, error)
+}
+
+/* ------------------------------------------------------------------
+   Implementation
+------------------------------------------------------------------ */
+
+type surgePolicyRepo struct {
+	db DB
+}
+
+func NewSurgePolicyRepository(db DB) SurgePolicyRepository {
+	return &surgePolicyRepo{db: db}
+}
+
+const surgePolicyColumns = `
+    id, name, scope, scope_key, stages, max_multiplier, max_pay_cents,
+    overrides, active, created_by, created_at, updated_at`
+
+func scanSurgePolicy(row pgx.Row) (*models.SurgePolicy, error) {
+	var p models.SurgePolicy
+	var stages, overrides []byte
+	err := row.Scan(
+		&p.ID, &p.Name, &p.Scope, &p.ScopeKey, &stages, &p.MaxMultiplier, &p.MaxPay,
+		&overrides, &p.Active, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	_ = json.Unmarshal(stages, &p.Stages)
+	_ = json.Unmarshal(overrides, &p.Overrides)
+	return &p, nil
+}
+
+func (r *surgePolicyRepo) Create(ctx context.Context, p *models.SurgePolicy) error {
+	stages, _ := json.Marshal(p.Stages)
+	overrides, _ := json.Marshal(p.Overrides)
+	return r.db.QueryRow(ctx, `
+        INSERT INTO surge_policies (
+            id, name, scope, scope_key, stages, max_multiplier, max_pay_cents,
+            overrides, active, created_by, created_at, updated_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10, NOW(), NOW())
+        RETURNING created_at, updated_at
+    `,
+		p.ID, p.Name, p.Scope, p.ScopeKey, stages, p.MaxMultiplier, p.MaxPay,
+		overrides, p.Active, p.CreatedBy,
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+}
+
+func (r *surgePolicyRepo) Update(ctx context.Context, p *models.SurgePolicy) error {
+	stages, _ := json.Marshal(p.Stages)
+	overrides, _ := json.Marshal(p.Overrides)
+	return r.db.QueryRow(ctx, `
+        UPDATE surge_policies SET
+            name=$1, scope=$2, scope_key=$3, stages=$4, max_multiplier=$5,
+            max_pay_cents=$6, overrides=$7, active=$8, updated_at=NOW()
+        WHERE id=$9
+        RETURNING updated_at
+    `,
+		p.Name, p.Scope, p.ScopeKey, stages, p.MaxMultiplier,
+		p.MaxPay, overrides, p.Active, p.ID,
+	).Scan(&p.UpdatedAt)
+}
+
+func (r *surgePolicyRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.SurgePolicy, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+surgePolicyColumns+` FROM surge_policies WHERE id=$1`, id)
+	return scanSurgePolicy(row)
+}
+
+func (r *surgePolicyRepo) ListActive(ctx context.Context) ([]*models.SurgePolicy, error) {
+	return r.list(ctx, `SELECT `+surgePolicyColumns+` FROM surge_policies WHERE active ORDER BY created_at`)
+}
+
+func (r *surgePolicyRepo) ListAll(ctx context.Context) ([]*models.SurgePolicy, error) {
+	return r.list(ctx, `SELECT `+surgePolicyColumns+` FROM surge_policies ORDER BY created_at`)
+}
+
+func (r *surgePolicyRepo) list(ctx context.Context, query string) ([]*models.SurgePolicy, error) {
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.SurgePolicy
+	for rows.Next() {
+		p, err := scanSurgePolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+/* ---------- audit ---------- */
+
+func (r *surgePolicyRepo) CreateAuditEvent(ctx context.Context, e *models.SurgeAuditEvent) error {
+	details, _ := json.Marshal(e.Details)
+	return r.db.QueryRow(ctx, `
+        INSERT INTO surge_audit_events (
+            id, action, actor_id, policy_id, instance_id,
+            old_pay_cents, new_pay_cents, reason, details, created_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9, NOW())
+        RETURNING created_at
+    `,
+		e.ID, e.Action, e.ActorID, e.PolicyID, e.InstanceID,
+		e.OldPay, e.NewPay, e.Reason, details,
+	).Scan(&e.CreatedAt)
+}
+
+func (r *surgePolicyRepo) ListAuditEvents(
+	ctx context.Context,
+	policyID, instanceID *uuid.UUID,
+	limit int,
+) ([]*models.SurgeAuditEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.Query(ctx, `
+        SELECT id, action, actor_id, policy_id, instance_id,
+               old_pay_cents, new_pay_cents, reason, details, created_at
+        FROM surge_audit_events
+        WHERE ($1::uuid IS NULL OR policy_id = $1)
+          AND ($2::uuid IS NULL OR instance_id = $2)
+        ORDER BY created_at DESC
+        LIMIT $3
+    `, policyID, instanceID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.SurgeAuditEvent
+	for rows.Next() {
+		var e models.SurgeAuditEvent
+		var details []byte
+		if err := rows.Scan(
+			&e.ID, &e.Action, &e.ActorID, &e.PolicyID, &e.InstanceID,
+			&e.OldPay, &e.NewPay, &e.Reason, &details, &e.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(details, &e.Details)
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+/*
+UnitOfWork runs multi-step operations in one transaction. Repositories taken
+from the Work are bound to its pgx.Tx, so either every step commits or none
+does. A repository method that opens its own transaction gets a savepoint
+inside the unit instead.
+
+Run stores the Work in the context it hands to fn. A Run made with that
+context (or one derived from it) joins the outer unit as a savepoint rather
+than starting a second transaction, so service methods can use Run whether
+or not their caller already has one.
+
+The outermost Run retries the whole function when Postgres reports a
+serialization failure or deadlock. fn may therefore run more than once and
+must not call out to anything but the database; send emails, queue jobs and
+call Stripe after Run returns.
+
+Inside fn, use only the Work's repositories for rows the unit writes:
+a pool-bound repository runs on another connection and waits on the unit's
+row locks.
+*/
+
+const (
+	DefaultUnitOfWorkMaxAttempts = 3
+	unitOfWorkRetryBaseDelay     = 20 * time.Millisecond
+)
+
+type UnitOfWorkOptions struct {
+	IsoLevel    pgx.TxIsoLevel // defaults to the server's (read committed)
+	MaxAttempts int
+}
+
+type UnitOfWork struct {
+	db     DB
+	encKey []byte
+	opts   UnitOfWorkOptions
+}
+
+// NewUnitOfWork starts units on db. encKey is the worker repository's
+// encryption key.
+func NewUnitOfWork(db DB, encKey []byte, opts UnitOfWorkOptions) *UnitOfWork {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultUnitOfWorkMaxAttempts
+	}
+	return &UnitOfWork{db: db, encKey: encKey, opts: opts}
+}
+
+// Work is one transaction (or savepoint) and the repositories bound to it.
+// It satisfies DB, so service-specific repositories can be built on it too.
+type Work struct {
+	pgx.Tx
+	encKey []byte
+}
+
+type workCtxKey struct{}
+
+// WorkFromContext returns the unit of work ctx runs in, or nil.
+func WorkFromContext(ctx context.Context) *Work {
+	w, _ := ctx.Value(workCtxKey{}).(*Work)
+	return w
+}
+
+// Run calls fn in a transaction, committing if it returns nil and rolling
+// back otherwise.
+func (u *UnitOfWork) Run(ctx context.Context, fn func(ctx context.Context, w *Work) error) error {
+	if outer := WorkFromContext(ctx); outer != nil {
+		return outer.Savepoint(ctx, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := u.runOnce(ctx, fn)
+		if err == nil || !IsRetryableTxError(err) || attempt >= u.opts.MaxAttempts {
+			return err
+		}
+		delay := unitOfWorkRetryBaseDelay*time.Duration(attempt) + rand.N(unitOfWorkRetryBaseDelay)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (u *UnitOfWork) runOnce(ctx context.Context, fn func(ctx context.Context, w *Work) error) (err error) {
+	var tx pgx.Tx
+	if b, ok := u.db.(interface {
+		BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error)
+	}); ok && u.opts.IsoLevel != "" {
+		tx, err = b.BeginTx(ctx, pgx.TxOptions{IsoLevel: u.opts.IsoLevel})
+	} else {
+		tx, err = u.db.Begin(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	return finish(ctx, &Work{Tx: tx, encKey: u.encKey}, fn)
+}
+
+// Savepoint runs fn in a nested transaction. If fn fails only its own
+// writes are undone, and the unit can carry on.
+func (w *Work) Savepoint(ctx context.Context, fn func(ctx context.Context, w *Work) error) error {
+	sp, err := w.Tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	return finish(ctx, &Work{Tx: sp, encKey: w.encKey}, fn)
+}
+
+func finish(ctx context.Context, w *Work, fn func(ctx context.Context, w *Work) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = w.Tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+	if err = fn(context.WithValue(ctx, workCtxKey{}, w), w); err != nil {
+		if rbErr := w.Tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return fmt.Errorf("%w (rollback: %v)", err, rbErr)
+		}
+		return err
+	}
+	return w.Tx.Commit(ctx)
+}
+
+// IsRetryableTxError reports whether err is a serialization failure or a
+// deadlock, after which the transaction can be run again.
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+func (w *Work) JobDefinitions() JobDefinitionRepository {
+	return NewJobDefinitionRepository(w)
+}
+
+func (w *Work) JobInstances() JobInstanceRepository {
+	return NewJobInstanceRepository(w)
+}
+
+func (w *Work) JobUnitVerifications() JobUnitVerificationRepository {
+	return NewJobUnitVerificationRepository(w)
+}
+
+func (w *Work) Workers() WorkerRepository {
+	return NewWorkerRepository(w, w.encKey)
+}
+
+func (w *Work) WorkerScoreEvents() WorkerScoreEventRepository {
+	return NewWorkerScoreEventRepository(w)
+}
+
+func (w *Work) Outbox() OutboxRepository {
+	return NewOutboxRepository(w)
+}
+
+func (w *Work) JobPayItems() JobPayItemRepository {
+	return NewJobPayItemRepository(w)
+}
+
+func (w *Work) EscalationPolicies() EscalationPolicyRepository {
+	return NewEscalationPolicyRepository(w)
+}
+
+func (w *Work) SurgePolicies() SurgePolicyRepository {
+	return NewSurgePolicyRepository(w)
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+)
+
+/* ------------------------------------------------------------------
+   Public interface
+------------------------------------------------------------------ */
+
+// VerificationPhotoRepository holds unit photos waiting on a queued
+// verification, so the queue row carries a key instead of the image.
+type VerificationPhotoRepository interface {
+	// Put stores the photo under key, replacing an earlier one.
+	Put(ctx context.Context, key string, photo []byte) error
+	// Get returns nil if there is no photo under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+/* ------------------------------------------------------------------
+   Implementation
+------------------------------------------------------------------ */
+
+type verificationPhotoRepo struct {
+	db DB
+}
+
+func NewVerificationPhotoRepository(db DB) VerificationPhotoRepository {
+	return &verificationPhotoRepo{db: db}
+}
+
+func (r *verificationPhotoRepo) Put(ctx context.Context, key string, photo []byte) error {
+	_, err := r.db.Exec(ctx, `
+        INSERT INTO verification_photos (key, photo) VALUES ($1,$2)
+        ON CONFLICT (key) DO UPDATE SET photo=EXCLUDED.photo, created_at=NOW()
+    `, key, photo)
+	return err
+}
+
+func (r *verificationPhotoRepo) Get(ctx context.Context, key string) ([]byte, error) {
+	var photo []byte
+	err := r.db.QueryRow(ctx, `SELECT photo FROM verification_photos WHERE key=$1`, key).Scan(&photo)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	return photo, err
+}
+
+func (r *verificationPhotoRepo) Delete(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM verification_photos WHERE key=$1`, key)
+	return err
+}
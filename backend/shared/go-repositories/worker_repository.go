@@ -4,6 +4,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -29,6 +30,8 @@ type WorkerRepository interface {
 	GetByCheckrCandidateID(ctx context.Context, cand string) (*models.Worker, error)
 
 	AdjustWorkerScoreAtomic(ctx context.Context, workerID uuid.UUID, delta int, eventType string) error
+	AdjustWorkerScore(ctx context.Context, adj models.ScoreAdjustment) (*models.WorkerScoreLedgerEntry, error)
+	ReverseScoreEvent(ctx context.Context, eventID uuid.UUID, actorID *uuid.UUID, note string) (*models.WorkerScoreLedgerEntry, error)
 	GetActiveWorkerCount(ctx context.Context) (int, error)
 	ListOldestWaitlistedWorkers(ctx context.Context, limit int, reason models.WaitlistReasonType) ([]*models.Worker, error)
 }
@@ -120,42 +123,73 @@ func (r *workerRepo) GetByCheckrCandidateID(ctx context.Context, cand string) (*
 	return r.scanWorker(row)
 }
 
-// AdjustWorkerScoreAtomic ...
+// AdjustWorkerScoreAtomic applies delta with no job, actor or note attached.
 func (r *workerRepo) AdjustWorkerScoreAtomic(ctx context.Context, workerID uuid.UUID, delta int, eventType string) error {
+	_, err := r.AdjustWorkerScore(ctx, models.ScoreAdjustment{WorkerID: workerID, Delta: delta, EventType: eventType})
+	return err
+}
+
+// AdjustWorkerScore changes the worker's reliability score, records the
+// change in worker_score_events and publishes it, all in one transaction.
+// A penalty that leaves the score at a threshold suspends or bans the
+// worker; with adj.Model set the model's bounds and new-worker floor apply.
+// A positive change that lifts the score back over the suspension threshold
+// ends a suspension; a ban is only lifted by a change an ops user
+// (adj.ActorID) makes.
+func (r *workerRepo) AdjustWorkerScore(ctx context.Context, adj models.ScoreAdjustment) (entry *models.WorkerScoreLedgerEntry, err error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
 			_ = tx.Rollback(ctx)
 		} else {
-			_ = tx.Commit(ctx)
+			err = tx.Commit(ctx)
 		}
 	}()
 
-	row := tx.QueryRow(ctx, baseSelectWorker()+" WHERE id=$1 FOR UPDATE", workerID)
+	row := tx.QueryRow(ctx, baseSelectWorker()+" WHERE id=$1 FOR UPDATE", adj.WorkerID)
 	w, err := r.scanWorker(row)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if w == nil {
-		return fmt.Errorf("worker not found for ID=%s", workerID)
+		return nil, fmt.Errorf("worker not found for ID=%s", adj.WorkerID)
 	}
 
+	now := time.Now().UTC()
 	oldScore := w.ReliabilityScore
-	newScore := max(min(oldScore+delta, utils.WorkerScoreMax), utils.WorkerScoreMin)
+	lo, hi := utils.WorkerScoreMin, utils.WorkerScoreMax
+	if adj.Model != nil {
+		lo, hi = adj.Model.MinScore, adj.Model.MaxScore
+	}
+	newScore := max(min(oldScore+adj.Delta, hi), lo)
+	if adj.Model != nil && adj.Delta < 0 {
+		// A penalty can't push a new worker below the floor, but doesn't
+		// raise a score that is already under it.
+		newScore = max(newScore, min(adj.Model.Floor(w.CreatedAt, now), oldScore))
+	}
 
 	isBanned := w.IsBanned
 	suspendedUntil := w.SuspendedUntil
-	now := time.Now().UTC()
 
-	if newScore <= utils.WorkerBanThresholdScore {
-		isBanned = true
-	} else if newScore <= utils.WorkerSuspendThresholdScore {
-		if suspendedUntil == nil || suspendedUntil.Before(now) {
-			st := now.AddDate(0, 0, utils.WorkerSuspensionDays)
-			suspendedUntil = &st
+	if adj.Delta < 0 {
+		if newScore <= utils.WorkerBanThresholdScore {
+			isBanned = true
+		} else if newScore <= utils.WorkerSuspendThresholdScore {
+			if suspendedUntil == nil || suspendedUntil.Before(now) {
+				st := now.AddDate(0, 0, utils.WorkerSuspensionDays)
+				suspendedUntil = &st
+			}
+		}
+	}
+	if adj.Delta > 0 {
+		if newScore > utils.WorkerBanThresholdScore && adj.ActorID != nil {
+			isBanned = false
+		}
+		if newScore > utils.WorkerSuspendThresholdScore {
+			suspendedUntil = nil
 		}
 	}
 
@@ -169,21 +203,107 @@ func (r *workerRepo) AdjustWorkerScoreAtomic(ctx context.Context, workerID uuid.
         WHERE id=$4
     `, newScore, isBanned, suspendedUntil, w.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	_, err = tx.Exec(ctx, `
-        INSERT INTO worker_score_events (id, worker_id, event_type, delta, old_score, new_score, created_at)
-        VALUES ($1,$2,$3,$4,$5,$6,NOW())
+	entry = &models.WorkerScoreLedgerEntry{
+		ID:              uuid.New(),
+		WorkerID:        w.ID,
+		EventType:       adj.EventType,
+		Delta:           adj.Delta,
+		OldScore:        oldScore,
+		NewScore:        newScore,
+		JobInstanceID:   adj.JobInstanceID,
+		ActorID:         adj.ActorID,
+		Note:            adj.Note,
+		ReversesEventID: adj.ReversesEventID,
+	}
+	err = tx.QueryRow(ctx, `
+        INSERT INTO worker_score_events (
+            id, worker_id, event_type, delta, old_score, new_score,
+            job_instance_id, actor_id, note, reverses_event_id, created_at
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NOW())
+        RETURNING created_at
     `,
-		uuid.New(),
-		w.ID,
-		eventType,
-		delta,
-		oldScore,
-		newScore,
-	)
-	return err
+		entry.ID,
+		entry.WorkerID,
+		entry.EventType,
+		entry.Delta,
+		entry.OldScore,
+		entry.NewScore,
+		entry.JobInstanceID,
+		entry.ActorID,
+		entry.Note,
+		entry.ReversesEventID,
+	).Scan(&entry.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			err = utils.ErrScoreEventAlreadyReversed
+		}
+		return nil, err
+	}
+
+	ev := models.WorkerScoreEvent{
+		ScoreEventID:   entry.ID,
+		WorkerID:       w.ID,
+		EventType:      adj.EventType,
+		Delta:          adj.Delta,
+		OldScore:       oldScore,
+		NewScore:       newScore,
+		JobInstanceID:  adj.JobInstanceID,
+		IsBanned:       isBanned,
+		SuspendedUntil: suspendedUntil,
+	}
+	if err = PublishEvent(ctx, tx, models.EventWorkerScoreChanged, w.ID, ev); err != nil {
+		return nil, err
+	}
+	if isBanned && !w.IsBanned {
+		if err = PublishEvent(ctx, tx, models.EventWorkerBanned, w.ID, ev); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// ReverseScoreEvent undoes the change a score event made, recording a
+// REVERSAL that points at it. Only the applied change is given back: a
+// penalty clamped at the minimum score is reversed by what it took.
+func (r *workerRepo) ReverseScoreEvent(ctx context.Context, eventID uuid.UUID, actorID *uuid.UUID, note string) (entry *models.WorkerScoreLedgerEntry, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			err = tx.Commit(ctx)
+		}
+	}()
+
+	orig, err := NewWorkerScoreEventRepository(tx).GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case orig == nil:
+		return nil, utils.ErrScoreEventNotFound
+	case orig.EventType == models.ScoreEventReversal:
+		return nil, utils.ErrScoreEventNotReversible
+	case orig.ReversedByID != nil:
+		return nil, utils.ErrScoreEventAlreadyReversed
+	}
+
+	return NewWorkerRepository(tx, r.encKey).AdjustWorkerScore(ctx, models.ScoreAdjustment{
+		WorkerID:        orig.WorkerID,
+		Delta:           -orig.AppliedDelta(),
+		EventType:       models.ScoreEventReversal,
+		JobInstanceID:   orig.JobInstanceID,
+		ActorID:         actorID,
+		Note:            note,
+		ReversesEventID: &orig.ID,
+	})
 }
 
 func (r *workerRepo) GetActiveWorkerCount(ctx context.Context) (int, error) {
@@ -233,6 +353,7 @@ func (r *workerRepo) update(ctx context.Context, w *models.Worker, check bool, e
             checkr_report_outcome=$21,checkr_report_eta=$22,
             reliability_score=$23,is_banned=$24,suspended_until=$25,tenant_token=$26,
             on_waitlist=$27,waitlisted_at=$28,waitlist_reason=$29,
+            home_latitude=$30,home_longitude=$31,
             updated_at=NOW()
     `
 	args := []any{
@@ -246,13 +367,14 @@ func (r *workerRepo) update(ctx context.Context, w *models.Worker, check bool, e
 		w.CheckrReportOutcome, w.CheckrReportETA,
 		w.ReliabilityScore, w.IsBanned, w.SuspendedUntil, w.TenantToken,
 		w.OnWaitlist, w.WaitlistedAt, w.WaitlistReason,
+		w.HomeLatitude, w.HomeLongitude,
 	}
 
 	if check {
-		sql += `, row_version=row_version+1 WHERE id=$30 AND row_version=$31`
+		sql += `, row_version=row_version+1 WHERE id=$32 AND row_version=$33`
 		args = append(args, w.ID, expected)
 	} else {
-		sql += ` WHERE id=$30`
+		sql += ` WHERE id=$32`
 		args = append(args, w.ID)
 	}
 	return r.db.Exec(ctx, sql, args...)
@@ -263,6 +385,7 @@ func baseSelectWorker() string {
     SELECT
         id,email,phone_number,totp_secret,
         first_name,last_name,street_address,apt_suite,city,state,zip_code,
+        home_latitude,home_longitude,
         vehicle_year,vehicle_make,vehicle_model,
         account_status,setup_progress,
         stripe_connect_account_id,current_stripe_idv_session_id,
@@ -286,6 +409,7 @@ func (r *workerRepo) scanWorker(row pgx.Row) (*models.Worker, error) {
 	err := row.Scan(
 		&w.ID, &w.Email, &w.PhoneNumber, &enc,
 		&w.FirstName, &w.LastName, &w.StreetAddress, &w.AptSuite, &w.City, &w.State, &w.ZipCode,
+		&w.HomeLatitude, &w.HomeLongitude,
 		&w.VehicleYear, &w.VehicleMake, &w.VehicleModel,
 		&acc, &prog,
 		&w.StripeConnectAccountID, &w.CurrentStripeIdvSessionID,
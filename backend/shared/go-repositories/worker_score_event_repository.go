@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/poofware/mono-repo/backend/shared/go-models"
+)
+
+/*
+WorkerScoreEventRepository reads the reliability score ledger. Entries are
+written by WorkerRepository.AdjustWorkerScore together with the score
+itself, so there is no write method here.
+*/
+type WorkerScoreEventRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.WorkerScoreLedgerEntry, error)
+	// ListByWorker returns the worker's events newest first. Pass the
+	// cursor of the last entry seen as after to page back.
+	ListByWorker(ctx context.Context, workerID uuid.UUID, after *ScoreEventCursor, limit int) ([]*models.WorkerScoreLedgerEntry, error)
+	// ListForReplay returns each worker's whole ledger, oldest first.
+	ListForReplay(ctx context.Context, workerIDs []uuid.UUID) (map[uuid.UUID][]*models.WorkerScoreLedgerEntry, error)
+	// ListWorkerIDs returns workers with any ledger entry, or with a
+	// penalty since penaltiesSince when it is set. limit <= 0 returns all.
+	ListWorkerIDs(ctx context.Context, penaltiesSince *time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// ScoreEventCursor is the keyset position of the last ledger entry a caller
+// consumed. Entries written in the same transaction share created_at, so the
+// ID breaks the tie.
+type ScoreEventCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+type workerScoreEventRepo struct {
+	db DB
+}
+
+func NewWorkerScoreEventRepository(db DB) WorkerScoreEventRepository {
+	return &workerScoreEventRepo{db: db}
+}
+
+const workerScoreEventSelect = `
+    SELECT e.id, e.worker_id, e.event_type, e.delta, e.old_score, e.new_score,
+           e.job_instance_id, e.actor_id, e.note, e.reverses_event_id,
+           rev.id, e.created_at
+    FROM worker_score_events e
+    LEFT JOIN worker_score_events rev ON rev.reverses_event_id = e.id`
+
+func scanWorkerScoreEvent(row pgx.Row) (*models.WorkerScoreLedgerEntry, error) {
+	var e models.WorkerScoreLedgerEntry
+	err := row.Scan(
+		&e.ID, &e.WorkerID, &e.EventType, &e.Delta, &e.OldScore, &e.NewScore,
+		&e.JobInstanceID, &e.ActorID, &e.Note, &e.ReversesEventID,
+		&e.ReversedByID, &e.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *workerScoreEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.WorkerScoreLedgerEntry, error) {
+	return scanWorkerScoreEvent(r.db.QueryRow(ctx, workerScoreEventSelect+` WHERE e.id=$1`, id))
+}
+
+func (r *workerScoreEventRepo) ListByWorker(
+	ctx context.Context,
+	workerID uuid.UUID,
+	after *ScoreEventCursor,
+	limit int,
+) ([]*models.WorkerScoreLedgerEntry, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var afterAt *time.Time
+	var afterID *uuid.UUID
+	if after != nil {
+		afterAt, afterID = &after.CreatedAt, &after.ID
+	}
+	rows, err := r.db.Query(ctx, workerScoreEventSelect+`
+        WHERE e.worker_id = $1
+          AND ($2::timestamptz IS NULL OR (e.created_at, e.id) < ($2, $3::uuid))
+        ORDER BY e.created_at DESC, e.id DESC
+        LIMIT $4
+    `, workerID, afterAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.WorkerScoreLedgerEntry
+	for rows.Next() {
+		e, err := scanWorkerScoreEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *workerScoreEventRepo) ListForReplay(
+	ctx context.Context,
+	workerIDs []uuid.UUID,
+) (map[uuid.UUID][]*models.WorkerScoreLedgerEntry, error) {
+	out := make(map[uuid.UUID][]*models.WorkerScoreLedgerEntry, len(workerIDs))
+	if len(workerIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Query(ctx, workerScoreEventSelect+`
+        WHERE e.worker_id = ANY($1)
+        ORDER BY e.worker_id, e.created_at, e.id
+    `, workerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanWorkerScoreEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[e.WorkerID] = append(out[e.WorkerID], e)
+	}
+	return out, rows.Err()
+}
+
+func (r *workerScoreEventRepo) ListWorkerIDs(ctx context.Context, penaltiesSince *time.Time, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+        SELECT DISTINCT worker_id
+        FROM worker_score_events
+        WHERE $1::timestamptz IS NULL OR (delta < 0 AND created_at >= $1)
+        ORDER BY worker_id
+        LIMIT NULLIF($2, 0)
+    `, penaltiesSince, max(limit, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -195,8 +195,8 @@ func (h *TestHelper) CreateTestJobDefinition(t *testing.T, ctx context.Context,
 		for i := range 7 {
 			dailyEstimates[i] = models.DailyPayEstimate{
 				DayOfWeek:                   time.Weekday(i),
-				BasePay:                     50.0,
-				InitialBasePay:              50.0,
+				BasePay:                     models.USD(5000),
+				InitialBasePay:              models.USD(5000),
 				EstimatedTimeMinutes:        60,
 				InitialEstimatedTimeMinutes: 60,
 			}
@@ -247,11 +247,12 @@ func (h *TestHelper) CreateTestJobDefinition(t *testing.T, ctx context.Context,
 	return createdDef
 }
 
-// CreateTestJobInstance creates and persists a job instance with the correct effective pay.
+// CreateTestJobInstance creates and persists a job instance with the correct
+// effective pay; pay, if given, is in dollars.
 func (h *TestHelper) CreateTestJobInstance(t *testing.T, ctx context.Context, defID uuid.UUID, serviceDate time.Time, status models.InstanceStatusType, assignedWorkerID *uuid.UUID, pay ...float64) *models.JobInstance {
-	var effectivePay float64
+	var effectivePay models.Money
 	if len(pay) > 0 {
-		effectivePay = pay[0]
+		effectivePay = models.MoneyFromDollars(pay[0])
 	} else {
 		def, err := h.JobDefRepo.GetByID(ctx, defID)
 		require.NoError(t, err, "Failed to get job definition in CreateTestJobInstance helper")
@@ -261,7 +262,7 @@ func (h *TestHelper) CreateTestJobInstance(t *testing.T, ctx context.Context, de
 		if dailyEstimate != nil {
 			effectivePay = dailyEstimate.BasePay
 		} else {
-			effectivePay = 50.0 // Fallback test pay
+			effectivePay = models.USD(5000) // Fallback test pay
 		}
 	}
 
@@ -57,6 +57,7 @@ type TestHelper struct {
 	DumpsterRepo    repositories.DumpsterRepository
 	JobDefRepo      repositories.JobDefinitionRepository
 	JobInstRepo     repositories.JobInstanceRepository
+	PayItemRepo     repositories.JobPayItemRepository
 	AgentRepo       repositories.AgentRepository
 	PMEmailRepo     repositories.PMEmailVerificationRepository
 	PMSMSRepo       repositories.PMSMSVerificationRepository
@@ -183,6 +184,7 @@ func NewTestHelper(t *testing.T, appName, uniqueRunID, uniqueRunNum string) *Tes
 		DumpsterRepo:        repositories.NewDumpsterRepository(dbPool),
 		JobDefRepo:          repositories.NewJobDefinitionRepository(dbPool),
 		JobInstRepo:         repositories.NewJobInstanceRepository(dbPool),
+		PayItemRepo:         repositories.NewJobPayItemRepository(dbPool),
 		AgentRepo:           repositories.NewAgentRepository(dbPool),
 		PMEmailRepo:         repositories.NewPMEmailVerificationRepository(dbPool),
 		PMSMSRepo:           repositories.NewPMSMSVerificationRepository(dbPool),
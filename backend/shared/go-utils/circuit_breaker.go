@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+/*────────────────────────────────────────────────────────────────────────────
+  CircuitBreaker stops calling a failing dependency for a cooldown period.
+
+  Closed:    calls flow; consecutive failures are counted.
+  Open:      calls are rejected until Cooldown has elapsed.
+  Half-open: one probe call is let through; success closes the breaker,
+             failure re-opens it for another cooldown.
+────────────────────────────────────────────────────────────────────────────*/
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probeSent bool
+}
+
+// NewCircuitBreaker opens after failureThreshold consecutive failures and
+// probes again once cooldown has passed.
+func NewCircuitBreaker(name string, failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a call may proceed. In half-open state only a single
+// probe is admitted until its outcome is recorded.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probeSent = true
+		return true
+	case CircuitHalfOpen:
+		if b.probeSent {
+			return false
+		}
+		b.probeSent = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitClosed {
+		Logger.Infof("[CircuitBreaker] %s closed", b.name)
+	}
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probeSent = false
+}
+
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold {
+		if b.state != CircuitOpen {
+			Logger.Warnf("[CircuitBreaker] %s opened after %d failure(s)", b.name, b.failures)
+		}
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		b.probeSent = false
+	}
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
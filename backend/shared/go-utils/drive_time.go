@@ -0,0 +1,280 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*────────────────────────────────────────────────────────────────────────────
+  Drive-time lookups.
+
+  A DriveTimeProvider answers origin × destination matrices. Callers go
+  through DriveTimeCache, which keys results by geohash-rounded origin and
+  destination plus a time bucket, batches every miss for one origin into a
+  single matrix request, and trips a circuit breaker when the provider keeps
+  failing. Anything the provider cannot answer falls back to the crow-flies
+  estimate (distance × CrowFliesDriveTimeMultiplier).
+────────────────────────────────────────────────────────────────────────────*/
+
+const (
+	// DriveTimeOriginPrecision rounds worker positions to ~1.2 km × 0.6 km cells.
+	DriveTimeOriginPrecision = 6
+	// DriveTimeDestPrecision keeps destinations distinct down to ~40 m.
+	DriveTimeDestPrecision = 8
+
+	DefaultDriveTimeBucket          = 15 * time.Minute
+	DefaultDriveTimeMaxEntries      = 50_000
+	DefaultDriveTimeBreakerFailures = 3
+	DefaultDriveTimeBreakerCooldown = 30 * time.Second
+)
+
+type LatLng struct {
+	Lat float64
+	Lng float64
+}
+
+// DriveTime is one matrix element. Estimated is set when the value came from
+// the crow-flies fallback rather than a routing provider.
+type DriveTime struct {
+	DistanceMiles float64
+	Minutes       int
+	Estimated     bool
+}
+
+// DriveTimeProvider returns a len(origins) × len(destinations) matrix.
+// Implementations may mark individual elements Estimated when no route was
+// found; a non-nil error means the whole batch failed.
+type DriveTimeProvider interface {
+	Matrix(ctx context.Context, origins, destinations []LatLng) ([][]DriveTime, error)
+}
+
+// CrowFliesDriveTime is the great-circle estimate used whenever routing is
+// unavailable.
+func CrowFliesDriveTime(from, to LatLng) DriveTime {
+	dist := DistanceMiles(from.Lat, from.Lng, to.Lat, to.Lng)
+	return DriveTime{
+		DistanceMiles: dist,
+		Minutes:       int(dist*CrowFliesDriveTimeMultiplier + 0.5),
+		Estimated:     true,
+	}
+}
+
+/*──────────── crow-flies & fake providers ────────────*/
+
+// CrowFliesProvider never calls out; it is used when the Routes API is
+// disabled.
+type CrowFliesProvider struct{}
+
+func (CrowFliesProvider) Matrix(_ context.Context, origins, destinations []LatLng) ([][]DriveTime, error) {
+	out := make([][]DriveTime, len(origins))
+	for i, o := range origins {
+		out[i] = make([]DriveTime, len(destinations))
+		for j, d := range destinations {
+			out[i][j] = CrowFliesDriveTime(o, d)
+		}
+	}
+	return out, nil
+}
+
+// FakeDriveTimeProvider is a deterministic provider for tests and offline
+// runs: drive time is the great-circle distance at MinutesPerMile, results are
+// never marked Estimated, and every call is recorded. Setting Err makes each
+// call fail.
+type FakeDriveTimeProvider struct {
+	MinutesPerMile float64
+	Err            error
+
+	mu    sync.Mutex
+	calls [][2]int
+}
+
+func (f *FakeDriveTimeProvider) Matrix(_ context.Context, origins, destinations []LatLng) ([][]DriveTime, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, [2]int{len(origins), len(destinations)})
+	f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	perMile := f.MinutesPerMile
+	if perMile <= 0 {
+		perMile = 1.5
+	}
+	out := make([][]DriveTime, len(origins))
+	for i, o := range origins {
+		out[i] = make([]DriveTime, len(destinations))
+		for j, d := range destinations {
+			dist := DistanceMiles(o.Lat, o.Lng, d.Lat, d.Lng)
+			out[i][j] = DriveTime{DistanceMiles: dist, Minutes: int(dist*perMile + 0.5)}
+		}
+	}
+	return out, nil
+}
+
+// Calls returns the (origins, destinations) size of every Matrix call so far.
+func (f *FakeDriveTimeProvider) Calls() [][2]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][2]int(nil), f.calls...)
+}
+
+/*──────────── cache ────────────*/
+
+type DriveTimeCacheOptions struct {
+	Bucket           time.Duration // entries are valid for the bucket they were fetched in
+	MaxEntries       int
+	BreakerFailures  int
+	BreakerCooldown  time.Duration
+	MaxBatchElements int // per provider request; 0 means DriveTimeMatrixMaxElements
+}
+
+type driveTimeKey struct {
+	origin string
+	dest   string
+	bucket int64
+}
+
+type DriveTimeCache struct {
+	provider DriveTimeProvider
+	breaker  *CircuitBreaker
+	opts     DriveTimeCacheOptions
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[driveTimeKey]DriveTime
+	bucket  int64
+}
+
+func NewDriveTimeCache(provider DriveTimeProvider, opts DriveTimeCacheOptions) *DriveTimeCache {
+	if opts.Bucket <= 0 {
+		opts.Bucket = DefaultDriveTimeBucket
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultDriveTimeMaxEntries
+	}
+	if opts.BreakerFailures <= 0 {
+		opts.BreakerFailures = DefaultDriveTimeBreakerFailures
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = DefaultDriveTimeBreakerCooldown
+	}
+	if opts.MaxBatchElements <= 0 {
+		opts.MaxBatchElements = DriveTimeMatrixMaxElements
+	}
+	if provider == nil {
+		provider = CrowFliesProvider{}
+	}
+	return &DriveTimeCache{
+		provider: provider,
+		breaker:  NewCircuitBreaker("drive-time", opts.BreakerFailures, opts.BreakerCooldown),
+		opts:     opts,
+		now:      time.Now,
+		entries:  make(map[driveTimeKey]DriveTime),
+	}
+}
+
+// FromOrigin returns drive times from origin to each destination, in order.
+// It never fails: cache misses are fetched in batches and anything the
+// provider cannot answer falls back to the crow-flies estimate.
+func (c *DriveTimeCache) FromOrigin(ctx context.Context, origin LatLng, dests []LatLng) []DriveTime {
+	out := make([]DriveTime, len(dests))
+	if len(dests) == 0 {
+		return out
+	}
+
+	bucket := c.now().UnixNano() / int64(c.opts.Bucket)
+	originKey := EncodeGeohash(origin.Lat, origin.Lng, DriveTimeOriginPrecision)
+	keys := make([]driveTimeKey, len(dests))
+
+	var missIdx []int
+	c.mu.Lock()
+	if bucket != c.bucket {
+		// New bucket: everything cached so far is stale.
+		c.entries = make(map[driveTimeKey]DriveTime)
+		c.bucket = bucket
+	}
+	seen := make(map[driveTimeKey]int)
+	for i, d := range dests {
+		keys[i] = driveTimeKey{
+			origin: originKey,
+			dest:   EncodeGeohash(d.Lat, d.Lng, DriveTimeDestPrecision),
+			bucket: bucket,
+		}
+		if dt, ok := c.entries[keys[i]]; ok {
+			out[i] = dt
+			continue
+		}
+		if _, dup := seen[keys[i]]; dup {
+			continue
+		}
+		seen[keys[i]] = i
+		missIdx = append(missIdx, i)
+	}
+	c.mu.Unlock()
+
+	fetched := make(map[driveTimeKey]DriveTime, len(missIdx))
+	for start := 0; start < len(missIdx); start += c.opts.MaxBatchElements {
+		end := min(start+c.opts.MaxBatchElements, len(missIdx))
+		batch := missIdx[start:end]
+		batchDests := make([]LatLng, len(batch))
+		for k, i := range batch {
+			batchDests[k] = dests[i]
+		}
+		for k, dt := range c.fetchRow(ctx, origin, batchDests) {
+			fetched[keys[batch[k]]] = dt
+		}
+	}
+
+	if len(fetched) > 0 {
+		c.mu.Lock()
+		if c.bucket == bucket {
+			if len(c.entries)+len(fetched) > c.opts.MaxEntries {
+				c.entries = make(map[driveTimeKey]DriveTime)
+			}
+			for k, dt := range fetched {
+				if !dt.Estimated {
+					c.entries[k] = dt
+				}
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	for i := range dests {
+		if dt, ok := fetched[keys[i]]; ok {
+			out[i] = dt
+		}
+	}
+	return out
+}
+
+// fetchRow asks the provider for one origin row, honouring the breaker.
+func (c *DriveTimeCache) fetchRow(ctx context.Context, origin LatLng, dests []LatLng) []DriveTime {
+	fallback := func() []DriveTime {
+		row := make([]DriveTime, len(dests))
+		for i, d := range dests {
+			row[i] = CrowFliesDriveTime(origin, d)
+		}
+		return row
+	}
+
+	if !c.breaker.Allow() {
+		return fallback()
+	}
+	m, err := c.provider.Matrix(ctx, []LatLng{origin}, dests)
+	if err != nil || len(m) != 1 || len(m[0]) != len(dests) {
+		if err != nil {
+			Logger.WithError(err).Warn("[DriveTimeCache] Provider matrix request failed. Falling back to Haversine.")
+		}
+		c.breaker.RecordFailure()
+		return fallback()
+	}
+	c.breaker.RecordSuccess()
+	return m[0]
+}
+
+// BreakerState exposes the provider circuit state for health reporting.
+func (c *DriveTimeCache) BreakerState() CircuitState {
+	return c.breaker.State()
+}
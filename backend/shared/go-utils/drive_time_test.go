@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	testOrigin = LatLng{Lat: 33.5186, Lng: -86.8104}
+	testDests  = []LatLng{
+		{Lat: 33.5500, Lng: -86.7800},
+		{Lat: 33.4800, Lng: -86.9000},
+		{Lat: 33.6000, Lng: -86.7000},
+	}
+)
+
+func TestDriveTimeCacheBatchesAndReusesResults(t *testing.T) {
+	fake := &FakeDriveTimeProvider{MinutesPerMile: 2}
+	cache := NewDriveTimeCache(fake, DriveTimeCacheOptions{})
+
+	first := cache.FromOrigin(context.Background(), testOrigin, testDests)
+	if got := fake.Calls(); len(got) != 1 || got[0] != [2]int{1, len(testDests)} {
+		t.Fatalf("expected one 1x%d matrix call, got %v", len(testDests), got)
+	}
+	for i, dt := range first {
+		if dt.Estimated || dt.Minutes <= 0 {
+			t.Fatalf("dest %d: unexpected result %+v", i, dt)
+		}
+	}
+
+	// A nearby origin in the same geohash cell is served from cache.
+	nearby := LatLng{Lat: testOrigin.Lat + 0.0005, Lng: testOrigin.Lng + 0.0005}
+	second := cache.FromOrigin(context.Background(), nearby, testDests)
+	if got := len(fake.Calls()); got != 1 {
+		t.Fatalf("expected cache hit, provider called %d times", got)
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("dest %d: cached %+v != original %+v", i, second[i], first[i])
+		}
+	}
+}
+
+func TestDriveTimeCacheExpiresWithBucket(t *testing.T) {
+	fake := &FakeDriveTimeProvider{}
+	cache := NewDriveTimeCache(fake, DriveTimeCacheOptions{Bucket: time.Minute})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.FromOrigin(context.Background(), testOrigin, testDests)
+	now = now.Add(2 * time.Minute)
+	cache.FromOrigin(context.Background(), testOrigin, testDests)
+
+	if got := len(fake.Calls()); got != 2 {
+		t.Fatalf("expected refetch in new bucket, provider called %d times", got)
+	}
+}
+
+func TestDriveTimeCacheSplitsLargeBatches(t *testing.T) {
+	fake := &FakeDriveTimeProvider{}
+	cache := NewDriveTimeCache(fake, DriveTimeCacheOptions{MaxBatchElements: 2})
+
+	cache.FromOrigin(context.Background(), testOrigin, testDests)
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0][1] != 2 || calls[1][1] != 1 {
+		t.Fatalf("expected batches of 2 and 1, got %v", calls)
+	}
+}
+
+func TestDriveTimeCacheBreakerFallsBackToCrowFlies(t *testing.T) {
+	fake := &FakeDriveTimeProvider{Err: errors.New("unavailable")}
+	cache := NewDriveTimeCache(fake, DriveTimeCacheOptions{BreakerFailures: 2, BreakerCooldown: time.Minute})
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.breaker.now = func() time.Time { return now }
+
+	for range 5 {
+		got := cache.FromOrigin(context.Background(), testOrigin, testDests)
+		for i, dt := range got {
+			if want := CrowFliesDriveTime(testOrigin, testDests[i]); dt != want {
+				t.Fatalf("dest %d: got %+v, want crow-flies %+v", i, dt, want)
+			}
+		}
+	}
+	if got := len(fake.Calls()); got != 2 {
+		t.Fatalf("breaker should stop calls after 2 failures, provider called %d times", got)
+	}
+	if cache.BreakerState() != CircuitOpen {
+		t.Fatalf("expected open breaker, got %s", cache.BreakerState())
+	}
+
+	// After the cooldown a single probe goes through and closes the breaker.
+	fake.Err = nil
+	now = now.Add(2 * time.Minute)
+	got := cache.FromOrigin(context.Background(), testOrigin, testDests)
+	if got[0].Estimated || cache.BreakerState() != CircuitClosed {
+		t.Fatalf("expected recovered provider result, got %+v (breaker %s)", got[0], cache.BreakerState())
+	}
+}
@@ -24,6 +24,19 @@ var (
         // NEW: For external service failures (e.g., Twilio, SendGrid)
         ErrExternalServiceFailure = errors.New("external_service_failure")
 
+	// Score ledger
+	ErrScoreEventNotFound        = errors.New("score_event_not_found")
+	ErrScoreEventAlreadyReversed = errors.New("score_event_already_reversed")
+	ErrScoreEventNotReversible   = errors.New("score_event_not_reversible")
+
+	// Pay ledger
+	ErrPayItemInvalid    = errors.New("pay_item_invalid")     // amount's sign doesn't suit the kind
+	ErrPayItemNotAllowed = errors.New("pay_item_not_allowed") // job's status doesn't take the item
+	ErrPayBelowZero      = errors.New("pay_below_zero")
+
+	// Ops endpoints
+	ErrNotOpsUser = errors.New("not_ops_user")
+
 	// Additional examples
 	ErrNoRowsUpdated = errors.New("no_rows_updated")
 )
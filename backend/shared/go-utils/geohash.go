@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+/*────────────────────────────────────────────────────────────────────────────
+  Geohash helpers.
+
+  Properties carry a precision-6 geohash (computed in SQL by geohash_encode,
+  see migrations). To find candidates near a worker we cover the search
+  circle's bounding box with a small set of coarser cells and match those as
+  prefixes, which a plain btree index on the geohash column can serve.
+────────────────────────────────────────────────────────────────────────────*/
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+	// GeohashStoredPrecision is the precision persisted on properties.geohash.
+	GeohashStoredPrecision = 6
+
+	// GeohashMaxCoverCells bounds how many prefixes a radius query may expand to.
+	GeohashMaxCoverCells = 32
+
+	milesPerDegreeLat = 69.0
+)
+
+// EncodeGeohash returns the base32 geohash of (lat, lng) at the given precision.
+func EncodeGeohash(lat, lng float64, precision int) string {
+	latLo, latHi := -90.0, 90.0
+	lngLo, lngHi := -180.0, 180.0
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	isLng := true
+	bit, ch := 0, 0
+	for sb.Len() < precision {
+		if isLng {
+			mid := (lngLo + lngHi) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				lngLo = mid
+			} else {
+				ch <<= 1
+				lngHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latLo = mid
+			} else {
+				ch <<= 1
+				latHi = mid
+			}
+		}
+		isLng = !isLng
+		bit++
+		if bit == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// geohashCellSize returns the height and width in degrees of a cell.
+func geohashCellSize(precision int) (latDeg, lngDeg float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Exp2(float64(latBits)), 360 / math.Exp2(float64(lngBits))
+}
+
+// GeohashCoverRadius returns geohash prefixes whose union contains every point
+// within radiusMiles of (lat, lng). It picks the finest precision (up to
+// GeohashStoredPrecision) that keeps the cover within GeohashMaxCoverCells.
+// Results are sorted; callers still need an exact distance check.
+func GeohashCoverRadius(lat, lng, radiusMiles float64) []string {
+	dLat := radiusMiles / milesPerDegreeLat
+	cosLat := math.Cos(lat * math.Pi / 180)
+	dLng := 180.0
+	if cosLat > 1e-6 {
+		dLng = math.Min(radiusMiles/(milesPerDegreeLat*cosLat), 180)
+	}
+
+	minLat, maxLat := math.Max(lat-dLat, -90), math.Min(lat+dLat, 90)
+	minLng, maxLng := lng-dLng, lng+dLng
+
+	var best []string
+	for p := 1; p <= GeohashStoredPrecision; p++ {
+		cells := coverBox(minLat, maxLat, minLng, maxLng, p)
+		if len(cells) > GeohashMaxCoverCells {
+			break
+		}
+		best = cells
+	}
+	if best == nil {
+		best = coverBox(minLat, maxLat, minLng, maxLng, 1)
+	}
+	return best
+}
+
+func coverBox(minLat, maxLat, minLng, maxLng float64, precision int) []string {
+	cellLat, cellLng := geohashCellSize(precision)
+	seen := make(map[string]struct{})
+
+	// Step one cell at a time and always include the far edges.
+	for la := minLat; ; la += cellLat {
+		la = math.Min(la, maxLat)
+		for lo := minLng; ; lo += cellLng {
+			lo = math.Min(lo, maxLng)
+			seen[EncodeGeohash(la, wrapLng(lo), precision)] = struct{}{}
+			if lo >= maxLng || len(seen) > GeohashMaxCoverCells*4 {
+				break
+			}
+		}
+		if la >= maxLat || len(seen) > GeohashMaxCoverCells*4 {
+			break
+		}
+	}
+
+	out := make([]string, 0, len(seen))
+	for c := range seen {
+		out = append(out, c)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func wrapLng(lng float64) float64 {
+	for lng < -180 {
+		lng += 360
+	}
+	for lng >= 180 {
+		lng -= 360
+	}
+	return lng
+}
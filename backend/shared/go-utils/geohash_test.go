@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEncodeGeohashKnownValues(t *testing.T) {
+	cases := []struct {
+		lat, lng float64
+		prec     int
+		want     string
+	}{
+		{42.6, -5.6, 5, "ezs42"},
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+	}
+	for _, c := range cases {
+		if got := EncodeGeohash(c.lat, c.lng, c.prec); got != c.want {
+			t.Errorf("EncodeGeohash(%v, %v, %d) = %q, want %q", c.lat, c.lng, c.prec, got, c.want)
+		}
+	}
+}
+
+func TestGeohashCoverRadiusContainsNearbyPoints(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	origins := [][2]float64{{33.5186, -86.8104}, {61.2, -149.9}, {0.01, 179.99}, {-33.9, 18.4}}
+	for _, o := range origins {
+		for _, radius := range []float64{5, 25, 75} {
+			cells := GeohashCoverRadius(o[0], o[1], radius)
+			if len(cells) == 0 || len(cells) > GeohashMaxCoverCells {
+				t.Fatalf("cover for radius %v has %d cells", radius, len(cells))
+			}
+			for range 500 {
+				lat := o[0] + (rng.Float64()*2-1)*radius/milesPerDegreeLat
+				lng := wrapLng(o[1] + (rng.Float64()*2-1)*radius/milesPerDegreeLat*2)
+				if DistanceMiles(o[0], o[1], lat, lng) > radius {
+					continue
+				}
+				if !hasPrefixIn(EncodeGeohash(lat, lng, GeohashStoredPrecision), cells) {
+					t.Fatalf("point (%v,%v) within %v mi of %v not covered by %v", lat, lng, radius, o, cells)
+				}
+			}
+		}
+	}
+}
+
+func hasPrefixIn(hash string, cells []string) bool {
+	for _, c := range cells {
+		if strings.HasPrefix(hash, c) {
+			return true
+		}
+	}
+	return false
+}
+
+/*────────────────────────────────────────────────────────────────────────────
+  Benchmarks: candidate selection over a synthetic national footprint.
+
+  LinearScan mirrors the old ListOpenJobs shape (distance to every property).
+  GeohashPrefix mirrors the indexed query: range-scan a sorted geohash column
+  for each cover cell, then distance-check only those hits.
+────────────────────────────────────────────────────────────────────────────*/
+
+type benchProperty struct {
+	lat, lng float64
+	hash     string
+}
+
+func benchProperties(n int) []benchProperty {
+	rng := rand.New(rand.NewSource(42))
+	props := make([]benchProperty, n)
+	for i := range props {
+		lat := 25 + rng.Float64()*24
+		lng := -124 + rng.Float64()*57
+		props[i] = benchProperty{lat: lat, lng: lng, hash: EncodeGeohash(lat, lng, GeohashStoredPrecision)}
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].hash < props[j].hash })
+	return props
+}
+
+func benchmarkLinear(b *testing.B, n int) {
+	props := benchProperties(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hits := 0
+		for _, p := range props {
+			if DistanceMiles(33.5186, -86.8104, p.lat, p.lng) <= 75 {
+				hits++
+			}
+		}
+		_ = hits
+	}
+}
+
+func benchmarkGeohash(b *testing.B, n int) {
+	props := benchProperties(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hits := 0
+		for _, cell := range GeohashCoverRadius(33.5186, -86.8104, 75) {
+			start := sort.Search(len(props), func(k int) bool { return props[k].hash >= cell })
+			for k := start; k < len(props) && strings.HasPrefix(props[k].hash, cell); k++ {
+				if DistanceMiles(33.5186, -86.8104, props[k].lat, props[k].lng) <= 75 {
+					hits++
+				}
+			}
+		}
+		_ = hits
+	}
+}
+
+func BenchmarkCandidatesLinearScan1k(b *testing.B)      { benchmarkLinear(b, 1_000) }
+func BenchmarkCandidatesLinearScan10k(b *testing.B)     { benchmarkLinear(b, 10_000) }
+func BenchmarkCandidatesLinearScan100k(b *testing.B)    { benchmarkLinear(b, 100_000) }
+func BenchmarkCandidatesGeohashPrefix1k(b *testing.B)   { benchmarkGeohash(b, 1_000) }
+func BenchmarkCandidatesGeohashPrefix10k(b *testing.B)  { benchmarkGeohash(b, 10_000) }
+func BenchmarkCandidatesGeohashPrefix100k(b *testing.B) { benchmarkGeohash(b, 100_000) }
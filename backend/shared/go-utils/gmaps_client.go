@@ -2,7 +2,9 @@ package utils
 
 import (
         "context"
+        "errors"
         "fmt"
+        "io"
         "net/http"
         "sync"
         "time"
@@ -195,6 +197,116 @@ func ComputeDriveDistanceTimeMiles(
 	return distMiles, mins, nil
 }
 
+/*────────────────────────────────────────────────────────────────────────────
+  GoogleRoutesProvider answers drive-time matrices with a single
+  ComputeRouteMatrix call per batch. Elements without a route are filled in
+  with the crow-flies estimate and marked Estimated.
+────────────────────────────────────────────────────────────────────────────*/
+
+// DriveTimeMatrixMaxElements is the Routes API cap on origins × destinations
+// for a non-traffic-aware matrix request.
+const DriveTimeMatrixMaxElements = 625
+
+type GoogleRoutesProvider struct {
+	APIKey  string
+	Timeout time.Duration
+}
+
+func NewGoogleRoutesProvider(apiKey string) *GoogleRoutesProvider {
+	return &GoogleRoutesProvider{APIKey: apiKey, Timeout: 5 * time.Second}
+}
+
+func routeMatrixWaypoint(p LatLng) *routingpb.Waypoint {
+	return &routingpb.Waypoint{
+		LocationType: &routingpb.Waypoint_Location{
+			Location: &routingpb.Location{
+				LatLng: &latlng.LatLng{Latitude: p.Lat, Longitude: p.Lng},
+			},
+		},
+	}
+}
+
+func (g *GoogleRoutesProvider) Matrix(
+	ctx context.Context,
+	origins, destinations []LatLng,
+) ([][]DriveTime, error) {
+	if g.APIKey == "" {
+		return nil, fmt.Errorf("API key is empty")
+	}
+	if len(origins)*len(destinations) > DriveTimeMatrixMaxElements {
+		return nil, fmt.Errorf("matrix of %dx%d exceeds %d elements", len(origins), len(destinations), DriveTimeMatrixMaxElements)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	cli, err := getRoutesClient(ctx, g.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &routingpb.ComputeRouteMatrixRequest{
+		TravelMode:        routingpb.RouteTravelMode_DRIVE,
+		RoutingPreference: routingpb.RoutingPreference_TRAFFIC_UNAWARE,
+	}
+	for _, o := range origins {
+		req.Origins = append(req.Origins, &routingpb.RouteMatrixOrigin{Waypoint: routeMatrixWaypoint(o)})
+	}
+	for _, d := range destinations {
+		req.Destinations = append(req.Destinations, &routingpb.RouteMatrixDestination{Waypoint: routeMatrixWaypoint(d)})
+	}
+
+	ctxWithFieldMask := metadata.AppendToOutgoingContext(
+		ctx,
+		"X-Goog-FieldMask",
+		"originIndex,destinationIndex,condition,duration,distanceMeters",
+	)
+	stream, err := cli.ComputeRouteMatrix(ctxWithFieldMask, req)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]DriveTime, len(origins))
+	found := make([][]bool, len(origins))
+	for i := range origins {
+		out[i] = make([]DriveTime, len(destinations))
+		found[i] = make([]bool, len(destinations))
+	}
+	for {
+		el, rErr := stream.Recv()
+		if errors.Is(rErr, io.EOF) {
+			break
+		}
+		if rErr != nil {
+			return nil, rErr
+		}
+		oi, di := int(el.GetOriginIndex()), int(el.GetDestinationIndex())
+		if oi >= len(origins) || di >= len(destinations) ||
+			el.GetCondition() != routingpb.RouteMatrixElementCondition_ROUTE_EXISTS ||
+			el.GetDuration() == nil {
+			continue
+		}
+		dist := round1(float64(el.GetDistanceMeters()) / 1609.344)
+		if dist <= 0 {
+			dist = DistanceMiles(origins[oi].Lat, origins[oi].Lng, destinations[di].Lat, destinations[di].Lng)
+		}
+		out[oi][di] = DriveTime{
+			DistanceMiles: dist,
+			Minutes:       int(el.GetDuration().AsDuration().Minutes() + 0.5),
+		}
+		found[oi][di] = true
+	}
+
+	for i := range origins {
+		for j := range destinations {
+			if !found[i][j] {
+				out[i][j] = CrowFliesDriveTime(origins[i], destinations[j])
+			}
+		}
+	}
+	return out, nil
+}
+
 func ComputeDistanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
 	distMiles := DistanceMiles(lat1, lng1, lat2, lng2)
 	return distMiles * 1609.344
@@ -0,0 +1,581 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+/*
+JobQueue is a durable background job queue kept in the background_jobs table.
+Workers claim due jobs with SELECT … FOR UPDATE SKIP LOCKED, so every replica
+of a service can drain the same queue without two of them running one job.
+
+A failed job is retried with exponential backoff until it has used
+MaxAttempts, then it is dead-lettered (status DEAD) and kept for ops to
+inspect or requeue. Handlers return PermanentJobError to dead-letter at once.
+A job enqueued with a UniqueKey is dropped while another PENDING or RUNNING
+job on the queue holds the same key.
+
+Delivery is at-least-once: if a worker dies mid-job the job stays RUNNING
+until its lock times out and is then run again, so handlers must be safe to
+repeat.
+*/
+
+type BackgroundJobStatus string
+
+const (
+	BackgroundJobPending BackgroundJobStatus = "PENDING"
+	BackgroundJobRunning BackgroundJobStatus = "RUNNING"
+	BackgroundJobDead    BackgroundJobStatus = "DEAD"
+)
+
+const (
+	DefaultJobQueueConcurrency  = 4
+	DefaultJobQueuePollInterval = 2 * time.Second
+	DefaultJobQueueLockTimeout  = 10 * time.Minute
+	DefaultJobMaxAttempts       = 8
+	DefaultJobBaseBackoff       = 15 * time.Second
+	DefaultJobMaxBackoff        = 6 * time.Hour
+	jobQueueDeadDefaultLimit    = 50
+	jobQueueDeadMaxLimit        = 500
+)
+
+type BackgroundJob struct {
+	ID          uuid.UUID           `json:"id"`
+	Queue       string              `json:"queue"`
+	Kind        string              `json:"kind"`
+	Payload     json.RawMessage     `json:"payload"`
+	UniqueKey   *string             `json:"unique_key,omitempty"`
+	Status      BackgroundJobStatus `json:"status"`
+	Attempts    int                 `json:"attempts"`
+	MaxAttempts int                 `json:"max_attempts"`
+	RunAt       time.Time           `json:"run_at"`
+	LockedBy    *string             `json:"locked_by,omitempty"`
+	LockedAt    *time.Time          `json:"locked_at,omitempty"`
+	LastError   *string             `json:"last_error,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type EnqueueOptions struct {
+	UniqueKey   string    // dedupe against unfinished jobs with this key; empty = none
+	RunAt       time.Time // earliest run time; zero = now
+	MaxAttempts int       // zero = the queue default
+}
+
+// PermanentJobError marks a failure that retrying cannot fix.
+type PermanentJobError struct {
+	Err error
+}
+
+func (e *PermanentJobError) Error() string { return e.Err.Error() }
+func (e *PermanentJobError) Unwrap() error { return e.Err }
+
+// Permanent wraps err so the queue dead-letters the job instead of retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentJobError{Err: err}
+}
+
+// BackgroundJobStore persists jobs. Complete, Retry and Bury only apply while
+// the job is still RUNNING with the same attempt count, so a worker whose lock
+// expired cannot overwrite a newer attempt.
+type BackgroundJobStore interface {
+	// Insert adds a job, returning false if its unique key is already taken.
+	Insert(ctx context.Context, job *BackgroundJob) (bool, error)
+	// Claim locks up to limit due PENDING jobs of the given kinds and marks them RUNNING.
+	Claim(ctx context.Context, queue string, kinds []string, worker string, limit int) ([]*BackgroundJob, error)
+	Complete(ctx context.Context, job *BackgroundJob) error
+	Retry(ctx context.Context, job *BackgroundJob, runAt time.Time, errMsg string) error
+	Bury(ctx context.Context, job *BackgroundJob, errMsg string) error
+	// ReleaseExpired returns RUNNING jobs locked before the cutoff to PENDING.
+	ReleaseExpired(ctx context.Context, queue string, lockedBefore time.Time) (int64, error)
+	ListDead(ctx context.Context, queue string, limit int) ([]*BackgroundJob, error)
+	// Requeue moves a DEAD job back to PENDING with a fresh attempt budget.
+	Requeue(ctx context.Context, queue string, id uuid.UUID) (bool, error)
+}
+
+type JobQueueOptions struct {
+	Concurrency  int           // jobs run at once per replica
+	PollInterval time.Duration // idle wait between claims
+	LockTimeout  time.Duration // a RUNNING job is reclaimed after this; also bounds each run
+	MaxAttempts  int           // default attempt budget per job
+	BaseBackoff  time.Duration // delay before the first retry, doubled per attempt
+	MaxBackoff   time.Duration
+}
+
+type jobHandler func(ctx context.Context, payload json.RawMessage) error
+
+type JobQueue struct {
+	queue  string
+	worker string
+	store  BackgroundJobStore
+	opts   JobQueueOptions
+
+	mu       sync.RWMutex
+	handlers map[string]jobHandler
+
+	slots    chan struct{}
+	running  sync.WaitGroup
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func NewJobQueue(queue string, store BackgroundJobStore, opts JobQueueOptions) *JobQueue {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultJobQueueConcurrency
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultJobQueuePollInterval
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = DefaultJobQueueLockTimeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultJobMaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = DefaultJobBaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultJobMaxBackoff
+	}
+
+	host, _ := os.Hostname()
+	return &JobQueue{
+		queue:    queue,
+		worker:   fmt.Sprintf("%s-%d", host, os.Getpid()),
+		store:    store,
+		opts:     opts,
+		handlers: make(map[string]jobHandler),
+		slots:    make(chan struct{}, opts.Concurrency),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// NewPostgresJobQueue stores the queue in background_jobs.
+func NewPostgresJobQueue(queue string, pool *pgxpool.Pool, opts JobQueueOptions) *JobQueue {
+	return NewJobQueue(queue, NewPGBackgroundJobStore(pool), opts)
+}
+
+// RegisterJobHandler routes jobs of kind to fn, decoding their payload as T.
+// A payload that does not decode is dead-lettered without calling fn.
+func RegisterJobHandler[T any](q *JobQueue, kind string, fn func(ctx context.Context, payload T) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[kind] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", kind, err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+// Enqueue stores a job for kind with payload marshalled as JSON. It returns
+// false, without error, when opts.UniqueKey is already queued.
+func (q *JobQueue) Enqueue(ctx context.Context, kind string, payload any, opts EnqueueOptions) (bool, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return false, fmt.Errorf("encode %s payload: %w", kind, err)
+	}
+	now := time.Now().UTC()
+	job := &BackgroundJob{
+		ID:          uuid.New(),
+		Queue:       q.queue,
+		Kind:        kind,
+		Payload:     raw,
+		Status:      BackgroundJobPending,
+		MaxAttempts: opts.MaxAttempts,
+		RunAt:       opts.RunAt.UTC(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if opts.UniqueKey != "" {
+		job.UniqueKey = &opts.UniqueKey
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = q.opts.MaxAttempts
+	}
+	if opts.RunAt.IsZero() {
+		job.RunAt = now
+	}
+
+	ok, err := q.store.Insert(ctx, job)
+	if err != nil {
+		return false, fmt.Errorf("enqueue %s: %w", kind, err)
+	}
+	if !ok {
+		Logger.Debugf("Job queue %s: %s with key %q already queued", q.queue, kind, opts.UniqueKey)
+	}
+	return ok, nil
+}
+
+// Start begins claiming and running jobs.
+func (q *JobQueue) Start() {
+	go q.loop()
+}
+
+// Stop stops claiming and waits for running jobs to finish.
+func (q *JobQueue) Stop() {
+	q.stopOnce.Do(func() {
+		close(q.stopCh)
+		<-q.doneCh
+		q.running.Wait()
+	})
+}
+
+func (q *JobQueue) loop() {
+	defer close(q.doneCh)
+	ticker := time.NewTicker(q.opts.PollInterval)
+	defer ticker.Stop()
+
+	lastReap := time.Time{}
+	for {
+		if time.Since(lastReap) >= q.opts.LockTimeout/2 {
+			q.releaseExpired()
+			lastReap = time.Now()
+		}
+		// A full batch means more work is probably waiting, so go again at once.
+		if q.poll() > 0 && len(q.slots) < cap(q.slots) {
+			select {
+			case <-q.stopCh:
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *JobQueue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for k := range q.handlers {
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// poll claims as many jobs as there are free slots and starts them. It
+// returns the number claimed.
+func (q *JobQueue) poll() int {
+	free := cap(q.slots) - len(q.slots)
+	kinds := q.kinds()
+	if free == 0 || len(kinds) == 0 {
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.opts.PollInterval+30*time.Second)
+	defer cancel()
+	jobs, err := q.store.Claim(ctx, q.queue, kinds, q.worker, free)
+	if err != nil {
+		Logger.WithError(err).Warnf("Job queue %s: claim failed", q.queue)
+		return 0
+	}
+	for _, job := range jobs {
+		q.slots <- struct{}{}
+		q.running.Add(1)
+		go func(job *BackgroundJob) {
+			defer func() { <-q.slots; q.running.Done() }()
+			q.runJob(job)
+		}(job)
+	}
+	return len(jobs)
+}
+
+func (q *JobQueue) runJob(job *BackgroundJob) {
+	q.mu.RLock()
+	handler := q.handlers[job.Kind]
+	q.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), q.opts.LockTimeout)
+	runErr := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		if handler == nil {
+			return Permanent(fmt.Errorf("no handler registered for %s", job.Kind))
+		}
+		return handler(ctx, job.Payload)
+	}()
+	cancel()
+
+	// The job's own context may be spent; bookkeeping gets a fresh one.
+	bctx, bcancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer bcancel()
+
+	if runErr == nil {
+		if err := q.store.Complete(bctx, job); err != nil {
+			Logger.WithError(err).Errorf("Job queue %s: failed to complete %s job %s", q.queue, job.Kind, job.ID)
+		}
+		return
+	}
+
+	msg := runErr.Error()
+	var permanent *PermanentJobError
+	if errors.As(runErr, &permanent) || job.Attempts >= job.MaxAttempts {
+		Logger.WithError(runErr).Errorf("Job queue %s: dead-lettering %s job %s after %d attempt(s)", q.queue, job.Kind, job.ID, job.Attempts)
+		if err := q.store.Bury(bctx, job, msg); err != nil {
+			Logger.WithError(err).Errorf("Job queue %s: failed to dead-letter %s job %s", q.queue, job.Kind, job.ID)
+		}
+		return
+	}
+
+	runAt := time.Now().UTC().Add(JobBackoff(job.Attempts, q.opts.BaseBackoff, q.opts.MaxBackoff))
+	Logger.WithError(runErr).Warnf("Job queue %s: %s job %s failed (attempt %d/%d), retrying at %s",
+		q.queue, job.Kind, job.ID, job.Attempts, job.MaxAttempts, runAt.Format(time.RFC3339))
+	if err := q.store.Retry(bctx, job, runAt, msg); err != nil {
+		Logger.WithError(err).Errorf("Job queue %s: failed to schedule retry of %s job %s", q.queue, job.Kind, job.ID)
+	}
+}
+
+func (q *JobQueue) releaseExpired() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	n, err := q.store.ReleaseExpired(ctx, q.queue, time.Now().Add(-q.opts.LockTimeout))
+	if err != nil {
+		Logger.WithError(err).Warnf("Job queue %s: failed to release expired locks", q.queue)
+		return
+	}
+	if n > 0 {
+		Logger.Warnf("Job queue %s: released %d job(s) whose worker stopped responding", q.queue, n)
+	}
+}
+
+// JobBackoff is the delay after the given failed attempt (1-based):
+// base, 2×base, 4×base, … capped at maxDelay.
+func JobBackoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := float64(base) * math.Pow(2, float64(attempt-1))
+	if d > float64(maxDelay) {
+		return maxDelay
+	}
+	return time.Duration(d)
+}
+
+type deadJobsResponse struct {
+	Queue string           `json:"queue"`
+	Jobs  []*BackgroundJob `json:"jobs"`
+}
+
+// DeadJobsHandler serves dead-lettered jobs, newest first (GET ?limit=,
+// default 50, max 500). Payloads can hold personal data (notification
+// recipients, message bodies), so only their field names are shown. Mount it
+// behind an ops check.
+func (q *JobQueue) DeadJobsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := jobQueueDeadDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			RespondErrorWithCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, "limit must be a positive integer", nil, err)
+			return
+		}
+		limit = min(n, jobQueueDeadMaxLimit)
+	}
+
+	jobs, err := q.store.ListDead(r.Context(), q.queue, limit)
+	if err != nil {
+		RespondErrorWithCode(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to load dead jobs", nil, err)
+		return
+	}
+	if jobs == nil {
+		jobs = []*BackgroundJob{}
+	}
+	for _, j := range jobs {
+		j.Payload = redactJobPayload(j.Payload)
+	}
+	RespondWithJSON(w, http.StatusOK, deadJobsResponse{Queue: q.queue, Jobs: jobs})
+}
+
+// redactJobPayload keeps a payload object's field names and replaces every
+// value.
+func redactJobPayload(payload json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return json.RawMessage(`"[redacted]"`)
+	}
+	for k := range fields {
+		fields[k] = json.RawMessage(`"[redacted]"`)
+	}
+	b, _ := json.Marshal(fields)
+	return b
+}
+
+// RequeueDeadJobHandler gives a dead job a fresh attempt budget (POST ?id=).
+func (q *JobQueue) RequeueDeadJobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		RespondErrorWithCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, "id is required", nil, err)
+		return
+	}
+	ok, err := q.store.Requeue(r.Context(), q.queue, id)
+	if err != nil {
+		RespondErrorWithCode(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to requeue job", nil, err)
+		return
+	}
+	if !ok {
+		RespondErrorWithCode(w, http.StatusNotFound, ErrCodeNotFound, "No dead job with that id, or its unique key is already queued", nil, nil)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, map[string]string{"id": id.String(), "status": string(BackgroundJobPending)})
+}
+
+/* ---------- Postgres implementation ---------- */
+
+type PGBackgroundJobStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGBackgroundJobStore(pool *pgxpool.Pool) *PGBackgroundJobStore {
+	return &PGBackgroundJobStore{pool: pool}
+}
+
+const backgroundJobColumns = `
+    id, queue, kind, payload, unique_key, status, attempts, max_attempts,
+    run_at, locked_by, locked_at, last_error, created_at, updated_at
+`
+
+func (st *PGBackgroundJobStore) Insert(ctx context.Context, job *BackgroundJob) (bool, error) {
+	tag, err := st.pool.Exec(ctx, `
+        INSERT INTO background_jobs (id, queue, kind, payload, unique_key, status, max_attempts, run_at, created_at, updated_at)
+        VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+        ON CONFLICT (queue, unique_key)
+            WHERE unique_key IS NOT NULL AND status IN ('PENDING', 'RUNNING')
+            DO NOTHING
+    `, job.ID, job.Queue, job.Kind, job.Payload, job.UniqueKey, job.Status,
+		job.MaxAttempts, job.RunAt, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (st *PGBackgroundJobStore) Claim(ctx context.Context, queue string, kinds []string, worker string, limit int) ([]*BackgroundJob, error) {
+	return st.query(ctx, `
+        UPDATE background_jobs
+        SET status='RUNNING', attempts=attempts+1, locked_by=$3, locked_at=NOW(), updated_at=NOW()
+        WHERE id IN (
+            SELECT id FROM background_jobs
+            WHERE queue=$1 AND status='PENDING' AND run_at <= NOW() AND kind = ANY($2)
+            ORDER BY run_at
+            LIMIT $4
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING `+backgroundJobColumns,
+		queue, kinds, worker, limit)
+}
+
+func (st *PGBackgroundJobStore) Complete(ctx context.Context, job *BackgroundJob) error {
+	_, err := st.pool.Exec(ctx, `
+        DELETE FROM background_jobs WHERE id=$1 AND status='RUNNING' AND attempts=$2
+    `, job.ID, job.Attempts)
+	return err
+}
+
+func (st *PGBackgroundJobStore) Retry(ctx context.Context, job *BackgroundJob, runAt time.Time, errMsg string) error {
+	_, err := st.pool.Exec(ctx, `
+        UPDATE background_jobs
+        SET status='PENDING', run_at=$3, last_error=$4, locked_by=NULL, locked_at=NULL, updated_at=NOW()
+        WHERE id=$1 AND status='RUNNING' AND attempts=$2
+    `, job.ID, job.Attempts, runAt, errMsg)
+	return err
+}
+
+func (st *PGBackgroundJobStore) Bury(ctx context.Context, job *BackgroundJob, errMsg string) error {
+	_, err := st.pool.Exec(ctx, `
+        UPDATE background_jobs
+        SET status='DEAD', last_error=$3, locked_by=NULL, locked_at=NULL, updated_at=NOW()
+        WHERE id=$1 AND status='RUNNING' AND attempts=$2
+    `, job.ID, job.Attempts, errMsg)
+	return err
+}
+
+func (st *PGBackgroundJobStore) ReleaseExpired(ctx context.Context, queue string, lockedBefore time.Time) (int64, error) {
+	// Jobs that were on their last attempt are dead-lettered rather than rerun.
+	tag, err := st.pool.Exec(ctx, `
+        UPDATE background_jobs
+        SET status = CASE WHEN attempts >= max_attempts THEN 'DEAD' ELSE 'PENDING' END,
+            last_error = 'lock expired on ' || COALESCE(locked_by, 'unknown worker'),
+            locked_by=NULL, locked_at=NULL, run_at=NOW(), updated_at=NOW()
+        WHERE queue=$1 AND status='RUNNING' AND locked_at < $2
+    `, queue, lockedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (st *PGBackgroundJobStore) ListDead(ctx context.Context, queue string, limit int) ([]*BackgroundJob, error) {
+	return st.query(ctx, `
+        SELECT `+backgroundJobColumns+`
+        FROM background_jobs
+        WHERE queue=$1 AND status='DEAD'
+        ORDER BY updated_at DESC
+        LIMIT $2
+    `, queue, limit)
+}
+
+func (st *PGBackgroundJobStore) Requeue(ctx context.Context, queue string, id uuid.UUID) (bool, error) {
+	tag, err := st.pool.Exec(ctx, `
+        UPDATE background_jobs j
+        SET status='PENDING', attempts=0, run_at=NOW(), updated_at=NOW()
+        WHERE j.id=$1 AND j.queue=$2 AND j.status='DEAD'
+          AND (j.unique_key IS NULL OR NOT EXISTS (
+              SELECT 1 FROM background_jobs o
+              WHERE o.queue=j.queue AND o.unique_key=j.unique_key
+                AND o.status IN ('PENDING', 'RUNNING')
+          ))
+    `, id, queue)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (st *PGBackgroundJobStore) query(ctx context.Context, q string, args ...any) ([]*BackgroundJob, error) {
+	rows, err := st.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*BackgroundJob
+	for rows.Next() {
+		var job BackgroundJob
+		if err := rows.Scan(
+			&job.ID, &job.Queue, &job.Kind, &job.Payload, &job.UniqueKey, &job.Status,
+			&job.Attempts, &job.MaxAttempts, &job.RunAt, &job.LockedBy, &job.LockedAt,
+			&job.LastError, &job.CreatedAt, &job.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &job)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memJobStore is an in-memory BackgroundJobStore that ignores run_at so
+// tests can drive retries without waiting.
+type memJobStore struct {
+	mu   sync.Mutex
+	jobs []*BackgroundJob
+}
+
+func (m *memJobStore) Insert(_ context.Context, job *BackgroundJob) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if job.UniqueKey != nil && j.UniqueKey != nil && *j.UniqueKey == *job.UniqueKey && j.Status != BackgroundJobDead {
+			return false, nil
+		}
+	}
+	cp := *job
+	m.jobs = append(m.jobs, &cp)
+	return true, nil
+}
+
+func (m *memJobStore) Claim(_ context.Context, _ string, _ []string, worker string, limit int) ([]*BackgroundJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*BackgroundJob
+	for _, j := range m.jobs {
+		if len(out) == limit {
+			break
+		}
+		if j.Status == BackgroundJobPending {
+			j.Status, j.LockedBy = BackgroundJobRunning, &worker
+			j.Attempts++
+			cp := *j
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memJobStore) find(job *BackgroundJob) *BackgroundJob {
+	for _, j := range m.jobs {
+		if j.ID == job.ID && j.Status == BackgroundJobRunning && j.Attempts == job.Attempts {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *memJobStore) Complete(_ context.Context, job *BackgroundJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, j := range m.jobs {
+		if j == m.find(job) {
+			m.jobs = append(m.jobs[:i], m.jobs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *memJobStore) Retry(_ context.Context, job *BackgroundJob, runAt time.Time, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j := m.find(job); j != nil {
+		j.Status, j.RunAt, j.LastError = BackgroundJobPending, runAt, &errMsg
+	}
+	return nil
+}
+
+func (m *memJobStore) Bury(_ context.Context, job *BackgroundJob, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j := m.find(job); j != nil {
+		j.Status, j.LastError = BackgroundJobDead, &errMsg
+	}
+	return nil
+}
+
+func (m *memJobStore) ReleaseExpired(context.Context, string, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memJobStore) ListDead(context.Context, string, int) ([]*BackgroundJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*BackgroundJob
+	for _, j := range m.jobs {
+		if j.Status == BackgroundJobDead {
+			cp := *j
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memJobStore) Requeue(context.Context, string, uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+// drain runs claimed jobs synchronously until nothing is pending.
+func drain(q *JobQueue) {
+	for q.poll() > 0 {
+		q.running.Wait()
+	}
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestJobQueueRetriesThenDeadLetters(t *testing.T) {
+	store := &memJobStore{}
+	q := NewJobQueue("svc", store, JobQueueOptions{MaxAttempts: 3})
+
+	var got []string
+	RegisterJobHandler(q, "greet", func(_ context.Context, p greeting) error {
+		got = append(got, p.Name)
+		return errors.New("smtp down")
+	})
+
+	if ok, err := q.Enqueue(context.Background(), "greet", greeting{Name: "ada"}, EnqueueOptions{UniqueKey: "greet:ada"}); !ok || err != nil {
+		t.Fatalf("enqueue: ok=%v err=%v", ok, err)
+	}
+	if ok, _ := q.Enqueue(context.Background(), "greet", greeting{Name: "ada"}, EnqueueOptions{UniqueKey: "greet:ada"}); ok {
+		t.Fatal("expected duplicate unique key to be dropped")
+	}
+
+	drain(q)
+	if len(got) != 3 || got[0] != "ada" {
+		t.Fatalf("expected 3 typed attempts, got %v", got)
+	}
+	if len(store.jobs) != 1 || store.jobs[0].Status != BackgroundJobDead || *store.jobs[0].LastError != "smtp down" {
+		t.Fatalf("expected a dead-lettered job, got %+v", store.jobs[0])
+	}
+
+	// A dead job no longer blocks its key.
+	if ok, _ := q.Enqueue(context.Background(), "greet", greeting{Name: "ada"}, EnqueueOptions{UniqueKey: "greet:ada"}); !ok {
+		t.Fatal("expected key to be free once the job is dead")
+	}
+}
+
+func TestJobQueuePermanentErrorsAndSuccess(t *testing.T) {
+	store := &memJobStore{}
+	q := NewJobQueue("svc", store, JobQueueOptions{})
+
+	calls := 0
+	RegisterJobHandler(q, "ok", func(context.Context, greeting) error { calls++; return nil })
+	RegisterJobHandler(q, "bad", func(context.Context, greeting) error {
+		calls++
+		return Permanent(errors.New("no such user"))
+	})
+
+	_, _ = q.Enqueue(context.Background(), "ok", greeting{}, EnqueueOptions{})
+	_, _ = q.Enqueue(context.Background(), "bad", greeting{}, EnqueueOptions{})
+	drain(q)
+
+	if calls != 2 {
+		t.Fatalf("expected each job to run once, got %d calls", calls)
+	}
+	if len(store.jobs) != 1 || store.jobs[0].Kind != "bad" || store.jobs[0].Status != BackgroundJobDead {
+		t.Fatalf("expected only the permanently failed job to remain, dead; got %+v", store.jobs)
+	}
+}
+
+func TestDeadJobsHandlerRedactsPayloads(t *testing.T) {
+	store := &memJobStore{}
+	q := NewJobQueue("svc", store, JobQueueOptions{})
+	RegisterJobHandler(q, "bad", func(context.Context, greeting) error {
+		return Permanent(errors.New("no such user"))
+	})
+	_, _ = q.Enqueue(context.Background(), "bad", greeting{Name: "jane@example.com"}, EnqueueOptions{})
+	drain(q)
+
+	rec := httptest.NewRecorder()
+	q.DeadJobsHandler(rec, httptest.NewRequest(http.MethodGet, "/dead", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "jane@example.com") {
+		t.Fatalf("expected the payload to be redacted, got %s", body)
+	}
+	if !strings.Contains(body, `"payload":{"name":"[redacted]"}`) {
+		t.Fatalf("expected the payload's field names to be kept, got %s", body)
+	}
+}
+
+func TestJobBackoff(t *testing.T) {
+	base, maxDelay := time.Second, 10*time.Second
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 5: maxDelay} {
+		if got := JobBackoff(attempt, base, maxDelay); got != want {
+			t.Errorf("attempt %d: got %v want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	twilio "github.com/twilio/twilio-go"
+	twilioclient "github.com/twilio/twilio-go/client"
+	twilioApi "github.com/twilio/twilio-go/rest/api/v2010"
+)
+
+const (
+	EmailJobKind = "notify.email"
+	SMSJobKind   = "notify.sms"
+)
+
+// EmailJob is one email, fully rendered at enqueue time.
+type EmailJob struct {
+	FromName     string `json:"from_name"`
+	FromEmail    string `json:"from_email"`
+	ToName       string `json:"to_name"`
+	ToEmail      string `json:"to_email"`
+	Subject      string `json:"subject"`
+	PlainText    string `json:"plain_text"`
+	HTML         string `json:"html,omitempty"`
+	Sandbox      bool   `json:"sandbox,omitempty"`
+	NoClickTrack bool   `json:"no_click_track,omitempty"`
+}
+
+type SMSJob struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+// Notifier delivers email and SMS as queued jobs, so a SendGrid or Twilio
+// outage delays messages instead of dropping them. Rejections that a retry
+// cannot fix (bad address, 4xx) are dead-lettered. A Notifier without a
+// queue sends inline and only logs failures.
+type Notifier struct {
+	queue          *JobQueue
+	sendgridClient *sendgrid.Client
+	twilioClient   *twilio.RestClient
+}
+
+func NewNotifier(queue *JobQueue, sg *sendgrid.Client, tw *twilio.RestClient) *Notifier {
+	n := &Notifier{queue: queue, sendgridClient: sg, twilioClient: tw}
+	if queue != nil {
+		RegisterJobHandler(queue, EmailJobKind, n.sendEmail)
+		RegisterJobHandler(queue, SMSJobKind, n.sendSMS)
+	}
+	return n
+}
+
+// Email queues (or, without a queue, sends) one email. Failures are logged,
+// never returned: notifications must not fail the caller's operation.
+func (n *Notifier) Email(ctx context.Context, job EmailJob) {
+	if n.queue == nil {
+		if err := n.sendEmail(ctx, job); err != nil {
+			Logger.WithError(err).Errorf("Failed to send email %q to %s", job.Subject, job.ToEmail)
+		}
+		return
+	}
+	if _, err := n.queue.Enqueue(ctx, EmailJobKind, job, EnqueueOptions{}); err != nil {
+		Logger.WithError(err).Errorf("Failed to queue email %q to %s", job.Subject, job.ToEmail)
+	}
+}
+
+// SMS queues (or, without a queue, sends) one text message.
+func (n *Notifier) SMS(ctx context.Context, job SMSJob) {
+	if n.queue == nil {
+		if err := n.sendSMS(ctx, job); err != nil {
+			Logger.WithError(err).Errorf("Failed to send SMS to %s", job.To)
+		}
+		return
+	}
+	if _, err := n.queue.Enqueue(ctx, SMSJobKind, job, EnqueueOptions{}); err != nil {
+		Logger.WithError(err).Errorf("Failed to queue SMS to %s", job.To)
+	}
+}
+
+func (n *Notifier) sendEmail(_ context.Context, job EmailJob) error {
+	if n.sendgridClient == nil {
+		Logger.Warnf("SendGrid client is nil, skipping email to %s", job.ToEmail)
+		return nil
+	}
+	from := mail.NewEmail(job.FromName, job.FromEmail)
+	to := mail.NewEmail(job.ToName, job.ToEmail)
+	msg := mail.NewSingleEmail(from, job.Subject, to, job.PlainText, job.HTML)
+	if job.NoClickTrack {
+		msg.TrackingSettings = &mail.TrackingSettings{
+			ClickTracking: &mail.ClickTrackingSetting{Enable: Ptr(false)},
+		}
+	}
+	if job.Sandbox {
+		ms := mail.NewMailSettings()
+		ms.SetSandboxMode(mail.NewSetting(true))
+		msg.MailSettings = ms
+	}
+
+	resp, err := n.sendgridClient.Send(msg)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		err := fmt.Errorf("sendgrid returned %d: %s", resp.StatusCode, resp.Body)
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (n *Notifier) sendSMS(_ context.Context, job SMSJob) error {
+	if n.twilioClient == nil {
+		Logger.Warnf("Twilio client is nil, skipping SMS to %s", job.To)
+		return nil
+	}
+	params := &twilioApi.CreateMessageParams{}
+	params.SetTo(job.To)
+	params.SetFrom(job.From)
+	params.SetBody(job.Body)
+
+	_, err := n.twilioClient.Api.CreateMessage(params)
+	var restErr *twilioclient.TwilioRestError
+	if errors.As(err, &restErr) && restErr.Status < 500 && restErr.Status != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+// go-utils/ops_users.go
+
+package utils
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ParseOpsUserIDs splits the ops_user_ids LaunchDarkly flag, a
+// comma-separated list of the user IDs allowed to use ops endpoints.
+func ParseOpsUserIDs(flag string) []string {
+	var ids []string
+	for _, id := range strings.Split(flag, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// IsOpsUser reports whether userID is one of opsUserIDs.
+func IsOpsUser(opsUserIDs []string, userID string) bool {
+	return userID != "" && slices.Contains(opsUserIDs, userID)
+}
+
+// RequireOpsUser writes a 403 and returns false unless userID is one of
+// opsUserIDs.
+func RequireOpsUser(w http.ResponseWriter, opsUserIDs []string, userID string) bool {
+	if IsOpsUser(opsUserIDs, userID) {
+		return true
+	}
+	RespondErrorWithCode(w, http.StatusForbidden, ErrCodeUnauthorized, "Ops access required", nil, ErrNotOpsUser)
+	return false
+}
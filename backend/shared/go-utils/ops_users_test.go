@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseOpsUserIDs(t *testing.T) {
+	for flag, want := range map[string][]string{
+		"":             nil,
+		" , ,":         nil,
+		"a":            {"a"},
+		" a , b ,, c ": {"a", "b", "c"},
+	} {
+		if got := ParseOpsUserIDs(flag); !slices.Equal(got, want) {
+			t.Errorf("%q: expected %v, got %v", flag, want, got)
+		}
+	}
+}
+
+func TestRequireOpsUser(t *testing.T) {
+	ops := ParseOpsUserIDs("a,b")
+	for userID, want := range map[string]bool{"a": true, "b": true, "c": false, "": false} {
+		rec := httptest.NewRecorder()
+		if got := RequireOpsUser(rec, ops, userID); got != want {
+			t.Errorf("%q: expected %v, got %v", userID, want, got)
+		}
+		if !want && rec.Code != http.StatusForbidden {
+			t.Errorf("%q: expected a 403, got %d", userID, rec.Code)
+		}
+	}
+	if IsOpsUser(nil, "") {
+		t.Errorf("expected no ops users when the flag is empty")
+	}
+}
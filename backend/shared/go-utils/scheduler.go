@@ -0,0 +1,416 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/robfig/cron/v3"
+)
+
+/*
+Scheduler wraps robfig/cron so that only one replica of a service runs its
+scheduled jobs. Replicas compete for a leader lock (a Postgres session
+advisory lock keyed by service name); followers keep the schedule but skip
+every run until they win the lock. Each run the leader performs is recorded
+in scheduler_runs.
+
+A run already in progress is not interrupted if leadership is lost mid-run,
+so jobs should still be safe to repeat.
+*/
+
+type SchedulerRunStatus string
+
+const (
+	SchedulerRunRunning   SchedulerRunStatus = "RUNNING"
+	SchedulerRunSucceeded SchedulerRunStatus = "SUCCEEDED"
+	SchedulerRunFailed    SchedulerRunStatus = "FAILED"
+)
+
+const (
+	DefaultSchedulerElectionInterval = 15 * time.Second
+	DefaultSchedulerRunRetention     = 30 * 24 * time.Hour
+	schedulerPruneSpec               = "45 4 * * *"
+	schedulerPruneJobName            = "scheduler-prune-runs"
+	schedulerRunsDefaultLimit        = 50
+	schedulerRunsMaxLimit            = 500
+)
+
+type SchedulerRun struct {
+	ID         uuid.UUID          `json:"id"`
+	Service    string             `json:"service"`
+	Job        string             `json:"job"`
+	Replica    string             `json:"replica"`
+	Status     SchedulerRunStatus `json:"status"`
+	StartedAt  time.Time          `json:"started_at"`
+	FinishedAt *time.Time         `json:"finished_at,omitempty"`
+	DurationMs *int64             `json:"duration_ms,omitempty"`
+	Error      *string            `json:"error,omitempty"`
+}
+
+// LeaderLock is held by at most one replica at a time.
+type LeaderLock interface {
+	// TryAcquire attempts to take the lock without blocking.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Alive checks that a held lock is still held.
+	Alive(ctx context.Context) error
+	Release(ctx context.Context)
+}
+
+// SchedulerRunStore persists run history.
+type SchedulerRunStore interface {
+	StartRun(ctx context.Context, run *SchedulerRun) error
+	FinishRun(ctx context.Context, run *SchedulerRun) error
+	RecentRuns(ctx context.Context, service string, limit int) ([]*SchedulerRun, error)
+	PruneRuns(ctx context.Context, service string, before time.Time) (int64, error)
+}
+
+type SchedulerOptions struct {
+	Location         *time.Location // cron time zone; defaults to local time
+	ElectionInterval time.Duration  // how often followers retry and leaders re-check the lock
+	RunRetention     time.Duration  // run history older than this is pruned daily
+}
+
+type Scheduler struct {
+	service string
+	replica string
+	lock    LeaderLock
+	store   SchedulerRunStore
+	opts    SchedulerOptions
+	cron    *cron.Cron
+
+	leader   atomic.Bool
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func NewScheduler(service string, lock LeaderLock, store SchedulerRunStore, opts SchedulerOptions) *Scheduler {
+	if opts.ElectionInterval <= 0 {
+		opts.ElectionInterval = DefaultSchedulerElectionInterval
+	}
+	if opts.RunRetention <= 0 {
+		opts.RunRetention = DefaultSchedulerRunRetention
+	}
+	cronOpts := []cron.Option{cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger))}
+	if opts.Location != nil {
+		cronOpts = append(cronOpts, cron.WithLocation(opts.Location))
+	}
+
+	host, _ := os.Hostname()
+	s := &Scheduler{
+		service: service,
+		replica: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		lock:    lock,
+		store:   store,
+		opts:    opts,
+		cron:    cron.New(cronOpts...),
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	_ = s.AddJob(schedulerPruneJobName, schedulerPruneSpec, time.Minute, s.pruneRuns)
+	return s
+}
+
+// NewPostgresScheduler elects the leader with a Postgres advisory lock and
+// records runs in scheduler_runs. The leader keeps one pool connection
+// checked out for as long as it leads.
+func NewPostgresScheduler(service string, pool *pgxpool.Pool, opts SchedulerOptions) *Scheduler {
+	return NewScheduler(
+		service,
+		NewPGAdvisoryLock(pool, "scheduler:"+service),
+		NewPGSchedulerRunStore(pool),
+		opts,
+	)
+}
+
+// AddJob schedules fn under name. timeout bounds each run; zero means none.
+func (s *Scheduler) AddJob(name, spec string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	_, err := s.cron.AddFunc(spec, func() { s.runJob(name, timeout, fn) })
+	if err != nil {
+		return fmt.Errorf("schedule %s: %w", name, err)
+	}
+	return nil
+}
+
+// Start begins leader election and the cron loop.
+func (s *Scheduler) Start() {
+	go s.electionLoop()
+	s.cron.Start()
+}
+
+// Stop waits for running jobs to finish and gives up leadership.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		<-s.cron.Stop().Done()
+		close(s.stopCh)
+		<-s.doneCh
+	})
+}
+
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+func (s *Scheduler) electionLoop() {
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.opts.ElectionInterval)
+	defer ticker.Stop()
+
+	s.elect()
+	for {
+		select {
+		case <-s.stopCh:
+			if s.leader.Swap(false) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				s.lock.Release(ctx)
+				cancel()
+			}
+			return
+		case <-ticker.C:
+			s.elect()
+		}
+	}
+}
+
+func (s *Scheduler) elect() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.ElectionInterval)
+	defer cancel()
+
+	if s.leader.Load() {
+		if err := s.lock.Alive(ctx); err != nil {
+			Logger.WithError(err).Warnf("Scheduler %s: lost leadership on %s", s.service, s.replica)
+			s.leader.Store(false)
+			s.lock.Release(ctx)
+		}
+		return
+	}
+
+	ok, err := s.lock.TryAcquire(ctx)
+	if err != nil {
+		Logger.WithError(err).Warnf("Scheduler %s: leader election failed", s.service)
+		return
+	}
+	if ok {
+		Logger.Infof("Scheduler %s: %s is now the leader", s.service, s.replica)
+		s.leader.Store(true)
+	}
+}
+
+func (s *Scheduler) runJob(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	if !s.leader.Load() {
+		Logger.Debugf("Scheduler %s: skipping %s, not the leader", s.service, name)
+		return
+	}
+
+	ctx := context.Background()
+	var cancel context.CancelFunc = func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	run := &SchedulerRun{
+		ID:        uuid.New(),
+		Service:   s.service,
+		Job:       name,
+		Replica:   s.replica,
+		Status:    SchedulerRunRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := s.store.StartRun(context.Background(), run); err != nil {
+		Logger.WithError(err).Warnf("Scheduler %s: failed to record start of %s", s.service, name)
+	}
+
+	runErr := fn(ctx)
+
+	finished := time.Now().UTC()
+	durationMs := finished.Sub(run.StartedAt).Milliseconds()
+	run.FinishedAt, run.DurationMs = &finished, &durationMs
+	run.Status = SchedulerRunSucceeded
+	if runErr != nil {
+		msg := runErr.Error()
+		run.Status, run.Error = SchedulerRunFailed, &msg
+		Logger.WithError(runErr).Errorf("Scheduled job %s failed", name)
+	}
+	if err := s.store.FinishRun(context.Background(), run); err != nil {
+		Logger.WithError(err).Warnf("Scheduler %s: failed to record end of %s", s.service, name)
+	}
+}
+
+func (s *Scheduler) pruneRuns(ctx context.Context) error {
+	n, err := s.store.PruneRuns(ctx, s.service, time.Now().Add(-s.opts.RunRetention))
+	if err == nil && n > 0 {
+		Logger.Infof("Scheduler %s: pruned %d old runs", s.service, n)
+	}
+	return err
+}
+
+type schedulerRunsResponse struct {
+	Service string          `json:"service"`
+	Replica string          `json:"replica"`
+	Leader  bool            `json:"leader"`
+	Runs    []*SchedulerRun `json:"runs"`
+}
+
+// RunsHandler serves the most recent runs of this service's jobs across all
+// replicas (GET ?limit=, default 50, max 500). Mount it behind an ops check.
+func (s *Scheduler) RunsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := schedulerRunsDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			RespondErrorWithCode(w, http.StatusBadRequest, ErrCodeInvalidPayload, "limit must be a positive integer", nil, err)
+			return
+		}
+		limit = min(n, schedulerRunsMaxLimit)
+	}
+
+	runs, err := s.store.RecentRuns(r.Context(), s.service, limit)
+	if err != nil {
+		RespondErrorWithCode(w, http.StatusInternalServerError, ErrCodeInternal, "Failed to load scheduler runs", nil, err)
+		return
+	}
+	if runs == nil {
+		runs = []*SchedulerRun{}
+	}
+	RespondWithJSON(w, http.StatusOK, schedulerRunsResponse{
+		Service: s.service,
+		Replica: s.replica,
+		Leader:  s.IsLeader(),
+		Runs:    runs,
+	})
+}
+
+/* ---------- Postgres implementations ---------- */
+
+// PGAdvisoryLock is a session-level advisory lock held on a dedicated pool
+// connection. Postgres drops it if that session dies, so a crashed leader
+// frees the lock for a follower.
+type PGAdvisoryLock struct {
+	pool *pgxpool.Pool
+	key  string
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn
+}
+
+func NewPGAdvisoryLock(pool *pgxpool.Pool, key string) *PGAdvisoryLock {
+	return &PGAdvisoryLock{pool: pool, key: key}
+}
+
+func (l *PGAdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return true, nil
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1)::bigint)`, l.key).Scan(&ok); err != nil {
+		conn.Release()
+		return false, err
+	}
+	if !ok {
+		conn.Release()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+func (l *PGAdvisoryLock) Alive(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return fmt.Errorf("advisory lock %q not held", l.key)
+	}
+	return l.conn.Conn().Ping(ctx)
+}
+
+func (l *PGAdvisoryLock) Release(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return
+	}
+	if _, err := l.conn.Exec(ctx, `SELECT pg_advisory_unlock(hashtext($1)::bigint)`, l.key); err != nil {
+		// Closing the session is the only other way to be sure the lock is gone.
+		_ = l.conn.Conn().Close(ctx)
+	}
+	l.conn.Release()
+	l.conn = nil
+}
+
+type PGSchedulerRunStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPGSchedulerRunStore(pool *pgxpool.Pool) *PGSchedulerRunStore {
+	return &PGSchedulerRunStore{pool: pool}
+}
+
+func (st *PGSchedulerRunStore) StartRun(ctx context.Context, run *SchedulerRun) error {
+	_, err := st.pool.Exec(ctx, `
+        INSERT INTO scheduler_runs (id, service, job_name, replica, status, started_at)
+        VALUES ($1,$2,$3,$4,$5,$6)
+    `, run.ID, run.Service, run.Job, run.Replica, run.Status, run.StartedAt)
+	return err
+}
+
+func (st *PGSchedulerRunStore) FinishRun(ctx context.Context, run *SchedulerRun) error {
+	_, err := st.pool.Exec(ctx, `
+        UPDATE scheduler_runs
+        SET status=$1, finished_at=$2, duration_ms=$3, error=$4
+        WHERE id=$5
+    `, run.Status, run.FinishedAt, run.DurationMs, run.Error, run.ID)
+	return err
+}
+
+func (st *PGSchedulerRunStore) RecentRuns(ctx context.Context, service string, limit int) ([]*SchedulerRun, error) {
+	rows, err := st.pool.Query(ctx, `
+        SELECT id, service, job_name, replica, status, started_at, finished_at, duration_ms, error
+        FROM scheduler_runs
+        WHERE service=$1
+        ORDER BY started_at DESC
+        LIMIT $2
+    `, service, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*SchedulerRun
+	for rows.Next() {
+		var run SchedulerRun
+		if err := rows.Scan(
+			&run.ID, &run.Service, &run.Job, &run.Replica, &run.Status,
+			&run.StartedAt, &run.FinishedAt, &run.DurationMs, &run.Error,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &run)
+	}
+	return out, rows.Err()
+}
+
+func (st *PGSchedulerRunStore) PruneRuns(ctx context.Context, service string, before time.Time) (int64, error) {
+	tag, err := st.pool.Exec(ctx, `
+        DELETE FROM scheduler_runs WHERE service=$1 AND started_at < $2
+    `, service, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sharedLock is an in-memory LeaderLock shared by several schedulers.
+type sharedLock struct {
+	mu     *sync.Mutex
+	holder *string
+	name   string
+	dead   bool
+}
+
+func newSharedLocks(names ...string) []*sharedLock {
+	mu, holder := &sync.Mutex{}, new(string)
+	out := make([]*sharedLock, len(names))
+	for i, n := range names {
+		out[i] = &sharedLock{mu: mu, holder: holder, name: n}
+	}
+	return out
+}
+
+func (l *sharedLock) TryAcquire(context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if *l.holder == "" || *l.holder == l.name {
+		*l.holder = l.name
+		return true, nil
+	}
+	return false, nil
+}
+
+func (l *sharedLock) Alive(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.dead || *l.holder != l.name {
+		return errors.New("session gone")
+	}
+	return nil
+}
+
+func (l *sharedLock) Release(context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if *l.holder == l.name {
+		*l.holder = ""
+	}
+}
+
+type memRunStore struct {
+	mu   sync.Mutex
+	runs []SchedulerRun
+}
+
+func (m *memRunStore) StartRun(_ context.Context, run *SchedulerRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *memRunStore) FinishRun(_ context.Context, run *SchedulerRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.runs {
+		if m.runs[i].ID == run.ID {
+			m.runs[i] = *run
+		}
+	}
+	return nil
+}
+
+func (m *memRunStore) RecentRuns(context.Context, string, int) ([]*SchedulerRun, error) {
+	return nil, nil
+}
+
+func (m *memRunStore) PruneRuns(context.Context, string, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestSchedulerOnlyLeaderRunsJobs(t *testing.T) {
+	locks := newSharedLocks("a", "b")
+	store := &memRunStore{}
+	a := NewScheduler("svc", locks[0], store, SchedulerOptions{})
+	b := NewScheduler("svc", locks[1], store, SchedulerOptions{})
+
+	a.elect()
+	b.elect()
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("expected a to lead alone, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+
+	calls := 0
+	job := func(context.Context) error { calls++; return nil }
+	a.runJob("job", 0, job)
+	b.runJob("job", 0, job)
+	if calls != 1 {
+		t.Fatalf("expected the job to run once, ran %d times", calls)
+	}
+	if len(store.runs) != 1 || store.runs[0].Status != SchedulerRunSucceeded || store.runs[0].DurationMs == nil {
+		t.Fatalf("unexpected run history %+v", store.runs)
+	}
+}
+
+func TestSchedulerRecordsFailuresAndFailsOver(t *testing.T) {
+	locks := newSharedLocks("a", "b")
+	store := &memRunStore{}
+	a := NewScheduler("svc", locks[0], store, SchedulerOptions{})
+	b := NewScheduler("svc", locks[1], store, SchedulerOptions{})
+
+	a.elect()
+	a.runJob("job", time.Second, func(context.Context) error { return errors.New("boom") })
+	if len(store.runs) != 1 || store.runs[0].Status != SchedulerRunFailed ||
+		store.runs[0].Error == nil || *store.runs[0].Error != "boom" {
+		t.Fatalf("expected a recorded failure, got %+v", store.runs)
+	}
+
+	// a's session dies: its next check steps down and b takes over.
+	locks[0].dead = true
+	a.elect()
+	b.elect()
+	if a.IsLeader() || !b.IsLeader() {
+		t.Fatalf("expected failover to b, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+}